@@ -21,6 +21,35 @@ import "time"
 // ServerTransportHTTPConfig represents the HTTP transport configuration
 type ServerTransportHTTPConfig struct {
 	Host string `yaml:"host"`
+
+	// TLS serves HTTPS instead of plain HTTP when a certificate is set
+	TLS ServerTLSConfig `yaml:"tls,omitempty"`
+
+	// ShutdownTimeout is how long in-flight requests are given to finish
+	// after SIGTERM/SIGINT before their connections are closed. Defaults
+	// to 25s, inside the 30s grace period Kubernetes gives a Pod.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout,omitempty"`
+
+	// HeartbeatInterval is how often a ping is sent on idle streams so
+	// proxies do not cut them. Defaults to 30s.
+	HeartbeatInterval time.Duration `yaml:"heartbeat_interval,omitempty"`
+
+	// Stateless drops MCP sessions, so any replica behind a load balancer
+	// can serve any request. The current context is then the default one
+	// for every call.
+	Stateless bool `yaml:"stateless,omitempty"`
+}
+
+// ServerTLSConfig represents the TLS configuration of the HTTP transport.
+// The files are reloaded when they change or on SIGHUP, so certificates can
+// be rotated without a restart.
+type ServerTLSConfig struct {
+	CertFile string `yaml:"cert_file,omitempty"`
+	KeyFile  string `yaml:"key_file,omitempty"`
+
+	// ClientCAFile enables mutual TLS: clients must present a certificate
+	// signed by one of these CAs.
+	ClientCAFile string `yaml:"client_ca_file,omitempty"`
 }
 
 // ServerTransportConfig represents the transport configuration
@@ -34,6 +63,11 @@ type ServerConfig struct {
 	Name      string                `yaml:"name"`
 	Version   string                `yaml:"version"`
 	Transport ServerTransportConfig `yaml:"transport,omitempty"`
+
+	// Instructions is free-form guidance sent to clients in the MCP
+	// initialize handshake, e.g. "production is read-only for this team".
+	// The list of configured contexts is appended automatically.
+	Instructions string `yaml:"instructions,omitempty"`
 }
 
 // AccessLogsConfig represents the AccessLogs middleware configuration
@@ -73,11 +107,31 @@ type APIKeysConfig struct {
 	Keys    []APIKeyConfig `yaml:"keys,omitempty"`
 }
 
+// RateLimitConfig represents the rate limiting middleware configuration.
+// Each caller (authorization.identity_claim of the authenticated payload)
+// gets its own token bucket; anonymous requests share one.
+type RateLimitConfig struct {
+	Enabled           bool    `yaml:"enabled"`
+	RequestsPerSecond float64 `yaml:"requests_per_second"`
+	Burst             int     `yaml:"burst"`
+}
+
+// MetricsConfig represents the Prometheus metrics configuration. Metrics
+// are only served by the HTTP transport.
+type MetricsConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Path is where the metrics are served. Default: "/metrics"
+	Path string `yaml:"path,omitempty"`
+}
+
 // MiddlewareConfig represents the middleware configuration section
 type MiddlewareConfig struct {
 	AccessLogs AccessLogsConfig `yaml:"access_logs"`
 	JWT        JWTConfig        `yaml:"jwt,omitempty"`
 	APIKeys    APIKeysConfig    `yaml:"api_keys,omitempty"`
+	RateLimit  RateLimitConfig  `yaml:"rate_limit,omitempty"`
+	Metrics    MetricsConfig    `yaml:"metrics,omitempty"`
 }
 
 // OAuthAuthorizationServer represents the OAuth Authorization Server configuration
@@ -117,6 +171,49 @@ type KubernetesContextConfig struct {
 	Description       string   `yaml:"description,omitempty"`
 	AllowedNamespaces []string `yaml:"allowed_namespaces,omitempty"`
 	DeniedNamespaces  []string `yaml:"denied_namespaces,omitempty"`
+
+	// InCluster forces the Pod's service-account credentials and never
+	// consults $KUBECONFIG or ~/.kube/config. Mutually exclusive with
+	// Kubeconfig and KubeconfigContext.
+	InCluster bool `yaml:"in_cluster,omitempty"`
+
+	// CAData / CAFile replace the CA bundle the resolved config trusts, for
+	// clusters whose CA is not in the kubeconfig. CAData is PEM, optionally
+	// base64-encoded as in kubeconfig's 'certificate-authority-data'.
+	CAData string `yaml:"ca_data,omitempty"`
+	CAFile string `yaml:"ca_file,omitempty"`
+
+	// InsecureSkipTLSVerify disables server certificate verification. Test
+	// clusters only; a warning is logged whenever it is set.
+	InsecureSkipTLSVerify bool `yaml:"insecure_skip_tls_verify,omitempty"`
+
+	// QPS / Burst override the client-side rate limit of this context's
+	// clients. Zero falls back to KubernetesConfig.QPS / Burst, then to
+	// client-go's defaults (5 QPS, burst 10).
+	QPS   float32 `yaml:"qps,omitempty"`
+	Burst int     `yaml:"burst,omitempty"`
+
+	// Impersonate makes the API server authorize and audit this context's
+	// requests as another identity than the one its credentials authenticate.
+	Impersonate ImpersonationConfig `yaml:"impersonate,omitempty"`
+}
+
+// ImpersonationConfig represents the identity a context's requests are
+// impersonated as. The credentials of the context need the 'impersonate'
+// verb on users, groups and userextras.
+type ImpersonationConfig struct {
+	// User, Groups and Extra impersonate a fixed identity. Groups and Extra
+	// are also added to the caller's identity when FromCaller is set.
+	User   string              `yaml:"user,omitempty"`
+	Groups []string            `yaml:"groups,omitempty"`
+	Extra  map[string][]string `yaml:"extra,omitempty"`
+
+	// FromCaller impersonates the caller of each tool call: the user name is
+	// the claim named by authorization.identity_claim and the groups are read
+	// from GroupsClaim. API calls made without a caller identity are refused,
+	// discovery excepted. Mutually exclusive with User.
+	FromCaller  bool   `yaml:"from_caller,omitempty"`
+	GroupsClaim string `yaml:"groups_claim,omitempty"`
 }
 
 // BulkOperationsConfig represents limits for bulk operations
@@ -124,9 +221,80 @@ type BulkOperationsConfig struct {
 	MaxResourcesPerOperation int `yaml:"max_resources_per_operation"`
 }
 
+// RedactionConfig controls the redaction pass applied to the YAML output of
+// every tool. Matched values are replaced with "***".
+type RedactionConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// SecretData masks every value under 'data' and 'stringData' of any
+	// object whose kind is Secret. Keys stay visible.
+	SecretData bool `yaml:"secret_data,omitempty"`
+
+	// FieldPaths are globs matched against the dotted path of each field from
+	// the document root. List elements are addressed by index, so
+	// "items.*.data.*" matches the data of every item of a List.
+	FieldPaths []string `yaml:"field_paths,omitempty"`
+
+	// KeyNames are case-insensitive globs matched against map keys and against
+	// the 'name' of name/value pairs such as container env vars.
+	// e.g. "*password*", "*_token", "apikey"
+	KeyNames []string `yaml:"key_names,omitempty"`
+}
+
 // KubernetesToolsConfig represents the tools configuration
 type KubernetesToolsConfig struct {
+	// EnabledTools restricts registration to these tools (base names without
+	// the tool prefix, globs allowed). Empty means every tool.
+	EnabledTools []string `yaml:"enabled_tools,omitempty"`
+
+	// DisabledTools are never registered, even if listed in EnabledTools
+	DisabledTools []string `yaml:"disabled_tools,omitempty"`
+
 	BulkOperations BulkOperationsConfig `yaml:"bulk_operations,omitempty"`
+	Redaction      RedactionConfig      `yaml:"redaction,omitempty"`
+
+	// MaxOutputBytes caps the text a single tool call returns; longer output
+	// is cut at a line boundary with a truncation marker. Calls can override
+	// it with 'max_output_bytes'. Default: 1048576 (1 MiB); a negative value
+	// removes the cap.
+	MaxOutputBytes int `yaml:"max_output_bytes,omitempty"`
+
+	// RequestTimeout bounds each tool call, and so every Kubernetes API call
+	// it makes. Tools that wait by design (wait=true, log follows, exec) get
+	// their own maximum wait on top. Each API request is also bounded by it
+	// as rest.Config.Timeout, watches and log follows excepted. Default: 30s.
+	RequestTimeout time.Duration `yaml:"request_timeout,omitempty"`
+
+	// PreferredVersions maps an API group ("core" or "" for the core API) to
+	// the version the read tools use when a call leaves 'version' empty or
+	// names one the group does not serve. Groups not listed fall back to the
+	// preferred version reported by discovery.
+	PreferredVersions map[string]string `yaml:"preferred_versions,omitempty"`
+
+	// WatchMaxTimeout is the longest 'timeout_seconds' watch_resources
+	// accepts. Default: 5m.
+	WatchMaxTimeout time.Duration `yaml:"watch_max_timeout,omitempty"`
+
+	// ListDefaultLimit is the page size list_resources uses when a call
+	// gives no 'limit'. Default: 500; a negative value lists everything.
+	ListDefaultLimit int `yaml:"list_default_limit,omitempty"`
+
+	Exec ExecConfig `yaml:"exec,omitempty"`
+	Copy CopyConfig `yaml:"copy,omitempty"`
+}
+
+// ExecConfig represents the exec_command configuration
+type ExecConfig struct {
+	// MaxTimeoutSeconds is the longest 'timeout_seconds' exec_command
+	// accepts. Default: 300.
+	MaxTimeoutSeconds int `yaml:"max_timeout_seconds,omitempty"`
+}
+
+// CopyConfig represents the copy_from_pod / copy_to_pod configuration
+type CopyConfig struct {
+	// MaxBytes caps the file content a single copy transfers, in either
+	// direction. Default: 10485760 (10 MiB).
+	MaxBytes int64 `yaml:"max_bytes,omitempty"`
 }
 
 // DiscoveryConfig controls how the kubernetes API discovery cache (used by the
@@ -144,6 +312,11 @@ type KubernetesConfig struct {
 	ContextsDir    string                    `yaml:"contexts_dir,omitempty"`
 	Tools          KubernetesToolsConfig     `yaml:"tools,omitempty"`
 	Discovery      DiscoveryConfig           `yaml:"discovery,omitempty"`
+
+	// QPS / Burst are the client-side rate limit applied to every context
+	// that does not set its own. Zero keeps client-go's defaults.
+	QPS   float32 `yaml:"qps,omitempty"`
+	Burst int     `yaml:"burst,omitempty"`
 }
 
 // MatchConfig represents a match condition for authorization
@@ -159,9 +332,31 @@ const (
 	RuleEffectDeny  RuleEffect = "deny"
 )
 
+// MatchType selects how the group, version and resource patterns of a
+// ResourceRule are matched
+type MatchType string
+
+const (
+	// MatchTypeExact compares strings as they are: "*" is not a wildcard.
+	// Without a match type, "*" wildcards apply as in every other list
+	MatchTypeExact MatchType = "exact"
+
+	// MatchTypeGlob matches shell globs: "*", "?" and "[...]" classes.
+	// "*" does not match "/"
+	MatchTypeGlob MatchType = "glob"
+
+	// MatchTypeRegex matches regular expressions anchored at both ends
+	MatchTypeRegex MatchType = "regex"
+)
+
 // ResourceRule represents a rule for filtering resources by GVR + namespace + name.
 // All fields support glob patterns. Omitted fields match everything.
 type ResourceRule struct {
+	// MatchType selects how Groups, Versions and Resources are matched:
+	// "exact", "glob" or "regex". Omitted, they take "*" wildcards like
+	// Namespaces and Names, which always do
+	MatchType MatchType `yaml:"match_type,omitempty"`
+
 	// Groups filters by API group (supports glob)
 	// - [""] = Core API only
 	// - ["_"] = Virtual MCP resources only
@@ -190,10 +385,32 @@ type ResourceRule struct {
 
 // AuthorizationRule represents a single allow or deny rule within a policy
 type AuthorizationRule struct {
-	Effect    RuleEffect     `yaml:"effect"`
-	Tools     []string       `yaml:"tools,omitempty"`
-	Contexts  []string       `yaml:"contexts,omitempty"`
+	Effect   RuleEffect `yaml:"effect"`
+	Tools    []string   `yaml:"tools,omitempty"`
+	Contexts []string   `yaml:"contexts,omitempty"`
+
+	// Verbs matches the canonical verbs of the tool (get, list, watch,
+	// create, update, patch, delete, exec, portforward, use). An allow rule
+	// must list every verb of the tool; a deny rule matches any of them.
+	// - omit = any tool, subject to 'tools'
+	Verbs []string `yaml:"verbs,omitempty"`
+
+	// Namespaces scopes the rule to the namespace of the request, whatever
+	// the resource. Supports globs; "" matches cluster-scoped requests.
+	// - omit = any namespace + cluster-scoped
+	Namespaces []string `yaml:"namespaces,omitempty"`
+
 	Resources []ResourceRule `yaml:"resources,omitempty"`
+
+	// LabelPrefixes scopes the label keys a write may set or remove.
+	// On an allow rule only keys starting with one of the prefixes are
+	// allowed; on a deny rule those keys are forbidden and the rule no
+	// longer denies the request as a whole. "*" matches every key.
+	// - omit = any key (allow), no key-level deny (deny)
+	LabelPrefixes []string `yaml:"label_prefixes,omitempty"`
+
+	// AnnotationPrefixes is LabelPrefixes for annotation keys
+	AnnotationPrefixes []string `yaml:"annotation_prefixes,omitempty"`
 }
 
 // AuthorizationPolicy represents an authorization policy
@@ -206,8 +423,25 @@ type AuthorizationPolicy struct {
 
 // AuthorizationConfig represents the authorization configuration
 type AuthorizationConfig struct {
-	AllowAnonymous bool                  `yaml:"allow_anonymous"`
-	Policies       []AuthorizationPolicy `yaml:"policies"`
+	AllowAnonymous bool `yaml:"allow_anonymous"`
+
+	// IdentityClaim is the JWT claim naming the caller, impersonated by
+	// contexts with impersonate.from_caller. Default: "sub".
+	IdentityClaim string `yaml:"identity_claim,omitempty"`
+
+	// Audit logs every authorization decision
+	Audit AuditConfig `yaml:"audit,omitempty"`
+
+	Policies []AuthorizationPolicy `yaml:"policies"`
+}
+
+// AuditConfig represents the audit log of authorization decisions. Entries
+// go to stderr next to the server logs.
+type AuditConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Format is "json" (default) or "text"
+	Format string `yaml:"format,omitempty"`
 }
 
 // Configuration represents the complete configuration structure
@@ -218,4 +452,9 @@ type Configuration struct {
 	OAuthProtectedResource   OAuthProtectedResourceConfig `yaml:"oauth_protected_resource,omitempty"`
 	Kubernetes               KubernetesConfig             `yaml:"kubernetes,omitempty"`
 	Authorization            AuthorizationConfig          `yaml:"authorization,omitempty"`
+
+	// UnknownFields is what to do with keys of the file that match no
+	// setting, usually typos: "warn" (default, logged), "error" (refuse the
+	// file) or "ignore"
+	UnknownFields string `yaml:"unknown_fields,omitempty"`
 }
@@ -17,26 +17,45 @@ limitations under the License.
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
+	"kubernetes-mcp/api"
 	"kubernetes-mcp/internal/authorization"
+	"kubernetes-mcp/internal/config"
 	"kubernetes-mcp/internal/globals"
 	"kubernetes-mcp/internal/handlers"
 	"kubernetes-mcp/internal/k8stools"
 	"kubernetes-mcp/internal/kubernetes"
 	"kubernetes-mcp/internal/middlewares"
+	"kubernetes-mcp/internal/servertls"
 
 	"github.com/mark3labs/mcp-go/server"
 )
 
 func main() {
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run starts the server and blocks until it stops. Failures are returned,
+// not fatal, so the deferred cleanup (file watchers, port forwards) runs on
+// every exit path.
+func run() error {
 
 	// 0. Process the configuration
 	appCtx, err := globals.NewApplicationContext()
 	if err != nil {
-		log.Fatalf("failed creating application context: %v", err.Error())
+		return fmt.Errorf("failed creating application context: %w", err)
 	}
 
 	// 1. Initialize middlewares that need it
@@ -58,19 +77,27 @@ func main() {
 		appCtx.Logger.Info("failed starting API key validation middleware", "error", err.Error())
 	}
 
-	// 2. Create a new MCP server
-	mcpServer := server.NewMCPServer(
-		appCtx.Config.Server.Name,
-		appCtx.Config.Server.Version,
-		server.WithToolCapabilities(true),
-	)
+	rateLimitMw, err := middlewares.NewRateLimitMiddleware(middlewares.RateLimitMiddlewareDependencies{
+		AppCtx: appCtx,
+	})
+	if err != nil {
+		return fmt.Errorf("failed starting rate limit middleware: %w", err)
+	}
 
-	// 3. Initialize handlers for later usage
+	// Metrics are only served over HTTP, so stdio deployments skip them
+	var metricsMw *middlewares.MetricsMiddleware
+	if appCtx.Config.Middleware.Metrics.Enabled && appCtx.Config.Server.Transport.Type == "http" {
+		metricsMw = middlewares.NewMetricsMiddleware(middlewares.MetricsMiddlewareDependencies{
+			AppCtx: appCtx,
+		})
+	}
+
+	// 2. Initialize handlers for later usage
 	hm := handlers.NewHandlersManager(handlers.HandlersManagerDependencies{
 		AppCtx: appCtx,
 	})
 
-	// 4. Initialize Kubernetes client manager
+	// 3. Initialize Kubernetes client manager
 	var clientManager *kubernetes.ClientManager
 	if len(appCtx.Config.Kubernetes.Contexts) > 0 || appCtx.Config.Kubernetes.ContextsDir != "" {
 		clientManager, err = kubernetes.NewClientManager(appCtx.Logger, &appCtx.Config.Kubernetes)
@@ -82,6 +109,24 @@ func main() {
 		appCtx.Logger.Info("no Kubernetes contexts configured, Kubernetes tools will not be available")
 	}
 
+	// 4. Create a new MCP server. It goes after the client manager so the
+	// instructions sent in the handshake can list the loaded contexts.
+	hooks := &server.Hooks{}
+	mcpServer := server.NewMCPServer(
+		appCtx.Config.Server.Name,
+		appCtx.Config.Server.Version,
+		server.WithToolCapabilities(true),
+		server.WithInstructions(k8stools.BuildInstructions(appCtx.Config, clientManager)),
+		server.WithHooks(hooks),
+	)
+
+	// The current context is kept per session; drop it when the session ends
+	if clientManager != nil {
+		hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+			clientManager.ForgetSession(session.SessionID())
+		})
+	}
+
 	// 5. Initialize authorization evaluator
 	var authzEvaluator *authorization.Evaluator
 	if len(appCtx.Config.Authorization.Policies) > 0 {
@@ -95,17 +140,33 @@ func main() {
 	}
 
 	// 6. Register Kubernetes tools
+	var k8sManager *k8stools.Manager
 	if clientManager != nil {
-		k8sManager := k8stools.NewManager(k8stools.ManagerDependencies{
+		k8sManager = k8stools.NewManager(k8stools.ManagerDependencies{
 			Logger:        appCtx.Logger,
 			Config:        appCtx.Config,
 			ClientManager: clientManager,
 			Authz:         authzEvaluator,
 			McpServer:     mcpServer,
 			ToolPrefix:    appCtx.ToolPrefix,
+			Metrics:       metricsMw,
 		})
 		k8sManager.RegisterAll()
 		appCtx.Logger.Info("registered Kubernetes tools", "contexts", clientManager.ListContexts())
+
+		// Follow the config file: policies, contexts and tool settings are
+		// applied live, server and middleware settings need a restart
+		configWatcher := config.NewWatcher(appCtx.Logger, appCtx.ConfigPath, func(newConfig api.Configuration) error {
+			return reloadConfig(appCtx.Logger, clientManager, k8sManager, &newConfig)
+		})
+		stopConfigWatch := make(chan struct{})
+		defer close(stopConfigWatch)
+		go configWatcher.Watch(stopConfigWatch)
+	}
+
+	// Open port forwards must not outlive the server
+	if k8sManager != nil {
+		defer k8sManager.Close()
 	}
 
 	// 7. Wrap MCP server in a transport (stdio, HTTP, SSE)
@@ -118,15 +179,24 @@ func main() {
 			appCtx.Logger.Warn("HTTP transport is enabled but no authorization policies are configured; ALL incoming requests will be allowed by default. Configure 'authorization.policies' before exposing this server.")
 		}
 
+		httpConfig := appCtx.Config.Server.Transport.HTTP
+		heartbeatInterval := httpConfig.HeartbeatInterval
+		if heartbeatInterval < 0 {
+			return fmt.Errorf("server.transport.http.heartbeat_interval must be positive, got %s", heartbeatInterval)
+		}
+		if heartbeatInterval == 0 {
+			heartbeatInterval = defaultHeartbeatInterval
+		}
+
 		httpServer := server.NewStreamableHTTPServer(mcpServer,
-			server.WithHeartbeatInterval(30*time.Second),
-			server.WithStateLess(false))
+			server.WithHeartbeatInterval(heartbeatInterval),
+			server.WithStateLess(httpConfig.Stateless))
 
 		// Register it under a path, then add custom endpoints.
 		// Custom endpoints are needed as the library is not feature-complete according to MCP spec requirements (2025-06-16)
 		// Ref: https://modelcontextprotocol.io/specification/2025-06-18/basic/authorization#overview
 		mux := http.NewServeMux()
-		mux.Handle("/mcp", accessLogsMw.Middleware(jwtValidationMw.Middleware(apiKeyValidationMw.Middleware(httpServer))))
+		mux.Handle("/mcp", accessLogsMw.Middleware(jwtValidationMw.Middleware(apiKeyValidationMw.Middleware(rateLimitMw.Middleware(httpServer)))))
 
 		if appCtx.Config.OAuthAuthorizationServer.Enabled {
 			mux.Handle("/.well-known/oauth-authorization-server"+appCtx.Config.OAuthAuthorizationServer.UrlSuffix,
@@ -138,18 +208,119 @@ func main() {
 				accessLogsMw.Middleware(http.HandlerFunc(hm.HandleOauthProtectedResources)))
 		}
 
-		// Start StreamableHTTP server
-		appCtx.Logger.Info("starting StreamableHTTP server", "host", appCtx.Config.Server.Transport.HTTP.Host)
-		err := http.ListenAndServe(appCtx.Config.Server.Transport.HTTP.Host, mux)
-		if err != nil {
-			log.Fatal(err)
+		srv := &http.Server{
+			Addr:    httpConfig.Host,
+			Handler: mux,
+		}
+
+		if metricsMw != nil {
+			metricsPath := appCtx.Config.Middleware.Metrics.Path
+			if metricsPath == "" {
+				metricsPath = middlewares.MetricsDefaultPath
+			}
+			mux.Handle(metricsPath, metricsMw.Handler())
+		}
+
+		// Start StreamableHTTP server, over TLS when a certificate is configured
+		tlsConfig := httpConfig.TLS
+		if tlsConfig.CertFile != "" || tlsConfig.KeyFile != "" {
+			reloader, err := servertls.NewReloader(appCtx.Logger, tlsConfig)
+			if err != nil {
+				return fmt.Errorf("failed configuring TLS: %w", err)
+			}
+			stopWatch := make(chan struct{})
+			defer close(stopWatch)
+			go reloader.Watch(stopWatch)
+			srv.TLSConfig = reloader.TLSConfig()
+		} else if tlsConfig.ClientCAFile != "" {
+			return errors.New("tls.client_ca_file requires tls.cert_file and tls.key_file")
+		}
+
+		serve := srv.ListenAndServe
+		if srv.TLSConfig != nil {
+			appCtx.Logger.Info("starting StreamableHTTP server", "host", srv.Addr, "tls", true,
+				"mtls", tlsConfig.ClientCAFile != "")
+			// The certificate comes from TLSConfig, so no file names here
+			serve = func() error { return srv.ListenAndServeTLS("", "") }
+		} else {
+			appCtx.Logger.Info("starting StreamableHTTP server", "host", srv.Addr)
 		}
 
+		shutdownTimeout := httpConfig.ShutdownTimeout
+		if shutdownTimeout <= 0 {
+			shutdownTimeout = defaultShutdownTimeout
+		}
+		return serveUntilSignal(appCtx.Logger, srv, serve, shutdownTimeout)
+
 	default:
 		// Start stdio server
 		appCtx.Logger.Info("starting stdio server")
-		if err := server.ServeStdio(mcpServer); err != nil {
-			log.Fatal(err)
+		return server.ServeStdio(mcpServer)
+	}
+}
+
+// reloadConfig validates a configuration re-read from disk and applies what
+// can change without a restart: the authorization policies, the Kubernetes
+// contexts with their namespace allow-lists, and the tool settings. Nothing
+// is applied when the policies or the contexts are invalid.
+func reloadConfig(logger *slog.Logger, clientManager *kubernetes.ClientManager, k8sManager *k8stools.Manager, newConfig *api.Configuration) error {
+	var authzEvaluator *authorization.Evaluator
+	if len(newConfig.Authorization.Policies) > 0 {
+		var err error
+		authzEvaluator, err = authorization.NewEvaluator(&newConfig.Authorization)
+		if err != nil {
+			return fmt.Errorf("invalid authorization policies: %w", err)
 		}
 	}
+
+	if err := clientManager.Reload(&newConfig.Kubernetes); err != nil {
+		return fmt.Errorf("invalid kubernetes contexts: %w", err)
+	}
+	k8sManager.Reload(newConfig, authzEvaluator)
+
+	if authzEvaluator == nil {
+		logger.Warn("the reloaded configuration has no authorization policies; ALL incoming requests will be allowed")
+	}
+	return nil
+}
+
+// defaultHeartbeatInterval is how often idle streams are pinged when
+// server.transport.http.heartbeat_interval is not set
+const defaultHeartbeatInterval = 30 * time.Second
+
+// defaultShutdownTimeout is how long in-flight requests get to finish on
+// SIGTERM/SIGINT when server.transport.http.shutdown_timeout is not set
+const defaultShutdownTimeout = 25 * time.Second
+
+// serveUntilSignal runs serve until it fails or SIGTERM/SIGINT arrives. On a
+// signal the server stops accepting connections and in-flight requests get
+// up to 'timeout' to finish; what is still open then (long streams such as
+// follow_logs or exec) is closed, which cancels those requests.
+func serveUntilSignal(logger *slog.Logger, srv *http.Server, serve func() error, timeout time.Duration) error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+	defer signal.Stop(signals)
+
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- serve() }()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case sig := <-signals:
+		logger.Info("shutting down StreamableHTTP server", "signal", sig.String(), "timeout", timeout.String())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		logger.Warn("in-flight requests did not finish in time, closing them", "error", err.Error())
+		srv.Close()
+	}
+
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	logger.Info("StreamableHTTP server stopped")
+	return nil
 }
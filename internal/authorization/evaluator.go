@@ -18,7 +18,11 @@ package authorization
 
 import (
 	"fmt"
+	"path"
+	"regexp"
+	"slices"
 	"strings"
+	"sync"
 
 	"kubernetes-mcp/api"
 
@@ -33,22 +37,29 @@ const (
 	VirtualResourceAPIDiscovery = "apidiscovery"
 	VirtualResourceClusterInfo  = "clusterinfo"
 	VirtualResourceContext      = "contexts"
+	VirtualResourcePolicies     = "policies"
 )
 
 // ToolVirtualResources maps tools to their virtual resources
 var ToolVirtualResources = map[string]ResourceInfo{
 	"list_api_resources":  {Group: VirtualResourceGroup, Resource: VirtualResourceAPIDiscovery},
 	"list_api_versions":   {Group: VirtualResourceGroup, Resource: VirtualResourceAPIDiscovery},
+	"explain_resource":    {Group: VirtualResourceGroup, Resource: VirtualResourceAPIDiscovery},
 	"get_cluster_info":    {Group: VirtualResourceGroup, Resource: VirtualResourceClusterInfo},
 	"get_current_context": {Group: VirtualResourceGroup, Resource: VirtualResourceContext},
 	"list_contexts":       {Group: VirtualResourceGroup, Resource: VirtualResourceContext},
 	"switch_context":      {Group: VirtualResourceGroup, Resource: VirtualResourceContext},
+	"test_authorization":  {Group: VirtualResourceGroup, Resource: VirtualResourcePolicies},
 }
 
 // CompiledPolicy holds a policy with its precompiled CEL programs
 type CompiledPolicy struct {
 	Policy  api.AuthorizationPolicy
 	Program cel.Program
+
+	// regexps holds the compiled patterns of its 'match_type: regex'
+	// resource rules, so requests never compile
+	regexps map[string]*regexp.Regexp
 }
 
 // Evaluator evaluates authorization policies using CEL
@@ -65,6 +76,27 @@ type AuthzRequest struct {
 	Context   string
 	Namespace string
 	Resource  ResourceInfo
+
+	// matches caches the policies matching the request, see WithMatchCache
+	matches *matchCache
+}
+
+// matchCache holds the policies matching a request, computed by the first
+// evaluator that needed them
+type matchCache struct {
+	mu        sync.Mutex
+	evaluator *Evaluator
+	rules     []policyRule
+	policies  []string
+}
+
+// WithMatchCache returns the request with a cache of the policies matching
+// it, shared by its copies: evaluating them with one Evaluator (Evaluate,
+// IsLabelPrefixAllowed, IsAnnotationPrefixAllowed...) runs each policy's CEL
+// program once. The request must not change afterwards.
+func (r AuthzRequest) WithMatchCache() AuthzRequest {
+	r.matches = &matchCache{}
+	return r
 }
 
 // ResourceInfo holds information about the resource being accessed (GVR)
@@ -73,17 +105,24 @@ type ResourceInfo struct {
 	Version  string `json:"version"`
 	Resource string `json:"resource"`
 	Name     string `json:"name"`
+
+	// Namespaced is true when the resource type is namespaced, as resolved
+	// through discovery. It is false for cluster-scoped types, virtual MCP
+	// resources and types whose scope could not be resolved.
+	Namespaced bool `json:"namespaced"`
 }
 
 // NewEvaluator creates a new authorization evaluator
 func NewEvaluator(config *api.AuthorizationConfig) (*Evaluator, error) {
-	env, err := cel.NewEnv(
+	opts := []cel.EnvOption{
 		cel.Variable("payload", cel.DynType),
 		cel.Variable("tool", cel.StringType),
+		cel.Variable("verbs", cel.ListType(cel.StringType)),
 		cel.Variable("context", cel.StringType),
 		cel.Variable("namespace", cel.StringType),
 		cel.Variable("resource", cel.DynType),
-	)
+	}
+	env, err := cel.NewEnv(append(opts, claimFunctions()...)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
 	}
@@ -105,9 +144,19 @@ func NewEvaluator(config *api.AuthorizationConfig) (*Evaluator, error) {
 			return nil, fmt.Errorf("failed to create program for policy %s: %w", policy.Name, err)
 		}
 
+		regexps := map[string]*regexp.Regexp{}
+		for _, rule := range policy.Rules {
+			for _, resourceRule := range rule.Resources {
+				if err := compileResourceRule(resourceRule, regexps); err != nil {
+					return nil, fmt.Errorf("invalid resource rule in policy %s: %w", policy.Name, err)
+				}
+			}
+		}
+
 		e.compiledPolicies = append(e.compiledPolicies, CompiledPolicy{
 			Policy:  policy,
 			Program: prg,
+			regexps: regexps,
 		})
 	}
 
@@ -124,7 +173,34 @@ func GetResourceForTool(tool string, resource ResourceInfo) ResourceInfo {
 	return resource
 }
 
-// Evaluate evaluates all matching policies and returns whether the request is allowed.
+// Decision is the outcome of an evaluation, with the policies behind it
+type Decision struct {
+	Allowed bool
+
+	// MatchedPolicies are the policies whose match expression was true
+	MatchedPolicies []string
+
+	// DecidingPolicy is the policy of the rule that allowed or denied the
+	// request; empty when no rule matched and the default deny applied.
+	DecidingPolicy string
+}
+
+// policyRule is a rule with the name and compiled regexps of the policy it
+// belongs to
+type policyRule struct {
+	policy  string
+	rule    api.AuthorizationRule
+	regexps map[string]*regexp.Regexp
+}
+
+// Evaluate evaluates all matching policies and returns whether the request
+// is allowed. See EvaluateDecision for the policies behind the answer.
+func (e *Evaluator) Evaluate(req AuthzRequest) (bool, error) {
+	decision, err := e.EvaluateDecision(req)
+	return decision.Allowed, err
+}
+
+// EvaluateDecision evaluates all matching policies and returns the decision.
 //
 // Algorithm:
 //  1. If no payload and anonymous not allowed -> deny
@@ -133,9 +209,130 @@ func GetResourceForTool(tool string, resource ResourceInfo) ResourceInfo {
 //  4. If ANY deny rule matches the request -> deny
 //  5. If ANY allow rule matches the request -> allow
 //  6. Default: deny
-func (e *Evaluator) Evaluate(req AuthzRequest) (bool, error) {
+func (e *Evaluator) EvaluateDecision(req AuthzRequest) (Decision, error) {
+	matched, policies := e.matchedPolicyRules(req)
+	decision := Decision{MatchedPolicies: policies}
+	if len(matched) == 0 {
+		return decision, nil
+	}
+	req.Resource = GetResourceForTool(req.Tool, req.Resource)
+
+	// Deny takes priority: if any deny rule matches, deny. Deny rules scoped
+	// to label or annotation prefixes only forbid those keys, see
+	// IsLabelPrefixAllowed.
+	for _, pr := range matched {
+		if pr.rule.Effect == api.RuleEffectDeny && !restrictsKeys(pr.rule) && ruleMatchesRequest(pr.rule, pr.regexps, req) {
+			decision.DecidingPolicy = pr.policy
+			return decision, nil
+		}
+	}
+
+	// Check if any allow rule matches
+	for _, pr := range matched {
+		if pr.rule.Effect == api.RuleEffectAllow && ruleMatchesRequest(pr.rule, pr.regexps, req) {
+			decision.Allowed = true
+			decision.DecidingPolicy = pr.policy
+			return decision, nil
+		}
+	}
+
+	return decision, nil
+}
+
+// IsLabelPrefixAllowed reports whether a write authorized as 'req' may set
+// or remove the label 'key', according to the 'label_prefixes' of the rules
+// matching the request.
+func (e *Evaluator) IsLabelPrefixAllowed(req AuthzRequest, key string) bool {
+	return e.isKeyAllowed(req, key, func(rule api.AuthorizationRule) []string { return rule.LabelPrefixes })
+}
+
+// IsAnnotationPrefixAllowed is IsLabelPrefixAllowed for annotation keys and
+// 'annotation_prefixes'.
+func (e *Evaluator) IsAnnotationPrefixAllowed(req AuthzRequest, key string) bool {
+	return e.isKeyAllowed(req, key, func(rule api.AuthorizationRule) []string { return rule.AnnotationPrefixes })
+}
+
+// isKeyAllowed applies the prefix lists returned by 'prefixes' to 'key':
+//  1. If ANY matching deny rule lists a prefix of the key -> deny
+//  2. If ANY matching allow rule lists no prefixes, or a prefix of the
+//     key -> allow
+//  3. Default: deny
+func (e *Evaluator) isKeyAllowed(req AuthzRequest, key string, prefixes func(api.AuthorizationRule) []string) bool {
+	matched, _ := e.matchedPolicyRules(req)
+	req.Resource = GetResourceForTool(req.Tool, req.Resource)
+
+	for _, pr := range matched {
+		if pr.rule.Effect == api.RuleEffectDeny && ruleMatchesRequest(pr.rule, pr.regexps, req) && matchesKeyPrefix(prefixes(pr.rule), key) {
+			return false
+		}
+	}
+
+	for _, pr := range matched {
+		if pr.rule.Effect != api.RuleEffectAllow || !ruleMatchesRequest(pr.rule, pr.regexps, req) {
+			continue
+		}
+		if len(prefixes(pr.rule)) == 0 || matchesKeyPrefix(prefixes(pr.rule), key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RuleResult is one rule of a matched policy and whether it applies to a
+// request
+type RuleResult struct {
+	Policy  string
+	Rule    api.AuthorizationRule
+	Applies bool
+}
+
+// Explain evaluates the request like EvaluateDecision and also returns every
+// rule of the matched policies, marking the ones that apply to it. It is
+// meant for debugging policies, not for the request path.
+func (e *Evaluator) Explain(req AuthzRequest) (Decision, []RuleResult, error) {
+	req = req.WithMatchCache()
+	decision, err := e.EvaluateDecision(req)
+	if err != nil {
+		return decision, nil, err
+	}
+
+	matched, _ := e.matchedPolicyRules(req)
+	req.Resource = GetResourceForTool(req.Tool, req.Resource)
+	results := make([]RuleResult, 0, len(matched))
+	for _, pr := range matched {
+		results = append(results, RuleResult{
+			Policy:  pr.policy,
+			Rule:    pr.rule,
+			Applies: ruleMatchesRequest(pr.rule, pr.regexps, req),
+		})
+	}
+	return decision, results, nil
+}
+
+// matchedPolicyRules returns the rules of every policy whose match
+// expression is true for the request, with the policy of each rule, and the
+// names of those policies. Requests with a match cache are evaluated once
+// per evaluator.
+func (e *Evaluator) matchedPolicyRules(req AuthzRequest) ([]policyRule, []string) {
+	c := req.matches
+	if c == nil {
+		return e.evaluateMatches(req)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.evaluator != e {
+		c.rules, c.policies = e.evaluateMatches(req)
+		c.evaluator = e
+	}
+	return c.rules, c.policies
+}
+
+// evaluateMatches runs the match expression of every policy for the request
+func (e *Evaluator) evaluateMatches(req AuthzRequest) ([]policyRule, []string) {
 	if len(req.Payload) == 0 && !e.config.AllowAnonymous {
-		return false, nil
+		return nil, nil
 	}
 
 	req.Resource = GetResourceForTool(req.Tool, req.Resource)
@@ -143,17 +340,20 @@ func (e *Evaluator) Evaluate(req AuthzRequest) (bool, error) {
 	evalCtx := map[string]any{
 		"payload":   req.Payload,
 		"tool":      req.Tool,
+		"verbs":     VerbsForTool(req.Tool),
 		"context":   req.Context,
 		"namespace": req.Namespace,
 		"resource": map[string]any{
-			"group":    req.Resource.Group,
-			"version":  req.Resource.Version,
-			"resource": req.Resource.Resource,
-			"name":     req.Resource.Name,
+			"group":      req.Resource.Group,
+			"version":    req.Resource.Version,
+			"resource":   req.Resource.Resource,
+			"name":       req.Resource.Name,
+			"namespaced": req.Resource.Namespaced,
 		},
 	}
 
-	var matchedRules []api.AuthorizationRule
+	var matchedRules []policyRule
+	var policies []string
 
 	for _, cp := range e.compiledPolicies {
 		out, _, err := cp.Program.Eval(evalCtx)
@@ -166,41 +366,51 @@ func (e *Evaluator) Evaluate(req AuthzRequest) (bool, error) {
 			continue
 		}
 
-		matchedRules = append(matchedRules, cp.Policy.Rules...)
+		policies = append(policies, cp.Policy.Name)
+		for _, rule := range cp.Policy.Rules {
+			matchedRules = append(matchedRules, policyRule{policy: cp.Policy.Name, rule: rule, regexps: cp.regexps})
+		}
 	}
 
-	if len(matchedRules) == 0 {
-		return false, nil
-	}
+	return matchedRules, policies
+}
 
-	// Deny takes priority: if any deny rule matches, deny
-	for _, rule := range matchedRules {
-		if rule.Effect == api.RuleEffectDeny && ruleMatchesRequest(rule, req) {
-			return false, nil
-		}
-	}
+// restrictsKeys reports whether a rule is scoped to label or annotation keys
+func restrictsKeys(rule api.AuthorizationRule) bool {
+	return len(rule.LabelPrefixes) > 0 || len(rule.AnnotationPrefixes) > 0
+}
 
-	// Check if any allow rule matches
-	for _, rule := range matchedRules {
-		if rule.Effect == api.RuleEffectAllow && ruleMatchesRequest(rule, req) {
-			return true, nil
+// matchesKeyPrefix checks if a label or annotation key starts with any of the
+// prefixes. "*" matches every key.
+func matchesKeyPrefix(prefixes []string, key string) bool {
+	for _, prefix := range prefixes {
+		if prefix == "*" || strings.HasPrefix(key, prefix) {
+			return true
 		}
 	}
-
-	return false, nil
+	return false
 }
 
-// ruleMatchesRequest checks if a rule matches the given request
-func ruleMatchesRequest(rule api.AuthorizationRule, req AuthzRequest) bool {
+// ruleMatchesRequest checks if a rule matches the given request. 'regexps'
+// are the compiled regex patterns of the rule's policy.
+func ruleMatchesRequest(rule api.AuthorizationRule, regexps map[string]*regexp.Regexp, req AuthzRequest) bool {
 	if !matchesTool(rule.Tools, req.Tool) {
 		return false
 	}
 
+	if !matchesVerbs(rule.Verbs, rule.Effect, VerbsForTool(req.Tool)) {
+		return false
+	}
+
 	if !matchesContext(rule.Contexts, req.Context) {
 		return false
 	}
 
-	if !matchesResources(rule.Resources, req.Resource, req.Namespace) {
+	if len(rule.Namespaces) > 0 && !matchesGlobList(rule.Namespaces, req.Namespace) {
+		return false
+	}
+
+	if !matchesResources(rule.Resources, regexps, req.Resource, req.Namespace) {
 		return false
 	}
 
@@ -237,12 +447,12 @@ func matchesContext(patterns []string, ctx string) bool {
 
 // matchesResources checks if a resource matches the resource rules.
 // Empty resource list means the rule applies to all resources.
-func matchesResources(rules []api.ResourceRule, resource ResourceInfo, namespace string) bool {
+func matchesResources(rules []api.ResourceRule, regexps map[string]*regexp.Regexp, resource ResourceInfo, namespace string) bool {
 	if len(rules) == 0 {
 		return true
 	}
 	for _, rule := range rules {
-		if matchesSingleResourceRule(rule, resource, namespace) {
+		if matchesSingleResourceRule(rule, regexps, resource, namespace) {
 			return true
 		}
 	}
@@ -250,16 +460,16 @@ func matchesResources(rules []api.ResourceRule, resource ResourceInfo, namespace
 }
 
 // matchesSingleResourceRule checks if a resource matches a single ResourceRule
-func matchesSingleResourceRule(rule api.ResourceRule, resource ResourceInfo, namespace string) bool {
-	if len(rule.Groups) > 0 && !matchesGlobList(rule.Groups, resource.Group) {
+func matchesSingleResourceRule(rule api.ResourceRule, regexps map[string]*regexp.Regexp, resource ResourceInfo, namespace string) bool {
+	if len(rule.Groups) > 0 && !matchesPatternList(rule.MatchType, rule.Groups, regexps, resource.Group) {
 		return false
 	}
 
-	if len(rule.Versions) > 0 && !matchesGlobList(rule.Versions, resource.Version) {
+	if len(rule.Versions) > 0 && !matchesPatternList(rule.MatchType, rule.Versions, regexps, resource.Version) {
 		return false
 	}
 
-	if len(rule.Resources) > 0 && !matchesGlobList(rule.Resources, resource.Resource) {
+	if len(rule.Resources) > 0 && !matchesPatternList(rule.MatchType, rule.Resources, regexps, resource.Resource) {
 		return false
 	}
 
@@ -284,6 +494,60 @@ func matchesGlobList(patterns []string, value string) bool {
 	return false
 }
 
+// compileResourceRule validates the match type and the group, version and
+// resource patterns of a ResourceRule, adding its regular expressions to
+// 'regexps'
+func compileResourceRule(rule api.ResourceRule, regexps map[string]*regexp.Regexp) error {
+	patterns := slices.Concat(rule.Groups, rule.Versions, rule.Resources)
+	switch rule.MatchType {
+	case "", api.MatchTypeExact:
+	case api.MatchTypeGlob:
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid glob %q: %w", pattern, err)
+			}
+		}
+	case api.MatchTypeRegex:
+		for _, pattern := range patterns {
+			re, err := regexp.Compile("^(?:" + pattern + ")$")
+			if err != nil {
+				return fmt.Errorf("invalid regex %q: %w", pattern, err)
+			}
+			regexps[pattern] = re
+		}
+	default:
+		return fmt.Errorf("unknown match_type %q: must be exact, glob or regex", rule.MatchType)
+	}
+	return nil
+}
+
+// matchesPatternList checks if a value matches any pattern in the list
+// under the given match type. Regex patterns are looked up in 'regexps',
+// anchored at both ends; invalid or unknown patterns never match.
+func matchesPatternList(matchType api.MatchType, patterns []string, regexps map[string]*regexp.Regexp, value string) bool {
+	for _, pattern := range patterns {
+		switch matchType {
+		case api.MatchTypeExact:
+			if pattern == value {
+				return true
+			}
+		case api.MatchTypeGlob:
+			if ok, _ := path.Match(pattern, value); ok {
+				return true
+			}
+		case api.MatchTypeRegex:
+			if re := regexps[pattern]; re != nil && re.MatchString(value) {
+				return true
+			}
+		default:
+			if globMatch(pattern, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // globMatch performs glob-style pattern matching.
 // Supports:
 //   - "*" matches everything
@@ -18,6 +18,7 @@ package authorization
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"kubernetes-mcp/api"
@@ -326,6 +327,55 @@ func TestCELMatchExpressions(t *testing.T) {
 	}
 }
 
+func TestCELResourceNamespaced(t *testing.T) {
+	// Deny every write to cluster-scoped resources, whatever their type
+	config := &api.AuthorizationConfig{
+		AllowAnonymous: true,
+		Policies: []api.AuthorizationPolicy{
+			{
+				Name:  "everyone",
+				Match: api.MatchConfig{Expression: "true"},
+				Rules: []api.AuthorizationRule{{Effect: api.RuleEffectAllow}},
+			},
+			{
+				Name:  "read-only-cluster-scope",
+				Match: api.MatchConfig{Expression: `!resource.namespaced`},
+				Rules: []api.AuthorizationRule{
+					{Effect: api.RuleEffectDeny, Tools: []string{"apply_manifest", "patch_*", "delete_*"}},
+				},
+			},
+		},
+	}
+
+	eval, err := NewEvaluator(config)
+	if err != nil {
+		t.Fatalf("NewEvaluator: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		tool     string
+		resource ResourceInfo
+		want     bool
+	}{
+		{"delete namespaced", "delete_resource", ResourceInfo{Version: "v1", Resource: "configmaps", Namespaced: true}, true},
+		{"delete cluster-scoped", "delete_resource", ResourceInfo{Version: "v1", Resource: "namespaces"}, false},
+		{"patch cluster-scoped CRD", "patch_resource", ResourceInfo{Group: "example.com", Version: "v1", Resource: "clusterwidgets"}, false},
+		{"read cluster-scoped", "get_resource", ResourceInfo{Version: "v1", Resource: "nodes"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, err := eval.Evaluate(AuthzRequest{Payload: map[string]any{}, Tool: tt.tool, Context: "prod", Resource: tt.resource})
+			if err != nil {
+				t.Fatalf("Evaluate: %v", err)
+			}
+			if allowed != tt.want {
+				t.Errorf("got %v, want %v", allowed, tt.want)
+			}
+		})
+	}
+}
+
 // ============================================================================
 // Tool glob pattern matching tests
 // ============================================================================
@@ -452,6 +502,159 @@ func TestContextMatching(t *testing.T) {
 	}
 }
 
+// ============================================================================
+// Namespace matching tests
+// ============================================================================
+
+func TestNamespaceMatching(t *testing.T) {
+	config := &api.AuthorizationConfig{
+		AllowAnonymous: true,
+		Policies: []api.AuthorizationPolicy{
+			{
+				Name:  "developers",
+				Match: api.MatchConfig{Expression: "true"},
+				Rules: []api.AuthorizationRule{
+					{
+						Effect:     api.RuleEffectAllow,
+						Tools:      []string{"*"},
+						Namespaces: []string{"team-a", "team-b", "sandbox-*"},
+					},
+					{
+						Effect:     api.RuleEffectAllow,
+						Tools:      []string{"get_resource"},
+						Namespaces: []string{"*"},
+					},
+					{
+						Effect:     api.RuleEffectDeny,
+						Tools:      []string{"delete_*"},
+						Namespaces: []string{"team-b"},
+					},
+				},
+			},
+		},
+	}
+
+	eval, err := NewEvaluator(config)
+	if err != nil {
+		t.Fatalf("NewEvaluator: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		tool      string
+		namespace string
+		want      bool
+	}{
+		{"allow-listed namespace", "apply_manifest", "team-a", true},
+		{"glob namespace", "apply_manifest", "sandbox-42", true},
+		{"namespace not listed", "apply_manifest", "team-c", false},
+		{"cluster-scoped not listed", "apply_manifest", "", false},
+		{"wildcard namespace", "get_resource", "kube-system", true},
+		{"wildcard matches cluster-scoped", "get_resource", "", true},
+		{"deny takes priority", "delete_resource", "team-b", false},
+		{"deny scoped to its namespace", "delete_resource", "team-a", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, err := eval.Evaluate(AuthzRequest{
+				Payload:   map[string]any{},
+				Tool:      tt.tool,
+				Context:   "development",
+				Namespace: tt.namespace,
+			})
+			if err != nil {
+				t.Fatalf("Evaluate: %v", err)
+			}
+			if allowed != tt.want {
+				t.Errorf("got %v, want %v", allowed, tt.want)
+			}
+		})
+	}
+}
+
+// ============================================================================
+// Verb matching tests
+// ============================================================================
+
+func TestVerbMatching(t *testing.T) {
+	config := &api.AuthorizationConfig{
+		AllowAnonymous: true,
+		Policies: []api.AuthorizationPolicy{
+			{
+				Name:  "developers",
+				Match: api.MatchConfig{Expression: "true"},
+				Rules: []api.AuthorizationRule{
+					{
+						Effect:   api.RuleEffectAllow,
+						Verbs:    []string{"get", "list", "watch"},
+						Contexts: []string{"production"},
+					},
+					{
+						Effect:   api.RuleEffectAllow,
+						Verbs:    []string{"*"},
+						Contexts: []string{"staging"},
+					},
+					{
+						Effect: api.RuleEffectDeny,
+						Verbs:  []string{"exec"},
+					},
+					{
+						// Tool names keep working next to verbs
+						Effect:   api.RuleEffectAllow,
+						Tools:    []string{"scale_resource"},
+						Contexts: []string{"production"},
+					},
+				},
+			},
+			{
+				Name:  "no-deletes",
+				Match: api.MatchConfig{Expression: `"delete" in verbs`},
+				Rules: []api.AuthorizationRule{{Effect: api.RuleEffectDeny, Contexts: []string{"staging"}}},
+			},
+		},
+	}
+
+	eval, err := NewEvaluator(config)
+	if err != nil {
+		t.Fatalf("NewEvaluator: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		tool    string
+		context string
+		want    bool
+	}{
+		{"read in prod", "get_resource", "production", true},
+		{"list and watch in prod", "watch_resources", "production", true},
+		{"write in prod", "apply_manifest", "production", false},
+		{"read plus write needs every verb", "label_resources", "production", false},
+		{"tool name rule", "scale_resource", "production", true},
+		{"write in staging", "apply_manifest", "staging", true},
+		{"denied verb", "exec_command", "staging", false},
+		{"CEL verbs variable", "delete_resource", "staging", false},
+		{"CEL verbs variable on one of several verbs", "drain_node", "staging", false},
+		{"tool without verbs", "unknown_tool", "staging", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, err := eval.Evaluate(AuthzRequest{
+				Payload: map[string]any{},
+				Tool:    tt.tool,
+				Context: tt.context,
+			})
+			if err != nil {
+				t.Fatalf("Evaluate: %v", err)
+			}
+			if allowed != tt.want {
+				t.Errorf("got %v, want %v", allowed, tt.want)
+			}
+		})
+	}
+}
+
 // ============================================================================
 // Resource GVR matching tests
 // ============================================================================
@@ -690,7 +893,7 @@ func TestResourceGVRMatching(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := matchesSingleResourceRule(tt.rule, tt.resource, tt.namespace)
+			got := matchesSingleResourceRule(tt.rule, nil, tt.resource, tt.namespace)
 			if got != tt.want {
 				t.Errorf("matchesSingleResourceRule() = %v, want %v", got, tt.want)
 			}
@@ -2506,6 +2709,65 @@ func TestDenyAcrossPolicies(t *testing.T) {
 	}
 }
 
+func TestDenyOverridesWildcardAllow(t *testing.T) {
+	config := &api.AuthorizationConfig{
+		AllowAnonymous: true,
+		Policies: []api.AuthorizationPolicy{
+			{
+				Name:  "admins",
+				Match: api.MatchConfig{Expression: "true"},
+				Rules: []api.AuthorizationRule{{Effect: api.RuleEffectAllow, Tools: []string{"*"}}},
+			},
+			{
+				Name:  "guardrails",
+				Match: api.MatchConfig{Expression: "true"},
+				Rules: []api.AuthorizationRule{
+					{Effect: api.RuleEffectDeny, Tools: []string{"delete_resource"}},
+					{Effect: api.RuleEffectDeny, Contexts: []string{"legacy"}},
+					{Effect: api.RuleEffectDeny, Namespaces: []string{"kube-system"}},
+					{
+						Effect: api.RuleEffectDeny,
+						Resources: []api.ResourceRule{
+							{Groups: []string{""}, Resources: []string{"secrets"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	eval, err := NewEvaluator(config)
+	if err != nil {
+		t.Fatalf("NewEvaluator: %v", err)
+	}
+
+	pods := ResourceInfo{Version: "v1", Resource: "pods"}
+	tests := []struct {
+		name string
+		req  AuthzRequest
+		want bool
+	}{
+		{"denied tool", AuthzRequest{Tool: "delete_resource", Context: "production", Namespace: "default", Resource: pods}, false},
+		{"denied context", AuthzRequest{Tool: "get_resource", Context: "legacy", Namespace: "default", Resource: pods}, false},
+		{"denied namespace", AuthzRequest{Tool: "get_resource", Context: "production", Namespace: "kube-system", Resource: pods}, false},
+		{"denied resource", AuthzRequest{Tool: "get_resource", Context: "production", Namespace: "default", Resource: ResourceInfo{Version: "v1", Resource: "secrets"}}, false},
+		{"everything else", AuthzRequest{Tool: "delete_resources", Context: "production", Namespace: "default", Resource: pods}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.Payload = map[string]any{}
+			allowed, err := eval.Evaluate(tt.req)
+			if err != nil {
+				t.Fatalf("Evaluate: %v", err)
+			}
+			if allowed != tt.want {
+				t.Errorf("got %v, want %v", allowed, tt.want)
+			}
+		})
+	}
+}
+
 // ============================================================================
 // Default deny: no allow rule matches
 // ============================================================================
@@ -2559,6 +2821,287 @@ func TestDefaultDeny(t *testing.T) {
 	}
 }
 
+// ============================================================================
+// Label and annotation prefixes
+// ============================================================================
+
+func TestMetadataKeyPrefixes(t *testing.T) {
+	config := &api.AuthorizationConfig{
+		AllowAnonymous: true,
+		Policies: []api.AuthorizationPolicy{
+			{
+				Name:  "team",
+				Match: api.MatchConfig{Expression: "true"},
+				Rules: []api.AuthorizationRule{
+					{
+						Effect:             api.RuleEffectAllow,
+						Tools:              []string{"apply_manifest", "label_resource"},
+						LabelPrefixes:      []string{"team.example.com/"},
+						AnnotationPrefixes: []string{"team.example.com/", "docs.example.com/"},
+					},
+					{
+						Effect: api.RuleEffectAllow,
+						Tools:  []string{"annotate_resource"},
+					},
+					{
+						Effect:             api.RuleEffectDeny,
+						Tools:              []string{"*"},
+						AnnotationPrefixes: []string{"docs.example.com/locked"},
+					},
+				},
+			},
+		},
+	}
+
+	eval, err := NewEvaluator(config)
+	if err != nil {
+		t.Fatalf("NewEvaluator: %v", err)
+	}
+
+	// A deny rule scoped to keys does not deny the request itself
+	allowed, _ := eval.Evaluate(AuthzRequest{Payload: map[string]any{}, Tool: "apply_manifest"})
+	if !allowed {
+		t.Fatal("apply_manifest should be allowed; the prefix-scoped deny only forbids keys")
+	}
+
+	scenarios := []struct {
+		name       string
+		tool       string
+		key        string
+		annotation bool
+		want       bool
+	}{
+		{"allowed label prefix", "apply_manifest", "team.example.com/owner", false, true},
+		{"other label prefix", "apply_manifest", "other.com/x", false, false},
+		{"unprefixed label", "label_resource", "app", false, false},
+		{"second annotation prefix", "apply_manifest", "docs.example.com/runbook", true, true},
+		{"denied annotation prefix", "apply_manifest", "docs.example.com/locked-by", true, false},
+		{"unrestricted rule", "annotate_resource", "other.com/x", true, true},
+		{"deny beats unrestricted rule", "annotate_resource", "docs.example.com/locked", true, false},
+		{"no matching rule", "patch_resource", "team.example.com/owner", false, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			req := AuthzRequest{Payload: map[string]any{}, Tool: s.tool}
+			got := eval.IsLabelPrefixAllowed(req, s.key)
+			if s.annotation {
+				got = eval.IsAnnotationPrefixAllowed(req, s.key)
+			}
+			if got != s.want {
+				t.Errorf("got %v, want %v", got, s.want)
+			}
+		})
+	}
+}
+
+// ============================================================================
+// Decision details
+// ============================================================================
+
+func TestEvaluateDecision(t *testing.T) {
+	config := &api.AuthorizationConfig{
+		Policies: []api.AuthorizationPolicy{
+			{
+				Name:  "devs-read",
+				Match: api.MatchConfig{Expression: `"devs" in payload.groups`},
+				Rules: []api.AuthorizationRule{
+					{Effect: api.RuleEffectAllow, Tools: []string{"get_*", "delete_resource"}, Contexts: []string{"*"}},
+				},
+			},
+			{
+				Name:  "no-prod-deletes",
+				Match: api.MatchConfig{Expression: "true"},
+				Rules: []api.AuthorizationRule{
+					{Effect: api.RuleEffectDeny, Tools: []string{"delete_resource"}, Contexts: []string{"prod"}},
+				},
+			},
+			{
+				Name:  "admins",
+				Match: api.MatchConfig{Expression: `"admins" in payload.groups`},
+				Rules: []api.AuthorizationRule{
+					{Effect: api.RuleEffectAllow, Tools: []string{"*"}, Contexts: []string{"*"}},
+				},
+			},
+		},
+	}
+	eval, err := NewEvaluator(config)
+	if err != nil {
+		t.Fatalf("NewEvaluator: %v", err)
+	}
+	dev := map[string]any{"sub": "dev", "groups": []any{"devs"}}
+
+	tests := []struct {
+		name     string
+		req      AuthzRequest
+		allowed  bool
+		matched  []string
+		deciding string
+	}{
+		{
+			name:     "allowed by the matching allow rule",
+			req:      AuthzRequest{Payload: dev, Tool: "get_resource", Context: "prod"},
+			allowed:  true,
+			matched:  []string{"devs-read", "no-prod-deletes"},
+			deciding: "devs-read",
+		},
+		{
+			name:     "denied by a deny rule of another policy",
+			req:      AuthzRequest{Payload: dev, Tool: "delete_resource", Context: "prod"},
+			matched:  []string{"devs-read", "no-prod-deletes"},
+			deciding: "no-prod-deletes",
+		},
+		{
+			name:    "default deny has no deciding policy",
+			req:     AuthzRequest{Payload: dev, Tool: "exec_command", Context: "prod"},
+			matched: []string{"devs-read", "no-prod-deletes"},
+		},
+		{
+			name: "anonymous request matches nothing",
+			req:  AuthzRequest{Tool: "get_resource", Context: "prod"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision, err := eval.EvaluateDecision(tt.req)
+			if err != nil {
+				t.Fatalf("EvaluateDecision: %v", err)
+			}
+			if decision.Allowed != tt.allowed {
+				t.Errorf("allowed = %v, want %v", decision.Allowed, tt.allowed)
+			}
+			if fmt.Sprint(decision.MatchedPolicies) != fmt.Sprint(tt.matched) {
+				t.Errorf("matched policies = %v, want %v", decision.MatchedPolicies, tt.matched)
+			}
+			if decision.DecidingPolicy != tt.deciding {
+				t.Errorf("deciding policy = %q, want %q", decision.DecidingPolicy, tt.deciding)
+			}
+		})
+	}
+}
+
+func TestMatchCache(t *testing.T) {
+	config := &api.AuthorizationConfig{
+		Policies: []api.AuthorizationPolicy{{
+			Name:  "devs",
+			Match: api.MatchConfig{Expression: `"devs" in payload.groups`},
+			Rules: []api.AuthorizationRule{{Effect: api.RuleEffectAllow, LabelPrefixes: []string{"team/"}}},
+		}},
+	}
+	eval, err := NewEvaluator(config)
+	if err != nil {
+		t.Fatalf("NewEvaluator: %v", err)
+	}
+	other, err := NewEvaluator(&api.AuthorizationConfig{})
+	if err != nil {
+		t.Fatalf("NewEvaluator: %v", err)
+	}
+
+	req := AuthzRequest{Payload: map[string]any{"groups": []any{"devs"}}, Tool: "label_resource"}
+	cached := req.WithMatchCache()
+	if allowed, _ := eval.Evaluate(cached); !allowed {
+		t.Fatal("expected the request to be allowed")
+	}
+
+	// Later checks of the cached request reuse the matches instead of
+	// running the programs, which are gone now
+	eval.compiledPolicies = nil
+	if !eval.IsLabelPrefixAllowed(cached, "team/owner") || eval.IsLabelPrefixAllowed(cached, "owner") {
+		t.Error("label checks of the cached request should use the cached matches")
+	}
+	if allowed, _ := eval.Evaluate(req); allowed {
+		t.Error("a request without cache should be evaluated again")
+	}
+
+	// Another evaluator does not reuse the matches of the first one
+	if allowed, _ := other.Evaluate(cached); allowed {
+		t.Error("a different evaluator should evaluate its own policies")
+	}
+}
+
+// ============================================================================
+// Resource rule match types
+// ============================================================================
+
+func TestResourceRuleMatchTypes(t *testing.T) {
+	config := &api.AuthorizationConfig{
+		AllowAnonymous: true,
+		Policies: []api.AuthorizationPolicy{
+			{
+				Name:  "everyone",
+				Match: api.MatchConfig{Expression: "true"},
+				Rules: []api.AuthorizationRule{
+					{Effect: api.RuleEffectAllow},
+					{Effect: api.RuleEffectDeny, Resources: []api.ResourceRule{
+						{MatchType: api.MatchTypeRegex, Groups: []string{`.*\.example\.com`}},
+					}},
+					{Effect: api.RuleEffectDeny, Resources: []api.ResourceRule{
+						{MatchType: api.MatchTypeGlob, Groups: []string{"apps"}, Resources: []string{"stateful?ets"}},
+					}},
+					{Effect: api.RuleEffectDeny, Resources: []api.ResourceRule{
+						{MatchType: api.MatchTypeExact, Groups: []string{"batch"}, Resources: []string{"cron*"}},
+					}},
+				},
+			},
+		},
+	}
+	eval, err := NewEvaluator(config)
+	if err != nil {
+		t.Fatalf("NewEvaluator: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		resource ResourceInfo
+		want     bool
+	}{
+		{"regex matches a CRD group", ResourceInfo{Group: "widgets.example.com", Version: "v1", Resource: "widgets"}, false},
+		{"regex is anchored", ResourceInfo{Group: "widgets.example.com.evil.io", Version: "v1", Resource: "widgets"}, true},
+		{"regex does not match the core group", ResourceInfo{Version: "v1", Resource: "pods"}, true},
+		{"glob matches", ResourceInfo{Group: "apps", Version: "v1", Resource: "statefulsets"}, false},
+		{"glob does not match", ResourceInfo{Group: "apps", Version: "v1", Resource: "deployments"}, true},
+		{"exact takes * literally", ResourceInfo{Group: "batch", Version: "v1", Resource: "cronjobs"}, true},
+		{"exact matches the literal", ResourceInfo{Group: "batch", Version: "v1", Resource: "cron*"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, err := eval.Evaluate(AuthzRequest{Tool: "get_resource", Context: "prod", Resource: tt.resource})
+			if err != nil {
+				t.Fatalf("Evaluate: %v", err)
+			}
+			if allowed != tt.want {
+				t.Errorf("got %v, want %v", allowed, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourceRuleInvalidPatterns(t *testing.T) {
+	tests := []struct {
+		name string
+		rule api.ResourceRule
+	}{
+		{"invalid regex", api.ResourceRule{MatchType: api.MatchTypeRegex, Groups: []string{"(unclosed"}}},
+		{"invalid glob", api.ResourceRule{MatchType: api.MatchTypeGlob, Resources: []string{"[pods"}}},
+		{"unknown match type", api.ResourceRule{MatchType: "fuzzy", Resources: []string{"pods"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewEvaluator(&api.AuthorizationConfig{
+				Policies: []api.AuthorizationPolicy{{
+					Name:  "broken",
+					Match: api.MatchConfig{Expression: "true"},
+					Rules: []api.AuthorizationRule{{Effect: api.RuleEffectAllow, Resources: []api.ResourceRule{tt.rule}}},
+				}},
+			})
+			if err == nil || !strings.Contains(err.Error(), "broken") {
+				t.Errorf("NewEvaluator error = %v, want one naming policy broken", err)
+			}
+		})
+	}
+}
+
 // ============================================================================
 // Benchmark
 // ============================================================================
@@ -2647,3 +3190,38 @@ func BenchmarkEvaluate(b *testing.B) {
 		eval.Evaluate(req)
 	}
 }
+
+func TestResourceRuleRegexpsPerEvaluator(t *testing.T) {
+	newEval := func(pattern string) *Evaluator {
+		t.Helper()
+		eval, err := NewEvaluator(&api.AuthorizationConfig{
+			AllowAnonymous: true,
+			Policies: []api.AuthorizationPolicy{{
+				Name:  "p",
+				Match: api.MatchConfig{Expression: "true"},
+				Rules: []api.AuthorizationRule{{Effect: api.RuleEffectAllow, Resources: []api.ResourceRule{
+					{MatchType: api.MatchTypeRegex, Resources: []string{pattern}},
+				}}},
+			}},
+		})
+		if err != nil {
+			t.Fatalf("NewEvaluator: %v", err)
+		}
+		return eval
+	}
+
+	// A reloaded configuration compiles its own patterns, dropping the old ones
+	first, second := newEval("pods|secrets"), newEval("deploy.*")
+	if len(first.compiledPolicies[0].regexps) != 1 || len(second.compiledPolicies[0].regexps) != 1 {
+		t.Fatalf("expected one compiled pattern per evaluator")
+	}
+	if _, ok := second.compiledPolicies[0].regexps["pods|secrets"]; ok {
+		t.Fatalf("expected the patterns of another evaluator not to be shared")
+	}
+	for eval, resource := range map[*Evaluator]string{first: "secrets", second: "deployments"} {
+		allowed, err := eval.Evaluate(AuthzRequest{Tool: "get_resource", Context: "prod", Resource: ResourceInfo{Version: "v1", Resource: resource}})
+		if err != nil || !allowed {
+			t.Errorf("expected %s allowed, got %v, %v", resource, allowed, err)
+		}
+	}
+}
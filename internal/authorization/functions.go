@@ -0,0 +1,115 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authorization
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common"
+	"github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+// claimFunctions returns the CEL helpers for the common claim checks:
+//
+//	hasGroup("admins")                  "admins" is in payload.groups
+//	claimContains("roles", "editor")    payload.roles holds "editor" (list, or string of space or comma separated values)
+//	emailDomain()                       the domain of payload.email, "" without one
+//
+// Each is a macro that passes 'payload' to a function of the same name, so
+// the explicit forms (hasGroup(payload, "admins"), ...) work too. Missing or
+// mistyped claims make them false or "" instead of an evaluation error.
+func claimFunctions() []cel.EnvOption {
+	return []cel.EnvOption{
+		cel.Macros(
+			payloadMacro("hasGroup", 1),
+			payloadMacro("claimContains", 2),
+			payloadMacro("emailDomain", 0),
+		),
+		cel.Function("hasGroup",
+			cel.Overload("hasGroup_dyn_string", []*cel.Type{cel.DynType, cel.StringType}, cel.BoolType,
+				cel.BinaryBinding(func(payload, group ref.Val) ref.Val {
+					return types.Bool(claimHas(claimValue(payload, "groups"), string(group.(types.String))))
+				}),
+			),
+		),
+		cel.Function("claimContains",
+			cel.Overload("claimContains_dyn_string_string", []*cel.Type{cel.DynType, cel.StringType, cel.StringType}, cel.BoolType,
+				cel.FunctionBinding(func(args ...ref.Val) ref.Val {
+					claim := claimValue(args[0], string(args[1].(types.String)))
+					return types.Bool(claimHas(claim, string(args[2].(types.String))))
+				}),
+			),
+		),
+		cel.Function("emailDomain",
+			cel.Overload("emailDomain_dyn", []*cel.Type{cel.DynType}, cel.StringType,
+				cel.UnaryBinding(func(payload ref.Val) ref.Val {
+					email, _ := claimValue(payload, "email").(string)
+					at := strings.LastIndex(email, "@")
+					if at < 0 {
+						return types.String("")
+					}
+					return types.String(strings.ToLower(email[at+1:]))
+				}),
+			),
+		),
+	}
+}
+
+// payloadMacro expands name(args...) into name(payload, args...)
+func payloadMacro(name string, argCount int) cel.Macro {
+	return cel.GlobalMacro(name, argCount, func(eh cel.MacroExprFactory, _ ast.Expr, args []ast.Expr) (ast.Expr, *common.Error) {
+		return eh.NewCall(name, append([]ast.Expr{eh.NewIdent("payload")}, args...)...), nil
+	})
+}
+
+// claimValue returns a claim of the payload, or nil when the payload is not
+// a map or lacks it
+func claimValue(payload ref.Val, claim string) any {
+	m, ok := payload.Value().(map[string]any)
+	if !ok {
+		return nil
+	}
+	return m[claim]
+}
+
+// claimHas reports whether a list claim holds the value, or a string claim
+// has it as one of its space or comma separated values ('scope' style).
+// Values are compared whole: "admin" is not in "superadmins".
+func claimHas(claim any, value string) bool {
+	switch c := claim.(type) {
+	case string:
+		return slices.Contains(strings.FieldsFunc(c, func(r rune) bool { return r == ',' || unicode.IsSpace(r) }), value)
+	case []any:
+		for _, item := range c {
+			if s, ok := item.(string); ok && s == value {
+				return true
+			}
+		}
+	case []string:
+		for _, item := range c {
+			if item == value {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authorization
+
+import (
+	"testing"
+
+	"kubernetes-mcp/api"
+)
+
+func TestClaimFunctions(t *testing.T) {
+	jane := map[string]any{
+		"email":  "Jane@Company.COM",
+		"groups": []any{"developers", "oncall"},
+		"scope":  "openid profile mcp:write",
+		"roles":  []any{"editor"},
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		payload    map[string]any
+		want       bool
+	}{
+		{"hasGroup member", `hasGroup("oncall")`, jane, true},
+		{"hasGroup non-member", `hasGroup("admins")`, jane, false},
+		{"hasGroup without groups claim", `hasGroup("admins")`, map[string]any{"sub": "x"}, false},
+		{"hasGroup explicit payload", `hasGroup(payload, "developers")`, jane, true},
+		{"claimContains list", `claimContains("roles", "editor")`, jane, true},
+		{"claimContains string", `claimContains("scope", "mcp:write")`, jane, true},
+		{"claimContains comma separated", `claimContains("tenants", "beta")`, map[string]any{"tenants": "alpha, beta"}, true},
+		{"claimContains near miss in string", `claimContains("roles", "admin")`, map[string]any{"roles": "not-admin"}, false},
+		{"hasGroup near miss in string", `hasGroup("admin")`, map[string]any{"groups": "superadmins"}, false},
+		{"hasGroup near miss in list", `hasGroup("admin")`, map[string]any{"groups": []any{"superadmins"}}, false},
+		{"claimContains missing claim", `claimContains("tenants", "acme")`, jane, false},
+		{"emailDomain", `emailDomain() == "company.com"`, jane, true},
+		{"emailDomain without email", `emailDomain() == ""`, map[string]any{"sub": "x"}, true},
+		{"combined", `hasGroup("developers") && emailDomain() == "company.com"`, jane, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eval, err := NewEvaluator(&api.AuthorizationConfig{
+				Policies: []api.AuthorizationPolicy{{
+					Name:  "p",
+					Match: api.MatchConfig{Expression: tt.expression},
+					Rules: []api.AuthorizationRule{{Effect: api.RuleEffectAllow}},
+				}},
+			})
+			if err != nil {
+				t.Fatalf("NewEvaluator: %v", err)
+			}
+			allowed, err := eval.Evaluate(AuthzRequest{Payload: tt.payload, Tool: "get_resource", Context: "prod"})
+			if err != nil {
+				t.Fatalf("Evaluate: %v", err)
+			}
+			if allowed != tt.want {
+				t.Errorf("%s = %v, want %v", tt.expression, allowed, tt.want)
+			}
+		})
+	}
+}
+
+func TestClaimFunctionsMisuse(t *testing.T) {
+	for _, expression := range []string{
+		`hasGroup(1)`,
+		`hasGroup("a", "b", "c")`,
+		`claimContains("roles")`,
+		`emailDomain() == 1`,
+	} {
+		t.Run(expression, func(t *testing.T) {
+			_, err := NewEvaluator(&api.AuthorizationConfig{
+				Policies: []api.AuthorizationPolicy{{Name: "p", Match: api.MatchConfig{Expression: expression}}},
+			})
+			if err == nil {
+				t.Errorf("expected %s to fail compilation", expression)
+			}
+		})
+	}
+}
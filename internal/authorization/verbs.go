@@ -0,0 +1,145 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authorization
+
+import "kubernetes-mcp/api"
+
+// Canonical verbs of the tools, modelled on Kubernetes RBAC verbs plus the
+// MCP-specific ones. Rules and CEL expressions can target them instead of
+// enumerating tool names.
+const (
+	VerbGet         = "get"
+	VerbList        = "list"
+	VerbWatch       = "watch"
+	VerbCreate      = "create"
+	VerbUpdate      = "update"
+	VerbPatch       = "patch"
+	VerbDelete      = "delete"
+	VerbExec        = "exec"
+	VerbPortForward = "portforward"
+	VerbUse         = "use"
+)
+
+// ToolVerbs maps each tool to the verbs it performs. A tool missing from
+// the map has no verbs, so only tool-name rules can match it.
+var ToolVerbs = map[string][]string{
+	// Read
+	"get_resource":            {VerbGet},
+	"describe_resource":       {VerbGet},
+	"get_resource_tree":       {VerbGet, VerbList},
+	"describe_namespace":      {VerbGet, VerbList},
+	"describe_hpa":            {VerbGet},
+	"resource_exists":         {VerbGet},
+	"diff_manifest":           {VerbGet},
+	"snapshot_resource":       {VerbGet},
+	"export_resource":         {VerbGet},
+	"check_permission":        {VerbGet},
+	"test_authorization":      {VerbGet},
+	"get_cluster_info":        {VerbGet},
+	"get_current_context":     {VerbGet},
+	"get_job_status":          {VerbGet},
+	"get_node_status":         {VerbGet},
+	"get_node_metrics":        {VerbGet, VerbList},
+	"top_nodes":               {VerbList},
+	"get_pdb_status":          {VerbGet},
+	"get_pod_context":         {VerbGet},
+	"get_pod_metrics":         {VerbGet, VerbList},
+	"get_probe_status":        {VerbGet},
+	"get_rollout_status":      {VerbGet},
+	"rollout_history":         {VerbGet, VerbList},
+	"get_logs":                {VerbGet},
+	"follow_logs":             {VerbGet},
+	"wait_for_log_pattern":    {VerbGet},
+	"get_logs_by_selector":    {VerbGet, VerbList},
+	"get_logs_multi_context":  {VerbGet, VerbList},
+	"list_resources":          {VerbList},
+	"list_events":             {VerbList},
+	"get_events_for_resource": {VerbGet, VerbList},
+	"list_namespaces":         {VerbList},
+	"list_webhooks":           {VerbList},
+	"list_api_resources":      {VerbList},
+	"list_api_versions":       {VerbList},
+	"explain_resource":        {VerbGet},
+	"list_contexts":           {VerbList},
+	"check_contexts":          {VerbGet},
+	"watch_resources":         {VerbList, VerbWatch},
+	"wait_for_condition":      {VerbGet},
+
+	// Write
+	"apply_manifest":     {VerbCreate, VerbUpdate},
+	"apply_and_wait":     {VerbCreate, VerbUpdate},
+	"create_resource":    {VerbCreate},
+	"replace_resource":   {VerbUpdate},
+	"restore_resource":   {VerbCreate, VerbPatch},
+	"create_sa_token":    {VerbCreate},
+	"trigger_cronjob":    {VerbCreate},
+	"patch_resource":     {VerbPatch},
+	"patch_list_element": {VerbPatch},
+	"set_image":          {VerbPatch},
+	"label_resource":     {VerbPatch},
+	"annotate_resource":  {VerbPatch},
+	"label_resources":    {VerbList, VerbPatch},
+	"annotate_resources": {VerbList, VerbPatch},
+	"scale_resource":     {VerbPatch},
+	"restart_rollout":    {VerbPatch},
+	"pause_rollout":      {VerbPatch},
+	"resume_rollout":     {VerbPatch},
+	"undo_rollout":       {VerbPatch},
+	"cordon_node":        {VerbPatch},
+	"uncordon_node":      {VerbPatch},
+
+	// Delete
+	"delete_resource":  {VerbDelete},
+	"delete_resources": {VerbList, VerbDelete},
+	"drain_node":       {VerbPatch, VerbDelete},
+
+	// Exec and session
+	"exec_command":      {VerbExec},
+	"copy_from_pod":     {VerbExec},
+	"copy_to_pod":       {VerbExec},
+	"port_forward":      {VerbPortForward},
+	"stop_port_forward": {VerbPortForward},
+	"switch_context":    {VerbUse},
+}
+
+// VerbsForTool returns the verbs a tool performs
+func VerbsForTool(tool string) []string {
+	return ToolVerbs[tool]
+}
+
+// matchesVerbs checks the verbs of a tool against the 'verbs' of a rule.
+// Empty list matches everything. An allow rule must cover every verb of the
+// tool, so "read verbs" never grants apply_manifest; a deny rule matches as
+// soon as one verb is listed. Supports glob patterns.
+func matchesVerbs(patterns []string, effect api.RuleEffect, verbs []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	if len(verbs) == 0 {
+		return false
+	}
+	for _, verb := range verbs {
+		matched := matchesGlobList(patterns, verb)
+		if effect == api.RuleEffectDeny && matched {
+			return true
+		}
+		if effect != api.RuleEffectDeny && !matched {
+			return false
+		}
+	}
+	return effect != api.RuleEffectDeny
+}
@@ -17,8 +17,12 @@ limitations under the License.
 package config
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"kubernetes-mcp/api"
 	"os"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -35,19 +39,68 @@ func Unmarshal(bytes []byte) (config api.Configuration, err error) {
 	return config, err
 }
 
-// ReadFile TODO
-func ReadFile(filepath string) (config api.Configuration, err error) {
+// ReadFile reads and parses a config file. The warnings are problems that
+// did not prevent loading it, such as unknown fields.
+func ReadFile(filepath string) (config api.Configuration, warnings []string, err error) {
 	var fileBytes []byte
 	fileBytes, err = os.ReadFile(filepath)
 	if err != nil {
-		return config, err
+		return config, nil, err
 	}
 
+	return Parse(fileBytes)
+}
+
+// Parse expands the environment variables of the content of a config file
+// and unmarshals it, reporting unknown fields as 'unknown_fields' says
+func Parse(fileBytes []byte) (config api.Configuration, warnings []string, err error) {
 	// Expand environment variables present in the config
 	// This will cause expansion in the following way: field: "$FIELD" -> field: "value_of_field"
-	fileExpandedEnv := os.ExpandEnv(string(fileBytes))
+	// See expandEnv for defaults (${FIELD:-value}) and required variables (${FIELD:?message})
+	expanded, warnings, err := expandEnv(string(fileBytes))
+	if err != nil {
+		return config, nil, err
+	}
+	fileExpandedEnv := []byte(expanded)
 
-	config, err = Unmarshal([]byte(fileExpandedEnv))
+	config, err = Unmarshal(fileExpandedEnv)
+	if err != nil {
+		return config, nil, err
+	}
 
-	return config, err
+	switch config.UnknownFields {
+	case "ignore":
+		return config, warnings, nil
+	case "", "warn":
+		return config, append(warnings, unknownFields(fileExpandedEnv)...), nil
+	case "error":
+		if unknown := unknownFields(fileExpandedEnv); len(unknown) > 0 {
+			return config, nil, fmt.Errorf("unknown fields in config: %s", strings.Join(unknown, "; "))
+		}
+		return config, warnings, nil
+	default:
+		return config, nil, fmt.Errorf("unknown_fields must be warn, error or ignore, got %q", config.UnknownFields)
+	}
+}
+
+// unknownFields decodes the content strictly and returns its keys that
+// match no setting, with their line, e.g. "line 12: field polices not found
+// in type api.AuthorizationConfig"
+func unknownFields(content []byte) []string {
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+
+	var strict api.Configuration
+	var typeErr *yaml.TypeError
+	if err := decoder.Decode(&strict); !errors.As(err, &typeErr) {
+		return nil
+	}
+
+	var unknown []string
+	for _, msg := range typeErr.Errors {
+		if strings.Contains(msg, "not found in type") {
+			unknown = append(unknown, msg)
+		}
+	}
+	return unknown
 }
@@ -0,0 +1,121 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseUnknownFields(t *testing.T) {
+	const typos = `
+authorization:
+  polices: []
+kubernetes:
+  contexts:
+    - name: prod
+      allowed_namespace: ["shop"]
+`
+
+	tests := []struct {
+		name     string
+		mode     string
+		warnings int
+		wantErr  string
+	}{
+		{name: "warn by default", warnings: 2},
+		{name: "warn", mode: "warn", warnings: 2},
+		{name: "ignore", mode: "ignore"},
+		{name: "error", mode: "error", wantErr: "field allowed_namespace not found"},
+		{name: "invalid mode", mode: "maybe", wantErr: "unknown_fields must be"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := typos
+			if tt.mode != "" {
+				content += "unknown_fields: " + tt.mode + "\n"
+			}
+			config, warnings, err := Parse([]byte(content))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if len(warnings) != tt.warnings {
+				t.Fatalf("warnings = %q, want %d", warnings, tt.warnings)
+			}
+			if tt.warnings > 0 && (!strings.Contains(warnings[0], "line 3: field polices") || !strings.Contains(warnings[1], "line 7: field allowed_namespace")) {
+				t.Errorf("warnings should name the field and its line, got %q", warnings)
+			}
+			if len(config.Kubernetes.Contexts) != 1 {
+				t.Errorf("the known fields should still be loaded, got %+v", config.Kubernetes)
+			}
+		})
+	}
+
+	if _, warnings, err := Parse([]byte("kubernetes:\n  default_context: prod\n")); err != nil || len(warnings) != 0 {
+		t.Errorf("a clean file should load without warnings, got %q, %v", warnings, err)
+	}
+}
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("MCP_TEST_HOST", "0.0.0.0:8080")
+	t.Setenv("MCP_TEST_EMPTY", "")
+
+	tests := []struct {
+		name     string
+		content  string
+		want     string
+		warnings int
+		wantErr  string
+	}{
+		{name: "plain", content: "host: $MCP_TEST_HOST", want: "host: 0.0.0.0:8080"},
+		{name: "braces", content: "host: ${MCP_TEST_HOST}", want: "host: 0.0.0.0:8080"},
+		{name: "default unused", content: "host: ${MCP_TEST_HOST:-localhost:80}", want: "host: 0.0.0.0:8080"},
+		{name: "default when unset", content: "host: ${MCP_TEST_UNSET:-localhost:80}", want: "host: localhost:80"},
+		{name: "default when empty", content: "host: ${MCP_TEST_EMPTY:-localhost:80}", want: "host: localhost:80"},
+		{name: "required set", content: "host: ${MCP_TEST_HOST:?set the host}", want: "host: 0.0.0.0:8080"},
+		{name: "required unset", content: "a: ${MCP_TEST_UNSET:?set the JWKS URI}\nb: ${MCP_TEST_EMPTY:?}", wantErr: "MCP_TEST_UNSET: set the JWKS URI\nenvironment variable MCP_TEST_EMPTY: required but not set"},
+		{name: "unset warns once", content: "a: $MCP_TEST_UNSET\nb: ${MCP_TEST_UNSET}", want: "a: \nb: ", warnings: 1},
+		{name: "set but empty does not warn", content: "a: $MCP_TEST_EMPTY", want: "a: "},
+		{name: "regex anchors are kept", content: `groups: ['.*\.example\.com$']`, want: `groups: ['.*\.example\.com$']`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, warnings, err := expandEnv(tt.content)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expandEnv: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expanded = %q, want %q", got, tt.want)
+			}
+			if len(warnings) != tt.warnings {
+				t.Errorf("warnings = %q, want %d", warnings, tt.warnings)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// envNameRe matches the variable names the expander warns about when unset;
+// shell specials such as $$ or $1 expand to "" silently, like os.ExpandEnv
+var envNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// expandEnv replaces the environment variables of a config file:
+//
+//	$VAR, ${VAR}          the value, "" when unset (with a warning)
+//	${VAR:-default}       the value, or 'default' when unset or empty
+//	${VAR:?message}       the value; an error with 'message' when unset or empty
+//
+// A default or message cannot contain "}". Every required variable that is
+// missing is reported in the error.
+func expandEnv(content string) (string, []string, error) {
+	var warnings []string
+	var missing []error
+
+	expanded := os.Expand(content, func(expr string) string {
+		if name, def, ok := strings.Cut(expr, ":-"); ok {
+			if value := os.Getenv(name); value != "" {
+				return value
+			}
+			return def
+		}
+
+		if name, message, ok := strings.Cut(expr, ":?"); ok {
+			if value := os.Getenv(name); value != "" {
+				return value
+			}
+			if message == "" {
+				message = "required but not set"
+			}
+			missing = append(missing, fmt.Errorf("environment variable %s: %s", name, message))
+			return ""
+		}
+
+		value, set := os.LookupEnv(expr)
+		if !set && envNameRe.MatchString(expr) {
+			warning := fmt.Sprintf("environment variable %s is not set, it expands to an empty string", expr)
+			if !slices.Contains(warnings, warning) {
+				warnings = append(warnings, warning)
+			}
+		}
+		return value
+	})
+
+	if len(missing) > 0 {
+		return "", nil, errors.Join(missing...)
+	}
+	return expanded, warnings, nil
+}
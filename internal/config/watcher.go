@@ -0,0 +1,128 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"kubernetes-mcp/api"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDebounce groups the events of one save (editors and ConfigMap
+// volume updates write in several steps) into a single reload
+const reloadDebounce = 500 * time.Millisecond
+
+// Watcher re-reads the config file when it changes or on SIGHUP and hands
+// the new configuration to a callback. A file that cannot be read or
+// parsed is logged and skipped, so the previous configuration stays.
+type Watcher struct {
+	logger *slog.Logger
+	path   string
+
+	// apply validates and applies a new configuration; an error keeps the
+	// previous one
+	apply func(api.Configuration) error
+
+	// last is the content of the last applied file, so writes that do not
+	// change it (touch, a ConfigMap resync) do not reload
+	last []byte
+}
+
+// NewWatcher returns a watcher of the config file at 'path'. The file is
+// read once to know its current content.
+func NewWatcher(logger *slog.Logger, path string, apply func(api.Configuration) error) *Watcher {
+	last, _ := os.ReadFile(path)
+	return &Watcher{logger: logger, path: path, apply: apply, last: last}
+}
+
+// Watch reloads the file on SIGHUP and whenever something changes in its
+// directory, until stop is closed. The directory is watched rather than
+// the file so atomic renames and ConfigMap volume updates (a symlink swap)
+// are seen.
+func (w *Watcher) Watch(stop <-chan struct{}) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var events <-chan fsnotify.Event
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		w.logger.Warn("failed to watch the config file, reload it with SIGHUP", "error", err)
+	} else {
+		defer watcher.Close()
+		events = watcher.Events
+		dir, _ := filepath.Abs(filepath.Dir(w.path))
+		if err := watcher.Add(dir); err != nil {
+			w.logger.Warn("failed to watch the config directory, reload it with SIGHUP", "directory", dir, "error", err)
+		}
+	}
+
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-stop:
+			return
+		case <-hup:
+			w.reload("SIGHUP", true)
+		case _, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			debounce = time.After(reloadDebounce)
+		case <-debounce:
+			debounce = nil
+			w.reload("file change", false)
+		}
+	}
+}
+
+// reload reads the file and applies it when it changed, or always when
+// 'force' is set
+func (w *Watcher) reload(reason string, force bool) {
+	content, err := os.ReadFile(w.path)
+	if err != nil {
+		w.logger.Error("failed to read the config file, keeping the previous configuration", "reason", reason, "error", err)
+		return
+	}
+	if !force && bytes.Equal(content, w.last) {
+		return
+	}
+
+	config, warnings, err := Parse(content)
+	if err != nil {
+		w.logger.Error("failed to parse the config file, keeping the previous configuration", "reason", reason, "error", err)
+		return
+	}
+	for _, warning := range warnings {
+		w.logger.Warn("config file warning", "path", w.path, "warning", warning)
+	}
+	if err := w.apply(config); err != nil {
+		w.logger.Error("invalid configuration, keeping the previous one", "reason", reason, "error", err)
+		return
+	}
+	w.last = content
+	w.logger.Info("reloaded configuration", "reason", reason, "path", w.path)
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"kubernetes-mcp/api"
+)
+
+func TestWatcherReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("server:\n  name: first\n")
+
+	var applied []string
+	reject := false
+	w := NewWatcher(slog.New(slog.NewTextHandler(io.Discard, nil)), path, func(config api.Configuration) error {
+		if reject {
+			return errors.New("rejected")
+		}
+		applied = append(applied, config.Server.Name)
+		return nil
+	})
+
+	// Unchanged content is not applied again, unless forced (SIGHUP)
+	w.reload("file change", false)
+	w.reload("SIGHUP", true)
+	if len(applied) != 1 || applied[0] != "first" {
+		t.Fatalf("applied = %v, want only the forced reload", applied)
+	}
+
+	write("server: [not a map\n")
+	w.reload("file change", false)
+
+	write("server:\n  name: second\n")
+	reject = true
+	w.reload("file change", false)
+	reject = false
+	w.reload("file change", false)
+
+	if len(applied) != 2 || applied[1] != "second" {
+		t.Fatalf("applied = %v, want the invalid files skipped and the rejected one retried", applied)
+	}
+}
+
+func TestWatcherWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("server:\n  name: first\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	applied := make(chan string, 1)
+	w := NewWatcher(slog.New(slog.NewTextHandler(io.Discard, nil)), path, func(config api.Configuration) error {
+		applied <- config.Server.Name
+		return nil
+	})
+	stop := make(chan struct{})
+	defer close(stop)
+	go w.Watch(stop)
+
+	// Give the watcher time to register the directory
+	time.Sleep(100 * time.Millisecond)
+	if err := os.WriteFile(path, []byte("server:\n  name: second\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case name := <-applied:
+		if name != "second" {
+			t.Fatalf("applied %q, want second", name)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the file change was not applied")
+	}
+}
@@ -32,6 +32,7 @@ type ApplicationContext struct {
 	Context    context.Context
 	Logger     *slog.Logger
 	Config     *api.Configuration
+	ConfigPath string
 	ToolPrefix string
 }
 
@@ -60,11 +61,15 @@ func NewApplicationContext() (*ApplicationContext, error) {
 	var configFlag = flag.String("config", "config.yaml", "path to the config file")
 	flag.Parse()
 
-	configContent, err := config.ReadFile(*configFlag)
+	configContent, warnings, err := config.ReadFile(*configFlag)
 	if err != nil {
 		return appCtx, err
 	}
+	for _, warning := range warnings {
+		appCtx.Logger.Warn("config file warning", "path", *configFlag, "warning", warning)
+	}
 	appCtx.Config = &configContent
+	appCtx.ConfigPath = *configFlag
 	serverName := configContent.Server.Name
 	if serverName == "" {
 		serverName = defaultServerName
@@ -0,0 +1,160 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jqutil
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/itchyny/gojq"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// evaluationTimeout stops a runaway program ('repeat', 'range(infinite)')
+// that would otherwise never return.
+const evaluationTimeout = 10 * time.Second
+
+// Evaluator processes jq expressions on YAML data. The YAML is read as JSON,
+// run through the jq program and written back as YAML, so jq and yq see the
+// same document.
+type Evaluator struct{}
+
+// NewEvaluator creates a new jq evaluator
+func NewEvaluator() *Evaluator {
+	return &Evaluator{}
+}
+
+// Evaluate applies jq expressions in cascade to the input YAML. Every YAML
+// document is an input; an expression producing several values feeds each
+// one to the next expression, like a shell pipe between jq invocations.
+func (e *Evaluator) Evaluate(input string, expressions []string) (string, error) {
+	if len(expressions) == 0 {
+		return input, nil
+	}
+
+	values, err := decodeDocuments(input)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse YAML: %w", err)
+	}
+
+	for _, expr := range expressions {
+		values, err = evaluateSingle(values, expr)
+		if err != nil {
+			return "", fmt.Errorf("failed to evaluate jq expression %q: %w", expr, err)
+		}
+	}
+
+	return encodeValues(values)
+}
+
+// evaluateSingle runs one jq program over every input and collects the results
+func evaluateSingle(inputs []any, expression string) ([]any, error) {
+	query, err := gojq.Parse(expression)
+	if err != nil {
+		return nil, err
+	}
+	code, err := gojq.Compile(query)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), evaluationTimeout)
+	defer cancel()
+
+	var results []any
+	for _, input := range inputs {
+		iter := code.RunWithContext(ctx, input)
+		for {
+			v, ok := iter.Next()
+			if !ok {
+				break
+			}
+			if err, isErr := v.(error); isErr {
+				var halt *gojq.HaltError
+				if errors.As(err, &halt) && halt.Value() == nil {
+					break
+				}
+				return nil, err
+			}
+			results = append(results, v)
+		}
+	}
+	return results, nil
+}
+
+// decodeDocuments reads each YAML document of the input as a JSON value
+func decodeDocuments(input string) ([]any, error) {
+	var values []any
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(strings.NewReader(input)))
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return values, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(string(doc)) == "" {
+			continue
+		}
+
+		data, err := yaml.YAMLToJSON(doc)
+		if err != nil {
+			return nil, err
+		}
+		var v any
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+}
+
+// encodeValues writes results back as YAML: strings unwrapped, one scalar per
+// line, structured values as separate documents.
+func encodeValues(values []any) (string, error) {
+	parts := make([]string, 0, len(values))
+	structured := false
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			parts = append(parts, s)
+			continue
+		}
+		out, err := yaml.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode result: %w", err)
+		}
+		switch v.(type) {
+		case map[string]any, []any:
+			structured = true
+		}
+		parts = append(parts, strings.TrimSpace(string(out)))
+	}
+
+	separator := "\n"
+	if structured {
+		separator = "\n---\n"
+	}
+	return strings.Join(parts, separator), nil
+}
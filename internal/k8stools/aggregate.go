@@ -0,0 +1,117 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// AggregateItem is the outcome of a single item of a fan-out operation
+// (one document of a multi-document apply, one context of a multi-context
+// query, one object of a bulk update, ...).
+type AggregateItem struct {
+	Target  string `json:"target"`
+	Success bool   `json:"success"`
+	Output  string `json:"output,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// AggregateResult collects the per-item outcomes of a fan-out tool so the
+// model can tell exactly which items failed and why, instead of getting a
+// single opaque error for the whole call.
+type AggregateResult struct {
+	Items []AggregateItem `json:"items"`
+}
+
+// NewAggregateResult creates an empty AggregateResult
+func NewAggregateResult() *AggregateResult {
+	return &AggregateResult{Items: []AggregateItem{}}
+}
+
+// AddSuccess records a successful item
+func (a *AggregateResult) AddSuccess(target, output string) {
+	a.Items = append(a.Items, AggregateItem{Target: target, Success: true, Output: output})
+}
+
+// AddError records a failed item. Partial output (e.g. what was done before
+// the failure) can be attached with 'output'.
+func (a *AggregateResult) AddError(target string, err error, output string) {
+	a.Items = append(a.Items, AggregateItem{Target: target, Success: false, Output: output, Error: err.Error()})
+}
+
+// Succeeded returns the number of successful items
+func (a *AggregateResult) Succeeded() int {
+	n := 0
+	for _, item := range a.Items {
+		if item.Success {
+			n++
+		}
+	}
+	return n
+}
+
+// Failed returns the number of failed items
+func (a *AggregateResult) Failed() int {
+	return len(a.Items) - a.Succeeded()
+}
+
+// Summary returns the one-line human summary, e.g. "3 succeeded, 1 failed"
+func (a *AggregateResult) Summary() string {
+	return fmt.Sprintf("%d succeeded, %d failed", a.Succeeded(), a.Failed())
+}
+
+// Render returns the human summary, one status line per item and the
+// structured JSON block. The JSON block carries the full per-item output.
+func (a *AggregateResult) Render() string {
+	var sb strings.Builder
+	sb.WriteString(a.Summary())
+	sb.WriteString("\n\n")
+
+	for _, item := range a.Items {
+		if item.Success {
+			fmt.Fprintf(&sb, "[OK]   %s\n", item.Target)
+		} else {
+			fmt.Fprintf(&sb, "[FAIL] %s: %s\n", item.Target, item.Error)
+		}
+	}
+
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		// Only strings and bools inside; cannot happen in practice.
+		return sb.String()
+	}
+	sb.WriteString("\nStructured result:\n")
+	sb.Write(data)
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// ToolResult converts the aggregate into an MCP result. The call is flagged
+// as an error only when no item succeeded; partial failures are reported as
+// a successful call whose body lists the failed items.
+func (a *AggregateResult) ToolResult() *mcp.CallToolResult {
+	result := successResult(a.Render())
+	if len(a.Items) > 0 && a.Succeeded() == 0 {
+		result.IsError = true
+	}
+	return result
+}
@@ -251,7 +251,7 @@ data:
 	requireContains(t, out, "would CREATE a new resource", "expected creation hint")
 }
 
-// diff_manifest must reject multi-document YAML for symmetry with apply_manifest.
+// diff_manifest compares a single object and must reject multi-document YAML.
 func TestE2E_DiffManifest_RejectsMultiDoc(t *testing.T) {
 	e := newE2EEnv(t)
 
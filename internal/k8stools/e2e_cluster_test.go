@@ -125,6 +125,7 @@ func TestE2E_ListAPIVersions(t *testing.T) {
 		t.Fatalf("go-error: %v", err)
 	}
 	out := expectOK(t, res, "list_api_versions")
-	requireContains(t, out, "apps", "expected apps API group")
-	requireContains(t, out, "networking.k8s.io", "expected networking.k8s.io API group")
+	requireContains(t, out, "group: apps", "expected apps API group")
+	requireContains(t, out, "group: networking.k8s.io", "expected networking.k8s.io API group")
+	requireContains(t, out, "preferred_version: v1", "expected preferred versions")
 }
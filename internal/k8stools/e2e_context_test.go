@@ -119,6 +119,7 @@ func TestE2E_ListContexts_ShowsAllAndCurrent(t *testing.T) {
 	out := expectOK(t, res, "list_contexts")
 	requireContains(t, out, "name: "+env.context, "expected primary context")
 	requireContains(t, out, "name: "+alias, "expected secondary context")
+	requireContains(t, out, "ready: true", "expected the clients reported as initialized")
 	// The primary should be marked as current=true. The alias should have current=false.
 	if !strings.Contains(out, "current: true") {
 		t.Fatalf("expected exactly one context marked current; got:\n%s", out)
@@ -7,7 +7,7 @@ Licensed under the Apache License, Version 2.0.
 
 // E2E tests covering the audit-driven hardening:
 //   - apply_manifest re-apply round-trip (B1, B2, B8)
-//   - apply_manifest multi-document rejection (B7)
+//   - apply_manifest multi-document bundles (B7)
 //   - patch_resource empty patch validation (B3)
 //   - delete_resources cross-namespace barrier and bulk cap (B11, B12)
 //   - get_rollout_status correctness for DaemonSet and StatefulSet (B5)
@@ -90,9 +90,9 @@ spec:
 	}
 }
 
-// --- B7: multi-document YAML must be rejected ---
+// --- B7: multi-document YAML is applied document by document ---
 
-func TestE2E_ApplyManifest_MultiDocRejected(t *testing.T) {
+func TestE2E_ApplyManifest_MultiDoc(t *testing.T) {
 	e := newE2EEnv(t)
 
 	manifest := `
@@ -119,8 +119,9 @@ data:
 	if err != nil {
 		t.Fatalf("go-error: %v", err)
 	}
-	text := expectErr(t, res, "multi-doc YAML must be rejected")
-	requireContains(t, text, "multi-document YAML is not supported", "expected multi-doc error")
+	text := expectOK(t, res, "multi-doc YAML must be applied")
+	requireContains(t, text, "2 succeeded, 0 failed", "expected both documents applied")
+	requireContains(t, text, "document 2: ConfigMap/kmcp-e2e-multidoc-2", "expected per-document report")
 }
 
 // --- B3: patch_resource empty patch must be a clean error, not a panic ---
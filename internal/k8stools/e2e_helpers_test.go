@@ -37,7 +37,6 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
-	"strings"
 	"testing"
 	"time"
 
@@ -45,13 +44,11 @@ import (
 	"kubernetes-mcp/internal/authorization"
 	"kubernetes-mcp/internal/kubernetes"
 
-	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
@@ -203,51 +200,6 @@ func deleteNamespace(cm *kubernetes.ClientManager, ctxName, ns string) {
 	_ = cli.Clientset.CoreV1().Namespaces().Delete(context.Background(), ns, metav1.DeleteOptions{})
 }
 
-// makeRequest builds a CallToolRequest with the provided arguments.
-func makeRequest(args map[string]any) mcp.CallToolRequest {
-	req := mcp.CallToolRequest{}
-	req.Params.Arguments = args
-	return req
-}
-
-// firstText returns the first text content of a tool result and the IsError flag.
-func firstText(res *mcp.CallToolResult) (string, bool) {
-	if res == nil {
-		return "<nil result>", true
-	}
-	for _, c := range res.Content {
-		if tc, ok := c.(mcp.TextContent); ok {
-			return tc.Text, res.IsError
-		}
-	}
-	return "", res.IsError
-}
-
-// expectOK fails the test if the tool returned an error result.
-func expectOK(t *testing.T, res *mcp.CallToolResult, msg string) string {
-	t.Helper()
-	text, isErr := firstText(res)
-	if isErr {
-		t.Fatalf("%s: tool returned error: %s", msg, text)
-	}
-	return text
-}
-
-// expectErr fails the test if the tool succeeded; returns the error text.
-func expectErr(t *testing.T, res *mcp.CallToolResult, msg string) string {
-	t.Helper()
-	text, isErr := firstText(res)
-	if !isErr {
-		t.Fatalf("%s: expected an error but tool succeeded; got: %s", msg, text)
-	}
-	return text
-}
-
-// gvrOf builds a GVR.
-func gvrOf(group, version, resource string) schema.GroupVersionResource {
-	return schema.GroupVersionResource{Group: group, Version: version, Resource: resource}
-}
-
 // resourceExists asserts a resource exists. Tries namespaced (in the test
 // namespace) first and falls back to cluster-scoped.
 func (e *e2eEnv) resourceExists(group, version, resource, name string) bool {
@@ -282,14 +234,6 @@ func waitForCondition(t *testing.T, timeout time.Duration, fn func() bool) {
 	t.Fatalf("timed out after %s waiting for condition", timeout)
 }
 
-// requireContains fails the test if `text` does not contain `needle`.
-func requireContains(t *testing.T, text, needle, msg string) {
-	t.Helper()
-	if !strings.Contains(text, needle) {
-		t.Fatalf("%s: expected text to contain %q\ngot:\n%s", msg, needle, text)
-	}
-}
-
 // applyManifest is a tiny helper to apply a manifest via the tool, returning the
 // reported text. Fails on tool error.
 func (e *e2eEnv) applyManifest(manifest string) string {
@@ -129,6 +129,77 @@ spec:
 	}
 	out := expectOK(t, res, "exec_command")
 	requireContains(t, out, "ping-pong", "expected exec output")
+	requireContains(t, out, "exit_code: 0", "expected an explicit zero exit code")
+
+	res, err = e.manager.handleExecCommand(context.Background(), makeRequest(map[string]any{
+		"context":   e.context,
+		"name":      name,
+		"namespace": e.namespace,
+		"command":   []any{"sh", "-c", "echo failing; exit 3"},
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	text := expectErr(t, res, "exec_command non-zero exit")
+	requireContains(t, text, "exit_code: 3", "expected the real exit code")
+	requireContains(t, text, "failing", "expected the captured output")
+}
+
+func TestE2E_CopyToAndFromPod(t *testing.T) {
+	e := newE2EEnv(t)
+
+	name := "kmcp-e2e-copy"
+	e.applyManifest(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: ` + name + `
+  namespace: ` + e.namespace + `
+spec:
+  restartPolicy: Never
+  containers:
+  - name: main
+    image: busybox:1.36
+    command: ["sh", "-c", "sleep 3600"]
+`)
+	e.waitForPodReady(name, 90*time.Second)
+
+	// Binary content must survive the round trip byte for byte
+	res, err := e.manager.handleCopyToPod(context.Background(), makeRequest(map[string]any{
+		"context":   e.context,
+		"name":      name,
+		"namespace": e.namespace,
+		"path":      "/tmp/blob.bin",
+		"content":   "AP8QIA==",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	requireContains(t, expectOK(t, res, "copy_to_pod"), "Copied 4 bytes", "expected the copied size")
+
+	res, err = e.manager.handleCopyFromPod(context.Background(), makeRequest(map[string]any{
+		"context":   e.context,
+		"name":      name,
+		"namespace": e.namespace,
+		"path":      "/tmp/blob.bin",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "copy_from_pod")
+	requireContains(t, out, "encoding: base64", "expected binary content to be base64-encoded")
+	requireContains(t, out, "content: AP8QIA==", "expected the original bytes back")
+
+	res, err = e.manager.handleCopyFromPod(context.Background(), makeRequest(map[string]any{
+		"context":   e.context,
+		"name":      name,
+		"namespace": e.namespace,
+		"path":      "/does/not/exist",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	requireContains(t, expectErr(t, res, "copy_from_pod missing path"), "tar exited with code", "expected tar's failure")
 }
 
 func TestE2E_ExecCommand_RequiresCommand(t *testing.T) {
@@ -0,0 +1,61 @@
+//go:build e2e
+
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0.
+*/
+
+// E2E tests for get_pdb_status.
+package k8stools
+
+import (
+	"context"
+	"testing"
+)
+
+func TestE2E_GetPDBStatus_BlockingPDB(t *testing.T) {
+	e := newE2EEnv(t)
+
+	// No Pod matches the selector, so the PDB can never allow a disruption.
+	e.applyManifest(`
+apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata:
+  name: kmcp-e2e-pdb
+  namespace: ` + e.namespace + `
+spec:
+  minAvailable: 1
+  selector:
+    matchLabels:
+      app: kmcp-e2e-pdb
+`)
+
+	res, err := e.manager.handleGetPDBStatus(context.Background(), makeRequest(map[string]any{
+		"context":   e.context,
+		"namespace": e.namespace,
+		"name":      "kmcp-e2e-pdb",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "get_pdb_status")
+	requireContains(t, out, "name: kmcp-e2e-pdb", "expected PDB name")
+	requireContains(t, out, "selector: app=kmcp-e2e-pdb", "expected formatted selector")
+	requireContains(t, out, `min_available: "1"`, "expected min_available")
+	requireContains(t, out, "disruptions_allowed: 0", "expected no disruptions allowed")
+	requireContains(t, out, "blocking: true", "expected PDB to be reported as blocking")
+}
+
+func TestE2E_GetPDBStatus_NameWithoutNamespace(t *testing.T) {
+	e := newE2EEnv(t)
+
+	res, err := e.manager.handleGetPDBStatus(context.Background(), makeRequest(map[string]any{
+		"context": e.context,
+		"name":    "anything",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	text := expectErr(t, res, "expected error when name is set without namespace")
+	requireContains(t, text, "'namespace' is required", "expected clear error")
+}
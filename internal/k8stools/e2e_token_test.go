@@ -0,0 +1,81 @@
+//go:build e2e
+
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0.
+*/
+
+// E2E tests for create_sa_token (TokenRequest API).
+package k8stools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func (e *e2eEnv) createServiceAccount(name string) {
+	e.applyManifest(`
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: ` + name + `
+  namespace: ` + e.namespace + `
+`)
+}
+
+func TestE2E_CreateSAToken_MaskedByDefault(t *testing.T) {
+	e := newE2EEnv(t)
+	e.createServiceAccount("kmcp-e2e-token")
+
+	res, err := e.manager.handleCreateSAToken(context.Background(), makeRequest(map[string]any{
+		"context":   e.context,
+		"namespace": e.namespace,
+		"name":      "kmcp-e2e-token",
+		"audiences": []any{"kmcp-e2e"},
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "create_sa_token")
+	requireContains(t, out, "Audiences:  kmcp-e2e", "expected requested audience")
+	requireContains(t, out, "Token:      ***", "expected token to be masked")
+}
+
+func TestE2E_CreateSAToken_Reveal(t *testing.T) {
+	e := newE2EEnv(t)
+	e.createServiceAccount("kmcp-e2e-token")
+
+	res, err := e.manager.handleCreateSAToken(context.Background(), makeRequest(map[string]any{
+		"context":            e.context,
+		"namespace":          e.namespace,
+		"name":               "kmcp-e2e-token",
+		"expiration_seconds": float64(600),
+		"reveal":             true,
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "create_sa_token reveal")
+	if strings.Contains(out, "***") {
+		t.Fatalf("expected token in clear text, got:\n%s", out)
+	}
+	// Bound SA tokens are JWTs
+	requireContains(t, out, "Token:      ey", "expected a JWT")
+}
+
+func TestE2E_CreateSAToken_ExpirationOutOfRange(t *testing.T) {
+	e := newE2EEnv(t)
+
+	res, err := e.manager.handleCreateSAToken(context.Background(), makeRequest(map[string]any{
+		"context":            e.context,
+		"namespace":          e.namespace,
+		"name":               "default",
+		"expiration_seconds": float64(60),
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	text := expectErr(t, res, "expected error on too short expiration")
+	requireContains(t, text, "expiration_seconds must be between", "expected range error")
+}
@@ -0,0 +1,268 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Unit-test harness: runs the tool handlers against client-go fakes instead
+// of a real cluster. Unlike the e2e tests it needs no build tag and runs as
+// part of 'go test ./...'.
+package k8stools
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"kubernetes-mcp/api"
+	"kubernetes-mcp/internal/kubernetes"
+
+	"github.com/mark3labs/mcp-go/server"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	metadatafake "k8s.io/client-go/metadata/fake"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const fakeContext = "fake"
+
+// fakeRESTMapper makes a static DefaultRESTMapper satisfy ResettableRESTMapper
+type fakeRESTMapper struct {
+	*meta.DefaultRESTMapper
+}
+
+func (fakeRESTMapper) Reset() {}
+
+// newFakeRESTMapper knows the handful of built-in kinds the unit tests use
+func newFakeRESTMapper() meta.ResettableRESTMapper {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	namespaced := []schema.GroupVersionKind{
+		{Version: "v1", Kind: "Pod"},
+		{Version: "v1", Kind: "ConfigMap"},
+		{Version: "v1", Kind: "Secret"},
+		{Version: "v1", Kind: "Service"},
+		{Version: "v1", Kind: "ServiceAccount"},
+		{Version: "v1", Kind: "Event"},
+		{Group: "apps", Version: "v1", Kind: "Deployment"},
+		{Group: "apps", Version: "v1", Kind: "StatefulSet"},
+		{Group: "apps", Version: "v1", Kind: "DaemonSet"},
+		{Group: "apps", Version: "v1", Kind: "ReplicaSet"},
+		{Group: "batch", Version: "v1", Kind: "Job"},
+		{Group: "batch", Version: "v1", Kind: "CronJob"},
+	}
+	for _, gvk := range namespaced {
+		mapper.Add(gvk, meta.RESTScopeNamespace)
+	}
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Node"}, meta.RESTScopeRoot)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+	mapper.Add(schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}, meta.RESTScopeRoot)
+	return fakeRESTMapper{mapper}
+}
+
+// fakeClientProvider is a ClientProvider serving a single fake context
+type fakeClientProvider struct {
+	client           *kubernetes.Client
+	deniedNamespaces []string
+}
+
+func (p *fakeClientProvider) GetClient(context string) (*kubernetes.Client, error) {
+	if context != fakeContext {
+		return nil, fmt.Errorf("context %s not found", context)
+	}
+	return p.client, nil
+}
+
+func (p *fakeClientProvider) GetCurrentContext(session string) string { return fakeContext }
+
+func (p *fakeClientProvider) SetCurrentContext(session, context string) error {
+	if context != fakeContext {
+		return fmt.Errorf("context %s not found", context)
+	}
+	return nil
+}
+
+func (p *fakeClientProvider) ListContexts() []string { return []string{fakeContext} }
+
+func (p *fakeClientProvider) GetContextConfig(context string) (api.KubernetesContextConfig, bool) {
+	if context != "" && context != fakeContext {
+		return api.KubernetesContextConfig{}, false
+	}
+	return api.KubernetesContextConfig{Name: fakeContext, DeniedNamespaces: p.deniedNamespaces}, true
+}
+
+func (p *fakeClientProvider) IsNamespaceAllowed(context, namespace string) bool {
+	return context == fakeContext && !slices.Contains(p.deniedNamespaces, namespace)
+}
+
+// fakeEnv groups everything a fake-client unit test needs
+type fakeEnv struct {
+	manager   *Manager
+	provider  *fakeClientProvider
+	dynamic   *dynamicfake.FakeDynamicClient
+	clientset *k8sfake.Clientset
+}
+
+// newFakeEnv builds a Manager whose only context is backed by fake clients
+// seeded with 'objects'. Typed objects are fine: the dynamic fake converts
+// them to unstructured. No authorization is configured.
+func newFakeEnv(t *testing.T, objects ...runtime.Object) *fakeEnv {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("build scheme: %v", err)
+	}
+
+	// CRDs are not in the client-go scheme; list_webhooks lists them
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme,
+		map[schema.GroupVersionResource]string{crdGVR: "CustomResourceDefinitionList"}, objects...)
+	clientset := k8sfake.NewClientset(objects...)
+	metadataScheme := metadatafake.NewTestScheme()
+	if err := metav1.AddMetaToScheme(metadataScheme); err != nil {
+		t.Fatalf("build metadata scheme: %v", err)
+	}
+	metadataClient := metadatafake.NewSimpleMetadataClient(metadataScheme, partialObjects(t, scheme, objects)...)
+
+	provider := &fakeClientProvider{
+		client: &kubernetes.Client{
+			Config:         &rest.Config{Host: "https://fake.cluster.local"},
+			Clientset:      clientset,
+			DynamicClient:  dynamicClient,
+			MetadataClient: metadataClient,
+			RESTMapper:     newFakeRESTMapper(),
+		},
+	}
+
+	config := &api.Configuration{Kubernetes: api.KubernetesConfig{DefaultContext: fakeContext}}
+	manager := NewManager(ManagerDependencies{
+		Logger:        slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Config:        config,
+		ClientManager: provider,
+		McpServer:     server.NewMCPServer("kmcp-test", "0.0.0", server.WithToolCapabilities(true)),
+	})
+
+	return &fakeEnv{
+		manager:   manager,
+		provider:  provider,
+		dynamic:   dynamicClient,
+		clientset: clientset,
+	}
+}
+
+// partialObjects converts the seed objects to the PartialObjectMetadata the
+// metadata fake serves, keeping each object's own apiVersion / kind.
+func partialObjects(t *testing.T, scheme *runtime.Scheme, objects []runtime.Object) []runtime.Object {
+	t.Helper()
+	out := make([]runtime.Object, 0, len(objects))
+	for _, obj := range objects {
+		raw, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			t.Fatalf("convert seed object: %v", err)
+		}
+		gvk := obj.GetObjectKind().GroupVersionKind()
+		if gvk.Empty() {
+			kinds, _, err := scheme.ObjectKinds(obj)
+			if err != nil {
+				t.Fatalf("seed object of unknown kind: %v", err)
+			}
+			gvk = kinds[0]
+		}
+		partial := &metav1.PartialObjectMetadata{}
+		if metadata, ok := raw["metadata"].(map[string]any); ok {
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(metadata, &partial.ObjectMeta); err != nil {
+				t.Fatalf("convert seed metadata: %v", err)
+			}
+		}
+		partial.SetGroupVersionKind(gvk)
+		out = append(out, partial)
+	}
+	return out
+}
+
+// serveShortNames publishes, through the fake discovery, the short names of
+// a few built-in resources and of a 'widgets' CRD.
+func (e *fakeEnv) serveShortNames() {
+	e.clientset.Resources = []*metav1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{
+			{Name: "pods", Kind: "Pod", Namespaced: true, ShortNames: []string{"po"}},
+			{Name: "services", Kind: "Service", Namespaced: true, ShortNames: []string{"svc"}},
+			{Name: "configmaps", Kind: "ConfigMap", Namespaced: true, ShortNames: []string{"cm"}},
+		}},
+		{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{
+			{Name: "deployments", Kind: "Deployment", Namespaced: true, ShortNames: []string{"deploy"}},
+			{Name: "statefulsets", Kind: "StatefulSet", Namespaced: true, ShortNames: []string{"sts"}},
+		}},
+		{GroupVersion: "example.com/v1", APIResources: []metav1.APIResource{
+			{Name: "widgets", Kind: "Widget", Namespaced: true, ShortNames: []string{"wd"}},
+		}},
+	}
+}
+
+// serveDryRun makes the dynamic client answer dry-run writes the way the API
+// server does: the resulting object is returned and nothing reaches the
+// store. The fake tracker otherwise ignores DryRun and persists the write.
+// Dry-run patches are applied as strategic merge patches, which covers the
+// merge patches the tests send.
+func (e *fakeEnv) serveDryRun() {
+	tracker := e.dynamic.Tracker()
+	isDryRun := func(dryRun []string) bool { return slices.Contains(dryRun, metav1.DryRunAll) }
+
+	e.dynamic.PrependReactor("*", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		switch a := action.(type) {
+		case k8stesting.CreateActionImpl:
+			return isDryRun(a.CreateOptions.DryRun), a.GetObject(), nil
+		case k8stesting.UpdateActionImpl:
+			return isDryRun(a.UpdateOptions.DryRun), a.GetObject(), nil
+		case k8stesting.DeleteActionImpl:
+			if !isDryRun(a.DeleteOptions.DryRun) {
+				return false, nil, nil
+			}
+			_, err := tracker.Get(a.GetResource(), a.GetNamespace(), a.GetName())
+			return true, nil, err
+		case k8stesting.PatchActionImpl:
+			if !isDryRun(a.PatchOptions.DryRun) {
+				return false, nil, nil
+			}
+			live, err := tracker.Get(a.GetResource(), a.GetNamespace(), a.GetName())
+			if err != nil {
+				return true, nil, err
+			}
+			typed, err := clientgoscheme.Scheme.New(live.GetObjectKind().GroupVersionKind())
+			if err != nil {
+				return true, nil, err
+			}
+			original, err := json.Marshal(live)
+			if err != nil {
+				return true, nil, err
+			}
+			patched, err := strategicpatch.StrategicMergePatch(original, a.GetPatch(), typed)
+			if err != nil {
+				return true, nil, err
+			}
+			obj := &unstructured.Unstructured{}
+			return true, obj, obj.UnmarshalJSON(patched)
+		}
+		return false, nil, nil
+	})
+}
@@ -17,15 +17,18 @@ limitations under the License.
 package k8stools
 
 import (
+	"context"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"kubernetes-mcp/internal/authorization"
 	"kubernetes-mcp/internal/kubernetes"
 	"kubernetes-mcp/internal/middlewares"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -56,53 +59,220 @@ func (m *Manager) extractAuthPayload(request mcp.CallToolRequest) map[string]any
 
 // checkAuthorization checks if the request is authorized
 func (m *Manager) checkAuthorization(request mcp.CallToolRequest, tool, k8sContext, namespace string, resource authorization.ResourceInfo) error {
-	if m.authz == nil {
+	authz := m.currentAuthz()
+	if authz == nil {
 		return nil
 	}
 
-	payload := m.extractAuthPayload(request)
+	req := m.authzRequest(request, tool, k8sContext, namespace, resource)
+	decision, err := authz.EvaluateDecision(req)
+	if err != nil {
+		return fmt.Errorf("authorization error: %w", err)
+	}
+	m.auditDecision(req, decision, "")
+	if m.metrics != nil {
+		m.metrics.ObserveAuthorization(tool, m.metricsContext(k8sContext), decision.Allowed)
+	}
+
+	if !decision.Allowed {
+		return fmt.Errorf("access denied: not authorized to use tool %s on context %s", tool, k8sContext)
+	}
+
+	return nil
+}
+
+// checkMetadataKeys checks the label and annotation keys a write sets or
+// removes against the 'label_prefixes' / 'annotation_prefixes' of the rules
+// authorizing it. One disallowed key rejects the whole operation. An empty
+// key stands for the whole map, e.g. a patch removing every label.
+func (m *Manager) checkMetadataKeys(request mcp.CallToolRequest, tool, k8sContext, namespace string, resource authorization.ResourceInfo,
+	labels, annotations []string) error {
+	authz := m.currentAuthz()
+	if authz == nil || len(labels)+len(annotations) == 0 {
+		return nil
+	}
+
+	req := m.authzRequest(request, tool, k8sContext, namespace, resource)
+	for _, key := range labels {
+		if !authz.IsLabelPrefixAllowed(req, key) {
+			m.observeMetadataDenial(req, "label", key)
+			return fmt.Errorf("access denied: tool %s may not change %s on context %s", tool, describeMetadataKey("label", key), k8sContext)
+		}
+	}
+	for _, key := range annotations {
+		if !authz.IsAnnotationPrefixAllowed(req, key) {
+			m.observeMetadataDenial(req, "annotation", key)
+			return fmt.Errorf("access denied: tool %s may not change %s on context %s", tool, describeMetadataKey("annotation", key), k8sContext)
+		}
+	}
+	return nil
+}
+
+// observeMetadataDenial records a key rejected by checkMetadataKeys as an
+// authorization denial. Allowed keys are not audited one by one: the
+// decision on the whole request already was.
+func (m *Manager) observeMetadataDenial(req authorization.AuthzRequest, kind, key string) {
+	if m.metrics != nil {
+		m.metrics.ObserveAuthorization(req.Tool, m.metricsContext(req.Context), false)
+	}
+	m.auditDecision(req, authorization.Decision{}, describeMetadataKey(kind, key))
+}
+
+// auditDecision writes one authorization decision to the audit log, when
+// enabled. 'metadataKey' names the label or annotation key a denial is
+// about, if any.
+func (m *Manager) auditDecision(req authorization.AuthzRequest, decision authorization.Decision, metadataKey string) {
+	auditLogger := m.currentAuditLogger()
+	if auditLogger == nil {
+		return
+	}
+	identity, _ := req.Payload[m.identityClaim()].(string)
+	resource := authorization.GetResourceForTool(req.Tool, req.Resource)
+	result := "deny"
+	if decision.Allowed {
+		result = "allow"
+	}
+
+	attrs := []any{
+		"identity", identity,
+		"tool", req.Tool,
+		"context", req.Context,
+		"namespace", req.Namespace,
+		"group", resource.Group,
+		"version", resource.Version,
+		"resource", resource.Resource,
+		"name", resource.Name,
+		"matched_policies", decision.MatchedPolicies,
+		"deciding_policy", decision.DecidingPolicy,
+		"decision", result,
+	}
+	if metadataKey != "" {
+		attrs = append(attrs, "metadata_key", metadataKey)
+	}
+	auditLogger.Info("authorization decision", attrs...)
+}
 
-	allowed, err := m.authz.Evaluate(authorization.AuthzRequest{
-		Payload:   payload,
+// describeMetadataKey names a key checked by checkMetadataKeys in errors
+func describeMetadataKey(kind, key string) string {
+	if key == "" {
+		return "every " + kind
+	}
+	return fmt.Sprintf("%s %q", kind, key)
+}
+
+// authzRequest builds the request evaluated by the authorization policies.
+// It caches the matching policies, so checking several keys of one write
+// evaluates the CEL programs once.
+func (m *Manager) authzRequest(request mcp.CallToolRequest, tool, k8sContext, namespace string, resource authorization.ResourceInfo) authorization.AuthzRequest {
+	resource.Namespaced = m.resolveNamespaced(k8sContext, resource)
+	return authorization.AuthzRequest{
+		Payload:   m.extractAuthPayload(request),
 		Tool:      tool,
 		Context:   k8sContext,
 		Namespace: namespace,
 		Resource:  resource,
-	})
+	}.WithMatchCache()
+}
+
+// resolveNamespaced reports the scope of the resource being authorized, so
+// policies can match on 'resource.namespaced'. Virtual resources and types
+// discovery cannot resolve count as not namespaced: a policy denying
+// cluster-scoped writes then also denies what it cannot classify.
+func (m *Manager) resolveNamespaced(k8sContext string, resource authorization.ResourceInfo) bool {
+	if resource.Resource == "" || resource.Group == authorization.VirtualResourceGroup {
+		return false
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
 	if err != nil {
-		return fmt.Errorf("authorization error: %w", err)
+		return false
 	}
 
-	if !allowed {
-		return fmt.Errorf("access denied: not authorized to use tool %s on context %s", tool, k8sContext)
+	namespaced, err := m.isNamespacedGVR(client, schema.GroupVersionResource{
+		Group:    resource.Group,
+		Version:  resource.Version,
+		Resource: resource.Resource,
+	})
+	if err != nil {
+		m.logger.Debug("could not resolve resource scope for authorization",
+			"context", k8sContext, "resource", resource.Resource, "error", err)
+		return false
 	}
+	return namespaced
+}
 
-	return nil
+// getContextParam extracts the context parameter or returns the current
+// context of the calling MCP session
+func (m *Manager) getContextParam(ctx context.Context, args map[string]any) string {
+	if k8sContext, ok := args["context"].(string); ok && k8sContext != "" {
+		return k8sContext
+	}
+	return m.clientManager.GetCurrentContext(sessionID(ctx))
 }
 
-// getContextParam extracts the context parameter or returns the current context
-func (m *Manager) getContextParam(args map[string]any) string {
-	if ctx, ok := args["context"].(string); ok && ctx != "" {
-		return ctx
+// sessionID returns the ID of the MCP session a tool call belongs to, or ""
+// outside of a session
+func sessionID(ctx context.Context) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return session.SessionID()
 	}
-	return m.clientManager.GetCurrentContext()
+	return ""
 }
 
-// applyYQExpressions applies yq expressions to the YAML output
-func (m *Manager) applyYQExpressions(yamlData string, args map[string]any) (string, error) {
-	exprs, ok := args["yq_expressions"].([]any)
-	if !ok || len(exprs) == 0 {
+// applyOutputFilters applies the 'yq_expressions' and then the
+// 'jq_expressions' of a call to the YAML output; jq reads the YAML left by
+// yq. This is the shared output step of the read tools, so it also runs the
+// configured redaction pass: once before filtering, so a projection like
+// '.data' cannot side-step a path-based rule, and once after, to catch
+// sensitive keys surfaced by reshaping.
+func (m *Manager) applyOutputFilters(yamlData string, args map[string]any) (string, error) {
+	yamlData = m.redactYAML(yamlData)
+
+	yqExpressions := stringArgs(args, "yq_expressions")
+	jqExpressions := stringArgs(args, "jq_expressions")
+	if len(yqExpressions) == 0 && len(jqExpressions) == 0 {
 		return yamlData, nil
 	}
 
-	var expressions []string
-	for _, e := range exprs {
-		if s, ok := e.(string); ok {
-			expressions = append(expressions, s)
+	result := yamlData
+	if len(yqExpressions) > 0 {
+		var err error
+		if result, err = m.yq.Evaluate(result, yqExpressions); err != nil {
+			return "", err
 		}
 	}
+	if len(jqExpressions) > 0 {
+		var err error
+		if result, err = m.jq.Evaluate(result, jqExpressions); err != nil {
+			return "", err
+		}
+	}
+	return m.redactYAML(result), nil
+}
 
-	return m.yq.Evaluate(yamlData, expressions)
+// stringArgs returns the string elements of an array argument
+func stringArgs(args map[string]any, key string) []string {
+	raw, _ := args[key].([]any)
+	var out []string
+	for _, v := range raw {
+		if s, ok := v.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// jqExpressionsParam is the 'jq_expressions' parameter every tool with
+// 'yq_expressions' takes
+func jqExpressionsParam() mcp.ToolOption {
+	return mcp.WithArray("jq_expressions", mcp.Description("Optional jq expressions applied in order to the same output read as JSON, after 'yq_expressions'; results come back as YAML. Use either, or both. Examples: '.metadata.name', '[.items[] | select(.status.phase != \"Running\") | .metadata.name]'."))
+}
+
+// redactYAML masks sensitive values in YAML output according to
+// 'kubernetes.tools.redaction'. Tools that return an object without going
+// through applyOutputFilters (apply, patch, scale, ...) must call it directly.
+func (m *Manager) redactYAML(yamlData string) string {
+	return m.currentRedactor().RedactYAML(yamlData)
 }
 
 // gvrFromArgs builds a GroupVersionResource directly from tool arguments.
@@ -134,6 +304,83 @@ func validateGVR(gvr schema.GroupVersionResource) error {
 	return nil
 }
 
+// expandShortName replaces a short name in 'resource' ('po', 'deploy',
+// 'svc', or a CRD's own) with the resource it stands for, taking the group
+// from discovery when the call left it empty. Anything else, including a
+// short name of another group, is returned unchanged for validateGVR and
+// the API server to judge.
+func (m *Manager) expandShortName(k8sContext string, gvr schema.GroupVersionResource) schema.GroupVersionResource {
+	if gvr.Resource == "" || strings.Contains(gvr.Resource, "/") {
+		return gvr
+	}
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return gvr
+	}
+	resource, ok, err := client.ResourceForShortName(gvr.Resource)
+	if err != nil || !ok {
+		return gvr
+	}
+	if gvr.Group != "" && gvr.Group != resource.Group {
+		return gvr
+	}
+	gvr.Group = resource.Group
+	gvr.Resource = resource.Resource
+	return gvr
+}
+
+// resolveGVRVersion fills in the version of a GVR when it is empty or not
+// served for its group: the configured preferred_versions entry wins, then
+// the preferred version reported by discovery. A served version is kept, and
+// so is any version when discovery cannot tell, so the API server reports
+// the error as usual.
+func (m *Manager) resolveGVRVersion(k8sContext string, gvr schema.GroupVersionResource) (schema.GroupVersionResource, error) {
+	if gvr.Resource == "" {
+		return gvr, nil // validateGVR reports it
+	}
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return gvr, err
+	}
+	if client.RESTMapper == nil {
+		return gvr, nil
+	}
+
+	if gvr.Version != "" {
+		if _, err := client.RESTMapper.KindFor(gvr); err == nil || !meta.IsNoMatchError(err) {
+			return gvr, nil
+		}
+	}
+
+	if version := m.preferredVersion(gvr.Group); version != "" {
+		gvr.Version = version
+		return gvr, nil
+	}
+
+	preferred, err := client.RESTMapper.ResourceFor(schema.GroupVersionResource{Group: gvr.Group, Resource: gvr.Resource})
+	if err != nil {
+		if gvr.Version != "" {
+			return gvr, nil
+		}
+		return gvr, fmt.Errorf("version is empty and no served version of %q in group %q was found via discovery: %w", gvr.Resource, gvr.Group, err)
+	}
+	gvr.Version = preferred.Version
+	return gvr, nil
+}
+
+// preferredVersion returns the configured version override of an API group.
+// The core group may be written as "" or "core".
+func (m *Manager) preferredVersion(group string) string {
+	versions := m.currentConfig().Kubernetes.Tools.PreferredVersions
+	if version, ok := versions[group]; ok {
+		return version
+	}
+	if group == "" {
+		return versions["core"]
+	}
+	return ""
+}
+
 // resolveGVRForGVK resolves a GroupVersionKind to its real GroupVersionResource
 // and namespaced flag using the cluster's discovery API via the RESTMapper.
 // This is used by tools that receive a manifest (apply_manifest, diff_manifest)
@@ -157,6 +404,17 @@ func (m *Manager) resolveKindForGVR(client *kubernetes.Client, gvr schema.GroupV
 	return gvk.Kind, nil
 }
 
+// isNamespacedGVR reports whether a GroupVersionResource is namespaced,
+// according to the RESTMapper.
+func (m *Manager) isNamespacedGVR(client *kubernetes.Client, gvr schema.GroupVersionResource) (bool, error) {
+	gvk, err := client.RESTMapper.KindFor(gvr)
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve kind for %s via discovery: %w", gvr.String(), err)
+	}
+	_, namespaced, err := m.resolveGVRForGVK(client, gvk)
+	return namespaced, err
+}
+
 // objectToYAML converts an unstructured object to YAML
 func objectToYAML(obj any) (string, error) {
 	data, err := yaml.Marshal(obj)
@@ -179,6 +437,18 @@ func errorResult(err error) *mcp.CallToolResult {
 	}
 }
 
+// resultText returns the text content of a result, without the "Error: "
+// prefix errorResult adds.
+func resultText(result *mcp.CallToolResult) string {
+	var parts []string
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			parts = append(parts, strings.TrimPrefix(text.Text, "Error: "))
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
 // successResult creates a success result for MCP
 func successResult(text string) *mcp.CallToolResult {
 	return &mcp.CallToolResult{
@@ -235,3 +505,16 @@ func getDeleteOptions(args map[string]any) (metav1.DeleteOptions, error) {
 
 	return opts, nil
 }
+
+// dryRunOption returns the DryRun value of a mutating request. With "All"
+// the API server runs defaulting, validation and admission, returns the
+// object it would have stored, and persists nothing.
+func dryRunOption(dryRun bool) []string {
+	if dryRun {
+		return []string{metav1.DryRunAll}
+	}
+	return nil
+}
+
+// dryRunNote is the line every dry-run result starts with
+const dryRunNote = "Dry run: nothing was changed."
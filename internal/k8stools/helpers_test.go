@@ -0,0 +1,79 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Result helpers shared by the fake-client unit tests and the e2e tests.
+package k8stools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// makeRequest builds a CallToolRequest with the provided arguments.
+func makeRequest(args map[string]any) mcp.CallToolRequest {
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = args
+	return req
+}
+
+// firstText returns the first text content of a tool result and the IsError flag.
+func firstText(res *mcp.CallToolResult) (string, bool) {
+	if res == nil {
+		return "<nil result>", true
+	}
+	for _, c := range res.Content {
+		if tc, ok := c.(mcp.TextContent); ok {
+			return tc.Text, res.IsError
+		}
+	}
+	return "", res.IsError
+}
+
+// expectOK fails the test if the tool returned an error result.
+func expectOK(t *testing.T, res *mcp.CallToolResult, msg string) string {
+	t.Helper()
+	text, isErr := firstText(res)
+	if isErr {
+		t.Fatalf("%s: tool returned error: %s", msg, text)
+	}
+	return text
+}
+
+// expectErr fails the test if the tool succeeded; returns the error text.
+func expectErr(t *testing.T, res *mcp.CallToolResult, msg string) string {
+	t.Helper()
+	text, isErr := firstText(res)
+	if !isErr {
+		t.Fatalf("%s: expected an error but tool succeeded; got: %s", msg, text)
+	}
+	return text
+}
+
+// requireContains fails the test if `text` does not contain `needle`.
+func requireContains(t *testing.T, text, needle, msg string) {
+	t.Helper()
+	if !strings.Contains(text, needle) {
+		t.Fatalf("%s: expected text to contain %q\ngot:\n%s", msg, needle, text)
+	}
+}
+
+// gvrOf builds a GVR.
+func gvrOf(group, version, resource string) schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: group, Version: version, Resource: resource}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"kubernetes-mcp/api"
+	"kubernetes-mcp/internal/kubernetes"
+)
+
+// BuildInstructions returns the instructions sent to clients in the MCP
+// initialize handshake: the operator-provided 'server.instructions' followed
+// by the contexts this server can reach and their namespace restrictions, so
+// the model knows the environment from the first message.
+// 'clientManager' may be nil when no Kubernetes context is configured.
+func BuildInstructions(config *api.Configuration, clientManager *kubernetes.ClientManager) string {
+	var sb strings.Builder
+
+	if custom := strings.TrimSpace(config.Server.Instructions); custom != "" {
+		sb.WriteString(custom)
+		sb.WriteString("\n\n")
+	}
+
+	if clientManager == nil {
+		sb.WriteString("No Kubernetes contexts are configured; Kubernetes tools are not available.\n")
+		return sb.String()
+	}
+
+	contexts := clientManager.ListContexts()
+	sort.Strings(contexts)
+	current := clientManager.GetCurrentContext("")
+
+	sb.WriteString("Kubernetes contexts available through this server (pass one in the 'context' parameter; empty uses the current one):\n")
+	for _, name := range contexts {
+		fmt.Fprintf(&sb, "- %s", name)
+		if name == current {
+			sb.WriteString(" (current)")
+		}
+
+		ctxConfig, _ := clientManager.GetContextConfig(name)
+		if ctxConfig.Description != "" {
+			fmt.Fprintf(&sb, ": %s", ctxConfig.Description)
+		}
+		if len(ctxConfig.AllowedNamespaces) > 0 {
+			fmt.Fprintf(&sb, " [only namespaces: %s]", strings.Join(ctxConfig.AllowedNamespaces, ", "))
+		}
+		if len(ctxConfig.DeniedNamespaces) > 0 {
+			fmt.Fprintf(&sb, " [denied namespaces: %s]", strings.Join(ctxConfig.DeniedNamespaces, ", "))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(config.Authorization.Policies) > 0 {
+		sb.WriteString("\nTool calls are subject to per-caller authorization policies; an 'access denied' error means the policy does not allow that tool, context or resource, not that the cluster rejected it.\n")
+	}
+
+	return sb.String()
+}
@@ -17,35 +17,74 @@ limitations under the License.
 package k8stools
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"kubernetes-mcp/api"
 	"kubernetes-mcp/internal/authorization"
+	"kubernetes-mcp/internal/jqutil"
 	"kubernetes-mcp/internal/kubernetes"
+	"kubernetes-mcp/internal/middlewares"
+	"kubernetes-mcp/internal/redaction"
 	"kubernetes-mcp/internal/yqutil"
 
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
+// ClientProvider is the subset of kubernetes.ClientManager used by the tools.
+// It exists so handlers can be exercised against fake clients in tests.
+type ClientProvider interface {
+	GetClient(context string) (*kubernetes.Client, error)
+	GetCurrentContext(session string) string
+	SetCurrentContext(session, context string) error
+	ListContexts() []string
+	GetContextConfig(context string) (api.KubernetesContextConfig, bool)
+	IsNamespaceAllowed(context, namespace string) bool
+}
+
 // Manager manages all Kubernetes MCP tools
 type Manager struct {
 	logger        *slog.Logger
-	config        *api.Configuration
-	clientManager *kubernetes.ClientManager
-	authz         *authorization.Evaluator
+	clientManager ClientProvider
 	yq            *yqutil.Evaluator
+	jq            *jqutil.Evaluator
 	mcpServer     *server.MCPServer
 	toolPrefix    string
+	metrics       *middlewares.MetricsMiddleware
+
+	// reloadMu guards what Reload swaps. Handlers read them through
+	// currentConfig, currentAuthz, currentRedactor and currentAuditLogger.
+	reloadMu    sync.RWMutex
+	config      *api.Configuration
+	authz       *authorization.Evaluator
+	redactor    *redaction.Redactor
+	auditLogger *slog.Logger
+
+	// forwards are the port forwards opened by port_forward, by id
+	forwardsMu sync.Mutex
+	forwards   map[string]*portForward
 }
 
 // ManagerDependencies holds dependencies for the Manager
 type ManagerDependencies struct {
 	Logger        *slog.Logger
 	Config        *api.Configuration
-	ClientManager *kubernetes.ClientManager
+	ClientManager ClientProvider
 	Authz         *authorization.Evaluator
 	McpServer     *server.MCPServer
 	ToolPrefix    string
+
+	// Metrics records tool calls and authorization decisions; nil disables them
+	Metrics *middlewares.MetricsMiddleware
 }
 
 // NewManager creates a new k8s tools manager
@@ -56,63 +95,336 @@ func NewManager(deps ManagerDependencies) *Manager {
 		clientManager: deps.ClientManager,
 		authz:         deps.Authz,
 		yq:            yqutil.NewEvaluator(),
+		jq:            jqutil.NewEvaluator(),
+		redactor:      redaction.NewRedactor(deps.Config.Kubernetes.Tools.Redaction),
 		mcpServer:     deps.McpServer,
 		toolPrefix:    deps.ToolPrefix,
+		metrics:       deps.Metrics,
+		auditLogger:   newAuditLogger(deps.Logger, deps.Config.Authorization.Audit),
+		forwards:      map[string]*portForward{},
+	}
+}
+
+// Reload swaps in a new configuration and authorization evaluator (nil
+// when no policies are configured). Calls in flight finish with the ones
+// they started with; the tools registered at startup stay registered.
+func (m *Manager) Reload(config *api.Configuration, authz *authorization.Evaluator) {
+	redactor := redaction.NewRedactor(config.Kubernetes.Tools.Redaction)
+	auditLogger := newAuditLogger(m.logger, config.Authorization.Audit)
+
+	m.reloadMu.Lock()
+	defer m.reloadMu.Unlock()
+	m.config = config
+	m.authz = authz
+	m.redactor = redactor
+	m.auditLogger = auditLogger
+}
+
+// currentConfig returns the configuration in effect
+func (m *Manager) currentConfig() *api.Configuration {
+	m.reloadMu.RLock()
+	defer m.reloadMu.RUnlock()
+	return m.config
+}
+
+// currentAuthz returns the authorization evaluator in effect, nil when no
+// policies are configured
+func (m *Manager) currentAuthz() *authorization.Evaluator {
+	m.reloadMu.RLock()
+	defer m.reloadMu.RUnlock()
+	return m.authz
+}
+
+// currentRedactor returns the redactor of the configuration in effect
+func (m *Manager) currentRedactor() *redaction.Redactor {
+	m.reloadMu.RLock()
+	defer m.reloadMu.RUnlock()
+	return m.redactor
+}
+
+// currentAuditLogger returns the audit logger in effect, nil when auditing
+// is disabled
+func (m *Manager) currentAuditLogger() *slog.Logger {
+	m.reloadMu.RLock()
+	defer m.reloadMu.RUnlock()
+	return m.auditLogger
+}
+
+// newAuditLogger returns the logger of authorization decisions, or nil
+// when 'authorization.audit' is disabled
+func newAuditLogger(logger *slog.Logger, config api.AuditConfig) *slog.Logger {
+	if !config.Enabled {
+		return nil
+	}
+	var handler slog.Handler
+	switch config.Format {
+	case "", "json":
+		handler = slog.NewJSONHandler(os.Stderr, nil)
+	case "text":
+		handler = slog.NewTextHandler(os.Stderr, nil)
+	default:
+		logger.Warn("unknown authorization.audit.format, using json", "format", config.Format)
+		handler = slog.NewJSONHandler(os.Stderr, nil)
 	}
+	return slog.New(handler).With("log_type", "audit")
 }
 
 func (m *Manager) toolName(base string) string {
 	return m.toolPrefix + base
 }
 
-// RegisterAll registers all Kubernetes tools with the MCP server
+// defaultRequestTimeout bounds a tool call when 'kubernetes.tools.request_timeout' is unset
+const defaultRequestTimeout = 30 * time.Second
+
+// requestTimeout returns the configured bound of a single tool call
+func (m *Manager) requestTimeout() time.Duration {
+	if timeout := m.currentConfig().Kubernetes.Tools.RequestTimeout; timeout > 0 {
+		return timeout
+	}
+	return defaultRequestTimeout
+}
+
+// addTool registers a tool whose handler runs under the request timeout
+func (m *Manager) addTool(tool mcp.Tool, handler server.ToolHandlerFunc) {
+	maxOutputBytesParam()(&tool)
+	m.mcpServer.AddTool(tool, m.withMetrics(m.withOutputLimit(m.withRequestTimeout(m.withCaller(handler), 0))))
+}
+
+// addWaitingTool registers a tool that waits by design for up to 'maxWait'
+// (a rollout, a log line, a command). The wait is granted on top of the
+// request timeout so the API calls around it keep their own budget.
+func (m *Manager) addWaitingTool(tool mcp.Tool, handler server.ToolHandlerFunc, maxWait time.Duration) {
+	maxOutputBytesParam()(&tool)
+	m.mcpServer.AddTool(tool, m.withMetrics(m.withOutputLimit(m.withRequestTimeout(m.withCaller(handler), maxWait))))
+}
+
+// defaultIdentityClaim names the caller when 'authorization.identity_claim' is unset
+const defaultIdentityClaim = "sub"
+
+// identityClaim returns the payload claim naming the caller
+func (m *Manager) identityClaim() string {
+	if claim := m.currentConfig().Authorization.IdentityClaim; claim != "" {
+		return claim
+	}
+	return defaultIdentityClaim
+}
+
+// withCaller attaches the authenticated caller to the handler's context, so
+// contexts with impersonate.from_caller send every API request as that user.
+func (m *Manager) withCaller(handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		payload := m.extractAuthPayload(request)
+		if payload == nil {
+			return handler(ctx, request)
+		}
+
+		name, _ := payload[m.identityClaim()].(string)
+		return handler(kubernetes.WithCaller(ctx, kubernetes.Caller{Name: name, Claims: payload}), request)
+	}
+}
+
+// withMetrics records the call's duration and result, labelled with the
+// tool (without prefix) and the context it targeted
+func (m *Manager) withMetrics(handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	if m.metrics == nil {
+		return handler
+	}
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		result, err := handler(ctx, request)
+
+		tool := strings.TrimPrefix(request.Params.Name, m.toolPrefix)
+		k8sContext := m.metricsContext(m.getContextParam(ctx, request.GetArguments()))
+		m.metrics.ObserveToolCall(tool, k8sContext, time.Since(start), err != nil || (result != nil && result.IsError))
+		return result, err
+	}
+}
+
+// metricsContext bounds the context label to the configured contexts; any
+// other name a caller sends is reported as "unknown"
+func (m *Manager) metricsContext(k8sContext string) string {
+	if _, ok := m.clientManager.GetContextConfig(k8sContext); !ok {
+		return "unknown"
+	}
+	return k8sContext
+}
+
+// withRequestTimeout bounds the handler's context, so a hung API server
+// can't hold the call forever, and rewrites a failure caused by the
+// deadline into an explicit timeout error.
+func (m *Manager) withRequestTimeout(handler server.ToolHandlerFunc, maxWait time.Duration) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		timeout := m.requestTimeout() + maxWait
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		result, err := handler(ctx, request)
+		if err == nil && result != nil && result.IsError && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return errorResult(fmt.Errorf("request timed out after %s (kubernetes.tools.request_timeout): the Kubernetes API did not answer in time; %s",
+				timeout, resultText(result))), nil
+		}
+		return result, err
+	}
+}
+
+// toolRegistration pairs a tool's base name (without prefix) with the method
+// that registers it
+type toolRegistration struct {
+	name     string
+	register func()
+}
+
+// RegisterAll registers the Kubernetes tools with the MCP server. Tools
+// filtered out by 'kubernetes.tools.enabled_tools' / 'disabled_tools' are
+// never registered, so they don't appear in the MCP tool list at all.
 func (m *Manager) RegisterAll() {
-	// Read tools
-	m.registerGetResource()
-	m.registerListResources()
-	m.registerDescribeResource()
-
-	// Modification tools
-	m.registerApplyManifest()
-	m.registerPatchResource()
-	m.registerDeleteResource()
-	m.registerDeleteResources()
-
-	// Scaling tools
-	m.registerScaleResource()
-
-	// Rollout tools
-	m.registerGetRolloutStatus()
-	m.registerRestartRollout()
-	m.registerUndoRollout()
-
-	// Logs and debug
-	m.registerGetLogs()
-	m.registerExecCommand()
-
-	// Cluster info
-	m.registerListAPIResources()
-	m.registerListAPIVersions()
-	m.registerGetClusterInfo()
-
-	// Namespace
-	m.registerListNamespaces()
-
-	// Context
-	m.registerGetCurrentContext()
-	m.registerListContexts()
-	m.registerSwitchContext()
-
-	// Events
-	m.registerListEvents()
-
-	// RBAC
-	m.registerCheckPermission()
-
-	// Metrics
-	m.registerGetPodMetrics()
-	m.registerGetNodeMetrics()
-
-	// Diff
-	m.registerDiffManifest()
+	registrations := []toolRegistration{
+		// Read tools
+		{"get_resource", m.registerGetResource},
+		{"export_resource", m.registerExportResource},
+		{"resource_exists", m.registerResourceExists},
+		{"list_resources", m.registerListResources},
+		{"describe_resource", m.registerDescribeResource},
+		{"get_resource_tree", m.registerGetResourceTree},
+		{"watch_resources", m.registerWatchResources},
+		{"wait_for_condition", m.registerWaitForCondition},
+
+		// Modification tools
+		{"apply_manifest", m.registerApplyManifest},
+		{"apply_and_wait", m.registerApplyAndWait},
+		{"create_resource", m.registerCreateResource},
+		{"replace_resource", m.registerReplaceResource},
+		{"snapshot_resource", m.registerSnapshotResource},
+		{"restore_resource", m.registerRestoreResource},
+		{"patch_resource", m.registerPatchResource},
+		{"patch_list_element", m.registerPatchListElement},
+		{"set_image", m.registerSetImage},
+		{"delete_resource", m.registerDeleteResource},
+		{"delete_resources", m.registerDeleteResources},
+		{"label_resources", m.registerLabelResources},
+		{"annotate_resources", m.registerAnnotateResources},
+		{"label_resource", m.registerLabelResource},
+		{"annotate_resource", m.registerAnnotateResource},
+
+		// Scaling tools
+		{"scale_resource", m.registerScaleResource},
+		{"describe_hpa", m.registerDescribeHPA},
+
+		// Rollout tools
+		{"get_rollout_status", m.registerGetRolloutStatus},
+		{"restart_rollout", m.registerRestartRollout},
+		{"undo_rollout", m.registerUndoRollout},
+		{"rollout_history", m.registerRolloutHistory},
+		{"pause_rollout", m.registerPauseRollout},
+		{"resume_rollout", m.registerResumeRollout},
+
+		// Batch tools
+		{"get_job_status", m.registerGetJobStatus},
+		{"trigger_cronjob", m.registerTriggerCronJob},
+
+		// Logs and debug
+		{"get_logs", m.registerGetLogs},
+		{"follow_logs", m.registerFollowLogs},
+		{"exec_command", m.registerExecCommand},
+		{"copy_from_pod", m.registerCopyFromPod},
+		{"copy_to_pod", m.registerCopyToPod},
+		{"port_forward", m.registerPortForward},
+		{"stop_port_forward", m.registerStopPortForward},
+		{"get_logs_multi_context", m.registerGetLogsMultiContext},
+		{"get_logs_by_selector", m.registerGetLogsBySelector},
+		{"wait_for_log_pattern", m.registerWaitForLogPattern},
+		{"get_probe_status", m.registerGetProbeStatus},
+		{"get_pod_context", m.registerGetPodContext},
+
+		// Cluster info
+		{"list_api_resources", m.registerListAPIResources},
+		{"list_api_versions", m.registerListAPIVersions},
+		{"explain_resource", m.registerExplainResource},
+		{"get_cluster_info", m.registerGetClusterInfo},
+		{"list_webhooks", m.registerListWebhooks},
+
+		// Namespace
+		{"list_namespaces", m.registerListNamespaces},
+		{"describe_namespace", m.registerDescribeNamespace},
+
+		// Nodes
+		{"get_node_status", m.registerGetNodeStatus},
+		{"cordon_node", m.registerCordonNode},
+		{"uncordon_node", m.registerUncordonNode},
+		{"drain_node", m.registerDrainNode},
+
+		// Context
+		{"get_current_context", m.registerGetCurrentContext},
+		{"list_contexts", m.registerListContexts},
+		{"check_contexts", m.registerCheckContexts},
+		{"switch_context", m.registerSwitchContext},
+
+		// Events
+		{"list_events", m.registerListEvents},
+		{"get_events_for_resource", m.registerGetEventsForResource},
+
+		// RBAC
+		{"check_permission", m.registerCheckPermission},
+		{"test_authorization", m.registerTestAuthorization},
+		{"create_sa_token", m.registerCreateSAToken},
+
+		// Metrics
+		{"get_pod_metrics", m.registerGetPodMetrics},
+		{"get_node_metrics", m.registerGetNodeMetrics},
+		{"top_nodes", m.registerTopNodes},
+
+		// Diff
+		{"diff_manifest", m.registerDiffManifest},
+
+		// Disruption
+		{"get_pdb_status", m.registerGetPDBStatus},
+	}
+
+	toolsConfig := m.currentConfig().Kubernetes.Tools
+	names := make([]string, 0, len(registrations))
+	var skipped []string
+	for _, r := range registrations {
+		names = append(names, r.name)
+		if !toolEnabled(r.name, toolsConfig.EnabledTools, toolsConfig.DisabledTools) {
+			skipped = append(skipped, r.name)
+			continue
+		}
+		r.register()
+	}
+
+	// A typo in the lists silently keeps a tool exposed (or hidden); say so.
+	for _, pattern := range append(append([]string{}, toolsConfig.EnabledTools...), toolsConfig.DisabledTools...) {
+		if !slices.ContainsFunc(names, func(name string) bool { return toolPatternMatches(pattern, name) }) {
+			m.logger.Warn("tool filter pattern matches no tool", "pattern", pattern)
+		}
+	}
+	if len(skipped) > 0 {
+		m.logger.Info("tools disabled by configuration", "tools", skipped)
+	}
+}
+
+// toolEnabled reports whether a tool passes the enabled/disabled filters.
+// An empty enabled list means every tool; disabled wins over enabled.
+func toolEnabled(name string, enabled, disabled []string) bool {
+	for _, pattern := range disabled {
+		if toolPatternMatches(pattern, name) {
+			return false
+		}
+	}
+	if len(enabled) == 0 {
+		return true
+	}
+	for _, pattern := range enabled {
+		if toolPatternMatches(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// toolPatternMatches matches a tool base name against an exact name or a
+// glob such as 'get_*'
+func toolPatternMatches(pattern, name string) bool {
+	ok, _ := path.Match(pattern, name)
+	return ok
 }
@@ -0,0 +1,451 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"kubernetes-mcp/api"
+	"kubernetes-mcp/internal/authorization"
+	"kubernetes-mcp/internal/kubernetes"
+	"kubernetes-mcp/internal/middlewares"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestToolEnabled(t *testing.T) {
+	tests := []struct {
+		name     string
+		tool     string
+		enabled  []string
+		disabled []string
+		want     bool
+	}{
+		{name: "no filters", tool: "exec_command", want: true},
+		{name: "disabled exact", tool: "exec_command", disabled: []string{"exec_command"}, want: false},
+		{name: "disabled glob", tool: "delete_resources", disabled: []string{"delete_*"}, want: false},
+		{name: "not in enabled", tool: "exec_command", enabled: []string{"get_*", "list_*"}, want: false},
+		{name: "in enabled glob", tool: "list_events", enabled: []string{"get_*", "list_*"}, want: true},
+		{name: "disabled wins", tool: "get_logs", enabled: []string{"get_*"}, disabled: []string{"get_logs"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := toolEnabled(tt.tool, tt.enabled, tt.disabled); got != tt.want {
+				t.Errorf("toolEnabled(%q, %v, %v) = %v, want %v", tt.tool, tt.enabled, tt.disabled, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterAll_ToolFilters(t *testing.T) {
+	e := newFakeEnv(t)
+	e.manager.toolPrefix = "k8s_"
+	e.manager.config.Kubernetes.Tools.EnabledTools = []string{"get_*", "delete_resource"}
+	e.manager.config.Kubernetes.Tools.DisabledTools = []string{"get_logs"}
+
+	e.manager.RegisterAll()
+	tools := e.manager.mcpServer.ListTools()
+
+	for _, name := range []string{"k8s_get_resource", "k8s_get_node_status", "k8s_delete_resource"} {
+		if _, ok := tools[name]; !ok {
+			t.Errorf("expected %s to be registered", name)
+		}
+	}
+	for _, name := range []string{"k8s_get_logs", "k8s_exec_command", "k8s_delete_resources", "k8s_list_resources"} {
+		if _, ok := tools[name]; ok {
+			t.Errorf("expected %s NOT to be registered", name)
+		}
+	}
+}
+
+func TestRegisterAll_NoFilters(t *testing.T) {
+	e := newFakeEnv(t)
+	e.manager.RegisterAll()
+
+	if _, ok := e.manager.mcpServer.ListTools()["exec_command"]; !ok {
+		t.Fatalf("expected every tool to be registered without filters")
+	}
+}
+
+func TestRegisterAll_ToolVerbs(t *testing.T) {
+	e := newFakeEnv(t)
+	e.manager.RegisterAll()
+
+	// A tool without verbs is invisible to 'verbs' rules
+	for name := range e.manager.mcpServer.ListTools() {
+		if len(authorization.VerbsForTool(name)) == 0 {
+			t.Errorf("tool %s has no entry in authorization.ToolVerbs", name)
+		}
+	}
+}
+
+func TestCheckAuthorization_Namespaced(t *testing.T) {
+	e := newFakeEnv(t)
+	authz, err := authorization.NewEvaluator(&api.AuthorizationConfig{
+		AllowAnonymous: true,
+		Policies: []api.AuthorizationPolicy{
+			{
+				Name:  "everyone",
+				Match: api.MatchConfig{Expression: "true"},
+				Rules: []api.AuthorizationRule{{Effect: api.RuleEffectAllow}},
+			},
+			{
+				Name:  "no-cluster-scoped",
+				Match: api.MatchConfig{Expression: "!resource.namespaced"},
+				Rules: []api.AuthorizationRule{{Effect: api.RuleEffectDeny}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewEvaluator: %v", err)
+	}
+	e.manager.authz = authz
+
+	tests := []struct {
+		name     string
+		resource authorization.ResourceInfo
+		allowed  bool
+	}{
+		{name: "namespaced core", resource: authorization.ResourceInfo{Version: "v1", Resource: "pods"}, allowed: true},
+		{name: "namespaced group", resource: authorization.ResourceInfo{Group: "apps", Version: "v1", Resource: "deployments"}, allowed: true},
+		{name: "cluster-scoped", resource: authorization.ResourceInfo{Version: "v1", Resource: "namespaces"}, allowed: false},
+		{name: "cluster-scoped without version", resource: authorization.ResourceInfo{Resource: "nodes"}, allowed: false},
+		{name: "unknown type", resource: authorization.ResourceInfo{Group: "example.com", Version: "v1", Resource: "widgets"}, allowed: false},
+		{name: "virtual resource", resource: authorization.ResourceInfo{Group: authorization.VirtualResourceGroup, Resource: authorization.VirtualResourceContext}, allowed: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := e.manager.checkAuthorization(makeRequest(nil), "get_resource", fakeContext, "default", tt.resource)
+			if tt.allowed && err != nil {
+				t.Fatalf("expected access, got %v", err)
+			}
+			if !tt.allowed && (err == nil || !strings.Contains(err.Error(), "access denied")) {
+				t.Fatalf("expected access denied, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCheckAuthorization_Audit(t *testing.T) {
+	e := newFakeEnv(t)
+	authz, err := authorization.NewEvaluator(&api.AuthorizationConfig{
+		Policies: []api.AuthorizationPolicy{
+			{
+				Name:  "devs",
+				Match: api.MatchConfig{Expression: "true"},
+				Rules: []api.AuthorizationRule{{Effect: api.RuleEffectAllow, Tools: []string{"get_resource"}}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewEvaluator: %v", err)
+	}
+	e.manager.authz = authz
+	var audit bytes.Buffer
+	e.manager.auditLogger = slog.New(slog.NewJSONHandler(&audit, nil))
+
+	request := makeRequest(nil)
+	request.Header = http.Header{}
+	raw, _ := json.Marshal(map[string]any{"sub": "jane"})
+	request.Header.Set(middlewares.AuthPayloadHeader, hex.EncodeToString(raw))
+
+	pod := authorization.ResourceInfo{Version: "v1", Resource: "pods", Name: "api-0"}
+	if err := e.manager.checkAuthorization(request, "get_resource", fakeContext, "default", pod); err != nil {
+		t.Fatalf("expected access, got %v", err)
+	}
+	if err := e.manager.checkAuthorization(request, "delete_resource", fakeContext, "default", pod); err == nil {
+		t.Fatal("expected access denied")
+	}
+
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(audit.String()), "\n") {
+		entry := map[string]any{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("audit line is not JSON: %q", line)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected one audit entry per decision, got %d", len(entries))
+	}
+	allow, deny := entries[0], entries[1]
+	if allow["identity"] != "jane" || allow["tool"] != "get_resource" || allow["context"] != fakeContext ||
+		allow["namespace"] != "default" || allow["resource"] != "pods" || allow["name"] != "api-0" ||
+		allow["decision"] != "allow" || allow["deciding_policy"] != "devs" {
+		t.Fatalf("unexpected allow entry: %v", allow)
+	}
+	if deny["decision"] != "deny" || deny["deciding_policy"] != "" || fmt.Sprint(deny["matched_policies"]) != "[devs]" {
+		t.Fatalf("unexpected deny entry: %v", deny)
+	}
+}
+
+func TestManagerReload(t *testing.T) {
+	e := newFakeEnv(t)
+	pod := authorization.ResourceInfo{Version: "v1", Resource: "pods"}
+	if err := e.manager.checkAuthorization(makeRequest(nil), "delete_resource", fakeContext, "default", pod); err != nil {
+		t.Fatalf("expected access without policies, got %v", err)
+	}
+
+	config := *e.manager.currentConfig()
+	config.Authorization = api.AuthorizationConfig{
+		AllowAnonymous: true,
+		Policies: []api.AuthorizationPolicy{{
+			Name:  "read-only",
+			Match: api.MatchConfig{Expression: "true"},
+			Rules: []api.AuthorizationRule{{Effect: api.RuleEffectAllow, Verbs: []string{"get", "list"}}},
+		}},
+	}
+	config.Kubernetes.Tools.RequestTimeout = 5 * time.Second
+	authz, err := authorization.NewEvaluator(&config.Authorization)
+	if err != nil {
+		t.Fatalf("NewEvaluator: %v", err)
+	}
+	e.manager.Reload(&config, authz)
+
+	if err := e.manager.checkAuthorization(makeRequest(nil), "delete_resource", fakeContext, "default", pod); err == nil {
+		t.Error("expected the reloaded policies to deny the delete")
+	}
+	if err := e.manager.checkAuthorization(makeRequest(nil), "get_resource", fakeContext, "default", pod); err != nil {
+		t.Errorf("expected the reloaded policies to allow the read, got %v", err)
+	}
+	if got := e.manager.requestTimeout(); got != 5*time.Second {
+		t.Errorf("request timeout = %s, want the reloaded 5s", got)
+	}
+}
+
+func TestWithRequestTimeout(t *testing.T) {
+	e := newFakeEnv(t)
+	e.manager.config.Kubernetes.Tools.RequestTimeout = 20 * time.Millisecond
+
+	// A hung API call: only returns once the context is done
+	hung := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		<-ctx.Done()
+		return errorResult(fmt.Errorf("get pods: %w", ctx.Err())), nil
+	}
+	res, err := e.manager.withRequestTimeout(hung, 0)(context.Background(), makeRequest(nil))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectErr(t, res, "hung handler")
+	requireContains(t, out, "request timed out after 20ms", "expected an explicit timeout error")
+	requireContains(t, out, "get pods: context deadline exceeded", "expected the original error kept")
+
+	// Ordinary failures are passed through untouched
+	failing := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return errorResult(fmt.Errorf("pods \"web\" not found")), nil
+	}
+	res, _ = e.manager.withRequestTimeout(failing, 0)(context.Background(), makeRequest(nil))
+	if out := expectErr(t, res, "failing handler"); strings.Contains(out, "timed out") {
+		t.Fatalf("non-timeout error rewritten: %s", out)
+	}
+
+	// Waiting tools get their maximum wait on top of the request timeout
+	var deadline time.Time
+	waiting := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		deadline, _ = ctx.Deadline()
+		return successResult("ok"), nil
+	}
+	start := time.Now()
+	res, _ = e.manager.withRequestTimeout(waiting, time.Minute)(context.Background(), makeRequest(nil))
+	expectOK(t, res, "waiting handler")
+	if remaining := deadline.Sub(start); remaining < time.Minute {
+		t.Fatalf("expected at least 1m for a waiting tool, got %s", remaining)
+	}
+}
+
+func TestWithOutputLimit(t *testing.T) {
+	e := newFakeEnv(t)
+	e.manager.config.Kubernetes.Tools.MaxOutputBytes = 16
+
+	large := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return successResult("line-one\nline-two\nline-three\n"), nil
+	}
+	call := func(args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		res, err := e.manager.withOutputLimit(large)(context.Background(), makeRequest(args))
+		if err != nil {
+			t.Fatalf("go-error: %v", err)
+		}
+		return res
+	}
+
+	want := "line-one\n... [truncated 20 of 29 bytes; refine with yq_expressions or a selector]"
+	if out := expectOK(t, call(nil), "configured cap"); out != want {
+		t.Fatalf("unexpected truncation:\n%q\nwant:\n%q", out, want)
+	}
+
+	// The per-call value overrides the configured one
+	if out := expectOK(t, call(map[string]any{"max_output_bytes": float64(1024)}), "raised cap"); strings.Contains(out, "truncated") {
+		t.Fatalf("expected the whole output, got:\n%s", out)
+	}
+	requireContains(t, expectErr(t, call(map[string]any{"max_output_bytes": float64(0)}), "zero cap"),
+		"max_output_bytes must be between 1 and", "expected the range error")
+
+	// A negative config removes the cap
+	e.manager.config.Kubernetes.Tools.MaxOutputBytes = -1
+	if out := expectOK(t, call(nil), "no cap"); strings.Contains(out, "truncated") {
+		t.Fatalf("expected the whole output, got:\n%s", out)
+	}
+}
+
+func TestTruncateOutput(t *testing.T) {
+	if got := truncateOutput("short", 10); got != "short" {
+		t.Fatalf("short text must be unchanged, got %q", got)
+	}
+	// A single long line is cut on a rune boundary
+	got := truncateOutput("ééééé", 5)
+	if !strings.HasPrefix(got, "éé\n... [truncated 6 of 10 bytes") {
+		t.Fatalf("unexpected truncation %q", got)
+	}
+}
+
+func TestWithCaller(t *testing.T) {
+	e := newFakeEnv(t)
+
+	var caller kubernetes.Caller
+	var found bool
+	record := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		caller, found = kubernetes.CallerFrom(ctx)
+		return successResult("ok"), nil
+	}
+	call := func(payload map[string]any) {
+		t.Helper()
+		request := makeRequest(nil)
+		if payload != nil {
+			raw, _ := json.Marshal(payload)
+			request.Header = http.Header{}
+			request.Header.Set(middlewares.AuthPayloadHeader, hex.EncodeToString(raw))
+		}
+		res, err := e.manager.withCaller(record)(context.Background(), request)
+		if err != nil {
+			t.Fatalf("go-error: %v", err)
+		}
+		expectOK(t, res, "recording handler")
+	}
+
+	// Unauthenticated calls carry no caller
+	call(nil)
+	if found {
+		t.Fatalf("expected no caller, got %+v", caller)
+	}
+
+	payload := map[string]any{"sub": "1234", "email": "jane@example.com", "groups": []any{"sre"}}
+	call(payload)
+	if !found || caller.Name != "1234" || caller.Claims["email"] != "jane@example.com" {
+		t.Fatalf("expected the 'sub' claim by default, got %+v", caller)
+	}
+
+	e.manager.config.Authorization.IdentityClaim = "email"
+	call(payload)
+	if caller.Name != "jane@example.com" {
+		t.Fatalf("expected the configured identity claim, got %q", caller.Name)
+	}
+}
+
+// fakeSession is an MCP client session with a fixed ID
+type fakeSession struct{ id string }
+
+func (s fakeSession) Initialize()                                         {}
+func (s fakeSession) Initialized() bool                                   { return true }
+func (s fakeSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return nil }
+func (s fakeSession) SessionID() string                                   { return s.id }
+
+func TestGetContextParam_Session(t *testing.T) {
+	e := newFakeEnv(t)
+	recorder := &sessionRecorder{fakeClientProvider: e.provider}
+	e.manager.clientManager = recorder
+
+	ctx := e.manager.mcpServer.WithContext(context.Background(), fakeSession{id: "abc"})
+	if got := e.manager.getContextParam(ctx, map[string]any{}); got != fakeContext {
+		t.Fatalf("expected the session's current context, got %q", got)
+	}
+	if recorder.session != "abc" {
+		t.Fatalf("expected the current context looked up for session abc, got %q", recorder.session)
+	}
+
+	// An explicit context wins without a lookup
+	recorder.session = ""
+	if got := e.manager.getContextParam(ctx, map[string]any{"context": "other"}); got != "other" || recorder.session != "" {
+		t.Fatalf("expected the explicit context, got %q (lookup for %q)", got, recorder.session)
+	}
+
+	if got := sessionID(context.Background()); got != "" {
+		t.Fatalf("expected no session outside of one, got %q", got)
+	}
+}
+
+// sessionRecorder records the session the current context is read for
+type sessionRecorder struct {
+	*fakeClientProvider
+	session string
+}
+
+func (r *sessionRecorder) GetCurrentContext(session string) string {
+	r.session = session
+	return r.fakeClientProvider.GetCurrentContext(session)
+}
+
+func TestWithMetrics(t *testing.T) {
+	e := newFakeEnv(t)
+	e.manager.metrics = middlewares.NewMetricsMiddleware(middlewares.MetricsMiddlewareDependencies{})
+
+	call := func(k8sContext string, handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)) {
+		t.Helper()
+		request := makeRequest(map[string]any{"context": k8sContext})
+		request.Params.Name = e.manager.toolName("delete_resource")
+		if _, err := e.manager.withMetrics(handler)(context.Background(), request); err != nil {
+			t.Fatalf("go-error: %v", err)
+		}
+	}
+	ok := func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return successResult("ok"), nil
+	}
+	failing := func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return errorResult(fmt.Errorf("boom")), nil
+	}
+
+	call(fakeContext, ok)
+	call(fakeContext, failing)
+	// A context name the caller made up must not become a label value
+	call("made-up-context", ok)
+
+	rec := httptest.NewRecorder()
+	e.manager.metrics.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	scraped := rec.Body.String()
+	for _, want := range []string{
+		`kubernetes_mcp_tool_calls_total{context="fake",result="success",tool="delete_resource"} 1`,
+		`kubernetes_mcp_tool_calls_total{context="fake",result="error",tool="delete_resource"} 1`,
+		`kubernetes_mcp_tool_calls_total{context="unknown",result="success",tool="delete_resource"} 1`,
+		`kubernetes_mcp_tool_call_duration_seconds_count{context="fake",tool="delete_resource"} 2`,
+	} {
+		requireContains(t, scraped, want, "scraped metrics")
+	}
+	if strings.Contains(scraped, "made-up-context") {
+		t.Fatal("an unconfigured context leaked into the labels")
+	}
+}
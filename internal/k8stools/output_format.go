@@ -0,0 +1,524 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/duration"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// Output formats accepted by 'output_format'
+const (
+	outputFormatYAML  = "yaml"
+	outputFormatJSON  = "json"
+	outputFormatTable = "table"
+)
+
+const tableNone = "<none>"
+
+// outputFormatParam is the 'output_format' parameter of the read tools
+func outputFormatParam() mcp.ToolOption {
+	return mcp.WithString("output_format",
+		mcp.Enum(outputFormatYAML, outputFormatJSON, outputFormatTable),
+		mcp.Description("Output format: 'yaml' (default), 'json', or 'table' for a compact kubectl-like column view (NAME, READY, STATUS, AGE, ... depending on the kind). 'table' cannot be combined with 'yq_expressions' or 'jq_expressions'; use 'json' for that."))
+}
+
+// tableColumn is one column of the table view after NAME
+type tableColumn struct {
+	header string
+	value  func(obj map[string]any) string
+}
+
+var ageColumn = tableColumn{"AGE", objectAge}
+
+// tableColumns are the columns shown per kind. Kinds not listed get NAME
+// and AGE only.
+var tableColumns = map[string][]tableColumn{
+	"Pod": {
+		{"READY", podReadyColumn},
+		{"STATUS", podStatusColumn},
+		{"RESTARTS", podRestartsColumn},
+		ageColumn,
+	},
+	"Deployment": {
+		{"READY", replicasColumn("readyReplicas")},
+		{"UP-TO-DATE", fieldColumn("status", "updatedReplicas")},
+		{"AVAILABLE", fieldColumn("status", "availableReplicas")},
+		ageColumn,
+	},
+	"StatefulSet": {
+		{"READY", replicasColumn("readyReplicas")},
+		ageColumn,
+	},
+	"DaemonSet": {
+		{"DESIRED", fieldColumn("status", "desiredNumberScheduled")},
+		{"CURRENT", fieldColumn("status", "currentNumberScheduled")},
+		{"READY", fieldColumn("status", "numberReady")},
+		{"UP-TO-DATE", fieldColumn("status", "updatedNumberScheduled")},
+		{"AVAILABLE", fieldColumn("status", "numberAvailable")},
+		ageColumn,
+	},
+	"Job": {
+		{"COMPLETIONS", jobCompletionsColumn},
+		ageColumn,
+	},
+	"Service": {
+		{"TYPE", fieldColumn("spec", "type")},
+		{"CLUSTER-IP", fieldColumn("spec", "clusterIP")},
+		{"PORTS", servicePortsColumn},
+		ageColumn,
+	},
+	"Node": {
+		{"STATUS", nodeStatusColumn},
+		ageColumn,
+	},
+	"Namespace": {
+		{"STATUS", fieldColumn("status", "phase")},
+		ageColumn,
+	},
+	"PodMetrics": {
+		{"CPU", podMetricsColumn("cpu")},
+		{"MEMORY", podMetricsColumn("memory")},
+	},
+	"NodeMetrics": {
+		{"CPU", usageColumn("cpu")},
+		{"MEMORY", usageColumn("memory")},
+	},
+}
+
+// formatOutput renders a read tool's YAML output in the requested
+// 'output_format'. YAML and JSON go through applyOutputFilters; the table
+// view is built from the redacted objects and takes no filters. 'kind'
+// names the kind of the objects when the output does not carry it (typed
+// lists drop it).
+func (m *Manager) formatOutput(yamlData string, args map[string]any, kind string) (string, error) {
+	format, _ := args["output_format"].(string)
+	switch format {
+	case "", outputFormatYAML:
+		return m.applyOutputFilters(yamlData, args)
+	case outputFormatJSON:
+		filtered, err := m.applyOutputFilters(yamlData, args)
+		if err != nil {
+			return "", err
+		}
+		return yamlToJSON(filtered)
+	case outputFormatTable:
+		if len(stringArgs(args, "yq_expressions")) > 0 || len(stringArgs(args, "jq_expressions")) > 0 {
+			return "", fmt.Errorf("output_format 'table' cannot be combined with yq_expressions or jq_expressions; use 'yaml' or 'json'")
+		}
+		var data any
+		if err := yaml.Unmarshal([]byte(m.redactYAML(yamlData)), &data); err != nil {
+			return "", err
+		}
+		return renderTable(data, kind), nil
+	default:
+		return "", fmt.Errorf("output_format must be one of yaml, json, table, got %q", format)
+	}
+}
+
+// yamlToJSON converts each YAML document to indented JSON, one per document
+func yamlToJSON(yamlData string) (string, error) {
+	var docs []string
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(strings.NewReader(yamlData)))
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if strings.TrimSpace(string(doc)) == "" {
+			continue
+		}
+
+		data, err := yaml.YAMLToJSON(doc)
+		if err != nil {
+			return "", err
+		}
+		var out bytes.Buffer
+		if err := json.Indent(&out, data, "", "  "); err != nil {
+			return "", err
+		}
+		docs = append(docs, out.String())
+	}
+	return strings.Join(docs, "\n"), nil
+}
+
+// renderTable lays out a List, an array of rows or a single object as
+// columns. Objects with metadata use the per-kind columns; flat rows (the
+// summaries some tools return) show their own keys, 'name' first.
+func renderTable(data any, kind string) string {
+	var rows []map[string]any
+	var continueToken string
+	switch v := data.(type) {
+	case []any:
+		rows = tableRows(v)
+	case map[string]any:
+		if items, ok := v["items"].([]any); ok {
+			rows = tableRows(items)
+			if listKind, _ := v["kind"].(string); kind == "" && strings.HasSuffix(listKind, "List") {
+				kind = strings.TrimSuffix(listKind, "List")
+			}
+			continueToken, _ = tableField(v, "metadata", "continue").(string)
+		} else {
+			rows = []map[string]any{v}
+		}
+	}
+	if len(rows) == 0 {
+		return "No resources found."
+	}
+
+	var headers []string
+	var cells [][]string
+	if _, hasMetadata := rows[0]["metadata"]; hasMetadata {
+		headers, cells = objectTable(rows, kind)
+	} else {
+		headers, cells = flatTable(rows)
+	}
+
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+	for _, row := range cells {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	w.Flush()
+
+	if continueToken != "" {
+		fmt.Fprintf(&sb, "\nMore items available: pass continue_token=%q to fetch the next page.\n", continueToken)
+	}
+	return sb.String()
+}
+
+// tableRows keeps the object elements of a list
+func tableRows(items []any) []map[string]any {
+	rows := make([]map[string]any, 0, len(items))
+	for _, item := range items {
+		if obj, ok := item.(map[string]any); ok {
+			rows = append(rows, obj)
+		}
+	}
+	return rows
+}
+
+// objectTable builds NAME plus the kind's columns, with a NAMESPACE column
+// in front when the rows span more than one namespace.
+func objectTable(rows []map[string]any, kind string) ([]string, [][]string) {
+	namespaces := map[string]bool{}
+	for _, row := range rows {
+		ns, _ := tableField(row, "metadata", "namespace").(string)
+		namespaces[ns] = true
+	}
+	withNamespace := len(namespaces) > 1
+
+	headers := []string{"NAME"}
+	if withNamespace {
+		headers = []string{"NAMESPACE", "NAME"}
+	}
+
+	cells := make([][]string, 0, len(rows))
+	for _, row := range rows {
+		rowKind, _ := row["kind"].(string)
+		if rowKind == "" {
+			rowKind = kind
+		}
+		columns, ok := tableColumns[rowKind]
+		if !ok {
+			columns = []tableColumn{ageColumn}
+		}
+		if len(cells) == 0 {
+			for _, c := range columns {
+				headers = append(headers, c.header)
+			}
+		}
+
+		cell := []string{cellString(tableField(row, "metadata", "name"))}
+		if withNamespace {
+			cell = append([]string{cellString(tableField(row, "metadata", "namespace"))}, cell...)
+		}
+		for _, c := range columns {
+			cell = append(cell, c.value(row))
+		}
+		cells = append(cells, cell)
+	}
+	return headers, cells
+}
+
+// flatTable uses the rows' own keys as columns, 'name' first
+func flatTable(rows []map[string]any) ([]string, [][]string) {
+	keySet := map[string]bool{}
+	for _, row := range rows {
+		for k := range row {
+			keySet[k] = true
+		}
+	}
+	keys := make([]string, 0, len(keySet))
+	for k := range keySet {
+		if k != "name" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	if keySet["name"] {
+		keys = append([]string{"name"}, keys...)
+	}
+
+	headers := make([]string, len(keys))
+	for i, k := range keys {
+		headers[i] = strings.ToUpper(k)
+	}
+	cells := make([][]string, 0, len(rows))
+	for _, row := range rows {
+		cell := make([]string, len(keys))
+		for i, k := range keys {
+			cell[i] = cellString(row[k])
+		}
+		cells = append(cells, cell)
+	}
+	return headers, cells
+}
+
+// tableField returns the value at a path of nested maps, or nil
+func tableField(obj map[string]any, path ...string) any {
+	var current any = obj
+	for _, key := range path {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil
+		}
+		current = m[key]
+	}
+	return current
+}
+
+// cellString renders a scalar cell; missing values show as <none>
+func cellString(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return tableNone
+	case string:
+		if t == "" {
+			return tableNone
+		}
+		return t
+	case map[string]any, []any:
+		data, _ := json.Marshal(t)
+		return string(data)
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+// tableInt reads a numeric field, 0 when missing
+func tableInt(obj map[string]any, path ...string) int64 {
+	switch v := tableField(obj, path...).(type) {
+	case float64:
+		return int64(v)
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	}
+	return 0
+}
+
+func fieldColumn(path ...string) func(map[string]any) string {
+	return func(obj map[string]any) string {
+		return cellString(tableField(obj, path...))
+	}
+}
+
+// replicasColumn renders 'status.<field>/spec.replicas', as kubectl's READY
+func replicasColumn(field string) func(map[string]any) string {
+	return func(obj map[string]any) string {
+		return fmt.Sprintf("%d/%d", tableInt(obj, "status", field), tableInt(obj, "spec", "replicas"))
+	}
+}
+
+// objectAge renders the time since creationTimestamp the way kubectl does
+func objectAge(obj map[string]any) string {
+	created, _ := tableField(obj, "metadata", "creationTimestamp").(string)
+	t, err := time.Parse(time.RFC3339, created)
+	if err != nil {
+		return tableNone
+	}
+	return duration.HumanDuration(time.Since(t))
+}
+
+func podReadyColumn(obj map[string]any) string {
+	containers, _ := tableField(obj, "spec", "containers").([]any)
+	statuses, _ := tableField(obj, "status", "containerStatuses").([]any)
+	ready := 0
+	for _, s := range statuses {
+		if status, ok := s.(map[string]any); ok && status["ready"] == true {
+			ready++
+		}
+	}
+	return fmt.Sprintf("%d/%d", ready, len(containers))
+}
+
+// podStatusColumn follows kubectl: Terminating, then the Pod reason, then
+// the first waiting or terminated container reason, then the phase.
+func podStatusColumn(obj map[string]any) string {
+	if tableField(obj, "metadata", "deletionTimestamp") != nil {
+		return "Terminating"
+	}
+	if reason, _ := tableField(obj, "status", "reason").(string); reason != "" {
+		return reason
+	}
+	statuses, _ := tableField(obj, "status", "containerStatuses").([]any)
+	for _, s := range statuses {
+		status, ok := s.(map[string]any)
+		if !ok {
+			continue
+		}
+		for _, state := range []string{"waiting", "terminated"} {
+			if reason, _ := tableField(status, "state", state, "reason").(string); reason != "" {
+				return reason
+			}
+		}
+	}
+	return cellString(tableField(obj, "status", "phase"))
+}
+
+func podRestartsColumn(obj map[string]any) string {
+	statuses, _ := tableField(obj, "status", "containerStatuses").([]any)
+	var restarts int64
+	for _, s := range statuses {
+		if status, ok := s.(map[string]any); ok {
+			restarts += tableInt(status, "restartCount")
+		}
+	}
+	return fmt.Sprint(restarts)
+}
+
+func jobCompletionsColumn(obj map[string]any) string {
+	completions := int64(1)
+	if tableField(obj, "spec", "completions") != nil {
+		completions = tableInt(obj, "spec", "completions")
+	}
+	return fmt.Sprintf("%d/%d", tableInt(obj, "status", "succeeded"), completions)
+}
+
+func servicePortsColumn(obj map[string]any) string {
+	ports, _ := tableField(obj, "spec", "ports").([]any)
+	parts := make([]string, 0, len(ports))
+	for _, p := range ports {
+		port, ok := p.(map[string]any)
+		if !ok {
+			continue
+		}
+		protocol, _ := port["protocol"].(string)
+		if protocol == "" {
+			protocol = "TCP"
+		}
+		entry := fmt.Sprint(tableInt(port, "port"))
+		if nodePort := tableInt(port, "nodePort"); nodePort != 0 {
+			entry += fmt.Sprintf(":%d", nodePort)
+		}
+		parts = append(parts, entry+"/"+protocol)
+	}
+	if len(parts) == 0 {
+		return tableNone
+	}
+	return strings.Join(parts, ",")
+}
+
+func nodeStatusColumn(obj map[string]any) string {
+	status := "Unknown"
+	conditions, _ := tableField(obj, "status", "conditions").([]any)
+	for _, c := range conditions {
+		cond, ok := c.(map[string]any)
+		if !ok || cond["type"] != "Ready" {
+			continue
+		}
+		if cond["status"] == "True" {
+			status = "Ready"
+		} else {
+			status = "NotReady"
+		}
+	}
+	if unschedulable, _ := tableField(obj, "spec", "unschedulable").(bool); unschedulable {
+		status += ",SchedulingDisabled"
+	}
+	return status
+}
+
+// podMetricsColumn shows the Pod total get_pod_metrics computes, or sums
+// one resource over the Pod's containers
+func podMetricsColumn(name string) func(map[string]any) string {
+	return func(obj map[string]any) string {
+		if total, ok := tableField(obj, "total", name).(string); ok {
+			return total
+		}
+		containers, _ := obj["containers"].([]any)
+		total := resource.Quantity{}
+		for _, c := range containers {
+			if container, ok := c.(map[string]any); ok {
+				if q, ok := usageQuantity(container, name); ok {
+					total.Add(q)
+				}
+			}
+		}
+		return formatUsage(name, total)
+	}
+}
+
+func usageColumn(name string) func(map[string]any) string {
+	return func(obj map[string]any) string {
+		q, ok := usageQuantity(obj, name)
+		if !ok {
+			return tableNone
+		}
+		return formatUsage(name, q)
+	}
+}
+
+func usageQuantity(obj map[string]any, name string) (resource.Quantity, bool) {
+	raw, ok := tableField(obj, "usage", name).(string)
+	if !ok {
+		return resource.Quantity{}, false
+	}
+	q, err := resource.ParseQuantity(raw)
+	if err != nil {
+		return resource.Quantity{}, false
+	}
+	return q, true
+}
+
+// formatUsage renders CPU in millicores and memory in MiB, as 'kubectl top'
+func formatUsage(name string, q resource.Quantity) string {
+	if name == "cpu" {
+		return fmt.Sprintf("%dm", q.MilliValue())
+	}
+	return fmt.Sprintf("%dMi", q.Value()/(1024*1024))
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestListResources_OutputFormat(t *testing.T) {
+	running := fakePod("default", "web-1", nil)
+	running.Status = corev1.PodStatus{
+		Phase:             corev1.PodRunning,
+		ContainerStatuses: []corev1.ContainerStatus{{Name: "app", Ready: true, RestartCount: 2}},
+	}
+	crashing := fakePod("other", "web-2", nil)
+	crashing.Status = corev1.PodStatus{
+		Phase: corev1.PodRunning,
+		ContainerStatuses: []corev1.ContainerStatus{{
+			Name:  "app",
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		}},
+	}
+	e := newFakeEnv(t, running, crashing)
+
+	list := func(extra map[string]any) (string, bool) {
+		t.Helper()
+		args := map[string]any{"version": "v1", "resource": "pods"}
+		for k, v := range extra {
+			args[k] = v
+		}
+		res, err := e.manager.handleListResources(context.Background(), makeRequest(args))
+		if err != nil {
+			t.Fatalf("go-error: %v", err)
+		}
+		return firstText(res)
+	}
+
+	out, isErr := list(map[string]any{"output_format": "table"})
+	if isErr {
+		t.Fatalf("table: unexpected error: %s", out)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected a header and 2 rows, got:\n%s", out)
+	}
+	if got := strings.Fields(lines[0]); strings.Join(got, " ") != "NAMESPACE NAME READY STATUS RESTARTS AGE" {
+		t.Fatalf("unexpected header %v", got)
+	}
+	requireContains(t, lines[1], "web-1   1/1     Running", "expected the running pod row")
+	requireContains(t, lines[2], "CrashLoopBackOff", "expected the waiting reason as status")
+
+	out, isErr = list(map[string]any{"output_format": "json", "jq_expressions": []any{"[.items[].metadata.name]"}})
+	if isErr {
+		t.Fatalf("json: unexpected error: %s", out)
+	}
+	var names []string
+	if err := json.Unmarshal([]byte(out), &names); err != nil || strings.Join(names, ",") != "web-1,web-2" {
+		t.Fatalf("expected a JSON array of names, got %q (%v)", out, err)
+	}
+
+	out, isErr = list(map[string]any{"output_format": "table", "yq_expressions": []any{".items"}})
+	if !isErr {
+		t.Fatalf("expected table with yq_expressions to fail, got:\n%s", out)
+	}
+	requireContains(t, out, "cannot be combined", "expected the combination error")
+
+	out, isErr = list(map[string]any{"output_format": "xml"})
+	if !isErr {
+		t.Fatalf("expected an unknown format to fail, got:\n%s", out)
+	}
+	requireContains(t, out, "output_format must be one of", "expected the format error")
+}
+
+func TestRenderTable(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		kind string
+		want string
+	}{
+		{
+			name: "pod metrics summed over containers",
+			yaml: `items:
+- metadata: {name: web, namespace: default}
+  containers:
+  - {name: app, usage: {cpu: 150m, memory: 64Mi}}
+  - {name: sidecar, usage: {cpu: "50000000n", memory: 16Mi}}`,
+			kind: "PodMetrics",
+			want: "NAME   CPU    MEMORY\nweb    200m   80Mi\n",
+		},
+		{
+			name: "unknown kind falls back to name and age",
+			yaml: `kind: Widget
+metadata: {name: w1}`,
+			want: "NAME   AGE\nw1     <none>\n",
+		},
+		{
+			name: "flat rows use their keys, name first",
+			yaml: `- {name: default, status: Active, allowed: true}`,
+			want: "NAME      ALLOWED   STATUS\ndefault   true      Active\n",
+		},
+		{
+			name: "empty list",
+			yaml: "kind: PodList\nitems: []",
+			want: "No resources found.",
+		},
+		{
+			name: "continue token is surfaced",
+			yaml: `kind: ConfigMapList
+metadata: {continue: abc}
+items:
+- metadata: {name: cm}`,
+			want: "NAME   AGE\ncm     <none>\n\nMore items available: pass continue_token=\"abc\" to fetch the next page.\n",
+		},
+	}
+
+	e := newFakeEnv(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := e.manager.formatOutput(tt.yaml, map[string]any{"output_format": "table"}, tt.kind)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out != tt.want {
+				t.Fatalf("unexpected table:\n%q\nwant:\n%q", out, tt.want)
+			}
+		})
+	}
+}
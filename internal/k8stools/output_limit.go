@@ -0,0 +1,107 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// defaultMaxOutputBytes caps a tool's output when
+	// 'kubernetes.tools.max_output_bytes' is unset
+	defaultMaxOutputBytes = 1 << 20 // 1 MiB
+
+	// maxOutputBytesCeiling is the largest per-call 'max_output_bytes'
+	maxOutputBytesCeiling = 16 << 20 // 16 MiB
+)
+
+// maxOutputBytes returns the configured output cap; 0 means no cap
+func (m *Manager) maxOutputBytes() int {
+	switch limit := m.currentConfig().Kubernetes.Tools.MaxOutputBytes; {
+	case limit > 0:
+		return limit
+	case limit < 0:
+		return 0
+	}
+	return defaultMaxOutputBytes
+}
+
+// maxOutputBytesParam is the 'max_output_bytes' parameter every tool takes.
+// addTool and addWaitingTool add it, so tools do not declare it themselves.
+func maxOutputBytesParam() mcp.ToolOption {
+	return mcp.WithNumber("max_output_bytes", mcp.Description(fmt.Sprintf(
+		"Cap on the size of this call's output, overriding the server's kubernetes.tools.max_output_bytes. Integer 1..%d. Longer output is cut at a line boundary with a truncation marker; prefer narrowing with yq_expressions or selectors over raising it.",
+		maxOutputBytesCeiling)))
+}
+
+// withOutputLimit truncates every text content of the result to the
+// call's 'max_output_bytes' or the configured cap, so no single call can
+// flood the client.
+func (m *Manager) withOutputLimit(handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		limit := m.maxOutputBytes()
+		if v, ok := request.GetArguments()["max_output_bytes"].(float64); ok {
+			if v < 1 || v > maxOutputBytesCeiling {
+				return errorResult(fmt.Errorf("max_output_bytes must be between 1 and %d, got %v", maxOutputBytesCeiling, v)), nil
+			}
+			limit = int(v)
+		}
+
+		result, err := handler(ctx, request)
+		if err != nil || result == nil || limit == 0 {
+			return result, err
+		}
+		for i, content := range result.Content {
+			if text, ok := content.(mcp.TextContent); ok {
+				text.Text = truncateOutput(text.Text, limit)
+				result.Content[i] = text
+			}
+		}
+		return result, nil
+	}
+}
+
+// truncateOutput cuts text longer than limit bytes at the last line break
+// within the limit (or the last whole rune when a single line is longer)
+// and appends a marker with the sizes involved.
+func truncateOutput(text string, limit int) string {
+	if len(text) <= limit {
+		return text
+	}
+
+	kept := text[:limit]
+	if i := strings.LastIndexByte(kept, '\n'); i >= 0 {
+		kept = kept[:i+1]
+	} else {
+		for len(kept) > 0 && !utf8.ValidString(kept) {
+			kept = kept[:len(kept)-1]
+		}
+	}
+
+	separator := ""
+	if !strings.HasSuffix(kept, "\n") {
+		separator = "\n"
+	}
+	return fmt.Sprintf("%s%s... [truncated %d of %d bytes; refine with yq_expressions or a selector]",
+		kept, separator, len(text)-len(kept), len(text))
+}
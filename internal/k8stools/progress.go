@@ -0,0 +1,96 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// progressNotifier sends one notification to the client of the current
+// request. It matches MCPServer.SendNotificationToClient so tests can
+// capture what would be sent.
+type progressNotifier func(ctx context.Context, method string, params map[string]any) error
+
+// progressReporter emits MCP progress notifications for a long-running
+// tool call, so clients can show live status ("2/5 contexts queried",
+// "waiting: 1/3 replicas updated") instead of a silent wait.
+//
+// It is a no-op when the client did not ask for progress (no
+// 'progressToken' in the request '_meta') or when there is no server in
+// the context, so handlers can report unconditionally. A nil reporter is
+// also valid.
+type progressReporter struct {
+	ctx    context.Context
+	token  mcp.ProgressToken
+	notify progressNotifier
+	logger *slog.Logger
+
+	mu          sync.Mutex
+	progress    float64
+	lastMessage string
+}
+
+// newProgressReporter builds the reporter of a tool call
+func (m *Manager) newProgressReporter(ctx context.Context, request mcp.CallToolRequest) *progressReporter {
+	p := &progressReporter{ctx: ctx, logger: m.logger}
+	if request.Params.Meta != nil {
+		p.token = request.Params.Meta.ProgressToken
+	}
+	if srv := server.ServerFromContext(ctx); srv != nil {
+		p.notify = srv.SendNotificationToClient
+	}
+	return p
+}
+
+// Report sends 'message' with 'done' out of 'total' units of work. A zero
+// total means the total is unknown. Progress never goes backwards, as the
+// spec requires, and a message identical to the previous one is not resent,
+// so polling loops can call Report on every iteration.
+func (p *progressReporter) Report(done, total float64, message string) {
+	if p == nil || p.token == nil || p.notify == nil {
+		return
+	}
+
+	p.mu.Lock()
+	if message == p.lastMessage {
+		p.mu.Unlock()
+		return
+	}
+	if done > p.progress {
+		p.progress = done
+	}
+	p.lastMessage = message
+	params := map[string]any{
+		"progressToken": p.token,
+		"progress":      p.progress,
+		"message":       message,
+	}
+	if total > 0 {
+		params["total"] = total
+	}
+	p.mu.Unlock()
+
+	// Progress is advisory: a client that went away must not fail the call
+	if err := p.notify(p.ctx, "notifications/progress", params); err != nil {
+		p.logger.Debug("progress notification not sent", "error", err)
+	}
+}
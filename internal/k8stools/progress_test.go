@@ -0,0 +1,125 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// progressRecorder captures the notifications a progressReporter sends
+type progressRecorder struct {
+	mu   sync.Mutex
+	sent []map[string]any
+}
+
+func (r *progressRecorder) reporter(t *testing.T, token mcp.ProgressToken) *progressReporter {
+	t.Helper()
+	e := newFakeEnv(t)
+	request := makeRequest(nil)
+	request.Params.Meta = &mcp.Meta{ProgressToken: token}
+	p := e.manager.newProgressReporter(context.Background(), request)
+	p.notify = func(_ context.Context, method string, params map[string]any) error {
+		if method != "notifications/progress" {
+			t.Errorf("unexpected method %q", method)
+		}
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.sent = append(r.sent, params)
+		return nil
+	}
+	return p
+}
+
+func (r *progressRecorder) messages() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]string, 0, len(r.sent))
+	for _, params := range r.sent {
+		out = append(out, params["message"].(string))
+	}
+	return out
+}
+
+func TestProgressReporter(t *testing.T) {
+	rec := &progressRecorder{}
+	p := rec.reporter(t, "tok-1")
+
+	p.Report(1, 3, "1/3 done")
+	p.Report(1, 3, "1/3 done") // duplicate, dropped
+	p.Report(0, 3, "retrying") // progress must not go backwards
+	p.Report(5, 0, "no total")
+
+	if len(rec.sent) != 3 {
+		t.Fatalf("expected 3 notifications, got %d: %v", len(rec.sent), rec.sent)
+	}
+	first := rec.sent[0]
+	if first["progressToken"] != "tok-1" || first["progress"] != float64(1) || first["total"] != float64(3) {
+		t.Fatalf("unexpected first notification: %v", first)
+	}
+	if rec.sent[1]["progress"] != float64(1) {
+		t.Fatalf("progress went backwards: %v", rec.sent[1])
+	}
+	if _, ok := rec.sent[2]["total"]; ok {
+		t.Fatalf("unknown total must be omitted: %v", rec.sent[2])
+	}
+}
+
+func TestProgressReporter_NoOp(t *testing.T) {
+	// No token: the client did not ask for progress
+	rec := &progressRecorder{}
+	rec.reporter(t, nil).Report(1, 2, "ignored")
+	if len(rec.sent) != 0 {
+		t.Fatalf("expected no notifications without a progress token, got %v", rec.sent)
+	}
+
+	// No server in the context and a nil reporter must not panic
+	e := newFakeEnv(t)
+	request := makeRequest(nil)
+	request.Params.Meta = &mcp.Meta{ProgressToken: "tok"}
+	e.manager.newProgressReporter(context.Background(), request).Report(1, 2, "ignored")
+	var nilReporter *progressReporter
+	nilReporter.Report(1, 2, "ignored")
+}
+
+func TestWaitForRollout_ReportsProgress(t *testing.T) {
+	interval := rolloutPollInterval
+	rolloutPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { rolloutPollInterval = interval })
+
+	deployment := rolledOut(fakeDeployment("default", "web", 3))
+	deployment.Status.UpdatedReplicas = 1
+	e := newFakeEnv(t, deployment)
+	gvr := gvrOf("apps", "v1", "deployments")
+
+	rec := &progressRecorder{}
+	_, err := waitForRollout(context.Background(), e.dynamic.Resource(gvr).Namespace("default"), "web", gvr,
+		deployment.Generation, 50*time.Millisecond, rec.reporter(t, 7))
+	if err == nil {
+		t.Fatalf("expected the rollout to time out")
+	}
+
+	messages := rec.messages()
+	if len(messages) != 1 || !strings.HasPrefix(messages[0], "waiting: 1/3 replicas updated") {
+		t.Fatalf("expected one deduplicated progress message, got %v", messages)
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+// serverInjectedAnnotations are written by kubectl or controllers rather than
+// by the user, so they never belong in a diff, a snapshot or an export.
+var serverInjectedAnnotations = map[string]bool{
+	// Added by 'kubectl apply'. It always represents the previous version,
+	// never the current one.
+	"kubectl.kubernetes.io/last-applied-configuration": true,
+	// Bumped by the Deployment controller on every rollout
+	"deployment.kubernetes.io/revision": true,
+	// Set by the DaemonSet controller
+	"deprecated.daemonset.template.generation": true,
+}
+
+// stripServerManagedFields returns a copy of the input without the fields
+// the API server adds or owns, leaving what a user would write in a
+// manifest. diff_manifest, snapshot_resource and export_resource all strip
+// through it, so they agree on what "server-managed" means. The input is
+// never modified: the maps it removes keys from (the top level, metadata,
+// its annotations, a Service or PVC spec) are copied, while every other
+// nested value is shared with the input and must not be mutated in place.
+func stripServerManagedFields(in map[string]any) map[string]any {
+	if in == nil {
+		return nil
+	}
+	out := make(map[string]any, len(in))
+	for k, v := range in {
+		out[k] = v
+	}
+	// Drop the entire status subtree — it's controller-owned, never relevant
+	// for what the user is about to apply.
+	delete(out, "status")
+
+	if md, ok := out["metadata"].(map[string]any); ok {
+		mdCopy := make(map[string]any, len(md))
+		for k, v := range md {
+			mdCopy[k] = v
+		}
+		// Server-managed metadata fields. Removing them on BOTH sides means
+		// they cannot show up as diffs.
+		for _, f := range []string{
+			"resourceVersion",
+			"uid",
+			"creationTimestamp",
+			"deletionTimestamp",
+			"deletionGracePeriodSeconds",
+			"generation",
+			"managedFields",
+			"selfLink",
+			"finalizers",
+			"ownerReferences",
+		} {
+			delete(mdCopy, f)
+		}
+		if ann, ok := mdCopy["annotations"].(map[string]any); ok {
+			annCopy := make(map[string]any, len(ann))
+			for k, v := range ann {
+				if serverInjectedAnnotations[k] {
+					continue
+				}
+				annCopy[k] = v
+			}
+			if len(annCopy) == 0 {
+				delete(mdCopy, "annotations")
+			} else {
+				mdCopy["annotations"] = annCopy
+			}
+		}
+		out["metadata"] = mdCopy
+	}
+
+	// Service: clusterIP / clusterIPs / ipFamilies are server-assigned the
+	// first time and immutable thereafter. Stripping them from current avoids
+	// "removed" diffs when the user submits a manifest without them.
+	kind, _ := out["kind"].(string)
+	if kind == "Service" {
+		if spec, ok := out["spec"].(map[string]any); ok {
+			specCopy := make(map[string]any, len(spec))
+			for k, v := range spec {
+				specCopy[k] = v
+			}
+			delete(specCopy, "clusterIP")
+			delete(specCopy, "clusterIPs")
+			delete(specCopy, "ipFamilies")
+			delete(specCopy, "ipFamilyPolicy")
+			out["spec"] = specCopy
+		}
+	}
+	if kind == "PersistentVolumeClaim" {
+		if spec, ok := out["spec"].(map[string]any); ok {
+			specCopy := make(map[string]any, len(spec))
+			for k, v := range spec {
+				specCopy[k] = v
+			}
+			delete(specCopy, "volumeName")
+			out["spec"] = specCopy
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,262 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"kubernetes-mcp/internal/kubernetes"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	// crdEstablishTimeout bounds the wait for the CRDs of a bundle to be served
+	crdEstablishTimeout = 30 * time.Second
+	// crdEstablishPollInterval is how often waitForCRDEstablished re-reads a CRD
+	crdEstablishPollInterval = time.Second
+)
+
+// manifestDocument is one document of a multi-document manifest
+type manifestDocument struct {
+	// index is the 1-based position in the manifest, used in the report
+	index int
+	obj   *unstructured.Unstructured
+}
+
+func (d manifestDocument) target() string {
+	return fmt.Sprintf("document %d: %s/%s", d.index, d.obj.GetKind(), d.obj.GetName())
+}
+
+func (d manifestDocument) isCRD() bool {
+	gvk := d.obj.GroupVersionKind()
+	return gvk.Group == crdGVR.Group && gvk.Kind == "CustomResourceDefinition"
+}
+
+// appliedDocument is a document applied by applyDocuments
+type appliedDocument struct {
+	doc     manifestDocument
+	applied *appliedObject
+}
+
+// applyManifestBundle applies a multi-document manifest and reports each
+// document.
+func (m *Manager) applyManifestBundle(ctx context.Context, request mcp.CallToolRequest, k8sContext, manifest, namespaceOverride string, dryRun bool) *mcp.CallToolResult {
+	docs, err := m.parseManifestBundle(manifest)
+	if err != nil {
+		return errorResult(err)
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err)
+	}
+
+	aggregate := NewAggregateResult()
+	progress := m.newProgressReporter(ctx, request)
+	for _, ad := range m.applyDocuments(ctx, request, "apply_manifest", k8sContext, client, docs, namespaceOverride, dryRun, aggregate, progress) {
+		aggregate.AddSuccess(ad.doc.target(), ad.summary())
+	}
+
+	return aggregate.ToolResult()
+}
+
+// parseManifestBundle splits a manifest into its documents, enforcing the
+// bulk operations limit.
+func (m *Manager) parseManifestBundle(manifest string) ([]manifestDocument, error) {
+	docs, err := splitManifestDocuments(manifest)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("manifest is empty")
+	}
+	if limit := m.bulkOperationsLimit(); len(docs) > limit {
+		return nil, fmt.Errorf("manifest has %d documents, more than the limit of %d per call; split it", len(docs), limit)
+	}
+	return docs, nil
+}
+
+// applyDocuments applies the documents of a bundle. CRDs go first and are
+// waited on until Established; the RESTMapper is then reset so the custom
+// resources later in the bundle resolve instead of failing with "no matches
+// for kind".
+//
+// CRD outcomes and every failure are recorded in 'aggregate'. The other
+// documents applied successfully are returned, in order, for the caller to
+// report. A dry run has no CRD to wait for: the CRDs are returned with the
+// rest.
+func (m *Manager) applyDocuments(ctx context.Context, request mcp.CallToolRequest, tool, k8sContext string, client *kubernetes.Client,
+	docs []manifestDocument, namespaceOverride string, dryRun bool, aggregate *AggregateResult, progress *progressReporter) []appliedDocument {
+
+	var crds, rest []manifestDocument
+	for _, doc := range docs {
+		if doc.isCRD() {
+			crds = append(crds, doc)
+		} else {
+			rest = append(rest, doc)
+		}
+	}
+
+	done := 0
+	apply := func(doc manifestDocument) (*appliedObject, bool) {
+		progress.Report(float64(done), float64(len(docs)),
+			fmt.Sprintf("%d/%d documents applied, applying %s/%s", done, len(docs), doc.obj.GetKind(), doc.obj.GetName()))
+		done++
+		if err := validateManifestObject(doc.obj); err != nil {
+			aggregate.AddError(doc.target(), err, "")
+			return nil, false
+		}
+		applied, err := m.applyObject(ctx, request, tool, k8sContext, client, doc.obj, namespaceOverride, dryRun)
+		if err != nil {
+			aggregate.AddError(doc.target(), err, "")
+			return nil, false
+		}
+		return applied, true
+	}
+
+	// Apply every CRD before waiting: the API server establishes them in
+	// parallel, so one deadline covers them all.
+	var appliedCRDs []appliedDocument
+	for _, doc := range crds {
+		if applied, ok := apply(doc); ok {
+			appliedCRDs = append(appliedCRDs, appliedDocument{doc: doc, applied: applied})
+		}
+	}
+	var applied []appliedDocument
+	if dryRun {
+		applied = appliedCRDs
+	} else if len(appliedCRDs) > 0 {
+		waitCtx, cancel := context.WithTimeout(ctx, crdEstablishTimeout)
+		for _, crd := range appliedCRDs {
+			if err := waitForCRDEstablished(waitCtx, client.DynamicClient, crd.doc.obj.GetName()); err != nil {
+				aggregate.AddError(crd.doc.target(), err, crd.applied.action)
+				continue
+			}
+			aggregate.AddSuccess(crd.doc.target(), crd.applied.action+", Established")
+		}
+		cancel()
+		resetRESTMapper(client)
+	}
+
+	for _, doc := range rest {
+		if obj, ok := apply(doc); ok {
+			applied = append(applied, appliedDocument{doc: doc, applied: obj})
+		}
+	}
+	return applied
+}
+
+// summary is the per-document line of the report, e.g. "created in
+// namespace default"
+func (d appliedDocument) summary() string {
+	action := d.applied.action
+	if d.applied.dryRun {
+		action += " (dry run)"
+	}
+	if d.applied.namespace == "" {
+		return action
+	}
+	return action + " in namespace " + d.applied.namespace
+}
+
+// splitManifestDocuments parses every non-empty document of a multi-document
+// manifest, keeping the order they were written in.
+func splitManifestDocuments(manifest string) ([]manifestDocument, error) {
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(strings.NewReader(manifest)))
+
+	var docs []manifestDocument
+	for index := 1; ; index++ {
+		raw, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return docs, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read document %d: %w", index, err)
+		}
+
+		// Decode through JSON so integers stay int64, as in objects read
+		// back from the API server; unstructured helpers reject float64.
+		data, err := yaml.YAMLToJSON(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse document %d: %w", index, err)
+		}
+		obj := &unstructured.Unstructured{}
+		if err := utiljson.Unmarshal(data, &obj.Object); err != nil {
+			return nil, fmt.Errorf("failed to parse document %d: %w", index, err)
+		}
+		if len(obj.Object) == 0 {
+			// Blank or comment-only document (e.g. a trailing '---')
+			index--
+			continue
+		}
+		docs = append(docs, manifestDocument{index: index, obj: obj})
+	}
+}
+
+// waitForCRDEstablished polls a CRD until its Established condition is True
+// or ctx is done.
+func waitForCRDEstablished(ctx context.Context, client dynamic.Interface, name string) error {
+	ticker := time.NewTicker(crdEstablishPollInterval)
+	defer ticker.Stop()
+
+	for {
+		crd, err := client.Resource(crdGVR).Get(ctx, name, metav1.GetOptions{})
+		if err == nil && crdConditionTrue(crd, "Established") {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if err != nil {
+				return fmt.Errorf("CRD %s was not Established within %s: %w", name, crdEstablishTimeout, err)
+			}
+			return fmt.Errorf("CRD %s was not Established within %s", name, crdEstablishTimeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// crdConditionTrue reports whether a CRD status condition is True
+func crdConditionTrue(crd *unstructured.Unstructured, conditionType string) bool {
+	conditions, _, _ := unstructured.NestedSlice(crd.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]any)
+		if ok && condition["type"] == conditionType {
+			return condition["status"] == "True"
+		}
+	}
+	return false
+}
+
+// resetRESTMapper drops the cached discovery so kinds served by CRDs created
+// moments ago resolve.
+func resetRESTMapper(client *kubernetes.Client) {
+	client.ResetDiscovery()
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// discoveringRESTMapper only learns the kinds in 'pending' when Reset is
+// called, like a discovery cache that has not seen a new CRD yet.
+type discoveringRESTMapper struct {
+	*meta.DefaultRESTMapper
+	pending []schema.GroupVersionKind
+}
+
+func (r *discoveringRESTMapper) Reset() {
+	for _, gvk := range r.pending {
+		r.Add(gvk, meta.RESTScopeNamespace)
+	}
+	r.pending = nil
+}
+
+const widgetBundle = `
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: first
+  namespace: default
+spec:
+  size: 3
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+spec:
+  group: example.com
+  names: {kind: Widget, plural: widgets}
+  scope: Namespaced
+status:
+  conditions:
+  - type: Established
+    status: "True"
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: widget-config
+  namespace: default
+`
+
+func TestApplyManifest_BundleAppliesCRDsFirst(t *testing.T) {
+	e := newFakeEnv(t)
+	mapper := &discoveringRESTMapper{
+		DefaultRESTMapper: newFakeRESTMapper().(fakeRESTMapper).DefaultRESTMapper,
+		pending:           []schema.GroupVersionKind{{Group: "example.com", Version: "v1", Kind: "Widget"}},
+	}
+	e.provider.client.RESTMapper = mapper
+
+	res, err := e.manager.handleApplyManifest(context.Background(), makeRequest(map[string]any{
+		"manifest": widgetBundle,
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "apply_manifest bundle")
+	requireContains(t, out, "3 succeeded, 0 failed", "expected every document applied")
+
+	// The CRD (document 2) is reported first, before the Widget that needs it
+	crd := strings.Index(out, "[OK]   document 2: CustomResourceDefinition/widgets.example.com")
+	widget := strings.Index(out, "[OK]   document 1: Widget/first")
+	if crd < 0 || widget < 0 || crd > widget {
+		t.Fatalf("expected the CRD applied before its instance:\n%s", out)
+	}
+	requireContains(t, out, "created, Established", "expected the CRD wait to be reported")
+
+	widgetGVR := schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+	if _, err := e.dynamic.Resource(widgetGVR).Namespace("default").Get(context.Background(), "first", metav1.GetOptions{}); err != nil {
+		t.Fatalf("expected the Widget to be created: %v", err)
+	}
+}
+
+func TestApplyManifest_BundlePartialFailure(t *testing.T) {
+	e := newFakeEnv(t)
+
+	manifest := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ok
+  namespace: default
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  namespace: default
+---
+# trailing comment-only document
+`
+	res, err := e.manager.handleApplyManifest(context.Background(), makeRequest(map[string]any{
+		"manifest": manifest,
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "apply_manifest partial bundle")
+	requireContains(t, out, "1 succeeded, 1 failed", "expected a partial result")
+	requireContains(t, out, "missing 'metadata.name'", "expected the invalid document reported")
+}
+
+func TestApplyManifest_BundleCRDNotEstablished(t *testing.T) {
+	interval, timeout := crdEstablishPollInterval, crdEstablishTimeout
+	crdEstablishPollInterval, crdEstablishTimeout = 10*time.Millisecond, 100*time.Millisecond
+	t.Cleanup(func() { crdEstablishPollInterval, crdEstablishTimeout = interval, timeout })
+
+	e := newFakeEnv(t)
+
+	manifest := `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: gadgets.example.com
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: gadget-config
+  namespace: default
+`
+	res, err := e.manager.handleApplyManifest(context.Background(), makeRequest(map[string]any{
+		"manifest": manifest,
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "apply_manifest bundle with unestablished CRD")
+	requireContains(t, out, "1 succeeded, 1 failed", "expected the CRD wait to fail alone")
+	requireContains(t, out, "CRD gadgets.example.com was not Established", "expected the wait error")
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	applyWaitDefaultTimeout = 300 * time.Second
+	applyWaitMaxTimeout     = 600 * time.Second
+)
+
+func (m *Manager) registerApplyAndWait() {
+	tool := mcp.NewTool(m.toolName("apply_and_wait"),
+		mcp.WithDescription(`Apply a manifest, then wait until the workloads in it are rolled out.
+
+Does what 'apply_manifest' does (create-or-update, multi-document YAML,
+CRDs first) and then, for every Deployment, StatefulSet and DaemonSet
+among the documents, waits until the rollout completes with the same
+criteria as 'get_rollout_status': every desired Pod updated and available,
+no old Pods left.
+
+The result reports each document: applied objects that are not workloads
+succeed as soon as they are applied; workloads succeed once ready and fail
+with their rollout status when 'timeout_seconds' elapses first. The
+timeout is shared by all the workloads of the call.`),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("manifest", mcp.Required(), mcp.Description("Kubernetes manifest(s) in YAML or JSON, one or more documents separated by '---'. Each document must include 'apiVersion', 'kind' and 'metadata.name'.")),
+		mcp.WithString("namespace", mcp.Description("Namespace override. If set, takes precedence over 'metadata.namespace' from the manifest. Ignored for cluster-scoped kinds.")),
+		mcp.WithNumber("timeout_seconds", mcp.Description("Maximum time to wait for the workloads to be ready. Integer 1..600. Defaults to 300.")),
+	)
+	m.addWaitingTool(tool, m.handleApplyAndWait, applyWaitMaxTimeout+crdEstablishTimeout)
+}
+
+func (m *Manager) handleApplyAndWait(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	manifest, _ := args["manifest"].(string)
+	namespaceOverride, _ := args["namespace"].(string)
+
+	timeout := applyWaitDefaultTimeout
+	if v, ok := args["timeout_seconds"].(float64); ok {
+		if v < 1 || v > applyWaitMaxTimeout.Seconds() {
+			return errorResult(fmt.Errorf("timeout_seconds must be between 1 and %d, got %v", int(applyWaitMaxTimeout.Seconds()), v)), nil
+		}
+		timeout = time.Duration(v) * time.Second
+	}
+
+	docs, err := m.parseManifestBundle(manifest)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	aggregate := NewAggregateResult()
+	progress := m.newProgressReporter(ctx, request)
+	applied := m.applyDocuments(ctx, request, "apply_and_wait", k8sContext, client, docs, namespaceOverride, false, aggregate, progress)
+
+	// The deadline starts once everything is applied and is shared, so the
+	// call as a whole never waits longer than 'timeout_seconds'.
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for _, ad := range applied {
+		gvr := ad.applied.gvr
+		if gvr.Group != "apps" || !rolloutSupportedResource(gvr.Resource) || ad.applied.namespace == "" {
+			aggregate.AddSuccess(ad.doc.target(), ad.summary())
+			continue
+		}
+
+		name := ad.applied.object.GetName()
+		nsClient := client.DynamicClient.Resource(gvr).Namespace(ad.applied.namespace)
+		final, err := waitForRollout(waitCtx, nsClient, name, gvr, ad.applied.object.GetGeneration(), timeout, progress)
+		if err != nil {
+			aggregate.AddError(ad.doc.target(), err, ad.summary())
+			continue
+		}
+
+		p := readRolloutProgress(final, gvr)
+		aggregate.AddSuccess(ad.doc.target(), fmt.Sprintf("%s, ready: %d/%d replicas updated and available",
+			ad.summary(), p.available, p.desired))
+	}
+
+	return aggregate.ToolResult(), nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// webBundle is a Deployment whose status is 'readyReplicas' out of 3, plus a
+// ConfigMap. The fake client keeps the status it is given.
+func webBundle(readyReplicas string) string {
+	return `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web-config
+  namespace: default
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: default
+spec:
+  replicas: 3
+  selector: {matchLabels: {app: web}}
+  template:
+    metadata: {labels: {app: web}}
+    spec:
+      containers: [{name: web, image: nginx}]
+status:
+  replicas: 3
+  updatedReplicas: 3
+  readyReplicas: ` + readyReplicas + `
+  availableReplicas: ` + readyReplicas + `
+`
+}
+
+func TestApplyAndWait(t *testing.T) {
+	e := newFakeEnv(t)
+
+	res, err := e.manager.handleApplyAndWait(context.Background(), makeRequest(map[string]any{
+		"manifest": webBundle("3"),
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "apply_and_wait")
+	requireContains(t, out, "2 succeeded, 0 failed", "expected both documents to succeed")
+	requireContains(t, out, "created in namespace default, ready: 3/3 replicas", "expected workload readiness")
+}
+
+func TestApplyAndWait_Timeout(t *testing.T) {
+	interval := rolloutPollInterval
+	rolloutPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { rolloutPollInterval = interval })
+
+	e := newFakeEnv(t)
+
+	res, err := e.manager.handleApplyAndWait(context.Background(), makeRequest(map[string]any{
+		"manifest":        webBundle("1"),
+		"timeout_seconds": float64(1),
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "apply_and_wait timeout")
+	requireContains(t, out, "1 succeeded, 1 failed", "expected only the workload to fail")
+	requireContains(t, out, "[FAIL] document 2: Deployment/web: the rollout did not complete within 1s", "expected the timeout")
+	requireContains(t, out, "[OK]   document 1: ConfigMap/web-config", "expected the ConfigMap to be applied")
+}
+
+func TestApplyAndWait_InvalidTimeout(t *testing.T) {
+	e := newFakeEnv(t)
+
+	res, err := e.manager.handleApplyAndWait(context.Background(), makeRequest(map[string]any{
+		"manifest":        webBundle("3"),
+		"timeout_seconds": float64(601),
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	text := expectErr(t, res, "apply_and_wait timeout out of range")
+	requireContains(t, text, "timeout_seconds must be between 1 and 600", "unexpected error text")
+}
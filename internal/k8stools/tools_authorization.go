@@ -0,0 +1,202 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"fmt"
+
+	"kubernetes-mcp/api"
+	"kubernetes-mcp/internal/authorization"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func (m *Manager) registerTestAuthorization() {
+	tool := mcp.NewTool(m.toolName("test_authorization"),
+		mcp.WithDescription(`Dry-run the MCP server's own authorization policies against a synthetic
+request: "would a caller with these claims be allowed to run this tool
+here?".
+
+Nothing is executed. Returns the final 'decision' (allow / deny) with the
+'reason', the policies whose match expression was true, the policy that
+decided, and every rule of those policies marked with whether it applies
+to the request.
+
+Use it to validate a policy change without a real token. This checks the
+MCP layer only; the cluster's RBAC is checked with 'check_permission'.`),
+		mcp.WithString("context", mcp.Description("Context of the synthetic request, also used to resolve whether the resource is namespaced. If empty, uses the currently active MCP context.")),
+		mcp.WithObject("payload", mcp.Description("Claims of the synthetic caller, as the JWT or API key payload would carry them. Example: {\"sub\": \"jane\", \"groups\": [\"developers\"]}. Omit for an anonymous request.")),
+		mcp.WithString("tool", mcp.Required(), mcp.Description("Tool name to test, without the server prefix. Example: 'delete_resource'.")),
+		mcp.WithString("namespace", mcp.Description("Namespace of the synthetic request. Empty for cluster-scoped requests.")),
+		mcp.WithString("group", mcp.Description("API group of the resource. Empty string \"\" for the core API.")),
+		mcp.WithString("version", mcp.Description("API version of the resource. Example: 'v1'.")),
+		mcp.WithString("resource", mcp.Description("Resource in the API sense: lowercase plural ('pods', 'deployments'). Omit for tools that act on a virtual resource, which is then filled in.")),
+		mcp.WithString("name", mcp.Description("Name of the resource instance, if any.")),
+	)
+	m.addTool(tool, m.handleTestAuthorization)
+}
+
+func (m *Manager) handleTestAuthorization(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	testedTool, _ := args["tool"].(string)
+	if testedTool == "" {
+		return errorResult(fmt.Errorf("tool is required")), nil
+	}
+	payload, _ := args["payload"].(map[string]any)
+	namespace, _ := args["namespace"].(string)
+	group, _ := args["group"].(string)
+	version, _ := args["version"].(string)
+	resource, _ := args["resource"].(string)
+	name, _ := args["name"].(string)
+
+	// Check authorization (virtual resource: _/policies)
+	if err := m.checkAuthorization(request, "test_authorization", k8sContext, "", authorization.ResourceInfo{
+		Group:    authorization.VirtualResourceGroup,
+		Resource: authorization.VirtualResourcePolicies,
+	}); err != nil {
+		return errorResult(err), nil
+	}
+
+	authz := m.currentAuthz()
+	if authz == nil {
+		return errorResult(fmt.Errorf("no authorization policies are configured: every request is allowed")), nil
+	}
+
+	info := authorization.ResourceInfo{Group: group, Version: version, Resource: resource, Name: name}
+	info.Namespaced = m.resolveNamespaced(k8sContext, info)
+	req := authorization.AuthzRequest{
+		Payload:   payload,
+		Tool:      testedTool,
+		Context:   k8sContext,
+		Namespace: namespace,
+		Resource:  info,
+	}
+
+	decision, rules, err := authz.Explain(req)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	effective := authorization.GetResourceForTool(testedTool, info)
+	result := map[string]any{
+		"request": map[string]any{
+			"tool":      testedTool,
+			"verbs":     authorization.VerbsForTool(testedTool),
+			"context":   k8sContext,
+			"namespace": namespace,
+			"resource": map[string]any{
+				"group":      effective.Group,
+				"version":    effective.Version,
+				"resource":   effective.Resource,
+				"name":       effective.Name,
+				"namespaced": effective.Namespaced,
+			},
+			"anonymous": len(payload) == 0,
+		},
+		"decision":         "deny",
+		"reason":           m.authorizationReason(req, decision),
+		"matched_policies": decision.MatchedPolicies,
+	}
+	if decision.Allowed {
+		result["decision"] = "allow"
+	}
+	if decision.DecidingPolicy != "" {
+		result["deciding_policy"] = decision.DecidingPolicy
+	}
+	if _, known := authorization.ToolVerbs[testedTool]; !known {
+		result["warning"] = fmt.Sprintf("tool %q has no verbs: either it does not exist or only rules naming it in 'tools' can match it", testedTool)
+	}
+
+	ruleResults := make([]map[string]any, 0, len(rules))
+	for _, r := range rules {
+		entry := map[string]any{
+			"policy":  r.Policy,
+			"applies": r.Applies,
+			"rule":    ruleSummary(r.Rule),
+		}
+		if len(r.Rule.LabelPrefixes)+len(r.Rule.AnnotationPrefixes) > 0 {
+			entry["note"] = "scoped to label/annotation keys: it does not allow or deny the request as a whole"
+		}
+		ruleResults = append(ruleResults, entry)
+	}
+	result["rules"] = ruleResults
+
+	yamlOutput, err := objectToYAML(result)
+	if err != nil {
+		return errorResult(err), nil
+	}
+	return successResult(yamlOutput), nil
+}
+
+// authorizationReason explains a decision in one sentence
+func (m *Manager) authorizationReason(req authorization.AuthzRequest, decision authorization.Decision) string {
+	switch {
+	case len(req.Payload) == 0 && !m.currentConfig().Authorization.AllowAnonymous:
+		return "the request has no payload and 'authorization.allow_anonymous' is false"
+	case len(decision.MatchedPolicies) == 0:
+		return "no policy's match expression is true for this request"
+	case decision.Allowed:
+		return fmt.Sprintf("an allow rule of policy %q applies and no deny rule does", decision.DecidingPolicy)
+	case decision.DecidingPolicy != "":
+		return fmt.Sprintf("a deny rule of policy %q applies (deny always wins)", decision.DecidingPolicy)
+	default:
+		return "no allow rule of the matched policies applies (default deny)"
+	}
+}
+
+// ruleSummary renders the set fields of a rule with their config names
+func ruleSummary(rule api.AuthorizationRule) map[string]any {
+	summary := map[string]any{"effect": string(rule.Effect)}
+	lists := map[string][]string{
+		"tools":               rule.Tools,
+		"contexts":            rule.Contexts,
+		"verbs":               rule.Verbs,
+		"namespaces":          rule.Namespaces,
+		"label_prefixes":      rule.LabelPrefixes,
+		"annotation_prefixes": rule.AnnotationPrefixes,
+	}
+	for key, values := range lists {
+		if len(values) > 0 {
+			summary[key] = values
+		}
+	}
+
+	if len(rule.Resources) > 0 {
+		resources := make([]map[string]any, 0, len(rule.Resources))
+		for _, r := range rule.Resources {
+			entry := map[string]any{}
+			resourceLists := map[string][]string{
+				"groups":     r.Groups,
+				"versions":   r.Versions,
+				"resources":  r.Resources,
+				"namespaces": r.Namespaces,
+				"names":      r.Names,
+			}
+			for key, values := range resourceLists {
+				if values != nil {
+					entry[key] = values
+				}
+			}
+			resources = append(resources, entry)
+		}
+		summary["resources"] = resources
+	}
+	return summary
+}
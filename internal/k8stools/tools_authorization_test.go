@@ -0,0 +1,104 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"kubernetes-mcp/api"
+	"kubernetes-mcp/internal/authorization"
+	"kubernetes-mcp/internal/middlewares"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestTestAuthorization(t *testing.T) {
+	e := newFakeEnv(t)
+
+	call := func(caller map[string]any, args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		request := makeRequest(args)
+		if caller != nil {
+			raw, _ := json.Marshal(caller)
+			request.Header = http.Header{}
+			request.Header.Set(middlewares.AuthPayloadHeader, hex.EncodeToString(raw))
+		}
+		res, err := e.manager.handleTestAuthorization(context.Background(), request)
+		if err != nil {
+			t.Fatalf("go-error: %v", err)
+		}
+		return res
+	}
+	admin := map[string]any{"sub": "root", "groups": []any{"admins"}}
+	dev := map[string]any{"sub": "jane", "groups": []any{"developers"}}
+
+	expectErr(t, call(admin, map[string]any{"tool": "get_resource"}), "no policies")
+
+	authz, err := authorization.NewEvaluator(&api.AuthorizationConfig{
+		Policies: []api.AuthorizationPolicy{
+			{
+				Name:  "admins",
+				Match: api.MatchConfig{Expression: `"admins" in payload.groups`},
+				Rules: []api.AuthorizationRule{{Effect: api.RuleEffectAllow}},
+			},
+			{
+				Name:  "developers",
+				Match: api.MatchConfig{Expression: `"developers" in payload.groups`},
+				Rules: []api.AuthorizationRule{
+					{Effect: api.RuleEffectAllow, Verbs: []string{"get", "list"}},
+					{Effect: api.RuleEffectDeny, Resources: []api.ResourceRule{{Groups: []string{""}, Resources: []string{"secrets"}}}},
+					{Effect: api.RuleEffectDeny, Tools: []string{"test_authorization"}},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewEvaluator: %v", err)
+	}
+	e.manager.authz = authz
+
+	// Only admins may dry-run policies
+	requireContains(t, expectErr(t, call(dev, map[string]any{"tool": "get_resource"}), "developer caller"),
+		"access denied", "expected the tool itself to be authorized")
+
+	out := expectOK(t, call(admin, map[string]any{
+		"payload": dev, "tool": "get_resource", "namespace": "shop", "version": "v1", "resource": "pods", "name": "web-1",
+	}), "developer reads a pod")
+	requireContains(t, out, "decision: allow", "expected allow")
+	requireContains(t, out, "deciding_policy: developers", "expected the deciding policy")
+	requireContains(t, out, "namespaced: true", "expected the resource scope to be resolved")
+
+	out = expectOK(t, call(admin, map[string]any{
+		"payload": dev, "tool": "get_resource", "namespace": "shop", "version": "v1", "resource": "secrets",
+	}), "developer reads a secret")
+	requireContains(t, out, "decision: deny", "expected deny")
+	requireContains(t, out, `a deny rule of policy "developers" applies`, "expected the deny reason")
+
+	out = expectOK(t, call(admin, map[string]any{"payload": dev, "tool": "delete_resource", "version": "v1", "resource": "pods"}), "developer deletes")
+	requireContains(t, out, "default deny", "expected the default deny reason")
+	requireContains(t, out, "applies: false", "expected the non-applying rules to be listed")
+
+	out = expectOK(t, call(admin, map[string]any{"tool": "get_resource"}), "anonymous")
+	requireContains(t, out, "allow_anonymous", "expected the anonymous reason")
+
+	out = expectOK(t, call(admin, map[string]any{"payload": dev, "tool": "get_resources"}), "typo")
+	requireContains(t, out, "warning:", "expected a warning for an unknown tool")
+}
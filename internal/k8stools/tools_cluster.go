@@ -19,6 +19,7 @@ package k8stools
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"kubernetes-mcp/internal/authorization"
 
@@ -41,14 +42,15 @@ resource", run this tool first to confirm the GVR exists.`),
 		mcp.WithString("api_group", mcp.Description("Restrict the listing to a single API group. Examples: 'apps', 'networking.k8s.io', 'storage.k8s.io'. Pass an empty string to match the core API only ('pods', 'configmaps', ...). Omit the parameter entirely to list every group.")),
 		mcp.WithBoolean("namespaced", mcp.Description("If set, return only namespaced (true) or only cluster-scoped (false) resources. Omit for no filtering.")),
 		mcp.WithArray("yq_expressions", mcp.Description("Optional yq expressions applied to the YAML output (a top-level array, NOT a List object — use '.[]'). Examples: '.[].name' (all plural names), '.[] | select(.namespaced == true) | .name' (namespaced names), 'map(select(.group == \"apps\"))' (apps group only).")),
+		jqExpressionsParam(),
 	)
-	m.mcpServer.AddTool(tool, m.handleListAPIResources)
+	m.addTool(tool, m.handleListAPIResources)
 }
 
 func (m *Manager) handleListAPIResources(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
 
-	k8sContext := m.getContextParam(args)
+	k8sContext := m.getContextParam(ctx, args)
 	apiGroup, hasAPIGroup := args["api_group"].(string)
 	namespacedFilter, hasNamespacedFilter := args["namespaced"].(bool)
 
@@ -128,7 +130,7 @@ func (m *Manager) handleListAPIResources(ctx context.Context, request mcp.CallTo
 	}
 
 	// Apply yq expressions
-	finalOutput, err := m.applyYQExpressions(yamlOutput, args)
+	finalOutput, err := m.applyOutputFilters(yamlOutput, args)
 	if err != nil {
 		return errorResult(err), nil
 	}
@@ -141,18 +143,25 @@ func (m *Manager) registerListAPIVersions() {
 		mcp.WithDescription(`List the API groups served by the cluster and the versions available
 within each group, including which version is the preferred one.
 
+Each item carries 'group' (empty for the core API), 'preferred_version'
+(the version to use unless you have a reason not to), 'group_version'
+(ready for a manifest's 'apiVersion') and every served 'versions' entry.
+Set 'preferred_only=true' to drop the full version list.
+
 Use this when you don't know whether a CRD ships 'v1', 'v1beta1', or both.
 For the actual resources within a group prefer 'list_api_resources'.`),
 		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
-		mcp.WithArray("yq_expressions", mcp.Description("Optional yq expressions applied to the APIGroupList YAML. Examples: '.groups[].name' (group names), '.groups[] | select(.name == \"apps\") | .preferredVersion.version' (preferred version of apps).")),
+		mcp.WithBoolean("preferred_only", mcp.Description("Return only the preferred version of each group. Defaults to false.")),
+		mcp.WithArray("yq_expressions", mcp.Description("Optional yq expressions applied to the YAML output. The output is a map with an 'items' list. Examples: '.items[].group' (group names), '.items[] | select(.group == \"apps\") | .preferred_version' (preferred version of apps).")),
+		jqExpressionsParam(),
 	)
-	m.mcpServer.AddTool(tool, m.handleListAPIVersions)
+	m.addTool(tool, m.handleListAPIVersions)
 }
 
 func (m *Manager) handleListAPIVersions(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
 
-	k8sContext := m.getContextParam(args)
+	k8sContext := m.getContextParam(ctx, args)
 
 	// Check authorization (virtual resource: _/APIDiscovery)
 	if err := m.checkAuthorization(request, "list_api_versions", k8sContext, "", authorization.ResourceInfo{
@@ -167,18 +176,28 @@ func (m *Manager) handleListAPIVersions(ctx context.Context, request mcp.CallToo
 		return errorResult(err), nil
 	}
 
+	preferredOnly, _ := args["preferred_only"].(bool)
+
 	groups, err := client.Clientset.Discovery().ServerGroups()
 	if err != nil {
 		return errorResult(err), nil
 	}
 
-	yamlOutput, err := objectToYAML(groups)
+	items := make([]map[string]any, 0, len(groups.Groups))
+	for _, group := range groups.Groups {
+		items = append(items, summarizeAPIGroup(group, preferredOnly))
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i]["group"].(string) < items[j]["group"].(string)
+	})
+
+	yamlOutput, err := objectToYAML(map[string]any{"items": items})
 	if err != nil {
 		return errorResult(err), nil
 	}
 
 	// Apply yq expressions
-	finalOutput, err := m.applyYQExpressions(yamlOutput, args)
+	finalOutput, err := m.applyOutputFilters(yamlOutput, args)
 	if err != nil {
 		return errorResult(err), nil
 	}
@@ -186,6 +205,31 @@ func (m *Manager) handleListAPIVersions(ctx context.Context, request mcp.CallToo
 	return successResult(finalOutput), nil
 }
 
+// summarizeAPIGroup flattens an APIGroup into its preferred version and the
+// list of served versions.
+func summarizeAPIGroup(group metav1.APIGroup, preferredOnly bool) map[string]any {
+	preferred := group.PreferredVersion
+	if preferred.Version == "" && len(group.Versions) > 0 {
+		preferred = group.Versions[0]
+	}
+
+	summary := map[string]any{
+		"group":             group.Name,
+		"preferred_version": preferred.Version,
+		"group_version":     preferred.GroupVersion,
+	}
+	if preferredOnly {
+		return summary
+	}
+
+	versions := make([]string, 0, len(group.Versions))
+	for _, v := range group.Versions {
+		versions = append(versions, v.Version)
+	}
+	summary["versions"] = versions
+	return summary
+}
+
 func (m *Manager) registerGetClusterInfo() {
 	tool := mcp.NewTool(m.toolName("get_cluster_info"),
 		mcp.WithDescription(`Return a small summary of the targeted cluster: server version, API host,
@@ -196,13 +240,13 @@ Useful as a smoke test before running anything destructive, and to confirm
 which physical cluster a context points at.`),
 		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
 	)
-	m.mcpServer.AddTool(tool, m.handleGetClusterInfo)
+	m.addTool(tool, m.handleGetClusterInfo)
 }
 
 func (m *Manager) handleGetClusterInfo(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
 
-	k8sContext := m.getContextParam(args)
+	k8sContext := m.getContextParam(ctx, args)
 
 	// Check authorization (virtual resource: _/ClusterInfo)
 	if err := m.checkAuthorization(request, "get_cluster_info", k8sContext, "", authorization.ResourceInfo{
@@ -281,14 +325,16 @@ with resource='namespaces'.`),
 		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
 		mcp.WithString("label_selector", mcp.Description("Kubernetes label selector. Examples: 'team=backend', 'env in (dev,staging)'.")),
 		mcp.WithArray("yq_expressions", mcp.Description("Optional yq expressions applied to the YAML array (use '.[]' to iterate). Examples: '.[].name' (just names), '.[] | select(.status == \"Active\") | .name' (only active), '.[] | select(.allowed == true) | .name' (only allowed by MCP authz).")),
+		jqExpressionsParam(),
+		outputFormatParam(),
 	)
-	m.mcpServer.AddTool(tool, m.handleListNamespaces)
+	m.addTool(tool, m.handleListNamespaces)
 }
 
 func (m *Manager) handleListNamespaces(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
 
-	k8sContext := m.getContextParam(args)
+	k8sContext := m.getContextParam(ctx, args)
 	labelSelector, _ := args["label_selector"].(string)
 
 	// Check authorization (real K8s resource: Namespace)
@@ -335,8 +381,8 @@ func (m *Manager) handleListNamespaces(ctx context.Context, request mcp.CallTool
 		return errorResult(err), nil
 	}
 
-	// Apply yq expressions
-	finalOutput, err := m.applyYQExpressions(yamlOutput, args)
+	// Apply filters and the requested output format
+	finalOutput, err := m.formatOutput(yamlOutput, args, "")
 	if err != nil {
 		return errorResult(err), nil
 	}
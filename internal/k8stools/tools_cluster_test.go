@@ -0,0 +1,67 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestListAPIVersions(t *testing.T) {
+	e := newFakeEnv(t)
+	// The fake discovery treats the first version of each group as preferred
+	e.clientset.Resources = []*metav1.APIResourceList{
+		{GroupVersion: "v1"},
+		{GroupVersion: "autoscaling/v2"},
+		{GroupVersion: "autoscaling/v1"},
+		{GroupVersion: "apps/v1"},
+	}
+
+	res, err := e.manager.handleListAPIVersions(context.Background(), makeRequest(map[string]any{
+		"yq_expressions": []any{`[.items[] | .group + "=" + .preferred_version + "(" + (.versions | join(",")) + ")"] | join(" ")`},
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "list_api_versions")
+	if got := strings.TrimSpace(out); got != "=v1(v1) apps=v1(v1) autoscaling=v2(v2,v1)" {
+		t.Fatalf("unexpected groups: %s", got)
+	}
+}
+
+func TestListAPIVersions_PreferredOnly(t *testing.T) {
+	e := newFakeEnv(t)
+	e.clientset.Resources = []*metav1.APIResourceList{
+		{GroupVersion: "autoscaling/v2"},
+		{GroupVersion: "autoscaling/v1"},
+	}
+
+	res, err := e.manager.handleListAPIVersions(context.Background(), makeRequest(map[string]any{
+		"preferred_only": true,
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "list_api_versions preferred_only")
+	requireContains(t, out, "group_version: autoscaling/v2", "expected preferred group_version")
+	if strings.Contains(out, "versions:") {
+		t.Fatalf("expected the version list to be dropped; got:\n%s", out)
+	}
+}
@@ -18,11 +18,16 @@ package k8stools
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sync"
+	"time"
 
 	"kubernetes-mcp/internal/authorization"
+	"kubernetes-mcp/internal/kubernetes"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"k8s.io/apimachinery/pkg/version"
 )
 
 func (m *Manager) registerGetCurrentContext() {
@@ -34,7 +39,7 @@ This is the context used by every other tool when its 'context' parameter
 is empty. To change it use 'switch_context'. To see all available contexts
 use 'list_contexts'.`),
 	)
-	m.mcpServer.AddTool(tool, m.handleGetCurrentContext)
+	m.addTool(tool, m.handleGetCurrentContext)
 }
 
 func (m *Manager) handleGetCurrentContext(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -46,7 +51,7 @@ func (m *Manager) handleGetCurrentContext(ctx context.Context, request mcp.CallT
 		return errorResult(err), nil
 	}
 
-	currentCtx := m.clientManager.GetCurrentContext()
+	currentCtx := m.clientManager.GetCurrentContext(sessionID(ctx))
 	config, _ := m.clientManager.GetContextConfig(currentCtx)
 
 	info := map[string]any{
@@ -67,12 +72,13 @@ func (m *Manager) registerListContexts() {
 		mcp.WithDescription(`List the MCP contexts (Kubernetes clusters) configured on this server.
 
 Each entry includes the context name, its human description from the server
-configuration, and whether it is the currently active default. Use this to
-pick a value for the 'context' parameter of other tools, or for
-'switch_context'.`),
+configuration, whether it is the currently active default and whether its
+client initialized ('ready'; 'error' explains why not). Use this to pick a
+value for the 'context' parameter of other tools, or for 'switch_context'.`),
 		mcp.WithArray("yq_expressions", mcp.Description("Optional yq expressions applied to the YAML array (use '.[]' to iterate). Examples: '.[].name' (just names), '.[] | select(.current == true) | .name' (the active one).")),
+		jqExpressionsParam(),
 	)
-	m.mcpServer.AddTool(tool, m.handleListContexts)
+	m.addTool(tool, m.handleListContexts)
 }
 
 func (m *Manager) handleListContexts(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -87,22 +93,32 @@ func (m *Manager) handleListContexts(ctx context.Context, request mcp.CallToolRe
 	}
 
 	contexts := m.clientManager.ListContexts()
-	currentCtx := m.clientManager.GetCurrentContext()
+	currentCtx := m.clientManager.GetCurrentContext(sessionID(ctx))
 
 	type ContextInfo struct {
 		Name        string `json:"name"`
 		Description string `json:"description"`
 		Current     bool   `json:"current"`
+		Ready       bool   `json:"ready"`
+		Error       string `json:"error,omitempty"`
 	}
 
 	var ctxList []ContextInfo
 	for _, name := range contexts {
 		config, _ := m.clientManager.GetContextConfig(name)
-		ctxList = append(ctxList, ContextInfo{
+		info := ContextInfo{
 			Name:        name,
 			Description: config.Description,
 			Current:     name == currentCtx,
-		})
+		}
+		// Clients are built on first use; building one here reports
+		// contexts that can't be used before a tool call fails on them.
+		if _, err := m.clientManager.GetClient(name); err != nil {
+			info.Error = err.Error()
+		} else {
+			info.Ready = true
+		}
+		ctxList = append(ctxList, info)
 	}
 
 	yamlOutput, err := objectToYAML(ctxList)
@@ -111,7 +127,7 @@ func (m *Manager) handleListContexts(ctx context.Context, request mcp.CallToolRe
 	}
 
 	// Apply yq expressions
-	finalOutput, err := m.applyYQExpressions(yamlOutput, args)
+	finalOutput, err := m.applyOutputFilters(yamlOutput, args)
 	if err != nil {
 		return errorResult(err), nil
 	}
@@ -121,19 +137,18 @@ func (m *Manager) handleListContexts(ctx context.Context, request mcp.CallToolRe
 
 func (m *Manager) registerSwitchContext() {
 	tool := mcp.NewTool(m.toolName("switch_context"),
-		mcp.WithDescription(`Change the MCP default context (Kubernetes cluster) used by every other
-tool when its 'context' parameter is empty.
-
-WARNING: this changes process-wide state. In an HTTP deployment with several
-clients connected to the same MCP server, every other caller will start
-seeing tools default to the new context as soon as this returns. To avoid
-accidents prefer passing 'context' explicitly to every destructive tool
-('apply_manifest', 'delete_resource', 'delete_resources', 'patch_resource',
-'scale_resource', 'restart_rollout', 'undo_rollout', 'exec_command') instead
-of relying on the active context.`),
+		mcp.WithDescription(`Change the current context (Kubernetes cluster) used by every other tool
+when its 'context' parameter is empty.
+
+The change only applies to this MCP session: other clients connected to the
+same server keep their own current context, and a new session starts on the
+server's default. Still, prefer passing 'context' explicitly to every
+destructive tool ('apply_manifest', 'delete_resource', 'delete_resources',
+'patch_resource', 'scale_resource', 'restart_rollout', 'undo_rollout',
+'exec_command') instead of relying on the current context.`),
 		mcp.WithString("context_name", mcp.Required(), mcp.Description("Name of the MCP context to make active. Must match one of the names returned by 'list_contexts'.")),
 	)
-	m.mcpServer.AddTool(tool, m.handleSwitchContext)
+	m.addTool(tool, m.handleSwitchContext)
 }
 
 func (m *Manager) handleSwitchContext(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -149,9 +164,15 @@ func (m *Manager) handleSwitchContext(ctx context.Context, request mcp.CallToolR
 		return errorResult(err), nil
 	}
 
-	oldContext := m.clientManager.GetCurrentContext()
+	// Stateless HTTP has no session to remember the choice in; switching
+	// would change the default of every client instead.
+	session := sessionID(ctx)
+	if transport := m.currentConfig().Server.Transport; session == "" && transport.Type == "http" && transport.HTTP.Stateless {
+		return errorResult(fmt.Errorf("switch_context is not available when the server runs stateless; pass 'context' to each tool instead")), nil
+	}
+	oldContext := m.clientManager.GetCurrentContext(session)
 
-	if err := m.clientManager.SetCurrentContext(contextName); err != nil {
+	if err := m.clientManager.SetCurrentContext(session, contextName); err != nil {
 		return errorResult(err), nil
 	}
 
@@ -159,3 +180,145 @@ func (m *Manager) handleSwitchContext(ctx context.Context, request mcp.CallToolR
 
 	return successResult(fmt.Sprintf("Switched context from %s to %s\nDescription: %s", oldContext, contextName, config.Description)), nil
 }
+
+const (
+	checkContextsDefaultTimeout = 5
+	checkContextsMaxTimeout     = 30
+)
+
+// contextHealth is the reachability of one context as reported by check_contexts
+type contextHealth struct {
+	Context   string `json:"context"`
+	Reachable bool   `json:"reachable"`
+	Version   string `json:"version,omitempty"`
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+func (m *Manager) registerCheckContexts() {
+	tool := mcp.NewTool(m.toolName("check_contexts"),
+		mcp.WithDescription(`Check which configured contexts (Kubernetes clusters) are actually reachable.
+
+For each context, asks the API server for its version and reports
+'reachable', the server 'version', the round trip in 'latency_ms' and the
+'error' when it fails: expired credentials, DNS or network problems, a
+kubeconfig that can't be loaded.
+
+Checks run concurrently and each one is bounded by 'timeout_seconds', so a
+dead cluster only delays the report by that much. Run it before a
+multi-context operation to skip the clusters that are down.`),
+		mcp.WithArray("contexts", mcp.Description("Contexts to check. Defaults to every configured context (see 'list_contexts').")),
+		mcp.WithNumber("timeout_seconds", mcp.Description("Maximum time each check may take. Integer 1..30. Defaults to 5.")),
+		mcp.WithArray("yq_expressions", mcp.Description("Optional yq expressions applied to the YAML array (use '.[]' to iterate). Examples: '.[] | select(.reachable == false) | .context' (the ones down), '.[] | [.context, .latency_ms]'.")),
+		jqExpressionsParam(),
+	)
+	m.addTool(tool, m.handleCheckContexts)
+}
+
+func (m *Manager) handleCheckContexts(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	contexts := stringArgs(args, "contexts")
+	if len(contexts) == 0 {
+		contexts = m.clientManager.ListContexts()
+	}
+
+	timeout := checkContextsDefaultTimeout
+	if v, ok := args["timeout_seconds"].(float64); ok {
+		if v < 1 || v > checkContextsMaxTimeout {
+			return errorResult(fmt.Errorf("timeout_seconds must be between 1 and %d, got %v", checkContextsMaxTimeout, v)), nil
+		}
+		timeout = int(v)
+	}
+
+	report := make([]contextHealth, len(contexts))
+	var wg sync.WaitGroup
+	for i, k8sContext := range contexts {
+		report[i].Context = k8sContext
+
+		// Check authorization (virtual resource: _/ClusterInfo), per context
+		if err := m.checkAuthorization(request, "check_contexts", k8sContext, "", authorization.ResourceInfo{
+			Group:    authorization.VirtualResourceGroup,
+			Resource: authorization.VirtualResourceClusterInfo,
+		}); err != nil {
+			report[i].Error = err.Error()
+			continue
+		}
+
+		wg.Add(1)
+		go func(health *contextHealth) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+			defer cancel()
+			m.checkContext(checkCtx, health)
+		}(&report[i])
+	}
+	wg.Wait()
+
+	yamlOutput, err := objectToYAML(report)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	finalOutput, err := m.applyOutputFilters(yamlOutput, args)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	return successResult(finalOutput), nil
+}
+
+// checkContext fills in the reachability of one context, giving up when
+// ctx is done even if the client doesn't honour it.
+func (m *Manager) checkContext(ctx context.Context, health *contextHealth) {
+	client, err := m.clientManager.GetClient(health.Context)
+	if err != nil {
+		health.Error = err.Error()
+		return
+	}
+
+	type versionResult struct {
+		info *version.Info
+		err  error
+	}
+	done := make(chan versionResult, 1)
+	start := time.Now()
+	go func() {
+		info, err := serverVersion(ctx, client)
+		done <- versionResult{info, err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		health.Error = fmt.Sprintf("no answer within the timeout: %v", ctx.Err())
+	case res := <-done:
+		if res.err != nil {
+			health.Error = res.err.Error()
+		} else {
+			health.Reachable = true
+			health.Version = res.info.GitVersion
+		}
+	}
+	health.LatencyMS = time.Since(start).Milliseconds()
+}
+
+// serverVersion asks the API server for its version through the discovery
+// REST client, so the request is bound to ctx. Clients without one (fakes)
+// fall back to ServerVersion.
+func serverVersion(ctx context.Context, client *kubernetes.Client) (*version.Info, error) {
+	discoveryClient := client.Clientset.Discovery()
+	restClient := discoveryClient.RESTClient()
+	if restClient == nil {
+		return discoveryClient.ServerVersion()
+	}
+
+	body, err := restClient.Get().AbsPath("/version").Do(ctx).Raw()
+	if err != nil {
+		return nil, err
+	}
+	var info version.Info
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, fmt.Errorf("unable to parse the server version: %w", err)
+	}
+	return &info, nil
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"kubernetes-mcp/api"
+	"kubernetes-mcp/internal/kubernetes"
+
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+)
+
+func TestCheckContexts(t *testing.T) {
+	e := newFakeEnv(t)
+	e.clientset.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.31.2"}
+
+	check := func(args map[string]any) string {
+		t.Helper()
+		res, err := e.manager.handleCheckContexts(context.Background(), makeRequest(args))
+		if err != nil {
+			t.Fatalf("go-error: %v", err)
+		}
+		return expectOK(t, res, "check_contexts")
+	}
+
+	// Every configured context by default
+	out := check(nil)
+	requireContains(t, out, "context: "+fakeContext, "expected the configured context")
+	requireContains(t, out, "reachable: true", "expected the fake cluster reachable")
+	requireContains(t, out, "version: v1.31.2", "expected the server version")
+	requireContains(t, out, "latency_ms:", "expected the latency")
+
+	// A failing context is reported without failing the others
+	out = check(map[string]any{
+		"contexts":       []any{fakeContext, "gone"},
+		"yq_expressions": []any{`.[] | .context + "=" + (.reachable | tostring)`},
+	})
+	if got := strings.Fields(out); strings.Join(got, " ") != fakeContext+"=true gone=false" {
+		t.Fatalf("unexpected report: %s", out)
+	}
+	out = check(map[string]any{"contexts": []any{"gone"}})
+	requireContains(t, out, "context gone not found", "expected the failure explained")
+
+	res, _ := e.manager.handleCheckContexts(context.Background(), makeRequest(map[string]any{"timeout_seconds": float64(60)}))
+	requireContains(t, expectErr(t, res, "timeout out of range"), "timeout_seconds must be between 1 and 30", "expected the range error")
+}
+
+func TestSwitchContext_PerSession(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "kubeconfig")
+	if err := os.WriteFile(kubeconfig, []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: https://127.0.0.1:6443
+users:
+- name: test
+  user:
+    token: test-token
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: test
+current-context: test
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	clientManager, err := kubernetes.NewClientManager(slog.New(slog.NewTextHandler(io.Discard, nil)), &api.KubernetesConfig{
+		DefaultContext: "prod",
+		Contexts: []api.KubernetesContextConfig{
+			{Name: "prod", Kubeconfig: kubeconfig},
+			{Name: "staging", Kubeconfig: kubeconfig},
+			{Name: "dev", Kubeconfig: kubeconfig},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewClientManager: %v", err)
+	}
+	defer clientManager.Stop()
+
+	e := newFakeEnv(t)
+	e.manager.clientManager = clientManager
+	sessionA := e.manager.mcpServer.WithContext(context.Background(), fakeSession{id: "a"})
+	sessionB := e.manager.mcpServer.WithContext(context.Background(), fakeSession{id: "b"})
+
+	switchTo := func(ctx context.Context, name string) {
+		t.Helper()
+		res, err := e.manager.handleSwitchContext(ctx, makeRequest(map[string]any{"context_name": name}))
+		if err != nil {
+			t.Fatalf("go-error: %v", err)
+		}
+		expectOK(t, res, "switch_context")
+	}
+	current := func(ctx context.Context) string {
+		t.Helper()
+		res, err := e.manager.handleGetCurrentContext(ctx, makeRequest(map[string]any{}))
+		if err != nil {
+			t.Fatalf("go-error: %v", err)
+		}
+		return expectOK(t, res, "get_current_context")
+	}
+
+	switchTo(sessionA, "staging")
+	switchTo(sessionB, "dev")
+	requireContains(t, current(sessionA), "name: staging", "session a kept its own switch")
+	requireContains(t, current(sessionB), "name: dev", "session b kept its own switch")
+
+	// Tools called without 'context' resolve the session's current one
+	if got := e.manager.getContextParam(sessionA, map[string]any{}); got != "staging" {
+		t.Fatalf("expected staging for session a, got %s", got)
+	}
+
+	// A session that never switched, and calls outside any session, use the default
+	sessionC := e.manager.mcpServer.WithContext(context.Background(), fakeSession{id: "c"})
+	requireContains(t, current(sessionC), "name: prod", "new session on the default")
+	requireContains(t, current(context.Background()), "name: prod", "no session on the default")
+
+	// Stateless HTTP calls carry no session: switching would change the
+	// default for every client, so it is refused
+	e.manager.config.Server.Transport.Type = "http"
+	e.manager.config.Server.Transport.HTTP.Stateless = true
+	res, err := e.manager.handleSwitchContext(context.Background(), makeRequest(map[string]any{"context_name": "dev"}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	expectErr(t, res, "stateless")
+	requireContains(t, current(context.Background()), "name: prod", "stateless switch left the default alone")
+}
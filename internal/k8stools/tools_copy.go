@@ -0,0 +1,378 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"kubernetes-mcp/internal/authorization"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+const (
+	// defaultCopyMaxBytes applies when 'kubernetes.tools.copy.max_bytes' is unset
+	defaultCopyMaxBytes = 10 << 20 // 10 MiB
+
+	// copyTimeout bounds a single transfer, tar included
+	copyTimeout = 2 * time.Minute
+
+	copyStderrBytes = 64 << 10
+)
+
+// Files move through 'tar' inside the container, the same mechanism as
+// 'kubectl cp': copy_from_pod runs 'tar cf - <path>' and reads the archive
+// from stdout, copy_to_pod writes a one-file archive to 'tar xmf -' on
+// stdin. The image must ship a 'tar' binary.
+
+// copyMaxBytes returns the most file content a single copy may transfer
+func (m *Manager) copyMaxBytes() int64 {
+	if maxBytes := m.currentConfig().Kubernetes.Tools.Copy.MaxBytes; maxBytes > 0 {
+		return maxBytes
+	}
+	return defaultCopyMaxBytes
+}
+
+func (m *Manager) registerCopyFromPod() {
+	tool := mcp.NewTool(m.toolName("copy_from_pod"),
+		mcp.WithDescription(fmt.Sprintf(`Read a file or directory out of a running container, like 'kubectl cp'.
+
+Runs 'tar cf - <path>' in the container and returns every regular file in
+the archive with its size and mode. Text files are returned as-is; binary
+files (heap dumps, archives, anything not valid UTF-8) are base64-encoded,
+as marked by 'encoding'. Symlinks are listed with their target, not
+followed.
+
+Bounded: the combined file content is capped at %d bytes
+(kubernetes.tools.copy.max_bytes) and the transfer at %s. The image must
+ship 'tar'; distroless images don't.`, m.copyMaxBytes(), copyTimeout)),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Name of the Pod to copy from.")),
+		mcp.WithString("namespace", mcp.Description("Namespace where the Pod lives. Defaults to 'default' if empty.")),
+		mcp.WithString("container", mcp.Description("Name of the container inside the Pod. Required when the Pod has more than one container.")),
+		mcp.WithString("path", mcp.Required(), mcp.Description("Path of the file or directory inside the container. Example: '/etc/nginx/nginx.conf', '/tmp/heap.hprof'.")),
+	)
+	m.addWaitingTool(tool, m.handleCopyFromPod, copyTimeout)
+}
+
+func (m *Manager) handleCopyFromPod(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	name, _ := args["name"].(string)
+	namespace, _ := args["namespace"].(string)
+	if namespace == "" {
+		namespace = "default"
+	}
+	container, _ := args["container"].(string)
+	srcPath, _ := args["path"].(string)
+	if srcPath == "" {
+		return errorResult(fmt.Errorf("path is required")), nil
+	}
+
+	// Check authorization (real K8s resource: Pod)
+	if err := m.checkAuthorization(request, "copy_from_pod", k8sContext, namespace, authorization.ResourceInfo{
+		Group:    "",
+		Version:  "v1",
+		Resource: "pods",
+		Name:     name,
+	}); err != nil {
+		return errorResult(err), nil
+	}
+
+	if !m.clientManager.IsNamespaceAllowed(k8sContext, namespace) {
+		return errorResult(fmt.Errorf("namespace %s is not allowed in context %s", namespace, k8sContext)), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	copyCtx, cancel := context.WithTimeout(ctx, copyTimeout)
+	defer cancel()
+
+	// The archive is parsed while it streams, so an oversized copy stops at
+	// the limit instead of being buffered whole.
+	pr, pw := io.Pipe()
+	stderr := newCappedBuffer(copyStderrBytes)
+	done := make(chan error, 1)
+	go func() {
+		err := streamPodExec(copyCtx, client, namespace, name, container, []string{"tar", "cf", "-", srcPath}, remotecommand.StreamOptions{
+			Stdout: pw,
+			Stderr: stderr,
+		})
+		pw.CloseWithError(err)
+		done <- err
+	}()
+
+	maxBytes := m.copyMaxBytes()
+	files, readErr := readTarFiles(pr, maxBytes)
+	if readErr != nil {
+		cancel()
+		pr.CloseWithError(readErr)
+	} else {
+		// Drain the archive trailer so tar can exit cleanly
+		_, _ = io.Copy(io.Discard, pr)
+	}
+	streamErr := <-done
+
+	if errors.Is(readErr, errCopyTooLarge) {
+		return errorResult(fmt.Errorf("%s in pod %s/%s holds more than %d bytes (kubernetes.tools.copy.max_bytes); copy a narrower path", srcPath, namespace, name, maxBytes)), nil
+	}
+	if streamErr != nil {
+		return errorResult(copyFailure(streamErr, stderr)), nil
+	}
+	if readErr != nil {
+		return errorResult(fmt.Errorf("reading the archive of %s: %w", srcPath, readErr)), nil
+	}
+	if len(files) == 0 {
+		return errorResult(fmt.Errorf("no files found at %s in pod %s/%s", srcPath, namespace, name)), nil
+	}
+
+	var total int64
+	entries := make([]map[string]any, 0, len(files))
+	for _, f := range files {
+		total += f.size
+		entries = append(entries, f.entry())
+	}
+	yamlOutput, err := objectToYAML(map[string]any{
+		"pod":         fmt.Sprintf("%s/%s", namespace, name),
+		"path":        srcPath,
+		"total_bytes": total,
+		"files":       entries,
+	})
+	if err != nil {
+		return errorResult(err), nil
+	}
+	return successResult(yamlOutput), nil
+}
+
+func (m *Manager) registerCopyToPod() {
+	tool := mcp.NewTool(m.toolName("copy_to_pod"),
+		mcp.WithDescription(fmt.Sprintf(`Write a file into a running container, like 'kubectl cp'.
+
+The content is packed into a one-file tar archive and unpacked by
+'tar xmf -' in the container, so binary data arrives intact. An existing
+file at 'path' is overwritten; the parent directory must already exist.
+The file is written with mode 0644 as the container's user.
+
+Pass binary content base64-encoded (the default 'encoding'), or plain text
+with encoding='text'. Bounded to %d bytes of decoded content
+(kubernetes.tools.copy.max_bytes). The image must ship 'tar'.`, m.copyMaxBytes())),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Name of the Pod to copy to.")),
+		mcp.WithString("namespace", mcp.Description("Namespace where the Pod lives. Defaults to 'default' if empty.")),
+		mcp.WithString("container", mcp.Description("Name of the container inside the Pod. Required when the Pod has more than one container.")),
+		mcp.WithString("path", mcp.Required(), mcp.Description("Absolute destination file path inside the container. Example: '/tmp/debug.sh'.")),
+		mcp.WithString("content", mcp.Required(), mcp.Description("File content, encoded as set by 'encoding'.")),
+		mcp.WithString("encoding", mcp.Enum("base64", "text"), mcp.Description("How 'content' is encoded. Defaults to 'base64'.")),
+	)
+	m.addWaitingTool(tool, m.handleCopyToPod, copyTimeout)
+}
+
+func (m *Manager) handleCopyToPod(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	name, _ := args["name"].(string)
+	namespace, _ := args["namespace"].(string)
+	if namespace == "" {
+		namespace = "default"
+	}
+	container, _ := args["container"].(string)
+	destPath, _ := args["path"].(string)
+	content, _ := args["content"].(string)
+	encoding, _ := args["encoding"].(string)
+
+	if !path.IsAbs(destPath) || strings.HasSuffix(destPath, "/") {
+		return errorResult(fmt.Errorf("path must be an absolute file path, got %q", destPath)), nil
+	}
+	destPath = path.Clean(destPath)
+
+	var data []byte
+	switch encoding {
+	case "", "base64":
+		decoded, err := base64.StdEncoding.DecodeString(content)
+		if err != nil {
+			return errorResult(fmt.Errorf("content is not valid base64: %w", err)), nil
+		}
+		data = decoded
+	case "text":
+		data = []byte(content)
+	default:
+		return errorResult(fmt.Errorf("encoding must be 'base64' or 'text', got %q", encoding)), nil
+	}
+	if maxBytes := m.copyMaxBytes(); int64(len(data)) > maxBytes {
+		return errorResult(fmt.Errorf("content is %d bytes, above the %d byte copy limit (kubernetes.tools.copy.max_bytes)", len(data), maxBytes)), nil
+	}
+
+	// Check authorization (real K8s resource: Pod)
+	if err := m.checkAuthorization(request, "copy_to_pod", k8sContext, namespace, authorization.ResourceInfo{
+		Group:    "",
+		Version:  "v1",
+		Resource: "pods",
+		Name:     name,
+	}); err != nil {
+		return errorResult(err), nil
+	}
+
+	if !m.clientManager.IsNamespaceAllowed(k8sContext, namespace) {
+		return errorResult(fmt.Errorf("namespace %s is not allowed in context %s", namespace, k8sContext)), nil
+	}
+
+	archive, err := singleFileTar(path.Base(destPath), data)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	copyCtx, cancel := context.WithTimeout(ctx, copyTimeout)
+	defer cancel()
+
+	stderr := newCappedBuffer(copyStderrBytes)
+	err = streamPodExec(copyCtx, client, namespace, name, container, []string{"tar", "xmf", "-", "-C", path.Dir(destPath)}, remotecommand.StreamOptions{
+		Stdin:  bytes.NewReader(archive),
+		Stdout: io.Discard,
+		Stderr: stderr,
+	})
+	if err != nil {
+		return errorResult(copyFailure(err, stderr)), nil
+	}
+
+	return successResult(fmt.Sprintf("Copied %d bytes to %s in pod %s/%s", len(data), destPath, namespace, name)), nil
+}
+
+// errCopyTooLarge stops reading an archive whose files exceed the copy limit
+var errCopyTooLarge = errors.New("copy limit exceeded")
+
+// copiedFile is one entry read from a container's tar archive
+type copiedFile struct {
+	path       string
+	mode       int64
+	size       int64
+	data       []byte
+	linkTarget string
+}
+
+// entry renders the file for the tool result, base64-encoding content that
+// is not plain text.
+func (f copiedFile) entry() map[string]any {
+	out := map[string]any{
+		"path": f.path,
+		"mode": fmt.Sprintf("%04o", f.mode&0o7777),
+		"size": f.size,
+	}
+	if f.linkTarget != "" {
+		out["link_target"] = f.linkTarget
+		return out
+	}
+	if utf8.Valid(f.data) && !bytes.ContainsRune(f.data, 0) {
+		out["encoding"] = "text"
+		out["content"] = string(f.data)
+	} else {
+		out["encoding"] = "base64"
+		out["content"] = base64.StdEncoding.EncodeToString(f.data)
+	}
+	return out
+}
+
+// readTarFiles collects the regular files and symlinks of a tar stream,
+// failing with errCopyTooLarge as soon as their content passes maxBytes.
+// Directories are implied by the file paths and skipped.
+func readTarFiles(r io.Reader, maxBytes int64) ([]copiedFile, error) {
+	var files []copiedFile
+	var total int64
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return files, err
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeReg:
+			total += hdr.Size
+			if total > maxBytes {
+				return files, errCopyTooLarge
+			}
+			data, err := io.ReadAll(io.LimitReader(tr, hdr.Size))
+			if err != nil {
+				return files, err
+			}
+			files = append(files, copiedFile{path: hdr.Name, mode: hdr.Mode, size: hdr.Size, data: data})
+		case tar.TypeSymlink:
+			files = append(files, copiedFile{path: hdr.Name, mode: hdr.Mode, linkTarget: hdr.Linkname})
+		}
+	}
+}
+
+// singleFileTar packs data as a single 0644 file named 'name'
+func singleFileTar(name string, data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0o644,
+		Size:     int64(len(data)),
+		ModTime:  time.Now(),
+	}); err != nil {
+		return nil, err
+	}
+	if _, err := tw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// copyFailure explains a failed tar exec with its exit code and stderr
+func copyFailure(err error, stderr *cappedBuffer) error {
+	msg := strings.TrimSpace(stderr.String())
+	if code, ok := execExitCode(err); ok {
+		if msg == "" {
+			return fmt.Errorf("tar exited with code %d", code)
+		}
+		return fmt.Errorf("tar exited with code %d: %s", code, msg)
+	}
+	if msg != "" {
+		return fmt.Errorf("copy failed: %v: %s", err, msg)
+	}
+	return fmt.Errorf("copy failed: %w", err)
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestReadTarFiles(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	write := func(hdr *tar.Header, data []byte) {
+		t.Helper()
+		hdr.Size = int64(len(data))
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(&tar.Header{Typeflag: tar.TypeDir, Name: "etc/app/", Mode: 0o755}, nil)
+	write(&tar.Header{Typeflag: tar.TypeReg, Name: "etc/app/config.yaml", Mode: 0o644}, []byte("port: 8080\n"))
+	write(&tar.Header{Typeflag: tar.TypeReg, Name: "etc/app/blob.bin", Mode: 0o600}, []byte{0x00, 0xff, 0x10})
+	write(&tar.Header{Typeflag: tar.TypeSymlink, Name: "etc/app/current", Linkname: "config.yaml", Mode: 0o777}, nil)
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := readTarFiles(bytes.NewReader(buf.Bytes()), 1024)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("expected 2 files and a symlink, got %d", len(files))
+	}
+
+	text := files[0].entry()
+	if text["encoding"] != "text" || text["content"] != "port: 8080\n" || text["mode"] != "0644" {
+		t.Fatalf("unexpected text entry: %v", text)
+	}
+	if binary := files[1].entry(); binary["encoding"] != "base64" || binary["content"] != "AP8Q" {
+		t.Fatalf("expected binary content base64-encoded, got %v", binary)
+	}
+	if link := files[2].entry(); link["link_target"] != "config.yaml" || link["content"] != nil {
+		t.Fatalf("expected the symlink target without content, got %v", link)
+	}
+
+	if _, err := readTarFiles(bytes.NewReader(buf.Bytes()), 12); !errors.Is(err, errCopyTooLarge) {
+		t.Fatalf("expected errCopyTooLarge past the limit, got %v", err)
+	}
+}
+
+func TestSingleFileTar(t *testing.T) {
+	archive, err := singleFileTar("debug.sh", []byte("#!/bin/sh\necho hi\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := readTarFiles(bytes.NewReader(archive), 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].path != "debug.sh" || string(files[0].data) != "#!/bin/sh\necho hi\n" {
+		t.Fatalf("unexpected round trip: %+v", files)
+	}
+}
+
+func TestCopyToPod_Validation(t *testing.T) {
+	e := newFakeEnv(t, fakePod("default", "web", nil))
+	e.manager.config.Kubernetes.Tools.Copy.MaxBytes = 4
+
+	cases := []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{"relative path", map[string]any{"path": "tmp/x", "content": "aGk="}, "absolute file path"},
+		{"directory path", map[string]any{"path": "/tmp/", "content": "aGk="}, "absolute file path"},
+		{"bad base64", map[string]any{"path": "/tmp/x", "content": "not base64!"}, "not valid base64"},
+		{"too large", map[string]any{"path": "/tmp/x", "content": "hello", "encoding": "text"}, "above the 4 byte copy limit"},
+		{"bad encoding", map[string]any{"path": "/tmp/x", "content": "hi", "encoding": "hex"}, "encoding must be"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.args["name"] = "web"
+			res, err := e.manager.handleCopyToPod(context.Background(), makeRequest(tc.args))
+			if err != nil {
+				t.Fatalf("go-error: %v", err)
+			}
+			requireContains(t, expectErr(t, res, tc.name), tc.want, "unexpected error")
+		})
+	}
+}
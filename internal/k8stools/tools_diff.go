@@ -19,10 +19,12 @@ package k8stools
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"kubernetes-mcp/internal/authorization"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/pmezard/go-difflib/difflib"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -34,14 +36,26 @@ func (m *Manager) registerDiffManifest() {
 		mcp.WithDescription(`Preview the changes that 'apply_manifest' would make, WITHOUT applying them.
 
 Compares the desired manifest against the current cluster state and reports
-field-level additions / removals / modifications. Server-managed fields that
+field-level additions / removals / modifications ('+' / '-' / '~', list
+elements addressed as 'path[i]' and compared in order), followed by a
+unified diff of the normalized YAML ('--- current' / '+++ desired'). Server-managed fields that
 would otherwise show up as constant noise are stripped from BOTH sides
 before the comparison: the 'status' subtree, metadata fields
 ('resourceVersion', 'uid', 'generation', 'creationTimestamp',
 'managedFields', 'finalizers', 'ownerReferences', 'deletionTimestamp'),
-the 'kubectl.kubernetes.io/last-applied-configuration' annotation, plus
-controller-assigned immutable fields ('Service.spec.clusterIP/clusterIPs/
+server-injected annotations ('kubectl.kubernetes.io/last-applied-configuration',
+'deployment.kubernetes.io/revision', 'deprecated.daemonset.template.generation'),
+plus controller-assigned immutable fields ('Service.spec.clusterIP/clusterIPs/
 ipFamilies/ipFamilyPolicy', 'PersistentVolumeClaim.spec.volumeName').
+Use 'ignore_paths' to suppress further fields known to be noisy.
+
+By default the manifest is first sent to the API server as a dry-run update
+(nothing is persisted), the same update 'apply_manifest' performs, and the
+object the server would store is compared, like 'kubectl diff'. Defaulted
+fields and admission webhook changes therefore don't show up as false
+positives. When the dry run is forbidden (no update permission) the raw
+manifest is compared instead and the result says so; 'server_side=false'
+forces that offline comparison.
 
 If the resource does not yet exist, the tool reports that it would be CREATED.
 
@@ -53,17 +67,28 @@ rejected with an explicit error (one call per document).`),
 		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
 		mcp.WithString("manifest", mcp.Required(), mcp.Description("A single Kubernetes manifest in YAML or JSON. Multi-document YAML is NOT supported.")),
 		mcp.WithString("namespace", mcp.Description("Namespace override. If set, takes precedence over 'metadata.namespace' from the manifest. Ignored for cluster-scoped kinds.")),
+		mcp.WithBoolean("server_side", mcp.Description("Compare the object a server-side dry run would store instead of the raw manifest. Defaults to true.")),
+		mcp.WithArray("ignore_paths", mcp.Description("Optional dotted paths whose changes are not reported; a path also ignores everything below it. Map keys are written as-is, dots included. Examples: 'spec.replicas' (managed by an HPA), 'metadata.annotations.argocd.argoproj.io/tracking-id', 'metadata.labels'.")),
 	)
-	m.mcpServer.AddTool(tool, m.handleDiffManifest)
+	m.addTool(tool, m.handleDiffManifest)
 }
 
 func (m *Manager) handleDiffManifest(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
 
-	k8sContext := m.getContextParam(args)
+	k8sContext := m.getContextParam(ctx, args)
 	manifest, _ := args["manifest"].(string)
 	namespaceOverride, _ := args["namespace"].(string)
 
+	var ignorePaths []string
+	if raw, ok := args["ignore_paths"].([]any); ok {
+		for _, p := range raw {
+			if s, ok := p.(string); ok && strings.Trim(s, ".") != "" {
+				ignorePaths = append(ignorePaths, strings.Trim(s, "."))
+			}
+		}
+	}
+
 	// Reject multi-document YAML explicitly. sigs.k8s.io/yaml.Unmarshal would
 	// silently keep only the first document, which masks bugs in callers.
 	if isMultiDocumentYAML(manifest) {
@@ -122,13 +147,11 @@ func (m *Manager) handleDiffManifest(ctx context.Context, request mcp.CallToolRe
 	}
 
 	// Get current resource from cluster
-	var current *unstructured.Unstructured
+	var nsClient dynamicResource = client.DynamicClient.Resource(gvr)
 	if namespace != "" {
-		current, err = client.DynamicClient.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
-	} else {
-		current, err = client.DynamicClient.Resource(gvr).Get(ctx, name, metav1.GetOptions{})
+		nsClient = client.DynamicClient.Resource(gvr).Namespace(namespace)
 	}
-
+	current, err := nsClient.Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			return successResult(fmt.Sprintf("Resource %s/%s does not exist in namespace %s\nThis manifest would CREATE a new resource.", gvk.Kind, name, namespace)), nil
@@ -136,40 +159,165 @@ func (m *Manager) handleDiffManifest(ctx context.Context, request mcp.CallToolRe
 		return errorResult(err), nil
 	}
 
-	// Compare the two
-	currentYAML, err := objectToYAML(current.Object)
-	if err != nil {
-		return errorResult(err), nil
-	}
-
-	desiredYAML, err := objectToYAML(obj.Object)
-	if err != nil {
-		return errorResult(err), nil
+	// Compare against what the API server would store, defaulting and
+	// admission included, unless the caller opted out or may not update.
+	desired := obj
+	mode := "submitted manifest"
+	if serverSide, ok := args["server_side"].(bool); !ok || serverSide {
+		stored, err := dryRunUpdate(ctx, nsClient, obj, current, namespace)
+		switch {
+		case err == nil:
+			desired = stored
+			mode = "server-side dry run"
+		case apierrors.IsForbidden(err) || apierrors.IsMethodNotSupported(err):
+			mode = fmt.Sprintf("submitted manifest; the server-side dry run was refused (%v), so defaulted fields may show as changes", err)
+		default:
+			return errorResult(fmt.Errorf("the server-side dry run rejected the manifest: %w", err)), nil
+		}
 	}
 
-	// Simple diff - compare key fields
-	diff := compareObjects(current.Object, obj.Object, "")
+	diff := compareObjects(current.Object, desired.Object, "", ignorePaths)
 
 	if len(diff) == 0 {
-		return successResult(fmt.Sprintf("No changes detected for %s/%s in namespace %s", gvk.Kind, name, namespace)), nil
+		return successResult(fmt.Sprintf("No changes detected for %s/%s in namespace %s (compared against the %s)", gvk.Kind, name, namespace, mode)), nil
+	}
+
+	// The change lines and the unified diff print old and new values, so when
+	// redaction is enabled they are rebuilt from the redacted objects. A
+	// change confined to redacted fields is still reported, just without its
+	// values.
+	currentObj, desiredObj := current.Object, desired.Object
+	if m.currentRedactor() != nil {
+		currentYAML, err := objectToYAML(current.Object)
+		if err != nil {
+			return errorResult(err), nil
+		}
+		desiredYAML, err := objectToYAML(desired.Object)
+		if err != nil {
+			return errorResult(err), nil
+		}
+		currentObj = redactedObject(m.redactYAML(currentYAML))
+		desiredObj = redactedObject(m.redactYAML(desiredYAML))
+		diff = compareObjects(currentObj, desiredObj, "", ignorePaths)
+		if len(diff) == 0 {
+			diff = []string{"~ (changes limited to redacted fields)"}
+		}
+	}
+
+	unified, err := unifiedObjectDiff(currentObj, desiredObj, ignorePaths)
+	if err != nil {
+		return errorResult(err), nil
 	}
 
-	output := fmt.Sprintf("Diff for %s/%s in namespace %s:\n\n", gvk.Kind, name, namespace)
+	output := fmt.Sprintf("Diff for %s/%s in namespace %s (compared against the %s):\n\n", gvk.Kind, name, namespace, mode)
 	output += "Changes:\n"
 	for _, d := range diff {
 		output += fmt.Sprintf("  %s\n", d)
 	}
-	output += "\n--- Current ---\n" + currentYAML
-	output += "\n--- Desired ---\n" + desiredYAML
+	output += "\n" + unified
 
 	return successResult(output), nil
 }
 
+// dryRunUpdate returns the object the API server would store if 'obj'
+// replaced 'live', the same update apply_manifest performs, without
+// persisting it.
+func dryRunUpdate(ctx context.Context, nsClient dynamicResource, obj, live *unstructured.Unstructured, namespace string) (*unstructured.Unstructured, error) {
+	desired := obj.DeepCopy()
+	if namespace != "" {
+		desired.SetNamespace(namespace)
+	}
+	mergeImmutableFields(desired, live, desired.GroupVersionKind())
+	desired.SetResourceVersion(live.GetResourceVersion())
+	return nsClient.Update(ctx, desired, metav1.UpdateOptions{DryRun: []string{metav1.DryRunAll}})
+}
+
+// redactedObject decodes redacted YAML back into a map for compareObjects.
+// The input always comes from objectToYAML, so a decode error is not expected.
+func redactedObject(redactedYAML string) map[string]any {
+	obj := map[string]any{}
+	_ = yaml.Unmarshal([]byte(redactedYAML), &obj)
+	return obj
+}
+
+// unifiedObjectDiff renders a unified diff of two objects as YAML, after the
+// same normalization compareObjects applies: server-managed fields and
+// ignored paths are removed from both sides first.
+func unifiedObjectDiff(current, desired map[string]any, ignorePaths []string) (string, error) {
+	currentYAML, err := objectToYAML(pruneIgnoredPaths(stripServerManagedFields(current), "", ignorePaths))
+	if err != nil {
+		return "", err
+	}
+	desiredYAML, err := objectToYAML(pruneIgnoredPaths(stripServerManagedFields(desired), "", ignorePaths))
+	if err != nil {
+		return "", err
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        yamlLines(currentYAML),
+		B:        yamlLines(desiredYAML),
+		FromFile: "current",
+		ToFile:   "desired",
+		Context:  3,
+	})
+}
+
+// yamlLines splits YAML into lines that keep their newline
+func yamlLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// pruneIgnoredPaths returns a copy of 'in' without the fields at or below
+// 'ignorePaths', addressed the same way as the diff lines.
+func pruneIgnoredPaths(in map[string]any, path string, ignorePaths []string) map[string]any {
+	if len(ignorePaths) == 0 {
+		return in
+	}
+	out := make(map[string]any, len(in))
+	for key, val := range in {
+		childPath := key
+		if path != "" {
+			childPath = path + "." + key
+		}
+		if pathIgnored(childPath, ignorePaths) {
+			continue
+		}
+		out[key] = pruneIgnoredValue(val, childPath, ignorePaths)
+	}
+	return out
+}
+
+func pruneIgnoredValue(val any, path string, ignorePaths []string) any {
+	if !ignoresBelow(path, ignorePaths) {
+		return val
+	}
+	switch v := val.(type) {
+	case map[string]any:
+		return pruneIgnoredPaths(v, path, ignorePaths)
+	case []any:
+		out := make([]any, 0, len(v))
+		for i, elem := range v {
+			elemPath := fmt.Sprintf("%s[%d]", path, i)
+			if pathIgnored(elemPath, ignorePaths) {
+				continue
+			}
+			out = append(out, pruneIgnoredValue(elem, elemPath, ignorePaths))
+		}
+		return out
+	}
+	return val
+}
+
 // compareObjects compares two maps and returns a list of differences.
 // It applies a "strip" pass to both sides to ignore server-managed fields
 // that produce false positives (last-applied-configuration, finalizers,
-// cluster-assigned IPs, etc.) before walking the structure.
-func compareObjects(current, desired map[string]any, path string) []string {
+// cluster-assigned IPs, etc.) before walking the structure. Fields at or
+// below one of 'ignorePaths' (dotted, as printed in the diff lines) are
+// skipped.
+func compareObjects(current, desired map[string]any, path string, ignorePaths []string) []string {
 	if path == "" {
 		current = stripServerManagedFields(current)
 		desired = stripServerManagedFields(desired)
@@ -182,33 +330,22 @@ func compareObjects(current, desired map[string]any, path string) []string {
 			currentPath = path + "." + key
 		}
 
+		if pathIgnored(currentPath, ignorePaths) {
+			continue
+		}
+
 		currentVal, exists := current[key]
 		if !exists {
+			if dv, ok := desiredVal.(map[string]any); ok && ignoresBelow(currentPath, ignorePaths) {
+				// Report the new subtree field by field so ignored keys drop out
+				diffs = append(diffs, compareObjects(map[string]any{}, dv, currentPath, ignorePaths)...)
+				continue
+			}
 			diffs = append(diffs, fmt.Sprintf("+ %s: %v", currentPath, summarizeValue(desiredVal)))
 			continue
 		}
 
-		// Compare values
-		switch dv := desiredVal.(type) {
-		case map[string]any:
-			if cv, ok := currentVal.(map[string]any); ok {
-				diffs = append(diffs, compareObjects(cv, dv, currentPath)...)
-			} else {
-				diffs = append(diffs, fmt.Sprintf("~ %s: type changed", currentPath))
-			}
-		case []any:
-			if cv, ok := currentVal.([]any); ok {
-				if !slicesEqualNormalized(cv, dv) {
-					diffs = append(diffs, fmt.Sprintf("~ %s: array changed", currentPath))
-				}
-			} else {
-				diffs = append(diffs, fmt.Sprintf("~ %s: type changed", currentPath))
-			}
-		default:
-			if currentVal != desiredVal {
-				diffs = append(diffs, fmt.Sprintf("~ %s: %v -> %v", currentPath, summarizeValue(currentVal), summarizeValue(desiredVal)))
-			}
-		}
+		diffs = append(diffs, compareValues(currentVal, desiredVal, currentPath, ignorePaths)...)
 	}
 
 	// Check for removed fields
@@ -217,7 +354,11 @@ func compareObjects(current, desired map[string]any, path string) []string {
 		if path != "" {
 			currentPath = path + "." + key
 		}
-		if _, exists := desired[key]; !exists {
+		if _, exists := desired[key]; !exists && !pathIgnored(currentPath, ignorePaths) {
+			if cv, ok := current[key].(map[string]any); ok && ignoresBelow(currentPath, ignorePaths) {
+				diffs = append(diffs, compareObjects(cv, map[string]any{}, currentPath, ignorePaths)...)
+				continue
+			}
 			diffs = append(diffs, fmt.Sprintf("- %s: %v", currentPath, summarizeValue(current[key])))
 		}
 	}
@@ -225,198 +366,109 @@ func compareObjects(current, desired map[string]any, path string) []string {
 	return diffs
 }
 
-// stripServerManagedFields removes fields that the API server adds or owns
-// from a deep copy of the input. Returning a copy avoids mutating the
-// caller's map. Only top-level structural fields are walked; nested
-// 'metadata' keys are removed in place.
-func stripServerManagedFields(in map[string]any) map[string]any {
-	if in == nil {
-		return nil
-	}
-	out := make(map[string]any, len(in))
-	for k, v := range in {
-		out[k] = v
-	}
-	// Drop the entire status subtree — it's controller-owned, never relevant
-	// for what the user is about to apply.
-	delete(out, "status")
-
-	if md, ok := out["metadata"].(map[string]any); ok {
-		mdCopy := make(map[string]any, len(md))
-		for k, v := range md {
-			mdCopy[k] = v
-		}
-		// Server-managed metadata fields. Removing them on BOTH sides means
-		// they cannot show up as diffs.
-		for _, f := range []string{
-			"resourceVersion",
-			"uid",
-			"creationTimestamp",
-			"deletionTimestamp",
-			"deletionGracePeriodSeconds",
-			"generation",
-			"managedFields",
-			"selfLink",
-			"finalizers",
-			"ownerReferences",
-		} {
-			delete(mdCopy, f)
-		}
-		// last-applied-configuration is added by `kubectl apply`. It always
-		// represents the previous version, never the current one — strip it.
-		if ann, ok := mdCopy["annotations"].(map[string]any); ok {
-			annCopy := make(map[string]any, len(ann))
-			for k, v := range ann {
-				if k == "kubectl.kubernetes.io/last-applied-configuration" {
-					continue
-				}
-				annCopy[k] = v
-			}
-			if len(annCopy) == 0 {
-				delete(mdCopy, "annotations")
-			} else {
-				mdCopy["annotations"] = annCopy
-			}
+// compareValues compares one field present on both sides, recursing into
+// maps and lists.
+func compareValues(currentVal, desiredVal any, path string, ignorePaths []string) []string {
+	switch dv := desiredVal.(type) {
+	case map[string]any:
+		if cv, ok := currentVal.(map[string]any); ok {
+			return compareObjects(cv, dv, path, ignorePaths)
 		}
-		out["metadata"] = mdCopy
-	}
-
-	// Service: clusterIP / clusterIPs / ipFamilies are server-assigned the
-	// first time and immutable thereafter. Stripping them from current avoids
-	// "removed" diffs when the user submits a manifest without them.
-	kind, _ := out["kind"].(string)
-	if kind == "Service" {
-		if spec, ok := out["spec"].(map[string]any); ok {
-			specCopy := make(map[string]any, len(spec))
-			for k, v := range spec {
-				specCopy[k] = v
-			}
-			delete(specCopy, "clusterIP")
-			delete(specCopy, "clusterIPs")
-			delete(specCopy, "ipFamilies")
-			delete(specCopy, "ipFamilyPolicy")
-			out["spec"] = specCopy
+		return []string{fmt.Sprintf("~ %s: type changed", path)}
+	case []any:
+		if cv, ok := currentVal.([]any); ok {
+			return compareLists(cv, dv, path, ignorePaths)
 		}
-	}
-	if kind == "PersistentVolumeClaim" {
-		if spec, ok := out["spec"].(map[string]any); ok {
-			specCopy := make(map[string]any, len(spec))
-			for k, v := range spec {
-				specCopy[k] = v
-			}
-			delete(specCopy, "volumeName")
-			out["spec"] = specCopy
+		return []string{fmt.Sprintf("~ %s: type changed", path)}
+	default:
+		if !scalarsEqual(currentVal, desiredVal) {
+			return []string{fmt.Sprintf("~ %s: %v -> %v", path, summarizeValue(currentVal), summarizeValue(desiredVal))}
 		}
+		return nil
 	}
-
-	return out
 }
 
-func summarizeValue(v any) string {
-	switch val := v.(type) {
-	case string:
-		if len(val) > 50 {
-			return fmt.Sprintf("%q...", val[:50])
+// compareLists compares two lists element by element, in order: Kubernetes
+// gives order meaning in most lists (container args, init containers,
+// tolerations), so a reordered list is a change. Elements are addressed as
+// 'path[i]'.
+func compareLists(current, desired []any, path string, ignorePaths []string) []string {
+	var diffs []string
+	for i := 0; i < max(len(current), len(desired)); i++ {
+		elemPath := fmt.Sprintf("%s[%d]", path, i)
+		if pathIgnored(elemPath, ignorePaths) {
+			continue
+		}
+		switch {
+		case i >= len(current):
+			diffs = append(diffs, fmt.Sprintf("+ %s: %v", elemPath, summarizeValue(desired[i])))
+		case i >= len(desired):
+			diffs = append(diffs, fmt.Sprintf("- %s: %v", elemPath, summarizeValue(current[i])))
+		default:
+			diffs = append(diffs, compareValues(current[i], desired[i], elemPath, ignorePaths)...)
 		}
-		return fmt.Sprintf("%q", val)
-	case map[string]any:
-		return fmt.Sprintf("{...%d keys}", len(val))
-	case []any:
-		return fmt.Sprintf("[...%d items]", len(val))
-	default:
-		return fmt.Sprintf("%v", val)
 	}
+	return diffs
 }
 
-// slicesEqualNormalized compares two slices for content equality, ignoring
-// order when all elements are scalars or maps with a 'name' key (a common
-// pattern in container ports / env / volumeMounts). For mixed or nested-list
-// content we fall back to ordered comparison.
-func slicesEqualNormalized(a, b []any) bool {
-	if len(a) != len(b) {
-		return false
-	}
-	if isUnorderedScalar(a) && isUnorderedScalar(b) {
-		return scalarSetEqual(a, b)
+// scalarsEqual compares two scalar values. Numbers compare by value: the live
+// object decodes integers as int64 while a parsed manifest holds float64.
+func scalarsEqual(a, b any) bool {
+	if fa, ok := numberValue(a); ok {
+		fb, ok := numberValue(b)
+		return ok && fa == fb
 	}
-	if isNamedMapList(a) && isNamedMapList(b) {
-		return namedMapListEqual(a, b)
-	}
-	// Fallback: ordered, stringified compare.
-	for i := range a {
-		if fmt.Sprintf("%v", a[i]) != fmt.Sprintf("%v", b[i]) {
-			return false
-		}
-	}
-	return true
+	return a == b
 }
 
-func isUnorderedScalar(s []any) bool {
-	for _, v := range s {
-		switch v.(type) {
-		case string, float64, int64, int, bool, nil:
-		default:
-			return false
-		}
-	}
-	return true
+func numberValue(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
 }
 
-func scalarSetEqual(a, b []any) bool {
-	count := func(in []any) map[string]int {
-		m := make(map[string]int, len(in))
-		for _, v := range in {
-			m[fmt.Sprintf("%v", v)]++
-		}
-		return m
-	}
-	ca, cb := count(a), count(b)
-	if len(ca) != len(cb) {
-		return false
-	}
-	for k, v := range ca {
-		if cb[k] != v {
-			return false
+// pathIgnored reports whether a dotted diff path equals or sits below one of
+// the ignored paths. Matching is on the printed path, so map keys containing
+// dots (annotations, labels) need no escaping.
+func pathIgnored(path string, ignorePaths []string) bool {
+	for _, p := range ignorePaths {
+		if path == p || strings.HasPrefix(path, p+".") || strings.HasPrefix(path, p+"[") {
+			return true
 		}
 	}
-	return true
+	return false
 }
 
-func isNamedMapList(s []any) bool {
-	for _, v := range s {
-		m, ok := v.(map[string]any)
-		if !ok {
-			return false
-		}
-		if _, hasName := m["name"]; !hasName {
-			return false
+// ignoresBelow reports whether some ignored path lies strictly below 'path'
+func ignoresBelow(path string, ignorePaths []string) bool {
+	for _, p := range ignorePaths {
+		if strings.HasPrefix(p, path+".") || strings.HasPrefix(p, path+"[") {
+			return true
 		}
 	}
-	return true
+	return false
 }
 
-func namedMapListEqual(a, b []any) bool {
-	index := func(in []any) map[string]any {
-		out := make(map[string]any, len(in))
-		for _, v := range in {
-			m := v.(map[string]any)
-			name, _ := m["name"].(string)
-			out[name] = m
-		}
-		return out
-	}
-	ia, ib := index(a), index(b)
-	if len(ia) != len(ib) {
-		return false
-	}
-	for k, va := range ia {
-		vb, ok := ib[k]
-		if !ok {
-			return false
-		}
-		if fmt.Sprintf("%v", va) != fmt.Sprintf("%v", vb) {
-			return false
+func summarizeValue(v any) string {
+	switch val := v.(type) {
+	case string:
+		if len(val) > 50 {
+			return fmt.Sprintf("%q...", val[:50])
 		}
+		return fmt.Sprintf("%q", val)
+	case map[string]any:
+		return fmt.Sprintf("{...%d keys}", len(val))
+	case []any:
+		return fmt.Sprintf("[...%d items]", len(val))
+	default:
+		return fmt.Sprintf("%v", val)
 	}
-	return true
 }
@@ -0,0 +1,307 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestCompareObjects_ServerInjectedAnnotations(t *testing.T) {
+	current := map[string]any{
+		"metadata": map[string]any{
+			"name": "web",
+			"annotations": map[string]any{
+				"deployment.kubernetes.io/revision":                "4",
+				"kubectl.kubernetes.io/last-applied-configuration": "{}",
+				"team": "core",
+			},
+		},
+	}
+	desired := map[string]any{
+		"metadata": map[string]any{
+			"name":        "web",
+			"annotations": map[string]any{"team": "core"},
+		},
+	}
+
+	if diff := compareObjects(current, desired, "", nil); len(diff) != 0 {
+		t.Fatalf("expected no diff, got %v", diff)
+	}
+}
+
+func TestCompareObjects_IgnorePaths(t *testing.T) {
+	current := map[string]any{
+		"metadata": map[string]any{
+			"name":        "web",
+			"annotations": map[string]any{"argocd.argoproj.io/tracking-id": "a"},
+		},
+		"spec": map[string]any{"replicas": float64(5), "paused": false},
+	}
+	desired := map[string]any{
+		"metadata": map[string]any{"name": "web"},
+		"spec":     map[string]any{"replicas": float64(2), "paused": true},
+	}
+
+	diff := compareObjects(current, desired, "", []string{"spec.replicas", "metadata.annotations.argocd.argoproj.io/tracking-id"})
+	if len(diff) != 1 || diff[0] != "~ spec.paused: false -> true" {
+		t.Fatalf("expected only the paused change, got %v", diff)
+	}
+
+	// A parent path ignores everything below it
+	if diff := compareObjects(current, desired, "", []string{"spec", "metadata"}); len(diff) != 0 {
+		t.Fatalf("expected no diff, got %v", diff)
+	}
+}
+
+func TestCompareObjects_Lists(t *testing.T) {
+	container := func(name, image string) map[string]any {
+		return map[string]any{"name": name, "image": image}
+	}
+	current := map[string]any{
+		"spec": map[string]any{
+			"args":       []any{"--a", "--b"},
+			"containers": []any{container("app", "nginx:1.27"), container("sidecar", "envoy:1.30")},
+			"ports":      []any{int64(80)},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		desired map[string]any
+		want    []string
+	}{
+		{
+			name: "reordered scalars",
+			desired: map[string]any{"spec": map[string]any{
+				"args":       []any{"--b", "--a"},
+				"containers": current["spec"].(map[string]any)["containers"],
+				"ports":      []any{float64(80)},
+			}},
+			want: []string{`~ spec.args[0]: "--a" -> "--b"`, `~ spec.args[1]: "--b" -> "--a"`},
+		},
+		{
+			name: "nested change in a list element",
+			desired: map[string]any{"spec": map[string]any{
+				"args":       []any{"--a", "--b"},
+				"containers": []any{container("app", "nginx:1.28"), container("sidecar", "envoy:1.30")},
+				"ports":      []any{float64(80)},
+			}},
+			want: []string{`~ spec.containers[0].image: "nginx:1.27" -> "nginx:1.28"`},
+		},
+		{
+			name: "reordered named elements",
+			desired: map[string]any{"spec": map[string]any{
+				"args":       []any{"--a", "--b"},
+				"containers": []any{container("sidecar", "envoy:1.30"), container("app", "nginx:1.27")},
+				"ports":      []any{float64(80)},
+			}},
+			want: []string{
+				`~ spec.containers[0].image: "nginx:1.27" -> "envoy:1.30"`,
+				`~ spec.containers[0].name: "app" -> "sidecar"`,
+				`~ spec.containers[1].image: "envoy:1.30" -> "nginx:1.27"`,
+				`~ spec.containers[1].name: "sidecar" -> "app"`,
+			},
+		},
+		{
+			name: "added and removed elements",
+			desired: map[string]any{"spec": map[string]any{
+				"args":       []any{"--a"},
+				"containers": current["spec"].(map[string]any)["containers"],
+				"ports":      []any{float64(80), float64(443)},
+			}},
+			want: []string{`- spec.args[1]: "--b"`, `+ spec.ports[1]: 443`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := compareObjects(current, tt.desired, "", nil)
+			sort.Strings(got)
+			sort.Strings(tt.want)
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Fatalf("unexpected diff:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+
+	// An ignored list path covers its elements
+	desired := map[string]any{"spec": map[string]any{
+		"args":       []any{"--z"},
+		"containers": current["spec"].(map[string]any)["containers"],
+		"ports":      []any{float64(80)},
+	}}
+	if diff := compareObjects(current, desired, "", []string{"spec.args"}); len(diff) != 0 {
+		t.Fatalf("expected no diff, got %v", diff)
+	}
+}
+
+func TestUnifiedObjectDiff(t *testing.T) {
+	current := map[string]any{
+		"metadata": map[string]any{"name": "web", "resourceVersion": "12"},
+		"data":     map[string]any{"a": "1", "b": "2", "skip": "x"},
+	}
+	desired := map[string]any{
+		"metadata": map[string]any{"name": "web"},
+		"data":     map[string]any{"a": "1", "b": "3", "skip": "y"},
+	}
+
+	got, err := unifiedObjectDiff(current, desired, []string{"data.skip"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `--- current
++++ desired
+@@ -1,5 +1,5 @@
+ data:
+   a: "1"
+-  b: "2"
++  b: "3"
+ metadata:
+   name: web
+`
+	if got != want {
+		t.Fatalf("unexpected unified diff:\n%s", got)
+	}
+}
+
+func TestDiffManifest_IgnorePaths(t *testing.T) {
+	live := fakeDeployment("default", "web", 5)
+	live.Annotations = map[string]string{"deployment.kubernetes.io/revision": "3"}
+	e := newFakeEnv(t, live)
+	e.serveDryRun()
+
+	manifest := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: default
+  labels:
+    app: web
+spec:
+  replicas: 2
+  strategy: {}
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: app
+        image: nginx:1.27
+        resources: {}
+`
+
+	res, err := e.manager.handleDiffManifest(context.Background(), makeRequest(map[string]any{
+		"manifest": manifest,
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "diff_manifest")
+	requireContains(t, out, "~ spec.replicas: 5 -> 2", "expected replicas change")
+	requireContains(t, out, "-  replicas: 5\n+  replicas: 2", "expected the unified diff")
+	if strings.Contains(out, "- metadata.annotations") {
+		t.Fatalf("revision annotation should not be reported:\n%s", out)
+	}
+
+	res, err = e.manager.handleDiffManifest(context.Background(), makeRequest(map[string]any{
+		"manifest":     manifest,
+		"ignore_paths": []any{"spec.replicas"},
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out = expectOK(t, res, "diff_manifest ignore_paths")
+	requireContains(t, out, "No changes detected", "expected replicas to be ignored")
+}
+
+func TestDiffManifest_ServerSide(t *testing.T) {
+	live := fakeConfigMap("default", "settings", map[string]string{"level": "info"})
+	live.Labels = map[string]string{"injected-by": "webhook"}
+	e := newFakeEnv(t, live)
+	e.serveDryRun()
+
+	manifest := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: default
+data:
+  level: debug
+`
+	diff := func(args map[string]any) string {
+		t.Helper()
+		args["manifest"] = manifest
+		res, err := e.manager.handleDiffManifest(context.Background(), makeRequest(args))
+		if err != nil {
+			t.Fatalf("go-error: %v", err)
+		}
+		return expectOK(t, res, "diff_manifest")
+	}
+
+	// The label an admission webhook adds on every write is not a change
+	e.dynamic.PrependReactor("update", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		update := action.(k8stesting.UpdateActionImpl)
+		if !slices.Contains(update.UpdateOptions.DryRun, metav1.DryRunAll) {
+			t.Fatalf("diff_manifest must never persist an update")
+		}
+		obj := update.GetObject().(*unstructured.Unstructured).DeepCopy()
+		obj.SetLabels(map[string]string{"injected-by": "webhook"})
+		return true, obj, nil
+	})
+	out := diff(map[string]any{})
+	requireContains(t, out, "(compared against the server-side dry run)", "expected the mode")
+	requireContains(t, out, `~ data.level: "info" -> "debug"`, "expected the data change")
+	if strings.Contains(out, "metadata.labels") {
+		t.Fatalf("the webhook label must not be reported as removed:\n%s", out)
+	}
+
+	out = diff(map[string]any{"server_side": false})
+	requireContains(t, out, "(compared against the submitted manifest)", "expected the offline mode")
+	requireContains(t, out, "- metadata.labels", "expected the offline diff to report the false positive")
+
+	// Without update permission the offline comparison is the fallback
+	e.dynamic.PrependReactor("update", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(gvrOf("", "v1", "configmaps").GroupResource(), "settings", fmt.Errorf("no update"))
+	})
+	out = diff(map[string]any{})
+	requireContains(t, out, "the server-side dry run was refused", "expected the fallback to be reported")
+	requireContains(t, out, `~ data.level: "info" -> "debug"`, "expected the offline diff")
+
+	// Any other rejection is the answer: the manifest would not apply
+	e.dynamic.PrependReactor("update", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewBadRequest("data.level: Invalid value")
+	})
+	res, err := e.manager.handleDiffManifest(context.Background(), makeRequest(map[string]any{"manifest": manifest}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	requireContains(t, expectErr(t, res, "rejected dry run"), "the server-side dry run rejected the manifest", "expected the rejection")
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"kubernetes-mcp/internal/authorization"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+const (
+	resourceEventsDefault = 50
+	resourceEventsMax     = 500
+)
+
+func (m *Manager) registerGetEventsForResource() {
+	tool := mcp.NewTool(m.toolName("get_events_for_resource"),
+		mcp.WithDescription(`List the events of one object, oldest first, as a table:
+LAST SEEN | TYPE | REASON | MESSAGE.
+
+Events are matched on the object's UID as well as its kind and name, so a
+Pod or Job recreated under the same name does not mix in the events of its
+predecessor. Repeated events show their count after the message.
+
+Prefer this over 'list_events' with a field selector when the question is
+about a single object ("why is this Pod not starting?").`),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("group", mcp.Description("API group of the object. Empty string \"\" for the core API. Examples: 'apps', 'batch'.")),
+		mcp.WithString("version", mcp.Description("API version, e.g. 'v1'. If empty or not served for the group, the preferred version is used.")),
+		mcp.WithString("resource", mcp.Required(), mcp.Description("Resource name in the API sense: lowercase plural ('pods', 'deployments', 'nodes'). NOT the Kind. Short names are accepted ('po', 'deploy', ...).")),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Name of the object.")),
+		mcp.WithString("namespace", mcp.Description("Namespace of the object. Leave empty for cluster-scoped resources, whose events are searched in all namespaces.")),
+		mcp.WithArray("types", mcp.Description("Filter by event type: any of 'Normal', 'Warning'. Empty or omitted means no type filter.")),
+		mcp.WithNumber("max_events", mcp.Description(fmt.Sprintf("Maximum events to return, keeping the most recent. Integer 1..%d. Defaults to %d.", resourceEventsMax, resourceEventsDefault))),
+	)
+	m.addTool(tool, m.handleGetEventsForResource)
+}
+
+func (m *Manager) handleGetEventsForResource(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	name, _ := args["name"].(string)
+	namespace, _ := args["namespace"].(string)
+	if name == "" {
+		return errorResult(fmt.Errorf("name is required")), nil
+	}
+	types := stringArgs(args, "types")
+
+	maxEvents := resourceEventsDefault
+	if v, ok := args["max_events"].(float64); ok {
+		if v < 1 || v > resourceEventsMax {
+			return errorResult(fmt.Errorf("max_events must be between 1 and %d, got %v", resourceEventsMax, v)), nil
+		}
+		maxEvents = int(v)
+	}
+
+	gvr, err := m.resolveGVRVersion(k8sContext, m.expandShortName(k8sContext, gvrFromArgs(args)))
+	if err != nil {
+		return errorResult(err), nil
+	}
+	if err := validateGVR(gvr); err != nil {
+		return errorResult(err), nil
+	}
+
+	// Check authorization: the object is read for its UID, then its events
+	// are listed
+	if err := m.checkAuthorization(request, "get_events_for_resource", k8sContext, namespace, authorization.ResourceInfo{
+		Group:    gvr.Group,
+		Version:  gvr.Version,
+		Resource: gvr.Resource,
+		Name:     name,
+	}); err != nil {
+		return errorResult(err), nil
+	}
+	if err := m.checkAuthorization(request, "get_events_for_resource", k8sContext, namespace, authorization.ResourceInfo{
+		Group:    "",
+		Version:  "v1",
+		Resource: "events",
+	}); err != nil {
+		return errorResult(err), nil
+	}
+
+	if namespace != "" && !m.clientManager.IsNamespaceAllowed(k8sContext, namespace) {
+		return errorResult(fmt.Errorf("namespace %s is not allowed in context %s", namespace, k8sContext)), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	var obj *unstructured.Unstructured
+	if namespace != "" {
+		obj, err = client.DynamicClient.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	} else {
+		obj, err = client.DynamicClient.Resource(gvr).Get(ctx, name, metav1.GetOptions{})
+	}
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	kind := obj.GetKind()
+	if kind == "" {
+		if kind, err = m.resolveKindForGVR(client, gvr); err != nil {
+			return errorResult(err), nil
+		}
+	}
+
+	events, err := client.Clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("involvedObject.name=%s,involvedObject.kind=%s,involvedObject.uid=%s", name, kind, obj.GetUID()),
+	})
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	// The selector is repeated client-side: not every client (fakes
+	// included) honours involvedObject.uid.
+	var matched []corev1.Event
+	for _, e := range events.Items {
+		if e.InvolvedObject.UID != obj.GetUID() || e.InvolvedObject.Kind != kind || e.InvolvedObject.Name != name {
+			continue
+		}
+		if len(types) > 0 && !containsFold(types, e.Type) {
+			continue
+		}
+		matched = append(matched, e)
+	}
+
+	label := fmt.Sprintf("%s/%s", kind, name)
+	if namespace != "" {
+		label = fmt.Sprintf("%s %s/%s", kind, namespace, name)
+	}
+	if len(matched) == 0 {
+		return successResult(fmt.Sprintf("No events found for %s (uid %s).", label, obj.GetUID())), nil
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		return eventTime(matched[i]).Before(eventTime(matched[j]))
+	})
+	var omitted int
+	if len(matched) > maxEvents {
+		omitted = len(matched) - maxEvents
+		matched = matched[len(matched)-maxEvents:]
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Events for %s (uid %s), oldest first:\n", label, obj.GetUID())
+	if omitted > 0 {
+		fmt.Fprintf(&sb, "(%d older events omitted, max_events=%d)\n", omitted, maxEvents)
+	}
+	sb.WriteString("\n")
+	sb.WriteString(renderEventTable(matched, time.Now()))
+	return successResult(strings.TrimRight(sb.String(), "\n")), nil
+}
+
+// renderEventTable lays events out as LAST SEEN | TYPE | REASON | MESSAGE,
+// with the repeat count appended to the message
+func renderEventTable(events []corev1.Event, now time.Time) string {
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "LAST SEEN\tTYPE\tREASON\tMESSAGE")
+	for _, e := range events {
+		lastSeen := tableNone
+		if t := eventTime(e); !t.IsZero() {
+			lastSeen = duration.HumanDuration(now.Sub(t)) + " ago"
+		}
+		message := strings.Join(strings.Fields(e.Message), " ")
+		if e.Count > 1 {
+			message += fmt.Sprintf(" (x%d)", e.Count)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", lastSeen, e.Type, e.Reason, message)
+	}
+	w.Flush()
+	return sb.String()
+}
+
+// containsFold reports whether list holds s, ignoring case
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestGetEventsForResource(t *testing.T) {
+	pod := fakePod("default", "web", nil)
+	pod.UID = "current-uid"
+
+	now := time.Now()
+	event := func(name, uid, reason string, age time.Duration) *corev1.Event {
+		e := repeatedEvent(name, 1, now.Add(-age), now.Add(-age))
+		e.InvolvedObject.UID = types.UID(uid)
+		e.Reason = reason
+		return e
+	}
+	unhealthy := event("web.unhealthy", "current-uid", "Unhealthy", time.Minute)
+	unhealthy.Count = 4
+	scheduled := event("web.scheduled", "current-uid", "Scheduled", 10*time.Minute)
+	scheduled.Type = corev1.EventTypeNormal
+	// Same name, earlier incarnation of the Pod
+	stale := event("web.stale", "previous-uid", "Killing", 5*time.Minute)
+
+	e := newFakeEnv(t, pod, unhealthy, scheduled, stale)
+
+	get := func(extra map[string]any) string {
+		t.Helper()
+		args := map[string]any{"version": "v1", "resource": "pods", "namespace": "default", "name": "web"}
+		for k, v := range extra {
+			args[k] = v
+		}
+		res, err := e.manager.handleGetEventsForResource(context.Background(), makeRequest(args))
+		if err != nil {
+			t.Fatalf("go-error: %v", err)
+		}
+		return expectOK(t, res, "get_events_for_resource")
+	}
+
+	out := get(nil)
+	lines := strings.Split(out, "\n")
+	if len(lines) != 5 {
+		t.Fatalf("expected a heading, a blank line, a header and 2 events, got:\n%s", out)
+	}
+	requireContains(t, lines[0], "Events for Pod default/web (uid current-uid)", "expected the heading")
+	if got := strings.Join(strings.Fields(lines[2]), " "); got != "LAST SEEN TYPE REASON MESSAGE" {
+		t.Fatalf("unexpected header %q", got)
+	}
+	if got := strings.Fields(lines[3]); strings.Join(got[:4], " ") != "10m ago Normal Scheduled" {
+		t.Fatalf("expected the oldest event first, got %q", lines[3])
+	}
+	requireContains(t, lines[4], "statuscode: 503 (x4)", "expected the repeat count")
+	if strings.Contains(out, "Killing") {
+		t.Fatalf("events of an earlier object with the same name must be left out:\n%s", out)
+	}
+
+	out = get(map[string]any{"types": []any{"warning"}, "max_events": float64(1)})
+	requireContains(t, out, "Unhealthy", "expected the warning")
+	if strings.Contains(out, "Scheduled") {
+		t.Fatalf("expected the Normal event filtered out:\n%s", out)
+	}
+
+	out = get(map[string]any{"max_events": float64(1)})
+	requireContains(t, out, "(1 older events omitted, max_events=1)", "expected the omitted count")
+}
@@ -0,0 +1,272 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"kubernetes-mcp/internal/authorization"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kube-openapi/pkg/spec3"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const openAPISchemaRefPrefix = "#/components/schemas/"
+
+func (m *Manager) registerExplainResource() {
+	tool := mcp.NewTool(m.toolName("explain_resource"),
+		mcp.WithDescription(`Show the schema of a resource kind or one of its fields, like 'kubectl explain'.
+
+Reads the OpenAPI v3 schema the cluster itself publishes, so it covers CRDs
+and the exact API version served. Returns the description and type of the
+kind (or of 'field_path') and its child fields with their types; required
+fields are marked '-required-'.
+
+Use it before writing a manifest or a patch to check that a field exists and
+what it expects, instead of guessing field names. Walk down one level at a
+time: 'spec', then 'spec.template.spec', ... Array fields are explained
+through their items ('spec.template.spec.containers.ports').`),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("group", mcp.Description("API group. Empty string \"\" for the core API.")),
+		mcp.WithString("version", mcp.Required(), mcp.Description("API version, e.g. 'v1'.")),
+		mcp.WithString("kind", mcp.Required(), mcp.Description("Kind, e.g. 'Deployment', 'Pod' or a CRD's kind. Case-insensitive.")),
+		mcp.WithString("field_path", mcp.Description("Dotted path to a field below the kind. Example: 'spec.template.spec.containers.resources'. Omit to explain the kind itself.")),
+	)
+	m.addTool(tool, m.handleExplainResource)
+}
+
+func (m *Manager) handleExplainResource(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	group, _ := args["group"].(string)
+	version, _ := args["version"].(string)
+	kind, _ := args["kind"].(string)
+	fieldPath, _ := args["field_path"].(string)
+	fieldPath = strings.Trim(strings.TrimSpace(fieldPath), ".")
+
+	if version == "" {
+		return errorResult(fmt.Errorf("version is required")), nil
+	}
+	if kind == "" {
+		return errorResult(fmt.Errorf("kind is required")), nil
+	}
+
+	// Check authorization (virtual resource: _/APIDiscovery)
+	if err := m.checkAuthorization(request, "explain_resource", k8sContext, "", authorization.ResourceInfo{
+		Group:    authorization.VirtualResourceGroup,
+		Resource: authorization.VirtualResourceAPIDiscovery,
+	}); err != nil {
+		return errorResult(err), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	gv := schema.GroupVersion{Group: group, Version: version}
+	doc, err := client.OpenAPIDocument(gv)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	gvk, root, err := findKindSchema(doc, gv, kind)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	// Walk the path; 'field' keeps the unresolved property schema because
+	// the field description often sits next to the $ref, not in the target.
+	field, node := root, resolveSchema(doc, root)
+	var walked []string
+	if fieldPath != "" {
+		for _, segment := range strings.Split(fieldPath, ".") {
+			node = elementSchema(doc, node)
+			child, ok := node.Properties[segment]
+			if !ok {
+				parent := gvk.Kind
+				if len(walked) > 0 {
+					parent = strings.Join(walked, ".")
+				}
+				return errorResult(fmt.Errorf("field %q does not exist in %s %s; fields of %s: %s",
+					strings.Join(append(walked, segment), "."), gv, gvk.Kind, parent, strings.Join(sortedProperties(node), ", "))), nil
+			}
+			walked = append(walked, segment)
+			field, node = &child, resolveSchema(doc, &child)
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "KIND:    %s\nVERSION: %s\n\n", gvk.Kind, gv)
+	if fieldPath != "" {
+		fmt.Fprintf(&sb, "FIELD: %s <%s>\n\n", fieldPath, schemaTypeName(doc, field))
+	}
+
+	description := field.Description
+	if description == "" {
+		description = node.Description
+	}
+	if description == "" {
+		description = "<empty>"
+	}
+	sb.WriteString("DESCRIPTION:\n")
+	for _, line := range strings.Split(description, "\n") {
+		fmt.Fprintf(&sb, "    %s\n", line)
+	}
+
+	node = elementSchema(doc, node)
+	if len(node.Properties) > 0 {
+		required := map[string]bool{}
+		for _, name := range node.Required {
+			required[name] = true
+		}
+		sb.WriteString("\nFIELDS:\n")
+		for _, name := range sortedProperties(node) {
+			child := node.Properties[name]
+			fmt.Fprintf(&sb, "  %s\t<%s>", name, schemaTypeName(doc, &child))
+			if required[name] {
+				sb.WriteString(" -required-")
+			}
+			sb.WriteString("\n")
+			childDescription := child.Description
+			if childDescription == "" {
+				childDescription = resolveSchema(doc, &child).Description
+			}
+			// The first paragraph only; explaining the field shows the rest
+			if summary, _, _ := strings.Cut(childDescription, "\n\n"); summary != "" {
+				fmt.Fprintf(&sb, "    %s\n", strings.ReplaceAll(summary, "\n", " "))
+			}
+		}
+	}
+
+	return successResult(sb.String()), nil
+}
+
+// findKindSchema returns the component schema tagged with the kind through
+// 'x-kubernetes-group-version-kind'.
+func findKindSchema(doc *spec3.OpenAPI, gv schema.GroupVersion, kind string) (schema.GroupVersionKind, *spec.Schema, error) {
+	var kinds []string
+	if doc.Components != nil {
+		for _, s := range doc.Components.Schemas {
+			for _, gvk := range schemaGVKs(s) {
+				if gvk.GroupVersion() != gv {
+					continue
+				}
+				if strings.EqualFold(gvk.Kind, kind) {
+					return gvk, s, nil
+				}
+				kinds = append(kinds, gvk.Kind)
+			}
+		}
+	}
+	sort.Strings(kinds)
+	return schema.GroupVersionKind{}, nil, fmt.Errorf("kind %q is not served in %s; kinds in the schema: %s", kind, gv, strings.Join(kinds, ", "))
+}
+
+// schemaGVKs reads the 'x-kubernetes-group-version-kind' extension
+func schemaGVKs(s *spec.Schema) []schema.GroupVersionKind {
+	entries, _ := s.Extensions["x-kubernetes-group-version-kind"].([]any)
+	gvks := make([]schema.GroupVersionKind, 0, len(entries))
+	for _, entry := range entries {
+		fields, ok := entry.(map[string]any)
+		if !ok {
+			continue
+		}
+		group, _ := fields["group"].(string)
+		version, _ := fields["version"].(string)
+		kind, _ := fields["kind"].(string)
+		gvks = append(gvks, schema.GroupVersionKind{Group: group, Version: version, Kind: kind})
+	}
+	return gvks
+}
+
+// resolveSchema follows a $ref, or the single-entry allOf the API server
+// wraps refs in, to the component schema it points to.
+func resolveSchema(doc *spec3.OpenAPI, s *spec.Schema) *spec.Schema {
+	for range 10 {
+		ref := schemaRef(s)
+		if ref == "" || doc.Components == nil {
+			return s
+		}
+		target, ok := doc.Components.Schemas[strings.TrimPrefix(ref, openAPISchemaRefPrefix)]
+		if !ok {
+			return s
+		}
+		s = target
+	}
+	return s
+}
+
+// schemaRef returns the $ref of a schema or of its single allOf entry
+func schemaRef(s *spec.Schema) string {
+	if ref := s.Ref.String(); ref != "" {
+		return ref
+	}
+	if len(s.AllOf) == 1 {
+		return s.AllOf[0].Ref.String()
+	}
+	return ""
+}
+
+// elementSchema steps through arrays to the schema of their items, the way
+// 'kubectl explain' lets 'containers.ports' address a list's elements.
+func elementSchema(doc *spec3.OpenAPI, s *spec.Schema) *spec.Schema {
+	for s.Type.Contains("array") && s.Items != nil && s.Items.Schema != nil {
+		s = resolveSchema(doc, s.Items.Schema)
+	}
+	return s
+}
+
+// schemaTypeName renders a schema type the way 'kubectl explain' does:
+// 'integer', 'DeploymentSpec', '[]Container', 'map[string]string'.
+func schemaTypeName(doc *spec3.OpenAPI, s *spec.Schema) string {
+	if ref := schemaRef(s); ref != "" {
+		name := strings.TrimPrefix(ref, openAPISchemaRefPrefix)
+		return name[strings.LastIndex(name, ".")+1:]
+	}
+	switch {
+	case s.Type.Contains("array"):
+		if s.Items != nil && s.Items.Schema != nil {
+			return "[]" + schemaTypeName(doc, s.Items.Schema)
+		}
+		return "[]Object"
+	case s.Type.Contains("object") && s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil:
+		return "map[string]" + schemaTypeName(doc, s.AdditionalProperties.Schema)
+	case len(s.Type) > 0:
+		return s.Type[0]
+	case s.Extensions["x-kubernetes-int-or-string"] == true:
+		return "IntOrString"
+	default:
+		return "Object"
+	}
+}
+
+// sortedProperties returns the property names of a schema, sorted
+func sortedProperties(s *spec.Schema) []string {
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
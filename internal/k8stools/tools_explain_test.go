@@ -0,0 +1,119 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"k8s.io/client-go/openapi"
+	"k8s.io/client-go/openapi/openapitest"
+)
+
+// widgetOpenAPI is the OpenAPI v3 document of a CRD as the API server
+// publishes it: the schema inline, tagged with its group/version/kind.
+const widgetOpenAPI = `{
+  "openapi": "3.0.0",
+  "info": {"title": "Kubernetes CRD Swagger", "version": "v0.1.0"},
+  "paths": {},
+  "components": {"schemas": {
+    "com.example.v1.Widget": {
+      "type": "object",
+      "description": "Widget is a test CRD.",
+      "x-kubernetes-group-version-kind": [{"group": "example.com", "kind": "Widget", "version": "v1"}],
+      "properties": {
+        "spec": {
+          "type": "object",
+          "description": "Desired widget.",
+          "required": ["size"],
+          "properties": {
+            "size": {"type": "integer", "description": "Size in units.\n\nMore detail."},
+            "tags": {"type": "object", "additionalProperties": {"type": "string"}}
+          }
+        }
+      }
+    }
+  }}
+}`
+
+func TestExplainResource(t *testing.T) {
+	explain := func(t *testing.T, client openapi.Client, args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		e := newFakeEnv(t)
+		e.provider.client.OpenAPIClient = client
+		res, err := e.manager.handleExplainResource(context.Background(), makeRequest(args))
+		if err != nil {
+			t.Fatalf("go-error: %v", err)
+		}
+		return res
+	}
+
+	t.Run("built-in kind", func(t *testing.T) {
+		out := expectOK(t, explain(t, openapitest.NewEmbeddedFileClient(), map[string]any{
+			"group": "apps", "version": "v1", "kind": "deployment",
+		}), "explain deployment")
+		requireContains(t, out, "KIND:    Deployment\nVERSION: apps/v1", "expected the header")
+		requireContains(t, out, "spec\t<DeploymentSpec>", "expected ref types rendered by name")
+	})
+
+	t.Run("field path", func(t *testing.T) {
+		out := expectOK(t, explain(t, openapitest.NewEmbeddedFileClient(), map[string]any{
+			"group": "apps", "version": "v1", "kind": "Deployment", "field_path": "spec.template.spec.containers",
+		}), "explain containers")
+		requireContains(t, out, "FIELD: spec.template.spec.containers <[]Container>", "expected the field type")
+		requireContains(t, out, "name\t<string> -required-", "expected the item fields with required markers")
+	})
+
+	t.Run("missing field", func(t *testing.T) {
+		out := expectErr(t, explain(t, openapitest.NewEmbeddedFileClient(), map[string]any{
+			"group": "apps", "version": "v1", "kind": "Deployment", "field_path": "spec.replicaz",
+		}), "explain missing field")
+		requireContains(t, out, `field "spec.replicaz" does not exist in apps/v1 Deployment`, "expected the bad path")
+		requireContains(t, out, "replicas", "expected the valid siblings")
+	})
+
+	t.Run("unknown kind", func(t *testing.T) {
+		out := expectErr(t, explain(t, openapitest.NewEmbeddedFileClient(), map[string]any{
+			"group": "apps", "version": "v1", "kind": "Deploymnt",
+		}), "explain unknown kind")
+		requireContains(t, out, "StatefulSet", "expected the available kinds")
+	})
+
+	t.Run("CRD", func(t *testing.T) {
+		client := &openapitest.FakeClient{PathsMap: map[string]openapi.GroupVersion{
+			"apis/example.com/v1": openapitest.FakeGroupVersion{GVSpec: []byte(widgetOpenAPI)},
+		}}
+		out := expectOK(t, explain(t, client, map[string]any{
+			"group": "example.com", "version": "v1", "kind": "Widget", "field_path": "spec",
+		}), "explain widget")
+		requireContains(t, out, "Desired widget.", "expected the field description")
+		requireContains(t, out, "size\t<integer> -required-\n    Size in units.\n", "expected the first paragraph of the child description")
+		requireContains(t, out, "tags\t<map[string]string>", "expected map types")
+		if strings.Contains(out, "More detail.") {
+			t.Fatalf("child descriptions should stop at the first paragraph:\n%s", out)
+		}
+	})
+
+	t.Run("group not served", func(t *testing.T) {
+		out := expectErr(t, explain(t, openapitest.NewFakeClient(), map[string]any{
+			"group": "example.com", "version": "v2", "kind": "Widget",
+		}), "explain unserved group")
+		requireContains(t, out, "no OpenAPI v3 schema for example.com/v2", "expected the missing group/version")
+	})
+}
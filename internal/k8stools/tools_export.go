@@ -0,0 +1,114 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/package k8stools
+
+import (
+	"context"
+	"fmt"
+
+	"kubernetes-mcp/internal/authorization"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func (m *Manager) registerExportResource() {
+	tool := mcp.NewTool(m.toolName("export_resource"),
+		mcp.WithDescription(`Fetch ONE Kubernetes resource as a clean, reapplyable manifest
+(what 'kubectl get -o yaml --export' used to give).
+
+Unlike 'get_resource', the server-managed parts are stripped: status,
+resourceVersion, uid, creationTimestamp, generation, managedFields,
+ownerReferences, finalizers, the last-applied-configuration annotation and
+server-assigned fields such as a Service's clusterIP. Use it to copy an
+object into a new manifest for 'apply_manifest' or 'create_resource'.
+
+Fields masked by redaction stay masked and must be filled in before
+applying.`),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("group", mcp.Description("API group. Empty string \"\" for the core API. Examples: 'apps', 'networking.k8s.io'.")),
+		mcp.WithString("version", mcp.Description("API version, e.g. 'v1'. If empty or not served for the group, the preferred version is used.")),
+		mcp.WithString("resource", mcp.Required(), mcp.Description("Resource name in the API sense: lowercase plural ('configmaps', 'deployments'). Short names are accepted.")),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Name of the resource instance.")),
+		mcp.WithString("namespace", mcp.Description("Namespace of the resource. Required for namespaced resources.")),
+		mcp.WithBoolean("strip_namespace", mcp.Description("If true, metadata.namespace is removed too, so the manifest applies to whichever namespace it is applied in. Defaults to false.")),
+		mcp.WithArray("yq_expressions", mcp.Description("Optional yq expressions applied in order to the exported YAML.")),
+		jqExpressionsParam(),
+		outputFormatParam(),
+	)
+	m.addTool(tool, m.handleExportResource)
+}
+
+func (m *Manager) handleExportResource(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	name, _ := args["name"].(string)
+	namespace, _ := args["namespace"].(string)
+	stripNamespace, _ := args["strip_namespace"].(bool)
+	gvr, err := m.resolveGVRVersion(k8sContext, m.expandShortName(k8sContext, gvrFromArgs(args)))
+	if err != nil {
+		return errorResult(err), nil
+	}
+	if err := validateGVR(gvr); err != nil {
+		return errorResult(err), nil
+	}
+
+	// Check authorization
+	if err := m.checkAuthorization(request, "export_resource", k8sContext, namespace, authorization.ResourceInfo{
+		Group:    gvr.Group,
+		Version:  gvr.Version,
+		Resource: gvr.Resource,
+		Name:     name,
+	}); err != nil {
+		return errorResult(err), nil
+	}
+
+	if namespace != "" && !m.clientManager.IsNamespaceAllowed(k8sContext, namespace) {
+		return errorResult(fmt.Errorf("namespace %s is not allowed in context %s", namespace, k8sContext)), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	var live *unstructured.Unstructured
+	if namespace != "" {
+		live, err = client.DynamicClient.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	} else {
+		live, err = client.DynamicClient.Resource(gvr).Get(ctx, name, metav1.GetOptions{})
+	}
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	exported := stripServerManagedFields(live.Object)
+	if md, ok := exported["metadata"].(map[string]any); ok && stripNamespace {
+		delete(md, "namespace")
+	}
+
+	yamlOutput, err := objectToYAML(exported)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	finalOutput, err := m.formatOutput(yamlOutput, args, live.GetKind())
+	if err != nil {
+		return errorResult(err), nil
+	}
+	return successResult(finalOutput), nil
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/package k8stools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestExportResource(t *testing.T) {
+	svc := &corev1.Service{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "default",
+			Name:            "web",
+			UID:             "1234",
+			ResourceVersion: "42",
+			Labels:          map[string]string{"app": "web"},
+			Annotations: map[string]string{
+				"kubectl.kubernetes.io/last-applied-configuration": "{}",
+				"team": "payments",
+			},
+			Finalizers: []string{"service.kubernetes.io/load-balancer-cleanup"},
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: "10.0.0.12",
+			Selector:  map[string]string{"app": "web"},
+			Ports:     []corev1.ServicePort{{Port: 80}},
+		},
+		Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{{IP: "1.2.3.4"}}}},
+	}
+	e := newFakeEnv(t, svc)
+
+	export := func(extra map[string]any) string {
+		t.Helper()
+		args := map[string]any{"version": "v1", "resource": "services", "namespace": "default", "name": "web"}
+		for k, v := range extra {
+			args[k] = v
+		}
+		res, err := e.manager.handleExportResource(context.Background(), makeRequest(args))
+		if err != nil {
+			t.Fatalf("go-error: %v", err)
+		}
+		return expectOK(t, res, "export_resource")
+	}
+
+	out := export(nil)
+	requireContains(t, out, "team: payments", "expected user annotations to be kept")
+	requireContains(t, out, "namespace: default", "expected the namespace to be kept by default")
+	for _, field := range []string{"uid:", "resourceVersion:", "last-applied-configuration", "finalizers:", "clusterIP:", "status:", "1.2.3.4"} {
+		if strings.Contains(out, field) {
+			t.Errorf("expected %s to be stripped from the export:\n%s", field, out)
+		}
+	}
+
+	out = export(map[string]any{"strip_namespace": true})
+	if strings.Contains(out, "namespace:") {
+		t.Errorf("expected strip_namespace to remove metadata.namespace:\n%s", out)
+	}
+
+}
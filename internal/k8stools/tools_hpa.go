@@ -0,0 +1,325 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"kubernetes-mcp/internal/authorization"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// hpaMaxEvents bounds the scaling events reported by describe_hpa
+const hpaMaxEvents = 15
+
+func (m *Manager) registerDescribeHPA() {
+	tool := mcp.NewTool(m.toolName("describe_hpa"),
+		mcp.WithDescription(`Explain what a HorizontalPodAutoscaler is doing and why.
+
+Reports the scale target, min / max replicas, current vs desired replicas,
+every metric with its current value next to its target, the status
+conditions that gate scaling ('AbleToScale', 'ScalingActive',
+'ScalingLimited') and the most recent events of the HPA (rescales,
+FailedGetResourceMetric, ...).
+
+Use this to answer "why isn't my HPA scaling?": a 'ScalingActive=False'
+condition usually means metrics are missing, 'ScalingLimited=True' means
+min/max is clamping the desired count.`),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Name of the HorizontalPodAutoscaler.")),
+		mcp.WithString("namespace", mcp.Required(), mcp.Description("Namespace where the HorizontalPodAutoscaler lives.")),
+		mcp.WithArray("yq_expressions", mcp.Description("Optional yq expressions applied to the YAML output. Examples: '.metrics' (current vs target per metric), '.conditions[] | select(.status == \"False\")'.")),
+		jqExpressionsParam(),
+	)
+	m.addTool(tool, m.handleDescribeHPA)
+}
+
+func (m *Manager) handleDescribeHPA(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	name, _ := args["name"].(string)
+	namespace, _ := args["namespace"].(string)
+
+	if name == "" || namespace == "" {
+		return errorResult(fmt.Errorf("'name' and 'namespace' are required")), nil
+	}
+
+	// Check authorization (real K8s resource: HorizontalPodAutoscaler)
+	if err := m.checkAuthorization(request, "describe_hpa", k8sContext, namespace, authorization.ResourceInfo{
+		Group:    "autoscaling",
+		Version:  "v2",
+		Resource: "horizontalpodautoscalers",
+		Name:     name,
+	}); err != nil {
+		return errorResult(err), nil
+	}
+
+	if !m.clientManager.IsNamespaceAllowed(k8sContext, namespace) {
+		return errorResult(fmt.Errorf("namespace %s is not allowed in context %s", namespace, k8sContext)), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	hpa, err := client.Clientset.AutoscalingV2().HorizontalPodAutoscalers(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	summary := summarizeHPA(hpa)
+
+	// Events are best effort, as in describe_resource: the HPA summary is
+	// still useful without them.
+	events, err := client.Clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("involvedObject.name=%s,involvedObject.kind=HorizontalPodAutoscaler", name),
+	})
+	if err == nil {
+		summary["events"] = summarizeHPAEvents(events.Items, name)
+	}
+
+	yamlOutput, err := objectToYAML(summary)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	finalOutput, err := m.applyOutputFilters(yamlOutput, args)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	return successResult(finalOutput), nil
+}
+
+// summarizeHPA flattens an autoscaling/v2 HPA into the fields needed to
+// reason about its scaling decisions.
+func summarizeHPA(hpa *autoscalingv2.HorizontalPodAutoscaler) map[string]any {
+	minReplicas := int32(1)
+	if hpa.Spec.MinReplicas != nil {
+		minReplicas = *hpa.Spec.MinReplicas
+	}
+
+	// Current values are reported per metric in status.currentMetrics; pair
+	// them with the spec entries by their description.
+	current := make(map[string]string, len(hpa.Status.CurrentMetrics))
+	for _, status := range hpa.Status.CurrentMetrics {
+		current[metricStatusName(status)] = metricValueString(metricStatusValue(status))
+	}
+
+	metrics := make([]map[string]any, 0, len(hpa.Spec.Metrics))
+	for _, spec := range hpa.Spec.Metrics {
+		metricName := metricSpecName(spec)
+		entry := map[string]any{
+			"type":   string(spec.Type),
+			"name":   metricName,
+			"target": metricTargetString(metricSpecTarget(spec)),
+		}
+		if value, ok := current[metricName]; ok {
+			entry["current"] = value
+		} else {
+			entry["current"] = "<unknown>"
+		}
+		metrics = append(metrics, entry)
+	}
+
+	conditions := make([]map[string]any, 0, len(hpa.Status.Conditions))
+	for _, c := range hpa.Status.Conditions {
+		entry := map[string]any{
+			"type":   string(c.Type),
+			"status": string(c.Status),
+			"reason": c.Reason,
+		}
+		if c.Message != "" {
+			entry["message"] = c.Message
+		}
+		conditions = append(conditions, entry)
+	}
+
+	summary := map[string]any{
+		"name":             hpa.Name,
+		"namespace":        hpa.Namespace,
+		"target_ref":       fmt.Sprintf("%s/%s", hpa.Spec.ScaleTargetRef.Kind, hpa.Spec.ScaleTargetRef.Name),
+		"min_replicas":     minReplicas,
+		"max_replicas":     hpa.Spec.MaxReplicas,
+		"current_replicas": hpa.Status.CurrentReplicas,
+		"desired_replicas": hpa.Status.DesiredReplicas,
+		"metrics":          metrics,
+		"conditions":       conditions,
+	}
+	if hpa.Status.LastScaleTime != nil {
+		summary["last_scale_time"] = hpa.Status.LastScaleTime.UTC().Format("2006-01-02T15:04:05Z")
+	}
+	return summary
+}
+
+// summarizeHPAEvents returns the newest events of the named HPA. The field
+// selector already filters server-side; the check is repeated here because
+// not every client (fakes included) honours it.
+func summarizeHPAEvents(events []corev1.Event, name string) []map[string]any {
+	var matching []corev1.Event
+	for _, e := range events {
+		if e.InvolvedObject.Kind == "HorizontalPodAutoscaler" && e.InvolvedObject.Name == name {
+			matching = append(matching, e)
+		}
+	}
+	sort.SliceStable(matching, func(i, j int) bool {
+		return eventTime(matching[i]).After(eventTime(matching[j]))
+	})
+	if len(matching) > hpaMaxEvents {
+		matching = matching[:hpaMaxEvents]
+	}
+
+	out := make([]map[string]any, 0, len(matching))
+	for _, e := range matching {
+		entry := map[string]any{
+			"type":    e.Type,
+			"reason":  e.Reason,
+			"message": e.Message,
+			"count":   e.Count,
+		}
+		if t := eventTime(e); !t.IsZero() {
+			entry["last_seen"] = t.UTC().Format("2006-01-02T15:04:05Z")
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+// metricSpecName describes which metric a spec entry targets, in the same
+// form as metricStatusName so the two can be paired.
+func metricSpecName(spec autoscalingv2.MetricSpec) string {
+	switch spec.Type {
+	case autoscalingv2.ResourceMetricSourceType:
+		if spec.Resource != nil {
+			return string(spec.Resource.Name)
+		}
+	case autoscalingv2.ContainerResourceMetricSourceType:
+		if spec.ContainerResource != nil {
+			return fmt.Sprintf("%s (container %s)", spec.ContainerResource.Name, spec.ContainerResource.Container)
+		}
+	case autoscalingv2.PodsMetricSourceType:
+		if spec.Pods != nil {
+			return spec.Pods.Metric.Name
+		}
+	case autoscalingv2.ObjectMetricSourceType:
+		if spec.Object != nil {
+			return fmt.Sprintf("%s (%s/%s)", spec.Object.Metric.Name, spec.Object.DescribedObject.Kind, spec.Object.DescribedObject.Name)
+		}
+	case autoscalingv2.ExternalMetricSourceType:
+		if spec.External != nil {
+			return spec.External.Metric.Name
+		}
+	}
+	return string(spec.Type)
+}
+
+// metricSpecTarget returns the target of a spec entry, whatever its type
+func metricSpecTarget(spec autoscalingv2.MetricSpec) autoscalingv2.MetricTarget {
+	switch {
+	case spec.Resource != nil:
+		return spec.Resource.Target
+	case spec.ContainerResource != nil:
+		return spec.ContainerResource.Target
+	case spec.Pods != nil:
+		return spec.Pods.Target
+	case spec.Object != nil:
+		return spec.Object.Target
+	case spec.External != nil:
+		return spec.External.Target
+	}
+	return autoscalingv2.MetricTarget{}
+}
+
+// metricStatusName is metricSpecName for status.currentMetrics entries
+func metricStatusName(status autoscalingv2.MetricStatus) string {
+	switch status.Type {
+	case autoscalingv2.ResourceMetricSourceType:
+		if status.Resource != nil {
+			return string(status.Resource.Name)
+		}
+	case autoscalingv2.ContainerResourceMetricSourceType:
+		if status.ContainerResource != nil {
+			return fmt.Sprintf("%s (container %s)", status.ContainerResource.Name, status.ContainerResource.Container)
+		}
+	case autoscalingv2.PodsMetricSourceType:
+		if status.Pods != nil {
+			return status.Pods.Metric.Name
+		}
+	case autoscalingv2.ObjectMetricSourceType:
+		if status.Object != nil {
+			return fmt.Sprintf("%s (%s/%s)", status.Object.Metric.Name, status.Object.DescribedObject.Kind, status.Object.DescribedObject.Name)
+		}
+	case autoscalingv2.ExternalMetricSourceType:
+		if status.External != nil {
+			return status.External.Metric.Name
+		}
+	}
+	return string(status.Type)
+}
+
+// metricStatusValue returns the current value of a status entry
+func metricStatusValue(status autoscalingv2.MetricStatus) autoscalingv2.MetricValueStatus {
+	switch {
+	case status.Resource != nil:
+		return status.Resource.Current
+	case status.ContainerResource != nil:
+		return status.ContainerResource.Current
+	case status.Pods != nil:
+		return status.Pods.Current
+	case status.Object != nil:
+		return status.Object.Current
+	case status.External != nil:
+		return status.External.Current
+	}
+	return autoscalingv2.MetricValueStatus{}
+}
+
+// metricTargetString renders a target the way 'kubectl describe hpa' does:
+// '80%' for utilization, the quantity otherwise ('(avg)' for per-Pod values).
+func metricTargetString(target autoscalingv2.MetricTarget) string {
+	switch {
+	case target.AverageUtilization != nil:
+		return fmt.Sprintf("%d%%", *target.AverageUtilization)
+	case target.AverageValue != nil:
+		return target.AverageValue.String() + " (avg)"
+	case target.Value != nil:
+		return target.Value.String()
+	}
+	return "<unset>"
+}
+
+// metricValueString renders a current value; utilization wins when the
+// controller reports both it and the raw average.
+func metricValueString(value autoscalingv2.MetricValueStatus) string {
+	switch {
+	case value.AverageUtilization != nil:
+		return fmt.Sprintf("%d%%", *value.AverageUtilization)
+	case value.AverageValue != nil:
+		return value.AverageValue.String() + " (avg)"
+	case value.Value != nil:
+		return value.Value.String()
+	}
+	return "<unknown>"
+}
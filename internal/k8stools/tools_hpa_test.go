@@ -0,0 +1,143 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func fakeHPA() *autoscalingv2.HorizontalPodAutoscaler {
+	minReplicas, cpuTarget, cpuCurrent := int32(2), int32(80), int32(95)
+	rps := resource.MustParse("100")
+	rpsCurrent := resource.MustParse("42")
+
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "autoscaling/v2", Kind: "HorizontalPodAutoscaler"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"},
+			MinReplicas:    &minReplicas,
+			MaxReplicas:    5,
+			Metrics: []autoscalingv2.MetricSpec{
+				{
+					Type: autoscalingv2.ResourceMetricSourceType,
+					Resource: &autoscalingv2.ResourceMetricSource{
+						Name:   corev1.ResourceCPU,
+						Target: autoscalingv2.MetricTarget{Type: autoscalingv2.UtilizationMetricType, AverageUtilization: &cpuTarget},
+					},
+				},
+				{
+					Type: autoscalingv2.PodsMetricSourceType,
+					Pods: &autoscalingv2.PodsMetricSource{
+						Metric: autoscalingv2.MetricIdentifier{Name: "http_requests_per_second"},
+						Target: autoscalingv2.MetricTarget{Type: autoscalingv2.AverageValueMetricType, AverageValue: &rps},
+					},
+				},
+			},
+		},
+		Status: autoscalingv2.HorizontalPodAutoscalerStatus{
+			CurrentReplicas: 5,
+			DesiredReplicas: 5,
+			CurrentMetrics: []autoscalingv2.MetricStatus{
+				{
+					Type: autoscalingv2.ResourceMetricSourceType,
+					Resource: &autoscalingv2.ResourceMetricStatus{
+						Name:    corev1.ResourceCPU,
+						Current: autoscalingv2.MetricValueStatus{AverageUtilization: &cpuCurrent},
+					},
+				},
+				{
+					Type: autoscalingv2.PodsMetricSourceType,
+					Pods: &autoscalingv2.PodsMetricStatus{
+						Metric:  autoscalingv2.MetricIdentifier{Name: "http_requests_per_second"},
+						Current: autoscalingv2.MetricValueStatus{AverageValue: &rpsCurrent},
+					},
+				},
+			},
+			Conditions: []autoscalingv2.HorizontalPodAutoscalerCondition{
+				{Type: autoscalingv2.ScalingLimited, Status: corev1.ConditionTrue, Reason: "TooManyReplicas", Message: "the desired replica count is more than the maximum replica count"},
+			},
+		},
+	}
+}
+
+func hpaEvent(name, reason string, at time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Namespace: "default", Name: name},
+		InvolvedObject: corev1.ObjectReference{Kind: "HorizontalPodAutoscaler", Name: "web", Namespace: "default"},
+		Type:           corev1.EventTypeNormal,
+		Reason:         reason,
+		LastTimestamp:  metav1.NewTime(at),
+	}
+}
+
+func TestDescribeHPA(t *testing.T) {
+	now := time.Now()
+	e := newFakeEnv(t,
+		fakeHPA(),
+		hpaEvent("web.1", "SuccessfulRescale", now.Add(-10*time.Minute)),
+		hpaEvent("web.2", "FailedGetPodsMetric", now.Add(-time.Minute)),
+	)
+
+	res, err := e.manager.handleDescribeHPA(context.Background(), makeRequest(map[string]any{
+		"namespace": "default",
+		"name":      "web",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "describe_hpa")
+	requireContains(t, out, "target_ref: Deployment/web", "expected target ref")
+	requireContains(t, out, "min_replicas: 2", "expected min replicas")
+	requireContains(t, out, "current: 95%", "expected cpu utilization")
+	requireContains(t, out, "target: 80%", "expected cpu target")
+	requireContains(t, out, "current: 42 (avg)", "expected pods metric value")
+	requireContains(t, out, "reason: TooManyReplicas", "expected ScalingLimited condition")
+
+	res, err = e.manager.handleDescribeHPA(context.Background(), makeRequest(map[string]any{
+		"namespace":      "default",
+		"name":           "web",
+		"yq_expressions": []any{".events[0].reason"},
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out = expectOK(t, res, "describe_hpa events")
+	requireContains(t, out, "FailedGetPodsMetric", "expected newest event first")
+}
+
+func TestDescribeHPA_DeniedNamespace(t *testing.T) {
+	e := newFakeEnv(t, fakeHPA())
+	e.provider.deniedNamespaces = []string{"default"}
+
+	res, err := e.manager.handleDescribeHPA(context.Background(), makeRequest(map[string]any{
+		"namespace": "default",
+		"name":      "web",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	text := expectErr(t, res, "denied namespace")
+	requireContains(t, text, "namespace default is not allowed", "expected namespace error")
+}
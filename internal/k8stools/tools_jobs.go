@@ -0,0 +1,522 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"kubernetes-mcp/internal/authorization"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/validation"
+	batchclient "k8s.io/client-go/kubernetes/typed/batch/v1"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+const (
+	jobWaitDefaultTimeout = 300 * time.Second
+	jobWaitMaxTimeout     = 600 * time.Second
+
+	// cronJobRecentJobs bounds the Jobs reported for a CronJob
+	cronJobRecentJobs = 5
+
+	// manualJobSuffix is appended to the CronJob name, before a random
+	// suffix, to name the Jobs trigger_cronjob creates.
+	manualJobSuffix = "-manual-"
+)
+
+// jobPollInterval is how often waitForJob re-reads the Job
+var jobPollInterval = 2 * time.Second
+
+func (m *Manager) registerGetJobStatus() {
+	tool := mcp.NewTool(m.toolName("get_job_status"),
+		mcp.WithDescription(`Report the status of a Job or a CronJob: "did my job finish?".
+
+For a Job ('resource=jobs', the default): its state (Running, Complete,
+Failed, Suspended), active / succeeded / failed Pod counts against the
+requested completions, the completion mode, start and completion times,
+and the Pods it created with their phase and restarts.
+
+For a CronJob ('resource=cronjobs'): the schedule, whether it is
+suspended, the last scheduled and last successful times, the Jobs running
+now and the most recent Jobs it created (newest first, at most 5) with
+their state.
+
+'wait=true' (Jobs only) polls until the Job completes or fails, or
+'timeout_seconds' elapses.`),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("resource", mcp.Description("'jobs' or 'cronjobs'. Defaults to 'jobs'.")),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Name of the Job or CronJob.")),
+		mcp.WithString("namespace", mcp.Required(), mcp.Description("Namespace where the Job or CronJob lives.")),
+		mcp.WithBoolean("wait", mcp.Description("Wait until the Job completes or fails. Jobs only. Defaults to false.")),
+		mcp.WithNumber("timeout_seconds", mcp.Description("Maximum time to wait when 'wait=true'. Integer 1..600. Defaults to 300.")),
+		mcp.WithArray("yq_expressions", mcp.Description("Optional yq expressions applied to the YAML output. Examples: '.state', '.pods[] | select(.phase == \"Failed\")', '.recent_jobs[0]'.")),
+		jqExpressionsParam(),
+	)
+	m.addWaitingTool(tool, m.handleGetJobStatus, jobWaitMaxTimeout)
+}
+
+func (m *Manager) handleGetJobStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	resource, _ := args["resource"].(string)
+	if resource == "" {
+		resource = "jobs"
+	}
+	name, _ := args["name"].(string)
+	namespace, _ := args["namespace"].(string)
+
+	if name == "" || namespace == "" {
+		return errorResult(fmt.Errorf("'name' and 'namespace' are required")), nil
+	}
+	if resource != "jobs" && resource != "cronjobs" {
+		return errorResult(fmt.Errorf("resource must be 'jobs' or 'cronjobs', got %q", resource)), nil
+	}
+
+	wait, _ := args["wait"].(bool)
+	if wait && resource != "jobs" {
+		return errorResult(fmt.Errorf("'wait' is only supported for jobs")), nil
+	}
+	timeout, err := jobWaitTimeout(args)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	// Check authorization (real K8s resource: Job or CronJob)
+	if err := m.checkAuthorization(request, "get_job_status", k8sContext, namespace, authorization.ResourceInfo{
+		Group:    "batch",
+		Version:  "v1",
+		Resource: resource,
+		Name:     name,
+	}); err != nil {
+		return errorResult(err), nil
+	}
+
+	if !m.clientManager.IsNamespaceAllowed(k8sContext, namespace) {
+		return errorResult(fmt.Errorf("namespace %s is not allowed in context %s", namespace, k8sContext)), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+	batch := client.Clientset.BatchV1()
+
+	var summary map[string]any
+	if resource == "cronjobs" {
+		cronJob, err := batch.CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return errorResult(err), nil
+		}
+		summary = summarizeCronJob(cronJob)
+		// Same treatment as describe_namespace: the CronJob itself is
+		// still useful when its Jobs can't be listed.
+		if jobs, err := batch.Jobs(namespace).List(ctx, metav1.ListOptions{}); err == nil {
+			summary["recent_jobs"] = recentCronJobJobs(cronJob, jobs.Items, cronJobRecentJobs)
+		} else {
+			summary["errors"] = map[string]string{"recent_jobs": err.Error()}
+		}
+	} else {
+		var job *batchv1.Job
+		if wait {
+			job, err = waitForJob(ctx, batch.Jobs(namespace), name, timeout, m.newProgressReporter(ctx, request))
+		} else {
+			job, err = batch.Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		}
+		if err != nil {
+			return errorResult(err), nil
+		}
+		summary = summarizeJob(job)
+		if pods, err := jobPods(ctx, client.Clientset.CoreV1(), job); err == nil {
+			summary["pods"] = pods
+		} else {
+			summary["errors"] = map[string]string{"pods": err.Error()}
+		}
+	}
+
+	yamlOutput, err := objectToYAML(summary)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	finalOutput, err := m.applyOutputFilters(yamlOutput, args)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	return successResult(finalOutput), nil
+}
+
+func (m *Manager) registerTriggerCronJob() {
+	tool := mcp.NewTool(m.toolName("trigger_cronjob"),
+		mcp.WithDescription(`Run a CronJob now by creating a Job from its 'jobTemplate'.
+
+Equivalent to 'kubectl create job --from=cronjob/<name>'. The Job copies the
+template's labels, annotations and spec, is owned by the CronJob (so it is
+listed and garbage-collected with the CronJob's own runs) and is annotated
+'cronjob.kubernetes.io/instantiate: manual'. Works on suspended CronJobs.
+
+Returns the name of the created Job. 'wait=true' then polls until the Job
+completes or fails, or 'timeout_seconds' elapses, and reports its status
+as 'get_job_status' does.`),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Name of the CronJob to trigger.")),
+		mcp.WithString("namespace", mcp.Required(), mcp.Description("Namespace where the CronJob lives.")),
+		mcp.WithString("job_name", mcp.Description("Name of the Job to create. Defaults to '<cronjob>-manual-<random>'.")),
+		mcp.WithBoolean("wait", mcp.Description("Wait until the Job completes or fails. Defaults to false.")),
+		mcp.WithNumber("timeout_seconds", mcp.Description("Maximum time to wait when 'wait=true'. Integer 1..600. Defaults to 300.")),
+	)
+	m.addWaitingTool(tool, m.handleTriggerCronJob, jobWaitMaxTimeout)
+}
+
+func (m *Manager) handleTriggerCronJob(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	name, _ := args["name"].(string)
+	namespace, _ := args["namespace"].(string)
+	jobName, _ := args["job_name"].(string)
+
+	if name == "" || namespace == "" {
+		return errorResult(fmt.Errorf("'name' and 'namespace' are required")), nil
+	}
+	if jobName == "" {
+		jobName = manualJobName(name)
+	}
+
+	wait, _ := args["wait"].(bool)
+	timeout, err := jobWaitTimeout(args)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	// Check authorization: reading the CronJob and creating the Job are
+	// both needed, so a policy must allow the tool on both kinds.
+	for _, resource := range []authorization.ResourceInfo{
+		{Group: "batch", Version: "v1", Resource: "cronjobs", Name: name},
+		{Group: "batch", Version: "v1", Resource: "jobs", Name: jobName},
+	} {
+		if err := m.checkAuthorization(request, "trigger_cronjob", k8sContext, namespace, resource); err != nil {
+			return errorResult(err), nil
+		}
+	}
+
+	if !m.clientManager.IsNamespaceAllowed(k8sContext, namespace) {
+		return errorResult(fmt.Errorf("namespace %s is not allowed in context %s", namespace, k8sContext)), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+	batch := client.Clientset.BatchV1()
+
+	cronJob, err := batch.CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	job, err := batch.Jobs(namespace).Create(ctx, jobFromCronJob(cronJob, jobName), metav1.CreateOptions{})
+	if err != nil {
+		return errorResult(fmt.Errorf("failed to create job from cronjob %s: %w", name, err)), nil
+	}
+
+	created := fmt.Sprintf("Created job %s from cronjob %s in namespace %s", job.Name, name, namespace)
+	if !wait {
+		return successResult(created), nil
+	}
+
+	final, err := waitForJob(ctx, batch.Jobs(namespace), job.Name, timeout, m.newProgressReporter(ctx, request))
+	if err != nil {
+		return errorResult(fmt.Errorf("%s, but %w", created, err)), nil
+	}
+
+	yamlOutput, err := objectToYAML(summarizeJob(final))
+	if err != nil {
+		return errorResult(err), nil
+	}
+	return successResult(fmt.Sprintf("%s; it finished as %s\n\n%s", created, jobState(final), yamlOutput)), nil
+}
+
+// manualJobName generates the name of a manually triggered Job, keeping it
+// within the 63 characters a Job name (it becomes a label value) allows.
+func manualJobName(cronJobName string) string {
+	const randomLength = 5
+	base := cronJobName
+	if limit := validation.DNS1123LabelMaxLength - len(manualJobSuffix) - randomLength; len(base) > limit {
+		base = base[:limit]
+	}
+	return base + manualJobSuffix + utilrand.String(randomLength)
+}
+
+// jobFromCronJob builds the Job a CronJob would create, the way
+// 'kubectl create job --from=cronjob' does.
+func jobFromCronJob(cronJob *batchv1.CronJob, jobName string) *batchv1.Job {
+	annotations := map[string]string{"cronjob.kubernetes.io/instantiate": "manual"}
+	for k, v := range cronJob.Spec.JobTemplate.Annotations {
+		annotations[k] = v
+	}
+
+	return &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{APIVersion: "batch/v1", Kind: "Job"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        jobName,
+			Namespace:   cronJob.Namespace,
+			Labels:      cronJob.Spec.JobTemplate.Labels,
+			Annotations: annotations,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(cronJob, batchv1.SchemeGroupVersion.WithKind("CronJob")),
+			},
+		},
+		Spec: cronJob.Spec.JobTemplate.Spec,
+	}
+}
+
+// jobWaitTimeout reads 'timeout_seconds' for the tools that wait on a Job
+func jobWaitTimeout(args map[string]any) (time.Duration, error) {
+	v, ok := args["timeout_seconds"].(float64)
+	if !ok {
+		return jobWaitDefaultTimeout, nil
+	}
+	if v < 1 || v > jobWaitMaxTimeout.Seconds() {
+		return 0, fmt.Errorf("timeout_seconds must be between 1 and %d, got %v", int(jobWaitMaxTimeout.Seconds()), v)
+	}
+	return time.Duration(v) * time.Second, nil
+}
+
+// jobPods summarises the Pods a Job created, selected with the Job's own
+// selector (the controller-uid label the Job controller sets).
+func jobPods(ctx context.Context, core typedcorev1.CoreV1Interface, job *batchv1.Job) ([]map[string]any, error) {
+	opts := metav1.ListOptions{}
+	if job.Spec.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(job.Spec.Selector)
+		if err != nil {
+			return nil, err
+		}
+		opts.LabelSelector = selector.String()
+	} else {
+		opts.LabelSelector = "job-name=" + job.Name
+	}
+
+	pods, err := core.Pods(job.Namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[i].CreationTimestamp.Before(&pods.Items[j].CreationTimestamp)
+	})
+
+	out := make([]map[string]any, 0, len(pods.Items))
+	for _, pod := range pods.Items {
+		var restarts int32
+		entry := map[string]any{
+			"name":  pod.Name,
+			"phase": string(pod.Status.Phase),
+		}
+		for _, cs := range pod.Status.ContainerStatuses {
+			restarts += cs.RestartCount
+			if t := cs.State.Terminated; t != nil && t.ExitCode != 0 {
+				entry["reason"] = fmt.Sprintf("%s: container %s exited with code %d", t.Reason, cs.Name, t.ExitCode)
+			}
+		}
+		entry["restarts"] = restarts
+		if pod.Spec.NodeName != "" {
+			entry["node"] = pod.Spec.NodeName
+		}
+		out = append(out, entry)
+	}
+	return out, nil
+}
+
+// jobState is the one-word state of a Job: Complete, Failed, Suspended or
+// Running.
+func jobState(job *batchv1.Job) string {
+	for _, c := range job.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case batchv1.JobComplete:
+			return "Complete"
+		case batchv1.JobFailed:
+			return "Failed"
+		}
+	}
+	if job.Spec.Suspend != nil && *job.Spec.Suspend {
+		return "Suspended"
+	}
+	return "Running"
+}
+
+// jobFinished reports whether a Job reached a terminal state
+func jobFinished(job *batchv1.Job) bool {
+	state := jobState(job)
+	return state == "Complete" || state == "Failed"
+}
+
+// summarizeJob flattens a Job into its progress counters and timing
+func summarizeJob(job *batchv1.Job) map[string]any {
+	mode := string(batchv1.NonIndexedCompletion)
+	if job.Spec.CompletionMode != nil {
+		mode = string(*job.Spec.CompletionMode)
+	}
+
+	summary := map[string]any{
+		"name":            job.Name,
+		"namespace":       job.Namespace,
+		"state":           jobState(job),
+		"completion_mode": mode,
+		"active":          job.Status.Active,
+		"succeeded":       job.Status.Succeeded,
+		"failed":          job.Status.Failed,
+	}
+	if job.Spec.Completions != nil {
+		summary["completions"] = *job.Spec.Completions
+	}
+	if job.Spec.Parallelism != nil {
+		summary["parallelism"] = *job.Spec.Parallelism
+	}
+	if job.Spec.BackoffLimit != nil {
+		summary["backoff_limit"] = *job.Spec.BackoffLimit
+	}
+	if start := job.Status.StartTime; start != nil {
+		summary["start_time"] = start.UTC().Format("2006-01-02T15:04:05Z")
+		if end := job.Status.CompletionTime; end != nil {
+			summary["completion_time"] = end.UTC().Format("2006-01-02T15:04:05Z")
+			summary["duration"] = end.Sub(start.Time).String()
+		}
+	}
+	// The reason a Job failed lives in its Failed condition
+	// (BackoffLimitExceeded, DeadlineExceeded, ...).
+	for _, c := range job.Status.Conditions {
+		if c.Type == batchv1.JobFailed && c.Status == corev1.ConditionTrue {
+			summary["failure"] = fmt.Sprintf("%s: %s", c.Reason, c.Message)
+		}
+	}
+	return summary
+}
+
+// summarizeCronJob flattens a CronJob into its schedule and last runs
+func summarizeCronJob(cronJob *batchv1.CronJob) map[string]any {
+	active := make([]string, 0, len(cronJob.Status.Active))
+	for _, ref := range cronJob.Status.Active {
+		active = append(active, ref.Name)
+	}
+
+	summary := map[string]any{
+		"name":               cronJob.Name,
+		"namespace":          cronJob.Namespace,
+		"schedule":           cronJob.Spec.Schedule,
+		"suspended":          cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend,
+		"concurrency_policy": string(cronJob.Spec.ConcurrencyPolicy),
+		"active_jobs":        active,
+	}
+	if cronJob.Spec.TimeZone != nil {
+		summary["time_zone"] = *cronJob.Spec.TimeZone
+	}
+	if t := cronJob.Status.LastScheduleTime; t != nil {
+		summary["last_schedule_time"] = t.UTC().Format("2006-01-02T15:04:05Z")
+	}
+	if t := cronJob.Status.LastSuccessfulTime; t != nil {
+		summary["last_successful_time"] = t.UTC().Format("2006-01-02T15:04:05Z")
+	}
+	return summary
+}
+
+// recentCronJobJobs returns the newest Jobs owned by a CronJob, in brief
+func recentCronJobJobs(cronJob *batchv1.CronJob, jobs []batchv1.Job, limit int) []map[string]any {
+	var owned []batchv1.Job
+	for _, job := range jobs {
+		for _, ref := range job.OwnerReferences {
+			if ref.UID == cronJob.UID && ref.Kind == "CronJob" {
+				owned = append(owned, job)
+				break
+			}
+		}
+	}
+	sort.SliceStable(owned, func(i, j int) bool {
+		return owned[j].CreationTimestamp.Before(&owned[i].CreationTimestamp)
+	})
+	if len(owned) > limit {
+		owned = owned[:limit]
+	}
+
+	out := make([]map[string]any, 0, len(owned))
+	for i := range owned {
+		job := &owned[i]
+		entry := map[string]any{
+			"name":      job.Name,
+			"state":     jobState(job),
+			"succeeded": job.Status.Succeeded,
+			"failed":    job.Status.Failed,
+		}
+		if start := job.Status.StartTime; start != nil {
+			entry["start_time"] = start.UTC().Format("2006-01-02T15:04:05Z")
+		}
+		if end := job.Status.CompletionTime; end != nil {
+			entry["completion_time"] = end.UTC().Format("2006-01-02T15:04:05Z")
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+// waitForJob polls a Job until it completes or fails, returning the last
+// object read. Each poll is reported to 'progress' as succeeded out of the
+// requested completions.
+func waitForJob(ctx context.Context, jobs batchclient.JobInterface, name string, timeout time.Duration, progress *progressReporter) (*batchv1.Job, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(jobPollInterval)
+	defer ticker.Stop()
+
+	var last *batchv1.Job
+	for {
+		job, err := jobs.Get(ctx, name, metav1.GetOptions{})
+		if err == nil {
+			last = job
+			if jobFinished(job) {
+				return job, nil
+			}
+			var completions float64
+			if job.Spec.Completions != nil {
+				completions = float64(*job.Spec.Completions)
+			}
+			progress.Report(float64(job.Status.Succeeded), completions,
+				fmt.Sprintf("waiting: %d succeeded, %d failed, %d active", job.Status.Succeeded, job.Status.Failed, job.Status.Active))
+		}
+
+		select {
+		case <-ctx.Done():
+			if last == nil {
+				return nil, fmt.Errorf("job %s did not finish within %s", name, timeout)
+			}
+			return nil, fmt.Errorf("job %s did not finish within %s (%d active, %d succeeded, %d failed)",
+				name, timeout, last.Status.Active, last.Status.Succeeded, last.Status.Failed)
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,298 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func fakeJob(namespace, name string, completions int32) *batchv1.Job {
+	return &batchv1.Job{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "batch/v1", Kind: "Job"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: batchv1.JobSpec{
+			Completions: &completions,
+			Selector:    &metav1.LabelSelector{MatchLabels: map[string]string{"job-name": name}},
+		},
+	}
+}
+
+// completed marks a Job as finished with 'condition' (Complete or Failed)
+func completed(job *batchv1.Job, condition batchv1.JobConditionType) *batchv1.Job {
+	start := metav1.NewTime(time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC))
+	end := metav1.NewTime(start.Add(90 * time.Second))
+	job.Status.StartTime = &start
+	job.Status.Conditions = []batchv1.JobCondition{{Type: condition, Status: corev1.ConditionTrue}}
+	if condition == batchv1.JobComplete {
+		job.Status.Succeeded = 1
+		if job.Spec.Completions != nil {
+			job.Status.Succeeded = *job.Spec.Completions
+		}
+		job.Status.CompletionTime = &end
+	} else {
+		job.Status.Failed = 1
+		job.Status.Conditions[0].Reason = "BackoffLimitExceeded"
+		job.Status.Conditions[0].Message = "Job has reached the specified backoff limit"
+	}
+	return job
+}
+
+func TestGetJobStatus(t *testing.T) {
+	failedPod := fakePod("default", "migrate-abc", map[string]string{"job-name": "migrate"})
+	failedPod.Status.Phase = corev1.PodFailed
+	failedPod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name:  "app",
+		State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 2}},
+	}}
+	e := newFakeEnv(t,
+		completed(fakeJob("default", "migrate", 1), batchv1.JobFailed),
+		failedPod,
+		fakePod("default", "other", map[string]string{"job-name": "other"}),
+	)
+
+	res, err := e.manager.handleGetJobStatus(context.Background(), makeRequest(map[string]any{
+		"name":      "migrate",
+		"namespace": "default",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "get_job_status")
+	requireContains(t, out, "state: Failed", "expected the job state")
+	requireContains(t, out, "failure: 'BackoffLimitExceeded: Job has reached the specified backoff limit'", "expected the failure reason")
+	requireContains(t, out, "completion_mode: NonIndexed", "expected the default completion mode")
+	requireContains(t, out, "name: migrate-abc", "expected the job's pod")
+	requireContains(t, out, "Error: container app exited with code 2", "expected the pod failure")
+	if strings.Contains(out, "name: other") {
+		t.Fatalf("pods of other jobs must not be listed:\n%s", out)
+	}
+}
+
+func TestGetJobStatus_CronJob(t *testing.T) {
+	cronJob := &batchv1.CronJob{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "batch/v1", Kind: "CronJob"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "backup", UID: types.UID("cron-uid")},
+		Spec:       batchv1.CronJobSpec{Schedule: "0 3 * * *"},
+	}
+	objs := []runtime.Object{cronJob}
+	for day := 1; day <= 7; day++ {
+		job := completed(fakeJob("default", fmt.Sprintf("backup-%d", day), 1), batchv1.JobComplete)
+		job.CreationTimestamp = metav1.NewTime(time.Date(2025, 1, day, 3, 0, 0, 0, time.UTC))
+		job.OwnerReferences = []metav1.OwnerReference{{Kind: "CronJob", Name: "backup", UID: cronJob.UID}}
+		objs = append(objs, job)
+	}
+	objs = append(objs, fakeJob("default", "unrelated", 1))
+	e := newFakeEnv(t, objs...)
+
+	res, err := e.manager.handleGetJobStatus(context.Background(), makeRequest(map[string]any{
+		"resource":  "cronjobs",
+		"name":      "backup",
+		"namespace": "default",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "get_job_status cronjob")
+	requireContains(t, out, "schedule: 0 3 * * *", "expected the schedule")
+	requireContains(t, out, "recent_jobs:\n- completion_time", "expected recent jobs")
+	if strings.Index(out, "backup-7") > strings.Index(out, "backup-6") {
+		t.Fatalf("expected newest jobs first:\n%s", out)
+	}
+	for _, name := range []string{"backup-2", "unrelated"} {
+		if strings.Contains(out, name) {
+			t.Fatalf("%s must not be reported:\n%s", name, out)
+		}
+	}
+}
+
+func TestGetJobStatus_Wait(t *testing.T) {
+	e := newFakeEnv(t, completed(fakeJob("default", "migrate", 1), batchv1.JobComplete))
+
+	res, err := e.manager.handleGetJobStatus(context.Background(), makeRequest(map[string]any{
+		"name":      "migrate",
+		"namespace": "default",
+		"wait":      true,
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "get_job_status wait")
+	requireContains(t, out, "state: Complete", "expected the job to be complete")
+	requireContains(t, out, "duration: 1m30s", "expected the job duration")
+}
+
+func TestGetJobStatus_WaitTimeout(t *testing.T) {
+	interval := jobPollInterval
+	jobPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { jobPollInterval = interval })
+
+	job := fakeJob("default", "migrate", 3)
+	job.Status.Active = 2
+	job.Status.Succeeded = 1
+	e := newFakeEnv(t, job)
+
+	res, err := e.manager.handleGetJobStatus(context.Background(), makeRequest(map[string]any{
+		"name":            "migrate",
+		"namespace":       "default",
+		"wait":            true,
+		"timeout_seconds": float64(1),
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	text := expectErr(t, res, "get_job_status wait timeout")
+	requireContains(t, text, "job migrate did not finish within 1s (2 active, 1 succeeded, 0 failed)", "expected timeout")
+}
+
+func TestGetJobStatus_Errors(t *testing.T) {
+	e := newFakeEnv(t)
+
+	tests := []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{"missing namespace", map[string]any{"name": "x"}, "'name' and 'namespace' are required"},
+		{"bad resource", map[string]any{"name": "x", "namespace": "default", "resource": "pods"}, "resource must be 'jobs' or 'cronjobs'"},
+		{"wait on cronjob", map[string]any{"name": "x", "namespace": "default", "resource": "cronjobs", "wait": true}, "only supported for jobs"},
+		{"timeout out of range", map[string]any{"name": "x", "namespace": "default", "timeout_seconds": float64(0)}, "timeout_seconds must be between 1 and 600"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := e.manager.handleGetJobStatus(context.Background(), makeRequest(tt.args))
+			if err != nil {
+				t.Fatalf("go-error: %v", err)
+			}
+			text := expectErr(t, res, tt.name)
+			requireContains(t, text, tt.want, "unexpected error text")
+		})
+	}
+}
+
+func fakeCronJob(namespace, name string) *batchv1.CronJob {
+	return &batchv1.CronJob{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "batch/v1", Kind: "CronJob"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, UID: types.UID(name + "-uid")},
+		Spec: batchv1.CronJobSpec{
+			Schedule: "0 3 * * *",
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{"app": name},
+					Annotations: map[string]string{"team": "storage"},
+				},
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+						Containers:    []corev1.Container{{Name: "app", Image: "busybox"}},
+						RestartPolicy: corev1.RestartPolicyNever,
+					}},
+				},
+			},
+		},
+	}
+}
+
+func TestTriggerCronJob(t *testing.T) {
+	e := newFakeEnv(t, fakeCronJob("default", "backup"))
+
+	res, err := e.manager.handleTriggerCronJob(context.Background(), makeRequest(map[string]any{
+		"name":      "backup",
+		"namespace": "default",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "trigger_cronjob")
+	requireContains(t, out, "Created job backup-manual-", "expected the generated job name")
+
+	jobs, err := e.clientset.BatchV1().Jobs("default").List(context.Background(), metav1.ListOptions{})
+	if err != nil || len(jobs.Items) != 1 {
+		t.Fatalf("expected one job, got %v (err %v)", jobs, err)
+	}
+	job := jobs.Items[0]
+	if len(job.OwnerReferences) != 1 || job.OwnerReferences[0].UID != "backup-uid" || !*job.OwnerReferences[0].Controller {
+		t.Fatalf("expected the cronjob as controller owner, got %+v", job.OwnerReferences)
+	}
+	if job.Annotations["cronjob.kubernetes.io/instantiate"] != "manual" || job.Annotations["team"] != "storage" {
+		t.Fatalf("unexpected annotations: %v", job.Annotations)
+	}
+	if job.Labels["app"] != "backup" || job.Spec.Template.Spec.Containers[0].Image != "busybox" {
+		t.Fatalf("expected the job template to be copied, got %+v", job)
+	}
+}
+
+func TestTriggerCronJob_Wait(t *testing.T) {
+	e := newFakeEnv(t, fakeCronJob("default", "backup"))
+	// The fake has no Job controller: finish the Job as it is created
+	e.clientset.PrependReactor("create", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		completed(action.(k8stesting.CreateAction).GetObject().(*batchv1.Job), batchv1.JobComplete)
+		return false, nil, nil
+	})
+
+	res, err := e.manager.handleTriggerCronJob(context.Background(), makeRequest(map[string]any{
+		"name":      "backup",
+		"namespace": "default",
+		"job_name":  "backup-now",
+		"wait":      true,
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "trigger_cronjob wait")
+	requireContains(t, out, "Created job backup-now from cronjob backup in namespace default; it finished as Complete", "expected completion")
+}
+
+func TestTriggerCronJob_Errors(t *testing.T) {
+	e := newFakeEnv(t, fakeCronJob("default", "backup"))
+
+	tests := []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{"missing name", map[string]any{"namespace": "default"}, "'name' and 'namespace' are required"},
+		{"unknown cronjob", map[string]any{"name": "nope", "namespace": "default"}, "not found"},
+		{"timeout out of range", map[string]any{"name": "backup", "namespace": "default", "timeout_seconds": float64(601)}, "timeout_seconds must be between 1 and 600"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := e.manager.handleTriggerCronJob(context.Background(), makeRequest(tt.args))
+			if err != nil {
+				t.Fatalf("go-error: %v", err)
+			}
+			text := expectErr(t, res, tt.name)
+			requireContains(t, text, tt.want, "unexpected error text")
+		})
+	}
+}
+
+func TestManualJobName(t *testing.T) {
+	name := manualJobName(strings.Repeat("a", 80))
+	if len(name) != 63 || !strings.Contains(name, "-manual-") {
+		t.Fatalf("expected a 63-character name, got %q (%d)", name, len(name))
+	}
+}
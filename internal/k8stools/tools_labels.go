@@ -0,0 +1,513 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"kubernetes-mcp/internal/authorization"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/dynamic"
+)
+
+// metadataField is the metadata map a bulk metadata tool edits
+type metadataField string
+
+const (
+	metadataLabels      metadataField = "labels"
+	metadataAnnotations metadataField = "annotations"
+)
+
+func (m *Manager) registerLabelResources() {
+	m.registerBulkMetadataTool("label_resources", metadataLabels)
+}
+
+func (m *Manager) registerAnnotateResources() {
+	m.registerBulkMetadataTool("annotate_resources", metadataAnnotations)
+}
+
+func (m *Manager) registerBulkMetadataTool(name string, field metadataField) {
+	singular := strings.TrimSuffix(string(field), "s")
+
+	tool := mcp.NewTool(m.toolName(name),
+		mcp.WithDescription(fmt.Sprintf(`Set or remove %[1]s on EVERY resource of one type matching a selector,
+e.g. tagging everything in a namespace with a cost-center %[2]s.
+
+Run 'list_resources' with the same selector first to confirm which objects
+will change. At least one selector ('label_selector' or 'field_selector')
+is required.
+
+Each object is patched individually (JSON merge patch on
+'metadata.%[1]s'); only the keys given in 'set' / 'remove' are touched.
+The number of matched objects is capped by the server's
+'kubernetes.tools.bulk_operations.max_resources_per_operation' setting
+(default 100): the call is rejected before changing anything if the
+selector matches more. The result lists every object with its outcome.`, field, singular)),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("group", mcp.Description("API group. Empty string \"\" for the core API.")),
+		mcp.WithString("version", mcp.Required(), mcp.Description("API version, e.g. 'v1'.")),
+		mcp.WithString("resource", mcp.Required(), mcp.Description("Resource name in the API sense: lowercase plural ('pods', 'deployments'). NOT the Kind.")),
+		mcp.WithString("namespace", mcp.Description("Namespace to scope the change to. Required for namespaced resources; ignored for cluster-scoped ones.")),
+		mcp.WithString("label_selector", mcp.Description("Kubernetes label selector choosing the objects. Example: 'app=checkout'. Required if 'field_selector' is empty.")),
+		mcp.WithString("field_selector", mcp.Description("Kubernetes field selector choosing the objects. Example: 'status.phase=Running'. Required if 'label_selector' is empty.")),
+		mcp.WithObject("set", mcp.AdditionalProperties(map[string]any{"type": "string"}), mcp.Description("Keys to add or overwrite, as a map of string values. Example: {\"cost-center\": \"cc-1234\"}.")),
+		mcp.WithArray("remove", mcp.Description("Keys to remove. Example: [\"deprecated-key\"].")),
+	)
+	m.addTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return m.handleBulkMetadata(ctx, request, name, field)
+	})
+}
+
+func (m *Manager) handleBulkMetadata(ctx context.Context, request mcp.CallToolRequest, toolName string, field metadataField) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	namespace, _ := args["namespace"].(string)
+	labelSelector, _ := args["label_selector"].(string)
+	fieldSelector, _ := args["field_selector"].(string)
+	gvr := gvrFromArgs(args)
+	if err := validateGVR(gvr); err != nil {
+		return errorResult(err), nil
+	}
+
+	if labelSelector == "" && fieldSelector == "" {
+		return errorResult(fmt.Errorf("at least one selector (label_selector or field_selector) is required")), nil
+	}
+
+	changes, err := parseMetadataChanges(args, field)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	// Check authorization
+	resource := authorization.ResourceInfo{
+		Group:    gvr.Group,
+		Version:  gvr.Version,
+		Resource: gvr.Resource,
+	}
+	if err := m.checkAuthorization(request, toolName, k8sContext, namespace, resource); err != nil {
+		return errorResult(err), nil
+	}
+	if err := m.checkFieldKeys(request, toolName, k8sContext, namespace, resource, field, mapKeys(changes)); err != nil {
+		return errorResult(err), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	// A namespaced resource listed without a namespace spans the whole
+	// cluster, which is never what "everything in a namespace" means.
+	namespaced, err := m.isNamespacedGVR(client, gvr)
+	if err != nil {
+		return errorResult(err), nil
+	}
+	if namespaced {
+		if namespace == "" {
+			return errorResult(fmt.Errorf("namespace is required for %s", gvr.Resource)), nil
+		}
+		if !m.clientManager.IsNamespaceAllowed(k8sContext, namespace) {
+			return errorResult(fmt.Errorf("namespace %s is not allowed in context %s", namespace, k8sContext)), nil
+		}
+	} else {
+		namespace = ""
+	}
+
+	var nsClient dynamic.ResourceInterface = client.DynamicClient.Resource(gvr)
+	if namespace != "" {
+		nsClient = client.DynamicClient.Resource(gvr).Namespace(namespace)
+	}
+
+	list, err := nsClient.List(ctx, metav1.ListOptions{LabelSelector: labelSelector, FieldSelector: fieldSelector})
+	if err != nil {
+		return errorResult(err), nil
+	}
+	matched := len(list.Items)
+	if matched == 0 {
+		return successResult(fmt.Sprintf("No %s matched the selector; nothing to change", gvr.Resource)), nil
+	}
+	if maxBulk := m.bulkOperationsLimit(); matched > maxBulk {
+		return errorResult(fmt.Errorf("selector matched %d resources, which exceeds the configured cap of %d (kubernetes.tools.bulk_operations.max_resources_per_operation); refine the selector or raise the cap", matched, maxBulk)), nil
+	}
+
+	patchBytes, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{string(field): changes},
+	})
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	aggregate := NewAggregateResult()
+	for _, item := range list.Items {
+		target := item.GetName()
+		if item.GetNamespace() != "" {
+			target = item.GetNamespace() + "/" + target
+		}
+		// The checks above have no object name; rules scoped by 'names'
+		// apply here
+		itemResource := resource
+		itemResource.Name = item.GetName()
+		if err := m.checkAuthorization(request, toolName, k8sContext, item.GetNamespace(), itemResource); err != nil {
+			aggregate.AddError(target, err, "")
+			continue
+		}
+		if err := m.checkFieldKeys(request, toolName, k8sContext, item.GetNamespace(), itemResource, field, mapKeys(changes)); err != nil {
+			aggregate.AddError(target, err, "")
+			continue
+		}
+		if _, err := nsClient.Patch(ctx, item.GetName(), types.MergePatchType, patchBytes, metav1.PatchOptions{}); err != nil {
+			aggregate.AddError(target, err, "")
+			continue
+		}
+		aggregate.AddSuccess(target, "")
+	}
+
+	header := fmt.Sprintf("Updated %s on %d of %d %s matching the selector\n\n", field, aggregate.Succeeded(), matched, gvr.Resource)
+	result := successResult(header + aggregate.Render())
+	if aggregate.Succeeded() == 0 {
+		result.IsError = true
+	}
+	return result, nil
+}
+
+func (m *Manager) registerLabelResource() {
+	m.registerMetadataTool("label_resource", metadataLabels)
+}
+
+func (m *Manager) registerAnnotateResource() {
+	m.registerMetadataTool("annotate_resource", metadataAnnotations)
+}
+
+func (m *Manager) registerMetadataTool(name string, field metadataField) {
+	singular := strings.TrimSuffix(string(field), "s")
+
+	tool := mcp.NewTool(m.toolName(name),
+		mcp.WithDescription(fmt.Sprintf(`Set or remove ONE %[2]s on ONE resource, without writing a patch.
+
+Sets 'key' to 'value', or deletes 'key' with 'remove=true' (or 'value: null'). Changing a key
+that already has a different value requires 'overwrite=true', as with
+'kubectl %[3]s --overwrite'. Only that key of 'metadata.%[1]s' is touched
+(JSON Patch on '/metadata/%[1]s/<key>').
+
+To change many objects at once by selector use '%[3]s_resources'.`, field, singular, strings.TrimSuffix(name, "_resource"))),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("group", mcp.Description("API group. Empty string \"\" for the core API.")),
+		mcp.WithString("version", mcp.Required(), mcp.Description("API version, e.g. 'v1'.")),
+		mcp.WithString("resource", mcp.Required(), mcp.Description("Resource name in the API sense: lowercase plural ('pods', 'deployments'). NOT the Kind.")),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Name of the resource instance.")),
+		mcp.WithString("namespace", mcp.Description("Namespace where the resource lives. Required for namespaced resources.")),
+		mcp.WithString("key", mcp.Required(), mcp.Description(fmt.Sprintf("Key of the %s, optionally prefixed: 'team' or 'example.com/team'.", singular))),
+		mcp.WithString("value", mcp.Description("Value to set. Required unless 'remove=true'; null removes the key.")),
+		mcp.WithBoolean("overwrite", mcp.Description("Allow replacing an existing, different value. Defaults to false.")),
+		mcp.WithBoolean("remove", mcp.Description(fmt.Sprintf("Delete the %s instead of setting it. Defaults to false.", singular))),
+	)
+	m.addTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return m.handleMetadata(ctx, request, name, field)
+	})
+}
+
+func (m *Manager) handleMetadata(ctx context.Context, request mcp.CallToolRequest, toolName string, field metadataField) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	singular := strings.TrimSuffix(string(field), "s")
+
+	k8sContext := m.getContextParam(ctx, args)
+	name, _ := args["name"].(string)
+	namespace, _ := args["namespace"].(string)
+	key, _ := args["key"].(string)
+	value, hasValue := args["value"].(string)
+	overwrite, _ := args["overwrite"].(bool)
+	remove, _ := args["remove"].(bool)
+	if v, ok := args["value"]; ok && v == nil {
+		remove = true
+	}
+	gvr := gvrFromArgs(args)
+	if err := validateGVR(gvr); err != nil {
+		return errorResult(err), nil
+	}
+
+	if key == "" {
+		return errorResult(fmt.Errorf("key is required")), nil
+	}
+	switch {
+	case remove && hasValue:
+		return errorResult(fmt.Errorf("value and remove=true are mutually exclusive")), nil
+	case !remove && !hasValue:
+		return errorResult(fmt.Errorf("value is required unless remove=true")), nil
+	}
+	if !remove {
+		if err := validateMetadataEntry(field, key, value); err != nil {
+			return errorResult(err), nil
+		}
+	}
+
+	// Check authorization
+	resource := authorization.ResourceInfo{
+		Group:    gvr.Group,
+		Version:  gvr.Version,
+		Resource: gvr.Resource,
+		Name:     name,
+	}
+	if err := m.checkAuthorization(request, toolName, k8sContext, namespace, resource); err != nil {
+		return errorResult(err), nil
+	}
+	if err := m.checkFieldKeys(request, toolName, k8sContext, namespace, resource, field, []string{key}); err != nil {
+		return errorResult(err), nil
+	}
+
+	if namespace != "" && !m.clientManager.IsNamespaceAllowed(k8sContext, namespace) {
+		return errorResult(fmt.Errorf("namespace %s is not allowed in context %s", namespace, k8sContext)), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	var nsClient dynamic.ResourceInterface = client.DynamicClient.Resource(gvr)
+	if namespace != "" {
+		nsClient = client.DynamicClient.Resource(gvr).Namespace(namespace)
+	}
+
+	live, err := nsClient.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return errorResult(err), nil
+	}
+	current, found, _ := unstructured.NestedStringMap(live.Object, "metadata", string(field))
+	old, exists := current[key]
+	path := jsonPointer([]string{"metadata", string(field), key})
+
+	var ops []map[string]any
+	switch {
+	case remove && !exists:
+		return successResult(fmt.Sprintf("%s/%s has no %s %q; nothing was changed", gvr.Resource, name, singular, key)), nil
+	case remove:
+		ops = []map[string]any{
+			{"op": "test", "path": path, "value": old},
+			{"op": "remove", "path": path},
+		}
+	case exists && old == value:
+		return successResult(fmt.Sprintf("%s %s=%s is already set on %s/%s; nothing was changed", singular, key, value, gvr.Resource, name)), nil
+	case exists && !overwrite:
+		return errorResult(fmt.Errorf("%s/%s already has %s %s=%s; pass overwrite=true to replace it", gvr.Resource, name, singular, key, old)), nil
+	case exists:
+		ops = []map[string]any{
+			{"op": "test", "path": path, "value": old},
+			{"op": "replace", "path": path, "value": value},
+		}
+	case !found:
+		// The map itself is missing: create it holding the one key
+		ops = []map[string]any{
+			{"op": "add", "path": jsonPointer([]string{"metadata", string(field)}), "value": map[string]string{key: value}},
+		}
+	default:
+		ops = []map[string]any{{"op": "add", "path": path, "value": value}}
+	}
+
+	patchBytes, err := json.Marshal(ops)
+	if err != nil {
+		return errorResult(fmt.Errorf("failed to encode JSON patch: %w", err)), nil
+	}
+	if _, err := nsClient.Patch(ctx, name, types.JSONPatchType, patchBytes, metav1.PatchOptions{}); err != nil {
+		return errorResult(err), nil
+	}
+
+	switch {
+	case remove:
+		return successResult(fmt.Sprintf("Successfully removed %s %s (was %q) from %s/%s", singular, key, old, gvr.Resource, name)), nil
+	case exists:
+		return successResult(fmt.Sprintf("Successfully set %s %s=%s on %s/%s (was %q)", singular, key, value, gvr.Resource, name, old)), nil
+	default:
+		return successResult(fmt.Sprintf("Successfully set %s %s=%s on %s/%s", singular, key, value, gvr.Resource, name)), nil
+	}
+}
+
+// parseMetadataChanges validates 'set' and 'remove' and merges them into the
+// map sent in the merge patch, where a null value deletes the key.
+func parseMetadataChanges(args map[string]any, field metadataField) (map[string]any, error) {
+	changes := map[string]any{}
+
+	set, _ := args["set"].(map[string]any)
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value, ok := set[key].(string)
+		if !ok {
+			return nil, fmt.Errorf("value of %q in 'set' must be a string, got %T", key, set[key])
+		}
+		if err := validateMetadataEntry(field, key, value); err != nil {
+			return nil, err
+		}
+		changes[key] = value
+	}
+
+	remove, _ := args["remove"].([]any)
+	for _, r := range remove {
+		key, ok := r.(string)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("'remove' must be a list of non-empty strings")
+		}
+		if _, ok := changes[key]; ok {
+			return nil, fmt.Errorf("key %q is both in 'set' and 'remove'", key)
+		}
+		changes[key] = nil
+	}
+
+	if len(changes) == 0 {
+		return nil, fmt.Errorf("nothing to change: provide 'set' and/or 'remove'")
+	}
+	return changes, nil
+}
+
+// validateMetadataEntry checks a key, and for labels the value, against the
+// API server's syntax rules so a bad entry fails before anything is sent.
+func validateMetadataEntry(field metadataField, key, value string) error {
+	if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+		return fmt.Errorf("invalid key %q: %s", key, strings.Join(errs, "; "))
+	}
+	if field == metadataLabels {
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return fmt.Errorf("invalid value %q for label %q: %s", value, key, strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
+
+// checkFieldKeys is checkMetadataKeys for tools editing a single map
+func (m *Manager) checkFieldKeys(request mcp.CallToolRequest, tool, k8sContext, namespace string, resource authorization.ResourceInfo,
+	field metadataField, keys []string) error {
+	if field == metadataLabels {
+		return m.checkMetadataKeys(request, tool, k8sContext, namespace, resource, keys, nil)
+	}
+	return m.checkMetadataKeys(request, tool, k8sContext, namespace, resource, nil, keys)
+}
+
+// mapKeys returns the keys of a metadata map, sorted
+func mapKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// patchMetadataKeys returns the label and annotation keys a patch sets or
+// removes. An empty key means the patch replaces or drops the whole map in a
+// way whose keys cannot be listed ('null', a '$patch' directive, removing
+// the map).
+func patchMetadataKeys(patchType types.PatchType, patch []byte) (labels, annotations []string, err error) {
+	if patchType != types.JSONPatchType {
+		var doc map[string]any
+		if err := json.Unmarshal(patch, &doc); err != nil {
+			return nil, nil, err
+		}
+		if metadata, present := doc["metadata"]; present {
+			labels, annotations = metadataObjectKeys(metadata)
+		}
+		return labels, annotations, nil
+	}
+
+	var ops []struct {
+		Op    string `json:"op"`
+		Path  string `json:"path"`
+		From  string `json:"from"`
+		Value any    `json:"value"`
+	}
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, nil, err
+	}
+	collect := func(path string, value any, removes bool) {
+		switch {
+		case path == "/metadata":
+			if removes {
+				labels, annotations = append(labels, ""), append(annotations, "")
+				return
+			}
+			l, a := metadataObjectKeys(value)
+			labels, annotations = append(labels, l...), append(annotations, a...)
+		case path == "/metadata/labels":
+			labels = append(labels, metadataMapKeys(value, removes)...)
+		case path == "/metadata/annotations":
+			annotations = append(annotations, metadataMapKeys(value, removes)...)
+		case strings.HasPrefix(path, "/metadata/labels/"):
+			labels = append(labels, unescapeJSONPointer(strings.TrimPrefix(path, "/metadata/labels/")))
+		case strings.HasPrefix(path, "/metadata/annotations/"):
+			annotations = append(annotations, unescapeJSONPointer(strings.TrimPrefix(path, "/metadata/annotations/")))
+		}
+	}
+	for _, op := range ops {
+		switch op.Op {
+		case "add", "replace", "copy":
+			collect(op.Path, op.Value, false)
+		case "remove":
+			collect(op.Path, nil, true)
+		case "move":
+			collect(op.Path, op.Value, false)
+			collect(op.From, nil, true)
+		}
+	}
+	return labels, annotations, nil
+}
+
+// metadataObjectKeys returns the label and annotation keys of a patched
+// 'metadata' object
+func metadataObjectKeys(metadata any) (labels, annotations []string) {
+	fields, ok := metadata.(map[string]any)
+	if !ok {
+		// 'metadata: null' drops every key
+		return []string{""}, []string{""}
+	}
+	if value, present := fields[string(metadataLabels)]; present {
+		labels = metadataMapKeys(value, value == nil)
+	}
+	if value, present := fields[string(metadataAnnotations)]; present {
+		annotations = metadataMapKeys(value, value == nil)
+	}
+	return labels, annotations
+}
+
+// metadataMapKeys returns the keys of a patched labels or annotations map.
+// Strategic merge directives ('$patch', '$retainKeys') may drop keys that
+// are not listed, so they count as the whole map.
+func metadataMapKeys(value any, removes bool) []string {
+	entries, ok := value.(map[string]any)
+	if removes || !ok {
+		return []string{""}
+	}
+	keys := make([]string, 0, len(entries))
+	for k := range entries {
+		if strings.HasPrefix(k, "$") {
+			k = ""
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
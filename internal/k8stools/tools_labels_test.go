@@ -0,0 +1,364 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"kubernetes-mcp/api"
+	"kubernetes-mcp/internal/authorization"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestLabelResources(t *testing.T) {
+	web1 := fakePod("shop", "web-1", map[string]string{"app": "web", "legacy": "true"})
+	web2 := fakePod("shop", "web-2", map[string]string{"app": "web"})
+	db := fakePod("shop", "db-1", map[string]string{"app": "db"})
+	e := newFakeEnv(t, web1, web2, db)
+
+	res, err := e.manager.handleBulkMetadata(context.Background(), makeRequest(map[string]any{
+		"version":        "v1",
+		"resource":       "pods",
+		"namespace":      "shop",
+		"label_selector": "app=web",
+		"set":            map[string]any{"cost-center": "cc-1234"},
+		"remove":         []any{"legacy"},
+	}), "label_resources", metadataLabels)
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "label_resources")
+	requireContains(t, out, "Updated labels on 2 of 2 pods", "expected count")
+	requireContains(t, out, "[OK]   shop/web-1", "expected web-1")
+	requireContains(t, out, "[OK]   shop/web-2", "expected web-2")
+
+	pods := e.dynamic.Resource(gvrOf("", "v1", "pods")).Namespace("shop")
+	for name, want := range map[string]string{"web-1": "cc-1234", "web-2": "cc-1234", "db-1": ""} {
+		obj, err := pods.Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("get %s: %v", name, err)
+		}
+		labels := obj.GetLabels()
+		if labels["cost-center"] != want {
+			t.Fatalf("%s: expected cost-center=%q, got labels %v", name, want, labels)
+		}
+		if _, ok := labels["legacy"]; ok {
+			t.Fatalf("%s: expected 'legacy' to be removed, got labels %v", name, labels)
+		}
+	}
+}
+
+func TestLabelResources_NameScopedDeny(t *testing.T) {
+	e := newFakeEnv(t,
+		fakePod("shop", "web-1", map[string]string{"app": "web"}),
+		fakePod("shop", "web-2", map[string]string{"app": "web"}),
+	)
+	authz, err := authorization.NewEvaluator(&api.AuthorizationConfig{
+		AllowAnonymous: true,
+		Policies: []api.AuthorizationPolicy{{
+			Name:  "not-web-2",
+			Match: api.MatchConfig{Expression: "true"},
+			Rules: []api.AuthorizationRule{
+				{Effect: api.RuleEffectAllow},
+				{Effect: api.RuleEffectDeny, Resources: []api.ResourceRule{{Groups: []string{""}, Resources: []string{"pods"}, Names: []string{"web-2"}}}},
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("NewEvaluator: %v", err)
+	}
+	e.manager.authz = authz
+
+	res, err := e.manager.handleBulkMetadata(context.Background(), makeRequest(map[string]any{
+		"version":        "v1",
+		"resource":       "pods",
+		"namespace":      "shop",
+		"label_selector": "app=web",
+		"set":            map[string]any{"cost-center": "cc-1234"},
+	}), "label_resources", metadataLabels)
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "label_resources")
+	requireContains(t, out, "Updated labels on 1 of 2 pods", "expected the denied pod not counted")
+	requireContains(t, out, "[OK]   shop/web-1", "expected web-1")
+	requireContains(t, out, "shop/web-2: access denied", "expected web-2 reported as denied")
+
+	pods := e.dynamic.Resource(gvrOf("", "v1", "pods")).Namespace("shop")
+	obj, err := pods.Get(context.Background(), "web-2", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get web-2: %v", err)
+	}
+	if _, ok := obj.GetLabels()["cost-center"]; ok {
+		t.Fatalf("expected the denied pod to be left alone, got labels %v", obj.GetLabels())
+	}
+}
+
+func TestAnnotateResources_ClusterScoped(t *testing.T) {
+	node := fakeNode("worker-1")
+	node.Labels = map[string]string{"pool": "gpu"}
+	e := newFakeEnv(t, node)
+
+	res, err := e.manager.handleBulkMetadata(context.Background(), makeRequest(map[string]any{
+		"version":        "v1",
+		"resource":       "nodes",
+		"label_selector": "pool=gpu",
+		"set":            map[string]any{"example.com/owner": "ml-team"},
+	}), "annotate_resources", metadataAnnotations)
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "annotate_resources")
+	requireContains(t, out, "[OK]   worker-1", "expected node")
+
+	obj, err := e.dynamic.Resource(gvrOf("", "v1", "nodes")).Get(context.Background(), "worker-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get node: %v", err)
+	}
+	if got := obj.GetAnnotations()["example.com/owner"]; got != "ml-team" {
+		t.Fatalf("expected annotation to be set, got %v", obj.GetAnnotations())
+	}
+}
+
+func TestLabelResources_Errors(t *testing.T) {
+	e := newFakeEnv(t,
+		fakePod("shop", "web-1", map[string]string{"app": "web"}),
+		fakePod("shop", "web-2", map[string]string{"app": "web"}),
+	)
+	e.manager.config.Kubernetes.Tools.BulkOperations.MaxResourcesPerOperation = 1
+
+	tests := []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{
+			name: "no selector",
+			args: map[string]any{"namespace": "shop", "set": map[string]any{"a": "b"}},
+			want: "at least one selector",
+		},
+		{
+			name: "nothing to change",
+			args: map[string]any{"namespace": "shop", "label_selector": "app=web"},
+			want: "nothing to change",
+		},
+		{
+			name: "invalid label value",
+			args: map[string]any{"namespace": "shop", "label_selector": "app=web", "set": map[string]any{"team": "has spaces"}},
+			want: "invalid value",
+		},
+		{
+			name: "set and remove the same key",
+			args: map[string]any{"namespace": "shop", "label_selector": "app=web", "set": map[string]any{"a": "b"}, "remove": []any{"a"}},
+			want: "both in 'set' and 'remove'",
+		},
+		{
+			name: "namespace required",
+			args: map[string]any{"label_selector": "app=web", "set": map[string]any{"a": "b"}},
+			want: "namespace is required for pods",
+		},
+		{
+			name: "bulk cap",
+			args: map[string]any{"namespace": "shop", "label_selector": "app=web", "set": map[string]any{"a": "b"}},
+			want: "exceeds the configured cap of 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := map[string]any{"version": "v1", "resource": "pods"}
+			for k, v := range tt.args {
+				args[k] = v
+			}
+			res, err := e.manager.handleBulkMetadata(context.Background(), makeRequest(args), "label_resources", metadataLabels)
+			if err != nil {
+				t.Fatalf("go-error: %v", err)
+			}
+			text := expectErr(t, res, tt.name)
+			requireContains(t, text, tt.want, "unexpected error text")
+		})
+	}
+}
+
+func TestLabelResource(t *testing.T) {
+	e := newFakeEnv(t, fakePod("shop", "web-1", map[string]string{"app": "web", "example.com/tier": "front"}))
+	call := func(tool string, field metadataField, args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		args["version"] = "v1"
+		args["resource"] = "pods"
+		args["namespace"] = "shop"
+		args["name"] = "web-1"
+		res, err := e.manager.handleMetadata(context.Background(), makeRequest(args), tool, field)
+		if err != nil {
+			t.Fatalf("go-error: %v", err)
+		}
+		return res
+	}
+	pod := func() *unstructured.Unstructured {
+		t.Helper()
+		obj, err := e.dynamic.Resource(gvrOf("", "v1", "pods")).Namespace("shop").Get(context.Background(), "web-1", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("get pod: %v", err)
+		}
+		return obj
+	}
+
+	requireContains(t, expectOK(t, call("label_resource", metadataLabels, map[string]any{"key": "team", "value": "a"}), "add"),
+		"set label team=a on pods/web-1", "expected summary")
+	requireContains(t, expectErr(t, call("label_resource", metadataLabels, map[string]any{"key": "team", "value": "b"}), "no overwrite"),
+		"pass overwrite=true", "expected overwrite guard")
+	requireContains(t, expectOK(t, call("label_resource", metadataLabels, map[string]any{"key": "team", "value": "b", "overwrite": true}), "overwrite"),
+		`(was "a")`, "expected previous value")
+	// The '/' of a prefixed key must be escaped in the JSON Pointer
+	expectOK(t, call("label_resource", metadataLabels, map[string]any{"key": "example.com/tier", "value": nil}), "remove prefixed")
+	if labels := pod().GetLabels(); labels["team"] != "b" || labels["app"] != "web" || labels["example.com/tier"] != "" {
+		t.Fatalf("unexpected labels %v", labels)
+	}
+	requireContains(t, expectErr(t, call("label_resource", metadataLabels, map[string]any{"key": "bad key", "value": "x"}), "invalid key"),
+		"invalid key", "expected key validation")
+
+	// No annotations yet: the map is created
+	requireContains(t, expectOK(t, call("annotate_resource", metadataAnnotations, map[string]any{"key": "example.com/note", "value": "hi"}), "annotate"),
+		"set annotation example.com/note=hi", "expected summary")
+	if got := pod().GetAnnotations()["example.com/note"]; got != "hi" {
+		t.Fatalf("expected annotation example.com/note=hi, got %q", got)
+	}
+	requireContains(t, expectOK(t, call("annotate_resource", metadataAnnotations, map[string]any{"key": "example.com/note", "remove": true}), "remove"),
+		"removed annotation example.com/note", "expected removal")
+	requireContains(t, expectOK(t, call("annotate_resource", metadataAnnotations, map[string]any{"key": "example.com/note", "remove": true}), "remove again"),
+		"nothing was changed", "expected no-op")
+}
+
+func TestMetadataKeyPrefixes(t *testing.T) {
+	e := newFakeEnv(t, fakePod("shop", "web-1", map[string]string{"app": "web"}))
+	authz, err := authorization.NewEvaluator(&api.AuthorizationConfig{
+		AllowAnonymous: true,
+		Policies: []api.AuthorizationPolicy{
+			{
+				Name:  "team",
+				Match: api.MatchConfig{Expression: "true"},
+				Rules: []api.AuthorizationRule{{
+					Effect:             api.RuleEffectAllow,
+					LabelPrefixes:      []string{"team.example.com/"},
+					AnnotationPrefixes: []string{"team.example.com/"},
+				}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewEvaluator: %v", err)
+	}
+	e.manager.authz = authz
+
+	apply := func(labels string) *mcp.CallToolResult {
+		t.Helper()
+		res, err := e.manager.handleApplyManifest(context.Background(), makeRequest(map[string]any{
+			"manifest": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n  namespace: shop\n  labels:\n" + labels,
+		}))
+		if err != nil {
+			t.Fatalf("go-error: %v", err)
+		}
+		return res
+	}
+	out := expectErr(t, apply("    team.example.com/owner: a\n    other.com/x: b\n"), "apply other.com/x")
+	requireContains(t, out, `may not change label "other.com/x"`, "expected the disallowed key to be named")
+	expectOK(t, apply("    team.example.com/owner: a\n"), "apply team.example.com/owner")
+
+	// Updating through a manifest removes the live keys it leaves out
+	cm := e.dynamic.Resource(gvrOf("", "v1", "configmaps")).Namespace("shop")
+	live, err := cm.Get(context.Background(), "settings", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get configmap: %v", err)
+	}
+	live.SetLabels(map[string]string{"team.example.com/owner": "a", "other.com/x": "b"})
+	if _, err := cm.Update(context.Background(), live, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("update configmap: %v", err)
+	}
+	out = expectErr(t, apply("    team.example.com/owner: a\n"), "apply dropping other.com/x")
+	requireContains(t, out, `may not change label "other.com/x"`, "expected the removed key to be named")
+	if live, err = cm.Get(context.Background(), "settings", metav1.GetOptions{}); err != nil {
+		t.Fatalf("get configmap: %v", err)
+	}
+	if live.GetLabels()["other.com/x"] != "b" {
+		t.Fatalf("expected other.com/x to survive the denied apply, got %v", live.GetLabels())
+	}
+
+	patch := func(patchType, patch string) *mcp.CallToolResult {
+		t.Helper()
+		res, err := e.manager.handlePatchResource(context.Background(), makeRequest(map[string]any{
+			"version": "v1", "resource": "pods", "namespace": "shop", "name": "web-1",
+			"patch_type": patchType, "patch": patch,
+		}))
+		if err != nil {
+			t.Fatalf("go-error: %v", err)
+		}
+		return res
+	}
+	expectErr(t, patch("merge", `{"metadata":{"labels":{"app":null}}}`), "merge patch removing app")
+	expectErr(t, patch("json", `[{"op":"add","path":"/metadata/annotations/other.com~1x","value":"b"}]`), "json patch other.com/x")
+	expectOK(t, patch("json", `[{"op":"add","path":"/metadata/labels/team.example.com~1owner","value":"a"}]`), "json patch team label")
+
+	label := func(key string) *mcp.CallToolResult {
+		t.Helper()
+		res, err := e.manager.handleMetadata(context.Background(), makeRequest(map[string]any{
+			"version": "v1", "resource": "pods", "namespace": "shop", "name": "web-1", "key": key, "value": "v",
+		}), "label_resource", metadataLabels)
+		if err != nil {
+			t.Fatalf("go-error: %v", err)
+		}
+		return res
+	}
+	expectErr(t, label("other.com/x"), "label_resource other.com/x")
+	expectOK(t, label("team.example.com/tier"), "label_resource team.example.com/tier")
+}
+
+func TestPatchMetadataKeys(t *testing.T) {
+	tests := []struct {
+		name        string
+		patchType   types.PatchType
+		patch       string
+		labels      []string
+		annotations []string
+	}{
+		{name: "merge", patchType: types.MergePatchType, patch: `{"metadata":{"labels":{"b":"1","a":null},"annotations":{"x/y":"z"}}}`, labels: []string{"a", "b"}, annotations: []string{"x/y"}},
+		{name: "merge without metadata", patchType: types.MergePatchType, patch: `{"spec":{"replicas":2}}`},
+		{name: "merge dropping labels", patchType: types.MergePatchType, patch: `{"metadata":{"labels":null}}`, labels: []string{""}},
+		{name: "strategic directive", patchType: types.StrategicMergePatchType, patch: `{"metadata":{"labels":{"$patch":"replace","a":"1"}}}`, labels: []string{"", "a"}},
+		{name: "json key", patchType: types.JSONPatchType, patch: `[{"op":"add","path":"/metadata/labels/example.com~1a","value":"1"}]`, labels: []string{"example.com/a"}},
+		{name: "json map", patchType: types.JSONPatchType, patch: `[{"op":"replace","path":"/metadata/annotations","value":{"a":"1"}}]`, annotations: []string{"a"}},
+		{name: "json remove map", patchType: types.JSONPatchType, patch: `[{"op":"remove","path":"/metadata/labels"}]`, labels: []string{""}},
+		{name: "json move", patchType: types.JSONPatchType, patch: `[{"op":"move","from":"/metadata/labels/a","path":"/metadata/labels/b"}]`, labels: []string{"b", "a"}},
+		{name: "json elsewhere", patchType: types.JSONPatchType, patch: `[{"op":"replace","path":"/spec/replicas","value":2}]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			labels, annotations, err := patchMetadataKeys(tt.patchType, []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("patchMetadataKeys: %v", err)
+			}
+			if !reflect.DeepEqual(labels, tt.labels) || !reflect.DeepEqual(annotations, tt.annotations) {
+				t.Fatalf("got labels=%q annotations=%q, want labels=%q annotations=%q", labels, annotations, tt.labels, tt.annotations)
+			}
+		})
+	}
+}
@@ -19,21 +19,43 @@ package k8stools
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"regexp"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"kubernetes-mcp/internal/authorization"
+	k8sclient "kubernetes-mcp/internal/kubernetes"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
 )
 
+const (
+	logsMaxBytes = 1 << 20 // 1 MiB
+
+	// logsContainerWorkers bounds the containers read at once by
+	// get_logs all_containers
+	logsContainerWorkers = 4
+)
+
+// containerLogs is the log of one container of a Pod
+type containerLogs struct {
+	container string
+	text      string
+	err       error
+}
+
 // eventTime returns the most precise timestamp available for an event,
 // preferring lastTimestamp and falling back to eventTime / firstTimestamp.
 // Used to sort newest-first.
@@ -58,52 +80,47 @@ Always combine 'tail_lines' or 'since_seconds' with this tool unless you are
 sure the log volume is small. A chatty container can return megabytes per
 second, which the model is not the right place to handle.
 
-For multi-container Pods you must set 'container'. To inspect logs from a
-crashed container that has been restarted, set 'previous: true'.`),
+For multi-container Pods you must set 'container', or set 'all_containers'
+to read every container (init containers first) with each line prefixed by
+'[container-name]', like 'kubectl logs --all-containers --prefix'. To inspect
+logs from a crashed container that has been restarted, set 'previous: true'.
+
+When the container has not started yet (ErrImagePull, CrashLoopBackOff,
+CreateContainerConfigError, ...) the error reports the waiting reason and
+message from the Pod status instead of the bare API error.`),
 		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
 		mcp.WithString("name", mcp.Required(), mcp.Description("Name of the Pod whose logs to fetch.")),
 		mcp.WithString("namespace", mcp.Description("Namespace where the Pod lives. Defaults to 'default' if empty.")),
 		mcp.WithString("container", mcp.Description("Name of the container inside the Pod. Required when the Pod has more than one container; ignored otherwise.")),
+		mcp.WithBoolean("all_containers", mcp.Description("If true, return the logs of every container of the Pod, init containers included, each line prefixed with '[container-name]'. Mutually exclusive with 'container'.")),
+		mcp.WithBoolean("container_prefix", mcp.Description("If true, prefix each line with '[container-name]' also when reading a single container. Always on with 'all_containers'.")),
 		mcp.WithBoolean("previous", mcp.Description("If true, return logs from the previous instance of the container (i.e. before the last restart). Useful to investigate crash loops. Fails if the container has never restarted.")),
 		mcp.WithNumber("since_seconds", mcp.Description("Only return logs newer than this many seconds. Integer >= 1. Omit or 0 to disable.")),
+		mcp.WithString("since_time", mcp.Description("Only return logs written at or after this RFC3339 time, e.g. '2025-01-02T15:04:05Z'. Mutually exclusive with 'since_seconds'.")),
 		mcp.WithNumber("tail_lines", mcp.Description("Return only the last N lines. Integer >= 1. Omit or 0 to return all logs (potentially huge).")),
 		mcp.WithBoolean("timestamps", mcp.Description("If true, prepend an RFC3339 timestamp to each line. Default false.")),
 	)
-	m.mcpServer.AddTool(tool, m.handleGetLogs)
+	m.addTool(tool, m.handleGetLogs)
 }
 
 func (m *Manager) handleGetLogs(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
 
-	k8sContext := m.getContextParam(args)
+	k8sContext := m.getContextParam(ctx, args)
 	name, _ := args["name"].(string)
 	namespace, _ := args["namespace"].(string)
 	if namespace == "" {
 		namespace = "default"
 	}
 	container, _ := args["container"].(string)
+	allContainers, _ := args["all_containers"].(bool)
+	containerPrefix, _ := args["container_prefix"].(bool)
 	previous, _ := args["previous"].(bool)
-	sinceSeconds, _ := args["since_seconds"].(float64)
 	tailLines, _ := args["tail_lines"].(float64)
 	timestamps, _ := args["timestamps"].(bool)
 
-	// Check authorization (real K8s resource: Pod)
-	if err := m.checkAuthorization(request, "get_logs", k8sContext, namespace, authorization.ResourceInfo{
-		Group:    "",
-		Version:  "v1",
-		Resource: "pods",
-		Name:     name,
-	}); err != nil {
-		return errorResult(err), nil
-	}
-
-	if !m.clientManager.IsNamespaceAllowed(k8sContext, namespace) {
-		return errorResult(fmt.Errorf("namespace %s is not allowed in context %s", namespace, k8sContext)), nil
-	}
-
-	client, err := m.clientManager.GetClient(k8sContext)
-	if err != nil {
-		return errorResult(err), nil
+	if allContainers && container != "" {
+		return errorResult(fmt.Errorf("all_containers and container are mutually exclusive")), nil
 	}
 
 	opts := &corev1.PodLogOptions{
@@ -111,10 +128,17 @@ func (m *Manager) handleGetLogs(ctx context.Context, request mcp.CallToolRequest
 		Previous:   previous,
 		Timestamps: timestamps,
 	}
+	if err := setLogsSince(opts, args); err != nil {
+		return errorResult(err), nil
+	}
 
-	if sinceSeconds > 0 {
-		since := int64(sinceSeconds)
-		opts.SinceSeconds = &since
+	if err := m.checkLogsAccess(request, "get_logs", k8sContext, namespace, name); err != nil {
+		return errorResult(err), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
 	}
 
 	if tailLines > 0 {
@@ -122,15 +146,18 @@ func (m *Manager) handleGetLogs(ctx context.Context, request mcp.CallToolRequest
 		opts.TailLines = &tail
 	}
 
+	if allContainers || containerPrefix {
+		return m.getPrefixedLogs(ctx, client.Clientset, namespace, name, container, allContainers, *opts)
+	}
+
 	req := client.Clientset.CoreV1().Pods(namespace).GetLogs(name, opts)
 	stream, err := req.Stream(ctx)
 	if err != nil {
-		return errorResult(err), nil
+		return errorResult(explainLogsError(ctx, client.Clientset, namespace, name, container, err)), nil
 	}
 	defer stream.Close()
 
 	// Cap output to avoid loading megabytes of logs into the model context.
-	const logsMaxBytes = 1 << 20 // 1 MiB
 	limited := io.LimitReader(stream, logsMaxBytes+1)
 
 	var buf bytes.Buffer
@@ -146,37 +173,240 @@ func (m *Manager) handleGetLogs(ctx context.Context, request mcp.CallToolRequest
 	return successResult(output), nil
 }
 
+// getPrefixedLogs serves get_logs with 'all_containers' or 'container_prefix':
+// every line is prefixed with its container, and containers are listed in
+// Pod spec order (init containers first) whatever order their reads end in.
+func (m *Manager) getPrefixedLogs(ctx context.Context, clientset kubernetes.Interface, namespace, name, container string,
+	allContainers bool, opts corev1.PodLogOptions) (*mcp.CallToolResult, error) {
+
+	pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	containers := []string{container}
+	switch {
+	case allContainers:
+		containers = containers[:0]
+		for _, c := range pod.Spec.InitContainers {
+			containers = append(containers, c.Name)
+		}
+		for _, c := range pod.Spec.Containers {
+			containers = append(containers, c.Name)
+		}
+	case container == "":
+		containers[0] = defaultContainer(pod)
+	}
+
+	results := fetchContainerLogs(ctx, clientset, pod, containers, opts)
+	if !allContainers && results[0].err != nil {
+		return errorResult(results[0].err), nil
+	}
+
+	var sb strings.Builder
+	for _, r := range results {
+		if r.err != nil {
+			fmt.Fprintf(&sb, "[%s] <error reading logs: %v>\n", r.container, r.err)
+			continue
+		}
+		for line := range strings.Lines(r.text) {
+			fmt.Fprintf(&sb, "[%s] %s", r.container, line)
+			if !strings.HasSuffix(line, "\n") {
+				sb.WriteByte('\n')
+			}
+		}
+	}
+
+	output := sb.String()
+	if len(output) > logsMaxBytes {
+		output = output[:logsMaxBytes] + "\n[... output truncated at 1MiB; use 'tail_lines' or 'since_seconds' to scope the request]"
+	}
+	return successResult(output), nil
+}
+
+// fetchContainerLogs reads the logs of several containers of a Pod, at most
+// logsContainerWorkers at a time. Results keep the order of 'containers'.
+func fetchContainerLogs(ctx context.Context, clientset kubernetes.Interface, pod *corev1.Pod, containers []string, opts corev1.PodLogOptions) []containerLogs {
+	results := make([]containerLogs, len(containers))
+	workers := make(chan struct{}, logsContainerWorkers)
+	var wg sync.WaitGroup
+
+	for i, container := range containers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			workers <- struct{}{}
+			defer func() { <-workers }()
+
+			containerOpts := opts
+			containerOpts.Container = container
+			results[i] = readContainerLogs(ctx, clientset, pod, &containerOpts)
+		}()
+	}
+
+	wg.Wait()
+	return results
+}
+
+// readContainerLogs reads up to logsMaxBytes of one container's log
+func readContainerLogs(ctx context.Context, clientset kubernetes.Interface, pod *corev1.Pod, opts *corev1.PodLogOptions) containerLogs {
+	result := containerLogs{container: opts.Container}
+
+	stream, err := clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, opts).Stream(ctx)
+	if err != nil {
+		if diagnosis := containerNotRunningError(pod, opts.Container); diagnosis != nil {
+			err = diagnosis
+		}
+		result.err = err
+		return result
+	}
+	defer stream.Close()
+
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, io.LimitReader(stream, logsMaxBytes)); err != nil {
+		result.err = err
+		return result
+	}
+	result.text = buf.String()
+	return result
+}
+
+// checkLogsAccess applies the authorization and namespace checks shared by
+// the tools that read a Pod's logs
+func (m *Manager) checkLogsAccess(request mcp.CallToolRequest, tool, k8sContext, namespace, name string) error {
+	// Check authorization (real K8s resource: Pod)
+	if err := m.checkAuthorization(request, tool, k8sContext, namespace, authorization.ResourceInfo{
+		Group:    "",
+		Version:  "v1",
+		Resource: "pods",
+		Name:     name,
+	}); err != nil {
+		return err
+	}
+
+	if !m.clientManager.IsNamespaceAllowed(k8sContext, namespace) {
+		return fmt.Errorf("namespace %s is not allowed in context %s", namespace, k8sContext)
+	}
+	return nil
+}
+
+// setLogsSince reads 'since_seconds' or 'since_time' (RFC3339) into opts
+func setLogsSince(opts *corev1.PodLogOptions, args map[string]any) error {
+	sinceSeconds, _ := args["since_seconds"].(float64)
+	sinceTime, _ := args["since_time"].(string)
+	if sinceSeconds > 0 && sinceTime != "" {
+		return fmt.Errorf("since_seconds and since_time are mutually exclusive")
+	}
+
+	if sinceSeconds > 0 {
+		since := int64(sinceSeconds)
+		opts.SinceSeconds = &since
+	}
+	if sinceTime != "" {
+		t, err := time.Parse(time.RFC3339, sinceTime)
+		if err != nil {
+			return fmt.Errorf("since_time must be an RFC3339 time such as '2025-01-02T15:04:05Z': %w", err)
+		}
+		since := metav1.NewTime(t)
+		opts.SinceTime = &since
+	}
+	return nil
+}
+
+// explainLogsError turns the API server's terse "container is waiting to
+// start" style errors into the actual reason reported in the Pod status
+// (ErrImagePull, CrashLoopBackOff, CreateContainerConfigError, ...). Any
+// other error, or a Pod that can't be read, yields the original error.
+func explainLogsError(ctx context.Context, clientset kubernetes.Interface, namespace, name, container string, logsErr error) error {
+	if !apierrors.IsBadRequest(logsErr) && !strings.Contains(logsErr.Error(), "waiting to start") {
+		return logsErr
+	}
+
+	pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return logsErr
+	}
+	if diagnosis := containerNotRunningError(pod, container); diagnosis != nil {
+		return diagnosis
+	}
+	return logsErr
+}
+
+// containerNotRunningError describes why a container has no logs to show, or
+// returns nil when the container is running (or can't be found). An empty
+// 'container' selects the Pod's default container.
+func containerNotRunningError(pod *corev1.Pod, container string) error {
+	if container == "" {
+		container = defaultContainer(pod)
+	}
+
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, cs := range statuses {
+		if cs.Name != container || cs.State.Waiting == nil {
+			continue
+		}
+
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "container %q in pod %s/%s is not running, so it has no logs yet\n", cs.Name, pod.Namespace, pod.Name)
+		fmt.Fprintf(&sb, "  Reason:   %s\n", cs.State.Waiting.Reason)
+		if cs.State.Waiting.Message != "" {
+			fmt.Fprintf(&sb, "  Message:  %s\n", cs.State.Waiting.Message)
+		}
+		fmt.Fprintf(&sb, "  Image:    %s\n", cs.Image)
+		fmt.Fprintf(&sb, "  Restarts: %d", cs.RestartCount)
+		if last := cs.LastTerminationState.Terminated; last != nil {
+			fmt.Fprintf(&sb, "\n  Last termination: %s (exit code %d)", last.Reason, last.ExitCode)
+			if last.Message != "" {
+				fmt.Fprintf(&sb, ": %s", last.Message)
+			}
+			sb.WriteString("\n  Hint: set 'previous: true' to read the logs of the crashed instance")
+		}
+		return fmt.Errorf("%s", sb.String())
+	}
+	return nil
+}
+
 func (m *Manager) registerExecCommand() {
+	maxTimeout := int(m.execMaxTimeout().Seconds())
 	tool := mcp.NewTool(m.toolName("exec_command"),
-		mcp.WithDescription(`Run a one-shot, non-interactive command inside a running container and
+		mcp.WithDescription(fmt.Sprintf(`Run a one-shot, non-interactive command inside a running container and
 return its stdout and stderr.
 
 Constraints:
   - Non-interactive (no TTY, no stdin). Anything that requires user input
     or paging will block until timeout.
-  - Default timeout 30 seconds, configurable via 'timeout_seconds' up to 300.
+  - Default timeout %d seconds, configurable via 'timeout_seconds' up to %d.
+    A command cut off by the timeout is reported as timed out, not failed.
   - Combined stdout+stderr is capped at 1 MiB; output beyond that is
     truncated with a clear marker.
   - The container must already exist (Pod in Running phase).
-  - When the command exits with a non-zero status, the result is reported
-    as an error (IsError=true) but the captured output is still included.
+  - The result starts with an 'exit_code: N' line (0 on success). When the
+    command exits with a non-zero status, the result is reported as an
+    error (IsError=true) but the exit code and captured output are still
+    included. Timeouts and transport failures carry no exit code.
 
 Typical uses: 'cat /etc/config.yaml', 'env', 'ps aux', 'ls /var/log'.
-Avoid 'top', 'tail -f', 'sh' and similar interactive sessions.`),
+Avoid 'top', 'tail -f', 'sh' and similar interactive sessions.
+
+'working_dir' and 'env' are applied by running the command through
+'/bin/sh' (values are shell-quoted, so they are passed literally), which
+means they need an image that ships a shell; distroless images don't.`, int(execDefaultTimeout.Seconds()), maxTimeout)),
 		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
 		mcp.WithString("name", mcp.Required(), mcp.Description("Name of the Pod to exec into.")),
 		mcp.WithString("namespace", mcp.Description("Namespace where the Pod lives. Defaults to 'default' if empty.")),
 		mcp.WithString("container", mcp.Description("Name of the container inside the Pod. Required when the Pod has more than one container.")),
 		mcp.WithArray("command", mcp.Required(), mcp.Description("Command and arguments as an array of strings. Example: [\"ls\", \"-la\", \"/var/log\"]. Use shell features by wrapping in 'sh -c': [\"sh\", \"-c\", \"echo $HOSTNAME && date\"].")),
-		mcp.WithNumber("timeout_seconds", mcp.Description("Hard timeout in seconds for the command. Integer 1..300. Defaults to 30.")),
+		mcp.WithNumber("timeout_seconds", mcp.Description(fmt.Sprintf("Hard timeout in seconds for the command. Integer 1..%d. Defaults to %d.", maxTimeout, int(execDefaultTimeout.Seconds())))),
+		mcp.WithString("working_dir", mcp.Description("Directory to run the command in. If empty, the container's default working directory is used.")),
+		mcp.WithObject("env", mcp.AdditionalProperties(map[string]any{"type": "string"}), mcp.Description("Extra environment variables for the command, as a map of string values. Names must match [A-Za-z_][A-Za-z0-9_]*. Example: {\"LOG_LEVEL\": \"debug\"}.")),
 	)
-	m.mcpServer.AddTool(tool, m.handleExecCommand)
+	m.addWaitingTool(tool, m.handleExecCommand, m.execMaxTimeout())
 }
 
 func (m *Manager) handleExecCommand(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
 
-	k8sContext := m.getContextParam(args)
+	k8sContext := m.getContextParam(ctx, args)
 	name, _ := args["name"].(string)
 	namespace, _ := args["namespace"].(string)
 	if namespace == "" {
@@ -184,19 +414,14 @@ func (m *Manager) handleExecCommand(ctx context.Context, request mcp.CallToolReq
 	}
 	container, _ := args["container"].(string)
 	commandArg, _ := args["command"].([]any)
-	timeoutSecs, _ := args["timeout_seconds"].(float64)
-
-	// Clamp timeout to [1, 300]; default 30.
-	timeout := 30 * time.Second
-	if timeoutSecs > 0 {
-		ts := int(timeoutSecs)
-		if ts < 1 {
-			ts = 1
-		}
-		if ts > 300 {
-			ts = 300
+
+	maxTimeout := int(m.execMaxTimeout().Seconds())
+	timeout := execDefaultTimeout
+	if v, ok := args["timeout_seconds"].(float64); ok {
+		if v < 1 || v > float64(maxTimeout) {
+			return errorResult(fmt.Errorf("timeout_seconds must be between 1 and %d, got %v", maxTimeout, v)), nil
 		}
-		timeout = time.Duration(ts) * time.Second
+		timeout = time.Duration(v) * time.Second
 	}
 
 	// Check authorization (real K8s resource: Pod)
@@ -224,27 +449,23 @@ func (m *Manager) handleExecCommand(ctx context.Context, request mcp.CallToolReq
 		return errorResult(fmt.Errorf("command is required")), nil
 	}
 
-	client, err := m.clientManager.GetClient(k8sContext)
+	workingDir, _ := args["working_dir"].(string)
+	env := map[string]string{}
+	if rawEnv, ok := args["env"].(map[string]any); ok {
+		for k, v := range rawEnv {
+			value, ok := v.(string)
+			if !ok {
+				return errorResult(fmt.Errorf("value of env var %q must be a string, got %T", k, v)), nil
+			}
+			env[k] = value
+		}
+	}
+	command, err := wrapExecCommand(command, workingDir, env)
 	if err != nil {
 		return errorResult(err), nil
 	}
 
-	req := client.Clientset.CoreV1().RESTClient().Post().
-		Resource("pods").
-		Name(name).
-		Namespace(namespace).
-		SubResource("exec")
-
-	req.VersionedParams(&corev1.PodExecOptions{
-		Container: container,
-		Command:   command,
-		Stdin:     false,
-		Stdout:    true,
-		Stderr:    true,
-		TTY:       false,
-	}, scheme.ParameterCodec)
-
-	exec, err := remotecommand.NewSPDYExecutor(client.Config, "POST", req.URL())
+	client, err := m.clientManager.GetClient(k8sContext)
 	if err != nil {
 		return errorResult(err), nil
 	}
@@ -256,7 +477,7 @@ func (m *Manager) handleExecCommand(ctx context.Context, request mcp.CallToolReq
 	execCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	streamErr := exec.StreamWithContext(execCtx, remotecommand.StreamOptions{
+	streamErr := streamPodExec(execCtx, client, namespace, name, container, command, remotecommand.StreamOptions{
 		Stdout: stdout,
 		Stderr: stderr,
 	})
@@ -271,19 +492,127 @@ func (m *Manager) handleExecCommand(ctx context.Context, request mcp.CallToolReq
 
 	if streamErr != nil {
 		// Non-zero exit, timeout, or transport error: surface as error result
-		// while preserving whatever output was captured.
+		// while preserving whatever output was captured. Only our own deadline
+		// counts as a timeout; a cancelled call is a plain failure.
+		text := fmt.Sprintf("Command failed: %v\n\nOutput:\n%s", streamErr, output)
+		if code, ok := execExitCode(streamErr); ok {
+			text = fmt.Sprintf("exit_code: %d\n\nCommand exited with a non-zero status.\n\nOutput:\n%s", code, output)
+		} else if errors.Is(execCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+			text = fmt.Sprintf("Command timed out after %s (timeout_seconds) and was stopped; it did not exit on its own.\n\nOutput so far:\n%s", timeout, output)
+		}
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
 				mcp.TextContent{
 					Type: "text",
-					Text: fmt.Sprintf("Command failed: %v\n\nOutput:\n%s", streamErr, output),
+					Text: text,
 				},
 			},
 			IsError: true,
 		}, nil
 	}
 
-	return successResult(output), nil
+	return successResult("exit_code: 0\n\n" + output), nil
+}
+
+// streamPodExec runs a non-interactive command in a container over the pods/exec
+// subresource. Stdin is attached only when 'streams' carries one.
+func streamPodExec(ctx context.Context, client *k8sclient.Client, namespace, name, container string, command []string, streams remotecommand.StreamOptions) error {
+	req := client.Clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(name).
+		Namespace(namespace).
+		SubResource("exec")
+
+	req.VersionedParams(&corev1.PodExecOptions{
+		Container: container,
+		Command:   command,
+		Stdin:     streams.Stdin != nil,
+		Stdout:    streams.Stdout != nil,
+		Stderr:    streams.Stderr != nil,
+		TTY:       false,
+	}, scheme.ParameterCodec)
+
+	exec, err := remotecommand.NewSPDYExecutor(client.Config, "POST", req.URL())
+	if err != nil {
+		return err
+	}
+	return exec.StreamWithContext(ctx, streams)
+}
+
+// execExitCode extracts the command's exit status from a stream error. The
+// remotecommand executor reports a non-zero exit as an ExitError; anything
+// else (timeout, transport, unknown container) has no exit code.
+func execExitCode(err error) (int, bool) {
+	var exitErr utilexec.ExitError
+	if errors.As(err, &exitErr) && exitErr.Exited() {
+		return exitErr.ExitStatus(), true
+	}
+	return 0, false
+}
+
+// exec_command timeout bounds ('timeout_seconds')
+const (
+	execDefaultTimeout = 30 * time.Second
+	// defaultExecMaxTimeout applies when 'kubernetes.tools.exec.max_timeout_seconds' is unset
+	defaultExecMaxTimeout = 300 * time.Second
+)
+
+// execMaxTimeout returns the longest 'timeout_seconds' exec_command accepts
+func (m *Manager) execMaxTimeout() time.Duration {
+	if secs := m.currentConfig().Kubernetes.Tools.Exec.MaxTimeoutSeconds; secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	return defaultExecMaxTimeout
+}
+
+// envVarName is the POSIX shell variable name syntax accepted in 'env'
+var envVarName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// wrapExecCommand applies a working directory and environment to an exec
+// command. PodExecOptions supports neither, so the command runs as
+//
+//	sh -c 'cd <dir> && export K=V && exec "$@"' -- <command...>
+//
+// where <dir> and every V are single-quoted and the original command is
+// passed as positional arguments, never interpolated into the script. The
+// command is returned unchanged when there is nothing to apply.
+func wrapExecCommand(command []string, workingDir string, env map[string]string) ([]string, error) {
+	if workingDir == "" && len(env) == 0 {
+		return command, nil
+	}
+
+	var steps []string
+	if workingDir != "" {
+		if strings.ContainsAny(workingDir, "\x00\n") {
+			return nil, fmt.Errorf("working_dir must not contain NUL or newline characters")
+		}
+		steps = append(steps, "cd "+shellQuote(workingDir))
+	}
+
+	names := make([]string, 0, len(env))
+	for name := range env {
+		if !envVarName.MatchString(name) {
+			return nil, fmt.Errorf("invalid env var name %q: must match [A-Za-z_][A-Za-z0-9_]*", name)
+		}
+		if strings.ContainsRune(env[name], 0) {
+			return nil, fmt.Errorf("value of env var %q must not contain NUL characters", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		steps = append(steps, "export "+name+"="+shellQuote(env[name]))
+	}
+
+	script := strings.Join(append(steps, `exec "$@"`), " && ")
+	return append([]string{"sh", "-c", script, "--"}, command...), nil
+}
+
+// shellQuote wraps a string in single quotes for POSIX sh. Inside single
+// quotes nothing is special except the quote itself, which is closed,
+// escaped and reopened.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
 }
 
 // cappedBuffer is a bytes.Buffer that stops accepting writes after `cap` bytes
@@ -322,23 +651,31 @@ kills, probe failures, etc. Events are time-bounded (the API server prunes
 them after a few hours by default), so don't rely on them for audit.
 
 Combine 'field_selector' and 'types' to narrow the noise. Use 'yq_expressions'
-to project just the fields you care about ('reason', 'message', 'involvedObject').`),
+to project just the fields you care about ('reason', 'message', 'involvedObject').
+
+'group=true' collapses repeated events (same object, type, reason and
+message, e.g. a probe failing every few seconds) into one row with the total
+count and the first / last time seen, like the aggregation of 'kubectl get
+events'. Far more compact than the raw list when a problem keeps recurring.`),
 		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
 		mcp.WithString("namespace", mcp.Description("Namespace to scope the listing to. Empty lists across ALL namespaces (subject to RBAC).")),
 		mcp.WithString("field_selector", mcp.Description("Field selector. Common keys: 'involvedObject.name', 'involvedObject.kind', 'involvedObject.namespace', 'reason', 'type'. Example: 'involvedObject.name=my-pod,type=Warning'.")),
 		mcp.WithArray("types", mcp.Description("Filter by event type. Accepts an array containing any of: 'Normal', 'Warning'. Empty or omitted means no type filter.")),
-		mcp.WithArray("yq_expressions", mcp.Description("Optional yq expressions applied to the events list. The output is an EventList so use '.items[]' to iterate. Examples: '.items[] | select(.type == \"Warning\") | .message' (all warning messages), '.items[] | {when: .lastTimestamp, reason: .reason, msg: .message}' (compact view).")),
+		mcp.WithBoolean("group", mcp.Description("Collapse identical events into one row with 'count', 'first_seen' and 'last_seen'. Defaults to false.")),
+		mcp.WithArray("yq_expressions", mcp.Description("Optional yq expressions applied to the events list. The output is an EventList (with 'group=true', a list of grouped rows) so use '.items[]' to iterate. Examples: '.items[] | select(.type == \"Warning\") | .message' (all warning messages), '.items[] | {when: .lastTimestamp, reason: .reason, msg: .message}' (compact view).")),
+		jqExpressionsParam(),
 	)
-	m.mcpServer.AddTool(tool, m.handleListEvents)
+	m.addTool(tool, m.handleListEvents)
 }
 
 func (m *Manager) handleListEvents(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
 
-	k8sContext := m.getContextParam(args)
+	k8sContext := m.getContextParam(ctx, args)
 	namespace, _ := args["namespace"].(string)
 	fieldSelector, _ := args["field_selector"].(string)
 	eventTypes, _ := args["types"].([]any)
+	group, _ := args["group"].(bool)
 
 	// Check authorization (real K8s resource: Event)
 	if err := m.checkAuthorization(request, "list_events", k8sContext, namespace, authorization.ResourceInfo{
@@ -424,16 +761,116 @@ func (m *Manager) handleListEvents(ctx context.Context, request mcp.CallToolRequ
 		return eventTime(events.Items[i]).After(eventTime(events.Items[j]))
 	})
 
-	yamlOutput, err := objectToYAML(events)
+	var output any = events
+	if group {
+		output = map[string]any{"items": groupEvents(events.Items)}
+	}
+
+	yamlOutput, err := objectToYAML(output)
 	if err != nil {
 		return errorResult(err), nil
 	}
 
 	// Apply yq expressions
-	finalOutput, err := m.applyYQExpressions(yamlOutput, args)
+	finalOutput, err := m.applyOutputFilters(yamlOutput, args)
 	if err != nil {
 		return errorResult(err), nil
 	}
 
 	return successResult(finalOutput), nil
 }
+
+// eventGroupKey identifies repeats of the same event
+type eventGroupKey struct {
+	namespace, kind, name, eventType, reason, message string
+}
+
+// groupEvents collapses events with the same involved object, type, reason
+// and message into one row. 'events' must be sorted newest first; the rows
+// keep that order by their last occurrence.
+func groupEvents(events []corev1.Event) []map[string]any {
+	type eventGroup struct {
+		event               corev1.Event
+		count               int32
+		firstSeen, lastSeen time.Time
+	}
+
+	var order []eventGroupKey
+	groups := map[eventGroupKey]*eventGroup{}
+	for _, e := range events {
+		key := eventGroupKey{
+			namespace: e.InvolvedObject.Namespace,
+			kind:      e.InvolvedObject.Kind,
+			name:      e.InvolvedObject.Name,
+			eventType: e.Type,
+			reason:    e.Reason,
+			message:   e.Message,
+		}
+		first, last := eventFirstTime(e), eventTime(e)
+
+		g, ok := groups[key]
+		if !ok {
+			g = &eventGroup{event: e, firstSeen: first, lastSeen: last}
+			groups[key] = g
+			order = append(order, key)
+		}
+		g.count += eventCount(e)
+		if first.Before(g.firstSeen) {
+			g.firstSeen = first
+		}
+		if last.After(g.lastSeen) {
+			g.lastSeen = last
+		}
+	}
+
+	out := make([]map[string]any, 0, len(order))
+	for _, key := range order {
+		g := groups[key]
+		row := map[string]any{
+			"object":  fmt.Sprintf("%s/%s", key.kind, key.name),
+			"type":    key.eventType,
+			"reason":  key.reason,
+			"message": key.message,
+			"count":   g.count,
+		}
+		if key.namespace != "" {
+			row["namespace"] = key.namespace
+		}
+		if !g.firstSeen.IsZero() {
+			row["first_seen"] = g.firstSeen.UTC().Format("2006-01-02T15:04:05Z")
+		}
+		if !g.lastSeen.IsZero() {
+			row["last_seen"] = g.lastSeen.UTC().Format("2006-01-02T15:04:05Z")
+		}
+		if source := g.event.Source.Component; source != "" {
+			row["source"] = source
+		}
+		out = append(out, row)
+	}
+	return out
+}
+
+// eventCount is how many times an event occurred: the API server folds
+// repeats into 'count' (core/v1) or 'series.count' (events.k8s.io writers).
+func eventCount(e corev1.Event) int32 {
+	count := e.Count
+	if e.Series != nil && e.Series.Count > count {
+		count = e.Series.Count
+	}
+	if count < 1 {
+		count = 1
+	}
+	return count
+}
+
+// eventFirstTime returns when an event was first seen, the counterpart of
+// eventTime.
+func eventFirstTime(e corev1.Event) time.Time {
+	if !e.FirstTimestamp.IsZero() {
+		return e.FirstTimestamp.Time
+	}
+	if !e.EventTime.IsZero() {
+		return e.EventTime.Time
+	}
+	return eventTime(e)
+}
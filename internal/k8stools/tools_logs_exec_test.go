@@ -0,0 +1,274 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+func waitingPod(reason, message string) *corev1.Pod {
+	pod := fakePod("default", "web", nil)
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name:  "app",
+		Image: "nginx:1.27",
+		State: corev1.ContainerState{
+			Waiting: &corev1.ContainerStateWaiting{Reason: reason, Message: message},
+		},
+	}}
+	return pod
+}
+
+func TestExplainLogsError_Waiting(t *testing.T) {
+	e := newFakeEnv(t, waitingPod("ErrImagePull", "manifest unknown"))
+	logsErr := apierrors.NewBadRequest(`container "app" in pod "web" is waiting to start: trying and failing to pull image`)
+
+	err := explainLogsError(context.Background(), e.clientset, "default", "web", "", logsErr)
+	requireContains(t, err.Error(), "Reason:   ErrImagePull", "expected waiting reason")
+	requireContains(t, err.Error(), "Message:  manifest unknown", "expected waiting message")
+	requireContains(t, err.Error(), "Image:    nginx:1.27", "expected image")
+}
+
+func TestExplainLogsError_CrashLoop(t *testing.T) {
+	pod := waitingPod("CrashLoopBackOff", "back-off 5m0s restarting failed container")
+	pod.Status.ContainerStatuses[0].RestartCount = 7
+	pod.Status.ContainerStatuses[0].LastTerminationState.Terminated = &corev1.ContainerStateTerminated{
+		Reason:   "Error",
+		ExitCode: 1,
+	}
+
+	err := containerNotRunningError(pod, "app")
+	if err == nil {
+		t.Fatalf("expected a diagnosis")
+	}
+	requireContains(t, err.Error(), "Restarts: 7", "expected restart count")
+	requireContains(t, err.Error(), "Last termination: Error (exit code 1)", "expected last termination")
+	requireContains(t, err.Error(), "previous: true", "expected hint")
+}
+
+func TestExplainLogsError_Passthrough(t *testing.T) {
+	running := fakePod("default", "web", nil)
+	running.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name:  "app",
+		State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+	}}
+	e := newFakeEnv(t, running)
+
+	// Unrelated errors are never enriched
+	other := errors.New("connection refused")
+	if err := explainLogsError(context.Background(), e.clientset, "default", "web", "", other); err != other {
+		t.Fatalf("expected the original error, got %v", err)
+	}
+
+	// A running container has nothing to explain
+	badRequest := apierrors.NewBadRequest("previous terminated container \"app\" in pod \"web\" not found")
+	if err := explainLogsError(context.Background(), e.clientset, "default", "web", "app", badRequest); err != badRequest {
+		t.Fatalf("expected the original error, got %v", err)
+	}
+}
+
+func TestGetLogs_AllContainers(t *testing.T) {
+	pod := fakePod("default", "web", nil)
+	pod.Spec.InitContainers = []corev1.Container{{Name: "migrate"}}
+	pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: "proxy"}, corev1.Container{Name: "agent"})
+	e := newFakeEnv(t, pod)
+
+	// Output follows the Pod spec, init containers first, however the
+	// concurrent reads finish.
+	for range 5 {
+		res, err := e.manager.handleGetLogs(context.Background(), makeRequest(map[string]any{
+			"name":           "web",
+			"all_containers": true,
+		}))
+		if err != nil {
+			t.Fatalf("go-error: %v", err)
+		}
+		out := expectOK(t, res, "get_logs all_containers")
+		want := "[migrate] fake logs\n[app] fake logs\n[proxy] fake logs\n[agent] fake logs\n"
+		if out != want {
+			t.Fatalf("unexpected output:\n%s", out)
+		}
+	}
+
+	res, err := e.manager.handleGetLogs(context.Background(), makeRequest(map[string]any{
+		"name":             "web",
+		"container_prefix": true,
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	if out := expectOK(t, res, "get_logs container_prefix"); out != "[app] fake logs\n" {
+		t.Fatalf("expected the default container prefixed, got %q", out)
+	}
+
+	res, err = e.manager.handleGetLogs(context.Background(), makeRequest(map[string]any{
+		"name":           "web",
+		"container":      "app",
+		"all_containers": true,
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	requireContains(t, expectErr(t, res, "both container flags"), "mutually exclusive", "expected a conflict error")
+}
+
+func TestExecCommand_TimeoutBounds(t *testing.T) {
+	e := newFakeEnv(t, fakePod("default", "web", nil))
+	run := func(timeout float64) *mcp.CallToolResult {
+		t.Helper()
+		res, err := e.manager.handleExecCommand(context.Background(), makeRequest(map[string]any{
+			"name":            "web",
+			"command":         []any{"true"},
+			"timeout_seconds": timeout,
+		}))
+		if err != nil {
+			t.Fatalf("go-error: %v", err)
+		}
+		return res
+	}
+
+	requireContains(t, expectErr(t, run(301), "above default max"), "between 1 and 300", "expected the default cap")
+	requireContains(t, expectErr(t, run(0), "zero timeout"), "between 1 and 300", "expected the lower bound")
+
+	e.manager.config.Kubernetes.Tools.Exec.MaxTimeoutSeconds = 900
+	if e.manager.execMaxTimeout() != 900*time.Second {
+		t.Fatalf("expected the configured max, got %s", e.manager.execMaxTimeout())
+	}
+	requireContains(t, expectErr(t, run(901), "above configured max"), "between 1 and 900", "expected the configured cap")
+}
+
+func TestExecExitCode(t *testing.T) {
+	exitErr := utilexec.CodeExitError{Err: errors.New("command terminated with exit code 3"), Code: 3}
+	if code, ok := execExitCode(fmt.Errorf("stream: %w", exitErr)); !ok || code != 3 {
+		t.Fatalf("expected exit code 3, got %d (ok=%v)", code, ok)
+	}
+	if _, ok := execExitCode(context.DeadlineExceeded); ok {
+		t.Fatalf("a timeout must not carry an exit code")
+	}
+}
+
+func TestWrapExecCommand(t *testing.T) {
+	command := []string{"ls", "-la"}
+
+	unchanged, err := wrapExecCommand(command, "", nil)
+	if err != nil || strings.Join(unchanged, " ") != "ls -la" {
+		t.Fatalf("expected the command unchanged, got %v (%v)", unchanged, err)
+	}
+
+	wrapped, err := wrapExecCommand(command, "/srv/app dir", map[string]string{"B": "2", "A": "it's"})
+	if err != nil {
+		t.Fatalf("wrap: %v", err)
+	}
+	want := []string{"sh", "-c", `cd '/srv/app dir' && export A='it'\''s' && export B='2' && exec "$@"`, "--", "ls", "-la"}
+	if strings.Join(wrapped, "\x00") != strings.Join(want, "\x00") {
+		t.Fatalf("unexpected wrapper:\n got: %q\nwant: %q", wrapped, want)
+	}
+
+	for _, tt := range []struct {
+		dir  string
+		env  map[string]string
+		want string
+	}{
+		{env: map[string]string{"BAD-NAME": "x"}, want: "invalid env var name"},
+		{env: map[string]string{"X; rm -rf /": "x"}, want: "invalid env var name"},
+		{dir: "/tmp\nrm -rf /", want: "working_dir must not contain"},
+	} {
+		if _, err := wrapExecCommand(command, tt.dir, tt.env); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Fatalf("expected error %q, got %v", tt.want, err)
+		}
+	}
+}
+
+// TestWrapExecCommand_Shell runs the wrapper through a real shell to prove
+// hostile values stay literal.
+func TestWrapExecCommand_Shell(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	dir := t.TempDir()
+
+	wrapped, err := wrapExecCommand(
+		[]string{"sh", "-c", `printf '%s|%s' "$PWD" "$PAYLOAD"`},
+		dir,
+		map[string]string{"PAYLOAD": `$(echo pwned) '; echo pwned; '` + "\nline2"},
+	)
+	if err != nil {
+		t.Fatalf("wrap: %v", err)
+	}
+	out, err := exec.Command(wrapped[0], wrapped[1:]...).Output()
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	want := dir + `|$(echo pwned) '; echo pwned; '` + "\nline2"
+	if string(out) != want {
+		t.Fatalf("unexpected output:\n got: %q\nwant: %q", out, want)
+	}
+}
+
+func repeatedEvent(name string, count int32, first, last time.Time) *corev1.Event {
+	return &corev1.Event{
+		TypeMeta:       metav1.TypeMeta{APIVersion: "v1", Kind: "Event"},
+		ObjectMeta:     metav1.ObjectMeta{Namespace: "default", Name: name},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "default", Name: "web"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         "Unhealthy",
+		Message:        "Readiness probe failed: HTTP probe failed with statuscode: 503",
+		Count:          count,
+		FirstTimestamp: metav1.NewTime(first),
+		LastTimestamp:  metav1.NewTime(last),
+	}
+}
+
+func TestListEvents_Group(t *testing.T) {
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	pulled := repeatedEvent("web.pulled", 1, base, base)
+	pulled.Type, pulled.Reason, pulled.Message = corev1.EventTypeNormal, "Pulled", "Successfully pulled image"
+	e := newFakeEnv(t,
+		repeatedEvent("web.1", 12, base.Add(time.Minute), base.Add(5*time.Minute)),
+		repeatedEvent("web.2", 3, base.Add(6*time.Minute), base.Add(8*time.Minute)),
+		pulled,
+	)
+
+	res, err := e.manager.handleListEvents(context.Background(), makeRequest(map[string]any{
+		"namespace": "default",
+		"group":     true,
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "list_events group")
+	if n := strings.Count(out, "reason: Unhealthy"); n != 1 {
+		t.Fatalf("expected the probe failures in one row, got %d:\n%s", n, out)
+	}
+	requireContains(t, out, "count: 15", "expected the counts to be summed")
+	requireContains(t, out, `first_seen: "2025-01-01T10:01:00Z"`, "expected the earliest first timestamp")
+	requireContains(t, out, `last_seen: "2025-01-01T10:08:00Z"`, "expected the latest last timestamp")
+	if strings.Index(out, "Unhealthy") > strings.Index(out, "Pulled") {
+		t.Fatalf("expected the most recent group first:\n%s", out)
+	}
+}
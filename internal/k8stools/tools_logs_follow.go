@@ -0,0 +1,221 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	followLogsDefaultTimeout = 15
+	followLogsMaxTimeout     = 300
+	followLogsDefaultLines   = 1000
+	followLogsMaxBytes       = 1 << 20 // 1 MiB, same cap as get_logs
+
+	followLogsProgressInterval = 5 * time.Second
+)
+
+// followedLogs is what a bounded follow collected
+type followedLogs struct {
+	text  string
+	lines int
+	bytes int64
+	// limit names the cap that ended the follow, empty when the stream
+	// ended or was closed first
+	limit string
+}
+
+func (m *Manager) registerFollowLogs() {
+	tool := mcp.NewTool(m.toolName("follow_logs"),
+		mcp.WithDescription(`Follow a container's logs live for a bounded time and return what was written:
+"what does the app log while I send this request?".
+
+Unlike 'get_logs', which returns a snapshot, this keeps the stream open and
+collects new lines until 'timeout_seconds' elapses (default 15, max 300),
+'max_lines' lines (default 1000) or 'max_bytes' bytes (default and max
+1 MiB) were read, or the container stops. Existing lines are included
+first; narrow them with 'since_seconds', 'since_time' or 'tail_lines', or
+set 'tail_lines: 0' to see only new output.
+
+To wait for one specific line, use 'wait_for_log_pattern' instead.`),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Name of the Pod whose logs to follow.")),
+		mcp.WithString("namespace", mcp.Description("Namespace where the Pod lives. Defaults to 'default' if empty.")),
+		mcp.WithString("container", mcp.Description("Name of the container inside the Pod. Required when the Pod has more than one container.")),
+		mcp.WithNumber("since_seconds", mcp.Description("Only include log lines newer than this many seconds. Integer >= 1.")),
+		mcp.WithString("since_time", mcp.Description("Only include log lines written at or after this RFC3339 time, e.g. '2025-01-02T15:04:05Z'. Mutually exclusive with 'since_seconds'.")),
+		mcp.WithNumber("tail_lines", mcp.Description("Start with only the last N existing lines. Integer >= 0; 0 starts with new lines only.")),
+		mcp.WithNumber("timeout_seconds", mcp.Description("How long to follow. Integer 1..300. Defaults to 15.")),
+		mcp.WithNumber("max_lines", mcp.Description("Stop after this many lines. Integer >= 1. Defaults to 1000.")),
+		mcp.WithNumber("max_bytes", mcp.Description("Stop after this many bytes. Integer 1..1048576. Defaults to 1048576 (1 MiB).")),
+		mcp.WithBoolean("timestamps", mcp.Description("If true, prepend an RFC3339 timestamp to each line. Default false.")),
+	)
+	m.addWaitingTool(tool, m.handleFollowLogs, followLogsMaxTimeout*time.Second)
+}
+
+func (m *Manager) handleFollowLogs(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	name, _ := args["name"].(string)
+	namespace, _ := args["namespace"].(string)
+	if namespace == "" {
+		namespace = "default"
+	}
+	container, _ := args["container"].(string)
+	timestamps, _ := args["timestamps"].(bool)
+
+	timeout := followLogsDefaultTimeout
+	if v, ok := args["timeout_seconds"].(float64); ok {
+		if v < 1 || v > followLogsMaxTimeout {
+			return errorResult(fmt.Errorf("timeout_seconds must be between 1 and %d, got %v", followLogsMaxTimeout, v)), nil
+		}
+		timeout = int(v)
+	}
+	maxLines := followLogsDefaultLines
+	if v, ok := args["max_lines"].(float64); ok {
+		if v < 1 {
+			return errorResult(fmt.Errorf("max_lines must be >= 1, got %v", v)), nil
+		}
+		maxLines = int(v)
+	}
+	maxBytes := int64(followLogsMaxBytes)
+	if v, ok := args["max_bytes"].(float64); ok {
+		if v < 1 || v > followLogsMaxBytes {
+			return errorResult(fmt.Errorf("max_bytes must be between 1 and %d, got %v", followLogsMaxBytes, v)), nil
+		}
+		maxBytes = int64(v)
+	}
+
+	opts := &corev1.PodLogOptions{
+		Container:  container,
+		Follow:     true,
+		Timestamps: timestamps,
+	}
+	if err := setLogsSince(opts, args); err != nil {
+		return errorResult(err), nil
+	}
+	if v, ok := args["tail_lines"].(float64); ok {
+		if v < 0 {
+			return errorResult(fmt.Errorf("tail_lines must be >= 0, got %v", v)), nil
+		}
+		tail := int64(v)
+		opts.TailLines = &tail
+	}
+
+	if err := m.checkLogsAccess(request, "follow_logs", k8sContext, namespace, name); err != nil {
+		return errorResult(err), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	followCtx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	stream, err := client.StreamingClientset().CoreV1().Pods(namespace).GetLogs(name, opts).Stream(followCtx)
+	if err != nil {
+		return errorResult(explainLogsError(ctx, client.Clientset, namespace, name, container, err)), nil
+	}
+	defer stream.Close()
+
+	// Closing the stream when the deadline passes or the MCP call goes away
+	// unblocks the reader, so the connection is never left behind.
+	progress := m.newProgressReporter(ctx, request)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(followLogsProgressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-followCtx.Done():
+				stream.Close()
+				return
+			case <-ticker.C:
+				followed := time.Since(start).Round(time.Second)
+				progress.Report(followed.Seconds(), float64(timeout),
+					fmt.Sprintf("following logs of %s/%s: %s of %ds elapsed", namespace, name, followed, timeout))
+			}
+		}
+	}()
+
+	logs := readFollowedLogs(stream, maxLines, maxBytes)
+	elapsed := time.Since(start).Round(time.Second)
+
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return errorResult(ctx.Err()), nil
+	}
+
+	var reason string
+	switch {
+	case logs.limit != "":
+		reason = logs.limit + " reached"
+	case errors.Is(followCtx.Err(), context.DeadlineExceeded):
+		reason = fmt.Sprintf("timeout_seconds (%ds) reached", timeout)
+	default:
+		reason = "the log stream ended (container stopped)"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(logs.text)
+	fmt.Fprintf(&sb, "[followed %s/%s for %s: %d lines, %d bytes; %s]", namespace, name, elapsed, logs.lines, logs.bytes, reason)
+	return successResult(sb.String()), nil
+}
+
+// readFollowedLogs reads whole lines until the reader ends or a cap is hit.
+// A line that would cross 'maxBytes' is not included.
+func readFollowedLogs(r io.Reader, maxLines int, maxBytes int64) followedLogs {
+	var result followedLogs
+	var sb strings.Builder
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), followLogsMaxBytes)
+	for scanner.Scan() {
+		line := scanner.Text()
+		size := int64(len(line)) + 1
+		if result.bytes+size > maxBytes {
+			result.limit = "max_bytes"
+			break
+		}
+		sb.WriteString(line)
+		sb.WriteByte('\n')
+		result.bytes += size
+		result.lines++
+		if result.lines >= maxLines {
+			result.limit = "max_lines"
+			break
+		}
+	}
+
+	result.text = sb.String()
+	return result
+}
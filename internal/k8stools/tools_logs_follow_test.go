@@ -0,0 +1,121 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestReadFollowedLogs(t *testing.T) {
+	logs := "one\ntwo\nthree\nfour\n"
+
+	result := readFollowedLogs(strings.NewReader(logs), 2, 1<<20)
+	if result.text != "one\ntwo\n" || result.limit != "max_lines" {
+		t.Fatalf("expected to stop after two lines, got %+v", result)
+	}
+
+	// "one\ntwo\n" is 8 bytes; the third line would cross the cap
+	result = readFollowedLogs(strings.NewReader(logs), 100, 10)
+	if result.text != "one\ntwo\n" || result.bytes != 8 || result.limit != "max_bytes" {
+		t.Fatalf("expected to stop before crossing max_bytes, got %+v", result)
+	}
+
+	result = readFollowedLogs(strings.NewReader(logs), 100, 1<<20)
+	if result.lines != 4 || result.limit != "" {
+		t.Fatalf("expected the whole stream, got %+v", result)
+	}
+}
+
+func TestSetLogsSince(t *testing.T) {
+	opts := &corev1.PodLogOptions{}
+	if err := setLogsSince(opts, map[string]any{"since_time": "2025-01-02T15:04:05Z"}); err != nil {
+		t.Fatalf("setLogsSince: %v", err)
+	}
+	if opts.SinceTime == nil || !opts.SinceTime.Time.Equal(time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)) {
+		t.Fatalf("unexpected since time: %v", opts.SinceTime)
+	}
+
+	if err := setLogsSince(&corev1.PodLogOptions{}, map[string]any{"since_time": "yesterday"}); err == nil {
+		t.Fatalf("expected a non-RFC3339 time to be rejected")
+	}
+	err := setLogsSince(&corev1.PodLogOptions{}, map[string]any{"since_seconds": float64(60), "since_time": "2025-01-02T15:04:05Z"})
+	if err == nil || !strings.Contains(err.Error(), "mutually exclusive") {
+		t.Fatalf("expected since_seconds and since_time to conflict, got %v", err)
+	}
+}
+
+func TestFollowLogs(t *testing.T) {
+	// The fake clientset serves "fake logs" for every Pod and then ends
+	e := newFakeEnv(t, fakePod("default", "web", nil))
+
+	res, err := e.manager.handleFollowLogs(context.Background(), makeRequest(map[string]any{
+		"name":       "web",
+		"since_time": "2025-01-02T15:04:05Z",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "follow_logs")
+	requireContains(t, out, "fake logs\n", "expected the streamed line")
+	requireContains(t, out, "1 lines, 10 bytes; the log stream ended", "expected the follow summary")
+
+	// A dropped MCP connection ends the follow with an error, not a result
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	res, err = e.manager.handleFollowLogs(ctx, makeRequest(map[string]any{"name": "web"}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	expectErr(t, res, "cancelled follow")
+}
+
+func TestFollowLogs_Validation(t *testing.T) {
+	e := newFakeEnv(t, fakePod("default", "web", nil))
+	e.provider.deniedNamespaces = []string{"kube-system"}
+
+	tests := []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{name: "timeout too long", args: map[string]any{"timeout_seconds": float64(301)}, want: "timeout_seconds must be between 1 and 300"},
+		{name: "zero lines", args: map[string]any{"max_lines": float64(0)}, want: "max_lines must be >= 1"},
+		{name: "too many bytes", args: map[string]any{"max_bytes": float64(2 << 20)}, want: "max_bytes must be between 1 and 1048576"},
+		{name: "bad since_time", args: map[string]any{"since_time": "1h"}, want: "since_time must be an RFC3339 time"},
+		{name: "denied namespace", args: map[string]any{"namespace": "kube-system"}, want: "namespace kube-system is not allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := map[string]any{"name": "web"}
+			for k, v := range tt.args {
+				args[k] = v
+			}
+			res, err := e.manager.handleFollowLogs(context.Background(), makeRequest(args))
+			if err != nil {
+				t.Fatalf("go-error: %v", err)
+			}
+			text := expectErr(t, res, tt.name)
+			requireContains(t, text, tt.want, "unexpected error text")
+		})
+	}
+}
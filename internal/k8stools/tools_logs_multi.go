@@ -0,0 +1,291 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"kubernetes-mcp/internal/authorization"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	multiLogsDefaultMaxPods   = 20
+	multiLogsMaxPodsLimit     = 100
+	multiLogsDefaultTailLines = 100
+	multiLogsMaxBytes         = 1 << 20 // 1 MiB, same cap as get_logs
+)
+
+// logLine is one line of a multi-context log stream
+type logLine struct {
+	source string
+	time   time.Time
+	text   string
+}
+
+func (m *Manager) registerGetLogsMultiContext() {
+	tool := mcp.NewTool(m.toolName("get_logs_multi_context"),
+		mcp.WithDescription(`Fetch recent logs of the Pods matching a label selector in SEVERAL contexts
+at once, for cross-cluster incident investigation.
+
+Each line is prefixed with its source as '[context/pod/container]'. With
+'timestamps=true' (the default) lines from every Pod and context are
+interleaved in timestamp order; with 'timestamps=false' they are grouped by
+context and Pod.
+
+Bounded on purpose: at most 'max_pods' Pods in total (default 20) and 1 MiB
+of combined output. Contexts that fail (unknown, unreachable, not
+authorized) are reported individually without aborting the others.`),
+		mcp.WithArray("contexts", mcp.Required(), mcp.Description("Contexts to query, e.g. [\"prod-eu\", \"prod-us\"]. See 'list_contexts'.")),
+		mcp.WithString("namespace", mcp.Description("Namespace of the Pods in every context. Defaults to 'default' if empty.")),
+		mcp.WithString("label_selector", mcp.Required(), mcp.Description("Kubernetes label selector for the Pods, e.g. 'app=checkout'.")),
+		mcp.WithString("container", mcp.Description("Container name. If empty, each Pod's default container is used ('kubectl.kubernetes.io/default-container' annotation, else the first container).")),
+		mcp.WithNumber("since_seconds", mcp.Description("Only return logs newer than this many seconds. Integer >= 1.")),
+		mcp.WithNumber("tail_lines", mcp.Description("Last N lines per Pod. Integer >= 1. Defaults to 100.")),
+		mcp.WithBoolean("timestamps", mcp.Description("Interleave lines by timestamp (default true). Set false to group by context and Pod instead.")),
+		mcp.WithNumber("max_pods", mcp.Description("Maximum number of Pods across all contexts. Integer 1..100. Defaults to 20.")),
+	)
+	m.addTool(tool, m.handleGetLogsMultiContext)
+}
+
+func (m *Manager) handleGetLogsMultiContext(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	var contexts []string
+	if raw, ok := args["contexts"].([]any); ok {
+		for _, c := range raw {
+			if s, ok := c.(string); ok && s != "" {
+				contexts = append(contexts, s)
+			}
+		}
+	}
+	if len(contexts) == 0 {
+		return errorResult(fmt.Errorf("at least one context is required in 'contexts'")), nil
+	}
+
+	namespace, _ := args["namespace"].(string)
+	if namespace == "" {
+		namespace = "default"
+	}
+	labelSelector, _ := args["label_selector"].(string)
+	if labelSelector == "" {
+		return errorResult(fmt.Errorf("label_selector is required")), nil
+	}
+	container, _ := args["container"].(string)
+
+	timestamps := true
+	if v, ok := args["timestamps"].(bool); ok {
+		timestamps = v
+	}
+
+	opts := corev1.PodLogOptions{Container: container, Timestamps: timestamps}
+	tail := int64(multiLogsDefaultTailLines)
+	if v, ok := args["tail_lines"].(float64); ok && v >= 1 {
+		tail = int64(v)
+	}
+	opts.TailLines = &tail
+	if v, ok := args["since_seconds"].(float64); ok && v >= 1 {
+		since := int64(v)
+		opts.SinceSeconds = &since
+	}
+
+	maxPods := multiLogsDefaultMaxPods
+	if v, ok := args["max_pods"].(float64); ok {
+		if v < 1 || v > multiLogsMaxPodsLimit {
+			return errorResult(fmt.Errorf("max_pods must be between 1 and %d, got %v", multiLogsMaxPodsLimit, v)), nil
+		}
+		maxPods = int(v)
+	}
+
+	aggregate := NewAggregateResult()
+	var lines []logLine
+	budget := multiLogsMaxBytes
+	podsLeft := maxPods
+	truncated := false
+	progress := m.newProgressReporter(ctx, request)
+
+	for i, k8sContext := range contexts {
+		progress.Report(float64(i), float64(len(contexts)),
+			fmt.Sprintf("%d/%d contexts queried, reading %s", i, len(contexts), k8sContext))
+
+		if podsLeft == 0 || budget <= 0 {
+			aggregate.AddError(k8sContext, fmt.Errorf("skipped: pod or byte budget exhausted"), "")
+			truncated = true
+			continue
+		}
+
+		contextLines, pods, err := m.collectContextLogs(ctx, request, k8sContext, namespace, labelSelector, opts, &podsLeft, &budget, &truncated)
+		if err != nil {
+			aggregate.AddError(k8sContext, err, "")
+			continue
+		}
+		lines = append(lines, contextLines...)
+		aggregate.AddSuccess(k8sContext, fmt.Sprintf("%d pods, %d lines", pods, len(contextLines)))
+	}
+
+	if timestamps {
+		sortLogLines(lines)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(aggregate.Render())
+	sb.WriteString("\n--- Logs ---\n")
+	for _, l := range lines {
+		fmt.Fprintf(&sb, "[%s] %s\n", l.source, l.text)
+	}
+	if truncated {
+		fmt.Fprintf(&sb, "[... output truncated: limited to %d pods and 1MiB; narrow 'label_selector' or use 'tail_lines' / 'since_seconds']\n", maxPods)
+	}
+
+	result := successResult(sb.String())
+	if aggregate.Succeeded() == 0 {
+		result.IsError = true
+	}
+	return result, nil
+}
+
+// collectContextLogs reads the logs of the Pods matching the selector in one
+// context, consuming the shared pod and byte budgets.
+func (m *Manager) collectContextLogs(ctx context.Context, request mcp.CallToolRequest, k8sContext, namespace, labelSelector string,
+	opts corev1.PodLogOptions, podsLeft, budget *int, truncated *bool) ([]logLine, int, error) {
+
+	if err := m.checkAuthorization(request, "get_logs_multi_context", k8sContext, namespace, authorization.ResourceInfo{
+		Group:    "",
+		Version:  "v1",
+		Resource: "pods",
+	}); err != nil {
+		return nil, 0, err
+	}
+
+	if !m.clientManager.IsNamespaceAllowed(k8sContext, namespace) {
+		return nil, 0, fmt.Errorf("namespace %s is not allowed in context %s", namespace, k8sContext)
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	pods, err := client.Clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var lines []logLine
+	count := 0
+	for i := range pods.Items {
+		if *podsLeft == 0 || *budget <= 0 {
+			*truncated = true
+			break
+		}
+		pod := &pods.Items[i]
+		// The check above has no pod name; rules scoped by 'names' apply here
+		if err := m.checkAuthorization(request, "get_logs_multi_context", k8sContext, namespace, authorization.ResourceInfo{
+			Group:    "",
+			Version:  "v1",
+			Resource: "pods",
+			Name:     pod.Name,
+		}); err != nil {
+			lines = append(lines, logLine{source: k8sContext + "/" + pod.Name, text: fmt.Sprintf("<skipped: %v>", err)})
+			continue
+		}
+		*podsLeft--
+		count++
+
+		podOpts := opts
+		if podOpts.Container == "" {
+			podOpts.Container = defaultContainer(pod)
+		}
+		source := fmt.Sprintf("%s/%s/%s", k8sContext, pod.Name, podOpts.Container)
+
+		stream, err := client.Clientset.CoreV1().Pods(namespace).GetLogs(pod.Name, &podOpts).Stream(ctx)
+		if err != nil {
+			if diagnosis := containerNotRunningError(pod, podOpts.Container); diagnosis != nil {
+				err = diagnosis
+			}
+			lines = append(lines, logLine{source: source, text: fmt.Sprintf("<error reading logs: %v>", err)})
+			continue
+		}
+		podLines, used, cut := readLogLines(stream, source, opts.Timestamps, *budget)
+		stream.Close()
+
+		lines = append(lines, podLines...)
+		*budget -= used
+		if cut {
+			*truncated = true
+		}
+	}
+
+	return lines, count, nil
+}
+
+// readLogLines splits a log stream into lines, reading at most 'budget'
+// bytes. With timestamps the leading RFC3339 stamp is parsed off each line;
+// lines without one inherit the previous line's time.
+func readLogLines(r io.Reader, source string, timestamps bool, budget int) ([]logLine, int, bool) {
+	var lines []logLine
+	used := 0
+	var last time.Time
+
+	scanner := bufio.NewScanner(io.LimitReader(r, int64(budget)+1))
+	scanner.Buffer(make([]byte, 64*1024), budget+1)
+	for scanner.Scan() {
+		text := scanner.Text()
+		used += len(text) + 1
+		if used > budget {
+			return lines, budget, true
+		}
+
+		line := logLine{source: source, time: last, text: text}
+		if timestamps {
+			if stamp, rest, ok := strings.Cut(text, " "); ok {
+				if t, err := time.Parse(time.RFC3339Nano, stamp); err == nil {
+					line.time, line.text = t, rest
+					last = t
+				}
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines, used, false
+}
+
+// sortLogLines orders lines by timestamp. The sort is stable so lines
+// without their own timestamp stay right after the line they continue.
+func sortLogLines(lines []logLine) {
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].time.Before(lines[j].time) })
+}
+
+// defaultContainer returns the container kubectl would pick for a Pod
+func defaultContainer(pod *corev1.Pod) string {
+	if name := pod.Annotations["kubectl.kubernetes.io/default-container"]; name != "" {
+		return name
+	}
+	if len(pod.Spec.Containers) > 0 {
+		return pod.Spec.Containers[0].Name
+	}
+	return ""
+}
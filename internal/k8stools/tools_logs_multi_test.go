@@ -0,0 +1,146 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"kubernetes-mcp/api"
+	"kubernetes-mcp/internal/authorization"
+)
+
+func TestGetLogsMultiContext_PartialFailure(t *testing.T) {
+	e := newFakeEnv(t,
+		fakePod("default", "web-1", map[string]string{"app": "web"}),
+		fakePod("default", "web-2", map[string]string{"app": "web"}),
+		fakePod("default", "db-1", map[string]string{"app": "db"}),
+	)
+
+	res, err := e.manager.handleGetLogsMultiContext(context.Background(), makeRequest(map[string]any{
+		"contexts":       []any{fakeContext, "missing"},
+		"label_selector": "app=web",
+		"timestamps":     false,
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "get_logs_multi_context")
+	requireContains(t, out, "1 succeeded, 1 failed", "expected aggregate summary")
+	requireContains(t, out, "[fake/web-1/app] fake logs", "expected web-1 logs")
+	requireContains(t, out, "[fake/web-2/app] fake logs", "expected web-2 logs")
+	if strings.Contains(out, "db-1") {
+		t.Fatalf("pod outside the selector was read:\n%s", out)
+	}
+}
+
+func TestGetLogsMultiContext_MaxPods(t *testing.T) {
+	e := newFakeEnv(t,
+		fakePod("default", "web-1", map[string]string{"app": "web"}),
+		fakePod("default", "web-2", map[string]string{"app": "web"}),
+	)
+
+	res, err := e.manager.handleGetLogsMultiContext(context.Background(), makeRequest(map[string]any{
+		"contexts":       []any{fakeContext},
+		"label_selector": "app=web",
+		"max_pods":       float64(1),
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "get_logs_multi_context")
+	requireContains(t, out, "1 pods, 1 lines", "expected a single pod to be read")
+	requireContains(t, out, "output truncated", "expected truncation marker")
+}
+
+func TestGetLogsMultiContext_AllFailed(t *testing.T) {
+	e := newFakeEnv(t)
+	e.provider.deniedNamespaces = []string{"default"}
+
+	res, err := e.manager.handleGetLogsMultiContext(context.Background(), makeRequest(map[string]any{
+		"contexts":       []any{fakeContext},
+		"label_selector": "app=web",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectErr(t, res, "denied namespace")
+	requireContains(t, out, "namespace default is not allowed", "expected namespace error")
+}
+
+func TestGetLogsMultiContext_NameScopedDeny(t *testing.T) {
+	e := newFakeEnv(t,
+		fakePod("default", "vault-0", map[string]string{"app": "vault"}),
+		fakePod("default", "vault-1", map[string]string{"app": "vault"}),
+	)
+	authz, err := authorization.NewEvaluator(&api.AuthorizationConfig{
+		AllowAnonymous: true,
+		Policies: []api.AuthorizationPolicy{{
+			Name:  "no-vault-0",
+			Match: api.MatchConfig{Expression: "true"},
+			Rules: []api.AuthorizationRule{
+				{Effect: api.RuleEffectAllow},
+				{Effect: api.RuleEffectDeny, Resources: []api.ResourceRule{{Groups: []string{""}, Resources: []string{"pods"}, Names: []string{"vault-0"}}}},
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("NewEvaluator: %v", err)
+	}
+	e.manager.authz = authz
+
+	res, err := e.manager.handleGetLogsMultiContext(context.Background(), makeRequest(map[string]any{
+		"contexts":       []any{fakeContext},
+		"label_selector": "app=vault",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "get_logs_multi_context")
+	requireContains(t, out, "[fake/vault-1/app] fake logs", "expected the allowed pod")
+	requireContains(t, out, "[fake/vault-0] <skipped: access denied", "expected the denied pod skipped with the reason")
+	if strings.Contains(out, "vault-0/app") {
+		t.Fatalf("expected the denied pod not to be read, got:\n%s", out)
+	}
+}
+
+func TestReadLogLines_Interleave(t *testing.T) {
+	a, _, _ := readLogLines(strings.NewReader(
+		"2025-01-01T10:00:00Z start\n2025-01-01T10:00:02Z done\n"), "eu/a/app", true, 1024)
+	b, _, _ := readLogLines(strings.NewReader(
+		"2025-01-01T10:00:01.5Z request\n  continuation\n"), "us/b/app", true, 1024)
+
+	lines := append(a, b...)
+	sortLogLines(lines)
+
+	var got []string
+	for _, l := range lines {
+		got = append(got, l.source+" "+l.text)
+	}
+	want := "eu/a/app start|us/b/app request|us/b/app   continuation|eu/a/app done"
+	if strings.Join(got, "|") != want {
+		t.Fatalf("unexpected order:\n got: %s\nwant: %s", strings.Join(got, "|"), want)
+	}
+}
+
+func TestReadLogLines_Budget(t *testing.T) {
+	lines, used, cut := readLogLines(strings.NewReader("aaaa\nbbbb\ncccc\n"), "x", false, 10)
+	if !cut || len(lines) != 2 || used != 10 {
+		t.Fatalf("expected 2 lines within a 10-byte budget and a cut, got %d lines, used=%d, cut=%v", len(lines), used, cut)
+	}
+}
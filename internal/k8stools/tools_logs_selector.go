@@ -0,0 +1,167 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	selectorLogsDefaultMaxPods   = 10
+	selectorLogsMaxPodsLimit     = 100
+	selectorLogsDefaultTailLines = 100
+)
+
+func (m *Manager) registerGetLogsBySelector() {
+	tool := mcp.NewTool(m.toolName("get_logs_by_selector"),
+		mcp.WithDescription(`Fetch recent logs of every Pod matching a label selector in one namespace:
+"what are the Pods of this Deployment logging?".
+
+Output is one block per Pod, in name order, each under a '=== pod/<name> ==='
+header. Use the workload's selector, e.g. 'app=web' (see
+'.spec.selector.matchLabels' of the Deployment/DaemonSet).
+
+Bounded on purpose: at most 'max_pods' Pods are read (default 10), the last
+'tail_lines' lines of each (default 100) and 1 MiB of combined output. Pods
+left out are listed at the end so nothing is dropped silently.`),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("namespace", mcp.Description("Namespace where the Pods live. Defaults to 'default' if empty.")),
+		mcp.WithString("label_selector", mcp.Required(), mcp.Description("Kubernetes label selector matching the Pods. Examples: 'app=web', 'app.kubernetes.io/name=api,tier=backend'.")),
+		mcp.WithString("container", mcp.Description("Container to read in each Pod. Defaults to each Pod's default container.")),
+		mcp.WithNumber("tail_lines", mcp.Description("Last N lines per Pod. Integer >= 1. Defaults to 100.")),
+		mcp.WithNumber("since_seconds", mcp.Description("Only return logs newer than this many seconds. Integer >= 1.")),
+		mcp.WithString("since_time", mcp.Description("Only return logs written at or after this RFC3339 time. Mutually exclusive with 'since_seconds'.")),
+		mcp.WithBoolean("timestamps", mcp.Description("If true, prepend an RFC3339 timestamp to each line. Default false.")),
+		mcp.WithNumber("max_pods", mcp.Description("Maximum Pods to read. Integer 1..100. Defaults to 10.")),
+	)
+	m.addTool(tool, m.handleGetLogsBySelector)
+}
+
+func (m *Manager) handleGetLogsBySelector(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	namespace, _ := args["namespace"].(string)
+	if namespace == "" {
+		namespace = "default"
+	}
+	labelSelector, _ := args["label_selector"].(string)
+	if labelSelector == "" {
+		return errorResult(fmt.Errorf("label_selector is required")), nil
+	}
+	container, _ := args["container"].(string)
+	timestamps, _ := args["timestamps"].(bool)
+
+	maxPods := selectorLogsDefaultMaxPods
+	if v, ok := args["max_pods"].(float64); ok {
+		if v < 1 || v > selectorLogsMaxPodsLimit {
+			return errorResult(fmt.Errorf("max_pods must be between 1 and %d, got %v", selectorLogsMaxPodsLimit, v)), nil
+		}
+		maxPods = int(v)
+	}
+
+	opts := corev1.PodLogOptions{Container: container, Timestamps: timestamps}
+	tail := int64(selectorLogsDefaultTailLines)
+	if v, ok := args["tail_lines"].(float64); ok && v >= 1 {
+		tail = int64(v)
+	}
+	opts.TailLines = &tail
+	if err := setLogsSince(&opts, args); err != nil {
+		return errorResult(err), nil
+	}
+
+	// Check authorization (real K8s resource: Pod)
+	if err := m.checkLogsAccess(request, "get_logs_by_selector", k8sContext, namespace, ""); err != nil {
+		return errorResult(err), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	pods, err := client.Clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return errorResult(err), nil
+	}
+	if len(pods.Items) == 0 {
+		return errorResult(fmt.Errorf("no pods match %q in namespace %s", labelSelector, namespace)), nil
+	}
+	sort.Slice(pods.Items, func(i, j int) bool { return pods.Items[i].Name < pods.Items[j].Name })
+
+	var skipped []string
+	read := pods.Items
+	if len(read) > maxPods {
+		for _, pod := range read[maxPods:] {
+			skipped = append(skipped, pod.Name)
+		}
+		read = read[:maxPods]
+	}
+
+	var sb strings.Builder
+	progress := m.newProgressReporter(ctx, request)
+	for i := range read {
+		pod := &read[i]
+		progress.Report(float64(i), float64(len(read)), fmt.Sprintf("%d/%d pods read, reading %s", i, len(read), pod.Name))
+
+		if sb.Len() >= logsMaxBytes {
+			skipped = append(skipped, pod.Name)
+			continue
+		}
+		// The check above has no pod name; rules scoped by 'names' apply here
+		if err := m.checkLogsAccess(request, "get_logs_by_selector", k8sContext, namespace, pod.Name); err != nil {
+			skipped = append(skipped, fmt.Sprintf("%s (%v)", pod.Name, err))
+			continue
+		}
+
+		podOpts := opts
+		if podOpts.Container == "" {
+			podOpts.Container = defaultContainer(pod)
+		}
+		logs := readContainerLogs(ctx, client.Clientset, pod, &podOpts)
+
+		fmt.Fprintf(&sb, "=== pod/%s ===\n", pod.Name)
+		switch {
+		case logs.err != nil:
+			fmt.Fprintf(&sb, "<error reading logs: %v>\n", logs.err)
+		case logs.text == "":
+			sb.WriteString("<no log lines>\n")
+		default:
+			sb.WriteString(logs.text)
+			if !strings.HasSuffix(logs.text, "\n") {
+				sb.WriteByte('\n')
+			}
+		}
+	}
+
+	output := sb.String()
+	if len(output) > logsMaxBytes {
+		output = output[:logsMaxBytes] + "\n[... output truncated at 1MiB; use 'tail_lines' or 'since_seconds' to scope the request]\n"
+	}
+	if len(skipped) > 0 {
+		output += fmt.Sprintf("\n[%d of %d matching pods skipped (max_pods=%d, the 1MiB cap or access denied): %s]\n",
+			len(skipped), len(pods.Items), maxPods, strings.Join(skipped, ", "))
+	}
+	return successResult(output), nil
+}
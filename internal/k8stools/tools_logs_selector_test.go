@@ -0,0 +1,113 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"kubernetes-mcp/api"
+	"kubernetes-mcp/internal/authorization"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestGetLogsBySelector(t *testing.T) {
+	var objects []runtime.Object
+	for i := 1; i <= 4; i++ {
+		objects = append(objects, fakePod("default", fmt.Sprintf("web-%d", i), map[string]string{"app": "web"}))
+	}
+	objects = append(objects, fakePod("default", "db-1", map[string]string{"app": "db"}))
+	e := newFakeEnv(t, objects...)
+
+	res, err := e.manager.handleGetLogsBySelector(context.Background(), makeRequest(map[string]any{
+		"label_selector": "app=web",
+		"max_pods":       float64(3),
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "get_logs_by_selector")
+	if !strings.HasPrefix(out, "=== pod/web-1 ===\nfake logs\n=== pod/web-2 ===\nfake logs\n=== pod/web-3 ===\n") {
+		t.Fatalf("expected a block per pod in name order, got:\n%s", out)
+	}
+	requireContains(t, out, "[1 of 4 matching pods skipped (max_pods=3, the 1MiB cap or access denied): web-4]", "expected the skipped pod")
+	if strings.Contains(out, "db-1") {
+		t.Fatalf("expected only pods matching the selector, got:\n%s", out)
+	}
+}
+
+func TestGetLogsBySelector_Rejects(t *testing.T) {
+	e := newFakeEnv(t, fakePod("default", "web-1", map[string]string{"app": "web"}))
+	e.provider.deniedNamespaces = []string{"kube-system"}
+
+	tests := []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{name: "missing selector", args: map[string]any{}, want: "label_selector is required"},
+		{name: "too many pods", args: map[string]any{"label_selector": "app=web", "max_pods": float64(101)}, want: "max_pods must be between 1 and 100"},
+		{name: "denied namespace", args: map[string]any{"label_selector": "app=web", "namespace": "kube-system"}, want: "namespace kube-system is not allowed"},
+		{name: "no match", args: map[string]any{"label_selector": "app=api"}, want: `no pods match "app=api"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := e.manager.handleGetLogsBySelector(context.Background(), makeRequest(tt.args))
+			if err != nil {
+				t.Fatalf("go-error: %v", err)
+			}
+			requireContains(t, expectErr(t, res, tt.name), tt.want, "unexpected error text")
+		})
+	}
+}
+
+func TestGetLogsBySelector_NameScopedDeny(t *testing.T) {
+	e := newFakeEnv(t,
+		fakePod("default", "vault-0", map[string]string{"app": "vault"}),
+		fakePod("default", "vault-1", map[string]string{"app": "vault"}),
+	)
+	authz, err := authorization.NewEvaluator(&api.AuthorizationConfig{
+		AllowAnonymous: true,
+		Policies: []api.AuthorizationPolicy{{
+			Name:  "no-vault-0",
+			Match: api.MatchConfig{Expression: "true"},
+			Rules: []api.AuthorizationRule{
+				{Effect: api.RuleEffectAllow},
+				{Effect: api.RuleEffectDeny, Resources: []api.ResourceRule{{Groups: []string{""}, Resources: []string{"pods"}, Names: []string{"vault-0"}}}},
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("NewEvaluator: %v", err)
+	}
+	e.manager.authz = authz
+
+	res, err := e.manager.handleGetLogsBySelector(context.Background(), makeRequest(map[string]any{"label_selector": "app=vault"}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "get_logs_by_selector")
+	requireContains(t, out, "=== pod/vault-1 ===", "expected the allowed pod")
+	requireContains(t, out, "vault-0 (access denied", "expected the denied pod skipped with the reason")
+	if strings.Contains(out, "=== pod/vault-0 ===") {
+		t.Fatalf("expected the denied pod not to be read, got:\n%s", out)
+	}
+}
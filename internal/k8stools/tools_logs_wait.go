@@ -0,0 +1,244 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"kubernetes-mcp/internal/authorization"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	logPatternDefaultWait    = 60
+	logPatternMaxWait        = 600
+	logPatternDefaultBytes   = 10 << 20 // 10 MiB scanned, not returned
+	logPatternDefaultContext = 5
+	logPatternMaxContext     = 50
+
+	logPatternProgressInterval = 5 * time.Second
+)
+
+// logPatternResult is the outcome of scanning a log stream for a pattern
+type logPatternResult struct {
+	matched bool
+	line    string
+	// before holds up to N lines preceding the match, or the last N lines
+	// seen when there was no match.
+	before  []string
+	scanned int64
+}
+
+func (m *Manager) registerWaitForLogPattern() {
+	tool := mcp.NewTool(m.toolName("wait_for_log_pattern"),
+		mcp.WithDescription(`Follow a container's logs until a line matches a regular expression, or give
+up after 'max_wait_seconds'. Returns the matching line with the lines that
+preceded it.
+
+Typical use: "wait until the app prints 'ready to accept connections'"
+after a deploy or restart. Existing log lines are scanned first (narrow
+with 'since_seconds'), then new lines as they are written.
+
+Bounded: waits at most 'max_wait_seconds' (default 60, max 600) and reads
+at most 'limit_bytes' of log (default 10 MiB); either limit ends the call
+with an error that includes the last lines seen.`),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Name of the Pod whose logs to follow.")),
+		mcp.WithString("namespace", mcp.Description("Namespace where the Pod lives. Defaults to 'default' if empty.")),
+		mcp.WithString("container", mcp.Description("Name of the container inside the Pod. Required when the Pod has more than one container.")),
+		mcp.WithString("pattern", mcp.Required(), mcp.Description("Regular expression (Go RE2 syntax) matched against each line. Example: 'ready to accept connections', '(?i)started .* in [0-9.]+ seconds'.")),
+		mcp.WithNumber("since_seconds", mcp.Description("Only scan log lines newer than this many seconds. Omit to scan the whole existing log first.")),
+		mcp.WithNumber("max_wait_seconds", mcp.Description("Maximum time to wait for a match. Integer 1..600. Defaults to 60.")),
+		mcp.WithNumber("limit_bytes", mcp.Description("Maximum bytes of log to read before giving up. Integer >= 1. Defaults to 10485760 (10 MiB).")),
+		mcp.WithNumber("context_lines", mcp.Description("Lines preceding the match to include. Integer 0..50. Defaults to 5.")),
+	)
+	m.addWaitingTool(tool, m.handleWaitForLogPattern, logPatternMaxWait*time.Second)
+}
+
+func (m *Manager) handleWaitForLogPattern(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	name, _ := args["name"].(string)
+	namespace, _ := args["namespace"].(string)
+	if namespace == "" {
+		namespace = "default"
+	}
+	container, _ := args["container"].(string)
+	pattern, _ := args["pattern"].(string)
+
+	if pattern == "" {
+		return errorResult(fmt.Errorf("pattern is required")), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return errorResult(fmt.Errorf("invalid pattern: %w", err)), nil
+	}
+
+	maxWait := logPatternDefaultWait
+	if v, ok := args["max_wait_seconds"].(float64); ok {
+		if v < 1 || v > logPatternMaxWait {
+			return errorResult(fmt.Errorf("max_wait_seconds must be between 1 and %d, got %v", logPatternMaxWait, v)), nil
+		}
+		maxWait = int(v)
+	}
+	limitBytes := int64(logPatternDefaultBytes)
+	if v, ok := args["limit_bytes"].(float64); ok {
+		if v < 1 {
+			return errorResult(fmt.Errorf("limit_bytes must be >= 1, got %v", v)), nil
+		}
+		limitBytes = int64(v)
+	}
+	contextLines := logPatternDefaultContext
+	if v, ok := args["context_lines"].(float64); ok {
+		if v < 0 || v > logPatternMaxContext {
+			return errorResult(fmt.Errorf("context_lines must be between 0 and %d, got %v", logPatternMaxContext, v)), nil
+		}
+		contextLines = int(v)
+	}
+
+	// Check authorization (real K8s resource: Pod)
+	if err := m.checkAuthorization(request, "wait_for_log_pattern", k8sContext, namespace, authorization.ResourceInfo{
+		Group:    "",
+		Version:  "v1",
+		Resource: "pods",
+		Name:     name,
+	}); err != nil {
+		return errorResult(err), nil
+	}
+
+	if !m.clientManager.IsNamespaceAllowed(k8sContext, namespace) {
+		return errorResult(fmt.Errorf("namespace %s is not allowed in context %s", namespace, k8sContext)), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	opts := &corev1.PodLogOptions{
+		Container:  container,
+		Follow:     true,
+		LimitBytes: &limitBytes,
+	}
+	if v, ok := args["since_seconds"].(float64); ok && v >= 1 {
+		since := int64(v)
+		opts.SinceSeconds = &since
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, time.Duration(maxWait)*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	stream, err := client.StreamingClientset().CoreV1().Pods(namespace).GetLogs(name, opts).Stream(waitCtx)
+	if err != nil {
+		return errorResult(explainLogsError(ctx, client.Clientset, namespace, name, container, err)), nil
+	}
+	defer stream.Close()
+
+	// Closing the stream on timeout unblocks the scanner. Meanwhile the
+	// elapsed time is reported so the client does not see a silent wait.
+	progress := m.newProgressReporter(ctx, request)
+	go func() {
+		ticker := time.NewTicker(logPatternProgressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-waitCtx.Done():
+				stream.Close()
+				return
+			case <-ticker.C:
+				waited := time.Since(start).Round(time.Second)
+				progress.Report(waited.Seconds(), float64(maxWait),
+					fmt.Sprintf("waiting for %q: %s of %ds elapsed", pattern, waited, maxWait))
+			}
+		}
+	}()
+
+	result := scanForPattern(stream, re, contextLines)
+	elapsed := time.Since(start).Round(time.Second)
+
+	if result.matched {
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "Pattern %q matched after %s in pod %s/%s:\n\n", pattern, elapsed, namespace, name)
+		for _, l := range result.before {
+			fmt.Fprintf(&sb, "  %s\n", l)
+		}
+		fmt.Fprintf(&sb, "> %s\n", result.line)
+		return successResult(sb.String()), nil
+	}
+
+	var reason string
+	switch {
+	case errors.Is(waitCtx.Err(), context.DeadlineExceeded):
+		reason = fmt.Sprintf("no line matched within %ds", maxWait)
+	case result.scanned >= limitBytes:
+		reason = fmt.Sprintf("no line matched in the first %d bytes (limit_bytes)", limitBytes)
+	default:
+		reason = "the log stream ended (container stopped) without a matching line"
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "pattern %q not found: %s; scanned %d bytes in %s", pattern, reason, result.scanned, elapsed)
+	if len(result.before) > 0 {
+		sb.WriteString("\n\nLast lines:\n")
+		for _, l := range result.before {
+			fmt.Fprintf(&sb, "  %s\n", l)
+		}
+	}
+	return errorResult(fmt.Errorf("%s", sb.String())), nil
+}
+
+// scanForPattern reads lines until one matches 're' or the reader ends,
+// keeping the last 'contextLines' lines as context.
+func scanForPattern(r io.Reader, re *regexp.Regexp, contextLines int) logPatternResult {
+	var result logPatternResult
+	window := make([]string, 0, contextLines)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for scanner.Scan() {
+		line := scanner.Text()
+		result.scanned += int64(len(line)) + 1
+
+		if re.MatchString(line) {
+			result.matched = true
+			result.line = line
+			result.before = window
+			return result
+		}
+
+		if contextLines > 0 {
+			if len(window) == contextLines {
+				window = append(window[:0], window[1:]...)
+			}
+			window = append(window, line)
+		}
+	}
+
+	result.before = window
+	return result
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestScanForPattern(t *testing.T) {
+	logs := "booting\nloading config\nlistening on :8080\nready to accept connections\nserving\n"
+
+	result := scanForPattern(strings.NewReader(logs), regexp.MustCompile(`ready to accept`), 2)
+	if !result.matched || result.line != "ready to accept connections" {
+		t.Fatalf("expected a match, got %+v", result)
+	}
+	if strings.Join(result.before, "|") != "loading config|listening on :8080" {
+		t.Fatalf("unexpected context: %v", result.before)
+	}
+
+	result = scanForPattern(strings.NewReader(logs), regexp.MustCompile(`panic`), 1)
+	if result.matched {
+		t.Fatalf("expected no match, got %+v", result)
+	}
+	if len(result.before) != 1 || result.before[0] != "serving" {
+		t.Fatalf("expected the last line as context, got %v", result.before)
+	}
+	if result.scanned != int64(len(logs)) {
+		t.Fatalf("expected %d bytes scanned, got %d", len(logs), result.scanned)
+	}
+}
+
+func TestWaitForLogPattern(t *testing.T) {
+	// The fake clientset serves "fake logs" for every Pod and then ends
+	e := newFakeEnv(t, fakePod("default", "web", nil))
+
+	res, err := e.manager.handleWaitForLogPattern(context.Background(), makeRequest(map[string]any{
+		"name":    "web",
+		"pattern": "^fake",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "wait_for_log_pattern")
+	requireContains(t, out, "> fake logs", "expected the matching line")
+
+	res, err = e.manager.handleWaitForLogPattern(context.Background(), makeRequest(map[string]any{
+		"name":    "web",
+		"pattern": "ready",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	text := expectErr(t, res, "stream ended without a match")
+	requireContains(t, text, "log stream ended", "expected end-of-stream reason")
+	requireContains(t, text, "fake logs", "expected the last lines")
+}
+
+func TestWaitForLogPattern_Validation(t *testing.T) {
+	e := newFakeEnv(t, fakePod("default", "web", nil))
+
+	tests := []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{name: "missing pattern", args: map[string]any{}, want: "pattern is required"},
+		{name: "invalid pattern", args: map[string]any{"pattern": "("}, want: "invalid pattern"},
+		{name: "wait too long", args: map[string]any{"pattern": "x", "max_wait_seconds": float64(601)}, want: "max_wait_seconds must be between 1 and 600"},
+		{name: "too much context", args: map[string]any{"pattern": "x", "context_lines": float64(51)}, want: "context_lines must be between 0 and 50"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := map[string]any{"name": "web"}
+			for k, v := range tt.args {
+				args[k] = v
+			}
+			res, err := e.manager.handleWaitForLogPattern(context.Background(), makeRequest(args))
+			if err != nil {
+				t.Fatalf("go-error: %v", err)
+			}
+			text := expectErr(t, res, tt.name)
+			requireContains(t, text, tt.want, "unexpected error text")
+		})
+	}
+}
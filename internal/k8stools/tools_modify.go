@@ -23,6 +23,7 @@ import (
 	"strings"
 
 	"kubernetes-mcp/internal/authorization"
+	"kubernetes-mcp/internal/kubernetes"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -36,39 +37,48 @@ import (
 
 func (m *Manager) registerApplyManifest() {
 	tool := mcp.NewTool(m.toolName("apply_manifest"),
-		mcp.WithDescription(`Create-or-update (upsert) a Kubernetes resource from a YAML or JSON manifest.
+		mcp.WithDescription(`Create-or-update (upsert) Kubernetes resources from a YAML or JSON manifest.
 
-Behaviour: tries to Create the resource; if it already exists, falls back
+Behaviour: tries to Create each resource; if it already exists, falls back
 to Update. The resource type is detected automatically from the manifest's
 'apiVersion' / 'kind', resolved against the cluster's discovery API via the
 RESTMapper, so CRDs and irregular plurals (StorageClass, NetworkPolicy, ...)
 work transparently.
 
+Multi-document YAML (documents separated by '---') is applied document by
+document and reported per document; one failing document does not stop the
+others. CustomResourceDefinitions in the bundle are applied first and waited
+on until Established, so an operator and its custom resources can be
+installed in one call.
+
 Limitations:
-  - Single-document manifests only. Multi-document YAML separated by '---'
-    is NOT supported; pass each document in a separate call.
   - This is a 'replace'-style update, not server-side strategic merge.
     For surgical changes prefer 'patch_resource'.
+  - A bundle may hold at most the configured bulk operations limit of
+    documents (default 100).
 
-Use 'diff_manifest' first if you want to preview the change without applying it.`),
+Use 'diff_manifest' first if you want to preview the change without applying it,
+or 'dry_run=true' to run it through the API server's validation and admission
+(webhooks included) and get back the object that would be stored, without
+persisting anything.`),
 		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
-		mcp.WithString("manifest", mcp.Required(), mcp.Description("A single Kubernetes manifest in YAML or JSON. Must include 'apiVersion', 'kind' and 'metadata.name'. For namespaced kinds either set 'metadata.namespace' here or pass the 'namespace' argument.")),
+		mcp.WithString("manifest", mcp.Required(), mcp.Description("Kubernetes manifest(s) in YAML or JSON. Each document must include 'apiVersion', 'kind' and 'metadata.name'. For namespaced kinds either set 'metadata.namespace' here or pass the 'namespace' argument.")),
 		mcp.WithString("namespace", mcp.Description("Namespace override. If set, takes precedence over 'metadata.namespace' from the manifest. Ignored for cluster-scoped kinds.")),
+		mcp.WithBoolean("dry_run", mcp.Description("Server-side dry run: validate and admit every document and return the result without persisting anything. CRDs are not created, so custom resources of a CRD in the same bundle fail to resolve. Defaults to false.")),
 	)
-	m.mcpServer.AddTool(tool, m.handleApplyManifest)
+	m.addWaitingTool(tool, m.handleApplyManifest, crdEstablishTimeout)
 }
 
 func (m *Manager) handleApplyManifest(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
 
-	k8sContext := m.getContextParam(args)
+	k8sContext := m.getContextParam(ctx, args)
 	manifest, _ := args["manifest"].(string)
 	namespaceOverride, _ := args["namespace"].(string)
+	dryRun, _ := args["dry_run"].(bool)
 
-	// Reject multi-document YAML explicitly. sigs.k8s.io/yaml.Unmarshal would
-	// silently keep only the first document, which masks bugs in callers.
 	if isMultiDocumentYAML(manifest) {
-		return errorResult(fmt.Errorf("multi-document YAML is not supported; submit one document per call (separators '---' detected)")), nil
+		return m.applyManifestBundle(ctx, request, k8sContext, manifest, namespaceOverride, dryRun), nil
 	}
 
 	// Parse manifest
@@ -76,16 +86,67 @@ func (m *Manager) handleApplyManifest(ctx context.Context, request mcp.CallToolR
 	if err := yaml.Unmarshal([]byte(manifest), &obj.Object); err != nil {
 		return errorResult(fmt.Errorf("failed to parse manifest: %w", err)), nil
 	}
-	if len(obj.Object) == 0 {
-		return errorResult(fmt.Errorf("manifest is empty")), nil
+	if err := validateManifestObject(obj); err != nil {
+		return errorResult(err), nil
 	}
 
-	gvk := obj.GroupVersionKind()
-	if gvk.Kind == "" {
-		return errorResult(fmt.Errorf("manifest is missing 'kind'")), nil
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
 	}
-	if obj.GetName() == "" {
-		return errorResult(fmt.Errorf("manifest is missing 'metadata.name'")), nil
+
+	applied, err := m.applyObject(ctx, request, "apply_manifest", k8sContext, client, obj, namespaceOverride, dryRun)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	yamlOutput, _ := objectToYAML(applied.object)
+	if dryRun {
+		return successResult(fmt.Sprintf("%s %s/%s would be %s in namespace %s; the API server would store:\n\n%s",
+			dryRunNote, obj.GetKind(), obj.GetName(), applied.action, applied.namespace, m.redactYAML(yamlOutput))), nil
+	}
+	return successResult(fmt.Sprintf("Successfully %s %s/%s in namespace %s\n\n%s",
+		applied.action, obj.GetKind(), obj.GetName(), applied.namespace, m.redactYAML(yamlOutput))), nil
+}
+
+func (m *Manager) registerCreateResource() {
+	tool := mcp.NewTool(m.toolName("create_resource"),
+		mcp.WithDescription(`Create ONE Kubernetes resource from a YAML or JSON manifest, failing if it
+already exists.
+
+Use it when the intent is a fresh object: unlike 'apply_manifest', which
+silently updates an existing object of the same name, this tool only calls
+Create and returns the API server's "already exists" error untouched.
+
+The resource type is resolved from the manifest's 'apiVersion' / 'kind'
+through discovery, like 'apply_manifest'. One document per call; use
+'apply_manifest' for multi-document bundles.`),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("manifest", mcp.Required(), mcp.Description("Kubernetes manifest of a single object in YAML or JSON. Must include 'apiVersion', 'kind' and 'metadata.name'. For namespaced kinds either set 'metadata.namespace' here or pass the 'namespace' argument.")),
+		mcp.WithString("namespace", mcp.Description("Namespace override. If set, takes precedence over 'metadata.namespace' from the manifest. Ignored for cluster-scoped kinds.")),
+		mcp.WithBoolean("dry_run", mcp.Description("Server-side dry run: validate and admit the object and return it without persisting anything. Defaults to false.")),
+	)
+	m.addTool(tool, m.handleCreateResource)
+}
+
+func (m *Manager) handleCreateResource(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	manifest, _ := args["manifest"].(string)
+	namespaceOverride, _ := args["namespace"].(string)
+	dryRun, _ := args["dry_run"].(bool)
+
+	if isMultiDocumentYAML(manifest) {
+		return errorResult(fmt.Errorf("create_resource takes a single document; use 'apply_manifest' for multi-document manifests")), nil
+	}
+
+	obj := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal([]byte(manifest), &obj.Object); err != nil {
+		return errorResult(fmt.Errorf("failed to parse manifest: %w", err)), nil
+	}
+	if err := validateManifestObject(obj); err != nil {
+		return errorResult(err), nil
 	}
 
 	client, err := m.clientManager.GetClient(k8sContext)
@@ -93,12 +154,177 @@ func (m *Manager) handleApplyManifest(ctx context.Context, request mcp.CallToolR
 		return errorResult(err), nil
 	}
 
-	// Resolve GVR + namespaced flag from the cluster discovery via RESTMapper
-	gvr, namespaced, err := m.resolveGVRForGVK(client, gvk)
+	target, err := m.resolveManifestTarget(request, "create_resource", k8sContext, client, obj, namespaceOverride)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	// No fallback to Update: "already exists" is the answer the caller asked for
+	created, err := target.client.Create(ctx, obj, metav1.CreateOptions{DryRun: dryRunOption(dryRun)})
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	yamlOutput, err := objectToYAML(created)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	if dryRun {
+		return successResult(fmt.Sprintf("%s %s/%s would be created in namespace %s; the API server would store:\n\n%s",
+			dryRunNote, obj.GetKind(), obj.GetName(), target.namespace, m.redactYAML(yamlOutput))), nil
+	}
+	return successResult(fmt.Sprintf("Successfully created %s/%s in namespace %s\n\n%s",
+		obj.GetKind(), obj.GetName(), target.namespace, m.redactYAML(yamlOutput))), nil
+}
+
+func (m *Manager) registerReplaceResource() {
+	tool := mcp.NewTool(m.toolName("replace_resource"),
+		mcp.WithDescription(`Replace ONE existing Kubernetes resource with the given manifest ('kubectl replace').
+
+True replace semantics: the object becomes exactly the manifest, so fields
+left out of it are removed. 'apply_manifest' and 'patch_resource' merge into
+the live object instead; use them when only some fields should change.
+
+Optimistic concurrency: the update only succeeds if the object still has the
+resourceVersion it was read at. Either pass that version as
+'resource_version' (or keep 'metadata.resourceVersion' in the manifest), or
+set 'fetch_current=true' to replace whatever version is live now. If the
+object changed in between, the call fails with a conflict: re-read it and
+start over.`),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("manifest", mcp.Required(), mcp.Description("Full manifest of the object in YAML or JSON. Must include 'apiVersion', 'kind' and 'metadata.name'. Every field the object should keep must be present.")),
+		mcp.WithString("namespace", mcp.Description("Namespace override. If set, takes precedence over 'metadata.namespace' from the manifest. Ignored for cluster-scoped kinds.")),
+		mcp.WithString("resource_version", mcp.Description("resourceVersion the replacement is based on, as read with 'get_resource'. Overrides 'metadata.resourceVersion' from the manifest.")),
+		mcp.WithBoolean("fetch_current", mcp.Description("Replace the live object whatever its resourceVersion: read it and reuse its resourceVersion. Cannot be combined with 'resource_version'. Defaults to false.")),
+		mcp.WithBoolean("dry_run", mcp.Description("Server-side dry run: validate and admit the replacement and return it without persisting anything. Defaults to false.")),
+	)
+	m.addTool(tool, m.handleReplaceResource)
+}
+
+func (m *Manager) handleReplaceResource(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	manifest, _ := args["manifest"].(string)
+	namespaceOverride, _ := args["namespace"].(string)
+	resourceVersion, _ := args["resource_version"].(string)
+	fetchCurrent, _ := args["fetch_current"].(bool)
+	dryRun, _ := args["dry_run"].(bool)
+
+	if isMultiDocumentYAML(manifest) {
+		return errorResult(fmt.Errorf("replace_resource takes a single document")), nil
+	}
+
+	obj := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal([]byte(manifest), &obj.Object); err != nil {
+		return errorResult(fmt.Errorf("failed to parse manifest: %w", err)), nil
+	}
+	if err := validateManifestObject(obj); err != nil {
+		return errorResult(err), nil
+	}
+
+	if fetchCurrent && resourceVersion != "" {
+		return errorResult(fmt.Errorf("resource_version and fetch_current are mutually exclusive")), nil
+	}
+	if resourceVersion == "" {
+		resourceVersion = obj.GetResourceVersion()
+	}
+	if !fetchCurrent && resourceVersion == "" {
+		return errorResult(fmt.Errorf("a resourceVersion is required: pass 'resource_version' (or set metadata.resourceVersion), or set fetch_current=true to replace the live version")), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	target, err := m.resolveManifestTarget(request, "replace_resource", k8sContext, client, obj, namespaceOverride)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	// The replacement drops every live label and annotation the manifest
+	// leaves out, so read the live object to check those keys as well
+	live, err := target.client.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return errorResult(err), nil
+	}
+	if err := m.checkRemovedMetadataKeys(request, "replace_resource", k8sContext, target, obj, live); err != nil {
+		return errorResult(err), nil
+	}
+	if fetchCurrent {
+		resourceVersion = live.GetResourceVersion()
+	}
+	obj.SetResourceVersion(resourceVersion)
+
+	replaced, err := target.client.Update(ctx, obj, metav1.UpdateOptions{DryRun: dryRunOption(dryRun)})
+	if apierrors.IsConflict(err) {
+		return errorResult(fmt.Errorf("%s/%s changed since resourceVersion %s was read; re-read it with 'get_resource' and replace again from the current version: %w",
+			obj.GetKind(), obj.GetName(), resourceVersion, err)), nil
+	}
 	if err != nil {
 		return errorResult(err), nil
 	}
 
+	yamlOutput, err := objectToYAML(replaced)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	if dryRun {
+		return successResult(fmt.Sprintf("%s %s/%s would be replaced in namespace %s; the API server would store:\n\n%s",
+			dryRunNote, obj.GetKind(), obj.GetName(), target.namespace, m.redactYAML(yamlOutput))), nil
+	}
+	return successResult(fmt.Sprintf("Successfully replaced %s/%s in namespace %s (resourceVersion %s -> %s)\n\n%s",
+		obj.GetKind(), obj.GetName(), target.namespace, resourceVersion, replaced.GetResourceVersion(), m.redactYAML(yamlOutput))), nil
+}
+
+// appliedObject is the outcome of applyObject
+type appliedObject struct {
+	// action is "created" or "updated"
+	action    string
+	gvr       schema.GroupVersionResource
+	namespace string
+	object    *unstructured.Unstructured
+	// dryRun is set when nothing was persisted
+	dryRun bool
+}
+
+// validateManifestObject checks the fields every applied document needs
+func validateManifestObject(obj *unstructured.Unstructured) error {
+	if len(obj.Object) == 0 {
+		return fmt.Errorf("manifest is empty")
+	}
+	if obj.GetKind() == "" {
+		return fmt.Errorf("manifest is missing 'kind'")
+	}
+	if obj.GetName() == "" {
+		return fmt.Errorf("manifest is missing 'metadata.name'")
+	}
+	return nil
+}
+
+// manifestTarget is where a manifest object is written to
+type manifestTarget struct {
+	gvr       schema.GroupVersionResource
+	namespace string
+	resource  authorization.ResourceInfo
+	client    dynamicResource
+}
+
+// resolveManifestTarget resolves the resource of a manifest object through
+// discovery, applies the namespace override, and checks 'tool' against the
+// authorization policies and the namespace rules.
+func (m *Manager) resolveManifestTarget(request mcp.CallToolRequest, tool, k8sContext string, client *kubernetes.Client,
+	obj *unstructured.Unstructured, namespaceOverride string) (*manifestTarget, error) {
+
+	// Resolve GVR + namespaced flag from the cluster discovery via RESTMapper
+	gvr, namespaced, err := m.resolveGVRForGVK(client, obj.GroupVersionKind())
+	if err != nil {
+		return nil, err
+	}
+
 	namespace := obj.GetNamespace()
 	if namespaceOverride != "" {
 		namespace = namespaceOverride
@@ -110,35 +336,74 @@ func (m *Manager) handleApplyManifest(ctx context.Context, request mcp.CallToolR
 	}
 
 	// Check authorization
-	if err := m.checkAuthorization(request, "apply_manifest", k8sContext, namespace, authorization.ResourceInfo{
+	resource := authorization.ResourceInfo{
 		Group:    gvr.Group,
 		Version:  gvr.Version,
 		Resource: gvr.Resource,
 		Name:     obj.GetName(),
-	}); err != nil {
-		return errorResult(err), nil
+	}
+	if err := m.checkAuthorization(request, tool, k8sContext, namespace, resource); err != nil {
+		return nil, err
+	}
+	if err := m.checkMetadataKeys(request, tool, k8sContext, namespace, resource,
+		mapKeys(obj.GetLabels()), mapKeys(obj.GetAnnotations())); err != nil {
+		return nil, err
 	}
 
 	if namespace != "" && !m.clientManager.IsNamespaceAllowed(k8sContext, namespace) {
-		return errorResult(fmt.Errorf("namespace %s is not allowed in context %s", namespace, k8sContext)), nil
+		return nil, fmt.Errorf("namespace %s is not allowed in context %s", namespace, k8sContext)
 	}
 
-	// Try to create. If the resource already exists, do a proper read-modify-
-	// write update: GET the live object, copy server-managed immutable fields
-	// (resourceVersion, clusterIP, ...), then Update.
 	resourceClient := client.DynamicClient.Resource(gvr)
 	var nsClient dynamicResource = resourceClient
 	if namespace != "" {
 		nsClient = resourceClient.Namespace(namespace)
 	}
+	return &manifestTarget{gvr: gvr, namespace: namespace, resource: resource, client: nsClient}, nil
+}
+
+// checkRemovedMetadataKeys checks the label and annotation keys that
+// replacing 'live' with 'obj' removes: the manifest check in
+// resolveManifestTarget only sees the keys the manifest sets.
+func (m *Manager) checkRemovedMetadataKeys(request mcp.CallToolRequest, tool, k8sContext string, target *manifestTarget,
+	obj, live *unstructured.Unstructured) error {
+	return m.checkMetadataKeys(request, tool, k8sContext, target.namespace, target.resource,
+		removedKeys(live.GetLabels(), obj.GetLabels()), removedKeys(live.GetAnnotations(), obj.GetAnnotations()))
+}
+
+// removedKeys returns the keys of 'live' missing from 'desired', sorted
+func removedKeys(live, desired map[string]string) []string {
+	var keys []string
+	for _, k := range mapKeys(live) {
+		if _, kept := desired[k]; !kept {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// applyObject creates one object, or updates it when it already exists,
+// authorized as 'tool' and under the namespace rules. With 'dryRun' the API
+// server only reports what it would have stored.
+func (m *Manager) applyObject(ctx context.Context, request mcp.CallToolRequest, tool, k8sContext string, client *kubernetes.Client,
+	obj *unstructured.Unstructured, namespaceOverride string, dryRun bool) (*appliedObject, error) {
 
-	created, err := nsClient.Create(ctx, obj, metav1.CreateOptions{})
+	target, err := m.resolveManifestTarget(request, tool, k8sContext, client, obj, namespaceOverride)
+	if err != nil {
+		return nil, err
+	}
+	gvk := obj.GroupVersionKind()
+	gvr, namespace, nsClient := target.gvr, target.namespace, target.client
+
+	// Try to create. If the resource already exists, do a proper read-modify-
+	// write update: GET the live object, copy server-managed immutable fields
+	// (resourceVersion, clusterIP, ...), then Update.
+	created, err := nsClient.Create(ctx, obj, metav1.CreateOptions{DryRun: dryRunOption(dryRun)})
 	if err == nil {
-		yamlOutput, _ := objectToYAML(created)
-		return successResult(fmt.Sprintf("Successfully created %s/%s in namespace %s\n\n%s", gvk.Kind, obj.GetName(), namespace, yamlOutput)), nil
+		return &appliedObject{action: "created", gvr: gvr, namespace: namespace, object: created, dryRun: dryRun}, nil
 	}
 	if !apierrors.IsAlreadyExists(err) {
-		return errorResult(err), nil
+		return nil, err
 	}
 
 	// Already exists -> Update path with retry-on-conflict, the same flow
@@ -151,19 +416,21 @@ func (m *Manager) handleApplyManifest(ctx context.Context, request mcp.CallToolR
 		if err != nil {
 			return err
 		}
+		if err := m.checkRemovedMetadataKeys(request, tool, k8sContext, target, obj, live); err != nil {
+			return err
+		}
 		mergeImmutableFields(obj, live, gvk)
 		obj.SetResourceVersion(live.GetResourceVersion())
 
 		var updErr error
-		updated, updErr = nsClient.Update(ctx, obj, metav1.UpdateOptions{})
+		updated, updErr = nsClient.Update(ctx, obj, metav1.UpdateOptions{DryRun: dryRunOption(dryRun)})
 		return updErr
 	})
 	if retryErr != nil {
-		return errorResult(retryErr), nil
+		return nil, retryErr
 	}
 
-	yamlOutput, _ := objectToYAML(updated)
-	return successResult(fmt.Sprintf("Successfully updated %s/%s in namespace %s\n\n%s", gvk.Kind, obj.GetName(), namespace, yamlOutput)), nil
+	return &appliedObject{action: "updated", gvr: gvr, namespace: namespace, object: updated, dryRun: dryRun}, nil
 }
 
 // dynamicResource is the minimal subset of dynamic.ResourceInterface we use,
@@ -172,6 +439,48 @@ type dynamicResource interface {
 	Create(ctx context.Context, obj *unstructured.Unstructured, opts metav1.CreateOptions, subresources ...string) (*unstructured.Unstructured, error)
 	Update(ctx context.Context, obj *unstructured.Unstructured, opts metav1.UpdateOptions, subresources ...string) (*unstructured.Unstructured, error)
 	Get(ctx context.Context, name string, opts metav1.GetOptions, subresources ...string) (*unstructured.Unstructured, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions, subresources ...string) error
+}
+
+// deletePreviewNames caps the names listed by a delete_resources preview
+const deletePreviewNames = 50
+
+// deletePreview describes what a delete_resources call would remove: the
+// count, the first names, and whether the bulk cap would refuse it.
+func deletePreview(items []unstructured.Unstructured, resource string, maxBulk int, force bool) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Preview: the selector matches %d %s; nothing was deleted.\n", len(items), resource)
+	switch {
+	case len(items) <= maxBulk:
+		fmt.Fprintf(&sb, "Within the bulk cap of %d; re-run with preview=false to delete them.\n", maxBulk)
+	case force:
+		fmt.Fprintf(&sb, "Above the bulk cap of %d; force=true would delete them anyway.\n", maxBulk)
+	default:
+		fmt.Fprintf(&sb, "Above the bulk cap of %d; the delete would be refused without force=true.\n", maxBulk)
+	}
+
+	sb.WriteString("\n")
+	for i, item := range items {
+		if i == deletePreviewNames {
+			fmt.Fprintf(&sb, "... and %d more\n", len(items)-deletePreviewNames)
+			break
+		}
+		if ns := item.GetNamespace(); ns != "" {
+			fmt.Fprintf(&sb, "- %s/%s\n", ns, item.GetName())
+		} else {
+			fmt.Fprintf(&sb, "- %s\n", item.GetName())
+		}
+	}
+	return sb.String()
+}
+
+// bulkOperationsLimit returns the configured cap on the number of objects a
+// single selector-based call may touch.
+func (m *Manager) bulkOperationsLimit() int {
+	if limit := m.currentConfig().Kubernetes.Tools.BulkOperations.MaxResourcesPerOperation; limit > 0 {
+		return limit
+	}
+	return 100
 }
 
 // isMultiDocumentYAML reports whether the input contains more than one YAML
@@ -258,7 +567,10 @@ Choose 'patch_type' carefully:
   - 'merge': RFC 7396 JSON Merge Patch. Works on any resource including
     CRDs. Replaces lists entirely (does not merge them by key).
   - 'json': RFC 6902 JSON Patch. An array of operations like
-    [{"op":"replace","path":"/spec/replicas","value":3}]. Most precise.`),
+    [{"op":"replace","path":"/spec/replicas","value":3}]. Most precise.
+
+'dry_run=true' sends the patch through validation and admission and returns
+the patched object without persisting it.`),
 		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
 		mcp.WithString("group", mcp.Description("API group. Empty string \"\" for the core API.")),
 		mcp.WithString("version", mcp.Required(), mcp.Description("API version, e.g. 'v1'.")),
@@ -267,30 +579,33 @@ Choose 'patch_type' carefully:
 		mcp.WithString("namespace", mcp.Description("Namespace where the resource lives. Required for namespaced resources.")),
 		mcp.WithString("patch_type", mcp.Required(), mcp.Description("'strategic' for Strategic Merge Patch (built-in types only), 'merge' for RFC 7396 JSON Merge Patch (works on CRDs), or 'json' for RFC 6902 JSON Patch operations.")),
 		mcp.WithString("patch", mcp.Required(), mcp.Description("Patch payload. YAML and JSON are both accepted. For 'json' patch_type the payload must be a JSON array of operations.")),
+		mcp.WithBoolean("dry_run", mcp.Description("Server-side dry run: return the object the API server would store, without persisting it. Defaults to false.")),
 	)
-	m.mcpServer.AddTool(tool, m.handlePatchResource)
+	m.addTool(tool, m.handlePatchResource)
 }
 
 func (m *Manager) handlePatchResource(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
 
-	k8sContext := m.getContextParam(args)
+	k8sContext := m.getContextParam(ctx, args)
 	name, _ := args["name"].(string)
 	namespace, _ := args["namespace"].(string)
 	patchTypeStr, _ := args["patch_type"].(string)
 	patchData, _ := args["patch"].(string)
+	dryRun, _ := args["dry_run"].(bool)
 	gvr := gvrFromArgs(args)
 	if err := validateGVR(gvr); err != nil {
 		return errorResult(err), nil
 	}
 
 	// Check authorization
-	if err := m.checkAuthorization(request, "patch_resource", k8sContext, namespace, authorization.ResourceInfo{
+	resource := authorization.ResourceInfo{
 		Group:    gvr.Group,
 		Version:  gvr.Version,
 		Resource: gvr.Resource,
 		Name:     name,
-	}); err != nil {
+	}
+	if err := m.checkAuthorization(request, "patch_resource", k8sContext, namespace, resource); err != nil {
 		return errorResult(err), nil
 	}
 
@@ -317,30 +632,45 @@ func (m *Manager) handlePatchResource(ctx context.Context, request mcp.CallToolR
 	}
 
 	// Validate patch is non-empty (avoid panic in subsequent indexing).
-	if strings.TrimSpace(patchData) == "" {
+	trimmed := strings.TrimSpace(patchData)
+	if trimmed == "" {
 		return errorResult(fmt.Errorf("patch is empty")), nil
 	}
 
 	// Convert YAML patch to JSON if needed
 	var patchBytes []byte
-	if strings.TrimSpace(patchData)[0] == '{' || strings.TrimSpace(patchData)[0] == '[' {
+	if trimmed[0] == '{' || trimmed[0] == '[' {
 		patchBytes = []byte(patchData)
 	} else {
 		var patchObj any
 		if err := yaml.Unmarshal([]byte(patchData), &patchObj); err != nil {
 			return errorResult(fmt.Errorf("failed to parse patch: %w", err)), nil
 		}
+		// A document with nothing in it ('---', only comments, 'null')
+		// parses fine but would be sent as a 'null' patch
+		if patchObj == nil {
+			return errorResult(fmt.Errorf("patch is empty")), nil
+		}
 		patchBytes, err = json.Marshal(patchObj)
 		if err != nil {
 			return errorResult(fmt.Errorf("failed to convert patch to JSON: %w", err)), nil
 		}
 	}
 
+	labelKeys, annotationKeys, err := patchMetadataKeys(patchType, patchBytes)
+	if err != nil {
+		return errorResult(fmt.Errorf("failed to parse patch: %w", err)), nil
+	}
+	if err := m.checkMetadataKeys(request, "patch_resource", k8sContext, namespace, resource, labelKeys, annotationKeys); err != nil {
+		return errorResult(err), nil
+	}
+
+	patchOpts := metav1.PatchOptions{DryRun: dryRunOption(dryRun)}
 	var result *unstructured.Unstructured
 	if namespace != "" {
-		result, err = client.DynamicClient.Resource(gvr).Namespace(namespace).Patch(ctx, name, patchType, patchBytes, metav1.PatchOptions{})
+		result, err = client.DynamicClient.Resource(gvr).Namespace(namespace).Patch(ctx, name, patchType, patchBytes, patchOpts)
 	} else {
-		result, err = client.DynamicClient.Resource(gvr).Patch(ctx, name, patchType, patchBytes, metav1.PatchOptions{})
+		result, err = client.DynamicClient.Resource(gvr).Patch(ctx, name, patchType, patchBytes, patchOpts)
 	}
 
 	if err != nil {
@@ -352,7 +682,10 @@ func (m *Manager) handlePatchResource(ctx context.Context, request mcp.CallToolR
 		return errorResult(err), nil
 	}
 
-	return successResult(fmt.Sprintf("Successfully patched %s/%s\n\n%s", gvr.Resource, name, yamlOutput)), nil
+	if dryRun {
+		return successResult(fmt.Sprintf("%s Patching %s/%s would store:\n\n%s", dryRunNote, gvr.Resource, name, m.redactYAML(yamlOutput))), nil
+	}
+	return successResult(fmt.Sprintf("Successfully patched %s/%s\n\n%s", gvr.Resource, name, m.redactYAML(yamlOutput))), nil
 }
 
 func (m *Manager) registerDeleteResource() {
@@ -363,7 +696,8 @@ DESTRUCTIVE. Cannot be undone (the API server has no trash bin).
 Verify with 'get_resource' first if you have any doubt.
 
 For deleting many objects at once with a selector use 'delete_resources'
-instead — but be even more careful.`),
+instead — but be even more careful. 'dry_run=true' checks the delete against
+validation and admission without removing anything.`),
 		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
 		mcp.WithString("group", mcp.Description("API group. Empty string \"\" for the core API.")),
 		mcp.WithString("version", mcp.Required(), mcp.Description("API version, e.g. 'v1'.")),
@@ -372,14 +706,15 @@ instead — but be even more careful.`),
 		mcp.WithString("namespace", mcp.Description("Namespace where the resource lives. Required for namespaced resources.")),
 		mcp.WithNumber("grace_period_seconds", mcp.Description("Seconds before forced termination. 0 = delete immediately (forceful, may leak resources). Omit to use the resource's default (30s for Pods).")),
 		mcp.WithString("propagation_policy", mcp.Description("How to handle dependents. 'Background' (default for most kinds): API returns immediately, dependents deleted asynchronously. 'Foreground': blocks until dependents are gone. 'Orphan': leaves dependents alive (e.g. delete a Deployment but keep its Pods).")),
+		mcp.WithBoolean("dry_run", mcp.Description("Server-side dry run: check that the delete would be admitted and return the object it would remove, without deleting it. Defaults to false.")),
 	)
-	m.mcpServer.AddTool(tool, m.handleDeleteResource)
+	m.addTool(tool, m.handleDeleteResource)
 }
 
 func (m *Manager) handleDeleteResource(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
 
-	k8sContext := m.getContextParam(args)
+	k8sContext := m.getContextParam(ctx, args)
 	name, _ := args["name"].(string)
 	namespace, _ := args["namespace"].(string)
 	gvr := gvrFromArgs(args)
@@ -410,17 +745,32 @@ func (m *Manager) handleDeleteResource(ctx context.Context, request mcp.CallTool
 	if err != nil {
 		return errorResult(err), nil
 	}
+	dryRun, _ := args["dry_run"].(bool)
+	deleteOpts.DryRun = dryRunOption(dryRun)
 
+	var nsClient dynamicResource = client.DynamicClient.Resource(gvr)
 	if namespace != "" {
-		err = client.DynamicClient.Resource(gvr).Namespace(namespace).Delete(ctx, name, deleteOpts)
-	} else {
-		err = client.DynamicClient.Resource(gvr).Delete(ctx, name, deleteOpts)
+		nsClient = client.DynamicClient.Resource(gvr).Namespace(namespace)
 	}
 
-	if err != nil {
+	if err := nsClient.Delete(ctx, name, deleteOpts); err != nil {
 		return errorResult(err), nil
 	}
 
+	if dryRun {
+		// A delete returns no object; the one that would go is still there
+		obj, err := nsClient.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return errorResult(err), nil
+		}
+		yamlOutput, err := objectToYAML(obj)
+		if err != nil {
+			return errorResult(err), nil
+		}
+		return successResult(fmt.Sprintf("%s Deleting %s/%s in namespace %s would be admitted; it would remove:\n\n%s",
+			dryRunNote, gvr.Resource, name, namespace, m.redactYAML(yamlOutput))), nil
+	}
+
 	return successResult(fmt.Sprintf("Successfully deleted %s/%s in namespace %s", gvr.Resource, name, namespace)), nil
 }
 
@@ -438,7 +788,13 @@ Safety checks enforced by this tool:
     explicitly (this barrier prevents accidental cross-namespace deletes).
   - The total number of matched resources is capped by the server's
     'kubernetes.tools.bulk_operations.max_resources_per_operation' setting
-    (default 100); the call is rejected if the selector matches more.
+    (default 100); the call is rejected if the selector matches more,
+    unless 'force=true' is passed. Only pass it after the user has seen
+    the count and confirmed the mass deletion.
+
+'preview=true' deletes nothing: it returns how many objects the selector
+matches and their names (the first 50), and whether the cap would refuse
+the call. Preview first, then re-run with 'preview=false'.
 
 For a single named resource use 'delete_resource'.`),
 		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
@@ -450,18 +806,22 @@ For a single named resource use 'delete_resource'.`),
 		mcp.WithString("label_selector", mcp.Description("Kubernetes label selector. Examples: 'app=nginx', 'temp=true', 'tier in (frontend,backend)'. Required if 'field_selector' is empty.")),
 		mcp.WithString("field_selector", mcp.Description("Kubernetes field selector. Example: 'status.phase=Failed'. Required if 'label_selector' is empty.")),
 		mcp.WithNumber("grace_period_seconds", mcp.Description("Seconds before forced termination. 0 = delete immediately. Omit to use the resource's default.")),
+		mcp.WithBoolean("force", mcp.Description("If true, delete even when the selector matches more resources than the bulk-operations cap. Defaults to false.")),
+		mcp.WithBoolean("preview", mcp.Description("If true, only list what the selector matches; nothing is deleted. Defaults to false.")),
 	)
-	m.mcpServer.AddTool(tool, m.handleDeleteResources)
+	m.addTool(tool, m.handleDeleteResources)
 }
 
 func (m *Manager) handleDeleteResources(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
 
-	k8sContext := m.getContextParam(args)
+	k8sContext := m.getContextParam(ctx, args)
 	namespace, _ := args["namespace"].(string)
 	allNamespaces, _ := args["all_namespaces"].(bool)
 	labelSelector, _ := args["label_selector"].(string)
 	fieldSelector, _ := args["field_selector"].(string)
+	force, _ := args["force"].(bool)
+	preview, _ := args["preview"].(bool)
 	gvr := gvrFromArgs(args)
 	if err := validateGVR(gvr); err != nil {
 		return errorResult(err), nil
@@ -505,10 +865,7 @@ func (m *Manager) handleDeleteResources(ctx context.Context, request mcp.CallToo
 	}
 
 	// Pre-list to enforce the bulk-operations cap. Avoids "delete and pray".
-	maxBulk := m.config.Kubernetes.Tools.BulkOperations.MaxResourcesPerOperation
-	if maxBulk <= 0 {
-		maxBulk = 100
-	}
+	maxBulk := m.bulkOperationsLimit()
 
 	var preList *unstructured.UnstructuredList
 	if namespace != "" {
@@ -523,8 +880,11 @@ func (m *Manager) handleDeleteResources(ctx context.Context, request mcp.CallToo
 	if matched == 0 {
 		return successResult(fmt.Sprintf("No %s matched the selector; nothing to delete", gvr.Resource)), nil
 	}
-	if matched > maxBulk {
-		return errorResult(fmt.Errorf("selector matched %d resources, which exceeds the configured cap of %d (kubernetes.tools.bulk_operations.max_resources_per_operation); refine the selector or raise the cap", matched, maxBulk)), nil
+	if preview {
+		return successResult(deletePreview(preList.Items, gvr.Resource, maxBulk, force)), nil
+	}
+	if matched > maxBulk && !force {
+		return errorResult(fmt.Errorf("selector matched %d resources, which exceeds the configured cap of %d (kubernetes.tools.bulk_operations.max_resources_per_operation); refine the selector, raise the cap, or pass force=true once the user has confirmed", matched, maxBulk)), nil
 	}
 
 	if namespace != "" {
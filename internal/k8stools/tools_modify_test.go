@@ -0,0 +1,430 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"kubernetes-mcp/api"
+	"kubernetes-mcp/internal/authorization"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func fakeConfigMap(namespace, name string, data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Data:       data,
+	}
+}
+
+func TestPatchResource(t *testing.T) {
+	tests := []struct {
+		name      string
+		patchType string
+		patch     string
+	}{
+		{name: "merge json", patchType: "merge", patch: `{"data":{"level":"debug"}}`},
+		{name: "merge yaml", patchType: "merge", patch: "data:\n  level: debug\n"},
+		{name: "json patch", patchType: "json", patch: `[{"op":"replace","path":"/data/level","value":"debug"}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newFakeEnv(t, fakeConfigMap("default", "settings", map[string]string{"level": "info"}))
+
+			res, err := e.manager.handlePatchResource(context.Background(), makeRequest(map[string]any{
+				"version":    "v1",
+				"resource":   "configmaps",
+				"namespace":  "default",
+				"name":       "settings",
+				"patch_type": tt.patchType,
+				"patch":      tt.patch,
+			}))
+			if err != nil {
+				t.Fatalf("go-error: %v", err)
+			}
+			out := expectOK(t, res, "patch_resource")
+			requireContains(t, out, "Successfully patched configmaps/settings", "expected summary line")
+			requireContains(t, out, "level: debug", "expected patched value in YAML")
+
+			cm, err := e.dynamic.Resource(gvrOf("", "v1", "configmaps")).Namespace("default").Get(context.Background(), "settings", metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("get patched configmap: %v", err)
+			}
+			if got := cm.Object["data"].(map[string]any)["level"]; got != "debug" {
+				t.Fatalf("expected level=debug in the store, got %v", got)
+			}
+		})
+	}
+}
+
+func TestPatchResource_Errors(t *testing.T) {
+	e := newFakeEnv(t, fakeConfigMap("default", "settings", nil))
+
+	tests := []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{
+			name: "invalid patch type",
+			args: map[string]any{"patch_type": "apply", "patch": "{}"},
+			want: "invalid patch type: apply",
+		},
+		{
+			name: "empty patch",
+			args: map[string]any{"patch_type": "merge", "patch": ""},
+			want: "patch is empty",
+		},
+		{
+			name: "whitespace patch",
+			args: map[string]any{"patch_type": "merge", "patch": "   "},
+			want: "patch is empty",
+		},
+		{
+			name: "empty yaml document",
+			args: map[string]any{"patch_type": "strategic", "patch": "---\n# nothing to change\n"},
+			want: "patch is empty",
+		},
+		{
+			name: "unparsable yaml",
+			args: map[string]any{"patch_type": "merge", "patch": "data: [unclosed"},
+			want: "failed to parse patch",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := map[string]any{"version": "v1", "resource": "configmaps", "namespace": "default", "name": "settings"}
+			for k, v := range tt.args {
+				args[k] = v
+			}
+			res, err := e.manager.handlePatchResource(context.Background(), makeRequest(args))
+			if err != nil {
+				t.Fatalf("go-error: %v", err)
+			}
+			text := expectErr(t, res, tt.name)
+			requireContains(t, text, tt.want, "unexpected error text")
+		})
+	}
+}
+
+func TestDeleteResource(t *testing.T) {
+	e := newFakeEnv(t, fakeConfigMap("default", "settings", nil))
+
+	res, err := e.manager.handleDeleteResource(context.Background(), makeRequest(map[string]any{
+		"version":   "v1",
+		"resource":  "configmaps",
+		"namespace": "default",
+		"name":      "settings",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "delete_resource")
+	requireContains(t, out, "Successfully deleted configmaps/settings in namespace default", "expected summary line")
+
+	_, err = e.dynamic.Resource(gvrOf("", "v1", "configmaps")).Namespace("default").Get(context.Background(), "settings", metav1.GetOptions{})
+	if !apierrors.IsNotFound(err) {
+		t.Fatalf("expected configmap to be gone, got err=%v", err)
+	}
+}
+
+func TestDeleteResource_Errors(t *testing.T) {
+	e := newFakeEnv(t, fakeConfigMap("default", "settings", nil))
+
+	tests := []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{
+			name: "not found",
+			args: map[string]any{"name": "missing"},
+			want: "not found",
+		},
+		{
+			name: "invalid propagation policy",
+			args: map[string]any{"name": "settings", "propagation_policy": "Cascade"},
+			want: "invalid propagation_policy",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := map[string]any{"version": "v1", "resource": "configmaps", "namespace": "default"}
+			for k, v := range tt.args {
+				args[k] = v
+			}
+			res, err := e.manager.handleDeleteResource(context.Background(), makeRequest(args))
+			if err != nil {
+				t.Fatalf("go-error: %v", err)
+			}
+			text := expectErr(t, res, tt.name)
+			requireContains(t, text, tt.want, "unexpected error text")
+		})
+	}
+}
+
+func TestDeleteResources_BulkCap(t *testing.T) {
+	var objs []runtime.Object
+	for _, name := range []string{"a", "b", "c"} {
+		cm := fakeConfigMap("default", name, nil)
+		cm.Labels = map[string]string{"tier": "cache"}
+		objs = append(objs, cm)
+	}
+	e := newFakeEnv(t, objs...)
+	e.manager.config.Kubernetes.Tools.BulkOperations.MaxResourcesPerOperation = 2
+
+	deleteAll := func(force bool, extra ...string) *mcp.CallToolResult {
+		t.Helper()
+		args := map[string]any{
+			"version":        "v1",
+			"resource":       "configmaps",
+			"namespace":      "default",
+			"label_selector": "tier=cache",
+			"force":          force,
+		}
+		for _, flag := range extra {
+			args[flag] = true
+		}
+		res, err := e.manager.handleDeleteResources(context.Background(), makeRequest(args))
+		if err != nil {
+			t.Fatalf("go-error: %v", err)
+		}
+		return res
+	}
+
+	out := expectOK(t, deleteAll(false, "preview"), "preview")
+	requireContains(t, out, "matches 3 configmaps; nothing was deleted", "expected the count")
+	requireContains(t, out, "refused without force=true", "expected the cap verdict")
+	requireContains(t, out, "- default/b", "expected the matched names")
+	requireContains(t, expectOK(t, deleteAll(true, "preview"), "forced preview"), "force=true would delete them anyway", "expected the forced verdict")
+
+	text := expectErr(t, deleteAll(false), "over the cap")
+	requireContains(t, text, "matched 3 resources, which exceeds the configured cap of 2", "expected the count and the cap")
+	requireContains(t, text, "force=true", "expected the override to be named")
+
+	list, err := e.dynamic.Resource(gvrOf("", "v1", "configmaps")).Namespace("default").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Items) != 3 {
+		t.Fatalf("a refused call must delete nothing, %d left", len(list.Items))
+	}
+
+	out = expectOK(t, deleteAll(true), "forced")
+	requireContains(t, out, "Successfully deleted 3 configmaps", "expected the forced delete to go through")
+}
+
+func TestDryRun(t *testing.T) {
+	level := func(t *testing.T, e *fakeEnv) any {
+		t.Helper()
+		cm, err := e.dynamic.Resource(gvrOf("", "v1", "configmaps")).Namespace("default").Get(context.Background(), "settings", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("get configmap: %v", err)
+		}
+		return cm.Object["data"].(map[string]any)["level"]
+	}
+
+	t.Run("apply_manifest", func(t *testing.T) {
+		e := newFakeEnv(t)
+		e.serveDryRun()
+		res, err := e.manager.handleApplyManifest(context.Background(), makeRequest(map[string]any{
+			"manifest": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: fresh\n  namespace: default\ndata:\n  level: debug\n",
+			"dry_run":  true,
+		}))
+		if err != nil {
+			t.Fatalf("go-error: %v", err)
+		}
+		out := expectOK(t, res, "apply_manifest dry_run")
+		requireContains(t, out, "Dry run: nothing was changed. ConfigMap/fresh would be created", "expected dry run summary")
+		requireContains(t, out, "level: debug", "expected the object that would be stored")
+		_, err = e.dynamic.Resource(gvrOf("", "v1", "configmaps")).Namespace("default").Get(context.Background(), "fresh", metav1.GetOptions{})
+		if !apierrors.IsNotFound(err) {
+			t.Fatalf("dry run must not create the object, got err=%v", err)
+		}
+	})
+
+	t.Run("patch_resource", func(t *testing.T) {
+		e := newFakeEnv(t, fakeConfigMap("default", "settings", map[string]string{"level": "info"}))
+		e.serveDryRun()
+		res, err := e.manager.handlePatchResource(context.Background(), makeRequest(map[string]any{
+			"version": "v1", "resource": "configmaps", "namespace": "default", "name": "settings",
+			"patch_type": "merge", "patch": `{"data":{"level":"debug"}}`, "dry_run": true,
+		}))
+		if err != nil {
+			t.Fatalf("go-error: %v", err)
+		}
+		out := expectOK(t, res, "patch_resource dry_run")
+		requireContains(t, out, "Dry run: nothing was changed.", "expected dry run summary")
+		requireContains(t, out, "level: debug", "expected the patched object")
+		if got := level(t, e); got != "info" {
+			t.Fatalf("dry run must not patch the store, got level=%v", got)
+		}
+	})
+
+	t.Run("delete_resource", func(t *testing.T) {
+		e := newFakeEnv(t, fakeConfigMap("default", "settings", map[string]string{"level": "info"}))
+		e.serveDryRun()
+		res, err := e.manager.handleDeleteResource(context.Background(), makeRequest(map[string]any{
+			"version": "v1", "resource": "configmaps", "namespace": "default", "name": "settings", "dry_run": true,
+		}))
+		if err != nil {
+			t.Fatalf("go-error: %v", err)
+		}
+		out := expectOK(t, res, "delete_resource dry_run")
+		requireContains(t, out, "would be admitted; it would remove", "expected dry run summary")
+		requireContains(t, out, "level: info", "expected the object that would be removed")
+		if got := level(t, e); got != "info" {
+			t.Fatalf("dry run must not delete the object")
+		}
+	})
+
+	t.Run("scale_resource", func(t *testing.T) {
+		e := newFakeEnv(t, fakeDeployment("default", "web", 2))
+		e.serveDryRun()
+		res, err := e.manager.handleScaleResource(context.Background(), makeRequest(map[string]any{
+			"version": "v1", "resource": "deployments", "namespace": "default", "name": "web", "replicas": float64(5), "dry_run": true,
+		}))
+		if err != nil {
+			t.Fatalf("go-error: %v", err)
+		}
+		out := expectOK(t, res, "scale_resource dry_run")
+		requireContains(t, out, "Scaling deployments/web to 5 replicas would store", "expected dry run summary")
+		requireContains(t, out, "replicas: 5", "expected the scaled object")
+		obj, err := e.dynamic.Resource(gvrOf("apps", "v1", "deployments")).Namespace("default").Get(context.Background(), "web", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("get deployment: %v", err)
+		}
+		if replicas, _, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas"); replicas != 2 {
+			t.Fatalf("dry run must not scale, got replicas=%d", replicas)
+		}
+	})
+}
+
+func TestCreateResource(t *testing.T) {
+	e := newFakeEnv(t, fakeConfigMap("default", "settings", map[string]string{"level": "info"}))
+	create := func(manifest string) *mcp.CallToolResult {
+		t.Helper()
+		res, err := e.manager.handleCreateResource(context.Background(), makeRequest(map[string]any{
+			"manifest":  manifest,
+			"namespace": "default",
+		}))
+		if err != nil {
+			t.Fatalf("go-error: %v", err)
+		}
+		return res
+	}
+
+	out := expectOK(t, create("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: fresh\ndata:\n  level: debug\n"), "create new")
+	requireContains(t, out, "Successfully created ConfigMap/fresh in namespace default", "expected summary line")
+
+	out = expectErr(t, create("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\ndata:\n  level: debug\n"), "create existing")
+	requireContains(t, out, "already exists", "expected the API error verbatim")
+	cm, err := e.dynamic.Resource(gvrOf("", "v1", "configmaps")).Namespace("default").Get(context.Background(), "settings", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get configmap: %v", err)
+	}
+	if got := cm.Object["data"].(map[string]any)["level"]; got != "info" {
+		t.Fatalf("an existing object must not be updated, got level=%v", got)
+	}
+
+	out = expectErr(t, create("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: b\n"), "bundle")
+	requireContains(t, out, "single document", "expected bundles to be rejected")
+}
+
+func TestReplaceResource(t *testing.T) {
+	e := newFakeEnv(t, fakeConfigMap("default", "settings", map[string]string{"level": "info", "extra": "x"}))
+	replace := func(args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		args["manifest"] = "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n  namespace: default\ndata:\n  level: debug\n"
+		res, err := e.manager.handleReplaceResource(context.Background(), makeRequest(args))
+		if err != nil {
+			t.Fatalf("go-error: %v", err)
+		}
+		return res
+	}
+
+	requireContains(t, expectErr(t, replace(map[string]any{}), "no resourceVersion"), "a resourceVersion is required", "expected the concurrency requirement")
+	requireContains(t, expectErr(t, replace(map[string]any{"resource_version": "1", "fetch_current": true}), "both"), "mutually exclusive", "expected conflicting options error")
+
+	requireContains(t, expectOK(t, replace(map[string]any{"fetch_current": true}), "fetch_current"), "Successfully replaced ConfigMap/settings", "expected summary line")
+	cm, err := e.dynamic.Resource(gvrOf("", "v1", "configmaps")).Namespace("default").Get(context.Background(), "settings", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get configmap: %v", err)
+	}
+	if data := cm.Object["data"].(map[string]any); data["level"] != "debug" || data["extra"] != nil {
+		t.Fatalf("expected the manifest to replace the data wholesale, got %v", data)
+	}
+
+	e.dynamic.PrependReactor("update", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewConflict(gvrOf("", "v1", "configmaps").GroupResource(), "settings", fmt.Errorf("the object has been modified"))
+	})
+	out := expectErr(t, replace(map[string]any{"resource_version": "41"}), "conflict")
+	requireContains(t, out, "changed since resourceVersion 41 was read", "expected a re-read hint")
+}
+
+func TestReplaceResource_RemovedMetadataKeys(t *testing.T) {
+	cm := fakeConfigMap("default", "settings", map[string]string{"level": "info"})
+	cm.Labels = map[string]string{"team.example.com/owner": "a", "other.com/x": "b"}
+	e := newFakeEnv(t, cm)
+	authz, err := authorization.NewEvaluator(&api.AuthorizationConfig{
+		AllowAnonymous: true,
+		Policies: []api.AuthorizationPolicy{
+			{
+				Name:  "team",
+				Match: api.MatchConfig{Expression: "true"},
+				Rules: []api.AuthorizationRule{{
+					Effect:        api.RuleEffectAllow,
+					LabelPrefixes: []string{"team.example.com/"},
+				}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewEvaluator: %v", err)
+	}
+	e.manager.authz = authz
+
+	res, err := e.manager.handleReplaceResource(context.Background(), makeRequest(map[string]any{
+		"manifest":      "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n  namespace: default\n  labels:\n    team.example.com/owner: b\n",
+		"fetch_current": true,
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	requireContains(t, expectErr(t, res, "replace dropping other.com/x"), `may not change label "other.com/x"`, "expected the removed key to be named")
+
+	live, err := e.dynamic.Resource(gvrOf("", "v1", "configmaps")).Namespace("default").Get(context.Background(), "settings", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get configmap: %v", err)
+	}
+	if live.GetLabels()["team.example.com/owner"] != "a" {
+		t.Fatalf("expected the denied replace to leave the object untouched, got %v", live.GetLabels())
+	}
+}
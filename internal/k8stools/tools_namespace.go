@@ -0,0 +1,271 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"kubernetes-mcp/internal/authorization"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	namespaceDefaultEvents = 10
+	namespaceMaxEvents     = 50
+)
+
+// namespaceWorkloadGVRs are the kinds counted by describe_namespace
+var namespaceWorkloadGVRs = []schema.GroupVersionResource{
+	{Group: "apps", Version: "v1", Resource: "deployments"},
+	{Group: "apps", Version: "v1", Resource: "statefulsets"},
+	{Group: "apps", Version: "v1", Resource: "daemonsets"},
+	{Group: "batch", Version: "v1", Resource: "jobs"},
+	{Group: "batch", Version: "v1", Resource: "cronjobs"},
+	{Group: "", Version: "v1", Resource: "services"},
+}
+
+func (m *Manager) registerDescribeNamespace() {
+	tool := mcp.NewTool(m.toolName("describe_namespace"),
+		mcp.WithDescription(`One-call overview of a namespace: "what's the state of this namespace?".
+
+Reports the namespace phase and labels, workload counts by kind
+(Deployments, StatefulSets, DaemonSets, Jobs, CronJobs, Services), a Pod
+phase breakdown with the number of Running Pods not ready, the most recent Warning
+events, ResourceQuota usage ('used / hard' per resource) and the
+NetworkPolicies present.
+
+Each section is best effort: a section the caller cannot read (RBAC) is
+reported under 'errors' instead of failing the whole call. Drill down with
+'list_resources' or 'describe_resource' afterwards.`),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Name of the namespace.")),
+		mcp.WithNumber("max_events", mcp.Description("Maximum Warning events to report, newest first. Integer 0..50. Defaults to 10.")),
+		mcp.WithArray("yq_expressions", mcp.Description("Optional yq expressions applied to the YAML output. Examples: '.pods', '.resource_quotas[].usage', '.warning_events[] | .reason'.")),
+		jqExpressionsParam(),
+	)
+	m.addTool(tool, m.handleDescribeNamespace)
+}
+
+func (m *Manager) handleDescribeNamespace(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	name, _ := args["name"].(string)
+	if name == "" {
+		return errorResult(fmt.Errorf("name is required")), nil
+	}
+
+	maxEvents := namespaceDefaultEvents
+	if v, ok := args["max_events"].(float64); ok {
+		if v < 0 || v > namespaceMaxEvents {
+			return errorResult(fmt.Errorf("max_events must be between 0 and %d, got %v", namespaceMaxEvents, v)), nil
+		}
+		maxEvents = int(v)
+	}
+
+	// Check authorization (real K8s resource: Namespace)
+	if err := m.checkAuthorization(request, "describe_namespace", k8sContext, name, authorization.ResourceInfo{
+		Group:    "",
+		Version:  "v1",
+		Resource: "namespaces",
+		Name:     name,
+	}); err != nil {
+		return errorResult(err), nil
+	}
+
+	if !m.clientManager.IsNamespaceAllowed(k8sContext, name) {
+		return errorResult(fmt.Errorf("namespace %s is not allowed in context %s", name, k8sContext)), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+	core := client.Clientset.CoreV1()
+
+	ns, err := core.Namespaces().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	overview := map[string]any{
+		"name":   ns.Name,
+		"phase":  string(ns.Status.Phase),
+		"age":    fmt.Sprintf("%v", metav1.Now().Sub(ns.CreationTimestamp.Time).Round(1e9)),
+		"labels": ns.Labels,
+	}
+	// Same treatment as get_cluster_info: a section that can't be read is
+	// reported under 'errors' so the model can tell "none" from "denied".
+	sectionErrors := map[string]string{}
+
+	workloads := map[string]int{}
+	for _, gvr := range namespaceWorkloadGVRs {
+		list, err := client.DynamicClient.Resource(gvr).Namespace(name).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			sectionErrors["workloads."+gvr.Resource] = err.Error()
+			continue
+		}
+		workloads[gvr.Resource] = len(list.Items)
+	}
+	overview["workloads"] = workloads
+
+	if pods, err := core.Pods(name).List(ctx, metav1.ListOptions{}); err == nil {
+		overview["pods"] = summarizePodPhases(pods.Items)
+	} else {
+		sectionErrors["pods"] = err.Error()
+	}
+
+	if events, err := core.Events(name).List(ctx, metav1.ListOptions{FieldSelector: "type=Warning"}); err == nil {
+		overview["warning_events"] = summarizeWarningEvents(events.Items, maxEvents)
+	} else {
+		sectionErrors["warning_events"] = err.Error()
+	}
+
+	if quotas, err := core.ResourceQuotas(name).List(ctx, metav1.ListOptions{}); err == nil {
+		overview["resource_quotas"] = summarizeResourceQuotas(quotas.Items)
+	} else {
+		sectionErrors["resource_quotas"] = err.Error()
+	}
+
+	if policies, err := client.Clientset.NetworkingV1().NetworkPolicies(name).List(ctx, metav1.ListOptions{}); err == nil {
+		overview["network_policies"] = summarizeNetworkPolicies(policies.Items)
+	} else {
+		sectionErrors["network_policies"] = err.Error()
+	}
+
+	if len(sectionErrors) > 0 {
+		overview["errors"] = sectionErrors
+	}
+
+	yamlOutput, err := objectToYAML(overview)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	finalOutput, err := m.applyOutputFilters(yamlOutput, args)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	return successResult(finalOutput), nil
+}
+
+// summarizePodPhases counts Pods per phase, plus the running ones that are
+// not Ready (the usual "it's up but not serving" case).
+func summarizePodPhases(pods []corev1.Pod) map[string]any {
+	phases := map[string]int{}
+	notReady := 0
+	for _, pod := range pods {
+		phases[string(pod.Status.Phase)]++
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		for _, c := range pod.Status.Conditions {
+			if c.Type == corev1.PodReady && c.Status != corev1.ConditionTrue {
+				notReady++
+				break
+			}
+		}
+	}
+	return map[string]any{
+		"total":             len(pods),
+		"phases":            phases,
+		"running_not_ready": notReady,
+	}
+}
+
+// summarizeWarningEvents returns the newest Warning events. The field
+// selector already filters server-side; the check is repeated because not
+// every client (fakes included) honours it.
+func summarizeWarningEvents(events []corev1.Event, limit int) []map[string]any {
+	var warnings []corev1.Event
+	for _, e := range events {
+		if e.Type == corev1.EventTypeWarning {
+			warnings = append(warnings, e)
+		}
+	}
+	sort.SliceStable(warnings, func(i, j int) bool {
+		return eventTime(warnings[i]).After(eventTime(warnings[j]))
+	})
+	if len(warnings) > limit {
+		warnings = warnings[:limit]
+	}
+
+	out := make([]map[string]any, 0, len(warnings))
+	for _, e := range warnings {
+		entry := map[string]any{
+			"object":  fmt.Sprintf("%s/%s", e.InvolvedObject.Kind, e.InvolvedObject.Name),
+			"reason":  e.Reason,
+			"message": e.Message,
+			"count":   e.Count,
+		}
+		if t := eventTime(e); !t.IsZero() {
+			entry["last_seen"] = t.UTC().Format("2006-01-02T15:04:05Z")
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+// summarizeResourceQuotas renders each quota as 'used / hard' per resource
+func summarizeResourceQuotas(quotas []corev1.ResourceQuota) []map[string]any {
+	out := make([]map[string]any, 0, len(quotas))
+	for _, q := range quotas {
+		usage := make(map[string]string, len(q.Status.Hard))
+		for resourceName, hard := range q.Status.Hard {
+			used := "0"
+			if u, ok := q.Status.Used[resourceName]; ok {
+				used = u.String()
+			}
+			usage[string(resourceName)] = fmt.Sprintf("%s / %s", used, hard.String())
+		}
+		out = append(out, map[string]any{
+			"name":  q.Name,
+			"usage": usage,
+		})
+	}
+	return out
+}
+
+// summarizeNetworkPolicies lists each policy with the Pods it selects and
+// the directions it restricts.
+func summarizeNetworkPolicies(policies []networkingv1.NetworkPolicy) []map[string]any {
+	out := make([]map[string]any, 0, len(policies))
+	for _, p := range policies {
+		selector := metav1.FormatLabelSelector(&p.Spec.PodSelector)
+		if selector == "<none>" {
+			selector = "<all pods>"
+		}
+		types := make([]string, 0, len(p.Spec.PolicyTypes))
+		for _, t := range p.Spec.PolicyTypes {
+			types = append(types, string(t))
+		}
+		out = append(out, map[string]any{
+			"name":         p.Name,
+			"pod_selector": selector,
+			"policy_types": types,
+		})
+	}
+	return out
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDescribeNamespace(t *testing.T) {
+	now := time.Now()
+
+	running := fakePod("shop", "web-1", nil)
+	running.Status.Phase = corev1.PodRunning
+	running.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+	unready := fakePod("shop", "web-2", nil)
+	unready.Status.Phase = corev1.PodRunning
+	unready.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}}
+	pending := fakePod("shop", "web-3", nil)
+	pending.Status.Phase = corev1.PodPending
+
+	warning := func(name, reason string, at time.Time) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Namespace: "shop", Name: name},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web-2", Namespace: "shop"},
+			Type:           corev1.EventTypeWarning,
+			Reason:         reason,
+			LastTimestamp:  metav1.NewTime(at),
+		}
+	}
+	normal := warning("web.normal", "Scheduled", now)
+	normal.Type = corev1.EventTypeNormal
+
+	e := newFakeEnv(t,
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: "shop", Labels: map[string]string{"team": "checkout"}},
+			Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceActive},
+		},
+		fakeDeployment("shop", "web", 3),
+		fakeDeployment("shop", "api", 2),
+		fakeDeployment("other", "ignored", 1),
+		running, unready, pending,
+		warning("web.1", "BackOff", now.Add(-time.Hour)),
+		warning("web.2", "Unhealthy", now.Add(-time.Minute)),
+		normal,
+		&corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "compute"},
+			Status: corev1.ResourceQuotaStatus{
+				Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")},
+				Used: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("3")},
+			},
+		},
+		&networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "default-deny"},
+			Spec:       networkingv1.NetworkPolicySpec{PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}},
+		},
+	)
+
+	res, err := e.manager.handleDescribeNamespace(context.Background(), makeRequest(map[string]any{
+		"name":       "shop",
+		"max_events": float64(1),
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "describe_namespace")
+	requireContains(t, out, "phase: Active", "expected namespace phase")
+	requireContains(t, out, "team: checkout", "expected namespace labels")
+	requireContains(t, out, "deployments: 2", "expected only this namespace's deployments")
+	requireContains(t, out, "total: 3", "expected pod total")
+	requireContains(t, out, "Pending: 1", "expected phase breakdown")
+	requireContains(t, out, "running_not_ready: 1", "expected unready pod count")
+	requireContains(t, out, "reason: Unhealthy", "expected newest warning")
+	requireContains(t, out, "pods: 3 / 10", "expected quota usage")
+	requireContains(t, out, "pod_selector: <all pods>", "expected network policy")
+	for _, unexpected := range []string{"BackOff", "Scheduled", "errors:"} {
+		if strings.Contains(out, unexpected) {
+			t.Fatalf("did not expect %q in output:\n%s", unexpected, out)
+		}
+	}
+}
+
+func TestDescribeNamespace_Errors(t *testing.T) {
+	e := newFakeEnv(t)
+
+	res, err := e.manager.handleDescribeNamespace(context.Background(), makeRequest(map[string]any{
+		"name":       "shop",
+		"max_events": float64(500),
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	text := expectErr(t, res, "max_events out of range")
+	requireContains(t, text, "max_events must be between 0 and 50", "expected range error")
+
+	e.provider.deniedNamespaces = []string{"kube-system"}
+	res, err = e.manager.handleDescribeNamespace(context.Background(), makeRequest(map[string]any{
+		"name": "kube-system",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	text = expectErr(t, res, "denied namespace")
+	requireContains(t, text, "namespace kube-system is not allowed", "expected namespace error")
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"kubernetes-mcp/internal/authorization"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (m *Manager) registerGetNodeStatus() {
+	tool := mcp.NewTool(m.toolName("get_node_status"),
+		mcp.WithDescription(`Summarize the health of one Node or all Nodes.
+
+For each Node the output reports its conditions (Ready, MemoryPressure,
+DiskPressure, PIDPressure, NetworkUnavailable), kubelet version, capacity
+and allocatable resources, taints and whether it is cordoned. Nodes flagged
+'healthy: false' (not Ready, under pressure or with network unavailable)
+are listed first, with the offending conditions in 'problems'.
+
+Prefer this over 'get_resource' on a Node: the raw object carries images
+and volume lists that rarely matter when checking node health.`),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("name", mcp.Description("Specific Node name. If empty, every Node (optionally filtered by 'label_selector') is reported.")),
+		mcp.WithString("label_selector", mcp.Description("Kubernetes label selector applied to Nodes when 'name' is empty. Examples: 'node-role.kubernetes.io/control-plane=', 'topology.kubernetes.io/zone=eu-west-1a'.")),
+		mcp.WithArray("yq_expressions", mcp.Description("Optional yq expressions applied to the YAML output. The output is a map with an 'items' list. Examples: '.items[] | select(.healthy == false) | .name' (unhealthy nodes), '.items[] | {name, kubelet_version}'.")),
+		jqExpressionsParam(),
+	)
+	m.addTool(tool, m.handleGetNodeStatus)
+}
+
+func (m *Manager) handleGetNodeStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	name, _ := args["name"].(string)
+	labelSelector, _ := args["label_selector"].(string)
+
+	// Check authorization (real K8s resource: Node)
+	if err := m.checkAuthorization(request, "get_node_status", k8sContext, "", authorization.ResourceInfo{
+		Group:    "",
+		Version:  "v1",
+		Resource: "nodes",
+		Name:     name,
+	}); err != nil {
+		return errorResult(err), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	var nodes []corev1.Node
+	if name != "" {
+		node, err := client.Clientset.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return errorResult(err), nil
+		}
+		nodes = append(nodes, *node)
+	} else {
+		list, err := client.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{
+			LabelSelector: labelSelector,
+		})
+		if err != nil {
+			return errorResult(err), nil
+		}
+		nodes = list.Items
+	}
+
+	items := make([]map[string]any, 0, len(nodes))
+	for i := range nodes {
+		items = append(items, summarizeNode(&nodes[i]))
+	}
+
+	// Unhealthy nodes first, then by name
+	sort.SliceStable(items, func(i, j int) bool {
+		hi, hj := items[i]["healthy"].(bool), items[j]["healthy"].(bool)
+		if hi != hj {
+			return !hi
+		}
+		return items[i]["name"].(string) < items[j]["name"].(string)
+	})
+
+	yamlOutput, err := objectToYAML(map[string]any{"items": items})
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	finalOutput, err := m.applyOutputFilters(yamlOutput, args)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	return successResult(finalOutput), nil
+}
+
+// summarizeNode flattens a Node into the fields that matter for health checks
+func summarizeNode(node *corev1.Node) map[string]any {
+	var problems []string
+	conditions := make([]map[string]any, 0, len(node.Status.Conditions))
+	ready := false
+
+	for _, c := range node.Status.Conditions {
+		entry := map[string]any{
+			"type":   string(c.Type),
+			"status": string(c.Status),
+		}
+		if c.Reason != "" {
+			entry["reason"] = c.Reason
+		}
+		if c.Message != "" {
+			entry["message"] = c.Message
+		}
+		if !c.LastTransitionTime.IsZero() {
+			entry["last_transition"] = c.LastTransitionTime.UTC().Format("2006-01-02T15:04:05Z")
+		}
+		conditions = append(conditions, entry)
+
+		switch c.Type {
+		case corev1.NodeReady:
+			ready = c.Status == corev1.ConditionTrue
+			if !ready {
+				problems = append(problems, fmt.Sprintf("Ready=%s", c.Status))
+			}
+		case corev1.NodeMemoryPressure, corev1.NodeDiskPressure, corev1.NodePIDPressure, corev1.NodeNetworkUnavailable:
+			if c.Status == corev1.ConditionTrue {
+				problems = append(problems, string(c.Type))
+			}
+		}
+	}
+	if !ready && len(problems) == 0 {
+		// No Ready condition reported at all (node still registering)
+		problems = append(problems, "Ready condition missing")
+	}
+
+	taints := make([]string, 0, len(node.Spec.Taints))
+	for _, t := range node.Spec.Taints {
+		taints = append(taints, t.ToString())
+	}
+
+	summary := map[string]any{
+		"name":            node.Name,
+		"healthy":         len(problems) == 0,
+		"ready":           ready,
+		"unschedulable":   node.Spec.Unschedulable,
+		"kubelet_version": node.Status.NodeInfo.KubeletVersion,
+		"conditions":      conditions,
+		"taints":          taints,
+		"capacity":        resourceListToStrings(node.Status.Capacity),
+		"allocatable":     resourceListToStrings(node.Status.Allocatable),
+	}
+	if len(problems) > 0 {
+		summary["problems"] = problems
+	}
+	return summary
+}
+
+// resourceListToStrings renders quantities in their canonical string form
+// (e.g. '3800m', '16Gi') instead of the serialized Quantity struct.
+func resourceListToStrings(list corev1.ResourceList) map[string]string {
+	out := make(map[string]string, len(list))
+	for name, q := range list {
+		out[string(name)] = q.String()
+	}
+	return out
+}
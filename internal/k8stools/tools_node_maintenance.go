@@ -0,0 +1,414 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"kubernetes-mcp/internal/authorization"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	drainDefaultTimeout = 300
+	drainMaxTimeout     = 600
+
+	// mirrorPodAnnotation marks the API copy of a static Pod
+	mirrorPodAnnotation = "kubernetes.io/config.mirror"
+)
+
+// drainPollInterval is how often drain_node retries evictions blocked by a
+// PodDisruptionBudget and checks that evicted Pods are gone
+var drainPollInterval = 2 * time.Second
+
+// drainPod is a Pod found on the node being drained, and what became of it
+type drainPod struct {
+	pod   corev1.Pod
+	evict bool
+	skip  string
+	err   error
+	gone  bool
+}
+
+func (p *drainPod) target() string {
+	return p.pod.Namespace + "/" + p.pod.Name
+}
+
+func (m *Manager) registerCordonNode() {
+	tool := mcp.NewTool(m.toolName("cordon_node"),
+		mcp.WithDescription(`Mark a Node unschedulable so no new Pods are placed on it ('kubectl cordon').
+Pods already running there are left alone; use 'drain_node' to move them.
+
+Reports the schedulable state before and after. Cordoning a Node that is
+already cordoned changes nothing.`),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Name of the Node.")),
+	)
+	m.addTool(tool, m.handleCordonNode)
+}
+
+func (m *Manager) registerUncordonNode() {
+	tool := mcp.NewTool(m.toolName("uncordon_node"),
+		mcp.WithDescription(`Mark a Node schedulable again ('kubectl uncordon'), typically after
+maintenance or a drain.
+
+Reports the schedulable state before and after. Uncordoning a Node that is
+already schedulable changes nothing.`),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Name of the Node.")),
+	)
+	m.addTool(tool, m.handleUncordonNode)
+}
+
+func (m *Manager) handleCordonNode(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return m.handleSetNodeSchedulable(ctx, request, "cordon_node", true)
+}
+
+func (m *Manager) handleUncordonNode(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return m.handleSetNodeSchedulable(ctx, request, "uncordon_node", false)
+}
+
+// handleSetNodeSchedulable serves cordon_node and uncordon_node
+func (m *Manager) handleSetNodeSchedulable(ctx context.Context, request mcp.CallToolRequest, tool string, unschedulable bool) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	name, _ := args["name"].(string)
+	if name == "" {
+		return errorResult(fmt.Errorf("name is required")), nil
+	}
+
+	// Check authorization (real K8s resource: Node)
+	if err := m.checkAuthorization(request, tool, k8sContext, "", authorization.ResourceInfo{
+		Group:    "",
+		Version:  "v1",
+		Resource: "nodes",
+		Name:     name,
+	}); err != nil {
+		return errorResult(err), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	action := "uncordoned"
+	if unschedulable {
+		action = "cordoned"
+	}
+
+	was, err := setNodeUnschedulable(ctx, client.Clientset, name, unschedulable)
+	if err != nil {
+		return errorResult(err), nil
+	}
+	if was == unschedulable {
+		return successResult(fmt.Sprintf("node/%s already %s (schedulable: %t, unchanged)", name, action, !unschedulable)), nil
+	}
+	return successResult(fmt.Sprintf("node/%s %s (schedulable: %t -> %t)", name, action, !was, !unschedulable)), nil
+}
+
+// setNodeUnschedulable sets spec.unschedulable with a strategic merge patch
+// and returns the previous value. A Node already in that state is not
+// patched.
+func setNodeUnschedulable(ctx context.Context, clientset kubernetes.Interface, name string, unschedulable bool) (bool, error) {
+	node, err := clientset.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return false, err
+	}
+	was := node.Spec.Unschedulable
+	if was == unschedulable {
+		return was, nil
+	}
+
+	patch := fmt.Appendf(nil, `{"spec":{"unschedulable":%t}}`, unschedulable)
+	if _, err := clientset.CoreV1().Nodes().Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return was, err
+	}
+	return was, nil
+}
+
+func (m *Manager) registerDrainNode() {
+	tool := mcp.NewTool(m.toolName("drain_node"),
+		mcp.WithDescription(`Drain a Node for maintenance ('kubectl drain'): cordon it, then evict its Pods
+through the Eviction API so PodDisruptionBudgets are respected.
+
+DaemonSet Pods are skipped ('ignore_daemonsets', default true) and mirror
+(static) Pods are always skipped, as are Pods not managed by a controller
+(nothing would recreate them) and Pods in namespaces this server may not
+touch. Pods using emptyDir volumes lose that data; the drain refuses to
+start unless 'delete_emptydir_data' is true.
+
+Evictions blocked by a PodDisruptionBudget are retried until
+'timeout_seconds' (default 300, max 600). The result lists every Pod as
+evicted, skipped (and why) or failed. The Node stays cordoned either way;
+use 'uncordon_node' after the maintenance.`),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("node", mcp.Required(), mcp.Description("Name of the Node to drain.")),
+		mcp.WithNumber("grace_period_seconds", mcp.Description("Termination grace period given to each Pod. Integer >= 0. Omit to use each Pod's own.")),
+		mcp.WithBoolean("ignore_daemonsets", mcp.Description("Skip DaemonSet-managed Pods instead of refusing to drain. Default true.")),
+		mcp.WithBoolean("delete_emptydir_data", mcp.Description("Evict Pods using emptyDir volumes, losing that data. Default false: such Pods make the drain refuse to start.")),
+		mcp.WithNumber("timeout_seconds", mcp.Description("Maximum time for evictions and Pod termination. Integer 1..600. Defaults to 300.")),
+	)
+	m.addWaitingTool(tool, m.handleDrainNode, drainMaxTimeout*time.Second)
+}
+
+func (m *Manager) handleDrainNode(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	name, _ := args["node"].(string)
+	if name == "" {
+		return errorResult(fmt.Errorf("node is required")), nil
+	}
+	ignoreDaemonSets := true
+	if v, ok := args["ignore_daemonsets"].(bool); ok {
+		ignoreDaemonSets = v
+	}
+	deleteEmptyDir, _ := args["delete_emptydir_data"].(bool)
+
+	var gracePeriod *int64
+	if v, ok := args["grace_period_seconds"].(float64); ok {
+		if v < 0 {
+			return errorResult(fmt.Errorf("grace_period_seconds must be >= 0, got %v", v)), nil
+		}
+		seconds := int64(v)
+		gracePeriod = &seconds
+	}
+	timeout := drainDefaultTimeout
+	if v, ok := args["timeout_seconds"].(float64); ok {
+		if v < 1 || v > drainMaxTimeout {
+			return errorResult(fmt.Errorf("timeout_seconds must be between 1 and %d, got %v", drainMaxTimeout, v)), nil
+		}
+		timeout = int(v)
+	}
+
+	// Check authorization (real K8s resource: Node)
+	if err := m.checkAuthorization(request, "drain_node", k8sContext, "", authorization.ResourceInfo{
+		Group:    "",
+		Version:  "v1",
+		Resource: "nodes",
+		Name:     name,
+	}); err != nil {
+		return errorResult(err), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	pods, err := client.Clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{FieldSelector: "spec.nodeName=" + name})
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	// Classify before changing anything, so a refused drain leaves the Node
+	// as it was
+	var drain []*drainPod
+	var blockers []string
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName != name {
+			continue
+		}
+		p := &drainPod{pod: pod}
+		switch {
+		case pod.Annotations[mirrorPodAnnotation] != "":
+			p.skip = "mirror (static) pod"
+		case controllerKind(&pod) == "DaemonSet":
+			if !ignoreDaemonSets {
+				blockers = append(blockers, p.target()+": managed by a DaemonSet (set ignore_daemonsets)")
+			}
+			p.skip = "managed by a DaemonSet"
+		case !m.clientManager.IsNamespaceAllowed(k8sContext, pod.Namespace):
+			p.skip = fmt.Sprintf("namespace %s is not allowed in context %s", pod.Namespace, k8sContext)
+		case controllerKind(&pod) == "" && pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed:
+			p.skip = "not managed by a controller, nothing would recreate it; delete it explicitly if intended"
+		case usesEmptyDir(&pod) && !deleteEmptyDir:
+			blockers = append(blockers, p.target()+": uses emptyDir volumes (set delete_emptydir_data)")
+		default:
+			// Evicting is deleting the Pod: the policies must allow it one
+			// by one, or the Node check would bypass a deny on the Pods
+			if err := m.checkAuthorization(request, "drain_node", k8sContext, pod.Namespace, authorization.ResourceInfo{
+				Group:    "",
+				Version:  "v1",
+				Resource: "pods",
+				Name:     pod.Name,
+			}); err != nil {
+				blockers = append(blockers, p.target()+": "+err.Error())
+				break
+			}
+			p.evict = true
+		}
+		drain = append(drain, p)
+	}
+	if len(blockers) > 0 {
+		return errorResult(fmt.Errorf("cannot drain node/%s, nothing was changed:\n  %s", name, strings.Join(blockers, "\n  "))), nil
+	}
+	sort.Slice(drain, func(i, j int) bool { return drain[i].target() < drain[j].target() })
+
+	was, err := setNodeUnschedulable(ctx, client.Clientset, name, true)
+	if err != nil {
+		return errorResult(fmt.Errorf("cordon node/%s: %w", name, err)), nil
+	}
+
+	drainCtx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	progress := m.newProgressReporter(ctx, request)
+	evictPods(drainCtx, client.Clientset, drain, gracePeriod, progress)
+	waitForEvictedPods(drainCtx, client.Clientset, drain)
+
+	var sb strings.Builder
+	if was {
+		fmt.Fprintf(&sb, "node/%s already cordoned\n", name)
+	} else {
+		fmt.Fprintf(&sb, "node/%s cordoned (schedulable: true -> false)\n", name)
+	}
+
+	evicted, skipped, failed := 0, 0, 0
+	var lines strings.Builder
+	for _, p := range drain {
+		switch {
+		case p.skip != "":
+			skipped++
+			fmt.Fprintf(&lines, "[SKIP]    %s: %s\n", p.target(), p.skip)
+		case p.err != nil:
+			failed++
+			fmt.Fprintf(&lines, "[FAIL]    %s: %v\n", p.target(), p.err)
+		case !p.gone:
+			evicted++
+			fmt.Fprintf(&lines, "[EVICTED] %s (still terminating)\n", p.target())
+		default:
+			evicted++
+			fmt.Fprintf(&lines, "[EVICTED] %s\n", p.target())
+		}
+	}
+	fmt.Fprintf(&sb, "Drain finished in %s: %d evicted, %d skipped, %d failed\n\n", time.Since(start).Round(time.Second), evicted, skipped, failed)
+	sb.WriteString(lines.String())
+
+	if failed > 0 {
+		sb.WriteString("\nThe drain is incomplete; the node stays cordoned.")
+		return errorResult(fmt.Errorf("%s", sb.String())), nil
+	}
+	return successResult(sb.String()), nil
+}
+
+// evictPods evicts every Pod marked for eviction, retrying those blocked by
+// a PodDisruptionBudget (429) until ctx is done
+func evictPods(ctx context.Context, clientset kubernetes.Interface, drain []*drainPod, gracePeriod *int64, progress *progressReporter) {
+	var pending []*drainPod
+	for _, p := range drain {
+		if p.evict {
+			pending = append(pending, p)
+		}
+	}
+	total, done := len(pending), 0
+
+	for len(pending) > 0 {
+		var blocked []*drainPod
+		var blockErr error
+		for _, p := range pending {
+			eviction := &policyv1.Eviction{
+				ObjectMeta:    metav1.ObjectMeta{Name: p.pod.Name, Namespace: p.pod.Namespace},
+				DeleteOptions: &metav1.DeleteOptions{GracePeriodSeconds: gracePeriod},
+			}
+			err := clientset.PolicyV1().Evictions(p.pod.Namespace).Evict(ctx, eviction)
+			switch {
+			case err == nil || apierrors.IsNotFound(err):
+			case apierrors.IsTooManyRequests(err):
+				blocked = append(blocked, p)
+				blockErr = err
+				continue
+			default:
+				p.err = err
+			}
+			done++
+			progress.Report(float64(done), float64(total), fmt.Sprintf("%d/%d pods evicted or failed, last %s", done, total, p.target()))
+		}
+
+		pending = blocked
+		if len(pending) == 0 {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			for _, p := range pending {
+				p.err = fmt.Errorf("eviction still blocked when the timeout was reached: %v", blockErr)
+			}
+			return
+		case <-time.After(drainPollInterval):
+		}
+	}
+}
+
+// waitForEvictedPods marks the evicted Pods that are gone (deleted, or
+// replaced by a new Pod with the same name) until all are or ctx is done
+func waitForEvictedPods(ctx context.Context, clientset kubernetes.Interface, drain []*drainPod) {
+	for {
+		remaining := 0
+		for _, p := range drain {
+			if !p.evict || p.err != nil || p.gone {
+				continue
+			}
+			current, err := clientset.CoreV1().Pods(p.pod.Namespace).Get(ctx, p.pod.Name, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) || (err == nil && current.UID != p.pod.UID) {
+				p.gone = true
+				continue
+			}
+			remaining++
+		}
+		if remaining == 0 {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(drainPollInterval):
+		}
+	}
+}
+
+// controllerKind returns the Kind of the Pod's controller, or "" if none
+func controllerKind(pod *corev1.Pod) string {
+	if ref := metav1.GetControllerOf(pod); ref != nil {
+		return ref.Kind
+	}
+	return ""
+}
+
+// usesEmptyDir reports whether the Pod mounts an emptyDir volume
+func usesEmptyDir(pod *corev1.Pod) bool {
+	for _, v := range pod.Spec.Volumes {
+		if v.EmptyDir != nil {
+			return true
+		}
+	}
+	return false
+}
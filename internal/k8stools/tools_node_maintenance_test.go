@@ -0,0 +1,237 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"kubernetes-mcp/api"
+	"kubernetes-mcp/internal/authorization"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// nodePod is a Pod scheduled on 'node', controlled by a 'kind' (none if empty)
+func nodePod(namespace, name, node, kind string) *corev1.Pod {
+	pod := fakePod(namespace, name, nil)
+	pod.UID = types.UID("uid-" + name)
+	pod.Spec.NodeName = node
+	pod.Status.Phase = corev1.PodRunning
+	if kind != "" {
+		controller := true
+		pod.OwnerReferences = []metav1.OwnerReference{{Kind: kind, Name: name + "-owner", Controller: &controller}}
+	}
+	return pod
+}
+
+// serveEvictions deletes evicted Pods, refusing the names in 'blocked' with
+// a 429 as a PodDisruptionBudget would, 'times' times each (forever if < 0)
+func (e *fakeEnv) serveEvictions(t *testing.T, blocked map[string]int) {
+	t.Helper()
+	e.clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		eviction := action.(k8stesting.CreateAction).GetObject().(*policyv1.Eviction)
+		if left, ok := blocked[eviction.Name]; ok && left != 0 {
+			blocked[eviction.Name] = left - 1
+			return true, nil, apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 1)
+		}
+		podsGVR := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+		if err := e.clientset.Tracker().Delete(podsGVR, eviction.Namespace, eviction.Name); err != nil {
+			return true, nil, err
+		}
+		return true, eviction, nil
+	})
+}
+
+func TestCordonUncordonNode(t *testing.T) {
+	e := newFakeEnv(t, fakeNode("worker-1", nodeCondition(corev1.NodeReady, corev1.ConditionTrue)))
+	unschedulable := func() bool {
+		node, err := e.clientset.CoreV1().Nodes().Get(context.Background(), "worker-1", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("get node: %v", err)
+		}
+		return node.Spec.Unschedulable
+	}
+
+	res, err := e.manager.handleCordonNode(context.Background(), makeRequest(map[string]any{"name": "worker-1"}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	requireContains(t, expectOK(t, res, "cordon_node"), "node/worker-1 cordoned (schedulable: true -> false)", "expected the state change")
+	if !unschedulable() {
+		t.Fatalf("expected the node to be unschedulable")
+	}
+
+	res, err = e.manager.handleCordonNode(context.Background(), makeRequest(map[string]any{"name": "worker-1"}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	requireContains(t, expectOK(t, res, "cordon_node again"), "already cordoned", "expected no change")
+
+	res, err = e.manager.handleUncordonNode(context.Background(), makeRequest(map[string]any{"name": "worker-1"}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	requireContains(t, expectOK(t, res, "uncordon_node"), "node/worker-1 uncordoned (schedulable: false -> true)", "expected the state change")
+	if unschedulable() {
+		t.Fatalf("expected the node to be schedulable again")
+	}
+
+	res, err = e.manager.handleCordonNode(context.Background(), makeRequest(map[string]any{"name": "missing"}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	requireContains(t, expectErr(t, res, "missing node"), "not found", "expected not found error")
+}
+
+func TestDrainNode(t *testing.T) {
+	interval := drainPollInterval
+	drainPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { drainPollInterval = interval })
+
+	mirror := nodePod("kube-system", "etcd-worker-1", "worker-1", "Node")
+	mirror.Annotations = map[string]string{mirrorPodAnnotation: "hash"}
+	e := newFakeEnv(t,
+		fakeNode("worker-1", nodeCondition(corev1.NodeReady, corev1.ConditionTrue)),
+		nodePod("default", "web-1", "worker-1", "ReplicaSet"),
+		nodePod("default", "db-0", "worker-1", "StatefulSet"),
+		nodePod("kube-system", "fluentd-x", "worker-1", "DaemonSet"),
+		nodePod("default", "bare", "worker-1", ""),
+		nodePod("default", "web-2", "worker-2", "ReplicaSet"),
+		mirror,
+	)
+	// The PDB lets db-0 go on the second attempt
+	e.serveEvictions(t, map[string]int{"db-0": 1})
+
+	res, err := e.manager.handleDrainNode(context.Background(), makeRequest(map[string]any{"node": "worker-1"}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "drain_node")
+	requireContains(t, out, "node/worker-1 cordoned (schedulable: true -> false)", "expected the cordon")
+	requireContains(t, out, "2 evicted, 3 skipped, 0 failed", "expected the totals")
+	requireContains(t, out, "[EVICTED] default/db-0\n", "expected the PDB-protected pod evicted after a retry")
+	requireContains(t, out, "[EVICTED] default/web-1\n", "expected the pod evicted")
+	requireContains(t, out, "[SKIP]    kube-system/fluentd-x: managed by a DaemonSet", "expected the DaemonSet pod skipped")
+	requireContains(t, out, "[SKIP]    kube-system/etcd-worker-1: mirror (static) pod", "expected the mirror pod skipped")
+	requireContains(t, out, "[SKIP]    default/bare: not managed by a controller", "expected the bare pod skipped")
+	if strings.Contains(out, "web-2") {
+		t.Fatalf("expected pods on other nodes to be left alone:\n%s", out)
+	}
+}
+
+func TestDrainNode_BlockedByPDB(t *testing.T) {
+	interval := drainPollInterval
+	drainPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { drainPollInterval = interval })
+
+	e := newFakeEnv(t,
+		fakeNode("worker-1", nodeCondition(corev1.NodeReady, corev1.ConditionTrue)),
+		nodePod("default", "db-0", "worker-1", "StatefulSet"),
+	)
+	e.serveEvictions(t, map[string]int{"db-0": -1})
+
+	res, err := e.manager.handleDrainNode(context.Background(), makeRequest(map[string]any{
+		"node":            "worker-1",
+		"timeout_seconds": float64(1),
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	text := expectErr(t, res, "blocked drain")
+	requireContains(t, text, "[FAIL]    default/db-0: eviction still blocked", "expected the blocked pod")
+	requireContains(t, text, "the node stays cordoned", "expected the incomplete drain note")
+}
+
+func TestDrainNode_RefusesBeforeCordon(t *testing.T) {
+	withEmptyDir := nodePod("default", "cache-0", "worker-1", "StatefulSet")
+	withEmptyDir.Spec.Volumes = []corev1.Volume{{Name: "scratch", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}}
+	e := newFakeEnv(t,
+		fakeNode("worker-1", nodeCondition(corev1.NodeReady, corev1.ConditionTrue)),
+		withEmptyDir,
+		nodePod("kube-system", "fluentd-x", "worker-1", "DaemonSet"),
+	)
+
+	res, err := e.manager.handleDrainNode(context.Background(), makeRequest(map[string]any{
+		"node":              "worker-1",
+		"ignore_daemonsets": false,
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	text := expectErr(t, res, "refused drain")
+	requireContains(t, text, "default/cache-0: uses emptyDir volumes (set delete_emptydir_data)", "expected the emptyDir blocker")
+	requireContains(t, text, "kube-system/fluentd-x: managed by a DaemonSet (set ignore_daemonsets)", "expected the DaemonSet blocker")
+
+	node, err := e.clientset.CoreV1().Nodes().Get(context.Background(), "worker-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get node: %v", err)
+	}
+	if node.Spec.Unschedulable {
+		t.Fatalf("expected a refused drain to leave the node schedulable")
+	}
+}
+
+func TestDrainNode_PodAuthorization(t *testing.T) {
+	e := newFakeEnv(t,
+		fakeNode("worker-1", nodeCondition(corev1.NodeReady, corev1.ConditionTrue)),
+		nodePod("default", "web-1", "worker-1", "ReplicaSet"),
+		nodePod("kube-system", "coredns-1", "worker-1", "ReplicaSet"),
+	)
+	authz, err := authorization.NewEvaluator(&api.AuthorizationConfig{
+		AllowAnonymous: true,
+		Policies: []api.AuthorizationPolicy{{
+			Name:  "no-system-pods",
+			Match: api.MatchConfig{Expression: "true"},
+			Rules: []api.AuthorizationRule{
+				{Effect: api.RuleEffectAllow},
+				{Effect: api.RuleEffectDeny, Namespaces: []string{"kube-system"}, Resources: []api.ResourceRule{{Groups: []string{""}, Resources: []string{"pods"}}}},
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("NewEvaluator: %v", err)
+	}
+	e.manager.authz = authz
+
+	res, err := e.manager.handleDrainNode(context.Background(), makeRequest(map[string]any{"node": "worker-1"}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	text := expectErr(t, res, "unauthorized drain")
+	requireContains(t, text, "kube-system/coredns-1: access denied", "expected the denied pod as a blocker")
+
+	node, err := e.clientset.CoreV1().Nodes().Get(context.Background(), "worker-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get node: %v", err)
+	}
+	if node.Spec.Unschedulable {
+		t.Fatalf("expected an unauthorized drain to leave the node schedulable")
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func fakeNode(name string, conditions ...corev1.NodeCondition) *corev1.Node {
+	return &corev1.Node{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Node"},
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Conditions: conditions,
+			NodeInfo:   corev1.NodeSystemInfo{KubeletVersion: "v1.35.0"},
+			Capacity: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("16Gi"),
+			},
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("3800m"),
+				corev1.ResourceMemory: resource.MustParse("15Gi"),
+			},
+		},
+	}
+}
+
+func nodeCondition(t corev1.NodeConditionType, status corev1.ConditionStatus) corev1.NodeCondition {
+	return corev1.NodeCondition{Type: t, Status: status}
+}
+
+func TestGetNodeStatus_UnhealthyFirst(t *testing.T) {
+	healthy := fakeNode("a-healthy",
+		nodeCondition(corev1.NodeReady, corev1.ConditionTrue),
+		nodeCondition(corev1.NodeMemoryPressure, corev1.ConditionFalse))
+	pressured := fakeNode("b-pressured",
+		nodeCondition(corev1.NodeReady, corev1.ConditionTrue),
+		nodeCondition(corev1.NodeDiskPressure, corev1.ConditionTrue))
+	notReady := fakeNode("c-notready",
+		nodeCondition(corev1.NodeReady, corev1.ConditionUnknown))
+	notReady.Spec.Unschedulable = true
+	notReady.Spec.Taints = []corev1.Taint{{Key: "node.kubernetes.io/unreachable", Effect: corev1.TaintEffectNoSchedule}}
+
+	e := newFakeEnv(t, healthy, pressured, notReady)
+
+	res, err := e.manager.handleGetNodeStatus(context.Background(), makeRequest(map[string]any{
+		"yq_expressions": []any{`[.items[] | .name + ":" + (.healthy | tostring)] | join(",")`},
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "get_node_status")
+	if got := strings.TrimSpace(out); got != "b-pressured:false,c-notready:false,a-healthy:true" {
+		t.Fatalf("unexpected order/health: %s", got)
+	}
+}
+
+func TestGetNodeStatus_Single(t *testing.T) {
+	node := fakeNode("worker-1", nodeCondition(corev1.NodeReady, corev1.ConditionFalse))
+	node.Spec.Taints = []corev1.Taint{{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}}
+	e := newFakeEnv(t, node)
+
+	res, err := e.manager.handleGetNodeStatus(context.Background(), makeRequest(map[string]any{
+		"name": "worker-1",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "get_node_status single")
+	requireContains(t, out, "name: worker-1", "expected node name")
+	requireContains(t, out, "healthy: false", "expected unhealthy node")
+	requireContains(t, out, "- Ready=False", "expected Ready problem")
+	requireContains(t, out, "kubelet_version: v1.35.0", "expected kubelet version")
+	requireContains(t, out, "- dedicated=gpu:NoSchedule", "expected taint")
+	requireContains(t, out, "cpu: 3800m", "expected allocatable cpu")
+	requireContains(t, out, "memory: 16Gi", "expected capacity memory")
+}
+
+func TestGetNodeStatus_NotFound(t *testing.T) {
+	e := newFakeEnv(t)
+
+	res, err := e.manager.handleGetNodeStatus(context.Background(), makeRequest(map[string]any{
+		"name": "missing",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	text := expectErr(t, res, "get_node_status missing node")
+	requireContains(t, text, "not found", "expected not found error")
+}
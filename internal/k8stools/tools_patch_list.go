@@ -0,0 +1,266 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"kubernetes-mcp/internal/authorization"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+func (m *Manager) registerPatchListElement() {
+	tool := mcp.NewTool(m.toolName("patch_list_element"),
+		mcp.WithDescription(`Update ONE element of a list inside a resource, matched by a key field,
+or append it when no element matches. Works on any resource, CRDs included.
+
+This is strategic-merge-by-key for resources that don't support strategic
+merge patch (every CRD): e.g. change the image of the container named 'app',
+or set one env var, without rewriting the whole list or guessing indices.
+
+The tool reads the live object, finds the element whose 'merge_key' equals
+the value in 'element', and sends a JSON Patch that:
+  - sets every field given in 'element' on the matched element (fields not
+    mentioned are kept), guarded by a 'test' op so a concurrent reorder of
+    the list makes the patch fail instead of touching the wrong element;
+  - or appends 'element' (creating the list if it's missing).`),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("group", mcp.Description("API group. Empty string \"\" for the core API.")),
+		mcp.WithString("version", mcp.Required(), mcp.Description("API version, e.g. 'v1'.")),
+		mcp.WithString("resource", mcp.Required(), mcp.Description("Resource name in the API sense: lowercase plural ('deployments', 'rollouts'). NOT the Kind.")),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Name of the resource instance to patch.")),
+		mcp.WithString("namespace", mcp.Description("Namespace where the resource lives. Required for namespaced resources.")),
+		mcp.WithString("list_path", mcp.Required(), mcp.Description("Path to the list. Dotted ('spec.template.spec.containers') or JSON Pointer ('/spec/template/spec/containers'). Nested lists are addressed by index, e.g. 'spec.template.spec.containers.0.env'.")),
+		mcp.WithString("merge_key", mcp.Required(), mcp.Description("Field identifying elements of the list, usually 'name' (containers, env vars, ports, volumes).")),
+		mcp.WithString("element", mcp.Required(), mcp.Description("Element to upsert, as a YAML or JSON object. Must contain 'merge_key'. Example: '{\"name\": \"app\", \"image\": \"nginx:1.27\"}'.")),
+	)
+	m.addTool(tool, m.handlePatchListElement)
+}
+
+func (m *Manager) handlePatchListElement(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	name, _ := args["name"].(string)
+	namespace, _ := args["namespace"].(string)
+	listPathStr, _ := args["list_path"].(string)
+	mergeKey, _ := args["merge_key"].(string)
+	elementStr, _ := args["element"].(string)
+	gvr := gvrFromArgs(args)
+	if err := validateGVR(gvr); err != nil {
+		return errorResult(err), nil
+	}
+
+	listPath, err := parseListPath(listPathStr)
+	if err != nil {
+		return errorResult(err), nil
+	}
+	if mergeKey == "" {
+		return errorResult(fmt.Errorf("merge_key is required")), nil
+	}
+
+	element := map[string]any{}
+	if err := yaml.Unmarshal([]byte(elementStr), &element); err != nil {
+		return errorResult(fmt.Errorf("failed to parse element: %w", err)), nil
+	}
+	if _, ok := element[mergeKey]; !ok {
+		return errorResult(fmt.Errorf("element must contain the merge key %q", mergeKey)), nil
+	}
+
+	// Check authorization
+	if err := m.checkAuthorization(request, "patch_list_element", k8sContext, namespace, authorization.ResourceInfo{
+		Group:    gvr.Group,
+		Version:  gvr.Version,
+		Resource: gvr.Resource,
+		Name:     name,
+	}); err != nil {
+		return errorResult(err), nil
+	}
+
+	if namespace != "" && !m.clientManager.IsNamespaceAllowed(k8sContext, namespace) {
+		return errorResult(fmt.Errorf("namespace %s is not allowed in context %s", namespace, k8sContext)), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	var nsClient dynamicResource = client.DynamicClient.Resource(gvr)
+	if namespace != "" {
+		nsClient = client.DynamicClient.Resource(gvr).Namespace(namespace)
+	}
+
+	live, err := nsClient.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	ops, action, err := buildListElementPatch(live.Object, listPath, mergeKey, element)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	patchBytes, err := json.Marshal(ops)
+	if err != nil {
+		return errorResult(fmt.Errorf("failed to encode JSON patch: %w", err)), nil
+	}
+
+	var result *unstructured.Unstructured
+	if namespace != "" {
+		result, err = client.DynamicClient.Resource(gvr).Namespace(namespace).Patch(ctx, name, types.JSONPatchType, patchBytes, metav1.PatchOptions{})
+	} else {
+		result, err = client.DynamicClient.Resource(gvr).Patch(ctx, name, types.JSONPatchType, patchBytes, metav1.PatchOptions{})
+	}
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	yamlOutput, err := objectToYAML(result)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	return successResult(fmt.Sprintf("Successfully %s element %s=%v in %s of %s/%s\n\n%s",
+		action, mergeKey, element[mergeKey], strings.Join(listPath, "."), gvr.Resource, name, m.redactYAML(yamlOutput))), nil
+}
+
+// parseListPath splits a dotted path or an RFC 6901 JSON Pointer into its
+// segments.
+func parseListPath(p string) ([]string, error) {
+	p = strings.TrimSpace(p)
+	if p == "" || p == "/" {
+		return nil, fmt.Errorf("list_path is required")
+	}
+
+	if strings.HasPrefix(p, "/") {
+		segments := strings.Split(p[1:], "/")
+		for i, s := range segments {
+			segments[i] = unescapeJSONPointer(s)
+		}
+		return segments, nil
+	}
+
+	segments := strings.Split(p, ".")
+	for _, s := range segments {
+		if s == "" {
+			return nil, fmt.Errorf("invalid list_path %q: empty segment", p)
+		}
+	}
+	return segments, nil
+}
+
+// jsonPointer renders path segments as an RFC 6901 JSON Pointer
+func jsonPointer(segments []string) string {
+	var sb strings.Builder
+	for _, s := range segments {
+		sb.WriteString("/")
+		sb.WriteString(strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1"))
+	}
+	return sb.String()
+}
+
+// buildListElementPatch returns the JSON Patch operations that upsert
+// 'element' into the list at 'listPath' of 'obj', matching on 'mergeKey',
+// and whether the element was "updated" or "appended".
+func buildListElementPatch(obj map[string]any, listPath []string, mergeKey string, element map[string]any) ([]map[string]any, string, error) {
+	keyValue := element[mergeKey]
+
+	// Walk to the list. Maps are addressed by key, lists by index.
+	var node any = obj
+	for i, segment := range listPath {
+		switch current := node.(type) {
+		case map[string]any:
+			next, ok := current[segment]
+			if !ok {
+				// Only the list itself may be missing; it is then created.
+				if i == len(listPath)-1 {
+					return []map[string]any{
+						{"op": "add", "path": jsonPointer(listPath), "value": []any{element}},
+					}, "appended", nil
+				}
+				return nil, "", fmt.Errorf("path %s does not exist in the object", jsonPointer(listPath[:i+1]))
+			}
+			node = next
+		case []any:
+			idx, err := strconv.Atoi(segment)
+			if err != nil || idx < 0 || idx >= len(current) {
+				return nil, "", fmt.Errorf("path %s: %q is not a valid index into a list of %d elements", jsonPointer(listPath[:i+1]), segment, len(current))
+			}
+			node = current[idx]
+		default:
+			return nil, "", fmt.Errorf("path %s does not point into an object or list", jsonPointer(listPath[:i+1]))
+		}
+	}
+
+	list, ok := node.([]any)
+	if !ok {
+		if node == nil {
+			// 'field: null' is treated like a missing list
+			return []map[string]any{
+				{"op": "replace", "path": jsonPointer(listPath), "value": []any{element}},
+			}, "appended", nil
+		}
+		return nil, "", fmt.Errorf("%s is not a list", jsonPointer(listPath))
+	}
+
+	for idx, item := range list {
+		itemMap, ok := item.(map[string]any)
+		if !ok || fmt.Sprint(itemMap[mergeKey]) != fmt.Sprint(keyValue) {
+			continue
+		}
+
+		elementPath := append(append([]string{}, listPath...), strconv.Itoa(idx))
+		ops := []map[string]any{
+			// Fails the whole patch if the list changed under us
+			{"op": "test", "path": jsonPointer(append(elementPath, mergeKey)), "value": itemMap[mergeKey]},
+		}
+
+		// Deterministic op order keeps the patch readable and testable
+		keys := make([]string, 0, len(element))
+		for k := range element {
+			if k != mergeKey {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			// 'add' on an object member replaces it when present
+			ops = append(ops, map[string]any{"op": "add", "path": jsonPointer(append(elementPath, k)), "value": element[k]})
+		}
+		return ops, "updated", nil
+	}
+
+	return []map[string]any{
+		{"op": "add", "path": jsonPointer(listPath) + "/-", "value": element},
+	}, "appended", nil
+}
+
+// unescapeJSONPointer decodes one RFC 6901 reference token
+func unescapeJSONPointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
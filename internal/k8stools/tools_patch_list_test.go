@@ -0,0 +1,220 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestParseListPath(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []string
+		wantErr bool
+	}{
+		{in: "spec.template.spec.containers", want: []string{"spec", "template", "spec", "containers"}},
+		{in: "/spec/template/spec/containers", want: []string{"spec", "template", "spec", "containers"}},
+		{in: "/metadata/annotations/a~1b~0c", want: []string{"metadata", "annotations", "a/b~c"}},
+		{in: "", wantErr: true},
+		{in: "spec..containers", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseListPath(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseListPath(%q): expected error, got %v", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseListPath(%q): unexpected error %v", tt.in, err)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("parseListPath(%q) = %v, want %v", tt.in, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("parseListPath(%q) = %v, want %v", tt.in, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestBuildListElementPatch(t *testing.T) {
+	obj := map[string]any{
+		"spec": map[string]any{
+			"containers": []any{
+				map[string]any{"name": "sidecar", "image": "envoy:1"},
+				map[string]any{"name": "app", "image": "nginx:1.25"},
+			},
+			"initContainers": nil,
+		},
+	}
+
+	tests := []struct {
+		name       string
+		path       []string
+		element    map[string]any
+		wantAction string
+		wantPatch  string
+		wantErr    bool
+	}{
+		{
+			name:       "update matched element",
+			path:       []string{"spec", "containers"},
+			element:    map[string]any{"name": "app", "image": "nginx:1.27"},
+			wantAction: "updated",
+			wantPatch:  `[{"op":"test","path":"/spec/containers/1/name","value":"app"},{"op":"add","path":"/spec/containers/1/image","value":"nginx:1.27"}]`,
+		},
+		{
+			name:       "append missing element",
+			path:       []string{"spec", "containers"},
+			element:    map[string]any{"name": "new", "image": "busybox"},
+			wantAction: "appended",
+			wantPatch:  `[{"op":"add","path":"/spec/containers/-","value":{"image":"busybox","name":"new"}}]`,
+		},
+		{
+			name:       "create missing list",
+			path:       []string{"spec", "volumes"},
+			element:    map[string]any{"name": "data"},
+			wantAction: "appended",
+			wantPatch:  `[{"op":"add","path":"/spec/volumes","value":[{"name":"data"}]}]`,
+		},
+		{
+			name:       "null list",
+			path:       []string{"spec", "initContainers"},
+			element:    map[string]any{"name": "init"},
+			wantAction: "appended",
+			wantPatch:  `[{"op":"replace","path":"/spec/initContainers","value":[{"name":"init"}]}]`,
+		},
+		{
+			name:       "nested list by index",
+			path:       []string{"spec", "containers", "1", "env"},
+			element:    map[string]any{"name": "LOG_LEVEL", "value": "debug"},
+			wantAction: "appended",
+			wantPatch:  `[{"op":"add","path":"/spec/containers/1/env","value":[{"name":"LOG_LEVEL","value":"debug"}]}]`,
+		},
+		{
+			name:    "missing parent",
+			path:    []string{"spec", "template", "containers"},
+			element: map[string]any{"name": "app"},
+			wantErr: true,
+		},
+		{
+			name:    "not a list",
+			path:    []string{"spec"},
+			element: map[string]any{"name": "app"},
+			wantErr: true,
+		},
+		{
+			name:    "index out of range",
+			path:    []string{"spec", "containers", "5", "env"},
+			element: map[string]any{"name": "app"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ops, action, err := buildListElementPatch(obj, tt.path, "name", tt.element)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %v", ops)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if action != tt.wantAction {
+				t.Errorf("action = %q, want %q", action, tt.wantAction)
+			}
+			got, _ := json.Marshal(ops)
+			if string(got) != tt.wantPatch {
+				t.Errorf("patch mismatch\n got: %s\nwant: %s", got, tt.wantPatch)
+			}
+		})
+	}
+}
+
+func TestPatchListElement(t *testing.T) {
+	deploy := fakeDeployment("default", "web", 1)
+	deploy.Spec.Template.Spec.Containers = append(deploy.Spec.Template.Spec.Containers,
+		corev1.Container{Name: "sidecar", Image: "envoy:1"})
+	e := newFakeEnv(t, deploy)
+
+	res, err := e.manager.handlePatchListElement(context.Background(), makeRequest(map[string]any{
+		"group":     "apps",
+		"version":   "v1",
+		"resource":  "deployments",
+		"namespace": "default",
+		"name":      "web",
+		"list_path": "spec.template.spec.containers",
+		"merge_key": "name",
+		"element":   "name: sidecar\nimage: envoy:2\n",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "patch_list_element")
+	requireContains(t, out, "Successfully updated element name=sidecar in spec.template.spec.containers of deployments/web", "expected summary line")
+
+	obj, err := e.dynamic.Resource(gvrOf("apps", "v1", "deployments")).Namespace("default").Get(context.Background(), "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get patched deployment: %v", err)
+	}
+	containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+	if len(containers) != 2 {
+		t.Fatalf("expected 2 containers, got %d", len(containers))
+	}
+	if img := containers[0].(map[string]any)["image"]; img != "nginx:1.27" {
+		t.Errorf("untouched container changed: image=%v", img)
+	}
+	if img := containers[1].(map[string]any)["image"]; img != "envoy:2" {
+		t.Errorf("expected sidecar image envoy:2, got %v", img)
+	}
+}
+
+func TestPatchListElement_ElementWithoutMergeKey(t *testing.T) {
+	e := newFakeEnv(t, fakeDeployment("default", "web", 1))
+
+	res, err := e.manager.handlePatchListElement(context.Background(), makeRequest(map[string]any{
+		"group":     "apps",
+		"version":   "v1",
+		"resource":  "deployments",
+		"namespace": "default",
+		"name":      "web",
+		"list_path": "spec.template.spec.containers",
+		"merge_key": "name",
+		"element":   `{"image": "nginx:1.27"}`,
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	text := expectErr(t, res, "element without merge key")
+	requireContains(t, text, `element must contain the merge key "name"`, "unexpected error text")
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"fmt"
+
+	"kubernetes-mcp/internal/authorization"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (m *Manager) registerGetPDBStatus() {
+	tool := mcp.NewTool(m.toolName("get_pdb_status"),
+		mcp.WithDescription(`Summarize PodDisruptionBudgets: which Pods each one protects and how many
+voluntary disruptions it currently allows.
+
+For every PDB the output reports the selector, 'min_available' /
+'max_unavailable', the 'current_healthy' / 'desired_healthy' / 'expected_pods'
+counters and 'disruptions_allowed' from its status. 'blocking: true' marks
+PDBs that allow no disruption right now: evicting (or draining a node that
+runs) any Pod they select will be refused by the API server.
+
+Run it before draining nodes or evicting Pods.`),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("namespace", mcp.Description("Namespace to scope the listing to. Empty string lists PDBs across ALL namespaces (subject to RBAC).")),
+		mcp.WithString("name", mcp.Description("Specific PDB name. Requires 'namespace'. If empty, every PDB in scope is reported.")),
+		mcp.WithString("label_selector", mcp.Description("Kubernetes label selector applied to the PDBs themselves (not to the Pods they protect). Example: 'team=payments'.")),
+		mcp.WithArray("yq_expressions", mcp.Description("Optional yq expressions applied to the YAML output. The output is a map with an 'items' list. Examples: '.items[] | select(.blocking) | .name' (PDBs blocking evictions), '.items[] | {name, disruptions_allowed}'.")),
+		jqExpressionsParam(),
+	)
+	m.addTool(tool, m.handleGetPDBStatus)
+}
+
+func (m *Manager) handleGetPDBStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	namespace, _ := args["namespace"].(string)
+	name, _ := args["name"].(string)
+	labelSelector, _ := args["label_selector"].(string)
+
+	if name != "" && namespace == "" {
+		return errorResult(fmt.Errorf("'namespace' is required when 'name' is set")), nil
+	}
+
+	// Check authorization (real K8s resource: PodDisruptionBudget)
+	if err := m.checkAuthorization(request, "get_pdb_status", k8sContext, namespace, authorization.ResourceInfo{
+		Group:    "policy",
+		Version:  "v1",
+		Resource: "poddisruptionbudgets",
+		Name:     name,
+	}); err != nil {
+		return errorResult(err), nil
+	}
+
+	if namespace != "" && !m.clientManager.IsNamespaceAllowed(k8sContext, namespace) {
+		return errorResult(fmt.Errorf("namespace %s is not allowed in context %s", namespace, k8sContext)), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	var pdbs []policyv1.PodDisruptionBudget
+	if name != "" {
+		pdb, err := client.Clientset.PolicyV1().PodDisruptionBudgets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return errorResult(err), nil
+		}
+		pdbs = append(pdbs, *pdb)
+	} else {
+		list, err := client.Clientset.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: labelSelector,
+		})
+		if err != nil {
+			return errorResult(err), nil
+		}
+		pdbs = list.Items
+	}
+
+	items := make([]map[string]any, 0, len(pdbs))
+	for i := range pdbs {
+		// An all-namespaces listing may include PDBs from denied namespaces;
+		// drop them rather than leaking their selectors.
+		if namespace == "" && !m.clientManager.IsNamespaceAllowed(k8sContext, pdbs[i].Namespace) {
+			continue
+		}
+		items = append(items, summarizePDB(&pdbs[i]))
+	}
+
+	yamlOutput, err := objectToYAML(map[string]any{"items": items})
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	finalOutput, err := m.applyOutputFilters(yamlOutput, args)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	return successResult(finalOutput), nil
+}
+
+// summarizePDB flattens the spec and status of a PodDisruptionBudget into the
+// handful of fields that matter when planning an eviction.
+func summarizePDB(pdb *policyv1.PodDisruptionBudget) map[string]any {
+	summary := map[string]any{
+		"name":                pdb.Name,
+		"namespace":           pdb.Namespace,
+		"selector":            metav1.FormatLabelSelector(pdb.Spec.Selector),
+		"current_healthy":     pdb.Status.CurrentHealthy,
+		"desired_healthy":     pdb.Status.DesiredHealthy,
+		"expected_pods":       pdb.Status.ExpectedPods,
+		"disruptions_allowed": pdb.Status.DisruptionsAllowed,
+		"blocking":            pdb.Status.DisruptionsAllowed <= 0,
+	}
+	if pdb.Spec.MinAvailable != nil {
+		summary["min_available"] = pdb.Spec.MinAvailable.String()
+	}
+	if pdb.Spec.MaxUnavailable != nil {
+		summary["max_unavailable"] = pdb.Spec.MaxUnavailable.String()
+	}
+	if pdb.Spec.UnhealthyPodEvictionPolicy != nil {
+		summary["unhealthy_pod_eviction_policy"] = string(*pdb.Spec.UnhealthyPodEvictionPolicy)
+	}
+	// The disruption controller has not processed the PDB yet when
+	// observedGeneration lags behind; the counters above are then stale.
+	if pdb.Status.ObservedGeneration < pdb.Generation {
+		summary["stale"] = true
+	}
+	return summary
+}
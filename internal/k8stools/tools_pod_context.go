@@ -0,0 +1,398 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"kubernetes-mcp/internal/authorization"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+func (m *Manager) registerGetPodContext() {
+	tool := mcp.NewTool(m.toolName("get_pod_context"),
+		mcp.WithDescription(`Gather everything around a Pod in one call: what an engineer assembles by
+hand when debugging it.
+
+Reports the Pod itself (phase, node, IP, service account), the Node it runs
+on (readiness, problems, taints), its owner chain (e.g. Deployment →
+ReplicaSet), the Services whose selector matches it, the ConfigMaps and
+Secrets it references (volumes, env, envFrom, image pull secrets) with where
+each one is used, and its PersistentVolumeClaims with their binding status.
+
+ConfigMaps and Secrets are listed by name only. With 'include_values' their
+data is included too, for each object the caller is authorized to read;
+Secret values are decoded and go through the same redaction as every other
+tool. Each section is best effort: one the caller cannot read is reported
+under 'errors' instead of failing the whole call.`),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Name of the Pod.")),
+		mcp.WithString("namespace", mcp.Description("Namespace where the Pod lives. Defaults to 'default' if empty.")),
+		mcp.WithBoolean("include_values", mcp.Description("Include the data of the referenced ConfigMaps and Secrets. Defaults to false (names only).")),
+		mcp.WithArray("yq_expressions", mcp.Description("Optional yq expressions applied to the YAML output. Examples: '.services[].name', '.secrets[] | {name, used_by}', '.node.problems'.")),
+		jqExpressionsParam(),
+	)
+	m.addTool(tool, m.handleGetPodContext)
+}
+
+func (m *Manager) handleGetPodContext(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	name, _ := args["name"].(string)
+	namespace, _ := args["namespace"].(string)
+	if namespace == "" {
+		namespace = "default"
+	}
+	includeValues, _ := args["include_values"].(bool)
+
+	if name == "" {
+		return errorResult(fmt.Errorf("name is required")), nil
+	}
+
+	// Check authorization (real K8s resource: Pod)
+	if err := m.checkAuthorization(request, "get_pod_context", k8sContext, namespace, authorization.ResourceInfo{
+		Group:    "",
+		Version:  "v1",
+		Resource: "pods",
+		Name:     name,
+	}); err != nil {
+		return errorResult(err), nil
+	}
+
+	if !m.clientManager.IsNamespaceAllowed(k8sContext, namespace) {
+		return errorResult(fmt.Errorf("namespace %s is not allowed in context %s", namespace, k8sContext)), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+	core := client.Clientset.CoreV1()
+
+	pod, err := core.Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	podContext := map[string]any{
+		"pod": map[string]any{
+			"name":            pod.Name,
+			"namespace":       pod.Namespace,
+			"phase":           string(pod.Status.Phase),
+			"node":            pod.Spec.NodeName,
+			"pod_ip":          pod.Status.PodIP,
+			"service_account": pod.Spec.ServiceAccountName,
+			"labels":          pod.Labels,
+		},
+	}
+	// Same treatment as describe_namespace: a section that can't be read is
+	// reported under 'errors' so the model can tell "none" from "denied".
+	sectionErrors := map[string]string{}
+
+	// authorized checks a related object under this tool's rules, recording
+	// the denial under 'section'.
+	authorized := func(section, resource, ns, objName string) bool {
+		err := m.checkAuthorization(request, "get_pod_context", k8sContext, ns, authorization.ResourceInfo{
+			Group:    "",
+			Version:  "v1",
+			Resource: resource,
+			Name:     objName,
+		})
+		if err != nil {
+			sectionErrors[section] = err.Error()
+			return false
+		}
+		return true
+	}
+
+	if pod.Spec.NodeName == "" {
+		podContext["node"] = nil
+	} else if authorized("node", "nodes", "", pod.Spec.NodeName) {
+		if node, err := core.Nodes().Get(ctx, pod.Spec.NodeName, metav1.GetOptions{}); err == nil {
+			summary := summarizeNode(node)
+			for _, verbose := range []string{"conditions", "capacity", "allocatable"} {
+				delete(summary, verbose)
+			}
+			podContext["node"] = summary
+		} else {
+			sectionErrors["node"] = err.Error()
+		}
+	}
+
+	if raw, err := runtime.DefaultUnstructuredConverter.ToUnstructured(pod); err == nil {
+		chain, note := m.resolveOwners(ctx, request, "get_pod_context", k8sContext, client, &unstructured.Unstructured{Object: raw})
+		owner := map[string]any{"chain": chain}
+		if note != "" {
+			owner["note"] = strings.Trim(note, " ()")
+		}
+		podContext["owner"] = owner
+	}
+
+	if authorized("services", "services", namespace, "") {
+		if services, err := core.Services(namespace).List(ctx, metav1.ListOptions{}); err == nil {
+			podContext["services"] = servicesSelectingPod(services.Items, pod)
+		} else {
+			sectionErrors["services"] = err.Error()
+		}
+	}
+
+	configMaps, secrets := podConfigReferences(pod)
+	podContext["config_maps"] = m.describePodReferences(ctx, request, k8sContext, core, namespace,
+		"ConfigMap", configMaps, includeValues, sectionErrors)
+	podContext["secrets"] = m.describePodReferences(ctx, request, k8sContext, core, namespace,
+		"Secret", secrets, includeValues, sectionErrors)
+
+	claims := make([]map[string]any, 0)
+	for _, v := range pod.Spec.Volumes {
+		if v.PersistentVolumeClaim == nil {
+			continue
+		}
+		claimName := v.PersistentVolumeClaim.ClaimName
+		section := "persistent_volume_claims." + claimName
+		if !authorized(section, "persistentvolumeclaims", namespace, claimName) {
+			continue
+		}
+		pvc, err := core.PersistentVolumeClaims(namespace).Get(ctx, claimName, metav1.GetOptions{})
+		if err != nil {
+			sectionErrors[section] = err.Error()
+			continue
+		}
+		claims = append(claims, summarizePVC(pvc, v.Name))
+	}
+	podContext["persistent_volume_claims"] = claims
+
+	if len(sectionErrors) > 0 {
+		podContext["errors"] = sectionErrors
+	}
+
+	yamlOutput, err := objectToYAML(podContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	finalOutput, err := m.applyOutputFilters(yamlOutput, args)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	return successResult(finalOutput), nil
+}
+
+// servicesSelectingPod returns the Services whose selector matches the Pod.
+// Services without a selector (manually managed endpoints) never match.
+func servicesSelectingPod(services []corev1.Service, pod *corev1.Pod) []map[string]any {
+	out := make([]map[string]any, 0)
+	podLabels := labels.Set(pod.Labels)
+	for _, svc := range services {
+		if len(svc.Spec.Selector) == 0 || !labels.SelectorFromSet(svc.Spec.Selector).Matches(podLabels) {
+			continue
+		}
+		ports := make([]string, 0, len(svc.Spec.Ports))
+		for _, p := range svc.Spec.Ports {
+			ports = append(ports, fmt.Sprintf("%d->%s/%s", p.Port, p.TargetPort.String(), p.Protocol))
+		}
+		out = append(out, map[string]any{
+			"name":       svc.Name,
+			"type":       string(svc.Spec.Type),
+			"cluster_ip": svc.Spec.ClusterIP,
+			"ports":      ports,
+		})
+	}
+	return out
+}
+
+// podConfigReferences maps every ConfigMap and Secret a Pod references to
+// where it is used ('volume:<name>', 'env:<container>',
+// 'envFrom:<container>', 'imagePullSecrets').
+func podConfigReferences(pod *corev1.Pod) (configMaps, secrets map[string][]string) {
+	configMaps = map[string][]string{}
+	secrets = map[string][]string{}
+	add := func(refs map[string][]string, name, usage string) {
+		if name == "" {
+			return
+		}
+		for _, u := range refs[name] {
+			if u == usage {
+				return
+			}
+		}
+		refs[name] = append(refs[name], usage)
+	}
+
+	for _, v := range pod.Spec.Volumes {
+		usage := "volume:" + v.Name
+		switch {
+		case v.ConfigMap != nil:
+			add(configMaps, v.ConfigMap.Name, usage)
+		case v.Secret != nil:
+			add(secrets, v.Secret.SecretName, usage)
+		case v.Projected != nil:
+			for _, source := range v.Projected.Sources {
+				if source.ConfigMap != nil {
+					add(configMaps, source.ConfigMap.Name, usage)
+				}
+				if source.Secret != nil {
+					add(secrets, source.Secret.Name, usage)
+				}
+			}
+		}
+	}
+
+	containers := append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+	for _, c := range containers {
+		for _, from := range c.EnvFrom {
+			if from.ConfigMapRef != nil {
+				add(configMaps, from.ConfigMapRef.Name, "envFrom:"+c.Name)
+			}
+			if from.SecretRef != nil {
+				add(secrets, from.SecretRef.Name, "envFrom:"+c.Name)
+			}
+		}
+		for _, env := range c.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if ref := env.ValueFrom.ConfigMapKeyRef; ref != nil {
+				add(configMaps, ref.Name, "env:"+c.Name)
+			}
+			if ref := env.ValueFrom.SecretKeyRef; ref != nil {
+				add(secrets, ref.Name, "env:"+c.Name)
+			}
+		}
+	}
+
+	for _, ref := range pod.Spec.ImagePullSecrets {
+		add(secrets, ref.Name, "imagePullSecrets")
+	}
+	return configMaps, secrets
+}
+
+// describePodReferences renders the ConfigMaps or Secrets referenced by a
+// Pod, sorted by name. With includeValues each object is read (under this
+// tool's authorization) and its data attached. Entries carry their 'kind' so
+// the redaction pass masks Secret data like it does for any Secret.
+func (m *Manager) describePodReferences(ctx context.Context, request mcp.CallToolRequest, k8sContext string,
+	core typedcorev1.CoreV1Interface, namespace, kind string, refs map[string][]string, includeValues bool,
+	sectionErrors map[string]string) []map[string]any {
+
+	resource := "configmaps"
+	if kind == "Secret" {
+		resource = "secrets"
+	}
+
+	names := make([]string, 0, len(refs))
+	for name := range refs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := make([]map[string]any, 0, len(names))
+	for _, name := range names {
+		entry := map[string]any{
+			"kind":    kind,
+			"name":    name,
+			"used_by": refs[name],
+		}
+		out = append(out, entry)
+		if !includeValues {
+			continue
+		}
+
+		section := resource + "." + name
+		if err := m.checkAuthorization(request, "get_pod_context", k8sContext, namespace, authorization.ResourceInfo{
+			Group:    "",
+			Version:  "v1",
+			Resource: resource,
+			Name:     name,
+		}); err != nil {
+			sectionErrors[section] = err.Error()
+			continue
+		}
+
+		data, err := readConfigData(ctx, core, namespace, kind, name)
+		if err != nil {
+			sectionErrors[section] = err.Error()
+			continue
+		}
+		entry["data"] = data
+	}
+	return out
+}
+
+// readConfigData returns the data of a ConfigMap or Secret as strings,
+// Secret values decoded.
+func readConfigData(ctx context.Context, core typedcorev1.CoreV1Interface, namespace, kind, name string) (map[string]string, error) {
+	data := map[string]string{}
+	if kind == "Secret" {
+		secret, err := core.Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range secret.Data {
+			data[k] = string(v)
+		}
+		return data, nil
+	}
+
+	cm, err := core.ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range cm.Data {
+		data[k] = v
+	}
+	for k := range cm.BinaryData {
+		data[k] = "<binary>"
+	}
+	return data, nil
+}
+
+// summarizePVC reports a claim's binding and size next to the volume that
+// mounts it.
+func summarizePVC(pvc *corev1.PersistentVolumeClaim, volume string) map[string]any {
+	modes := make([]string, 0, len(pvc.Status.AccessModes))
+	for _, mode := range pvc.Status.AccessModes {
+		modes = append(modes, string(mode))
+	}
+	storageClass := ""
+	if pvc.Spec.StorageClassName != nil {
+		storageClass = *pvc.Spec.StorageClassName
+	}
+	out := map[string]any{
+		"name":          pvc.Name,
+		"pod_volume":    volume,
+		"phase":         string(pvc.Status.Phase),
+		"volume":        pvc.Spec.VolumeName,
+		"storage_class": storageClass,
+		"access_modes":  modes,
+	}
+	if capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+		out["capacity"] = capacity.String()
+	}
+	return out
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"kubernetes-mcp/api"
+	"kubernetes-mcp/internal/authorization"
+	"kubernetes-mcp/internal/redaction"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// podContextObjects is a Deployment-owned Pod on a Node, selected by one
+// Service, mounting a ConfigMap and a PVC and reading a Secret from env.
+func podContextObjects() []runtime.Object {
+	controller := true
+	deployment := fakeDeployment("default", "web", 1)
+	deployment.UID = "deploy-uid"
+	rs := &appsv1.ReplicaSet{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "ReplicaSet"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web-7d4b9c", UID: "rs-uid",
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", UID: "deploy-uid", Controller: &controller}},
+		},
+	}
+
+	pod := fakePod("default", "web-7d4b9c-abcde", map[string]string{"app": "web"})
+	pod.OwnerReferences = []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web-7d4b9c", UID: "rs-uid", Controller: &controller}}
+	pod.Spec.NodeName = "node-1"
+	pod.Spec.Volumes = []corev1.Volume{
+		{Name: "config", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "web-config"}}}},
+		{Name: "data", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "web-data"}}},
+	}
+	pod.Spec.Containers[0].Env = []corev1.EnvVar{{Name: "DB_PASSWORD", ValueFrom: &corev1.EnvVarSource{
+		SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "web-db"}, Key: "password"},
+	}}}
+
+	storageClass := "standard"
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web-data"},
+		Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: &storageClass, VolumeName: "pv-123"},
+		Status: corev1.PersistentVolumeClaimStatus{
+			Phase:       corev1.ClaimBound,
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Capacity:    corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+		},
+	}
+
+	return []runtime.Object{
+		deployment, rs, pod, pvc,
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+			}},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+			Spec: corev1.ServiceSpec{
+				Type:     corev1.ServiceTypeClusterIP,
+				Selector: map[string]string{"app": "web"},
+				Ports:    []corev1.ServicePort{{Port: 80, TargetPort: intstr.FromInt32(8080), Protocol: corev1.ProtocolTCP}},
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "other"},
+			Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "other"}},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web-config"},
+			Data:       map[string]string{"LOG_LEVEL": "debug"},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web-db"},
+			Data:       map[string][]byte{"password": []byte("hunter2")},
+		},
+	}
+}
+
+func TestGetPodContext(t *testing.T) {
+	e := newFakeEnv(t, podContextObjects()...)
+
+	res, err := e.manager.handleGetPodContext(context.Background(), makeRequest(map[string]any{
+		"name": "web-7d4b9c-abcde",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "get_pod_context")
+	requireContains(t, out, "name: node-1", "expected the node")
+	requireContains(t, out, "- Deployment/web\n", "expected the owner chain")
+	requireContains(t, out, "- ReplicaSet/web-7d4b9c\n", "expected the owner chain")
+	requireContains(t, out, "80->8080/TCP", "expected the selecting service")
+	requireContains(t, out, "volume:config", "expected the ConfigMap usage")
+	requireContains(t, out, "env:app", "expected the Secret usage")
+	requireContains(t, out, "capacity: 10Gi", "expected the PVC")
+	if strings.Contains(out, "name: other") {
+		t.Fatalf("service with a non-matching selector must not be listed:\n%s", out)
+	}
+	if strings.Contains(out, "hunter2") || strings.Contains(out, "LOG_LEVEL") {
+		t.Fatalf("values must not be read without include_values:\n%s", out)
+	}
+}
+
+func TestGetPodContext_IncludeValues(t *testing.T) {
+	e := newFakeEnv(t, podContextObjects()...)
+	e.manager.redactor = redaction.NewRedactor(api.RedactionConfig{Enabled: true, SecretData: true})
+
+	res, err := e.manager.handleGetPodContext(context.Background(), makeRequest(map[string]any{
+		"name":           "web-7d4b9c-abcde",
+		"include_values": true,
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "get_pod_context include_values")
+	requireContains(t, out, "LOG_LEVEL: debug", "expected ConfigMap data")
+	requireContains(t, out, "password: '***'", "expected Secret data to be redacted")
+	if strings.Contains(out, "hunter2") {
+		t.Fatalf("secret value leaked:\n%s", out)
+	}
+}
+
+func TestGetPodContext_SecretsDenied(t *testing.T) {
+	e := newFakeEnv(t, podContextObjects()...)
+	authz, err := authorization.NewEvaluator(&api.AuthorizationConfig{
+		AllowAnonymous: true,
+		Policies: []api.AuthorizationPolicy{
+			{
+				Name:  "everyone",
+				Match: api.MatchConfig{Expression: "true"},
+				Rules: []api.AuthorizationRule{{Effect: api.RuleEffectAllow}},
+			},
+			{
+				Name:  "no-secrets",
+				Match: api.MatchConfig{Expression: `resource.resource == "secrets"`},
+				Rules: []api.AuthorizationRule{{Effect: api.RuleEffectDeny}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewEvaluator: %v", err)
+	}
+	e.manager.authz = authz
+
+	res, err := e.manager.handleGetPodContext(context.Background(), makeRequest(map[string]any{
+		"name":           "web-7d4b9c-abcde",
+		"include_values": true,
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "get_pod_context secrets denied")
+	requireContains(t, out, "secrets.web-db: 'access denied", "expected the denial under errors")
+	requireContains(t, out, "LOG_LEVEL: debug", "expected ConfigMap data to still be read")
+	if strings.Contains(out, "hunter2") {
+		t.Fatalf("denied secret was read:\n%s", out)
+	}
+}
+
+func TestGetPodContext_NotFound(t *testing.T) {
+	e := newFakeEnv(t)
+
+	res, err := e.manager.handleGetPodContext(context.Background(), makeRequest(map[string]any{"name": "missing"}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	expectErr(t, res, "get_pod_context missing pod")
+}
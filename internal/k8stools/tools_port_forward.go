@@ -0,0 +1,396 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"kubernetes-mcp/internal/authorization"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+const (
+	portForwardDefaultDuration = 300
+	portForwardMaxDuration     = 3600
+	portForwardMaxActive       = 10
+	portForwardReadyTimeout    = 30 * time.Second
+
+	// portForwardAddress is where forwards listen: the MCP host only
+	portForwardAddress = "127.0.0.1"
+)
+
+// portForward is a forward opened by port_forward. It stays up until it
+// expires, stop_port_forward stops it, the Pod goes away or the server
+// shuts down.
+type portForward struct {
+	ID           string `json:"id"`
+	Context      string `json:"context"`
+	Namespace    string `json:"namespace"`
+	Service      string `json:"service,omitempty"`
+	Pod          string `json:"pod"`
+	LocalAddress string `json:"local_address"`
+	RemotePort   int    `json:"remote_port"`
+	ExpiresAt    string `json:"expires_at"`
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	timer    *time.Timer
+}
+
+// stop closes the forward; it is safe to call more than once
+func (f *portForward) stop() {
+	f.stopOnce.Do(func() {
+		if f.timer != nil {
+			f.timer.Stop()
+		}
+		close(f.stopCh)
+	})
+}
+
+func (m *Manager) registerPortForward() {
+	tool := mcp.NewTool(m.toolName("port_forward"),
+		mcp.WithDescription(`Forward a local port on the MCP host to a Pod, or to a Pod behind a Service,
+for a limited time: "let me reach this ClusterIP service during the incident".
+
+Returns the local address to connect to (always 127.0.0.1, so only
+processes on the MCP host can use it) and a forward 'id'. The forward closes
+by itself after 'duration_seconds' (default 300, max 3600); close it earlier
+with 'stop_port_forward'. At most 10 forwards are open at a time.
+
+Set exactly one of 'pod' or 'service'. For a Service, 'remote_port' is the
+Service port; it is mapped to the target port of a running, ready Pod
+selected by the Service.`),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("namespace", mcp.Description("Namespace of the Pod or Service. Defaults to 'default' if empty.")),
+		mcp.WithString("pod", mcp.Description("Name of the Pod to forward to. Mutually exclusive with 'service'.")),
+		mcp.WithString("service", mcp.Description("Name of the Service to forward to, through one of its ready Pods. Mutually exclusive with 'pod'.")),
+		mcp.WithNumber("remote_port", mcp.Required(), mcp.Description("Port on the Pod, or the Service port when 'service' is set. Integer 1..65535.")),
+		mcp.WithNumber("local_port", mcp.Description("Local port to listen on. Integer 1..65535. Omit to pick a free one.")),
+		mcp.WithNumber("duration_seconds", mcp.Description("How long the forward stays open. Integer 1..3600. Defaults to 300.")),
+	)
+	m.addTool(tool, m.handlePortForward)
+}
+
+func (m *Manager) handlePortForward(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	namespace, _ := args["namespace"].(string)
+	if namespace == "" {
+		namespace = "default"
+	}
+	pod, _ := args["pod"].(string)
+	service, _ := args["service"].(string)
+	if (pod == "") == (service == "") {
+		return errorResult(fmt.Errorf("set exactly one of pod or service")), nil
+	}
+
+	remotePort, _ := args["remote_port"].(float64)
+	if remotePort < 1 || remotePort > 65535 {
+		return errorResult(fmt.Errorf("remote_port must be between 1 and 65535, got %v", remotePort)), nil
+	}
+	localPort := 0
+	if v, ok := args["local_port"].(float64); ok {
+		if v < 1 || v > 65535 {
+			return errorResult(fmt.Errorf("local_port must be between 1 and 65535, got %v", v)), nil
+		}
+		localPort = int(v)
+	}
+	duration := portForwardDefaultDuration
+	if v, ok := args["duration_seconds"].(float64); ok {
+		if v < 1 || v > portForwardMaxDuration {
+			return errorResult(fmt.Errorf("duration_seconds must be between 1 and %d, got %v", portForwardMaxDuration, v)), nil
+		}
+		duration = int(v)
+	}
+
+	if !m.clientManager.IsNamespaceAllowed(k8sContext, namespace) {
+		return errorResult(fmt.Errorf("namespace %s is not allowed in context %s", namespace, k8sContext)), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	port := int(remotePort)
+	if service != "" {
+		pod, port, err = podForService(ctx, client.Clientset, namespace, service, port)
+		if err != nil {
+			return errorResult(err), nil
+		}
+	}
+
+	// Check authorization (real K8s resource: Pod). Opening a connection
+	// into a Pod is treated like exec: allow it per tool, not as a read.
+	if err := m.checkAuthorization(request, "port_forward", k8sContext, namespace, authorization.ResourceInfo{
+		Group:    "",
+		Version:  "v1",
+		Resource: "pods",
+		Name:     pod,
+	}); err != nil {
+		return errorResult(err), nil
+	}
+
+	if m.activeForwards() >= portForwardMaxActive {
+		return errorResult(fmt.Errorf("%d port forwards are already open; close one with 'stop_port_forward' first", portForwardMaxActive)), nil
+	}
+
+	transport, upgrader, err := spdy.RoundTripperFor(client.Config)
+	if err != nil {
+		return errorResult(err), nil
+	}
+	url := client.Clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("portforward").
+		URL()
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, url)
+
+	fw := &portForward{
+		ID:         "pf-" + utilrand.String(8),
+		Context:    k8sContext,
+		Namespace:  namespace,
+		Service:    service,
+		Pod:        pod,
+		RemotePort: port,
+		stopCh:     make(chan struct{}),
+	}
+	readyCh := make(chan struct{})
+	var errOut strings.Builder
+	forwarder, err := portforward.NewOnAddresses(dialer, []string{portForwardAddress},
+		[]string{fmt.Sprintf("%d:%d", localPort, port)}, fw.stopCh, readyCh, io.Discard, &errOut)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- forwarder.ForwardPorts() }()
+
+	select {
+	case <-readyCh:
+	case err := <-errCh:
+		return errorResult(fmt.Errorf("port forward to pod %s/%s failed: %v %s", namespace, pod, err, errOut.String())), nil
+	case <-ctx.Done():
+		fw.stop()
+		return errorResult(ctx.Err()), nil
+	case <-time.After(portForwardReadyTimeout):
+		fw.stop()
+		return errorResult(fmt.Errorf("port forward to pod %s/%s not ready after %s", namespace, pod, portForwardReadyTimeout)), nil
+	}
+
+	ports, err := forwarder.GetPorts()
+	if err != nil || len(ports) == 0 {
+		fw.stop()
+		return errorResult(fmt.Errorf("port forward to pod %s/%s has no local port: %v", namespace, pod, err)), nil
+	}
+	fw.LocalAddress = fmt.Sprintf("%s:%d", portForwardAddress, ports[0].Local)
+	lifetime := time.Duration(duration) * time.Second
+	fw.ExpiresAt = time.Now().Add(lifetime).UTC().Format("2006-01-02T15:04:05Z")
+
+	if err := m.trackForward(fw, lifetime); err != nil {
+		fw.stop()
+		return errorResult(err), nil
+	}
+
+	// Forget the forward as soon as it ends, whatever ended it
+	go func() {
+		if err := <-errCh; err != nil {
+			m.logger.Warn("port forward ended", "id", fw.ID, "pod", namespace+"/"+pod, "error", err.Error())
+		}
+		m.stopForward(fw.ID)
+	}()
+
+	yamlOutput, err := objectToYAML(fw)
+	if err != nil {
+		return errorResult(err), nil
+	}
+	return successResult(yamlOutput), nil
+}
+
+func (m *Manager) registerStopPortForward() {
+	tool := mcp.NewTool(m.toolName("stop_port_forward"),
+		mcp.WithDescription(`Close a port forward opened by 'port_forward' before it expires.`),
+		mcp.WithString("id", mcp.Required(), mcp.Description("Forward 'id' returned by 'port_forward'.")),
+	)
+	m.addTool(tool, m.handleStopPortForward)
+}
+
+func (m *Manager) handleStopPortForward(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	id, _ := args["id"].(string)
+	if id == "" {
+		return errorResult(fmt.Errorf("id is required")), nil
+	}
+
+	m.forwardsMu.Lock()
+	fw, ok := m.forwards[id]
+	m.forwardsMu.Unlock()
+	if !ok {
+		return errorResult(fmt.Errorf("no open port forward with id %q; it may have expired", id)), nil
+	}
+
+	// Check authorization (real K8s resource: the forwarded Pod)
+	if err := m.checkAuthorization(request, "stop_port_forward", fw.Context, fw.Namespace, authorization.ResourceInfo{
+		Group:    "",
+		Version:  "v1",
+		Resource: "pods",
+		Name:     fw.Pod,
+	}); err != nil {
+		return errorResult(err), nil
+	}
+
+	m.stopForward(id)
+	return successResult(fmt.Sprintf("Port forward %s to pod %s/%s (%s) stopped", id, fw.Namespace, fw.Pod, fw.LocalAddress)), nil
+}
+
+// trackForward records an open forward and schedules its expiry
+func (m *Manager) trackForward(fw *portForward, lifetime time.Duration) error {
+	m.forwardsMu.Lock()
+	defer m.forwardsMu.Unlock()
+
+	if len(m.forwards) >= portForwardMaxActive {
+		return fmt.Errorf("%d port forwards are already open; close one with 'stop_port_forward' first", portForwardMaxActive)
+	}
+	m.forwards[fw.ID] = fw
+	fw.timer = time.AfterFunc(lifetime, func() { m.stopForward(fw.ID) })
+	return nil
+}
+
+// stopForward closes and forgets a forward. Unknown ids are ignored.
+func (m *Manager) stopForward(id string) {
+	m.forwardsMu.Lock()
+	fw, ok := m.forwards[id]
+	delete(m.forwards, id)
+	m.forwardsMu.Unlock()
+
+	if ok {
+		fw.stop()
+	}
+}
+
+// activeForwards returns the number of open forwards
+func (m *Manager) activeForwards() int {
+	m.forwardsMu.Lock()
+	defer m.forwardsMu.Unlock()
+	return len(m.forwards)
+}
+
+// Close stops every open port forward. Call it on server shutdown.
+func (m *Manager) Close() {
+	m.forwardsMu.Lock()
+	open := m.forwards
+	m.forwards = map[string]*portForward{}
+	m.forwardsMu.Unlock()
+
+	for _, fw := range open {
+		fw.stop()
+	}
+}
+
+// podForService picks a running, ready Pod selected by a Service, in name
+// order, and the container port the Service port 'port' targets on it.
+func podForService(ctx context.Context, clientset kubernetes.Interface, namespace, name string, port int) (string, int, error) {
+	svc, err := clientset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", 0, err
+	}
+	if len(svc.Spec.Selector) == 0 {
+		return "", 0, fmt.Errorf("service %s/%s has no selector, so it has no Pods to forward to", namespace, name)
+	}
+
+	var svcPort *corev1.ServicePort
+	var declared []string
+	for i, p := range svc.Spec.Ports {
+		declared = append(declared, fmt.Sprintf("%d", p.Port))
+		if int(p.Port) == port {
+			svcPort = &svc.Spec.Ports[i]
+		}
+	}
+	if svcPort == nil {
+		return "", 0, fmt.Errorf("service %s/%s has no port %d (ports: %s)", namespace, name, port, strings.Join(declared, ", "))
+	}
+
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(svc.Spec.Selector).String(),
+	})
+	if err != nil {
+		return "", 0, err
+	}
+	sort.Slice(pods.Items, func(i, j int) bool { return pods.Items[i].Name < pods.Items[j].Name })
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase != corev1.PodRunning || !podIsReady(pod) {
+			continue
+		}
+		target, ok := servicePortTarget(pod, *svcPort)
+		if !ok {
+			continue
+		}
+		return pod.Name, target, nil
+	}
+	return "", 0, fmt.Errorf("service %s/%s has no running, ready Pod serving port %d", namespace, name, port)
+}
+
+// servicePortTarget resolves a Service port's targetPort on a Pod. A named
+// target is looked up in the Pod's container ports.
+func servicePortTarget(pod *corev1.Pod, port corev1.ServicePort) (int, bool) {
+	switch {
+	case port.TargetPort.Type == intstr.String:
+		for _, c := range pod.Spec.Containers {
+			for _, p := range c.Ports {
+				if p.Name == port.TargetPort.StrVal {
+					return int(p.ContainerPort), true
+				}
+			}
+		}
+		return 0, false
+	case port.TargetPort.IntVal != 0:
+		return int(port.TargetPort.IntVal), true
+	default:
+		return int(port.Port), true
+	}
+}
+
+// podIsReady reports the Pod's Ready condition
+func podIsReady(pod *corev1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func servedPod(name string, ready bool) *corev1.Pod {
+	pod := fakePod("default", name, map[string]string{"app": "web"})
+	pod.Spec.Containers[0].Ports = []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}}
+	pod.Status.Phase = corev1.PodRunning
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: status}}
+	return pod
+}
+
+func fakeService(target intstr.IntOrString) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": "web"},
+			Ports:    []corev1.ServicePort{{Name: "http", Port: 80, TargetPort: target}},
+		},
+	}
+}
+
+func TestPodForService(t *testing.T) {
+	e := newFakeEnv(t, fakeService(intstr.FromString("http")), servedPod("web-a", false), servedPod("web-b", true))
+
+	pod, port, err := podForService(context.Background(), e.clientset, "default", "web", 80)
+	if err != nil {
+		t.Fatalf("podForService: %v", err)
+	}
+	if pod != "web-b" || port != 8080 {
+		t.Fatalf("expected the ready pod on the named port, got %s:%d", pod, port)
+	}
+
+	if _, _, err := podForService(context.Background(), e.clientset, "default", "web", 443); err == nil {
+		t.Fatalf("expected a port the service does not declare to fail")
+	}
+
+	e = newFakeEnv(t, fakeService(intstr.FromInt32(9090)), servedPod("web-a", false))
+	if _, _, err := podForService(context.Background(), e.clientset, "default", "web", 80); err == nil {
+		t.Fatalf("expected a service without ready pods to fail")
+	}
+}
+
+func TestPortForward_Rejects(t *testing.T) {
+	e := newFakeEnv(t)
+	e.provider.deniedNamespaces = []string{"kube-system"}
+
+	tests := []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{name: "no target", args: map[string]any{"remote_port": float64(80)}, want: "set exactly one of pod or service"},
+		{name: "both targets", args: map[string]any{"pod": "web", "service": "web", "remote_port": float64(80)}, want: "set exactly one of pod or service"},
+		{name: "bad port", args: map[string]any{"pod": "web", "remote_port": float64(70000)}, want: "remote_port must be between 1 and 65535"},
+		{name: "too long", args: map[string]any{"pod": "web", "remote_port": float64(80), "duration_seconds": float64(3601)}, want: "duration_seconds must be between 1 and 3600"},
+		{name: "denied namespace", args: map[string]any{"pod": "web", "namespace": "kube-system", "remote_port": float64(80)}, want: "namespace kube-system is not allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := e.manager.handlePortForward(context.Background(), makeRequest(tt.args))
+			if err != nil {
+				t.Fatalf("go-error: %v", err)
+			}
+			requireContains(t, expectErr(t, res, tt.name), tt.want, "unexpected error text")
+		})
+	}
+}
+
+func TestPortForward_Registry(t *testing.T) {
+	e := newFakeEnv(t)
+	newForward := func(id string) *portForward {
+		return &portForward{ID: id, Context: fakeContext, Namespace: "default", Pod: "web", stopCh: make(chan struct{})}
+	}
+
+	// Expiry closes and forgets the forward
+	expiring := newForward("pf-expiring")
+	if err := e.manager.trackForward(expiring, 10*time.Millisecond); err != nil {
+		t.Fatalf("trackForward: %v", err)
+	}
+	select {
+	case <-expiring.stopCh:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected the forward to expire")
+	}
+	if n := e.manager.activeForwards(); n != 0 {
+		t.Fatalf("expected no open forwards, got %d", n)
+	}
+
+	// stop_port_forward closes it by id
+	stopped := newForward("pf-stopped")
+	if err := e.manager.trackForward(stopped, time.Hour); err != nil {
+		t.Fatalf("trackForward: %v", err)
+	}
+	res, err := e.manager.handleStopPortForward(context.Background(), makeRequest(map[string]any{"id": "pf-stopped"}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	requireContains(t, expectOK(t, res, "stop_port_forward"), "stopped", "expected a confirmation")
+	<-stopped.stopCh
+
+	res, err = e.manager.handleStopPortForward(context.Background(), makeRequest(map[string]any{"id": "pf-stopped"}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	requireContains(t, expectErr(t, res, "unknown id"), "no open port forward", "expected an unknown id error")
+
+	// The cap holds, and Close stops everything on shutdown
+	var open []*portForward
+	for i := range portForwardMaxActive {
+		fw := newForward(string(rune('a' + i)))
+		if err := e.manager.trackForward(fw, time.Hour); err != nil {
+			t.Fatalf("trackForward: %v", err)
+		}
+		open = append(open, fw)
+	}
+	if err := e.manager.trackForward(newForward("pf-extra"), time.Hour); err == nil {
+		t.Fatalf("expected the forward cap to be enforced")
+	}
+	e.manager.Close()
+	for _, fw := range open {
+		<-fw.stopCh
+	}
+	if n := e.manager.activeForwards(); n != 0 {
+		t.Fatalf("expected Close to forget every forward, got %d", n)
+	}
+}
@@ -0,0 +1,362 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"kubernetes-mcp/internal/authorization"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// probeMaxEvents bounds the probe-failure events reported by get_probe_status
+const probeMaxEvents = 20
+
+func (m *Manager) registerGetProbeStatus() {
+	tool := mcp.NewTool(m.toolName("get_probe_status"),
+		mcp.WithDescription(`Show the liveness, readiness and startup probes of a Pod's containers next to
+the probe failures the kubelet reported for them.
+
+For each container the output reports whether it is ready / started, its
+restart count and last termination, and every configured probe: handler
+('http_get', 'tcp_socket', 'exec', 'grpc') with its target, initial delay,
+period, timeout and success / failure thresholds (Kubernetes defaults filled
+in). Recent 'Unhealthy' / 'ProbeWarning' events of the Pod follow, newest
+first, each attributed to its container and probe.
+
+Use this to answer "why does my Pod keep restarting / never become
+ready?". 'hints' flags common misconfigurations, such as a probe on a
+named port the container does not declare or probes timing out at a 1s
+timeout.`),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Name of the Pod.")),
+		mcp.WithString("namespace", mcp.Description("Namespace where the Pod lives. Defaults to 'default' if empty.")),
+		mcp.WithString("container", mcp.Description("Only report this container. If empty, every container of the Pod is reported.")),
+		mcp.WithArray("yq_expressions", mcp.Description("Optional yq expressions applied to the YAML output. Examples: '.containers[] | {name, probes}', '.events[] | select(.probe == \"liveness\")'.")),
+		jqExpressionsParam(),
+	)
+	m.addTool(tool, m.handleGetProbeStatus)
+}
+
+func (m *Manager) handleGetProbeStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	name, _ := args["name"].(string)
+	namespace, _ := args["namespace"].(string)
+	if namespace == "" {
+		namespace = "default"
+	}
+	container, _ := args["container"].(string)
+
+	if name == "" {
+		return errorResult(fmt.Errorf("name is required")), nil
+	}
+
+	// Check authorization (real K8s resource: Pod)
+	if err := m.checkAuthorization(request, "get_probe_status", k8sContext, namespace, authorization.ResourceInfo{
+		Group:    "",
+		Version:  "v1",
+		Resource: "pods",
+		Name:     name,
+	}); err != nil {
+		return errorResult(err), nil
+	}
+
+	if !m.clientManager.IsNamespaceAllowed(k8sContext, namespace) {
+		return errorResult(fmt.Errorf("namespace %s is not allowed in context %s", namespace, k8sContext)), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	pod, err := client.Clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	var containers []corev1.Container
+	for _, c := range pod.Spec.Containers {
+		if container == "" || c.Name == container {
+			containers = append(containers, c)
+		}
+	}
+	if len(containers) == 0 {
+		names := make([]string, 0, len(pod.Spec.Containers))
+		for _, c := range pod.Spec.Containers {
+			names = append(names, c.Name)
+		}
+		return errorResult(fmt.Errorf("container %s not found in pod %s/%s (containers: %s)", container, namespace, name, strings.Join(names, ", "))), nil
+	}
+
+	// Events are best effort, as in describe_resource: the probe
+	// configuration is still useful without them.
+	var failures []corev1.Event
+	events, err := client.Clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("involvedObject.name=%s,involvedObject.kind=Pod", name),
+	})
+	if err == nil {
+		failures = probeFailureEvents(events.Items, name, container)
+	}
+
+	statuses := make(map[string]corev1.ContainerStatus, len(pod.Status.ContainerStatuses))
+	for _, s := range pod.Status.ContainerStatuses {
+		statuses[s.Name] = s
+	}
+
+	items := make([]map[string]any, 0, len(containers))
+	var hints []string
+	for _, c := range containers {
+		items = append(items, summarizeContainerProbes(c, statuses[c.Name]))
+		hints = append(hints, probeHints(c, failures)...)
+	}
+
+	summary := map[string]any{
+		"pod":        fmt.Sprintf("%s/%s", namespace, name),
+		"phase":      string(pod.Status.Phase),
+		"containers": items,
+		"events":     summarizeProbeEvents(failures),
+	}
+	if len(hints) > 0 {
+		summary["hints"] = hints
+	}
+
+	yamlOutput, err := objectToYAML(summary)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	finalOutput, err := m.applyOutputFilters(yamlOutput, args)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	return successResult(finalOutput), nil
+}
+
+// summarizeContainerProbes joins a container's probe configuration with
+// the parts of its status the probes drive.
+func summarizeContainerProbes(c corev1.Container, status corev1.ContainerStatus) map[string]any {
+	probes := map[string]any{}
+	for kind, probe := range map[string]*corev1.Probe{
+		"liveness":  c.LivenessProbe,
+		"readiness": c.ReadinessProbe,
+		"startup":   c.StartupProbe,
+	} {
+		if probe != nil {
+			probes[kind] = summarizeProbe(probe)
+		}
+	}
+
+	entry := map[string]any{
+		"name":          c.Name,
+		"ready":         status.Ready,
+		"restart_count": status.RestartCount,
+		"probes":        probes,
+	}
+	if status.Started != nil {
+		entry["started"] = *status.Started
+	}
+	switch {
+	case status.State.Waiting != nil:
+		entry["state"] = "Waiting: " + status.State.Waiting.Reason
+	case status.State.Running != nil:
+		entry["state"] = "Running"
+	case status.State.Terminated != nil:
+		entry["state"] = "Terminated: " + status.State.Terminated.Reason
+	}
+	if t := status.LastTerminationState.Terminated; t != nil {
+		entry["last_termination"] = fmt.Sprintf("%s (exit code %d)", t.Reason, t.ExitCode)
+	}
+	return entry
+}
+
+// summarizeProbe flattens a probe, filling in the defaults the API server
+// applies so the reported timings are the effective ones.
+func summarizeProbe(p *corev1.Probe) map[string]any {
+	orDefault := func(v, def int32) int32 {
+		if v == 0 {
+			return def
+		}
+		return v
+	}
+
+	handler, target := probeHandler(p.ProbeHandler)
+	return map[string]any{
+		"handler":               handler,
+		"target":                target,
+		"initial_delay_seconds": p.InitialDelaySeconds,
+		"period_seconds":        orDefault(p.PeriodSeconds, 10),
+		"timeout_seconds":       orDefault(p.TimeoutSeconds, 1),
+		"success_threshold":     orDefault(p.SuccessThreshold, 1),
+		"failure_threshold":     orDefault(p.FailureThreshold, 3),
+	}
+}
+
+// probeHandler describes what a probe checks, e.g.
+// ('http_get', 'HTTP GET :8080/healthz') or ('tcp_socket', ':5432').
+func probeHandler(h corev1.ProbeHandler) (string, string) {
+	switch {
+	case h.HTTPGet != nil:
+		scheme := string(h.HTTPGet.Scheme)
+		if scheme == "" {
+			scheme = string(corev1.URISchemeHTTP)
+		}
+		return "http_get", fmt.Sprintf("%s GET %s:%s%s", scheme, h.HTTPGet.Host, h.HTTPGet.Port.String(), h.HTTPGet.Path)
+	case h.TCPSocket != nil:
+		return "tcp_socket", fmt.Sprintf("%s:%s", h.TCPSocket.Host, h.TCPSocket.Port.String())
+	case h.Exec != nil:
+		return "exec", strings.Join(h.Exec.Command, " ")
+	case h.GRPC != nil:
+		target := fmt.Sprintf(":%d", h.GRPC.Port)
+		if h.GRPC.Service != nil && *h.GRPC.Service != "" {
+			target += " service=" + *h.GRPC.Service
+		}
+		return "grpc", target
+	}
+	return "unknown", ""
+}
+
+// probePort returns the port a probe connects to, if it uses one
+func probePort(h corev1.ProbeHandler) (intstr.IntOrString, bool) {
+	switch {
+	case h.HTTPGet != nil:
+		return h.HTTPGet.Port, true
+	case h.TCPSocket != nil:
+		return h.TCPSocket.Port, true
+	}
+	return intstr.IntOrString{}, false
+}
+
+// probeFailureEvents keeps the probe-related events of the named Pod,
+// newest first. The field selector already filters server-side; the check
+// is repeated because not every client (fakes included) honours it.
+func probeFailureEvents(events []corev1.Event, pod, container string) []corev1.Event {
+	var matching []corev1.Event
+	for _, e := range events {
+		if e.InvolvedObject.Kind != "Pod" || e.InvolvedObject.Name != pod {
+			continue
+		}
+		if e.Reason != "Unhealthy" && e.Reason != "ProbeWarning" {
+			continue
+		}
+		if container != "" && eventContainer(e) != container {
+			continue
+		}
+		matching = append(matching, e)
+	}
+	sort.SliceStable(matching, func(i, j int) bool {
+		return eventTime(matching[i]).After(eventTime(matching[j]))
+	})
+	if len(matching) > probeMaxEvents {
+		matching = matching[:probeMaxEvents]
+	}
+	return matching
+}
+
+func summarizeProbeEvents(events []corev1.Event) []map[string]any {
+	out := make([]map[string]any, 0, len(events))
+	for _, e := range events {
+		entry := map[string]any{
+			"container": eventContainer(e),
+			"probe":     eventProbe(e),
+			"reason":    e.Reason,
+			"message":   e.Message,
+			"count":     e.Count,
+		}
+		if t := eventTime(e); !t.IsZero() {
+			entry["last_seen"] = t.UTC().Format("2006-01-02T15:04:05Z")
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+// eventContainer extracts the container from a kubelet event's field path,
+// e.g. 'spec.containers{app}'.
+func eventContainer(e corev1.Event) string {
+	path := e.InvolvedObject.FieldPath
+	start, end := strings.Index(path, "{"), strings.LastIndex(path, "}")
+	if start < 0 || end < start {
+		return ""
+	}
+	return path[start+1 : end]
+}
+
+// eventProbe extracts the probe kind from messages such as
+// 'Readiness probe failed: HTTP probe failed with statuscode: 503'.
+func eventProbe(e corev1.Event) string {
+	kind, _, ok := strings.Cut(e.Message, " probe ")
+	if !ok {
+		return ""
+	}
+	return strings.ToLower(kind)
+}
+
+// probeHints flags the probe misconfigurations that are easy to tell from
+// the spec and the events alone.
+func probeHints(c corev1.Container, failures []corev1.Event) []string {
+	var hints []string
+
+	declared := make(map[string]bool, len(c.Ports))
+	for _, p := range c.Ports {
+		if p.Name != "" {
+			declared[p.Name] = true
+		}
+	}
+
+	for kind, probe := range map[string]*corev1.Probe{
+		"liveness":  c.LivenessProbe,
+		"readiness": c.ReadinessProbe,
+		"startup":   c.StartupProbe,
+	} {
+		if probe == nil {
+			continue
+		}
+		if port, ok := probePort(probe.ProbeHandler); ok && port.Type == intstr.String && !declared[port.StrVal] {
+			hints = append(hints, fmt.Sprintf("container %s: %s probe uses named port %q, which the container does not declare", c.Name, kind, port.StrVal))
+		}
+		if probe.TimeoutSeconds <= 1 && probeTimedOut(failures, c.Name, kind) {
+			hints = append(hints, fmt.Sprintf("container %s: %s probe is timing out with timeout_seconds=1; the endpoint may just be slow, consider raising 'timeoutSeconds'", c.Name, kind))
+		}
+	}
+	sort.Strings(hints)
+	return hints
+}
+
+// probeTimedOut reports whether a container's probe failed on a timeout
+func probeTimedOut(failures []corev1.Event, container, kind string) bool {
+	for _, e := range failures {
+		if eventContainer(e) != container || eventProbe(e) != kind {
+			continue
+		}
+		msg := strings.ToLower(e.Message)
+		if strings.Contains(msg, "deadline exceeded") || strings.Contains(msg, "timeout") || strings.Contains(msg, "timed out") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// probedPod has an HTTP liveness probe on an undeclared named port and a
+// TCP readiness probe, with the kubelet reporting the container unready.
+func probedPod() *corev1.Pod {
+	pod := fakePod("default", "web", nil)
+	pod.Spec.Containers[0].Ports = []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}}
+	pod.Spec.Containers[0].LivenessProbe = &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{Path: "/healthz", Port: intstr.FromString("health")},
+		},
+		InitialDelaySeconds: 5,
+	}
+	pod.Spec.Containers[0].ReadinessProbe = &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(8080)},
+		},
+		PeriodSeconds:    5,
+		FailureThreshold: 6,
+	}
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name:         "app",
+		Ready:        false,
+		RestartCount: 4,
+		State:        corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+		LastTerminationState: corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 137},
+		},
+	}}
+	return pod
+}
+
+func probeEvent(name, message string, at time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		InvolvedObject: corev1.ObjectReference{
+			Kind: "Pod", Name: "web", Namespace: "default", FieldPath: "spec.containers{app}",
+		},
+		Type:          corev1.EventTypeWarning,
+		Reason:        "Unhealthy",
+		Message:       message,
+		Count:         3,
+		LastTimestamp: metav1.NewTime(at),
+	}
+}
+
+func TestGetProbeStatus(t *testing.T) {
+	now := time.Now()
+	pulled := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Namespace: "default", Name: "web.pulled"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web", Namespace: "default"},
+		Reason:         "Pulled",
+		LastTimestamp:  metav1.NewTime(now),
+	}
+	e := newFakeEnv(t,
+		probedPod(),
+		probeEvent("web.1", "Readiness probe failed: dial tcp 10.0.0.7:8080: connect: connection refused", now.Add(-5*time.Minute)),
+		probeEvent("web.2", `Liveness probe failed: Get "http://10.0.0.7:9090/healthz": context deadline exceeded (Client.Timeout exceeded while awaiting headers)`, now.Add(-time.Minute)),
+		pulled,
+	)
+
+	res, err := e.manager.handleGetProbeStatus(context.Background(), makeRequest(map[string]any{
+		"namespace": "default",
+		"name":      "web",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "get_probe_status")
+	requireContains(t, out, "target: HTTP GET :health/healthz", "expected liveness target")
+	requireContains(t, out, "target: :8080", "expected readiness target")
+	requireContains(t, out, "failure_threshold: 6", "expected configured threshold")
+	requireContains(t, out, "timeout_seconds: 1", "expected defaulted timeout")
+	requireContains(t, out, "restart_count: 4", "expected restart count")
+	requireContains(t, out, "last_termination: Error (exit code 137)", "expected last termination")
+	requireContains(t, out, `named port "health"`, "expected undeclared port hint")
+	requireContains(t, out, "liveness probe is timing out", "expected timeout hint")
+	if strings.Contains(out, "Pulled") {
+		t.Fatalf("non-probe events must be filtered out:\n%s", out)
+	}
+
+	res, err = e.manager.handleGetProbeStatus(context.Background(), makeRequest(map[string]any{
+		"namespace":      "default",
+		"name":           "web",
+		"yq_expressions": []any{".events[0].probe"},
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out = expectOK(t, res, "get_probe_status events")
+	requireContains(t, out, "liveness", "expected newest event first, attributed to its probe")
+}
+
+func TestGetProbeStatus_Errors(t *testing.T) {
+	e := newFakeEnv(t, probedPod())
+
+	res, err := e.manager.handleGetProbeStatus(context.Background(), makeRequest(map[string]any{
+		"namespace": "default",
+		"name":      "web",
+		"container": "sidecar",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	text := expectErr(t, res, "unknown container")
+	requireContains(t, text, "containers: app", "expected available containers")
+
+	e.provider.deniedNamespaces = []string{"default"}
+	res, err = e.manager.handleGetProbeStatus(context.Background(), makeRequest(map[string]any{
+		"namespace": "default",
+		"name":      "web",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	text = expectErr(t, res, "denied namespace")
+	requireContains(t, text, "namespace default is not allowed", "expected namespace error")
+}
@@ -19,14 +19,21 @@ package k8stools
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
+	"text/tabwriter"
 
 	"kubernetes-mcp/internal/authorization"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	authv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	authorizationv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
 )
 
 // metricsServerError converts an API error coming from the metrics API into
@@ -59,26 +66,34 @@ MCP server's own authorization layer (which is checked first, before the
 call ever reaches the cluster).
 
 Useful as a pre-flight check before destructive operations, or to debug
-"Forbidden" errors.`),
+"Forbidden" errors.
+
+'verbose=true' explains the decision: the RBAC rules of the identity that
+cover the resource (via a SelfSubjectRulesReview), why they do not grant
+the verb (no rule on the resource at all, other verbs only, other resource
+names only, or an explicit denial by a webhook that RBAC cannot override)
+and the rule a Role / ClusterRole would need to grant it.`),
 		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
 		mcp.WithString("verb", mcp.Required(), mcp.Description("RBAC verb to check. Standard values: 'get', 'list', 'watch', 'create', 'update', 'patch', 'delete', 'deletecollection'. Some resources accept extra verbs (e.g. 'use' on PodSecurityPolicies, 'bind' on ClusterRoles, 'impersonate' on Users).")),
 		mcp.WithString("group", mcp.Description("API group of the resource being checked. Empty string \"\" for the core API.")),
 		mcp.WithString("resource", mcp.Required(), mcp.Description("Resource name in the API sense: lowercase plural ('pods', 'deployments', 'secrets'). NOT the Kind. Subresources can be checked as 'pods/exec', 'pods/log', 'deployments/scale'.")),
 		mcp.WithString("name", mcp.Description("Optional resource instance name. When set, the check applies to that specific object; when empty, the check is for the resource type as a whole.")),
 		mcp.WithString("namespace", mcp.Description("Namespace where the check applies. Empty for cluster-scoped checks or for checks across all namespaces.")),
+		mcp.WithBoolean("verbose", mcp.Description("Explain the decision with the covering RBAC rules and, when denied, the rule needed to grant it. Defaults to false.")),
 	)
-	m.mcpServer.AddTool(tool, m.handleCheckPermission)
+	m.addTool(tool, m.handleCheckPermission)
 }
 
 func (m *Manager) handleCheckPermission(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
 
-	k8sContext := m.getContextParam(args)
+	k8sContext := m.getContextParam(ctx, args)
 	verb, _ := args["verb"].(string)
 	group, _ := args["group"].(string)
 	resource, _ := args["resource"].(string)
 	name, _ := args["name"].(string)
 	namespace, _ := args["namespace"].(string)
+	verbose, _ := args["verbose"].(bool)
 
 	// Split 'pods/exec' into ('pods', 'exec') so the SAR addresses the
 	// subresource correctly (the API expects the two parts separately).
@@ -149,9 +164,166 @@ func (m *Manager) handleCheckPermission(ctx context.Context, request mcp.CallToo
 		output += fmt.Sprintf("  Eval error:  %s\n", result.Status.EvaluationError)
 	}
 
+	if verbose {
+		output += "\n" + explainPermission(ctx, client.Clientset.AuthorizationV1(), review.Spec.ResourceAttributes, result.Status)
+	}
+
 	return successResult(output), nil
 }
 
+// explainPermission lists the RBAC rules of the caller that cover the
+// resource of a SelfSubjectAccessReview and says why they do or don't grant
+// it. The rules come from a SelfSubjectRulesReview, which is namespaced:
+// cluster-scoped checks are reviewed in 'default', where the ClusterRole
+// bindings of the caller apply as well.
+func explainPermission(ctx context.Context, client authorizationv1client.AuthorizationV1Interface,
+	attrs *authv1.ResourceAttributes, status authv1.SubjectAccessReviewStatus) string {
+
+	var sb strings.Builder
+	sb.WriteString("Details:\n")
+
+	if status.Denied {
+		sb.WriteString("  An authorizer ahead of RBAC (typically a webhook) denied the request explicitly;\n")
+		sb.WriteString("  no Role or ClusterRole can grant it. Check the webhook authorizer's policy.\n")
+		return sb.String()
+	}
+
+	namespace := attrs.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+	review, err := client.SelfSubjectRulesReviews().Create(ctx, &authv1.SelfSubjectRulesReview{
+		Spec: authv1.SelfSubjectRulesReviewSpec{Namespace: namespace},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		fmt.Fprintf(&sb, "  The RBAC rules could not be reviewed: %v\n", err)
+		return sb.String()
+	}
+
+	resource := attrs.Resource
+	if attrs.Subresource != "" {
+		resource += "/" + attrs.Subresource
+	}
+
+	// Rules on the resource, split by whether they grant this verb and name
+	var covering, granting []authv1.ResourceRule
+	for _, rule := range review.Status.ResourceRules {
+		if !ruleMatches(rule.APIGroups, attrs.Group) || !ruleMatchesResource(rule.Resources, resource) {
+			continue
+		}
+		covering = append(covering, rule)
+		if ruleMatches(rule.Verbs, attrs.Verb) && (len(rule.ResourceNames) == 0 || ruleMatches(rule.ResourceNames, attrs.Name)) {
+			granting = append(granting, rule)
+		}
+	}
+
+	if len(covering) == 0 {
+		fmt.Fprintf(&sb, "  No RBAC rule bound to this identity covers %s in group %q.\n", resource, attrs.Group)
+	} else {
+		fmt.Fprintf(&sb, "  RBAC rules covering %s:\n", resource)
+		for _, rule := range covering {
+			fmt.Fprintf(&sb, "    - %s\n", formatResourceRule(rule))
+		}
+	}
+
+	switch {
+	case status.Allowed:
+		if len(granting) == 0 {
+			sb.WriteString("  Allowed by an authorizer other than RBAC (no RBAC rule grants it).\n")
+		}
+	case len(granting) > 0:
+		// RBAC would allow it, so another authorizer or the rules review
+		// scope is the explanation.
+		sb.WriteString("  A rule above grants the verb, yet the request was not allowed: the rules review\n")
+		sb.WriteString("  may include bindings that do not apply to this exact request.\n")
+	default:
+		switch {
+		case len(covering) == 0:
+			sb.WriteString("  Reason: no Role or ClusterRole bound to this identity mentions the resource.\n")
+		case attrs.Name != "" && anyRuleGrantsVerb(covering, attrs.Verb):
+			fmt.Fprintf(&sb, "  Reason: the rules granting %q are restricted to other resourceNames.\n", attrs.Verb)
+		default:
+			fmt.Fprintf(&sb, "  Reason: the rules on the resource do not include the verb %q.\n", attrs.Verb)
+		}
+		sb.WriteString("  To grant it, bind a Role (namespaced) or ClusterRole with:\n")
+		fmt.Fprintf(&sb, "    - apiGroups: [%q]\n", attrs.Group)
+		fmt.Fprintf(&sb, "      resources: [%q]\n", resource)
+		if attrs.Name != "" {
+			fmt.Fprintf(&sb, "      resourceNames: [%q]\n", attrs.Name)
+		}
+		fmt.Fprintf(&sb, "      verbs: [%q]\n", attrs.Verb)
+	}
+
+	if review.Status.Incomplete {
+		sb.WriteString("  Note: the rules review is incomplete (an authorizer cannot list its rules)")
+		if review.Status.EvaluationError != "" {
+			fmt.Fprintf(&sb, ": %s", review.Status.EvaluationError)
+		}
+		sb.WriteString(".\n")
+	}
+
+	return sb.String()
+}
+
+// ruleMatches reports whether a rule field (verbs, apiGroups,
+// resourceNames) includes 'value', honouring the '*' wildcard.
+func ruleMatches(values []string, value string) bool {
+	for _, v := range values {
+		if v == "*" || v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleMatchesResource is ruleMatches for resources, where 'pods/*' covers
+// every subresource of pods and '*/scale' a subresource of every resource.
+func ruleMatchesResource(resources []string, resource string) bool {
+	if ruleMatches(resources, resource) {
+		return true
+	}
+	parent, sub, found := strings.Cut(resource, "/")
+	if !found {
+		return false
+	}
+	for _, r := range resources {
+		if r == parent+"/*" || r == "*/"+sub {
+			return true
+		}
+	}
+	return false
+}
+
+// anyRuleGrantsVerb reports whether any of 'rules' includes 'verb'
+func anyRuleGrantsVerb(rules []authv1.ResourceRule, verb string) bool {
+	for _, rule := range rules {
+		if ruleMatches(rule.Verbs, verb) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatResourceRule renders a rule on one line, e.g.
+// 'verbs [get list] on [pods] (apiGroups [""])'.
+func formatResourceRule(rule authv1.ResourceRule) string {
+	out := fmt.Sprintf("verbs [%s] on [%s] (apiGroups [%s])",
+		strings.Join(rule.Verbs, " "), strings.Join(rule.Resources, " "), strings.Join(quoteAll(rule.APIGroups), " "))
+	if len(rule.ResourceNames) > 0 {
+		out += fmt.Sprintf(", resourceNames [%s]", strings.Join(rule.ResourceNames, " "))
+	}
+	return out
+}
+
+// quoteAll quotes every value, so the core group shows as ""
+func quoteAll(values []string) []string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = fmt.Sprintf("%q", v)
+	}
+	return out
+}
+
 func (m *Manager) registerGetPodMetrics() {
 	tool := mcp.NewTool(m.toolName("get_pod_metrics"),
 		mcp.WithDescription(`Return live CPU and memory usage for one Pod or many Pods.
@@ -164,24 +336,39 @@ Selection rules:
     defaulting to 'default' if empty).
   - 'name' empty + 'namespace' set: lists metrics for all Pods in that
     namespace, optionally filtered by 'label_selector'.
-  - both empty: lists Pod metrics across all namespaces (subject to RBAC).`),
+  - both empty: lists Pod metrics across all namespaces (subject to RBAC).
+
+Every Pod gets a computed 'total' (CPU in millicores, memory in MiB) summing
+its containers. For a "top pods" view, pass 'sort_by' ('cpu' or 'memory')
+and 'top' to keep only the N highest consumers, and 'show_containers=false'
+to drop the per-container breakdown.`),
 		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
 		mcp.WithString("namespace", mcp.Description("Namespace to scope the query to. See selection rules in the description.")),
 		mcp.WithString("name", mcp.Description("Specific Pod name. If set, the response is a single PodMetrics object instead of a list.")),
 		mcp.WithString("label_selector", mcp.Description("Kubernetes label selector. Only applied to the list flavours (when 'name' is empty).")),
-		mcp.WithArray("yq_expressions", mcp.Description("Optional yq expressions applied to the YAML output. List flavour returns a PodMetricsList (use '.items[]'); single flavour returns a PodMetrics object. Examples: '.items[] | {pod: .metadata.name, cpu: .containers[0].usage.cpu}' (compact), '.items[].metadata.name' (just names).")),
+		mcp.WithString("sort_by", mcp.Enum("cpu", "memory"), mcp.Description("Sort the list flavours by the Pod total, highest first. Quantities are compared as quantities, so 1500m > 900m and 1Gi > 900Mi.")),
+		mcp.WithNumber("top", mcp.Description("Keep only the first N Pods after sorting. Integer >= 1. Sorts by 'cpu' when 'sort_by' is omitted.")),
+		mcp.WithBoolean("show_containers", mcp.Description("Include the per-container usage under 'containers'. Defaults to true; false leaves only the Pod 'total'.")),
+		mcp.WithArray("yq_expressions", mcp.Description("Optional yq expressions applied to the YAML output. List flavour returns a PodMetricsList (use '.items[]'); single flavour returns a PodMetrics object. Examples: '.items[] | {pod: .metadata.name, cpu: .total.cpu}' (compact), '.items[].metadata.name' (just names).")),
+		jqExpressionsParam(),
+		outputFormatParam(),
 	)
-	m.mcpServer.AddTool(tool, m.handleGetPodMetrics)
+	m.addTool(tool, m.handleGetPodMetrics)
 }
 
 func (m *Manager) handleGetPodMetrics(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
 
-	k8sContext := m.getContextParam(args)
+	k8sContext := m.getContextParam(ctx, args)
 	namespace, _ := args["namespace"].(string)
 	name, _ := args["name"].(string)
 	labelSelector, _ := args["label_selector"].(string)
 
+	view, err := podMetricsViewFromArgs(args)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
 	// Check authorization (real K8s resource: PodMetrics, surfaced under
 	// metrics.k8s.io/v1beta1 with the standard 'pods' plural — same name
 	// 'kubectl top pod' targets).
@@ -213,17 +400,20 @@ func (m *Manager) handleGetPodMetrics(ctx context.Context, request mcp.CallToolR
 		if namespace == "" {
 			namespace = "default"
 		}
-		result, err = client.MetricsClient.MetricsV1beta1().PodMetricses(namespace).Get(ctx, name, metav1.GetOptions{})
-	} else if namespace != "" {
-		// List pod metrics in namespace
-		result, err = client.MetricsClient.MetricsV1beta1().PodMetricses(namespace).List(ctx, metav1.ListOptions{
-			LabelSelector: labelSelector,
-		})
+		var podMetrics *metricsv1beta1.PodMetrics
+		podMetrics, err = client.MetricsClient.MetricsV1beta1().PodMetricses(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err == nil {
+			result, err = view.object(*podMetrics)
+		}
 	} else {
-		// List all pod metrics
-		result, err = client.MetricsClient.MetricsV1beta1().PodMetricses("").List(ctx, metav1.ListOptions{
+		// List pod metrics in the namespace, or in all of them when empty
+		var list *metricsv1beta1.PodMetricsList
+		list, err = client.MetricsClient.MetricsV1beta1().PodMetricses(namespace).List(ctx, metav1.ListOptions{
 			LabelSelector: labelSelector,
 		})
+		if err == nil {
+			result, err = view.list(list.Items)
+		}
 	}
 
 	if err != nil {
@@ -235,8 +425,8 @@ func (m *Manager) handleGetPodMetrics(ctx context.Context, request mcp.CallToolR
 		return errorResult(err), nil
 	}
 
-	// Apply yq expressions
-	finalOutput, err := m.applyYQExpressions(yamlOutput, args)
+	// Apply filters and the requested output format
+	finalOutput, err := m.formatOutput(yamlOutput, args, "PodMetrics")
 	if err != nil {
 		return errorResult(err), nil
 	}
@@ -244,6 +434,97 @@ func (m *Manager) handleGetPodMetrics(ctx context.Context, request mcp.CallToolR
 	return successResult(finalOutput), nil
 }
 
+// podMetricsView is how get_pod_metrics arranges its result
+type podMetricsView struct {
+	sortBy         corev1.ResourceName
+	top            int
+	showContainers bool
+}
+
+func podMetricsViewFromArgs(args map[string]any) (podMetricsView, error) {
+	view := podMetricsView{showContainers: true}
+	if v, ok := args["show_containers"].(bool); ok {
+		view.showContainers = v
+	}
+	switch sortBy, _ := args["sort_by"].(string); sortBy {
+	case "":
+	case "cpu", "memory":
+		view.sortBy = corev1.ResourceName(sortBy)
+	default:
+		return view, fmt.Errorf("sort_by must be 'cpu' or 'memory', got %q", sortBy)
+	}
+	if v, ok := args["top"].(float64); ok {
+		if v < 1 {
+			return view, fmt.Errorf("top must be >= 1, got %v", v)
+		}
+		view.top = int(v)
+		if view.sortBy == "" {
+			view.sortBy = corev1.ResourceCPU
+		}
+	}
+	return view, nil
+}
+
+// list sorts and trims the Pods, then renders them as a PodMetricsList
+func (v podMetricsView) list(items []metricsv1beta1.PodMetrics) (map[string]any, error) {
+	if v.sortBy != "" {
+		sort.SliceStable(items, func(i, j int) bool {
+			a, b := podUsageTotal(items[i]), podUsageTotal(items[j])
+			return a.Name(v.sortBy, resource.DecimalSI).Cmp(*b.Name(v.sortBy, resource.DecimalSI)) > 0
+		})
+	}
+	if v.top > 0 && len(items) > v.top {
+		items = items[:v.top]
+	}
+
+	out := make([]any, 0, len(items))
+	for _, item := range items {
+		obj, err := v.object(item)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, obj)
+	}
+	return map[string]any{
+		"apiVersion": metricsv1beta1.SchemeGroupVersion.String(),
+		"kind":       "PodMetricsList",
+		"items":      out,
+	}, nil
+}
+
+// object renders one Pod's metrics with its computed total
+func (v podMetricsView) object(podMetrics metricsv1beta1.PodMetrics) (map[string]any, error) {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&podMetrics)
+	if err != nil {
+		return nil, err
+	}
+	obj["apiVersion"] = metricsv1beta1.SchemeGroupVersion.String()
+	obj["kind"] = "PodMetrics"
+
+	total := podUsageTotal(podMetrics)
+	obj["total"] = map[string]any{
+		"cpu":    formatUsage("cpu", *total.Cpu()),
+		"memory": formatUsage("memory", *total.Memory()),
+	}
+	if !v.showContainers {
+		delete(obj, "containers")
+	}
+	return obj, nil
+}
+
+// podUsageTotal sums the usage of every container of a Pod
+func podUsageTotal(podMetrics metricsv1beta1.PodMetrics) corev1.ResourceList {
+	total := corev1.ResourceList{}
+	for _, c := range podMetrics.Containers {
+		for name, q := range c.Usage {
+			sum := total[name]
+			sum.Add(q)
+			total[name] = sum
+		}
+	}
+	return total
+}
+
 func (m *Manager) registerGetNodeMetrics() {
 	tool := mcp.NewTool(m.toolName("get_node_metrics"),
 		mcp.WithDescription(`Return live CPU and memory usage for one Node or all Nodes.
@@ -258,14 +539,16 @@ tool returns a clear "metrics-server is not available" error.
 		mcp.WithString("name", mcp.Description("Specific Node name. If set, the response is a single NodeMetrics object instead of a list.")),
 		mcp.WithString("label_selector", mcp.Description("Kubernetes label selector. Only applied to the list flavour (when 'name' is empty). Examples: 'node-role.kubernetes.io/control-plane=', 'topology.kubernetes.io/zone=eu-west-1a'.")),
 		mcp.WithArray("yq_expressions", mcp.Description("Optional yq expressions applied to the YAML output. List flavour returns a NodeMetricsList (use '.items[]'); single flavour returns a NodeMetrics object. Examples: '.items[] | {name: .metadata.name, cpu: .usage.cpu, memory: .usage.memory}' (compact), '.items[].metadata.name' (just names).")),
+		jqExpressionsParam(),
+		outputFormatParam(),
 	)
-	m.mcpServer.AddTool(tool, m.handleGetNodeMetrics)
+	m.addTool(tool, m.handleGetNodeMetrics)
 }
 
 func (m *Manager) handleGetNodeMetrics(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
 
-	k8sContext := m.getContextParam(args)
+	k8sContext := m.getContextParam(ctx, args)
 	name, _ := args["name"].(string)
 	labelSelector, _ := args["label_selector"].(string)
 
@@ -308,11 +591,153 @@ func (m *Manager) handleGetNodeMetrics(ctx context.Context, request mcp.CallTool
 		return errorResult(err), nil
 	}
 
-	// Apply yq expressions
-	finalOutput, err := m.applyYQExpressions(yamlOutput, args)
+	// Apply filters and the requested output format
+	finalOutput, err := m.formatOutput(yamlOutput, args, "NodeMetrics")
 	if err != nil {
 		return errorResult(err), nil
 	}
 
 	return successResult(finalOutput), nil
 }
+
+// nodeUsage is one row of top_nodes
+type nodeUsage struct {
+	name       string
+	hasMetrics bool
+	cpu        resource.Quantity
+	memory     resource.Quantity
+	cpuPct     int64
+	memoryPct  int64
+}
+
+func (m *Manager) registerTopNodes() {
+	tool := mcp.NewTool(m.toolName("top_nodes"),
+		mcp.WithDescription(`Show each Node's CPU and memory usage against its allocatable capacity,
+like 'kubectl top nodes': NODE, CPU(cores), CPU%, MEMORY, MEMORY%.
+
+Joins the Node metrics (metrics-server) with each Node's
+'status.allocatable', so the percentages are of what Pods can actually use,
+not of the raw machine. Nodes without metrics yet are listed as <unknown>.
+
+Requires metrics-server. Use it for "which nodes are full?" before
+scheduling, scaling or draining.`),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("label_selector", mcp.Description("Kubernetes label selector on the Nodes. Examples: 'node-role.kubernetes.io/control-plane=', 'topology.kubernetes.io/zone=eu-west-1a'.")),
+		mcp.WithString("sort_by", mcp.Enum("cpu", "memory"), mcp.Description("Sort by CPU% or memory% utilization, highest first. Defaults to sorting by Node name.")),
+	)
+	m.addTool(tool, m.handleTopNodes)
+}
+
+func (m *Manager) handleTopNodes(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	labelSelector, _ := args["label_selector"].(string)
+	sortBy, _ := args["sort_by"].(string)
+	if sortBy != "" && sortBy != "cpu" && sortBy != "memory" {
+		return errorResult(fmt.Errorf("sort_by must be 'cpu' or 'memory', got %q", sortBy)), nil
+	}
+
+	// Check authorization: NodeMetrics for the usage, Nodes for allocatable
+	if err := m.checkAuthorization(request, "top_nodes", k8sContext, "", authorization.ResourceInfo{
+		Group:    "metrics.k8s.io",
+		Version:  "v1beta1",
+		Resource: "nodes",
+	}); err != nil {
+		return errorResult(err), nil
+	}
+	if err := m.checkAuthorization(request, "top_nodes", k8sContext, "", authorization.ResourceInfo{
+		Group:    "",
+		Version:  "v1",
+		Resource: "nodes",
+	}); err != nil {
+		return errorResult(err), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	if client.MetricsClient == nil {
+		return errorResult(fmt.Errorf("metrics-server is not available in this cluster")), nil
+	}
+
+	listOpts := metav1.ListOptions{LabelSelector: labelSelector}
+	nodes, err := client.Clientset.CoreV1().Nodes().List(ctx, listOpts)
+	if err != nil {
+		return errorResult(err), nil
+	}
+	metrics, err := client.MetricsClient.MetricsV1beta1().NodeMetricses().List(ctx, listOpts)
+	if err != nil {
+		return errorResult(metricsServerError(err)), nil
+	}
+
+	if len(nodes.Items) == 0 {
+		return successResult("No nodes found."), nil
+	}
+	rows := nodeUsageRows(nodes.Items, metrics.Items)
+	sortNodeUsage(rows, sortBy)
+	return successResult(renderNodeUsage(rows)), nil
+}
+
+// nodeUsageRows matches the metrics to the Nodes by name and computes the
+// utilization of each Node's allocatable capacity
+func nodeUsageRows(nodes []corev1.Node, metrics []metricsv1beta1.NodeMetrics) []nodeUsage {
+	byName := make(map[string]metricsv1beta1.NodeMetrics, len(metrics))
+	for _, nm := range metrics {
+		byName[nm.Name] = nm
+	}
+
+	rows := make([]nodeUsage, 0, len(nodes))
+	for _, node := range nodes {
+		row := nodeUsage{name: node.Name}
+		if nm, ok := byName[node.Name]; ok {
+			row.hasMetrics = true
+			row.cpu = *nm.Usage.Cpu()
+			row.memory = *nm.Usage.Memory()
+			if alloc := node.Status.Allocatable.Cpu().MilliValue(); alloc > 0 {
+				row.cpuPct = row.cpu.MilliValue() * 100 / alloc
+			}
+			if alloc := node.Status.Allocatable.Memory().Value(); alloc > 0 {
+				row.memoryPct = row.memory.Value() * 100 / alloc
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// sortNodeUsage orders by the chosen utilization, highest first, Nodes
+// without metrics last; by name otherwise
+func sortNodeUsage(rows []nodeUsage, sortBy string) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if sortBy == "" {
+			return a.name < b.name
+		}
+		if a.hasMetrics != b.hasMetrics {
+			return a.hasMetrics
+		}
+		if sortBy == "memory" {
+			return a.memoryPct > b.memoryPct
+		}
+		return a.cpuPct > b.cpuPct
+	})
+}
+
+func renderNodeUsage(rows []nodeUsage) string {
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NODE\tCPU(cores)\tCPU%\tMEMORY\tMEMORY%")
+	for _, row := range rows {
+		if !row.hasMetrics {
+			fmt.Fprintf(w, "%s\t<unknown>\t<unknown>\t<unknown>\t<unknown>\n", row.name)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d%%\t%s\t%d%%\n", row.name,
+			formatUsage("cpu", row.cpu), row.cpuPct, formatUsage("memory", row.memory), row.memoryPct)
+	}
+	w.Flush()
+	return strings.TrimRight(sb.String(), "\n")
+}
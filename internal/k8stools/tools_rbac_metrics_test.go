@@ -0,0 +1,272 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	authv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+)
+
+// reviewEnv answers access reviews with 'status' and rules reviews with
+// 'rules', as the API server would for the kubeconfig identity.
+func reviewEnv(t *testing.T, status authv1.SubjectAccessReviewStatus, rules []authv1.ResourceRule) *fakeEnv {
+	t.Helper()
+	e := newFakeEnv(t)
+	e.clientset.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authv1.SelfSubjectAccessReview)
+		review.Status = status
+		return true, review, nil
+	})
+	e.clientset.PrependReactor("create", "selfsubjectrulesreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authv1.SelfSubjectRulesReview)
+		review.Status.ResourceRules = rules
+		return true, review, nil
+	})
+	return e
+}
+
+func TestCheckPermission_Concise(t *testing.T) {
+	e := reviewEnv(t, authv1.SubjectAccessReviewStatus{}, nil)
+
+	res, err := e.manager.handleCheckPermission(context.Background(), makeRequest(map[string]any{
+		"verb":      "delete",
+		"resource":  "pods",
+		"namespace": "default",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "check_permission")
+	requireContains(t, out, "Permission check: denied", "expected the decision")
+	if strings.Contains(out, "Details:") {
+		t.Fatalf("details must only be reported with verbose=true:\n%s", out)
+	}
+}
+
+func TestCheckPermission_Verbose(t *testing.T) {
+	readOnly := []authv1.ResourceRule{
+		{Verbs: []string{"get", "list"}, APIGroups: []string{""}, Resources: []string{"pods"}},
+		{Verbs: []string{"*"}, APIGroups: []string{"apps"}, Resources: []string{"deployments"}},
+	}
+
+	tests := []struct {
+		name   string
+		status authv1.SubjectAccessReviewStatus
+		rules  []authv1.ResourceRule
+		args   map[string]any
+		want   []string
+	}{
+		{
+			name:  "missing verb",
+			rules: readOnly,
+			args:  map[string]any{"verb": "delete", "resource": "pods"},
+			want: []string{
+				`verbs [get list] on [pods] (apiGroups [""])`,
+				`do not include the verb "delete"`,
+				`resources: ["pods"]`,
+				`verbs: ["delete"]`,
+			},
+		},
+		{
+			name:  "no rule on the resource",
+			rules: readOnly,
+			args:  map[string]any{"verb": "create", "resource": "pods/exec"},
+			want:  []string{`No RBAC rule bound to this identity covers pods/exec`, `resources: ["pods/exec"]`},
+		},
+		{
+			name: "other resource names",
+			rules: []authv1.ResourceRule{
+				{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{"app-config"}},
+			},
+			args: map[string]any{"verb": "get", "resource": "secrets", "name": "db-password"},
+			want: []string{`restricted to other resourceNames`, `resourceNames: ["db-password"]`},
+		},
+		{
+			name:   "explicit denial",
+			status: authv1.SubjectAccessReviewStatus{Denied: true, Reason: "blocked by policy"},
+			args:   map[string]any{"verb": "get", "resource": "pods"},
+			want:   []string{"denied (explicit)", "no Role or ClusterRole can grant it"},
+		},
+		{
+			name:   "allowed",
+			status: authv1.SubjectAccessReviewStatus{Allowed: true, Reason: `RBAC: allowed by ClusterRoleBinding "ops"`},
+			rules:  readOnly,
+			args:   map[string]any{"verb": "patch", "group": "apps", "resource": "deployments"},
+			want:   []string{`allowed by ClusterRoleBinding "ops"`, "verbs [*] on [deployments]"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := reviewEnv(t, tt.status, tt.rules)
+			tt.args["verbose"] = true
+			res, err := e.manager.handleCheckPermission(context.Background(), makeRequest(tt.args))
+			if err != nil {
+				t.Fatalf("go-error: %v", err)
+			}
+			out := expectOK(t, res, tt.name)
+			for _, want := range tt.want {
+				requireContains(t, out, want, "unexpected explanation")
+			}
+		})
+	}
+}
+
+func TestRuleMatchesResource(t *testing.T) {
+	tests := []struct {
+		rule     string
+		resource string
+		want     bool
+	}{
+		{"pods", "pods", true},
+		{"*", "pods/exec", true},
+		{"pods/*", "pods/exec", true},
+		{"*/scale", "deployments/scale", true},
+		{"pods", "pods/exec", false},
+		{"pods/log", "pods/exec", false},
+	}
+	for _, tt := range tests {
+		if got := ruleMatchesResource([]string{tt.rule}, tt.resource); got != tt.want {
+			t.Errorf("ruleMatchesResource(%q, %q) = %v, want %v", tt.rule, tt.resource, got, tt.want)
+		}
+	}
+}
+
+func podMetrics(name string, usage ...corev1.ResourceList) *metricsv1beta1.PodMetrics {
+	pm := &metricsv1beta1.PodMetrics{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name}}
+	for i, u := range usage {
+		pm.Containers = append(pm.Containers, metricsv1beta1.ContainerMetrics{Name: fmt.Sprintf("c%d", i), Usage: u})
+	}
+	return pm
+}
+
+func usage(cpu, memory string) corev1.ResourceList {
+	return corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu), corev1.ResourceMemory: resource.MustParse(memory)}
+}
+
+func TestGetPodMetrics_SortAndTop(t *testing.T) {
+	e := newFakeEnv(t)
+	metricsClient := metricsfake.NewSimpleClientset()
+	// The fake guesses 'podmetricses' from the kind; the client asks for 'pods'
+	podsGVR := metricsv1beta1.SchemeGroupVersion.WithResource("pods")
+	for _, pm := range []*metricsv1beta1.PodMetrics{
+		podMetrics("small", usage("900m", "1Gi")),
+		podMetrics("large", usage("1", "512Mi"), usage("500m", "100Mi")),
+		podMetrics("medium", usage("1200m", "900Mi")),
+	} {
+		if err := metricsClient.Tracker().Create(podsGVR, pm, pm.Namespace); err != nil {
+			t.Fatalf("seed metrics: %v", err)
+		}
+	}
+	e.provider.client.MetricsClient = metricsClient
+
+	get := func(extra map[string]any) string {
+		t.Helper()
+		args := map[string]any{"namespace": "default", "yq_expressions": []any{`.items[] | .metadata.name + " " + .total.cpu + " " + .total.memory`}}
+		for k, v := range extra {
+			args[k] = v
+		}
+		res, err := e.manager.handleGetPodMetrics(context.Background(), makeRequest(args))
+		if err != nil {
+			t.Fatalf("go-error: %v", err)
+		}
+		return strings.TrimSpace(expectOK(t, res, "get_pod_metrics"))
+	}
+
+	// 1500m > 1200m > 900m, compared as quantities
+	if out := get(map[string]any{"sort_by": "cpu"}); out != "large 1500m 612Mi\nmedium 1200m 900Mi\nsmall 900m 1024Mi" {
+		t.Fatalf("unexpected cpu order:\n%s", out)
+	}
+	// 1Gi > 900Mi > 612Mi
+	if out := get(map[string]any{"sort_by": "memory", "top": float64(2)}); out != "small 900m 1024Mi\nmedium 1200m 900Mi" {
+		t.Fatalf("unexpected memory top 2:\n%s", out)
+	}
+
+	res, _ := e.manager.handleGetPodMetrics(context.Background(), makeRequest(map[string]any{
+		"namespace": "default", "top": float64(1), "show_containers": false,
+	}))
+	out := expectOK(t, res, "collapsed containers")
+	requireContains(t, out, "name: large", "expected the top cpu consumer")
+	if strings.Contains(out, "containers:") || strings.Contains(out, "name: medium") {
+		t.Fatalf("expected one Pod without the container breakdown:\n%s", out)
+	}
+
+	res, _ = e.manager.handleGetPodMetrics(context.Background(), makeRequest(map[string]any{"sort_by": "disk"}))
+	requireContains(t, expectErr(t, res, "bad sort_by"), "sort_by must be 'cpu' or 'memory'", "expected the sort_by error")
+}
+
+func TestTopNodes(t *testing.T) {
+	node := func(name, cpu, memory string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status:     corev1.NodeStatus{Allocatable: usage(cpu, memory)},
+		}
+	}
+	e := newFakeEnv(t, node("node-a", "4", "8Gi"), node("node-b", "2", "4Gi"), node("node-c", "2", "4Gi"))
+
+	metricsClient := metricsfake.NewSimpleClientset()
+	nodesGVR := metricsv1beta1.SchemeGroupVersion.WithResource("nodes")
+	for name, u := range map[string]corev1.ResourceList{
+		"node-a": usage("1", "6Gi"),     // 25%, 75%
+		"node-b": usage("1500m", "1Gi"), // 75%, 25%
+	} {
+		nm := &metricsv1beta1.NodeMetrics{ObjectMeta: metav1.ObjectMeta{Name: name}, Usage: u}
+		if err := metricsClient.Tracker().Create(nodesGVR, nm, ""); err != nil {
+			t.Fatalf("seed metrics: %v", err)
+		}
+	}
+	e.provider.client.MetricsClient = metricsClient
+
+	top := func(sortBy string) []string {
+		t.Helper()
+		res, err := e.manager.handleTopNodes(context.Background(), makeRequest(map[string]any{"sort_by": sortBy}))
+		if err != nil {
+			t.Fatalf("go-error: %v", err)
+		}
+		var rows []string
+		for _, line := range strings.Split(expectOK(t, res, "top_nodes"), "\n") {
+			rows = append(rows, strings.Join(strings.Fields(line), " "))
+		}
+		return rows
+	}
+
+	want := []string{
+		"NODE CPU(cores) CPU% MEMORY MEMORY%",
+		"node-b 1500m 75% 1024Mi 25%",
+		"node-a 1000m 25% 6144Mi 75%",
+		"node-c <unknown> <unknown> <unknown> <unknown>",
+	}
+	if got := top("cpu"); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected cpu order:\n%s", strings.Join(got, "\n"))
+	}
+	if got := top("memory"); got[1] != want[2] || got[3] != want[3] {
+		t.Fatalf("unexpected memory order:\n%s", strings.Join(got, "\n"))
+	}
+	if got := top(""); got[1] != want[2] {
+		t.Fatalf("expected name order by default:\n%s", strings.Join(got, "\n"))
+	}
+}
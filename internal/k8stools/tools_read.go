@@ -19,11 +19,19 @@ package k8stools
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 
 	"kubernetes-mcp/internal/authorization"
+	"kubernetes-mcp/internal/kubernetes"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 func (m *Manager) registerGetResource() {
@@ -39,25 +47,37 @@ Kubernetes API expects. The plural lowercase form ('pods', 'deployments',
 'ingresses', 'storageclasses', ...) is required, NOT the Kind.`),
 		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context (see 'get_current_context').")),
 		mcp.WithString("group", mcp.Description("API group. Empty string \"\" for the core API ('pods', 'configmaps', ...). Examples: 'apps', 'batch', 'networking.k8s.io'.")),
-		mcp.WithString("version", mcp.Required(), mcp.Description("API version, e.g. 'v1', 'v1beta1', 'v2'.")),
-		mcp.WithString("resource", mcp.Required(), mcp.Description("Resource name in the API sense: lowercase plural ('pods', 'deployments', 'ingresses', 'networkpolicies', 'storageclasses'). NOT the Kind ('Pod', 'Deployment', ...). Run 'list_api_resources' if unsure.")),
+		mcp.WithString("version", mcp.Description("API version, e.g. 'v1', 'v1beta1', 'v2'. If empty or not served for the group, the preferred version is used (kubernetes.tools.preferred_versions, else discovery).")),
+		mcp.WithString("resource", mcp.Required(), mcp.Description("Resource name in the API sense: lowercase plural ('pods', 'deployments', 'ingresses', 'networkpolicies', 'storageclasses'). NOT the Kind ('Pod', 'Deployment', ...). Run 'list_api_resources' if unsure. Short names are accepted ('po', 'deploy', 'sts', ...).")),
 		mcp.WithString("name", mcp.Required(), mcp.Description("Name of the specific resource instance to fetch.")),
 		mcp.WithString("namespace", mcp.Description("Namespace where the resource lives. Required for namespaced resources; ignored for cluster-scoped resources (Nodes, Namespaces, StorageClasses, ...).")),
+		mcp.WithBoolean("resolve_owners", mcp.Description("If true, follow metadata.ownerReferences up to the top-level owner and prepend a readable chain to the output, e.g. '# Owned by: Deployment/web → ReplicaSet/web-7d4b9c'. Answers 'what created this?' in one call.")),
+		mcp.WithBoolean("metadata_only", mcp.Description("If true, the API server returns only the object's metadata (name, labels, annotations, owners, ...), not its spec or status. Cheap for large objects.")),
+		mcp.WithArray("fields", mcp.Description(fieldsParamDescription)),
 		mcp.WithArray("yq_expressions", mcp.Description("Optional yq expressions (see https://mikefarah.gitbook.io/yq) applied in order to filter or transform the YAML output. Useful to keep the response small. Examples: '.metadata.name' (just the name), '.spec.containers[].image' (image list), '.status.podIP' (IP address), '{name: .metadata.name, ip: .status.podIP}' (custom shape).")),
+		jqExpressionsParam(),
+		outputFormatParam(),
 	)
-	m.mcpServer.AddTool(tool, m.handleGetResource)
+	m.addTool(tool, m.handleGetResource)
 }
 
 func (m *Manager) handleGetResource(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
 
-	k8sContext := m.getContextParam(args)
+	k8sContext := m.getContextParam(ctx, args)
 	name, _ := args["name"].(string)
 	namespace, _ := args["namespace"].(string)
-	gvr := gvrFromArgs(args)
+	gvr, err := m.resolveGVRVersion(k8sContext, m.expandShortName(k8sContext, gvrFromArgs(args)))
+	if err != nil {
+		return errorResult(err), nil
+	}
 	if err := validateGVR(gvr); err != nil {
 		return errorResult(err), nil
 	}
+	fields, metadataOnly, err := projectionFromArgs(args)
+	if err != nil {
+		return errorResult(err), nil
+	}
 
 	// Check authorization
 	if err := m.checkAuthorization(request, "get_resource", k8sContext, namespace, authorization.ResourceInfo{
@@ -79,10 +99,13 @@ func (m *Manager) handleGetResource(ctx context.Context, request mcp.CallToolReq
 		return errorResult(err), nil
 	}
 
-	var result any
-	if namespace != "" {
+	var result *unstructured.Unstructured
+	switch {
+	case metadataOnly && client.MetadataClient != nil:
+		result, err = getObjectMetadata(ctx, client, gvr, namespace, name)
+	case namespace != "":
 		result, err = client.DynamicClient.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
-	} else {
+	default:
 		result, err = client.DynamicClient.Resource(gvr).Get(ctx, name, metav1.GetOptions{})
 	}
 
@@ -90,21 +113,129 @@ func (m *Manager) handleGetResource(ctx context.Context, request mcp.CallToolReq
 		return errorResult(err), nil
 	}
 
-	yamlOutput, err := objectToYAML(result)
+	yamlOutput, err := objectToYAML(projectFields(result.Object, "", fields))
 	if err != nil {
 		return errorResult(err), nil
 	}
 
-	// Apply yq expressions
-	finalOutput, err := m.applyYQExpressions(yamlOutput, args)
+	// Apply filters and the requested output format
+	finalOutput, err := m.formatOutput(yamlOutput, args, "")
 	if err != nil {
 		return errorResult(err), nil
 	}
 
+	if resolveOwners, _ := args["resolve_owners"].(bool); resolveOwners {
+		finalOutput = m.ownerChain(ctx, request, "get_resource", k8sContext, client, result) + "\n" + finalOutput
+	}
+
 	return successResult(finalOutput), nil
 }
 
+func (m *Manager) registerResourceExists() {
+	tool := mcp.NewTool(m.toolName("resource_exists"),
+		mcp.WithDescription(`Check whether a single resource exists, without fetching the whole object.
+
+Returns 'exists: true' with its 'resource_version' (and 'deleting: true'
+while it is being deleted), or 'exists: false' when the API server answers
+NotFound. Any other failure (forbidden, unknown resource type, unreachable
+cluster) is reported as an error, so 'exists: false' can be trusted for
+branching.`),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("group", mcp.Description("API group. Empty string \"\" for the core API.")),
+		mcp.WithString("version", mcp.Description("API version, e.g. 'v1'. If empty or not served for the group, the preferred version is used (kubernetes.tools.preferred_versions, else discovery).")),
+		mcp.WithString("resource", mcp.Required(), mcp.Description("Resource name in the API sense: lowercase plural ('pods', 'deployments'). NOT the Kind. Short names are accepted ('po', 'deploy', 'sts', ...).")),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Name of the resource instance to check.")),
+		mcp.WithString("namespace", mcp.Description("Namespace where the resource lives. Required for namespaced resources; ignored for cluster-scoped resources.")),
+	)
+	m.addTool(tool, m.handleResourceExists)
+}
+
+func (m *Manager) handleResourceExists(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	name, _ := args["name"].(string)
+	namespace, _ := args["namespace"].(string)
+	gvr, err := m.resolveGVRVersion(k8sContext, m.expandShortName(k8sContext, gvrFromArgs(args)))
+	if err != nil {
+		return errorResult(err), nil
+	}
+	if err := validateGVR(gvr); err != nil {
+		return errorResult(err), nil
+	}
+	if name == "" {
+		return errorResult(fmt.Errorf("name is required")), nil
+	}
+
+	// Check authorization
+	if err := m.checkAuthorization(request, "resource_exists", k8sContext, namespace, authorization.ResourceInfo{
+		Group:    gvr.Group,
+		Version:  gvr.Version,
+		Resource: gvr.Resource,
+		Name:     name,
+	}); err != nil {
+		return errorResult(err), nil
+	}
+
+	// Check namespace access
+	if namespace != "" && !m.clientManager.IsNamespaceAllowed(k8sContext, namespace) {
+		return errorResult(fmt.Errorf("namespace %s is not allowed in context %s", namespace, k8sContext)), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	var result *unstructured.Unstructured
+	if namespace != "" {
+		result, err = client.DynamicClient.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	} else {
+		result, err = client.DynamicClient.Resource(gvr).Get(ctx, name, metav1.GetOptions{})
+	}
+
+	output := map[string]any{"exists": false}
+	switch {
+	case apierrors.IsNotFound(err):
+		// Reported as a normal answer, not an error
+	case err != nil:
+		return errorResult(err), nil
+	default:
+		output["exists"] = true
+		output["resource_version"] = result.GetResourceVersion()
+		if result.GetDeletionTimestamp() != nil {
+			output["deleting"] = true
+		}
+	}
+
+	yamlOutput, err := objectToYAML(output)
+	if err != nil {
+		return errorResult(err), nil
+	}
+	return successResult(yamlOutput), nil
+}
+
+// defaultListLimit is the page size list_resources uses when neither the
+// call nor the config sets one, the same as kubectl's chunk size.
+const defaultListLimit = 500
+
+// listDefaultLimit returns the page size applied to list_resources calls
+// without 'limit'; 0 means unbounded.
+func (m *Manager) listDefaultLimit() int64 {
+	switch limit := m.currentConfig().Kubernetes.Tools.ListDefaultLimit; {
+	case limit > 0:
+		return int64(limit)
+	case limit < 0:
+		return 0
+	}
+	return defaultListLimit
+}
+
 func (m *Manager) registerListResources() {
+	pageLimit := "Omit to list everything in one call (the server is configured without a default page size; use only on small clusters)."
+	if limit := m.listDefaultLimit(); limit > 0 {
+		pageLimit = fmt.Sprintf("Defaults to %d.", limit)
+	}
 	tool := mcp.NewTool(m.toolName("list_resources"),
 		mcp.WithDescription(`List Kubernetes resources of a given type, optionally filtered by namespace,
 labels and fields.
@@ -117,27 +248,38 @@ Resources are addressed via GroupVersionResource (GVR). The plural lowercase
 form ('pods', 'deployments', 'ingresses', ...) is required, NOT the Kind.`),
 		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
 		mcp.WithString("group", mcp.Description("API group. Empty string \"\" for the core API. Examples: 'apps', 'networking.k8s.io', 'batch'.")),
-		mcp.WithString("version", mcp.Required(), mcp.Description("API version, e.g. 'v1', 'v1beta1'.")),
-		mcp.WithString("resource", mcp.Required(), mcp.Description("Resource name in the API sense: lowercase plural ('pods', 'deployments', 'ingresses'). NOT the Kind. Use 'list_api_resources' if unsure.")),
+		mcp.WithString("version", mcp.Description("API version, e.g. 'v1', 'v1beta1'. If empty or not served for the group, the preferred version is used (kubernetes.tools.preferred_versions, else discovery).")),
+		mcp.WithString("resource", mcp.Required(), mcp.Description("Resource name in the API sense: lowercase plural ('pods', 'deployments', 'ingresses'). NOT the Kind. Use 'list_api_resources' if unsure. Short names are accepted ('po', 'deploy', 'sts', ...).")),
 		mcp.WithString("namespace", mcp.Description("Namespace to scope the listing to. Empty string lists across ALL namespaces (subject to RBAC) and is ignored for cluster-scoped resources.")),
 		mcp.WithString("label_selector", mcp.Description("Kubernetes label selector. Comma separates AND clauses. Examples: 'app=nginx', 'app=api,env!=prod', 'tier in (frontend,backend)'.")),
 		mcp.WithString("field_selector", mcp.Description("Kubernetes field selector. Only a small set of fields is selectable per resource type (typically 'metadata.name', 'metadata.namespace', 'status.phase', 'spec.nodeName'). Examples: 'status.phase=Running', 'metadata.name=foo'.")),
-		mcp.WithNumber("limit", mcp.Description("Maximum number of items to return. Integer >= 1. When the cluster has more matching items, the response includes a `metadata.continue` token; pass it back in 'continue_token' to fetch the next page. Omit for no limit (use only on small clusters).")),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of items to return (page size). Integer >= 1. When the cluster has more matching items, the response includes a `metadata.continue` token; pass it back in 'continue_token' to fetch the next page. "+pageLimit)),
 		mcp.WithString("continue_token", mcp.Description("Continuation token returned by a previous call to fetch the next page. Pass alongside the same 'limit' and selectors.")),
+		mcp.WithBoolean("metadata_only", mcp.Description("If true, the API server returns only each item's metadata (name, labels, annotations, owners, ...), not its spec or status. The cheapest way to answer 'which objects exist, with which labels?' over thousands of items.")),
+		mcp.WithArray("fields", mcp.Description(fieldsParamDescription)),
 		mcp.WithArray("yq_expressions", mcp.Description("Optional yq expressions applied in order to filter or transform the YAML output. The output is a List object so use '.items[]' to iterate. Examples: '.items[].metadata.name' (just names), '.items | length' (count), '.items[] | select(.status.phase == \"Running\") | .metadata.name' (filter+project), '.items[] | {name: .metadata.name, ip: .status.podIP}' (reshape).")),
+		jqExpressionsParam(),
+		outputFormatParam(),
 	)
-	m.mcpServer.AddTool(tool, m.handleListResources)
+	m.addTool(tool, m.handleListResources)
 }
 
 func (m *Manager) handleListResources(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
 
-	k8sContext := m.getContextParam(args)
+	k8sContext := m.getContextParam(ctx, args)
 	namespace, _ := args["namespace"].(string)
-	gvr := gvrFromArgs(args)
+	gvr, err := m.resolveGVRVersion(k8sContext, m.expandShortName(k8sContext, gvrFromArgs(args)))
+	if err != nil {
+		return errorResult(err), nil
+	}
 	if err := validateGVR(gvr); err != nil {
 		return errorResult(err), nil
 	}
+	fields, metadataOnly, err := projectionFromArgs(args)
+	if err != nil {
+		return errorResult(err), nil
+	}
 
 	// Check authorization
 	if err := m.checkAuthorization(request, "list_resources", k8sContext, namespace, authorization.ResourceInfo{
@@ -159,32 +301,73 @@ func (m *Manager) handleListResources(ctx context.Context, request mcp.CallToolR
 	}
 
 	listOpts := getListOptions(args)
+	if listOpts.Limit == 0 {
+		listOpts.Limit = m.listDefaultLimit()
+	}
 
-	var result any
-	if namespace != "" {
-		result, err = client.DynamicClient.Resource(gvr).Namespace(namespace).List(ctx, listOpts)
+	var result map[string]any
+	if metadataOnly && client.MetadataClient != nil {
+		result, err = listObjectMetadata(ctx, client, gvr, namespace, listOpts)
 	} else {
-		result, err = client.DynamicClient.Resource(gvr).List(ctx, listOpts)
+		var list *unstructured.UnstructuredList
+		if namespace != "" {
+			list, err = client.DynamicClient.Resource(gvr).Namespace(namespace).List(ctx, listOpts)
+		} else {
+			list, err = client.DynamicClient.Resource(gvr).List(ctx, listOpts)
+		}
+		if err == nil {
+			result = list.UnstructuredContent()
+		}
 	}
 
 	if err != nil {
 		return errorResult(err), nil
 	}
 
+	if len(fields) > 0 {
+		items, _ := result["items"].([]any)
+		for i, item := range items {
+			if obj, ok := item.(map[string]any); ok {
+				items[i] = projectFields(obj, "", fields)
+			}
+		}
+	}
+
 	yamlOutput, err := objectToYAML(result)
 	if err != nil {
 		return errorResult(err), nil
 	}
 
-	// Apply yq expressions
-	finalOutput, err := m.applyYQExpressions(yamlOutput, args)
+	// Apply filters and the requested output format. Filters see only this
+	// page, so the continue token is repeated when they may have dropped it.
+	finalOutput, err := m.formatOutput(yamlOutput, args, "")
 	if err != nil {
 		return errorResult(err), nil
 	}
+	if note := pageNote(result, args); note != "" {
+		finalOutput = note + "\n" + finalOutput
+	}
 
 	return successResult(finalOutput), nil
 }
 
+// pageNote tells a filtered list_resources call that more items remain.
+// Unfiltered YAML and JSON keep 'metadata.continue' and the table view
+// prints it, so those need no note.
+func pageNote(list map[string]any, args map[string]any) string {
+	token, _, _ := unstructured.NestedString(list, "metadata", "continue")
+	if token == "" {
+		return ""
+	}
+	if format, _ := args["output_format"].(string); format == outputFormatTable {
+		return ""
+	}
+	if len(stringArgs(args, "yq_expressions")) == 0 && len(stringArgs(args, "jq_expressions")) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("# Filters ran on this page only; more items match. Pass continue_token=%q to fetch the next page.", token)
+}
+
 func (m *Manager) registerDescribeResource() {
 	tool := mcp.NewTool(m.toolName("describe_resource"),
 		mcp.WithDescription(`Return a resource together with its related events, similar to 'kubectl describe'.
@@ -197,22 +380,27 @@ caller only needs to provide the GVR.
 Events are only included when 'namespace' is set (the events API is namespaced).`),
 		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
 		mcp.WithString("group", mcp.Description("API group. Empty string \"\" for the core API.")),
-		mcp.WithString("version", mcp.Required(), mcp.Description("API version, e.g. 'v1'.")),
-		mcp.WithString("resource", mcp.Required(), mcp.Description("Resource name in the API sense: lowercase plural ('pods', 'deployments'). NOT the Kind.")),
+		mcp.WithString("version", mcp.Description("API version, e.g. 'v1'. If empty or not served for the group, the preferred version is used (kubernetes.tools.preferred_versions, else discovery).")),
+		mcp.WithString("resource", mcp.Required(), mcp.Description("Resource name in the API sense: lowercase plural ('pods', 'deployments'). NOT the Kind. Short names are accepted ('po', 'deploy', 'sts', ...).")),
 		mcp.WithString("name", mcp.Required(), mcp.Description("Name of the specific resource instance.")),
 		mcp.WithString("namespace", mcp.Description("Namespace where the resource lives. Required for namespaced resources; ignored for cluster-scoped resources. Events are only included when this is set.")),
+		mcp.WithBoolean("resolve_owners", mcp.Description("If true, follow metadata.ownerReferences up to the top-level owner and prepend a readable chain to the output, e.g. '# Owned by: Deployment/web → ReplicaSet/web-7d4b9c'.")),
 		mcp.WithArray("yq_expressions", mcp.Description("Optional yq expressions applied to the combined YAML (resource + events). The events are appended after a '---' separator. Examples: '.status.conditions' (just conditions), '.spec.containers[].image' (image list).")),
+		jqExpressionsParam(),
 	)
-	m.mcpServer.AddTool(tool, m.handleDescribeResource)
+	m.addTool(tool, m.handleDescribeResource)
 }
 
 func (m *Manager) handleDescribeResource(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
 
-	k8sContext := m.getContextParam(args)
+	k8sContext := m.getContextParam(ctx, args)
 	name, _ := args["name"].(string)
 	namespace, _ := args["namespace"].(string)
-	gvr := gvrFromArgs(args)
+	gvr, err := m.resolveGVRVersion(k8sContext, m.expandShortName(k8sContext, gvrFromArgs(args)))
+	if err != nil {
+		return errorResult(err), nil
+	}
 	if err := validateGVR(gvr); err != nil {
 		return errorResult(err), nil
 	}
@@ -237,7 +425,7 @@ func (m *Manager) handleDescribeResource(ctx context.Context, request mcp.CallTo
 	}
 
 	// Get the resource
-	var resource any
+	var resource *unstructured.Unstructured
 	if namespace != "" {
 		resource, err = client.DynamicClient.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
 	} else {
@@ -274,10 +462,250 @@ func (m *Manager) handleDescribeResource(ctx context.Context, request mcp.CallTo
 	combinedOutput := resourceYAML + eventsOutput
 
 	// Apply yq expressions
-	finalOutput, err := m.applyYQExpressions(combinedOutput, args)
+	finalOutput, err := m.applyOutputFilters(combinedOutput, args)
 	if err != nil {
 		return errorResult(err), nil
 	}
 
+	if resolveOwners, _ := args["resolve_owners"].(bool); resolveOwners {
+		finalOutput = m.ownerChain(ctx, request, "describe_resource", k8sContext, client, resource) + "\n" + finalOutput
+	}
+
 	return successResult(finalOutput), nil
 }
+
+// ownerChainMaxDepth bounds the ownerReferences walk. Real chains are short
+// (CronJob → Job → Pod, Deployment → ReplicaSet → Pod); the cap only
+// protects against malformed or cyclic references.
+const ownerChainMaxDepth = 8
+
+// ownerChain renders the owners of an object top-down as a YAML comment:
+// "# Owned by: Deployment/web → ReplicaSet/web-7d4b9c".
+func (m *Manager) ownerChain(ctx context.Context, request mcp.CallToolRequest, tool, k8sContext string, client *kubernetes.Client, obj *unstructured.Unstructured) string {
+	chain, note := m.resolveOwners(ctx, request, tool, k8sContext, client, obj)
+	if len(chain) == 0 {
+		return "# Owned by: (no owner references)"
+	}
+	return "# Owned by: " + strings.Join(chain, " → ") + note
+}
+
+// resolveOwners follows metadata.ownerReferences upwards, preferring the
+// controller reference at each level, and returns the chain outermost owner
+// first as Kind/name entries. Each owner is fetched under the same tool's
+// authorization and namespace rules; the walk stops at the first owner it
+// cannot read and 'note' says why.
+func (m *Manager) resolveOwners(ctx context.Context, request mcp.CallToolRequest, tool, k8sContext string, client *kubernetes.Client, obj *unstructured.Unstructured) (chain []string, note string) {
+	owners, note := m.walkOwners(ctx, request, tool, k8sContext, client, obj, ownerChainMaxDepth)
+	for _, owner := range owners {
+		chain = append(chain, owner.label)
+	}
+	return chain, note
+}
+
+// ownerLink is one owner found by walkOwners. 'obj' is nil for the last
+// link when the walk stopped because that owner could not be read.
+type ownerLink struct {
+	label string
+	obj   *unstructured.Unstructured
+}
+
+// walkOwners does the walk behind resolveOwners, up to maxDepth owners, and
+// returns them outermost first together with the objects read.
+func (m *Manager) walkOwners(ctx context.Context, request mcp.CallToolRequest, tool, k8sContext string, client *kubernetes.Client, obj *unstructured.Unstructured, maxDepth int) (owners []ownerLink, note string) {
+	namespace := obj.GetNamespace()
+	seen := map[types.UID]bool{obj.GetUID(): true}
+
+	current := obj
+	for depth := 0; depth < maxDepth; depth++ {
+		ref, ok := controllerOrFirstOwner(current.GetOwnerReferences())
+		if !ok {
+			break
+		}
+		label := ref.Kind + "/" + ref.Name
+		owners = append(owners, ownerLink{label: label})
+
+		if seen[ref.UID] {
+			note = " (cycle detected)"
+			break
+		}
+		seen[ref.UID] = true
+
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil {
+			note = fmt.Sprintf(" (could not parse apiVersion %q)", ref.APIVersion)
+			break
+		}
+		gvr, namespaced, err := m.resolveGVRForGVK(client, gv.WithKind(ref.Kind))
+		if err != nil {
+			note = fmt.Sprintf(" (%s not resolvable via discovery)", label)
+			break
+		}
+
+		// Owners are either in the same namespace or cluster-scoped.
+		ownerNS := ""
+		if namespaced {
+			ownerNS = namespace
+		}
+		if err := m.checkAuthorization(request, tool, k8sContext, ownerNS, authorization.ResourceInfo{
+			Group:    gvr.Group,
+			Version:  gvr.Version,
+			Resource: gvr.Resource,
+			Name:     ref.Name,
+		}); err != nil {
+			note = fmt.Sprintf(" (not authorized to read %s)", label)
+			break
+		}
+
+		var owner *unstructured.Unstructured
+		if ownerNS != "" {
+			owner, err = client.DynamicClient.Resource(gvr).Namespace(ownerNS).Get(ctx, ref.Name, metav1.GetOptions{})
+		} else {
+			owner, err = client.DynamicClient.Resource(gvr).Get(ctx, ref.Name, metav1.GetOptions{})
+		}
+		if err != nil {
+			note = fmt.Sprintf(" (could not read %s: %v)", label, err)
+			break
+		}
+		owners[len(owners)-1].obj = owner
+		current = owner
+	}
+
+	if len(owners) == maxDepth && note == "" {
+		note = " (max depth reached)"
+	}
+
+	// Collected bottom-up; present outermost owner first.
+	slices.Reverse(owners)
+	return owners, note
+}
+
+// controllerOrFirstOwner returns the managing controller reference when
+// there is one, else the first owner reference.
+func controllerOrFirstOwner(refs []metav1.OwnerReference) (metav1.OwnerReference, bool) {
+	if len(refs) == 0 {
+		return metav1.OwnerReference{}, false
+	}
+	for _, ref := range refs {
+		if ref.Controller != nil && *ref.Controller {
+			return ref, true
+		}
+	}
+	return refs[0], true
+}
+
+const fieldsParamDescription = "Optional dotted paths of the fields to return, everything else is dropped (apiVersion, kind, metadata.name and metadata.namespace are always kept). A path keeps everything below it. Map keys are written as-is, dots included. When every path is under 'metadata' the API server returns metadata only. Examples: 'metadata.labels', 'spec.replicas', 'status.phase', 'spec.template.spec.containers'."
+
+// projectionAlwaysKept are the fields a 'fields' projection never drops, so
+// every item stays identifiable.
+var projectionAlwaysKept = []string{"apiVersion", "kind", "metadata.name", "metadata.namespace"}
+
+// projectionFromArgs reads 'fields' and 'metadata_only'. A projection that
+// only touches metadata switches to a metadata-only read, so the API server
+// does the trimming instead of sending full objects.
+func projectionFromArgs(args map[string]any) (fields []string, metadataOnly bool, err error) {
+	metadataOnly, _ = args["metadata_only"].(bool)
+
+	raw, _ := args["fields"].([]any)
+	for _, f := range raw {
+		if s, ok := f.(string); ok && strings.Trim(s, ".") != "" {
+			fields = append(fields, strings.Trim(s, "."))
+		}
+	}
+	if len(fields) == 0 {
+		return nil, metadataOnly, nil
+	}
+
+	allMetadata := true
+	for _, f := range fields {
+		if !fieldSelected(f, append([]string{"metadata"}, projectionAlwaysKept...)) {
+			allMetadata = false
+			if metadataOnly {
+				return nil, false, fmt.Errorf("field %q is not part of the metadata; drop it or set metadata_only=false", f)
+			}
+		}
+	}
+	return append(fields, projectionAlwaysKept...), metadataOnly || allMetadata, nil
+}
+
+// projectFields keeps only the selected dotted paths of an object (all of it
+// when 'fields' is empty). Lists are kept or dropped whole.
+func projectFields(obj map[string]any, prefix string, fields []string) map[string]any {
+	if len(fields) == 0 {
+		return obj
+	}
+	out := map[string]any{}
+	for key, value := range obj {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		if fieldSelected(path, fields) {
+			out[key] = value
+			continue
+		}
+		child, isMap := value.(map[string]any)
+		if !isMap || !fieldBelow(path, fields) {
+			continue
+		}
+		if projected := projectFields(child, path, fields); len(projected) > 0 {
+			out[key] = projected
+		}
+	}
+	return out
+}
+
+// fieldSelected reports whether path is one of fields or lies below one
+func fieldSelected(path string, fields []string) bool {
+	for _, f := range fields {
+		if path == f || strings.HasPrefix(path, f+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldBelow reports whether one of fields lies below path
+func fieldBelow(path string, fields []string) bool {
+	for _, f := range fields {
+		if strings.HasPrefix(f, path+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// getObjectMetadata reads only the metadata of one object
+// (PartialObjectMetadata), as unstructured.
+func getObjectMetadata(ctx context.Context, client *kubernetes.Client, gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error) {
+	var partial *metav1.PartialObjectMetadata
+	var err error
+	if namespace != "" {
+		partial, err = client.MetadataClient.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	} else {
+		partial, err = client.MetadataClient.Resource(gvr).Get(ctx, name, metav1.GetOptions{})
+	}
+	if err != nil {
+		return nil, err
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(partial)
+	if err != nil {
+		return nil, err
+	}
+	return &unstructured.Unstructured{Object: content}, nil
+}
+
+// listObjectMetadata lists only the metadata of the matching objects
+// (PartialObjectMetadataList), as unstructured content.
+func listObjectMetadata(ctx context.Context, client *kubernetes.Client, gvr schema.GroupVersionResource, namespace string, opts metav1.ListOptions) (map[string]any, error) {
+	var list *metav1.PartialObjectMetadataList
+	var err error
+	if namespace != "" {
+		list, err = client.MetadataClient.Resource(gvr).Namespace(namespace).List(ctx, opts)
+	} else {
+		list, err = client.MetadataClient.Resource(gvr).List(ctx, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return runtime.DefaultUnstructuredConverter.ToUnstructured(list)
+}
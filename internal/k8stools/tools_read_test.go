@@ -0,0 +1,553 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func fakePod(namespace, name string, labels map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "app", Image: "nginx:1.27"}},
+		},
+	}
+}
+
+func TestGetResource(t *testing.T) {
+	e := newFakeEnv(t, fakePod("default", "web", nil))
+
+	res, err := e.manager.handleGetResource(context.Background(), makeRequest(map[string]any{
+		"version":   "v1",
+		"resource":  "pods",
+		"namespace": "default",
+		"name":      "web",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "get_resource")
+	requireContains(t, out, "kind: Pod", "expected kind")
+	requireContains(t, out, "name: web", "expected name")
+	requireContains(t, out, "image: nginx:1.27", "expected container image")
+}
+
+func TestGetResource_YQExpression(t *testing.T) {
+	e := newFakeEnv(t, fakePod("default", "web", nil))
+
+	res, err := e.manager.handleGetResource(context.Background(), makeRequest(map[string]any{
+		"version":        "v1",
+		"resource":       "pods",
+		"namespace":      "default",
+		"name":           "web",
+		"yq_expressions": []any{".spec.containers[0].image"},
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "get_resource with yq")
+	if strings.TrimSpace(out) != "nginx:1.27" {
+		t.Fatalf("expected only the image, got:\n%s", out)
+	}
+}
+
+func TestGetResource_JQExpression(t *testing.T) {
+	e := newFakeEnv(t, fakePod("default", "web", nil))
+
+	get := func(filters map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		args := map[string]any{"version": "v1", "resource": "pods", "namespace": "default", "name": "web"}
+		for k, v := range filters {
+			args[k] = v
+		}
+		res, err := e.manager.handleGetResource(context.Background(), makeRequest(args))
+		if err != nil {
+			t.Fatalf("go-error: %v", err)
+		}
+		return res
+	}
+
+	out := expectOK(t, get(map[string]any{"jq_expressions": []any{".spec.containers[0].image"}}), "jq")
+	if strings.TrimSpace(out) != "nginx:1.27" {
+		t.Fatalf("expected only the image, got:\n%s", out)
+	}
+
+	// yq runs first and jq reads what it left; structured results are YAML
+	out = expectOK(t, get(map[string]any{
+		"yq_expressions": []any{".spec.containers[0]"},
+		"jq_expressions": []any{"{container: .name, image}"},
+	}), "yq then jq")
+	if strings.TrimSpace(out) != "container: app\nimage: nginx:1.27" {
+		t.Fatalf("unexpected combined output:\n%s", out)
+	}
+
+	requireContains(t, expectErr(t, get(map[string]any{"jq_expressions": []any{".spec |"}}), "bad jq"),
+		"failed to evaluate jq expression", "expected the jq error")
+}
+
+func TestGetResource_Errors(t *testing.T) {
+	e := newFakeEnv(t, fakePod("default", "web", nil))
+	e.provider.deniedNamespaces = []string{"kube-system"}
+
+	tests := []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{
+			name: "not found",
+			args: map[string]any{"version": "v1", "resource": "pods", "namespace": "default", "name": "missing"},
+			want: "not found",
+		},
+		{
+			name: "kind instead of resource",
+			args: map[string]any{"version": "v1", "resource": "Pod", "namespace": "default", "name": "web"},
+			want: "lowercase plural",
+		},
+		{
+			name: "missing version of an unknown resource",
+			args: map[string]any{"resource": "widgets", "namespace": "default", "name": "web"},
+			want: "version is empty and no served version of \"widgets\"",
+		},
+		{
+			name: "denied namespace",
+			args: map[string]any{"version": "v1", "resource": "pods", "namespace": "kube-system", "name": "web"},
+			want: "namespace kube-system is not allowed in context fake",
+		},
+		{
+			name: "unknown context",
+			args: map[string]any{"context": "other", "version": "v1", "resource": "pods", "name": "web"},
+			want: "context other not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := e.manager.handleGetResource(context.Background(), makeRequest(tt.args))
+			if err != nil {
+				t.Fatalf("go-error: %v", err)
+			}
+			text := expectErr(t, res, tt.name)
+			requireContains(t, text, tt.want, "unexpected error text")
+		})
+	}
+}
+
+func TestGetResource_ResolvesVersion(t *testing.T) {
+	e := newFakeEnv(t, fakePod("default", "web", nil), fakeDeployment("default", "api", 1))
+
+	tests := []struct {
+		name      string
+		preferred map[string]string
+		args      map[string]any
+		want      string
+	}{
+		{
+			name: "empty version",
+			args: map[string]any{"resource": "pods", "namespace": "default", "name": "web"},
+			want: "name: web",
+		},
+		{
+			name: "version not served",
+			args: map[string]any{"group": "apps", "version": "v1beta1", "resource": "deployments", "namespace": "default", "name": "api"},
+			want: "name: api",
+		},
+		{
+			name:      "configured override",
+			preferred: map[string]string{"core": "v1"},
+			args:      map[string]any{"version": "v2", "resource": "pods", "namespace": "default", "name": "web"},
+			want:      "name: web",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e.manager.config.Kubernetes.Tools.PreferredVersions = tt.preferred
+			res, err := e.manager.handleGetResource(context.Background(), makeRequest(tt.args))
+			if err != nil {
+				t.Fatalf("go-error: %v", err)
+			}
+			requireContains(t, expectOK(t, res, tt.name), tt.want, "expected the resource")
+		})
+	}
+}
+
+func TestResourceExists(t *testing.T) {
+	pod := fakePod("default", "web", nil)
+	pod.ResourceVersion = "42"
+	e := newFakeEnv(t, pod)
+
+	tests := []struct {
+		name string
+		pod  string
+		want string
+	}{
+		{name: "exists", pod: "web", want: "exists: true\nresource_version: \"42\"\n"},
+		{name: "not found", pod: "missing", want: "exists: false\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := e.manager.handleResourceExists(context.Background(), makeRequest(map[string]any{
+				"version":   "v1",
+				"resource":  "pods",
+				"namespace": "default",
+				"name":      tt.pod,
+			}))
+			if err != nil {
+				t.Fatalf("go-error: %v", err)
+			}
+			if out := expectOK(t, res, tt.name); out != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, out)
+			}
+		})
+	}
+}
+
+func TestResourceExists_OtherErrors(t *testing.T) {
+	e := newFakeEnv(t)
+	e.dynamic.PrependReactor("get", "secrets", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "secrets"}, "db", nil)
+	})
+
+	res, err := e.manager.handleResourceExists(context.Background(), makeRequest(map[string]any{
+		"version":   "v1",
+		"resource":  "secrets",
+		"namespace": "default",
+		"name":      "db",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	text := expectErr(t, res, "forbidden must not read as exists:false")
+	requireContains(t, text, "forbidden", "expected the API error")
+}
+
+func TestListResources_LabelSelector(t *testing.T) {
+	e := newFakeEnv(t,
+		fakePod("default", "web-1", map[string]string{"app": "web"}),
+		fakePod("default", "web-2", map[string]string{"app": "web"}),
+		fakePod("default", "db-1", map[string]string{"app": "db"}),
+		fakePod("other", "web-3", map[string]string{"app": "web"}),
+	)
+
+	res, err := e.manager.handleListResources(context.Background(), makeRequest(map[string]any{
+		"version":        "v1",
+		"resource":       "pods",
+		"namespace":      "default",
+		"label_selector": "app=web",
+		"yq_expressions": []any{"[.items[].metadata.name] | join(\",\")"},
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "list_resources")
+	if strings.TrimSpace(out) != "web-1,web-2" {
+		t.Fatalf("expected web-1,web-2, got:\n%s", out)
+	}
+}
+
+func TestListResources_AllNamespaces(t *testing.T) {
+	e := newFakeEnv(t,
+		fakePod("default", "web-1", nil),
+		fakePod("other", "web-2", nil),
+	)
+
+	res, err := e.manager.handleListResources(context.Background(), makeRequest(map[string]any{
+		"version":  "v1",
+		"resource": "pods",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "list_resources across namespaces")
+	requireContains(t, out, "kind: PodList", "expected a List object")
+	requireContains(t, out, "name: web-1", "expected pod from default")
+	requireContains(t, out, "name: web-2", "expected pod from other")
+}
+
+func TestListResources_Pagination(t *testing.T) {
+	e := newFakeEnv(t)
+
+	var limits []int64
+	e.dynamic.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		limits = append(limits, action.(k8stesting.ListActionImpl).ListOptions.Limit)
+		list := &unstructured.UnstructuredList{Object: map[string]any{"apiVersion": "v1", "kind": "PodList"}}
+		list.SetContinue("next-page")
+		list.Items = []unstructured.Unstructured{{Object: map[string]any{
+			"apiVersion": "v1", "kind": "Pod", "metadata": map[string]any{"name": "web-1", "namespace": "default"},
+		}}}
+		return true, list, nil
+	})
+
+	list := func(extra map[string]any) string {
+		t.Helper()
+		args := map[string]any{"version": "v1", "resource": "pods", "namespace": "default"}
+		for k, v := range extra {
+			args[k] = v
+		}
+		res, err := e.manager.handleListResources(context.Background(), makeRequest(args))
+		if err != nil {
+			t.Fatalf("go-error: %v", err)
+		}
+		return expectOK(t, res, "list_resources")
+	}
+
+	requireContains(t, list(nil), "continue: next-page", "expected the continue token in the List")
+	list(map[string]any{"limit": float64(20)})
+	e.manager.config.Kubernetes.Tools.ListDefaultLimit = -1
+	list(nil)
+	if want := []int64{defaultListLimit, 20, 0}; !slices.Equal(limits, want) {
+		t.Fatalf("expected limits %v, got %v", want, limits)
+	}
+
+	// A filter that drops the token gets it repeated in a note
+	out := list(map[string]any{"yq_expressions": []any{".items[].metadata.name"}})
+	requireContains(t, out, `continue_token="next-page"`, "expected the page note")
+	requireContains(t, out, "web-1", "expected the filtered page")
+}
+
+func controllerRef(apiVersion, kind, name string, uid types.UID) metav1.OwnerReference {
+	isController := true
+	return metav1.OwnerReference{APIVersion: apiVersion, Kind: kind, Name: name, UID: uid, Controller: &isController}
+}
+
+func TestGetResource_ResolveOwners(t *testing.T) {
+	deploy := fakeDeployment("default", "web", 1)
+	deploy.UID = "deploy-uid"
+
+	rs := &appsv1.ReplicaSet{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "ReplicaSet"},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "default",
+			Name:            "web-7d4b9c",
+			UID:             "rs-uid",
+			OwnerReferences: []metav1.OwnerReference{controllerRef("apps/v1", "Deployment", "web", "deploy-uid")},
+		},
+	}
+
+	pod := fakePod("default", "web-7d4b9c-x2x2", nil)
+	pod.OwnerReferences = []metav1.OwnerReference{controllerRef("apps/v1", "ReplicaSet", "web-7d4b9c", "rs-uid")}
+
+	orphan := fakePod("default", "orphan", nil)
+
+	// The ReplicaSet of this Pod no longer exists
+	dangling := fakePod("default", "dangling", nil)
+	dangling.OwnerReferences = []metav1.OwnerReference{controllerRef("apps/v1", "ReplicaSet", "gone", "gone-uid")}
+
+	e := newFakeEnv(t, deploy, rs, pod, orphan, dangling)
+
+	tests := []struct {
+		name string
+		pod  string
+		want string
+	}{
+		{name: "full chain", pod: "web-7d4b9c-x2x2", want: "# Owned by: Deployment/web → ReplicaSet/web-7d4b9c\n"},
+		{name: "no owners", pod: "orphan", want: "# Owned by: (no owner references)\n"},
+		{name: "missing owner", pod: "dangling", want: "# Owned by: ReplicaSet/gone (could not read ReplicaSet/gone"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := e.manager.handleGetResource(context.Background(), makeRequest(map[string]any{
+				"version":        "v1",
+				"resource":       "pods",
+				"namespace":      "default",
+				"name":           tt.pod,
+				"resolve_owners": true,
+			}))
+			if err != nil {
+				t.Fatalf("go-error: %v", err)
+			}
+			out := expectOK(t, res, "get_resource resolve_owners")
+			if !strings.HasPrefix(out, tt.want) {
+				t.Fatalf("expected output to start with %q, got:\n%s", tt.want, out)
+			}
+		})
+	}
+}
+
+func TestListResources_MetadataOnly(t *testing.T) {
+	e := newFakeEnv(t,
+		fakePod("default", "web-1", map[string]string{"app": "web"}),
+		fakePod("default", "web-2", map[string]string{"app": "web"}),
+	)
+
+	res, err := e.manager.handleListResources(context.Background(), makeRequest(map[string]any{
+		"version":   "v1",
+		"resource":  "pods",
+		"namespace": "default",
+		"fields":    []any{"metadata.labels"},
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "list_resources metadata fields")
+	requireContains(t, out, "name: web-2", "expected item names kept")
+	requireContains(t, out, "app: web", "expected the requested labels")
+	if strings.Contains(out, "nginx") {
+		t.Fatalf("spec must not be returned:\n%s", out)
+	}
+
+	// Metadata-only reads go to the metadata client, not the dynamic one
+	for _, action := range e.dynamic.Actions() {
+		if action.GetVerb() == "list" {
+			t.Fatalf("expected a metadata-only list, got a full dynamic list")
+		}
+	}
+}
+
+func TestGetResource_Fields(t *testing.T) {
+	pod := fakePod("default", "web", map[string]string{"app": "web"})
+	pod.Spec.NodeName = "node-1"
+	pod.Status.Phase = corev1.PodRunning
+	e := newFakeEnv(t, pod)
+
+	res, err := e.manager.handleGetResource(context.Background(), makeRequest(map[string]any{
+		"version":   "v1",
+		"resource":  "pods",
+		"namespace": "default",
+		"name":      "web",
+		"fields":    []any{"status.phase", "spec.nodeName"},
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "get_resource fields")
+	requireContains(t, out, "kind: Pod", "expected kind kept")
+	requireContains(t, out, "phase: Running", "expected status.phase")
+	requireContains(t, out, "nodeName: node-1", "expected spec.nodeName")
+	if strings.Contains(out, "nginx") || strings.Contains(out, "labels") {
+		t.Fatalf("unrequested fields must be dropped:\n%s", out)
+	}
+
+	res, err = e.manager.handleGetResource(context.Background(), makeRequest(map[string]any{
+		"version":       "v1",
+		"resource":      "pods",
+		"namespace":     "default",
+		"name":          "web",
+		"metadata_only": true,
+		"fields":        []any{"status.phase"},
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out = expectErr(t, res, "metadata_only with a non-metadata field")
+	requireContains(t, out, `field "status.phase" is not part of the metadata`, "expected a clear error")
+}
+
+func TestProjectFields(t *testing.T) {
+	obj := map[string]any{
+		"kind": "Deployment",
+		"metadata": map[string]any{
+			"name":        "web",
+			"labels":      map[string]any{"app": "web"},
+			"annotations": map[string]any{"example.com/owner": "team-a", "other": "x"},
+		},
+		"spec": map[string]any{"replicas": int64(3), "paused": true},
+	}
+
+	got := projectFields(obj, "", []string{"metadata.annotations.example.com/owner", "spec.replicas", "metadata.name"})
+	want := map[string]any{
+		"metadata": map[string]any{
+			"name":        "web",
+			"annotations": map[string]any{"example.com/owner": "team-a"},
+		},
+		"spec": map[string]any{"replicas": int64(3)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("projectFields = %v, want %v", got, want)
+	}
+}
+
+func TestExpandShortName(t *testing.T) {
+	e := newFakeEnv(t)
+	e.serveShortNames()
+
+	tests := []struct {
+		in   schema.GroupVersionResource
+		want schema.GroupVersionResource
+	}{
+		{gvrOf("", "v1", "po"), gvrOf("", "v1", "pods")},
+		{gvrOf("", "v1", "svc"), gvrOf("", "v1", "services")},
+		{gvrOf("", "v1", "cm"), gvrOf("", "v1", "configmaps")},
+		{gvrOf("", "v1", "deploy"), gvrOf("apps", "v1", "deployments")},
+		{gvrOf("apps", "v1", "sts"), gvrOf("apps", "v1", "statefulsets")},
+		{gvrOf("", "v1", "wd"), gvrOf("example.com", "v1", "widgets")},
+		// Not short names, or a short name of another group: unchanged
+		{gvrOf("", "v1", "pods"), gvrOf("", "v1", "pods")},
+		{gvrOf("batch", "v1", "deploy"), gvrOf("batch", "v1", "deploy")},
+		{gvrOf("", "v1", "po/log"), gvrOf("", "v1", "po/log")},
+	}
+	for _, tt := range tests {
+		if got := e.manager.expandShortName(fakeContext, tt.in); got != tt.want {
+			t.Errorf("expandShortName(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestExpandShortName_Cached(t *testing.T) {
+	e := newFakeEnv(t)
+	e.serveShortNames()
+	client := e.provider.client
+
+	if got := e.manager.expandShortName(fakeContext, gvrOf("", "v1", "wd")); got.Resource != "widgets" {
+		t.Fatalf("expected wd to expand, got %v", got)
+	}
+
+	// The CRD goes away: the table is kept until discovery is reset
+	e.clientset.Resources = e.clientset.Resources[:2]
+	if got := e.manager.expandShortName(fakeContext, gvrOf("", "v1", "wd")); got.Resource != "widgets" {
+		t.Fatalf("expected the cached table to be used, got %v", got)
+	}
+	client.ResetDiscovery()
+	if got := e.manager.expandShortName(fakeContext, gvrOf("", "v1", "wd")); got.Resource != "wd" {
+		t.Fatalf("expected the table to be reloaded after a reset, got %v", got)
+	}
+}
+
+func TestGetResource_ShortName(t *testing.T) {
+	e := newFakeEnv(t, fakePod("default", "web", nil))
+	e.serveShortNames()
+
+	res, err := e.manager.handleGetResource(context.Background(), makeRequest(map[string]any{
+		"version":   "v1",
+		"resource":  "po",
+		"namespace": "default",
+		"name":      "web",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	requireContains(t, expectOK(t, res, "get_resource po"), "name: web", "expected the pod")
+}
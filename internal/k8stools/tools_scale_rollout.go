@@ -45,22 +45,26 @@ the workload without deleting it; restoring the value brings it back.
 DaemonSets are NOT supported: they have no 'spec.replicas' (one Pod per
 node) and a patch on the field would be silently ignored by the
 controller. The tool rejects DaemonSet GVRs explicitly so the call does
-not look successful while doing nothing.`),
+not look successful while doing nothing.
+
+'dry_run=true' validates the change server-side and returns the object it
+would produce without scaling anything.`),
 		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
 		mcp.WithString("group", mcp.Description("API group. Defaults to 'apps'.")),
 		mcp.WithString("version", mcp.Required(), mcp.Description("API version, typically 'v1'.")),
-		mcp.WithString("resource", mcp.Required(), mcp.Description("Lowercase plural: 'deployments', 'statefulsets', 'replicasets'. NOT 'daemonsets' (cannot be scaled). NOT the Kind.")),
+		mcp.WithString("resource", mcp.Required(), mcp.Description("Lowercase plural: 'deployments', 'statefulsets', 'replicasets'. NOT 'daemonsets' (cannot be scaled). NOT the Kind. Short names are accepted ('po', 'deploy', 'sts', ...).")),
 		mcp.WithString("name", mcp.Required(), mcp.Description("Name of the workload to scale.")),
 		mcp.WithString("namespace", mcp.Description("Namespace where the workload lives. Required (these kinds are namespaced).")),
 		mcp.WithNumber("replicas", mcp.Required(), mcp.Description("Desired replica count. Must be an integer >= 0. Use 0 to stop the workload without deleting it.")),
+		mcp.WithBoolean("dry_run", mcp.Description("Server-side dry run: return the object the API server would store, without scaling. Defaults to false.")),
 	)
-	m.mcpServer.AddTool(tool, m.handleScaleResource)
+	m.addTool(tool, m.handleScaleResource)
 }
 
 func (m *Manager) handleScaleResource(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
 
-	k8sContext := m.getContextParam(args)
+	k8sContext := m.getContextParam(ctx, args)
 	group, _ := args["group"].(string)
 	if group == "" {
 		group = "apps"
@@ -70,8 +74,9 @@ func (m *Manager) handleScaleResource(ctx context.Context, request mcp.CallToolR
 	name, _ := args["name"].(string)
 	namespace, _ := args["namespace"].(string)
 	replicas, _ := args["replicas"].(float64)
+	dryRun, _ := args["dry_run"].(bool)
 
-	gvr := schema.GroupVersionResource{Group: group, Version: version, Resource: resource}
+	gvr := m.expandShortName(k8sContext, schema.GroupVersionResource{Group: group, Version: version, Resource: resource})
 	if err := validateGVR(gvr); err != nil {
 		return errorResult(err), nil
 	}
@@ -120,7 +125,7 @@ func (m *Manager) handleScaleResource(ctx context.Context, request mcp.CallToolR
 	}
 
 	result, err := client.DynamicClient.Resource(gvr).Namespace(namespace).Patch(
-		ctx, name, types.MergePatchType, patchBytes, metav1.PatchOptions{})
+		ctx, name, types.MergePatchType, patchBytes, metav1.PatchOptions{DryRun: dryRunOption(dryRun)})
 	if err != nil {
 		return errorResult(err), nil
 	}
@@ -130,7 +135,12 @@ func (m *Manager) handleScaleResource(ctx context.Context, request mcp.CallToolR
 		return errorResult(err), nil
 	}
 
-	return successResult(fmt.Sprintf("Successfully scaled %s/%s to %d replicas\n\n%s", gvr.Resource, name, int(replicas), yamlOutput)), nil
+	if dryRun {
+		return successResult(fmt.Sprintf("%s Scaling %s/%s to %d replicas would store:\n\n%s",
+			dryRunNote, gvr.Resource, name, int(replicas), m.redactYAML(yamlOutput))), nil
+	}
+
+	return successResult(fmt.Sprintf("Successfully scaled %s/%s to %d replicas\n\n%s", gvr.Resource, name, int(replicas), m.redactYAML(yamlOutput))), nil
 }
 
 func (m *Manager) registerGetRolloutStatus() {
@@ -145,17 +155,17 @@ a change to confirm the rollout has converged.`),
 		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
 		mcp.WithString("group", mcp.Description("API group. Defaults to 'apps'.")),
 		mcp.WithString("version", mcp.Required(), mcp.Description("API version, typically 'v1'.")),
-		mcp.WithString("resource", mcp.Required(), mcp.Description("Lowercase plural: 'deployments', 'daemonsets', 'statefulsets'. NOT the Kind.")),
+		mcp.WithString("resource", mcp.Required(), mcp.Description("Lowercase plural: 'deployments', 'daemonsets', 'statefulsets'. NOT the Kind. Short names are accepted ('po', 'deploy', 'sts', ...).")),
 		mcp.WithString("name", mcp.Required(), mcp.Description("Name of the workload.")),
 		mcp.WithString("namespace", mcp.Description("Namespace where the workload lives.")),
 	)
-	m.mcpServer.AddTool(tool, m.handleGetRolloutStatus)
+	m.addTool(tool, m.handleGetRolloutStatus)
 }
 
 func (m *Manager) handleGetRolloutStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
 
-	k8sContext := m.getContextParam(args)
+	k8sContext := m.getContextParam(ctx, args)
 	group, _ := args["group"].(string)
 	if group == "" {
 		group = "apps"
@@ -165,7 +175,7 @@ func (m *Manager) handleGetRolloutStatus(ctx context.Context, request mcp.CallTo
 	name, _ := args["name"].(string)
 	namespace, _ := args["namespace"].(string)
 
-	gvr := schema.GroupVersionResource{Group: group, Version: version, Resource: resource}
+	gvr := m.expandShortName(k8sContext, schema.GroupVersionResource{Group: group, Version: version, Resource: resource})
 	if err := validateGVR(gvr); err != nil {
 		return errorResult(err), nil
 	}
@@ -204,46 +214,90 @@ func (m *Manager) handleGetRolloutStatus(ctx context.Context, request mcp.CallTo
 	return successResult(statusText), nil
 }
 
-// formatRolloutStatus renders a kind-aware rollout summary. Deployments,
+// rolloutProgress holds the replica counters of a rollout, normalised across
+// Deployments, StatefulSets and DaemonSets.
+type rolloutProgress struct {
+	desired, ready, updated, available int64
+	// current counts every Pod the controller still owns, old revisions
+	// included. Only Deployments report it; for the others it equals updated.
+	current                        int64
+	generation, observedGeneration int64
+}
+
+// synced reports whether the controller has observed the latest spec
+func (p rolloutProgress) synced() bool {
+	return p.generation == p.observedGeneration
+}
+
+// complete reports whether every desired Pod runs the latest template and is
+// available, with no Pods of older revisions left.
+func (p rolloutProgress) complete() bool {
+	return p.synced() &&
+		p.updated >= p.desired &&
+		p.ready >= p.desired &&
+		p.available >= p.desired &&
+		p.current <= p.updated
+}
+
+// readRolloutProgress extracts the rollout counters of a workload. Deployments,
 // StatefulSets and DaemonSets each expose a different set of status fields;
 // a one-size-fits-all reader (the previous implementation) returned 0s for
 // the kinds it did not match.
-func formatRolloutStatus(obj *unstructured.Unstructured, gvr schema.GroupVersionResource, name string) string {
+func readRolloutProgress(obj *unstructured.Unstructured, gvr schema.GroupVersionResource) rolloutProgress {
 	status, _, _ := unstructured.NestedMap(obj.Object, "status")
 	spec, _, _ := unstructured.NestedMap(obj.Object, "spec")
-	generation := obj.GetGeneration()
-	observedGeneration, _, _ := unstructured.NestedInt64(status, "observedGeneration")
 
-	var (
-		desired, ready, updated, available int64
-	)
+	var p rolloutProgress
+	p.generation = obj.GetGeneration()
+	p.observedGeneration, _, _ = unstructured.NestedInt64(status, "observedGeneration")
 
 	switch gvr.Resource {
 	case "daemonsets":
-		desired, _, _ = unstructured.NestedInt64(status, "desiredNumberScheduled")
-		ready, _, _ = unstructured.NestedInt64(status, "numberReady")
-		updated, _, _ = unstructured.NestedInt64(status, "updatedNumberScheduled")
-		available, _, _ = unstructured.NestedInt64(status, "numberAvailable")
+		p.desired, _, _ = unstructured.NestedInt64(status, "desiredNumberScheduled")
+		p.ready, _, _ = unstructured.NestedInt64(status, "numberReady")
+		p.updated, _, _ = unstructured.NestedInt64(status, "updatedNumberScheduled")
+		p.available, _, _ = unstructured.NestedInt64(status, "numberAvailable")
+		p.current = p.updated
 	case "statefulsets":
-		desired, _, _ = unstructured.NestedInt64(spec, "replicas")
-		ready, _, _ = unstructured.NestedInt64(status, "readyReplicas")
-		updated, _, _ = unstructured.NestedInt64(status, "updatedReplicas")
+		p.desired = specReplicas(spec)
+		p.ready, _, _ = unstructured.NestedInt64(status, "readyReplicas")
+		p.updated, _, _ = unstructured.NestedInt64(status, "updatedReplicas")
 		// StatefulSets do not report 'availableReplicas' in older versions; in
 		// recent versions they do (1.22+). Try and fall back to readyReplicas.
 		availableField, found, _ := unstructured.NestedInt64(status, "availableReplicas")
 		if found {
-			available = availableField
+			p.available = availableField
 		} else {
-			available = ready
+			p.available = p.ready
 		}
+		p.current = p.updated
 	default: // deployments (the public handler restricts to the three apps/v1
 		// rollout kinds; this branch covers Deployments and acts as a safe
 		// fallback for any future addition that uses replicas-style status).
-		desired, _, _ = unstructured.NestedInt64(spec, "replicas")
-		ready, _, _ = unstructured.NestedInt64(status, "readyReplicas")
-		updated, _, _ = unstructured.NestedInt64(status, "updatedReplicas")
-		available, _, _ = unstructured.NestedInt64(status, "availableReplicas")
+		p.desired = specReplicas(spec)
+		p.ready, _, _ = unstructured.NestedInt64(status, "readyReplicas")
+		p.updated, _, _ = unstructured.NestedInt64(status, "updatedReplicas")
+		p.available, _, _ = unstructured.NestedInt64(status, "availableReplicas")
+		p.current, _, _ = unstructured.NestedInt64(status, "replicas")
+	}
+
+	return p
+}
+
+// specReplicas returns spec.replicas, defaulting to 1 like the API server
+// does when the field is omitted.
+func specReplicas(spec map[string]any) int64 {
+	replicas, found, _ := unstructured.NestedInt64(spec, "replicas")
+	if !found {
+		return 1
 	}
+	return replicas
+}
+
+// formatRolloutStatus renders a kind-aware rollout summary
+func formatRolloutStatus(obj *unstructured.Unstructured, gvr schema.GroupVersionResource, name string) string {
+	status, _, _ := unstructured.NestedMap(obj.Object, "status")
+	p := readRolloutProgress(obj, gvr)
 
 	statusText := fmt.Sprintf(`Rollout Status for %s/%s:
   Desired:    %d
@@ -251,11 +305,13 @@ func formatRolloutStatus(obj *unstructured.Unstructured, gvr schema.GroupVersion
   Updated:    %d
   Available:  %d
   Generation: %d (observed: %d)
-  Synced:     %v`,
+  Synced:     %v
+  Complete:   %v`,
 		gvr.Resource, name,
-		desired, ready, updated, available,
-		generation, observedGeneration,
-		generation == observedGeneration,
+		p.desired, p.ready, p.updated, p.available,
+		p.generation, p.observedGeneration,
+		p.synced(),
+		p.complete(),
 	)
 
 	conditions, found, _ := unstructured.NestedSlice(status, "conditions")
@@ -284,21 +340,29 @@ forces the controller to recreate the Pods using the controlled rollout
 strategy (no downtime if maxSurge / maxUnavailable are sane).
 
 Useful to pick up new images with the same tag, refresh secrets mounted
-as files, or clear a transient bad state without changing the spec.`),
+as files, or clear a transient bad state without changing the spec.
+
+The output reports how many Pods will be recreated. 'dry_run=true' only
+reports that number without restarting anything. 'wait=true' polls until
+the rollout completes (same criteria as 'get_rollout_status': every desired
+Pod updated and available, no old Pods left) or 'timeout_seconds' elapses.`),
 		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
 		mcp.WithString("group", mcp.Description("API group. Defaults to 'apps'.")),
 		mcp.WithString("version", mcp.Required(), mcp.Description("API version, typically 'v1'.")),
-		mcp.WithString("resource", mcp.Required(), mcp.Description("Lowercase plural: 'deployments', 'daemonsets', 'statefulsets'. NOT the Kind.")),
+		mcp.WithString("resource", mcp.Required(), mcp.Description("Lowercase plural: 'deployments', 'daemonsets', 'statefulsets'. NOT the Kind. Short names are accepted ('po', 'deploy', 'sts', ...).")),
 		mcp.WithString("name", mcp.Required(), mcp.Description("Name of the workload to restart.")),
 		mcp.WithString("namespace", mcp.Description("Namespace where the workload lives.")),
+		mcp.WithBoolean("dry_run", mcp.Description("Only report the Pods that would be recreated; do not restart. Defaults to false.")),
+		mcp.WithBoolean("wait", mcp.Description("Wait until the restart has rolled out to every Pod. Defaults to false.")),
+		mcp.WithNumber("timeout_seconds", mcp.Description("Maximum time to wait when 'wait=true'. Integer 1..600. Defaults to 300.")),
 	)
-	m.mcpServer.AddTool(tool, m.handleRestartRollout)
+	m.addWaitingTool(tool, m.handleRestartRollout, restartMaxTimeout)
 }
 
 func (m *Manager) handleRestartRollout(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
 
-	k8sContext := m.getContextParam(args)
+	k8sContext := m.getContextParam(ctx, args)
 	group, _ := args["group"].(string)
 	if group == "" {
 		group = "apps"
@@ -308,7 +372,7 @@ func (m *Manager) handleRestartRollout(ctx context.Context, request mcp.CallTool
 	name, _ := args["name"].(string)
 	namespace, _ := args["namespace"].(string)
 
-	gvr := schema.GroupVersionResource{Group: group, Version: version, Resource: resource}
+	gvr := m.expandShortName(k8sContext, schema.GroupVersionResource{Group: group, Version: version, Resource: resource})
 	if err := validateGVR(gvr); err != nil {
 		return errorResult(err), nil
 	}
@@ -319,6 +383,16 @@ func (m *Manager) handleRestartRollout(ctx context.Context, request mcp.CallTool
 		return errorResult(fmt.Errorf("restart_rollout is only supported for apps/{deployments,statefulsets,daemonsets}; got %s/%s", gvr.Group, gvr.Resource)), nil
 	}
 
+	dryRun, _ := args["dry_run"].(bool)
+	wait, _ := args["wait"].(bool)
+	timeout := restartDefaultTimeout
+	if v, ok := args["timeout_seconds"].(float64); ok {
+		if v < 1 || v > restartMaxTimeout.Seconds() {
+			return errorResult(fmt.Errorf("timeout_seconds must be between 1 and %d, got %v", int(restartMaxTimeout.Seconds()), v)), nil
+		}
+		timeout = time.Duration(v) * time.Second
+	}
+
 	// Check authorization
 	if err := m.checkAuthorization(request, "restart_rollout", k8sContext, namespace, authorization.ResourceInfo{
 		Group:    gvr.Group,
@@ -338,6 +412,19 @@ func (m *Manager) handleRestartRollout(ctx context.Context, request mcp.CallTool
 		return errorResult(err), nil
 	}
 
+	nsClient := client.DynamicClient.Resource(gvr).Namespace(namespace)
+
+	obj, err := nsClient.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return errorResult(err), nil
+	}
+	affected := readRolloutProgress(obj, gvr).desired
+
+	if dryRun {
+		return successResult(fmt.Sprintf("Dry run: restarting %s/%s would recreate %d pods. Nothing was changed.",
+			gvr.Resource, name, affected)), nil
+	}
+
 	// Patch with restart annotation
 	patch := map[string]any{
 		"spec": map[string]any{
@@ -356,13 +443,69 @@ func (m *Manager) handleRestartRollout(ctx context.Context, request mcp.CallTool
 		return errorResult(err), nil
 	}
 
-	_, err = client.DynamicClient.Resource(gvr).Namespace(namespace).Patch(
-		ctx, name, types.MergePatchType, patchBytes, metav1.PatchOptions{})
+	patched, err := nsClient.Patch(ctx, name, types.MergePatchType, patchBytes, metav1.PatchOptions{})
 	if err != nil {
 		return errorResult(err), nil
 	}
 
-	return successResult(fmt.Sprintf("Successfully triggered restart for %s/%s", gvr.Resource, name)), nil
+	triggered := fmt.Sprintf("Successfully triggered restart for %s/%s (%d pods will be recreated)", gvr.Resource, name, affected)
+	if !wait {
+		return successResult(triggered), nil
+	}
+
+	progress := m.newProgressReporter(ctx, request)
+	final, err := waitForRollout(ctx, nsClient, name, gvr, patched.GetGeneration(), timeout, progress)
+	if err != nil {
+		return errorResult(fmt.Errorf("%s, but %w", triggered, err)), nil
+	}
+
+	p := readRolloutProgress(final, gvr)
+	return successResult(fmt.Sprintf("Restart of %s/%s finished: %d/%d pods replaced\n\n%s",
+		gvr.Resource, name, p.updated, p.desired, formatRolloutStatus(final, gvr, name))), nil
+}
+
+const (
+	restartDefaultTimeout = 300 * time.Second
+	restartMaxTimeout     = 600 * time.Second
+)
+
+// rolloutPollInterval is how often waitForRollout re-reads the workload
+var rolloutPollInterval = 2 * time.Second
+
+// waitForRollout polls a workload until the controller has observed at least
+// 'generation' and the rollout is complete, returning the last object read.
+// Each poll is reported to 'progress' as updated out of desired replicas.
+func waitForRollout(ctx context.Context, nsClient dynamicResource, name string, gvr schema.GroupVersionResource,
+	generation int64, timeout time.Duration, progress *progressReporter) (*unstructured.Unstructured, error) {
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(rolloutPollInterval)
+	defer ticker.Stop()
+
+	var last *unstructured.Unstructured
+	for {
+		obj, err := nsClient.Get(ctx, name, metav1.GetOptions{})
+		if err == nil {
+			last = obj
+			p := readRolloutProgress(obj, gvr)
+			if p.observedGeneration >= generation && p.complete() {
+				return obj, nil
+			}
+			progress.Report(float64(p.updated), float64(p.desired),
+				fmt.Sprintf("waiting: %d/%d replicas updated, %d available", p.updated, p.desired, p.available))
+		}
+
+		select {
+		case <-ctx.Done():
+			if last == nil {
+				return nil, fmt.Errorf("the rollout did not complete within %s", timeout)
+			}
+			return nil, fmt.Errorf("the rollout did not complete within %s\n\n%s", timeout, formatRolloutStatus(last, gvr, name))
+		case <-ticker.C:
+		}
+	}
 }
 
 // rolloutSupportedResource reports whether a resource has a meaningful rollout
@@ -408,18 +551,18 @@ patch on 'spec.template'. Labels and selectors are not touched.`),
 		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
 		mcp.WithString("group", mcp.Description("API group. Must be 'apps' (default).")),
 		mcp.WithString("version", mcp.Required(), mcp.Description("API version, typically 'v1'.")),
-		mcp.WithString("resource", mcp.Required(), mcp.Description("One of 'deployments', 'statefulsets', 'daemonsets'. Lowercase plural, NOT the Kind.")),
+		mcp.WithString("resource", mcp.Required(), mcp.Description("One of 'deployments', 'statefulsets', 'daemonsets'. Lowercase plural, NOT the Kind. Short names are accepted ('po', 'deploy', 'sts', ...).")),
 		mcp.WithString("name", mcp.Required(), mcp.Description("Name of the workload to roll back.")),
 		mcp.WithString("namespace", mcp.Description("Namespace where the workload lives.")),
 		mcp.WithNumber("to_revision", mcp.Description("Specific revision number to roll back to. Omit or 0 to roll back to the revision immediately before the current one (kubectl-compatible default).")),
 	)
-	m.mcpServer.AddTool(tool, m.handleUndoRollout)
+	m.addTool(tool, m.handleUndoRollout)
 }
 
 func (m *Manager) handleUndoRollout(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
 
-	k8sContext := m.getContextParam(args)
+	k8sContext := m.getContextParam(ctx, args)
 	group, _ := args["group"].(string)
 	if group == "" {
 		group = "apps"
@@ -430,7 +573,7 @@ func (m *Manager) handleUndoRollout(ctx context.Context, request mcp.CallToolReq
 	namespace, _ := args["namespace"].(string)
 	toRevision, _ := args["to_revision"].(float64)
 
-	gvr := schema.GroupVersionResource{Group: group, Version: version, Resource: resource}
+	gvr := m.expandShortName(k8sContext, schema.GroupVersionResource{Group: group, Version: version, Resource: resource})
 	if err := validateGVR(gvr); err != nil {
 		return errorResult(err), nil
 	}
@@ -468,6 +611,247 @@ func (m *Manager) handleUndoRollout(ctx context.Context, request mcp.CallToolReq
 	}
 }
 
+func (m *Manager) registerRolloutHistory() {
+	tool := mcp.NewTool(m.toolName("rollout_history"),
+		mcp.WithDescription(`List the revisions of a Deployment ('kubectl rollout history'), newest first.
+
+Each revision is one ReplicaSet owned by the Deployment, reported with its
+revision number, creation time, replicas and the container images of its
+pod template, plus the 'kubernetes.io/change-cause' annotation when set.
+The revision marked 'current: true' is the one being served.
+
+Use it before 'undo_rollout' to pick a 'to_revision'.`),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Name of the Deployment.")),
+		mcp.WithString("namespace", mcp.Description("Namespace where the Deployment lives. Defaults to 'default' if empty.")),
+		mcp.WithArray("yq_expressions", mcp.Description("Optional yq expressions applied to the YAML output. Examples: '.revisions[] | {revision, images}', '.revisions[1].revision' (the previous revision).")),
+		jqExpressionsParam(),
+	)
+	m.addTool(tool, m.handleRolloutHistory)
+}
+
+func (m *Manager) handleRolloutHistory(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	name, _ := args["name"].(string)
+	if name == "" {
+		return errorResult(fmt.Errorf("name is required")), nil
+	}
+	namespace, _ := args["namespace"].(string)
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	gvr := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+
+	// Check authorization
+	if err := m.checkAuthorization(request, "rollout_history", k8sContext, namespace, authorization.ResourceInfo{
+		Group:    gvr.Group,
+		Version:  gvr.Version,
+		Resource: gvr.Resource,
+		Name:     name,
+	}); err != nil {
+		return errorResult(err), nil
+	}
+
+	if !m.clientManager.IsNamespaceAllowed(k8sContext, namespace) {
+		return errorResult(fmt.Errorf("namespace %s is not allowed in context %s", namespace, k8sContext)), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	deployment, err := client.DynamicClient.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return errorResult(err), nil
+	}
+	history, err := deploymentHistory(ctx, client, deployment)
+	if err != nil {
+		return errorResult(err), nil
+	}
+	if len(history) == 0 {
+		return errorResult(fmt.Errorf("no rollout history found for deployment %s/%s", namespace, name)), nil
+	}
+
+	current := nestedString(deployment.Object, "metadata", "annotations", "deployment.kubernetes.io/revision")
+	revisions := make([]map[string]any, 0, len(history))
+	for _, h := range history {
+		revisions = append(revisions, summarizeRevision(h, current))
+	}
+
+	yamlOutput, err := objectToYAML(map[string]any{
+		"deployment": name,
+		"namespace":  namespace,
+		"revisions":  revisions,
+	})
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	finalOutput, err := m.applyOutputFilters(yamlOutput, args)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	return successResult(finalOutput), nil
+}
+
+// summarizeRevision describes one ReplicaSet of a Deployment's history
+func summarizeRevision(h rsRevision, currentRevision string) map[string]any {
+	rs := h.obj
+	replicas, _, _ := unstructured.NestedInt64(rs.Object, "spec", "replicas")
+	ready, _, _ := unstructured.NestedInt64(rs.Object, "status", "readyReplicas")
+
+	images := []string{}
+	for _, field := range []string{"initContainers", "containers"} {
+		containers, _, _ := unstructured.NestedSlice(rs.Object, "spec", "template", "spec", field)
+		for _, c := range containers {
+			container, ok := c.(map[string]any)
+			if !ok {
+				continue
+			}
+			images = append(images, fmt.Sprintf("%s=%s", container["name"], container["image"]))
+		}
+	}
+
+	revision := map[string]any{
+		"revision":       h.revision,
+		"current":        strconv.FormatInt(h.revision, 10) == currentRevision,
+		"replicaset":     rs.GetName(),
+		"created":        rs.GetCreationTimestamp().UTC().Format("2006-01-02T15:04:05Z"),
+		"replicas":       replicas,
+		"ready_replicas": ready,
+		"images":         images,
+	}
+	if cause := rs.GetAnnotations()["kubernetes.io/change-cause"]; cause != "" {
+		revision["change_cause"] = cause
+	}
+	return revision
+}
+
+func (m *Manager) registerPauseRollout() {
+	tool := mcp.NewTool(m.toolName("pause_rollout"),
+		mcp.WithDescription(`Pause a Deployment rollout ('kubectl rollout pause') by setting 'spec.paused'.
+
+While paused, changes to the pod template are recorded but not rolled out,
+so several edits can be made and inspected before any Pod is replaced.
+Scaling still works. Resume with 'resume_rollout'.
+
+Only Deployments can be paused. Pausing an already paused Deployment is
+reported and changes nothing.`),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("group", mcp.Description("API group. Must be 'apps' (default).")),
+		mcp.WithString("version", mcp.Required(), mcp.Description("API version, typically 'v1'.")),
+		mcp.WithString("resource", mcp.Required(), mcp.Description("Must be 'deployments'. Lowercase plural, NOT the Kind. Short names are accepted ('deploy').")),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Name of the Deployment to pause.")),
+		mcp.WithString("namespace", mcp.Description("Namespace where the Deployment lives.")),
+	)
+	m.addTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return m.handleSetRolloutPaused(ctx, request, "pause_rollout", true)
+	})
+}
+
+func (m *Manager) registerResumeRollout() {
+	tool := mcp.NewTool(m.toolName("resume_rollout"),
+		mcp.WithDescription(`Resume a paused Deployment rollout ('kubectl rollout resume') by clearing 'spec.paused'.
+
+Any pod template change made while paused starts rolling out right away;
+follow it with 'get_rollout_status'.
+
+Only Deployments can be resumed. Resuming a Deployment that is not paused
+is reported and changes nothing.`),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("group", mcp.Description("API group. Must be 'apps' (default).")),
+		mcp.WithString("version", mcp.Required(), mcp.Description("API version, typically 'v1'.")),
+		mcp.WithString("resource", mcp.Required(), mcp.Description("Must be 'deployments'. Lowercase plural, NOT the Kind. Short names are accepted ('deploy').")),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Name of the Deployment to resume.")),
+		mcp.WithString("namespace", mcp.Description("Namespace where the Deployment lives.")),
+	)
+	m.addTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return m.handleSetRolloutPaused(ctx, request, "resume_rollout", false)
+	})
+}
+
+// handleSetRolloutPaused backs pause_rollout and resume_rollout: both set
+// 'spec.paused' on a Deployment with a merge patch, skipping the patch when
+// the Deployment is already in the requested state.
+func (m *Manager) handleSetRolloutPaused(ctx context.Context, request mcp.CallToolRequest, tool string, paused bool) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	group, _ := args["group"].(string)
+	if group == "" {
+		group = "apps"
+	}
+	version, _ := args["version"].(string)
+	resource, _ := args["resource"].(string)
+	name, _ := args["name"].(string)
+	namespace, _ := args["namespace"].(string)
+
+	gvr := m.expandShortName(k8sContext, schema.GroupVersionResource{Group: group, Version: version, Resource: resource})
+	if err := validateGVR(gvr); err != nil {
+		return errorResult(err), nil
+	}
+	if namespace == "" {
+		return errorResult(fmt.Errorf("namespace is required for %s", gvr.Resource)), nil
+	}
+	if gvr.Group != "apps" || gvr.Resource != "deployments" {
+		return errorResult(fmt.Errorf("%s is only supported for apps/deployments; got %s/%s", tool, gvr.Group, gvr.Resource)), nil
+	}
+
+	// Check authorization
+	if err := m.checkAuthorization(request, tool, k8sContext, namespace, authorization.ResourceInfo{
+		Group:    gvr.Group,
+		Version:  gvr.Version,
+		Resource: gvr.Resource,
+		Name:     name,
+	}); err != nil {
+		return errorResult(err), nil
+	}
+
+	if !m.clientManager.IsNamespaceAllowed(k8sContext, namespace) {
+		return errorResult(fmt.Errorf("namespace %s is not allowed in context %s", namespace, k8sContext)), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	nsClient := client.DynamicClient.Resource(gvr).Namespace(namespace)
+
+	obj, err := nsClient.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	state := "resumed"
+	if paused {
+		state = "paused"
+	}
+	wasPaused, _, _ := unstructured.NestedBool(obj.Object, "spec", "paused")
+	if wasPaused == paused {
+		return successResult(fmt.Sprintf("%s/%s is already %s; nothing was changed", gvr.Resource, name, state)), nil
+	}
+
+	patchBytes, err := json.Marshal(map[string]any{
+		"spec": map[string]any{"paused": paused},
+	})
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	if _, err := nsClient.Patch(ctx, name, types.MergePatchType, patchBytes, metav1.PatchOptions{}); err != nil {
+		return errorResult(err), nil
+	}
+
+	return successResult(fmt.Sprintf("Successfully %s rollout of %s/%s (paused: %t -> %t)",
+		state, gvr.Resource, name, wasPaused, paused)), nil
+}
+
 // Annotations preserved on the Deployment when rolling back: kubectl propagates
 // these from the current object instead of restoring them from the target RS.
 // Mirrors kubectl/pkg/polymorphichelpers/rollback.go:annotationsToSkip.
@@ -519,43 +903,14 @@ func (m *Manager) undoDeploymentRollout(
 		}
 	}
 
-	rsGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}
-	rsList, err := client.DynamicClient.Resource(rsGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	history, err := deploymentHistory(ctx, client, deployment)
 	if err != nil {
 		return errorResult(err), nil
 	}
-
-	type rsRevision struct {
-		revision int64
-		obj      *unstructured.Unstructured
-	}
-
-	deploymentUID := nestedString(deployment.Object, "metadata", "uid")
-
-	var history []rsRevision
-	for i := range rsList.Items {
-		item := &rsList.Items[i]
-		if !ownedBy(item.Object, deploymentUID) {
-			continue
-		}
-		revStr := nestedString(item.Object, "metadata", "annotations", "deployment.kubernetes.io/revision")
-		if revStr == "" {
-			continue
-		}
-		rev, err := strconv.ParseInt(revStr, 10, 64)
-		if err != nil {
-			continue
-		}
-		history = append(history, rsRevision{revision: rev, obj: item})
-	}
-
 	if len(history) == 0 {
 		return errorResult(fmt.Errorf("no rollout history found for deployment %s/%s", namespace, name)), nil
 	}
 
-	// Sort newest first.
-	sort.Slice(history, func(i, j int) bool { return history[i].revision > history[j].revision })
-
 	// If the deployment annotation was missing/unparseable, treat the highest
 	// revision as the current one (kubectl-compatible fallback).
 	effectiveCurrent := history[0].revision
@@ -723,6 +1078,46 @@ func (m *Manager) undoControllerRevisionRollout(
 	return successResult(fmt.Sprintf("Successfully rolled back %s %s/%s to revision %d", gvr.Resource, namespace, name, target.revision)), nil
 }
 
+// rsRevision is a ReplicaSet of a Deployment with its revision number
+type rsRevision struct {
+	revision int64
+	obj      *unstructured.Unstructured
+}
+
+// deploymentHistory returns the ReplicaSets owned by a Deployment that carry
+// a 'deployment.kubernetes.io/revision' annotation, newest first. Shared by
+// undo_rollout and rollout_history so both see the same revisions.
+func deploymentHistory(ctx context.Context, client *kubernetes.Client, deployment *unstructured.Unstructured) ([]rsRevision, error) {
+	rsGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}
+	rsList, err := client.DynamicClient.Resource(rsGVR).Namespace(deployment.GetNamespace()).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	deploymentUID := nestedString(deployment.Object, "metadata", "uid")
+
+	var history []rsRevision
+	for i := range rsList.Items {
+		item := &rsList.Items[i]
+		if !ownedBy(item.Object, deploymentUID) {
+			continue
+		}
+		revStr := nestedString(item.Object, "metadata", "annotations", "deployment.kubernetes.io/revision")
+		if revStr == "" {
+			continue
+		}
+		rev, err := strconv.ParseInt(revStr, 10, 64)
+		if err != nil {
+			continue
+		}
+		history = append(history, rsRevision{revision: rev, obj: item})
+	}
+
+	// Sort newest first.
+	sort.Slice(history, func(i, j int) bool { return history[i].revision > history[j].revision })
+	return history, nil
+}
+
 // pickTargetRevision chooses the revision to roll back to.
 // 'history' must already be sorted by revision DESCENDING.
 //
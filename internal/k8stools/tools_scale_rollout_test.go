@@ -0,0 +1,365 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func fakeDeployment(namespace, name string, replicas int32) *appsv1.Deployment {
+	labels := map[string]string{"app": name}
+	return &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "app", Image: "nginx:1.27"}},
+				},
+			},
+		},
+	}
+}
+
+func TestScaleResource(t *testing.T) {
+	e := newFakeEnv(t, fakeDeployment("default", "web", 1))
+
+	res, err := e.manager.handleScaleResource(context.Background(), makeRequest(map[string]any{
+		"version":   "v1",
+		"resource":  "deployments",
+		"namespace": "default",
+		"name":      "web",
+		"replicas":  float64(3),
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "scale_resource")
+	requireContains(t, out, "Successfully scaled deployments/web to 3 replicas", "expected summary line")
+	requireContains(t, out, "replicas: 3", "expected new replicas in YAML")
+
+	obj, err := e.dynamic.Resource(gvrOf("apps", "v1", "deployments")).Namespace("default").Get(context.Background(), "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get scaled deployment: %v", err)
+	}
+	replicas, _, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	if replicas != 3 {
+		t.Fatalf("expected 3 replicas in the store, got %d", replicas)
+	}
+}
+
+func TestScaleResource_ShortName(t *testing.T) {
+	e := newFakeEnv(t, fakeDeployment("default", "web", 1))
+	e.serveShortNames()
+
+	res, err := e.manager.handleScaleResource(context.Background(), makeRequest(map[string]any{
+		"version":   "v1",
+		"resource":  "deploy",
+		"namespace": "default",
+		"name":      "web",
+		"replicas":  float64(2),
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	requireContains(t, expectOK(t, res, "scale_resource deploy"), "Successfully scaled deployments/web to 2 replicas", "expected the short name to expand")
+}
+
+func TestScaleResource_Errors(t *testing.T) {
+	e := newFakeEnv(t, fakeDeployment("default", "web", 1))
+
+	tests := []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{
+			name: "daemonsets are rejected",
+			args: map[string]any{"resource": "daemonsets", "namespace": "default", "name": "web", "replicas": float64(2)},
+			want: "only supported for apps/{deployments,statefulsets,replicasets}",
+		},
+		{
+			name: "negative replicas",
+			args: map[string]any{"resource": "deployments", "namespace": "default", "name": "web", "replicas": float64(-1)},
+			want: "replicas must be a non-negative integer",
+		},
+		{
+			name: "fractional replicas",
+			args: map[string]any{"resource": "deployments", "namespace": "default", "name": "web", "replicas": float64(1.5)},
+			want: "replicas must be a non-negative integer",
+		},
+		{
+			name: "missing namespace",
+			args: map[string]any{"resource": "deployments", "name": "web", "replicas": float64(2)},
+			want: "namespace is required",
+		},
+		{
+			name: "not found",
+			args: map[string]any{"resource": "deployments", "namespace": "default", "name": "missing", "replicas": float64(2)},
+			want: "not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := map[string]any{"version": "v1"}
+			for k, v := range tt.args {
+				args[k] = v
+			}
+			res, err := e.manager.handleScaleResource(context.Background(), makeRequest(args))
+			if err != nil {
+				t.Fatalf("go-error: %v", err)
+			}
+			text := expectErr(t, res, tt.name)
+			requireContains(t, text, tt.want, "unexpected error text")
+		})
+	}
+}
+
+// rolledOut marks a Deployment's status as fully converged
+func rolledOut(d *appsv1.Deployment) *appsv1.Deployment {
+	n := *d.Spec.Replicas
+	d.Status = appsv1.DeploymentStatus{
+		ObservedGeneration: d.Generation,
+		Replicas:           n,
+		UpdatedReplicas:    n,
+		ReadyReplicas:      n,
+		AvailableReplicas:  n,
+	}
+	return d
+}
+
+func restartAnnotation(t *testing.T, e *fakeEnv) string {
+	t.Helper()
+	obj, err := e.dynamic.Resource(gvrOf("apps", "v1", "deployments")).Namespace("default").Get(context.Background(), "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get deployment: %v", err)
+	}
+	value, _, _ := unstructured.NestedString(obj.Object, "spec", "template", "metadata", "annotations", "kubectl.kubernetes.io/restartedAt")
+	return value
+}
+
+func TestRestartRollout_DryRun(t *testing.T) {
+	e := newFakeEnv(t, fakeDeployment("default", "web", 3))
+
+	res, err := e.manager.handleRestartRollout(context.Background(), makeRequest(map[string]any{
+		"version":   "v1",
+		"resource":  "deployments",
+		"namespace": "default",
+		"name":      "web",
+		"dry_run":   true,
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "restart_rollout dry_run")
+	requireContains(t, out, "would recreate 3 pods", "expected affected pod count")
+	if restartAnnotation(t, e) != "" {
+		t.Fatalf("dry run must not patch the workload")
+	}
+}
+
+func TestRestartRollout_Wait(t *testing.T) {
+	e := newFakeEnv(t, rolledOut(fakeDeployment("default", "web", 3)))
+
+	res, err := e.manager.handleRestartRollout(context.Background(), makeRequest(map[string]any{
+		"version":   "v1",
+		"resource":  "deployments",
+		"namespace": "default",
+		"name":      "web",
+		"wait":      true,
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "restart_rollout wait")
+	requireContains(t, out, "3/3 pods replaced", "expected completion summary")
+	requireContains(t, out, "Complete:   true", "expected rollout status")
+	if restartAnnotation(t, e) == "" {
+		t.Fatalf("expected the restart annotation to be set")
+	}
+}
+
+func TestRestartRollout_WaitTimeout(t *testing.T) {
+	interval := rolloutPollInterval
+	rolloutPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { rolloutPollInterval = interval })
+
+	// Status never converges: one old Pod is still around
+	deployment := rolledOut(fakeDeployment("default", "web", 3))
+	deployment.Status.Replicas = 4
+	e := newFakeEnv(t, deployment)
+
+	res, err := e.manager.handleRestartRollout(context.Background(), makeRequest(map[string]any{
+		"version":         "v1",
+		"resource":        "deployments",
+		"namespace":       "default",
+		"name":            "web",
+		"wait":            true,
+		"timeout_seconds": float64(1),
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectErr(t, res, "restart_rollout wait timeout")
+	requireContains(t, out, "Successfully triggered restart", "expected the restart to be reported")
+	requireContains(t, out, "did not complete within 1s", "expected timeout")
+}
+
+// revisionOf returns a ReplicaSet of 'd' at the given revision, running 'image'
+func revisionOf(d *appsv1.Deployment, revision, image string, replicas int32) *appsv1.ReplicaSet {
+	controller := true
+	template := *d.Spec.Template.DeepCopy()
+	template.Spec.Containers[0].Image = image
+	return &appsv1.ReplicaSet{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "ReplicaSet"},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       d.Namespace,
+			Name:            d.Name + "-" + revision,
+			Annotations:     map[string]string{"deployment.kubernetes.io/revision": revision},
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: d.Name, UID: d.UID, Controller: &controller}},
+		},
+		Spec: appsv1.ReplicaSetSpec{Replicas: &replicas, Template: template},
+	}
+}
+
+func TestRolloutHistory(t *testing.T) {
+	d := fakeDeployment("default", "web", 2)
+	d.UID = "uid-web"
+	d.Annotations = map[string]string{"deployment.kubernetes.io/revision": "3"}
+	other := fakeDeployment("default", "api", 1)
+	other.UID = "uid-api"
+
+	rev2 := revisionOf(d, "2", "nginx:1.26", 0)
+	rev2.Annotations["kubernetes.io/change-cause"] = "bump to 1.26"
+	e := newFakeEnv(t, d, other,
+		revisionOf(d, "1", "nginx:1.25", 0),
+		rev2,
+		revisionOf(d, "3", "nginx:1.27", 2),
+		revisionOf(other, "1", "api:v1", 1),
+	)
+
+	res, err := e.manager.handleRolloutHistory(context.Background(), makeRequest(map[string]any{
+		"name":           "web",
+		"yq_expressions": []any{`[.revisions[] | (.revision | tostring) + ":" + (.current | tostring) + ":" + .images[0]] | join(",")`},
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := strings.TrimSpace(expectOK(t, res, "rollout_history"))
+	if out != "3:true:app=nginx:1.27,2:false:app=nginx:1.26,1:false:app=nginx:1.25" {
+		t.Fatalf("unexpected history: %s", out)
+	}
+
+	res, err = e.manager.handleRolloutHistory(context.Background(), makeRequest(map[string]any{"name": "web"}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	requireContains(t, expectOK(t, res, "rollout_history"), "change_cause: bump to 1.26", "expected the change cause")
+
+	res, err = e.manager.handleRolloutHistory(context.Background(), makeRequest(map[string]any{"name": "missing"}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	requireContains(t, expectErr(t, res, "missing deployment"), "not found", "expected not found error")
+}
+
+func TestPauseResumeRollout(t *testing.T) {
+	e := newFakeEnv(t, fakeDeployment("default", "web", 2))
+	e.serveShortNames()
+	paused := func() bool {
+		t.Helper()
+		obj, err := e.dynamic.Resource(gvrOf("apps", "v1", "deployments")).Namespace("default").Get(context.Background(), "web", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("get deployment: %v", err)
+		}
+		value, _, _ := unstructured.NestedBool(obj.Object, "spec", "paused")
+		return value
+	}
+	call := func(tool string, pause bool, args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		res, err := e.manager.handleSetRolloutPaused(context.Background(), makeRequest(args), tool, pause)
+		if err != nil {
+			t.Fatalf("go-error: %v", err)
+		}
+		return res
+	}
+	web := map[string]any{"version": "v1", "resource": "deploy", "namespace": "default", "name": "web"}
+
+	requireContains(t, expectOK(t, call("pause_rollout", true, web), "pause"), "paused: false -> true", "expected transition")
+	if !paused() {
+		t.Fatalf("expected spec.paused=true")
+	}
+	requireContains(t, expectOK(t, call("pause_rollout", true, web), "pause again"), "already paused", "expected no-op")
+
+	requireContains(t, expectOK(t, call("resume_rollout", false, web), "resume"), "paused: true -> false", "expected transition")
+	if paused() {
+		t.Fatalf("expected spec.paused=false")
+	}
+
+	out := expectErr(t, call("pause_rollout", true, map[string]any{
+		"version": "v1", "resource": "statefulsets", "namespace": "default", "name": "web",
+	}), "statefulset")
+	requireContains(t, out, "only supported for apps/deployments", "expected kind error")
+
+	e.provider.deniedNamespaces = []string{"default"}
+	requireContains(t, expectErr(t, call("resume_rollout", false, web), "denied"), "is not allowed", "expected namespace error")
+}
+
+func TestUndoRollout_PreviousRevision(t *testing.T) {
+	d := fakeDeployment("default", "web", 2)
+	d.UID = "uid-web"
+	d.Annotations = map[string]string{"deployment.kubernetes.io/revision": "3"}
+	e := newFakeEnv(t, d,
+		revisionOf(d, "1", "nginx:1.25", 0),
+		revisionOf(d, "3", "nginx:1.27", 2),
+		revisionOf(d, "2", "nginx:1.26", 0),
+	)
+
+	res, err := e.manager.handleUndoRollout(context.Background(), makeRequest(map[string]any{
+		"version":   "v1",
+		"resource":  "deployments",
+		"namespace": "default",
+		"name":      "web",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	requireContains(t, expectOK(t, res, "undo_rollout"), "to revision 2", "expected the previous revision")
+
+	obj, err := e.dynamic.Resource(gvrOf("apps", "v1", "deployments")).Namespace("default").Get(context.Background(), "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get deployment: %v", err)
+	}
+	containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+	if image := containers[0].(map[string]any)["image"]; image != "nginx:1.26" {
+		t.Fatalf("expected the template of revision 2, got image %v", image)
+	}
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"kubernetes-mcp/internal/authorization"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func (m *Manager) registerSetImage() {
+	tool := mcp.NewTool(m.toolName("set_image"),
+		mcp.WithDescription(`Change the image of ONE container of a workload ('kubectl set image'),
+without writing a patch. Prefer this over 'patch_resource' to bump an image.
+
+Reads the live object, finds the container by name in the pod template
+and sends a strategic merge patch that only touches that container's
+'image'; every other field is left as is. Returns the old and new image.
+
+Works on Deployments (default), StatefulSets, DaemonSets, ReplicaSets,
+Jobs, CronJobs (their 'jobTemplate') and bare Pods. CRDs don't support
+strategic merge patch: use 'patch_list_element' for those.`),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("group", mcp.Description("API group. Defaults to 'apps'. Empty string \"\" for Pods, 'batch' for Jobs and CronJobs.")),
+		mcp.WithString("version", mcp.Description("API version. Defaults to 'v1'.")),
+		mcp.WithString("resource", mcp.Description("Lowercase plural: 'deployments' (default), 'statefulsets', 'daemonsets', 'replicasets', 'jobs', 'cronjobs', 'pods'. NOT the Kind. Short names are accepted ('deploy', 'sts', ...).")),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Name of the workload.")),
+		mcp.WithString("namespace", mcp.Description("Namespace where the workload lives.")),
+		mcp.WithString("container", mcp.Required(), mcp.Description("Name of the container whose image changes.")),
+		mcp.WithString("image", mcp.Required(), mcp.Description("New image reference, e.g. 'nginx:1.27' or 'registry.example.com/app@sha256:...'.")),
+		mcp.WithBoolean("init_container", mcp.Description("Look the container up in 'initContainers' instead of 'containers'. Defaults to false.")),
+	)
+	m.addTool(tool, m.handleSetImage)
+}
+
+func (m *Manager) handleSetImage(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	group, ok := args["group"].(string)
+	if !ok {
+		group = "apps"
+	}
+	version, _ := args["version"].(string)
+	if version == "" {
+		version = "v1"
+	}
+	resource, _ := args["resource"].(string)
+	if resource == "" {
+		resource = "deployments"
+	}
+	name, _ := args["name"].(string)
+	namespace, _ := args["namespace"].(string)
+	container, _ := args["container"].(string)
+	image, _ := args["image"].(string)
+	initContainer, _ := args["init_container"].(bool)
+
+	gvr := m.expandShortName(k8sContext, schema.GroupVersionResource{Group: group, Version: version, Resource: resource})
+	if err := validateGVR(gvr); err != nil {
+		return errorResult(err), nil
+	}
+	if name == "" {
+		return errorResult(fmt.Errorf("name is required")), nil
+	}
+	if namespace == "" {
+		return errorResult(fmt.Errorf("namespace is required for %s", gvr.Resource)), nil
+	}
+	if container == "" {
+		return errorResult(fmt.Errorf("container is required")), nil
+	}
+	if image == "" {
+		return errorResult(fmt.Errorf("image is required")), nil
+	}
+	podSpecPath, ok := podSpecPath(gvr)
+	if !ok {
+		return errorResult(fmt.Errorf("set_image does not support %s/%s; use 'patch_list_element' on spec.template.spec.containers instead", gvr.Group, gvr.Resource)), nil
+	}
+
+	// Check authorization
+	if err := m.checkAuthorization(request, "set_image", k8sContext, namespace, authorization.ResourceInfo{
+		Group:    gvr.Group,
+		Version:  gvr.Version,
+		Resource: gvr.Resource,
+		Name:     name,
+	}); err != nil {
+		return errorResult(err), nil
+	}
+
+	if !m.clientManager.IsNamespaceAllowed(k8sContext, namespace) {
+		return errorResult(fmt.Errorf("namespace %s is not allowed in context %s", namespace, k8sContext)), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	nsClient := client.DynamicClient.Resource(gvr).Namespace(namespace)
+
+	live, err := nsClient.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	field := "containers"
+	if initContainer {
+		field = "initContainers"
+	}
+	oldImage, err := containerImage(live.Object, podSpecPath, field, container)
+	if err != nil {
+		return errorResult(fmt.Errorf("%s/%s: %w", gvr.Resource, name, err)), nil
+	}
+	if oldImage == image {
+		return successResult(fmt.Sprintf("Container %q of %s/%s already runs %s; nothing was changed",
+			container, gvr.Resource, name, image)), nil
+	}
+
+	// Containers merge by name, so a one-element list with only name and
+	// image touches nothing else in the pod template.
+	var patch any = map[string]any{
+		field: []any{map[string]any{"name": container, "image": image}},
+	}
+	for i := len(podSpecPath) - 1; i >= 0; i-- {
+		patch = map[string]any{podSpecPath[i]: patch}
+	}
+	patchBytes, err := json.Marshal(patch)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	if _, err := nsClient.Patch(ctx, name, types.StrategicMergePatchType, patchBytes, metav1.PatchOptions{}); err != nil {
+		return errorResult(err), nil
+	}
+
+	return successResult(fmt.Sprintf("Successfully updated container %q of %s/%s: %s -> %s",
+		container, gvr.Resource, name, oldImage, image)), nil
+}
+
+// podSpecPath returns where the pod spec lives in a workload, or false for
+// resources that don't carry one.
+func podSpecPath(gvr schema.GroupVersionResource) ([]string, bool) {
+	switch {
+	case gvr.Group == "" && gvr.Resource == "pods":
+		return []string{"spec"}, true
+	case gvr.Group == "apps" && (gvr.Resource == "deployments" || gvr.Resource == "statefulsets" ||
+		gvr.Resource == "daemonsets" || gvr.Resource == "replicasets"):
+		return []string{"spec", "template", "spec"}, true
+	case gvr.Group == "batch" && gvr.Resource == "jobs":
+		return []string{"spec", "template", "spec"}, true
+	case gvr.Group == "batch" && gvr.Resource == "cronjobs":
+		return []string{"spec", "jobTemplate", "spec", "template", "spec"}, true
+	}
+	return nil, false
+}
+
+// containerImage returns the image of the named container in the list
+// 'field' of the pod spec at 'path', listing the available names when the
+// container is not there.
+func containerImage(obj map[string]any, path []string, field, container string) (string, error) {
+	containers, _, _ := unstructured.NestedSlice(obj, append(path, field)...)
+	var names []string
+	for _, c := range containers {
+		entry, ok := c.(map[string]any)
+		if !ok {
+			continue
+		}
+		n, _ := entry["name"].(string)
+		if n == container {
+			image, _ := entry["image"].(string)
+			return image, nil
+		}
+		names = append(names, n)
+	}
+	if len(names) == 0 {
+		return "", fmt.Errorf("no %s in the pod template", field)
+	}
+	return "", fmt.Errorf("container %q not found in %s (available: %v)", container, field, names)
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestSetImage(t *testing.T) {
+	d := fakeDeployment("default", "web", 2)
+	d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers,
+		corev1.Container{Name: "sidecar", Image: "envoy:1.30"})
+	d.Spec.Template.Spec.InitContainers = []corev1.Container{{Name: "migrate", Image: "migrate:v1"}}
+	e := newFakeEnv(t, d)
+
+	images := func(field string) []string {
+		t.Helper()
+		obj, err := e.dynamic.Resource(gvrOf("apps", "v1", "deployments")).Namespace("default").Get(context.Background(), "web", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("get deployment: %v", err)
+		}
+		containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", field)
+		var out []string
+		for _, c := range containers {
+			out = append(out, c.(map[string]any)["image"].(string))
+		}
+		return out
+	}
+	call := func(args map[string]any) string {
+		t.Helper()
+		args["name"] = "web"
+		args["namespace"] = "default"
+		res, err := e.manager.handleSetImage(context.Background(), makeRequest(args))
+		if err != nil {
+			t.Fatalf("go-error: %v", err)
+		}
+		if res.IsError {
+			return expectErr(t, res, "set_image")
+		}
+		return expectOK(t, res, "set_image")
+	}
+
+	out := call(map[string]any{"container": "app", "image": "nginx:1.28"})
+	requireContains(t, out, "nginx:1.27 -> nginx:1.28", "expected old and new image")
+	if got := images("containers"); len(got) != 2 || got[0] != "nginx:1.28" || got[1] != "envoy:1.30" {
+		t.Fatalf("expected only the app image to change, got %v", got)
+	}
+
+	out = call(map[string]any{"container": "migrate", "image": "migrate:v2", "init_container": true})
+	requireContains(t, out, "migrate:v1 -> migrate:v2", "expected the init container to change")
+	if got := images("initContainers"); len(got) != 1 || got[0] != "migrate:v2" {
+		t.Fatalf("unexpected init containers: %v", got)
+	}
+
+	requireContains(t, call(map[string]any{"container": "app", "image": "nginx:1.28"}), "nothing was changed", "expected no-op")
+	requireContains(t, call(map[string]any{"container": "db", "image": "postgres:16"}), "available: [app sidecar]", "expected container names")
+	requireContains(t, call(map[string]any{"group": "", "resource": "services", "container": "app", "image": "x"}), "does not support", "expected unsupported resource")
+}
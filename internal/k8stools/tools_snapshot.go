@@ -0,0 +1,194 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"kubernetes-mcp/internal/authorization"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+// restoreFieldManager owns the fields written by restore_resource
+const restoreFieldManager = "kubernetes-mcp-restore"
+
+func (m *Manager) registerSnapshotResource() {
+	tool := mcp.NewTool(m.toolName("snapshot_resource"),
+		mcp.WithDescription(`Capture ONE Kubernetes resource so it can be restored later with
+'restore_resource': a lightweight undo before a risky change, for any kind.
+
+Returns the object as YAML without its server-managed fields (status,
+resourceVersion, uid, managedFields, ownerReferences, finalizers, ...).
+The server keeps nothing: store the snapshot yourself and pass it back
+unchanged to 'restore_resource'.
+
+Refused when redaction ('kubernetes.tools.redaction') would mask fields of
+the object, e.g. Secret data: restoring the masks would destroy the values.`),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("group", mcp.Description("API group. Empty string \"\" for the core API. Examples: 'apps', 'networking.k8s.io'.")),
+		mcp.WithString("version", mcp.Description("API version, e.g. 'v1'. If empty or not served for the group, the preferred version is used.")),
+		mcp.WithString("resource", mcp.Required(), mcp.Description("Resource name in the API sense: lowercase plural ('configmaps', 'deployments'). Short names are accepted.")),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Name of the resource instance.")),
+		mcp.WithString("namespace", mcp.Description("Namespace of the resource. Required for namespaced resources.")),
+	)
+	m.addTool(tool, m.handleSnapshotResource)
+}
+
+func (m *Manager) handleSnapshotResource(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	name, _ := args["name"].(string)
+	namespace, _ := args["namespace"].(string)
+	gvr, err := m.resolveGVRVersion(k8sContext, m.expandShortName(k8sContext, gvrFromArgs(args)))
+	if err != nil {
+		return errorResult(err), nil
+	}
+	if err := validateGVR(gvr); err != nil {
+		return errorResult(err), nil
+	}
+
+	// Check authorization
+	if err := m.checkAuthorization(request, "snapshot_resource", k8sContext, namespace, authorization.ResourceInfo{
+		Group:    gvr.Group,
+		Version:  gvr.Version,
+		Resource: gvr.Resource,
+		Name:     name,
+	}); err != nil {
+		return errorResult(err), nil
+	}
+
+	if namespace != "" && !m.clientManager.IsNamespaceAllowed(k8sContext, namespace) {
+		return errorResult(fmt.Errorf("namespace %s is not allowed in context %s", namespace, k8sContext)), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	var live *unstructured.Unstructured
+	if namespace != "" {
+		live, err = client.DynamicClient.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	} else {
+		live, err = client.DynamicClient.Resource(gvr).Get(ctx, name, metav1.GetOptions{})
+	}
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	snapshot, err := objectToYAML(stripServerManagedFields(live.Object))
+	if err != nil {
+		return errorResult(err), nil
+	}
+	if m.redactYAML(snapshot) != snapshot {
+		return errorResult(fmt.Errorf("cannot snapshot %s/%s: redaction masks some of its fields, and restoring the masks would overwrite the real values",
+			live.GetKind(), name)), nil
+	}
+
+	return successResult(fmt.Sprintf("Snapshot of %s/%s (resourceVersion %s). Pass the YAML below unchanged as 'snapshot' to 'restore_resource' to restore it.\n\n%s",
+		live.GetKind(), name, live.GetResourceVersion(), snapshot)), nil
+}
+
+func (m *Manager) registerRestoreResource() {
+	tool := mcp.NewTool(m.toolName("restore_resource"),
+		mcp.WithDescription(`Restore ONE Kubernetes resource from a snapshot taken with 'snapshot_resource'.
+
+The snapshot is re-applied with server-side apply, forcing ownership of its
+fields: every field it holds gets its snapshot value back, and an object
+deleted since is created again. Fields added after the snapshot by someone
+else are left in place; use 'replace_resource' for an exact replacement.
+
+The resource type is resolved from the snapshot's 'apiVersion' / 'kind'
+through discovery.`),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("snapshot", mcp.Required(), mcp.Description("The YAML returned by 'snapshot_resource', unchanged.")),
+		mcp.WithString("namespace", mcp.Description("Namespace override, to restore into another namespace. Defaults to the snapshot's namespace.")),
+		mcp.WithBoolean("dry_run", mcp.Description("Server-side dry run: validate and admit the restore and return the object that would be stored, without persisting anything. Defaults to false.")),
+	)
+	m.addTool(tool, m.handleRestoreResource)
+}
+
+func (m *Manager) handleRestoreResource(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	snapshot, _ := args["snapshot"].(string)
+	namespaceOverride, _ := args["namespace"].(string)
+	dryRun, _ := args["dry_run"].(bool)
+
+	if isMultiDocumentYAML(snapshot) {
+		return errorResult(fmt.Errorf("restore_resource takes the snapshot of a single object")), nil
+	}
+
+	parsed := map[string]any{}
+	if err := yaml.Unmarshal([]byte(snapshot), &parsed); err != nil {
+		return errorResult(fmt.Errorf("failed to parse snapshot: %w", err)), nil
+	}
+	// A live object pasted as a snapshot would fail to apply on its
+	// resourceVersion or uid
+	obj := &unstructured.Unstructured{Object: stripServerManagedFields(parsed)}
+	if err := validateManifestObject(obj); err != nil {
+		return errorResult(err), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	target, err := m.resolveManifestTarget(request, "restore_resource", k8sContext, client, obj, namespaceOverride)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	data, err := json.Marshal(obj.Object)
+	if err != nil {
+		return errorResult(err), nil
+	}
+	force := true
+	opts := metav1.PatchOptions{FieldManager: restoreFieldManager, Force: &force, DryRun: dryRunOption(dryRun)}
+	resourceClient := client.DynamicClient.Resource(target.gvr)
+	var restored *unstructured.Unstructured
+	if target.namespace != "" {
+		restored, err = resourceClient.Namespace(target.namespace).Patch(ctx, obj.GetName(), types.ApplyPatchType, data, opts)
+	} else {
+		restored, err = resourceClient.Patch(ctx, obj.GetName(), types.ApplyPatchType, data, opts)
+	}
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	yamlOutput, err := objectToYAML(restored)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	if dryRun {
+		return successResult(fmt.Sprintf("%s %s/%s would be restored in namespace %s; the API server would store:\n\n%s",
+			dryRunNote, obj.GetKind(), obj.GetName(), target.namespace, m.redactYAML(yamlOutput))), nil
+	}
+	return successResult(fmt.Sprintf("Successfully restored %s/%s in namespace %s (resourceVersion %s)\n\n%s",
+		obj.GetKind(), obj.GetName(), target.namespace, restored.GetResourceVersion(), m.redactYAML(yamlOutput))), nil
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"kubernetes-mcp/api"
+	"kubernetes-mcp/internal/redaction"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestSnapshotAndRestoreResource(t *testing.T) {
+	cm := fakeConfigMap("default", "settings", map[string]string{"level": "info"})
+	cm.UID = "1234"
+	cm.ResourceVersion = "42"
+	e := newFakeEnv(t, cm)
+	ctx := context.Background()
+
+	res, err := e.manager.handleSnapshotResource(ctx, makeRequest(map[string]any{
+		"version":   "v1",
+		"resource":  "configmaps",
+		"namespace": "default",
+		"name":      "settings",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "snapshot_resource")
+	requireContains(t, out, "level: info", "expected data in the snapshot")
+	snapshot := out[strings.Index(out, "\n\n")+2:]
+	for _, field := range []string{"uid:", "resourceVersion:"} {
+		if strings.Contains(snapshot, field) {
+			t.Errorf("expected %s to be stripped from the snapshot:\n%s", field, snapshot)
+		}
+	}
+
+	configmaps := e.dynamic.Resource(gvrOf("", "v1", "configmaps")).Namespace("default")
+	live, err := configmaps.Get(ctx, "settings", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get configmap: %v", err)
+	}
+	live.Object["data"] = map[string]any{"level": "debug"}
+	if _, err := configmaps.Update(ctx, live, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("update configmap: %v", err)
+	}
+
+	// The fake client cannot apply onto unstructured objects: store the
+	// applied configuration as is, which is what a forced apply of a full
+	// snapshot converges to
+	e.dynamic.PrependReactor("patch", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patch := action.(k8stesting.PatchActionImpl)
+		if patch.GetPatchType() != types.ApplyPatchType {
+			t.Fatalf("expected a server-side apply, got %s", patch.GetPatchType())
+		}
+		if patch.PatchOptions.Force == nil || !*patch.PatchOptions.Force || patch.PatchOptions.FieldManager != restoreFieldManager {
+			t.Fatalf("expected a forced apply by %s, got %+v", restoreFieldManager, patch.PatchOptions)
+		}
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(patch.GetPatch()); err != nil {
+			t.Fatalf("decode applied configuration: %v", err)
+		}
+		if err := e.dynamic.Tracker().Update(patch.GetResource(), obj, patch.GetNamespace()); err != nil {
+			return true, nil, err
+		}
+		return true, obj, nil
+	})
+	res, err = e.manager.handleRestoreResource(ctx, makeRequest(map[string]any{"snapshot": snapshot}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out = expectOK(t, res, "restore_resource")
+	requireContains(t, out, "Successfully restored ConfigMap/settings in namespace default", "expected summary line")
+
+	restored, err := configmaps.Get(ctx, "settings", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get restored configmap: %v", err)
+	}
+	if got := restored.Object["data"].(map[string]any)["level"]; got != "info" {
+		t.Fatalf("expected level=info after restore, got %v", got)
+	}
+}
+
+func TestSnapshotResource_Redacted(t *testing.T) {
+	e := newFakeEnv(t, &corev1.Secret{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "creds"},
+		Data:       map[string][]byte{"password": []byte("hunter2")},
+	})
+	e.manager.redactor = redaction.NewRedactor(api.RedactionConfig{Enabled: true, SecretData: true})
+
+	res, err := e.manager.handleSnapshotResource(context.Background(), makeRequest(map[string]any{
+		"version":   "v1",
+		"resource":  "secrets",
+		"namespace": "default",
+		"name":      "creds",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	text := expectErr(t, res, "snapshot_resource")
+	requireContains(t, text, "redaction masks some of its fields", "unexpected error text")
+}
+
+func TestRestoreResource_Errors(t *testing.T) {
+	e := newFakeEnv(t)
+
+	tests := []struct {
+		name     string
+		snapshot string
+		want     string
+	}{
+		{
+			name:     "multiple documents",
+			snapshot: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: b\n",
+			want:     "single object",
+		},
+		{
+			name:     "unparsable",
+			snapshot: "data: [unclosed",
+			want:     "failed to parse snapshot",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := e.manager.handleRestoreResource(context.Background(), makeRequest(map[string]any{"snapshot": tt.snapshot}))
+			if err != nil {
+				t.Fatalf("go-error: %v", err)
+			}
+			text := expectErr(t, res, tt.name)
+			requireContains(t, text, tt.want, "unexpected error text")
+		})
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"kubernetes-mcp/internal/authorization"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// TokenRequest rejects expirations under 10 minutes
+	minTokenExpirationSeconds     = 600
+	maxTokenExpirationSeconds     = 86400
+	defaultTokenExpirationSeconds = 3600
+)
+
+func (m *Manager) registerCreateSAToken() {
+	tool := mcp.NewTool(m.toolName("create_sa_token"),
+		mcp.WithDescription(`Mint a short-lived token for a ServiceAccount using the TokenRequest API
+(the 'serviceaccounts/token' subresource, same as 'kubectl create token').
+
+The token grants whatever RBAC the ServiceAccount holds, so this is a
+privilege-granting operation: only call it when a workflow genuinely needs a
+scoped credential to talk to another service.
+
+By default the token itself is NOT returned, only its audiences and
+expiration. Pass 'reveal=true' to include it in the response.`),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("namespace", mcp.Required(), mcp.Description("Namespace of the ServiceAccount.")),
+		mcp.WithString("name", mcp.Required(), mcp.Description("ServiceAccount name.")),
+		mcp.WithArray("audiences", mcp.Description("Intended audiences of the token (the 'aud' claim). Omit to use the API server's default audience.")),
+		mcp.WithNumber("expiration_seconds", mcp.Description("Requested token lifetime in seconds. Range 600..86400, default 3600. The API server may issue a shorter-lived token.")),
+		mcp.WithBoolean("reveal", mcp.Description("Return the token in clear text. Default false: the token is minted but masked in the output.")),
+	)
+	m.addTool(tool, m.handleCreateSAToken)
+}
+
+func (m *Manager) handleCreateSAToken(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	namespace, _ := args["namespace"].(string)
+	name, _ := args["name"].(string)
+	reveal, _ := args["reveal"].(bool)
+
+	if namespace == "" || name == "" {
+		return errorResult(fmt.Errorf("both 'namespace' and 'name' are required")), nil
+	}
+
+	expiration := int64(defaultTokenExpirationSeconds)
+	if v, ok := args["expiration_seconds"].(float64); ok {
+		if v < minTokenExpirationSeconds || v > maxTokenExpirationSeconds {
+			return errorResult(fmt.Errorf("expiration_seconds must be between %d and %d, got %v", minTokenExpirationSeconds, maxTokenExpirationSeconds, v)), nil
+		}
+		expiration = int64(v)
+	}
+
+	var audiences []string
+	if raw, ok := args["audiences"].([]any); ok {
+		for _, a := range raw {
+			if s, ok := a.(string); ok && s != "" {
+				audiences = append(audiences, s)
+			}
+		}
+	}
+
+	// Check authorization (real K8s resource: the ServiceAccount the token is
+	// minted for). The tool name is deliberately outside the read-only
+	// prefixes (get_*, list_*, ...) so wildcard read policies never grant it.
+	if err := m.checkAuthorization(request, "create_sa_token", k8sContext, namespace, authorization.ResourceInfo{
+		Group:    "",
+		Version:  "v1",
+		Resource: "serviceaccounts",
+		Name:     name,
+	}); err != nil {
+		return errorResult(err), nil
+	}
+
+	if !m.clientManager.IsNamespaceAllowed(k8sContext, namespace) {
+		return errorResult(fmt.Errorf("namespace %s is not allowed in context %s", namespace, k8sContext)), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	tokenRequest := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			Audiences:         audiences,
+			ExpirationSeconds: &expiration,
+		},
+	}
+
+	result, err := client.Clientset.CoreV1().ServiceAccounts(namespace).CreateToken(ctx, name, tokenRequest, metav1.CreateOptions{})
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	token := "*** (pass reveal=true to include it)"
+	if reveal {
+		token = result.Status.Token
+	}
+
+	audienceText := "(API server default)"
+	if len(result.Spec.Audiences) > 0 {
+		audienceText = strings.Join(result.Spec.Audiences, ", ")
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Token created for serviceaccount %s/%s\n", namespace, name)
+	fmt.Fprintf(&sb, "Audiences:  %s\n", audienceText)
+	fmt.Fprintf(&sb, "Expires at: %s\n", result.Status.ExpirationTimestamp.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&sb, "Token:      %s\n", token)
+
+	return successResult(sb.String()), nil
+}
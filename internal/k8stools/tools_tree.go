@@ -0,0 +1,397 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"kubernetes-mcp/internal/authorization"
+	"kubernetes-mcp/internal/kubernetes"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	treeDefaultDepth = 5
+	treeMaxDepth     = 10
+
+	// treeMaxNodes bounds the dependents rendered, so a namespace with
+	// thousands of owned objects cannot produce an unbounded tree
+	treeMaxNodes = 200
+
+	treeDirectionOwners     = "owners"
+	treeDirectionDependents = "dependents"
+	treeDirectionBoth       = "both"
+)
+
+// treeKind is a listable resource type that may hold dependents
+type treeKind struct {
+	gvr  schema.GroupVersionResource
+	kind string
+}
+
+// treeChild is an object found to reference an owner
+type treeChild struct {
+	kind      treeKind
+	name      string
+	namespace string
+	uid       types.UID
+}
+
+// treeNode is one line of the rendered tree
+type treeNode struct {
+	label  string
+	status string
+	// root marks the requested object when owners are drawn above it
+	root     bool
+	children []*treeNode
+}
+
+func (m *Manager) registerGetResourceTree() {
+	tool := mcp.NewTool(m.toolName("get_resource_tree"),
+		mcp.WithDescription(`Show the ownership tree of a resource, like 'kubectl tree': what owns it
+(ownerReferences upwards) and what it owns (dependents downwards, e.g.
+Deployment → ReplicaSet → Pod), one line per object with its status.
+
+Use it to answer "what created this Pod?" or "what does this Deployment,
+CronJob or operator CR own right now?".
+
+Finding dependents lists every resource type discovery reports in the
+root's namespace (metadata only) and matches ownerReferences, so it works
+for CRDs too. Types the caller may not list are skipped and counted in a
+note. For a cluster-scoped root, only cluster-scoped dependents are searched.`),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("group", mcp.Description("API group of the root. Empty string \"\" for the core API. Examples: 'apps', 'batch'.")),
+		mcp.WithString("version", mcp.Description("API version of the root, e.g. 'v1'. If empty or not served for the group, the preferred version is used.")),
+		mcp.WithString("resource", mcp.Required(), mcp.Description("Resource of the root, lowercase plural ('deployments', 'pods', 'cronjobs'). NOT the Kind. Short names are accepted ('deploy', 'po', ...).")),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Name of the root object.")),
+		mcp.WithString("namespace", mcp.Description("Namespace of the root. Leave empty for cluster-scoped resources.")),
+		mcp.WithString("direction", mcp.Enum(treeDirectionOwners, treeDirectionDependents, treeDirectionBoth), mcp.Description("Which way to walk: 'owners' (upwards), 'dependents' (downwards) or 'both'. Defaults to 'both'.")),
+		mcp.WithNumber("max_depth", mcp.Description(fmt.Sprintf("Levels to walk in each direction. Integer 1..%d. Defaults to %d.", treeMaxDepth, treeDefaultDepth))),
+	)
+	m.addTool(tool, m.handleGetResourceTree)
+}
+
+func (m *Manager) handleGetResourceTree(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	name, _ := args["name"].(string)
+	namespace, _ := args["namespace"].(string)
+	if name == "" {
+		return errorResult(fmt.Errorf("name is required")), nil
+	}
+
+	direction, _ := args["direction"].(string)
+	switch direction {
+	case "":
+		direction = treeDirectionBoth
+	case treeDirectionOwners, treeDirectionDependents, treeDirectionBoth:
+	default:
+		return errorResult(fmt.Errorf("direction must be one of owners, dependents, both, got %q", direction)), nil
+	}
+	maxDepth := treeDefaultDepth
+	if v, ok := args["max_depth"].(float64); ok {
+		if v < 1 || v > treeMaxDepth {
+			return errorResult(fmt.Errorf("max_depth must be between 1 and %d, got %v", treeMaxDepth, v)), nil
+		}
+		maxDepth = int(v)
+	}
+
+	gvr, err := m.resolveGVRVersion(k8sContext, m.expandShortName(k8sContext, gvrFromArgs(args)))
+	if err != nil {
+		return errorResult(err), nil
+	}
+	if err := validateGVR(gvr); err != nil {
+		return errorResult(err), nil
+	}
+
+	// Check authorization
+	if err := m.checkAuthorization(request, "get_resource_tree", k8sContext, namespace, authorization.ResourceInfo{
+		Group:    gvr.Group,
+		Version:  gvr.Version,
+		Resource: gvr.Resource,
+		Name:     name,
+	}); err != nil {
+		return errorResult(err), nil
+	}
+
+	if namespace != "" && !m.clientManager.IsNamespaceAllowed(k8sContext, namespace) {
+		return errorResult(fmt.Errorf("namespace %s is not allowed in context %s", namespace, k8sContext)), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	var root *unstructured.Unstructured
+	if namespace != "" {
+		root, err = client.DynamicClient.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	} else {
+		root, err = client.DynamicClient.Resource(gvr).Get(ctx, name, metav1.GetOptions{})
+	}
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	rootNode := &treeNode{label: objectLabel(root), status: treeStatus(root.Object)}
+	var notes []string
+
+	if direction != treeDirectionOwners {
+		note := m.addDependents(ctx, request, k8sContext, client, root, rootNode, maxDepth)
+		if note != "" {
+			notes = append(notes, note)
+		}
+	}
+
+	top := rootNode
+	if direction != treeDirectionDependents {
+		owners, note := m.walkOwners(ctx, request, "get_resource_tree", k8sContext, client, root, maxDepth)
+		if note != "" {
+			notes = append(notes, "owners:"+note)
+		}
+		// Nest the root under its owners, outermost first
+		rootNode.root = len(owners) > 0
+		for i := len(owners) - 1; i >= 0; i-- {
+			node := &treeNode{label: owners[i].label, status: "not readable", children: []*treeNode{top}}
+			if owners[i].obj != nil {
+				node.status = treeStatus(owners[i].obj.Object)
+			}
+			top = node
+		}
+	}
+
+	var sb strings.Builder
+	renderTree(&sb, top, "", "")
+	for _, note := range notes {
+		fmt.Fprintf(&sb, "\nNote: %s", note)
+	}
+	return successResult(strings.TrimRight(sb.String(), "\n")), nil
+}
+
+// addDependents attaches the objects owned by 'root', level by level, up to
+// maxDepth levels. The ownership index is built once from metadata-only
+// lists of every listable type in the root's scope.
+func (m *Manager) addDependents(ctx context.Context, request mcp.CallToolRequest, k8sContext string, client *kubernetes.Client, root *unstructured.Unstructured, rootNode *treeNode, maxDepth int) string {
+	namespace := root.GetNamespace()
+	kinds, err := treeKinds(client, namespace != "")
+	if err != nil {
+		return fmt.Sprintf("dependents: discovery failed: %v", err)
+	}
+
+	byOwner := map[types.UID][]treeChild{}
+	skipped := 0
+	for _, kind := range kinds {
+		if err := m.checkAuthorization(request, "get_resource_tree", k8sContext, namespace, authorization.ResourceInfo{
+			Group:    kind.gvr.Group,
+			Version:  kind.gvr.Version,
+			Resource: kind.gvr.Resource,
+		}); err != nil {
+			skipped++
+			continue
+		}
+		children, err := listOwnedObjects(ctx, client, kind, namespace)
+		if err != nil {
+			skipped++
+			continue
+		}
+		for owner, list := range children {
+			byOwner[owner] = append(byOwner[owner], list...)
+		}
+	}
+
+	var notes []string
+	if skipped > 0 {
+		notes = append(notes, fmt.Sprintf("%d resource types could not be listed (RBAC or not listable) and were not searched", skipped))
+	}
+
+	type pending struct {
+		uid   types.UID
+		node  *treeNode
+		depth int
+	}
+	queue := []pending{{uid: root.GetUID(), node: rootNode}}
+	seen := map[types.UID]bool{root.GetUID(): true}
+	count := 0
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current.depth == maxDepth {
+			if len(byOwner[current.uid]) > 0 {
+				current.node.status += fmt.Sprintf(" (+%d dependents below max_depth)", len(byOwner[current.uid]))
+			}
+			continue
+		}
+		children := byOwner[current.uid]
+		slices.SortFunc(children, func(a, b treeChild) int {
+			return strings.Compare(a.kind.kind+"/"+a.name, b.kind.kind+"/"+b.name)
+		})
+		for _, child := range children {
+			if seen[child.uid] {
+				continue
+			}
+			seen[child.uid] = true
+			if count == treeMaxNodes {
+				notes = append(notes, fmt.Sprintf("stopped after %d dependents; start from a lower object or lower max_depth", treeMaxNodes))
+				return "dependents: " + strings.Join(notes, "; ")
+			}
+			count++
+
+			node := &treeNode{label: child.kind.kind + "/" + child.name}
+			if obj, err := getTreeObject(ctx, client, child); err == nil {
+				node.status = treeStatus(obj.Object)
+			}
+			current.node.children = append(current.node.children, node)
+			queue = append(queue, pending{uid: child.uid, node: node, depth: current.depth + 1})
+		}
+	}
+
+	if len(notes) == 0 {
+		return ""
+	}
+	return "dependents: " + strings.Join(notes, "; ")
+}
+
+// treeKinds returns the listable resource types of one scope, at the
+// group's preferred version. Subresources and events are left out.
+func treeKinds(client *kubernetes.Client, namespaced bool) ([]treeKind, error) {
+	groups, lists, err := client.Clientset.Discovery().ServerGroupsAndResources()
+	if err != nil && lists == nil {
+		return nil, err
+	}
+	preferred := map[string]string{}
+	for _, g := range groups {
+		preferred[g.Name] = g.PreferredVersion.Version
+	}
+
+	var kinds []treeKind
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil || (preferred[gv.Group] != "" && preferred[gv.Group] != gv.Version) {
+			continue
+		}
+		for _, r := range list.APIResources {
+			if strings.Contains(r.Name, "/") || r.Namespaced != namespaced || r.Name == "events" || !slices.Contains(r.Verbs, "list") {
+				continue
+			}
+			kinds = append(kinds, treeKind{gvr: gv.WithResource(r.Name), kind: r.Kind})
+		}
+	}
+	return kinds, nil
+}
+
+// listOwnedObjects lists one type's metadata and groups the objects that
+// have owners by owner UID
+func listOwnedObjects(ctx context.Context, client *kubernetes.Client, kind treeKind, namespace string) (map[types.UID][]treeChild, error) {
+	var items []metav1.Object
+	if client.MetadataClient != nil {
+		list, err := client.MetadataClient.Resource(kind.gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			items = append(items, &list.Items[i])
+		}
+	} else {
+		list, err := client.DynamicClient.Resource(kind.gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			items = append(items, &list.Items[i])
+		}
+	}
+
+	out := map[types.UID][]treeChild{}
+	for _, item := range items {
+		for _, ref := range item.GetOwnerReferences() {
+			out[ref.UID] = append(out[ref.UID], treeChild{
+				kind:      kind,
+				name:      item.GetName(),
+				namespace: item.GetNamespace(),
+				uid:       item.GetUID(),
+			})
+		}
+	}
+	return out, nil
+}
+
+func getTreeObject(ctx context.Context, client *kubernetes.Client, child treeChild) (*unstructured.Unstructured, error) {
+	if child.namespace != "" {
+		return client.DynamicClient.Resource(child.kind.gvr).Namespace(child.namespace).Get(ctx, child.name, metav1.GetOptions{})
+	}
+	return client.DynamicClient.Resource(child.kind.gvr).Get(ctx, child.name, metav1.GetOptions{})
+}
+
+func objectLabel(obj *unstructured.Unstructured) string {
+	return obj.GetKind() + "/" + obj.GetName()
+}
+
+// treeStatus summarizes an object in a few words, reusing the table
+// columns where a kind has them
+func treeStatus(obj map[string]any) string {
+	kind, _ := obj["kind"].(string)
+	switch kind {
+	case "Pod":
+		return fmt.Sprintf("%s, %s ready", podStatusColumn(obj), podReadyColumn(obj))
+	case "Job":
+		return "completions " + jobCompletionsColumn(obj)
+	}
+
+	if phase, _ := tableField(obj, "status", "phase").(string); phase != "" {
+		return phase
+	}
+	if tableField(obj, "spec", "replicas") != nil {
+		return "ready " + replicasColumn("readyReplicas")(obj)
+	}
+	conditions, _ := tableField(obj, "status", "conditions").([]any)
+	for _, c := range conditions {
+		cond, ok := c.(map[string]any)
+		if ok && (cond["type"] == "Ready" || cond["type"] == "Available") {
+			return fmt.Sprintf("%s=%v", cond["type"], cond["status"])
+		}
+	}
+	return ""
+}
+
+// renderTree writes one node and its children with box-drawing branches
+func renderTree(sb *strings.Builder, node *treeNode, prefix, childPrefix string) {
+	line := node.label
+	if node.status != "" {
+		line += "  (" + node.status + ")"
+	}
+	if node.root {
+		line += "  <- root"
+	}
+	sb.WriteString(prefix + line + "\n")
+
+	for i, child := range node.children {
+		if i == len(node.children)-1 {
+			renderTree(sb, child, childPrefix+"└── ", childPrefix+"    ")
+		} else {
+			renderTree(sb, child, childPrefix+"├── ", childPrefix+"│   ")
+		}
+	}
+}
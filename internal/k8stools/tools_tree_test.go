@@ -0,0 +1,103 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestGetResourceTree(t *testing.T) {
+	deploy := fakeDeployment("default", "web", 2)
+	deploy.UID = "deploy-uid"
+	deploy.Status.ReadyReplicas = 2
+	replicas := int32(2)
+	rs := &appsv1.ReplicaSet{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "ReplicaSet"},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default", Name: "web-7d4b9c", UID: "rs-uid",
+			OwnerReferences: []metav1.OwnerReference{controllerRef("apps/v1", "Deployment", "web", "deploy-uid")},
+		},
+		Spec:   appsv1.ReplicaSetSpec{Replicas: &replicas},
+		Status: appsv1.ReplicaSetStatus{ReadyReplicas: 1},
+	}
+	var pods []*corev1.Pod
+	for i, name := range []string{"web-7d4b9c-b", "web-7d4b9c-a"} {
+		pod := fakePod("default", name, nil)
+		pod.UID = types.UID(name)
+		pod.OwnerReferences = []metav1.OwnerReference{controllerRef("apps/v1", "ReplicaSet", "web-7d4b9c", "rs-uid")}
+		pod.Status.Phase = corev1.PodRunning
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "app", Ready: i == 0}}
+		pods = append(pods, pod)
+	}
+	e := newFakeEnv(t, deploy, rs, pods[0], pods[1], fakeConfigMap("default", "unrelated", nil))
+	list := []string{"list", "get"}
+	e.clientset.Resources = []*metav1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{
+			{Name: "pods", Kind: "Pod", Namespaced: true, Verbs: list},
+			{Name: "pods/log", Kind: "Pod", Namespaced: true, Verbs: []string{"get"}},
+			{Name: "configmaps", Kind: "ConfigMap", Namespaced: true, Verbs: list},
+			{Name: "nodes", Kind: "Node", Verbs: list},
+		}},
+		{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{
+			{Name: "deployments", Kind: "Deployment", Namespaced: true, Verbs: list},
+			{Name: "replicasets", Kind: "ReplicaSet", Namespaced: true, Verbs: list},
+		}},
+	}
+
+	tree := func(extra map[string]any) string {
+		t.Helper()
+		args := map[string]any{"group": "apps", "version": "v1", "resource": "replicasets", "namespace": "default", "name": "web-7d4b9c"}
+		for k, v := range extra {
+			args[k] = v
+		}
+		res, err := e.manager.handleGetResourceTree(context.Background(), makeRequest(args))
+		if err != nil {
+			t.Fatalf("go-error: %v", err)
+		}
+		return expectOK(t, res, "get_resource_tree")
+	}
+
+	want := `Deployment/web  (ready 2/2)
+└── ReplicaSet/web-7d4b9c  (ready 1/2)  <- root
+    ├── Pod/web-7d4b9c-a  (Running, 0/1 ready)
+    └── Pod/web-7d4b9c-b  (Running, 1/1 ready)`
+	if out := tree(nil); out != want {
+		t.Fatalf("unexpected tree:\n%s\nwant:\n%s", out, want)
+	}
+
+	if out := tree(map[string]any{"direction": "owners"}); strings.Contains(out, "Pod/") || !strings.Contains(out, "Deployment/web") {
+		t.Fatalf("expected only the owners, got:\n%s", out)
+	}
+
+	out := tree(map[string]any{"direction": "dependents", "max_depth": float64(1), "group": "apps", "resource": "deployments", "name": "web"})
+	requireContains(t, out, "└── ReplicaSet/web-7d4b9c  (ready 1/2 (+2 dependents below max_depth))", "expected the depth cut reported")
+	if strings.Contains(out, "<- root") {
+		t.Fatalf("root marker without owners:\n%s", out)
+	}
+
+	res, _ := e.manager.handleGetResourceTree(context.Background(), makeRequest(map[string]any{
+		"resource": "pods", "namespace": "default", "name": "web-7d4b9c-a", "direction": "sideways",
+	}))
+	requireContains(t, expectErr(t, res, "bad direction"), "direction must be one of", "expected the direction error")
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"kubernetes-mcp/internal/authorization"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	conditionWaitDefaultTimeout = 60 * time.Second
+	conditionWaitMaxTimeout     = 600 * time.Second
+)
+
+// conditionPollInitial and conditionPollMax bound the backoff between reads
+// of wait_for_condition: quick at first, then no more than every few seconds.
+var (
+	conditionPollInitial = 500 * time.Millisecond
+	conditionPollMax     = 5 * time.Second
+)
+
+// conditionCheck is what wait_for_condition waits for: either a
+// status.conditions entry or a yq expression evaluating to true.
+type conditionCheck struct {
+	conditionType string
+	status        string
+	expression    string
+}
+
+func (c conditionCheck) String() string {
+	if c.expression != "" {
+		return fmt.Sprintf("%q to be true", c.expression)
+	}
+	return fmt.Sprintf("condition %s=%s", c.conditionType, c.status)
+}
+
+func (m *Manager) registerWaitForCondition() {
+	tool := mcp.NewTool(m.toolName("wait_for_condition"),
+		mcp.WithDescription(`Wait until a resource reaches a state, like 'kubectl wait'.
+
+Either give 'condition_type' (and optionally 'status', default "True") to
+wait for an entry of 'status.conditions', e.g. a Deployment 'Available' or
+a Job 'Complete'; or give 'condition_expression', a yq expression over the
+object that must evaluate to 'true', for readiness that is not a condition
+(e.g. '.status.phase == "Running"', '.status.readyReplicas >= 3').
+
+The object is re-read with a backoff until the check passes or
+'timeout_seconds' elapses; on timeout the error includes the last status
+seen. A condition reported for an older generation of the object
+(observedGeneration) does not count. An object that does not exist yet is
+waited for too.`),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("group", mcp.Description("API group. Empty string \"\" for the core API. Examples: 'apps', 'batch'.")),
+		mcp.WithString("version", mcp.Description("API version, e.g. 'v1'. If empty or not served for the group, the preferred version is used.")),
+		mcp.WithString("resource", mcp.Required(), mcp.Description("Resource name in the API sense: lowercase plural ('deployments', 'jobs', 'pods'). NOT the Kind. Short names are accepted ('deploy', 'po', ...).")),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Name of the resource to wait for.")),
+		mcp.WithString("namespace", mcp.Description("Namespace where the resource lives. Required for namespaced resources.")),
+		mcp.WithString("condition_type", mcp.Description("Type of the status.conditions entry to wait for. Examples: 'Available', 'Ready', 'Complete', 'Established'. Mutually exclusive with 'condition_expression'.")),
+		mcp.WithString("status", mcp.Description("Expected status of the condition: 'True', 'False' or 'Unknown'. Defaults to 'True'.")),
+		mcp.WithString("condition_expression", mcp.Description("yq expression evaluated on the object YAML; the wait ends when it yields 'true'. Example: '.status.phase == \"Succeeded\"'. Mutually exclusive with 'condition_type'.")),
+		mcp.WithNumber("timeout_seconds", mcp.Description("Maximum time to wait. Integer 1..600. Defaults to 60.")),
+	)
+	m.addWaitingTool(tool, m.handleWaitForCondition, conditionWaitMaxTimeout)
+}
+
+func (m *Manager) handleWaitForCondition(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	name, _ := args["name"].(string)
+	namespace, _ := args["namespace"].(string)
+	check := conditionCheck{status: "True"}
+	check.conditionType, _ = args["condition_type"].(string)
+	check.expression, _ = args["condition_expression"].(string)
+	if v, _ := args["status"].(string); v != "" {
+		check.status = v
+	}
+	gvr, err := m.resolveGVRVersion(k8sContext, m.expandShortName(k8sContext, gvrFromArgs(args)))
+	if err != nil {
+		return errorResult(err), nil
+	}
+	if err := validateGVR(gvr); err != nil {
+		return errorResult(err), nil
+	}
+
+	if name == "" {
+		return errorResult(fmt.Errorf("name is required")), nil
+	}
+	switch {
+	case check.conditionType == "" && check.expression == "":
+		return errorResult(fmt.Errorf("one of condition_type or condition_expression is required")), nil
+	case check.conditionType != "" && check.expression != "":
+		return errorResult(fmt.Errorf("condition_type and condition_expression are mutually exclusive")), nil
+	}
+	switch check.status {
+	case "True", "False", "Unknown":
+	default:
+		return errorResult(fmt.Errorf("status must be 'True', 'False' or 'Unknown', got %q", check.status)), nil
+	}
+
+	timeout := conditionWaitDefaultTimeout
+	if v, ok := args["timeout_seconds"].(float64); ok {
+		if v < 1 || v > conditionWaitMaxTimeout.Seconds() {
+			return errorResult(fmt.Errorf("timeout_seconds must be between 1 and %d, got %v", int(conditionWaitMaxTimeout.Seconds()), v)), nil
+		}
+		timeout = time.Duration(v) * time.Second
+	}
+
+	// Check authorization
+	if err := m.checkAuthorization(request, "wait_for_condition", k8sContext, namespace, authorization.ResourceInfo{
+		Group:    gvr.Group,
+		Version:  gvr.Version,
+		Resource: gvr.Resource,
+		Name:     name,
+	}); err != nil {
+		return errorResult(err), nil
+	}
+
+	if namespace != "" && !m.clientManager.IsNamespaceAllowed(k8sContext, namespace) {
+		return errorResult(fmt.Errorf("namespace %s is not allowed in context %s", namespace, k8sContext)), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	var nsClient dynamicResource = client.DynamicClient.Resource(gvr)
+	if namespace != "" {
+		nsClient = client.DynamicClient.Resource(gvr).Namespace(namespace)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	progress := m.newProgressReporter(ctx, request)
+	start := time.Now()
+	delay := conditionPollInitial
+	last := "the object was not found"
+	for {
+		obj, err := nsClient.Get(waitCtx, name, metav1.GetOptions{})
+		switch {
+		case err == nil:
+			met, seen, err := m.evaluateCondition(obj, check)
+			if err != nil {
+				return errorResult(err), nil
+			}
+			last = seen
+			if met {
+				elapsed := time.Since(start).Round(time.Millisecond)
+				return successResult(fmt.Sprintf("%s/%s met %s after %s\n\n%s", gvr.Resource, name, check, elapsed, seen)), nil
+			}
+		case apierrors.IsNotFound(err):
+			// Not created yet, e.g. right after an apply of its owner
+		case waitCtx.Err() == nil:
+			return errorResult(err), nil
+		}
+
+		waited := time.Since(start)
+		progress.Report(waited.Seconds(), timeout.Seconds(),
+			fmt.Sprintf("waiting for %s: %s of %s elapsed", check, waited.Round(time.Second), timeout))
+
+		select {
+		case <-waitCtx.Done():
+			if ctx.Err() != nil {
+				return errorResult(fmt.Errorf("wait for %s on %s/%s cancelled", check, gvr.Resource, name)), nil
+			}
+			return errorResult(fmt.Errorf("%s/%s did not meet %s within %s; last seen:\n\n%s", gvr.Resource, name, check, timeout, last)), nil
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, conditionPollMax)
+	}
+}
+
+// evaluateCondition reports whether the object passes the check, along with
+// a readable account of what was seen: the conditions, or the expression
+// result.
+func (m *Manager) evaluateCondition(obj *unstructured.Unstructured, check conditionCheck) (bool, string, error) {
+	if check.expression != "" {
+		// Redacted first, so the expression cannot read masked values
+		yamlOutput, err := objectToYAML(obj.Object)
+		if err != nil {
+			return false, "", err
+		}
+		results, err := m.yq.EvaluateToStrings(m.redactYAML(yamlOutput), check.expression)
+		if err != nil {
+			return false, "", fmt.Errorf("invalid condition_expression: %w", err)
+		}
+		seen := fmt.Sprintf("%s => %s", check.expression, strings.Join(results, ", "))
+		return len(results) == 1 && results[0] == "true", seen, nil
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	generation := obj.GetGeneration()
+
+	met := false
+	var sb strings.Builder
+	for _, c := range conditions {
+		cond, ok := c.(map[string]any)
+		if !ok {
+			continue
+		}
+		condType, _ := cond["type"].(string)
+		status, _ := cond["status"].(string)
+		reason, _ := cond["reason"].(string)
+		message, _ := cond["message"].(string)
+		observed, hasObserved, _ := unstructured.NestedInt64(cond, "observedGeneration")
+
+		stale := hasObserved && generation > 0 && observed < generation
+		fmt.Fprintf(&sb, "- %s=%s", condType, status)
+		if reason != "" {
+			fmt.Fprintf(&sb, " (%s)", reason)
+		}
+		if message != "" {
+			fmt.Fprintf(&sb, ": %s", message)
+		}
+		if stale {
+			fmt.Fprintf(&sb, " [observedGeneration %d < generation %d]", observed, generation)
+		}
+		sb.WriteString("\n")
+
+		if strings.EqualFold(condType, check.conditionType) && status == check.status && !stale {
+			met = true
+		}
+	}
+	if len(conditions) == 0 {
+		sb.WriteString("no status.conditions reported\n")
+	}
+	return met, "Conditions:\n" + sb.String(), nil
+}
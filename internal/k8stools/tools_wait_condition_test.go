@@ -0,0 +1,101 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestWaitForCondition(t *testing.T) {
+	initial, maxDelay := conditionPollInitial, conditionPollMax
+	conditionPollInitial, conditionPollMax = time.Millisecond, 5*time.Millisecond
+	t.Cleanup(func() { conditionPollInitial, conditionPollMax = initial, maxDelay })
+
+	available := fakeDeployment("default", "web", 2)
+	available.Generation = 4
+	available.Status.Conditions = []appsv1.DeploymentCondition{
+		{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue, Reason: "MinimumReplicasAvailable"},
+		{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue, Reason: "NewReplicaSetAvailable"},
+	}
+	pod := fakePod("default", "runner", nil)
+	pod.Status.Phase = corev1.PodPending
+
+	e := newFakeEnv(t, available, pod)
+	wait := func(args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		args["namespace"] = "default"
+		res, err := e.manager.handleWaitForCondition(context.Background(), makeRequest(args))
+		if err != nil {
+			t.Fatalf("go-error: %v", err)
+		}
+		return res
+	}
+
+	out := expectOK(t, wait(map[string]any{"group": "apps", "version": "v1", "resource": "deployments", "name": "web", "condition_type": "available"}), "already available")
+	requireContains(t, out, "deployments/web met condition available=True", "expected the met condition")
+	requireContains(t, out, "- Available=True (MinimumReplicasAvailable)", "expected the conditions seen")
+
+	// The Pod reaches Running on the third read
+	reads := 0
+	e.dynamic.PrependReactor("get", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if reads++; reads < 3 {
+			return false, nil, nil
+		}
+		running := pod.DeepCopy()
+		running.Status.Phase = corev1.PodRunning
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(running)
+		return true, &unstructured.Unstructured{Object: obj}, err
+	})
+	out = expectOK(t, wait(map[string]any{"version": "v1", "resource": "pods", "name": "runner", "condition_expression": `.status.phase == "Running"`}), "expression")
+	requireContains(t, out, `.status.phase == "Running" => true`, "expected the expression result")
+	if reads < 3 {
+		t.Fatalf("expected the pod to be polled until Running, got %d reads", reads)
+	}
+
+	// A condition reported for an older generation does not count
+	stale := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "example.com/v1",
+		"kind":       "Widget",
+		"metadata":   map[string]any{"name": "gadget", "namespace": "default", "generation": int64(3)},
+		"status": map[string]any{"conditions": []any{
+			map[string]any{"type": "Ready", "status": "True", "observedGeneration": int64(2)},
+		}},
+	}}
+	if _, err := e.dynamic.Resource(gvrOf("example.com", "v1", "widgets")).Namespace("default").Create(context.Background(), stale, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("create widget: %v", err)
+	}
+	out = expectErr(t, wait(map[string]any{"group": "example.com", "version": "v1", "resource": "widgets", "name": "gadget", "condition_type": "Ready", "timeout_seconds": 1.0}), "stale condition")
+	requireContains(t, out, "did not meet condition Ready=True within 1s", "expected a timeout")
+	requireContains(t, out, "- Ready=True [observedGeneration 2 < generation 3]", "expected the last status seen")
+
+	requireContains(t, expectErr(t, wait(map[string]any{"version": "v1", "resource": "pods", "name": "runner"}), "no check"),
+		"one of condition_type or condition_expression is required", "expected a missing check error")
+	requireContains(t, expectErr(t, wait(map[string]any{"version": "v1", "resource": "pods", "name": "runner", "condition_type": "Ready", "condition_expression": ".x"}), "both checks"),
+		"mutually exclusive", "expected conflicting checks error")
+	requireContains(t, expectErr(t, wait(map[string]any{"version": "v1", "resource": "pods", "name": "runner", "condition_type": "Ready", "status": "yes"}), "bad status"),
+		"status must be", "expected a status validation error")
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// Shared plumbing for tools that observe changes with a bounded watch.
+//
+// A watch is resumable across tool calls: the result carries the last
+// resourceVersion observed, bookmarks included, and the next call passes it
+// back as 'resource_version' to continue exactly where the previous one
+// stopped, without missing or replaying events. Bookmarks make the server
+// advance that token even while nothing changes.
+
+// watchEvent is one change observed by a watch
+type watchEvent struct {
+	Type            string `json:"type"`
+	Name            string `json:"name"`
+	Namespace       string `json:"namespace,omitempty"`
+	ResourceVersion string `json:"resource_version"`
+	object          metav1.Object
+}
+
+// watchOutcome is what a bounded watch observed
+type watchOutcome struct {
+	events []watchEvent
+	// resourceVersion is the latest version seen, from events or bookmarks;
+	// pass it back as 'resource_version' to resume.
+	resourceVersion string
+	// expired is set when the server no longer has the requested version
+	// (410 Gone); the caller must list again and resume from the list's
+	// resourceVersion.
+	expired bool
+	// capped is set when the event cap ended the watch
+	capped bool
+}
+
+// watchListOptions builds the ListOptions of a resumable watch. An empty
+// resourceVersion starts from the current state, which the server replays
+// as ADDED events first.
+func watchListOptions(labelSelector, fieldSelector, resourceVersion string) metav1.ListOptions {
+	return metav1.ListOptions{
+		LabelSelector:       labelSelector,
+		FieldSelector:       fieldSelector,
+		ResourceVersion:     resourceVersion,
+		AllowWatchBookmarks: true,
+	}
+}
+
+// collectWatch drains a watch until ctx is done, the server closes it or
+// maxEvents changes were seen. It always stops the watch before returning.
+func collectWatch(ctx context.Context, w watch.Interface, startVersion string, maxEvents int) (watchOutcome, error) {
+	defer w.Stop()
+	outcome := watchOutcome{resourceVersion: startVersion}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return outcome, nil
+		case ev, ok := <-w.ResultChan():
+			if !ok {
+				return outcome, nil
+			}
+
+			if ev.Type == watch.Error {
+				status, isStatus := ev.Object.(*metav1.Status)
+				if isStatus && status.Code == http.StatusGone {
+					outcome.expired = true
+					return outcome, nil
+				}
+				if isStatus {
+					return outcome, fmt.Errorf("watch failed: %s", status.Message)
+				}
+				return outcome, fmt.Errorf("watch failed: unexpected error event")
+			}
+
+			obj, err := meta.Accessor(ev.Object)
+			if err != nil {
+				continue
+			}
+			if rv := obj.GetResourceVersion(); rv != "" {
+				outcome.resourceVersion = rv
+			}
+			// Bookmarks only move the resume token forward
+			if ev.Type == watch.Bookmark {
+				continue
+			}
+
+			outcome.events = append(outcome.events, watchEvent{
+				Type:            string(ev.Type),
+				Name:            obj.GetName(),
+				Namespace:       obj.GetNamespace(),
+				ResourceVersion: obj.GetResourceVersion(),
+				object:          obj,
+			})
+			if maxEvents > 0 && len(outcome.events) >= maxEvents {
+				outcome.capped = true
+				return outcome, nil
+			}
+		}
+	}
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"kubernetes-mcp/internal/authorization"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+const (
+	watchDefaultTimeout = 30
+	watchDefaultEvents  = 100
+	watchMaxEvents      = 1000
+
+	// defaultWatchMaxTimeout applies when 'kubernetes.tools.watch_max_timeout' is unset
+	defaultWatchMaxTimeout = 5 * time.Minute
+)
+
+// watchMaxTimeout returns the longest watch a single call may hold open
+func (m *Manager) watchMaxTimeout() time.Duration {
+	if timeout := m.currentConfig().Kubernetes.Tools.WatchMaxTimeout; timeout > 0 {
+		return timeout
+	}
+	return defaultWatchMaxTimeout
+}
+
+func (m *Manager) registerWatchResources() {
+	maxTimeout := int(m.watchMaxTimeout().Seconds())
+	tool := mcp.NewTool(m.toolName("watch_resources"),
+		mcp.WithDescription(fmt.Sprintf(`Watch resources of a given type for a bounded time and return what changed:
+"what happens to these Pods while I scale?", "did anything touch this ConfigMap?".
+
+Collects ADDED, MODIFIED and DELETED events until 'timeout_seconds' elapses
+(default %d, max %d) or 'max_events' changes were seen. Returns the events
+in order as {type, name, namespace, resource_version}, the final state of
+every object still present, and a 'resource_version' to pass back to resume
+exactly where this call stopped.
+
+Without 'resource_version' the watch starts from the current state, which
+the server replays as ADDED events first. Narrow it with selectors.`, watchDefaultTimeout, maxTimeout)),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("group", mcp.Description("API group. Empty string \"\" for the core API. Examples: 'apps', 'networking.k8s.io', 'batch'.")),
+		mcp.WithString("version", mcp.Description("API version, e.g. 'v1', 'v1beta1'. If empty or not served for the group, the preferred version is used (kubernetes.tools.preferred_versions, else discovery).")),
+		mcp.WithString("resource", mcp.Required(), mcp.Description("Resource name in the API sense: lowercase plural ('pods', 'deployments', 'ingresses'). NOT the Kind. Use 'list_api_resources' if unsure. Short names are accepted ('po', 'deploy', 'sts', ...).")),
+		mcp.WithString("namespace", mcp.Description("Namespace to scope the watch to. Empty string watches across ALL namespaces (subject to RBAC) and is ignored for cluster-scoped resources.")),
+		mcp.WithString("label_selector", mcp.Description("Kubernetes label selector. Examples: 'app=nginx', 'app=api,env!=prod'.")),
+		mcp.WithString("field_selector", mcp.Description("Kubernetes field selector. Examples: 'metadata.name=foo', 'status.phase=Running'.")),
+		mcp.WithString("resource_version", mcp.Description("Resume token: the 'resource_version' returned by a previous call. Omit to start from the current state.")),
+		mcp.WithNumber("timeout_seconds", mcp.Description(fmt.Sprintf("How long to watch. Integer 1..%d. Defaults to %d.", maxTimeout, watchDefaultTimeout))),
+		mcp.WithNumber("max_events", mcp.Description(fmt.Sprintf("Stop after this many changes. Integer 1..%d. Defaults to %d.", watchMaxEvents, watchDefaultEvents))),
+		mcp.WithArray("yq_expressions", mcp.Description("Optional yq expressions applied to the YAML output. Examples: '.events[] | select(.type == \"DELETED\") | .name', '.objects[].status.phase'.")),
+		jqExpressionsParam(),
+	)
+	m.addWaitingTool(tool, m.handleWatchResources, m.watchMaxTimeout())
+}
+
+func (m *Manager) handleWatchResources(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	namespace, _ := args["namespace"].(string)
+	labelSelector, _ := args["label_selector"].(string)
+	fieldSelector, _ := args["field_selector"].(string)
+	resourceVersion, _ := args["resource_version"].(string)
+
+	gvr, err := m.resolveGVRVersion(k8sContext, m.expandShortName(k8sContext, gvrFromArgs(args)))
+	if err != nil {
+		return errorResult(err), nil
+	}
+	if err := validateGVR(gvr); err != nil {
+		return errorResult(err), nil
+	}
+
+	maxTimeout := int(m.watchMaxTimeout().Seconds())
+	timeout := watchDefaultTimeout
+	if v, ok := args["timeout_seconds"].(float64); ok {
+		if v < 1 || v > float64(maxTimeout) {
+			return errorResult(fmt.Errorf("timeout_seconds must be between 1 and %d, got %v", maxTimeout, v)), nil
+		}
+		timeout = int(v)
+	}
+	maxEvents := watchDefaultEvents
+	if v, ok := args["max_events"].(float64); ok {
+		if v < 1 || v > watchMaxEvents {
+			return errorResult(fmt.Errorf("max_events must be between 1 and %d, got %v", watchMaxEvents, v)), nil
+		}
+		maxEvents = int(v)
+	}
+
+	// Check authorization
+	if err := m.checkAuthorization(request, "watch_resources", k8sContext, namespace, authorization.ResourceInfo{
+		Group:    gvr.Group,
+		Version:  gvr.Version,
+		Resource: gvr.Resource,
+	}); err != nil {
+		return errorResult(err), nil
+	}
+
+	// Check namespace access if specified
+	if namespace != "" && !m.clientManager.IsNamespaceAllowed(k8sContext, namespace) {
+		return errorResult(fmt.Errorf("namespace %s is not allowed in context %s", namespace, k8sContext)), nil
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	// The server closes the watch on its own after TimeoutSeconds; the
+	// context covers the client side, including the MCP call being cancelled.
+	watchCtx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	opts := watchListOptions(labelSelector, fieldSelector, resourceVersion)
+	serverTimeout := int64(timeout)
+	opts.TimeoutSeconds = &serverTimeout
+
+	var w watch.Interface
+	if namespace != "" {
+		w, err = client.StreamingDynamicClient().Resource(gvr).Namespace(namespace).Watch(watchCtx, opts)
+	} else {
+		w, err = client.StreamingDynamicClient().Resource(gvr).Watch(watchCtx, opts)
+	}
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	outcome, err := collectWatch(watchCtx, w, resourceVersion, maxEvents)
+	if err != nil {
+		return errorResult(err), nil
+	}
+	if ctx.Err() != nil {
+		return errorResult(ctx.Err()), nil
+	}
+	if outcome.expired {
+		return errorResult(fmt.Errorf("resource_version %s is too old (410 Gone); call again without 'resource_version' to start from the current state", resourceVersion)), nil
+	}
+
+	result := map[string]any{
+		"events":           outcome.events,
+		"objects":          finalWatchObjects(outcome.events),
+		"resource_version": outcome.resourceVersion,
+		"stopped_by":       watchStopReason(watchCtx, outcome),
+	}
+	if outcome.events == nil {
+		result["events"] = []watchEvent{}
+	}
+
+	yamlOutput, err := objectToYAML(result)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	finalOutput, err := m.applyOutputFilters(yamlOutput, args)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	return successResult(finalOutput), nil
+}
+
+// finalWatchObjects returns the last state seen of every object the watch
+// reported, in order of first appearance. Objects deleted by the end of the
+// watch are left out; their DELETED event says so.
+func finalWatchObjects(events []watchEvent) []map[string]any {
+	var order []string
+	last := map[string]watchEvent{}
+	for _, ev := range events {
+		key := ev.Namespace + "/" + ev.Name
+		if _, seen := last[key]; !seen {
+			order = append(order, key)
+		}
+		last[key] = ev
+	}
+
+	objects := []map[string]any{}
+	for _, key := range order {
+		ev := last[key]
+		if ev.Type == string(watch.Deleted) {
+			continue
+		}
+		if u, ok := ev.object.(*unstructured.Unstructured); ok {
+			objects = append(objects, u.Object)
+		}
+	}
+	return objects
+}
+
+// watchStopReason tells the caller why the watch ended
+func watchStopReason(ctx context.Context, outcome watchOutcome) string {
+	switch {
+	case outcome.capped:
+		return "max_events"
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return "timeout"
+	default:
+		return "server closed the watch"
+	}
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func watchedConfigMap(name, resourceVersion string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetNamespace("default")
+	obj.SetName(name)
+	obj.SetResourceVersion(resourceVersion)
+	return obj
+}
+
+func TestWatchListOptions(t *testing.T) {
+	opts := watchListOptions("app=web", "", "1234")
+	if !opts.AllowWatchBookmarks {
+		t.Fatalf("expected bookmarks to be requested")
+	}
+	if opts.ResourceVersion != "1234" || opts.LabelSelector != "app=web" {
+		t.Fatalf("unexpected options: %+v", opts)
+	}
+}
+
+func TestCollectWatch_Bookmarks(t *testing.T) {
+	w := watch.NewFake()
+	go func() {
+		w.Add(watchedConfigMap("a", "11"))
+		w.Modify(watchedConfigMap("a", "12"))
+		// A bookmark during a quiet period only advances the resume token
+		w.Action(watch.Bookmark, watchedConfigMap("", "20"))
+		w.Stop()
+	}()
+
+	outcome, err := collectWatch(context.Background(), w, "10", 0)
+	if err != nil {
+		t.Fatalf("collectWatch: %v", err)
+	}
+	if len(outcome.events) != 2 {
+		t.Fatalf("expected 2 events (bookmark not reported), got %+v", outcome.events)
+	}
+	if outcome.events[1].Type != "MODIFIED" || outcome.events[1].ResourceVersion != "12" {
+		t.Fatalf("unexpected second event: %+v", outcome.events[1])
+	}
+	if outcome.resourceVersion != "20" {
+		t.Fatalf("expected resume token from the bookmark, got %q", outcome.resourceVersion)
+	}
+}
+
+func TestCollectWatch_MaxEventsAndTimeout(t *testing.T) {
+	w := watch.NewFakeWithChanSize(2, false)
+	w.Add(watchedConfigMap("a", "11"))
+	w.Add(watchedConfigMap("b", "12"))
+
+	outcome, err := collectWatch(context.Background(), w, "", 1)
+	if err != nil {
+		t.Fatalf("collectWatch: %v", err)
+	}
+	if !outcome.capped || len(outcome.events) != 1 || outcome.resourceVersion != "11" {
+		t.Fatalf("expected to stop after one event, got %+v", outcome)
+	}
+	if !w.IsStopped() {
+		t.Fatalf("expected the watch to be stopped")
+	}
+
+	// No events at all: the deadline ends the watch and the start version
+	// is handed back unchanged.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	outcome, err = collectWatch(ctx, watch.NewFake(), "42", 0)
+	if err != nil {
+		t.Fatalf("collectWatch: %v", err)
+	}
+	if len(outcome.events) != 0 || outcome.resourceVersion != "42" {
+		t.Fatalf("expected an empty outcome resuming from 42, got %+v", outcome)
+	}
+}
+
+func TestCollectWatch_Expired(t *testing.T) {
+	w := watch.NewFake()
+	go w.Error(&metav1.Status{Status: metav1.StatusFailure, Code: http.StatusGone, Reason: metav1.StatusReasonExpired, Message: "too old resource version: 5 (900)"})
+
+	outcome, err := collectWatch(context.Background(), w, "5", 0)
+	if err != nil {
+		t.Fatalf("collectWatch: %v", err)
+	}
+	if !outcome.expired {
+		t.Fatalf("expected the outcome to be flagged expired")
+	}
+
+	w = watch.NewFake()
+	go w.Error(&metav1.Status{Status: metav1.StatusFailure, Code: http.StatusForbidden, Message: "forbidden"})
+	if _, err := collectWatch(context.Background(), w, "", 0); err == nil {
+		t.Fatalf("expected other watch errors to be returned")
+	}
+}
+
+// serveWatch makes every dynamic watch return 'w' and records what it was
+// restricted to
+func (e *fakeEnv) serveWatch(w watch.Interface) *k8stesting.WatchRestrictions {
+	var seen k8stesting.WatchRestrictions
+	e.dynamic.PrependWatchReactor("*", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		seen = action.(k8stesting.WatchActionImpl).GetWatchRestrictions()
+		return true, w, nil
+	})
+	return &seen
+}
+
+func TestWatchResources(t *testing.T) {
+	e := newFakeEnv(t)
+	w := watch.NewFake()
+	opts := e.serveWatch(w)
+	go func() {
+		w.Add(watchedConfigMap("a", "11"))
+		w.Add(watchedConfigMap("b", "12"))
+		w.Modify(watchedConfigMap("a", "13"))
+		w.Delete(watchedConfigMap("b", "14"))
+		w.Stop()
+	}()
+
+	res, err := e.manager.handleWatchResources(context.Background(), makeRequest(map[string]any{
+		"version":        "v1",
+		"resource":       "configmaps",
+		"namespace":      "default",
+		"label_selector": "app=web",
+		"max_events":     float64(10),
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "watch_resources")
+	requireContains(t, out, "type: MODIFIED", "expected the MODIFIED event")
+	requireContains(t, out, "type: DELETED", "expected the DELETED event")
+	requireContains(t, out, "resource_version: \"14\"", "expected the resume token")
+	requireContains(t, out, "stopped_by: server closed the watch", "expected the stop reason")
+	if opts.Labels == nil || opts.Labels.String() != "app=web" {
+		t.Fatalf("expected the label selector to reach the watch, got %+v", opts)
+	}
+
+	objects := finalWatchObjects([]watchEvent{
+		{Type: "ADDED", Name: "a", Namespace: "default", object: watchedConfigMap("a", "11")},
+		{Type: "MODIFIED", Name: "a", Namespace: "default", object: watchedConfigMap("a", "13")},
+		{Type: "DELETED", Name: "b", Namespace: "default", object: watchedConfigMap("b", "14")},
+	})
+	if len(objects) != 1 {
+		t.Fatalf("expected only the surviving object, got %+v", objects)
+	}
+	if rv, _, _ := unstructured.NestedString(objects[0], "metadata", "resourceVersion"); rv != "13" {
+		t.Fatalf("expected the last state of 'a', got %q", rv)
+	}
+}
+
+func TestWatchResources_StopsOnCapAndCancel(t *testing.T) {
+	e := newFakeEnv(t)
+	w := watch.NewFakeWithChanSize(3, false)
+	e.serveWatch(w)
+	for _, name := range []string{"a", "b", "c"} {
+		w.Add(watchedConfigMap(name, "1"+name))
+	}
+
+	res, err := e.manager.handleWatchResources(context.Background(), makeRequest(map[string]any{
+		"version":    "v1",
+		"resource":   "configmaps",
+		"max_events": float64(2),
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "capped watch")
+	requireContains(t, out, "stopped_by: max_events", "expected the cap to end the watch")
+	if !w.IsStopped() {
+		t.Fatalf("expected the watch to be stopped")
+	}
+
+	// A cancelled MCP call tears the watch down instead of waiting it out
+	idle := watch.NewFake()
+	e.serveWatch(idle)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	res, err = e.manager.handleWatchResources(ctx, makeRequest(map[string]any{
+		"version":  "v1",
+		"resource": "configmaps",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	expectErr(t, res, "cancelled watch")
+	if !idle.IsStopped() {
+		t.Fatalf("expected the cancelled watch to be stopped")
+	}
+}
+
+func TestWatchResources_Rejects(t *testing.T) {
+	e := newFakeEnv(t)
+	e.provider.deniedNamespaces = []string{"kube-system"}
+
+	cases := []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{"denied namespace", map[string]any{"version": "v1", "resource": "configmaps", "namespace": "kube-system"}, "namespace kube-system is not allowed"},
+		{"timeout too long", map[string]any{"version": "v1", "resource": "configmaps", "timeout_seconds": float64(301)}, "timeout_seconds must be between 1 and 300"},
+		{"max_events zero", map[string]any{"version": "v1", "resource": "configmaps", "max_events": float64(0)}, "max_events must be between 1 and 1000"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := e.manager.handleWatchResources(context.Background(), makeRequest(tc.args))
+			if err != nil {
+				t.Fatalf("go-error: %v", err)
+			}
+			requireContains(t, expectErr(t, res, tc.name), tc.want, "unexpected error")
+		})
+	}
+
+	// Resuming from a compacted version asks the caller to start over
+	w := watch.NewFake()
+	e.serveWatch(w)
+	go w.Error(&metav1.Status{Status: metav1.StatusFailure, Code: http.StatusGone, Reason: metav1.StatusReasonExpired})
+	res, err := e.manager.handleWatchResources(context.Background(), makeRequest(map[string]any{
+		"version":          "v1",
+		"resource":         "configmaps",
+		"resource_version": "5",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	requireContains(t, expectErr(t, res, "expired"), "call again without 'resource_version'", "expected a restart hint")
+}
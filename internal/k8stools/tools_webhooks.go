@@ -0,0 +1,400 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"kubernetes-mcp/internal/authorization"
+	"kubernetes-mcp/internal/kubernetes"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	discoveryclientv1 "k8s.io/client-go/kubernetes/typed/discovery/v1"
+)
+
+var crdGVR = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+func (m *Manager) registerListWebhooks() {
+	tool := mcp.NewTool(m.toolName("list_webhooks"),
+		mcp.WithDescription(`List the admission webhooks (validating / mutating) and CRD conversion
+webhooks of the cluster, summarizing what each one intercepts and how it
+fails.
+
+Each entry reports the operations and resources its rules match, the
+namespace / object selectors, 'failure_policy', 'timeout_seconds' and the
+backend (in-cluster Service or URL). For Service backends the number of
+ready endpoints is included when readable: a webhook with
+'failure_policy: Fail' and no ready endpoints rejects every matching
+request.
+
+Use this to answer "why did my create / update hang or fail with a webhook
+error?". Narrow with 'resource' to see only the webhooks that intercept a
+given resource.`),
+		mcp.WithString("context", mcp.Description("Kubernetes context to target. If empty, uses the currently active MCP context.")),
+		mcp.WithString("type", mcp.Enum("all", "validating", "mutating", "conversion"), mcp.Description("Which webhooks to list. Defaults to 'all'.")),
+		mcp.WithString("resource", mcp.Description("Only webhooks intercepting this resource: plural name, optionally with its group. Examples: 'pods', 'deployments', 'apps/deployments', 'certificates.cert-manager.io'.")),
+		mcp.WithArray("yq_expressions", mcp.Description("Optional yq expressions applied to the YAML output. The output is a map with an 'items' list. Examples: '.items[] | select(.failure_policy == \"Fail\")', '.items[] | select(.ready_endpoints == 0) | .name'.")),
+		jqExpressionsParam(),
+	)
+	m.addTool(tool, m.handleListWebhooks)
+}
+
+func (m *Manager) handleListWebhooks(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	k8sContext := m.getContextParam(ctx, args)
+	webhookType, _ := args["type"].(string)
+	if webhookType == "" {
+		webhookType = "all"
+	}
+	switch webhookType {
+	case "all", "validating", "mutating", "conversion":
+	default:
+		return errorResult(fmt.Errorf("type must be one of all, validating, mutating, conversion; got %q", webhookType)), nil
+	}
+	resourceFilter, _ := args["resource"].(string)
+
+	wanted := map[string]schema.GroupVersionResource{
+		"validating": {Group: "admissionregistration.k8s.io", Version: "v1", Resource: "validatingwebhookconfigurations"},
+		"mutating":   {Group: "admissionregistration.k8s.io", Version: "v1", Resource: "mutatingwebhookconfigurations"},
+		"conversion": crdGVR,
+	}
+	if webhookType != "all" {
+		wanted = map[string]schema.GroupVersionResource{webhookType: wanted[webhookType]}
+	}
+
+	// Check authorization (real K8s resources, one per webhook source)
+	for _, gvr := range wanted {
+		if err := m.checkAuthorization(request, "list_webhooks", k8sContext, "", authorization.ResourceInfo{
+			Group:    gvr.Group,
+			Version:  gvr.Version,
+			Resource: gvr.Resource,
+		}); err != nil {
+			return errorResult(err), nil
+		}
+	}
+
+	client, err := m.clientManager.GetClient(k8sContext)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	// Each source is best effort, as in get_cluster_info: one the caller
+	// cannot read is reported under 'errors'.
+	var webhooks []map[string]any
+	sourceErrors := map[string]string{}
+
+	if _, ok := wanted["validating"]; ok {
+		list, err := client.Clientset.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			sourceErrors["validating"] = err.Error()
+		} else {
+			for _, cfg := range list.Items {
+				for _, wh := range cfg.Webhooks {
+					entry := summarizeAdmissionWebhook("validating", cfg.Name, wh.Name, wh.Rules, wh.ClientConfig,
+						wh.FailurePolicy, wh.TimeoutSeconds, wh.NamespaceSelector, wh.ObjectSelector)
+					webhooks = append(webhooks, entry)
+				}
+			}
+		}
+	}
+
+	if _, ok := wanted["mutating"]; ok {
+		list, err := client.Clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			sourceErrors["mutating"] = err.Error()
+		} else {
+			for _, cfg := range list.Items {
+				for _, wh := range cfg.Webhooks {
+					entry := summarizeAdmissionWebhook("mutating", cfg.Name, wh.Name, wh.Rules, wh.ClientConfig,
+						wh.FailurePolicy, wh.TimeoutSeconds, wh.NamespaceSelector, wh.ObjectSelector)
+					if wh.ReinvocationPolicy != nil {
+						entry["reinvocation_policy"] = string(*wh.ReinvocationPolicy)
+					}
+					webhooks = append(webhooks, entry)
+				}
+			}
+		}
+	}
+
+	if _, ok := wanted["conversion"]; ok {
+		list, err := client.DynamicClient.Resource(crdGVR).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			sourceErrors["conversion"] = err.Error()
+		} else {
+			for _, item := range list.Items {
+				var crd apiextensionsv1.CustomResourceDefinition
+				if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &crd); err != nil {
+					continue
+				}
+				if entry := summarizeConversionWebhook(&crd); entry != nil {
+					webhooks = append(webhooks, entry)
+				}
+			}
+		}
+	}
+
+	var target schema.GroupResource
+	groupKnown := false
+	if resourceFilter != "" {
+		target, groupKnown = m.parseWebhookFilter(client, resourceFilter)
+	}
+
+	items := make([]map[string]any, 0, len(webhooks))
+	for _, entry := range webhooks {
+		if resourceFilter != "" && !webhookMatchesResource(entry, target, !groupKnown) {
+			continue
+		}
+		delete(entry, "match")
+
+		if svc, ok := entry["service"].(map[string]string); ok {
+			if ready, ok := m.serviceReadyEndpoints(ctx, request, client.Clientset.DiscoveryV1(), k8sContext, svc["namespace"], svc["name"]); ok {
+				entry["ready_endpoints"] = ready
+			}
+		}
+		items = append(items, entry)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i]["type"] != items[j]["type"] {
+			return items[i]["type"].(string) < items[j]["type"].(string)
+		}
+		return items[i]["name"].(string) < items[j]["name"].(string)
+	})
+
+	output := map[string]any{"items": items}
+	if len(sourceErrors) > 0 {
+		output["errors"] = sourceErrors
+	}
+
+	yamlOutput, err := objectToYAML(output)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	finalOutput, err := m.applyOutputFilters(yamlOutput, args)
+	if err != nil {
+		return errorResult(err), nil
+	}
+
+	return successResult(finalOutput), nil
+}
+
+// webhookMatch is the set of group/resource pairs a webhook intercepts,
+// kept on the entry until the 'resource' filter has run.
+type webhookMatch []schema.GroupResource
+
+// summarizeAdmissionWebhook flattens one webhook of a Validating or Mutating
+// configuration; both types share these fields.
+func summarizeAdmissionWebhook(kind, configuration, name string, rules []admissionregistrationv1.RuleWithOperations,
+	clientConfig admissionregistrationv1.WebhookClientConfig, failurePolicy *admissionregistrationv1.FailurePolicyType,
+	timeoutSeconds *int32, namespaceSelector, objectSelector *metav1.LabelSelector) map[string]any {
+
+	// API server defaults: failurePolicy Fail, timeoutSeconds 10
+	policy := string(admissionregistrationv1.Fail)
+	if failurePolicy != nil {
+		policy = string(*failurePolicy)
+	}
+	timeout := int32(10)
+	if timeoutSeconds != nil {
+		timeout = *timeoutSeconds
+	}
+
+	var match webhookMatch
+	ruleStrings := make([]string, 0, len(rules))
+	for _, r := range rules {
+		ops := make([]string, 0, len(r.Operations))
+		for _, op := range r.Operations {
+			ops = append(ops, string(op))
+		}
+		groups := strings.Join(r.APIGroups, ",")
+		if groups == "" {
+			groups = `""`
+		}
+		ruleStrings = append(ruleStrings, fmt.Sprintf("%s %s/%s %s",
+			strings.Join(ops, ","), groups, strings.Join(r.APIVersions, ","), strings.Join(r.Resources, ",")))
+
+		for _, g := range r.APIGroups {
+			for _, res := range r.Resources {
+				match = append(match, schema.GroupResource{Group: g, Resource: res})
+			}
+		}
+	}
+
+	entry := map[string]any{
+		"type":            kind,
+		"configuration":   configuration,
+		"name":            name,
+		"rules":           ruleStrings,
+		"failure_policy":  policy,
+		"timeout_seconds": timeout,
+		"match":           match,
+	}
+	if s := formatWebhookSelector(namespaceSelector); s != "" {
+		entry["namespace_selector"] = s
+	}
+	if s := formatWebhookSelector(objectSelector); s != "" {
+		entry["object_selector"] = s
+	}
+	addWebhookBackend(entry, clientConfig.Service, clientConfig.URL)
+	return entry
+}
+
+// summarizeConversionWebhook describes the conversion webhook of a CRD, or
+// returns nil when the CRD doesn't use one.
+func summarizeConversionWebhook(crd *apiextensionsv1.CustomResourceDefinition) map[string]any {
+	conversion := crd.Spec.Conversion
+	if conversion == nil || conversion.Strategy != apiextensionsv1.WebhookConverter || conversion.Webhook == nil {
+		return nil
+	}
+
+	versions := make([]string, 0, len(crd.Spec.Versions))
+	for _, v := range crd.Spec.Versions {
+		versions = append(versions, v.Name)
+	}
+
+	entry := map[string]any{
+		"type":     "conversion",
+		"name":     crd.Name,
+		"rules":    []string{fmt.Sprintf("convert %s/%s %s", crd.Spec.Group, strings.Join(versions, ","), crd.Spec.Names.Plural)},
+		"versions": versions,
+		"match":    webhookMatch{{Group: crd.Spec.Group, Resource: crd.Spec.Names.Plural}},
+	}
+	if cc := conversion.Webhook.ClientConfig; cc != nil {
+		var service *admissionregistrationv1.ServiceReference
+		if cc.Service != nil {
+			service = &admissionregistrationv1.ServiceReference{
+				Namespace: cc.Service.Namespace,
+				Name:      cc.Service.Name,
+				Path:      cc.Service.Path,
+				Port:      cc.Service.Port,
+			}
+		}
+		addWebhookBackend(entry, service, cc.URL)
+	}
+	return entry
+}
+
+// addWebhookBackend records where the API server sends the review
+func addWebhookBackend(entry map[string]any, service *admissionregistrationv1.ServiceReference, url *string) {
+	switch {
+	case service != nil:
+		port := int32(443)
+		if service.Port != nil {
+			port = *service.Port
+		}
+		path := ""
+		if service.Path != nil {
+			path = *service.Path
+		}
+		entry["backend"] = fmt.Sprintf("service %s/%s:%d%s", service.Namespace, service.Name, port, path)
+		entry["service"] = map[string]string{"namespace": service.Namespace, "name": service.Name}
+	case url != nil:
+		entry["backend"] = *url
+	}
+}
+
+// formatWebhookSelector renders a selector, or "" when it matches everything
+func formatWebhookSelector(selector *metav1.LabelSelector) string {
+	if selector == nil {
+		return ""
+	}
+	s := metav1.FormatLabelSelector(selector)
+	if s == "<none>" {
+		return ""
+	}
+	return s
+}
+
+// parseWebhookFilter splits 'resource' given as 'plural', 'group/plural' or
+// 'plural.group'. An unqualified plural is resolved through discovery
+// ('deployments' -> apps); when that fails the group is left open.
+func (m *Manager) parseWebhookFilter(client *kubernetes.Client, filter string) (schema.GroupResource, bool) {
+	if g, r, ok := strings.Cut(filter, "/"); ok {
+		return schema.GroupResource{Group: g, Resource: r}, true
+	}
+	if r, g, ok := strings.Cut(filter, "."); ok {
+		return schema.GroupResource{Group: g, Resource: r}, true
+	}
+	if gvr, err := client.RESTMapper.ResourceFor(schema.GroupVersionResource{Resource: filter}); err == nil {
+		return gvr.GroupResource(), true
+	}
+	return schema.GroupResource{Resource: filter}, false
+}
+
+// webhookMatchesResource reports whether an entry intercepts a resource.
+// With 'anyGroup' only the resource name has to match.
+func webhookMatchesResource(entry map[string]any, target schema.GroupResource, anyGroup bool) bool {
+	match, _ := entry["match"].(webhookMatch)
+	for _, gr := range match {
+		if !anyGroup && gr.Group != "*" && gr.Group != target.Group {
+			continue
+		}
+		// Rules may target subresources ('pods/exec') or wildcards ('*/*')
+		ruleResource, _, _ := strings.Cut(gr.Resource, "/")
+		if ruleResource == "*" || ruleResource == target.Resource {
+			return true
+		}
+	}
+	return false
+}
+
+// serviceReadyEndpoints counts the ready endpoints behind a webhook Service.
+// It is best effort: skipped when the caller may not read EndpointSlices in
+// the Service's namespace.
+func (m *Manager) serviceReadyEndpoints(ctx context.Context, request mcp.CallToolRequest, client discoveryclientv1.DiscoveryV1Interface,
+	k8sContext, namespace, name string) (int, bool) {
+
+	if err := m.checkAuthorization(request, "list_webhooks", k8sContext, namespace, authorization.ResourceInfo{
+		Group:    "discovery.k8s.io",
+		Version:  "v1",
+		Resource: "endpointslices",
+	}); err != nil {
+		return 0, false
+	}
+	if !m.clientManager.IsNamespaceAllowed(k8sContext, namespace) {
+		return 0, false
+	}
+	slices, err := client.EndpointSlices(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: discoveryv1.LabelServiceName + "=" + name,
+	})
+	if err != nil {
+		return 0, false
+	}
+
+	ready := 0
+	for _, slice := range slices.Items {
+		if slice.Labels[discoveryv1.LabelServiceName] != name {
+			continue
+		}
+		for _, ep := range slice.Endpoints {
+			if ep.Conditions.Ready == nil || *ep.Conditions.Ready {
+				ready++
+			}
+		}
+	}
+	return ready, true
+}
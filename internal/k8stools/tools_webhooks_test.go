@@ -0,0 +1,189 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8stools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func fakeWebhooks() (*admissionregistrationv1.ValidatingWebhookConfiguration, *admissionregistrationv1.MutatingWebhookConfiguration) {
+	fail, ignore := admissionregistrationv1.Fail, admissionregistrationv1.Ignore
+	timeout := int32(30)
+	path := "/validate"
+
+	validating := &admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy-engine"},
+		Webhooks: []admissionregistrationv1.ValidatingWebhook{{
+			Name: "validate.policy.example.com",
+			Rules: []admissionregistrationv1.RuleWithOperations{{
+				Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create, admissionregistrationv1.Update},
+				Rule:       admissionregistrationv1.Rule{APIGroups: []string{"apps"}, APIVersions: []string{"v1"}, Resources: []string{"deployments"}},
+			}},
+			ClientConfig: admissionregistrationv1.WebhookClientConfig{
+				Service: &admissionregistrationv1.ServiceReference{Namespace: "policy", Name: "webhook", Path: &path},
+			},
+			FailurePolicy:     &fail,
+			TimeoutSeconds:    &timeout,
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"policy": "enforced"}},
+		}},
+	}
+	url := "https://injector.example.com/mutate"
+	mutating := &admissionregistrationv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "sidecar-injector"},
+		Webhooks: []admissionregistrationv1.MutatingWebhook{{
+			Name: "inject.example.com",
+			Rules: []admissionregistrationv1.RuleWithOperations{{
+				Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+				Rule:       admissionregistrationv1.Rule{APIGroups: []string{""}, APIVersions: []string{"v1"}, Resources: []string{"pods"}},
+			}},
+			ClientConfig:  admissionregistrationv1.WebhookClientConfig{URL: &url},
+			FailurePolicy: &ignore,
+		}},
+	}
+	return validating, mutating
+}
+
+func TestListWebhooks(t *testing.T) {
+	validating, mutating := fakeWebhooks()
+	ready := false
+	e := newFakeEnv(t, validating, mutating, &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "policy", Name: "webhook-abc", Labels: map[string]string{discoveryv1.LabelServiceName: "webhook"}},
+		Endpoints:  []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.9"}, Conditions: discoveryv1.EndpointConditions{Ready: &ready}}},
+	})
+
+	crd := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]any{"name": "widgets.example.com"},
+		"spec": map[string]any{
+			"group": "example.com",
+			"names": map[string]any{"plural": "widgets", "kind": "Widget"},
+			"scope": "Namespaced",
+			"versions": []any{
+				map[string]any{"name": "v1", "served": true, "storage": true},
+				map[string]any{"name": "v1beta1", "served": true, "storage": false},
+			},
+			"conversion": map[string]any{
+				"strategy": "Webhook",
+				"webhook": map[string]any{
+					"conversionReviewVersions": []any{"v1"},
+					"clientConfig":             map[string]any{"service": map[string]any{"namespace": "widgets", "name": "converter"}},
+				},
+			},
+		},
+	}}
+	if _, err := e.dynamic.Resource(crdGVR).Create(context.Background(), crd, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("create crd: %v", err)
+	}
+
+	res, err := e.manager.handleListWebhooks(context.Background(), makeRequest(map[string]any{}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "list_webhooks")
+	requireContains(t, out, "CREATE,UPDATE apps/v1 deployments", "expected validating rule")
+	requireContains(t, out, "backend: service policy/webhook:443/validate", "expected service backend")
+	requireContains(t, out, "ready_endpoints: 0", "expected unready webhook backend")
+	requireContains(t, out, "namespace_selector: policy=enforced", "expected namespace selector")
+	requireContains(t, out, "timeout_seconds: 30", "expected timeout")
+	requireContains(t, out, "backend: https://injector.example.com/mutate", "expected URL backend")
+	requireContains(t, out, "failure_policy: Ignore", "expected mutating failure policy")
+	requireContains(t, out, "convert example.com/v1,v1beta1 widgets", "expected conversion webhook")
+	if strings.Contains(out, "match:") {
+		t.Fatalf("internal match data must not leak into the output:\n%s", out)
+	}
+
+	res, err = e.manager.handleListWebhooks(context.Background(), makeRequest(map[string]any{
+		"resource": "pods",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out = expectOK(t, res, "list_webhooks resource filter")
+	requireContains(t, out, "inject.example.com", "expected the pods webhook")
+	for _, unexpected := range []string{"validate.policy.example.com", "widgets.example.com"} {
+		if strings.Contains(out, unexpected) {
+			t.Fatalf("did not expect %q for resource=pods:\n%s", unexpected, out)
+		}
+	}
+
+	res, err = e.manager.handleListWebhooks(context.Background(), makeRequest(map[string]any{
+		"type":     "validating",
+		"resource": "apps/deployments",
+	}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out = expectOK(t, res, "list_webhooks validating")
+	requireContains(t, out, "validate.policy.example.com", "expected the deployments webhook")
+	if strings.Contains(out, "inject.example.com") {
+		t.Fatalf("type=validating must not list mutating webhooks:\n%s", out)
+	}
+}
+
+func TestListWebhooks_DeniedServiceNamespace(t *testing.T) {
+	validating, _ := fakeWebhooks()
+	e := newFakeEnv(t, validating)
+	e.provider.deniedNamespaces = []string{"policy"}
+
+	res, err := e.manager.handleListWebhooks(context.Background(), makeRequest(map[string]any{"type": "validating"}))
+	if err != nil {
+		t.Fatalf("go-error: %v", err)
+	}
+	out := expectOK(t, res, "list_webhooks")
+	requireContains(t, out, "validate.policy.example.com", "expected the webhook itself")
+	if strings.Contains(out, "ready_endpoints") {
+		t.Fatalf("endpoints in a denied namespace must not be read:\n%s", out)
+	}
+}
+
+func TestWebhookMatchesResource(t *testing.T) {
+	e := newFakeEnv(t)
+	client, _ := e.provider.GetClient(fakeContext)
+	entry := map[string]any{"match": webhookMatch{
+		{Group: "apps", Resource: "deployments"},
+		{Group: "", Resource: "pods/exec"},
+		{Group: "cert-manager.io", Resource: "*"},
+	}}
+	tests := []struct {
+		filter string
+		want   bool
+	}{
+		{"deployments", true},
+		{"apps/deployments", true},
+		{"deployments.apps", true},
+		{"extensions/deployments", false},
+		{"pods", true},
+		{"certificates.cert-manager.io", true},
+		{"services", false},
+		// Unknown to discovery: only the name is compared
+		{"widgets", true},
+	}
+	for _, tt := range tests {
+		target, groupKnown := e.manager.parseWebhookFilter(client, tt.filter)
+		if got := webhookMatchesResource(entry, target, !groupKnown); got != tt.want {
+			t.Errorf("filter %q: got %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+}
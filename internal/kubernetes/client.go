@@ -17,47 +17,98 @@ limitations under the License.
 package kubernetes
 
 import (
+	"crypto/x509"
+	"encoding/base64"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"kubernetes-mcp/api"
 
 	"github.com/fsnotify/fsnotify"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
 	memcache "k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/openapi"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/kube-openapi/pkg/spec3"
 	metricsv "k8s.io/metrics/pkg/client/clientset/versioned"
 )
 
 // Client holds all the kubernetes clients for a single context
 type Client struct {
 	Config          *rest.Config
-	Clientset       *kubernetes.Clientset
+	Clientset       kubernetes.Interface
 	DynamicClient   dynamic.Interface
-	MetricsClient   *metricsv.Clientset
+	MetadataClient  metadata.Interface
+	MetricsClient   metricsv.Interface
 	DiscoveryClient discovery.CachedDiscoveryInterface
 	RESTMapper      meta.ResettableRESTMapper
+
+	// StreamClientset and StreamDynamicClient are the clients for watches
+	// and log follows, which outlive the request timeout of the others and
+	// are bounded by their own deadline. See StreamingClientset.
+	StreamClientset     kubernetes.Interface
+	StreamDynamicClient dynamic.Interface
+
+	// OpenAPIClient overrides the OpenAPI v3 client served by discovery
+	OpenAPIClient openapi.Client
+
+	// shortNames maps resource short names to their resource; see
+	// ResourceForShortName.
+	shortNamesMu sync.Mutex
+	shortNames   map[string]schema.GroupResource
+
+	// openAPIDocs holds the parsed OpenAPI v3 documents; see
+	// OpenAPIDocument.
+	openAPIMu   sync.Mutex
+	openAPIDocs map[schema.GroupVersion]*spec3.OpenAPI
+
+	// transport is the authenticated transport under the re-authentication
+	// wrapper, used to retry a rejected request with fresh credentials
+	transport http.RoundTripper
 }
 
-// ClientManager manages multiple kubernetes clients for different contexts
+// ClientManager manages multiple kubernetes clients for different contexts.
+// Clients are built on first use, so a context that cannot be initialized
+// only fails the calls that target it.
 type ClientManager struct {
-	logger         *slog.Logger
-	config         *api.KubernetesConfig
+	logger *slog.Logger
+
+	// config is swapped by Reload; read it with config.Load()
+	config         atomic.Pointer[api.KubernetesConfig]
 	contextsByName map[string]api.KubernetesContextConfig
 	clients        map[string]*Client
 	mutex          sync.RWMutex
 	currentContext string
 
+	// sessionContexts holds the context each MCP session switched to, so
+	// switch_context in one session never moves another one
+	sessionContexts map[string]string
+
+	// initErrors holds the last client initialization failure per context,
+	// cleared once a client is built
+	initErrors map[string]error
+
+	// loadConfig resolves the rest.Config of a context; restConfigFor
+	// unless replaced in tests
+	loadConfig func(ctxConfig api.KubernetesContextConfig) (*rest.Config, error)
+
 	// File watching
 	watcher        *fsnotify.Watcher
 	fileToContexts map[string][]string // kubeconfig path -> context names
@@ -72,26 +123,25 @@ func NewClientManager(logger *slog.Logger, config *api.KubernetesConfig) (*Clien
 	}
 
 	cm := &ClientManager{
-		logger:         logger,
-		config:         config,
-		contextsByName: make(map[string]api.KubernetesContextConfig),
-		clients:        make(map[string]*Client),
-		currentContext: config.DefaultContext,
-		watcher:        watcher,
-		fileToContexts: make(map[string][]string),
-		stopChan:       make(chan struct{}),
+		logger:          logger,
+		contextsByName:  make(map[string]api.KubernetesContextConfig),
+		clients:         make(map[string]*Client),
+		initErrors:      make(map[string]error),
+		sessionContexts: make(map[string]string),
+		currentContext:  config.DefaultContext,
+		watcher:         watcher,
+		fileToContexts:  make(map[string][]string),
+		stopChan:        make(chan struct{}),
 	}
 
-	// Initialize clients for explicit contexts
+	cm.config.Store(config)
+	cm.loadConfig = cm.restConfigFor
+
+	// Register explicit contexts; their clients are built on first use
 	for _, ctxConfig := range config.Contexts {
 		if _, exists := cm.contextsByName[ctxConfig.Name]; exists {
 			return nil, fmt.Errorf("duplicate context name %q in explicit contexts", ctxConfig.Name)
 		}
-		client, err := cm.createClient(ctxConfig.Name, ctxConfig)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create client for context %s: %w", ctxConfig.Name, err)
-		}
-		cm.clients[ctxConfig.Name] = client
 		cm.contextsByName[ctxConfig.Name] = ctxConfig
 
 		// Track the kubeconfig file for inotify-based reloads. We only watch
@@ -128,12 +178,12 @@ func NewClientManager(logger *slog.Logger, config *api.KubernetesConfig) (*Clien
 	return cm, nil
 }
 
-// refreshDiscoveryLoop periodically resets the RESTMapper / discovery cache
-// of every client so newly installed CRDs and API changes get picked up.
-// RESTMapper.Reset() also calls Invalidate() on the underlying cached
-// discovery client, so we don't need to invalidate it separately.
+// refreshDiscoveryLoop periodically resets the discovery caches (RESTMapper,
+// short names) of every client so newly installed CRDs and API changes get
+// picked up. RESTMapper.Reset() also calls Invalidate() on the underlying
+// cached discovery client, so we don't need to invalidate it separately.
 func (cm *ClientManager) refreshDiscoveryLoop() {
-	interval := cm.config.Discovery.RefreshInterval
+	interval := cm.config.Load().Discovery.RefreshInterval
 	if interval <= 0 {
 		interval = 10 * time.Minute
 	}
@@ -151,16 +201,15 @@ func (cm *ClientManager) refreshDiscoveryLoop() {
 			}
 			cm.mutex.RUnlock()
 			for _, c := range clients {
-				if c.RESTMapper != nil {
-					c.RESTMapper.Reset()
-				}
+				c.ResetDiscovery()
 			}
 			cm.logger.Debug("refreshed kubernetes discovery cache", "clients", len(clients))
 		}
 	}
 }
 
-// loadContextsFromDir loads kubeconfig files from a directory
+// loadContextsFromDir registers the kubeconfig files of a directory. A file
+// that can't be parsed is skipped with a warning rather than failing startup.
 func (cm *ClientManager) loadContextsFromDir(dir string) error {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
@@ -182,12 +231,14 @@ func (cm *ClientManager) loadContextsFromDir(dir string) error {
 		// Load kubeconfig to extract current-context
 		kubeconfig, err := clientcmd.LoadFromFile(kubeconfigPath)
 		if err != nil {
-			return fmt.Errorf("failed to load kubeconfig %s: %w", kubeconfigPath, err)
+			cm.logger.Warn("skipping unreadable kubeconfig in contexts directory", "kubeconfig", kubeconfigPath, "error", err)
+			continue
 		}
 
 		contextName := kubeconfig.CurrentContext
 		if contextName == "" {
-			return fmt.Errorf("kubeconfig %s has no current-context set", kubeconfigPath)
+			cm.logger.Warn("skipping kubeconfig without current-context in contexts directory", "kubeconfig", kubeconfigPath)
+			continue
 		}
 
 		// Check for collision
@@ -196,19 +247,11 @@ func (cm *ClientManager) loadContextsFromDir(dir string) error {
 				contextName, existing.Kubeconfig, kubeconfigPath)
 		}
 
-		ctxConfig := api.KubernetesContextConfig{
+		cm.contextsByName[contextName] = api.KubernetesContextConfig{
 			Name:       contextName,
 			Kubeconfig: kubeconfigPath,
 		}
 
-		client, err := cm.createClient(contextName, ctxConfig)
-		if err != nil {
-			return fmt.Errorf("failed to create client for context %s from %s: %w", contextName, kubeconfigPath, err)
-		}
-
-		cm.clients[contextName] = client
-		cm.contextsByName[contextName] = ctxConfig
-
 		// Track file for watching
 		cm.trackFile(kubeconfigPath, contextName)
 	}
@@ -259,10 +302,11 @@ func (cm *ClientManager) watchFiles() {
 			absPath, _ := filepath.Abs(event.Name)
 
 			// Check if this is a new file in the contexts directory
-			if event.Op&fsnotify.Create != 0 && cm.config.ContextsDir != "" {
-				absDir, err := filepath.Abs(cm.config.ContextsDir)
+			contextsDir := cm.config.Load().ContextsDir
+			if event.Op&fsnotify.Create != 0 && contextsDir != "" {
+				absDir, err := filepath.Abs(contextsDir)
 				if err != nil {
-					cm.logger.Warn("failed to get absolute path for contexts directory", "path", cm.config.ContextsDir, "error", err)
+					cm.logger.Warn("failed to get absolute path for contexts directory", "path", contextsDir, "error", err)
 					continue
 				}
 				if filepath.Dir(absPath) == absDir {
@@ -337,33 +381,37 @@ func (cm *ClientManager) loadNewContextFromFile(kubeconfigPath string) error {
 	cm.mutex.Lock()
 	defer cm.mutex.Unlock()
 
-	ctxConfig := api.KubernetesContextConfig{
+	// A context that is already defined keeps its configuration (in_cluster,
+	// impersonation, namespaces, ...). The same file written again only
+	// drops the client, which is rebuilt from the file on next use.
+	if existing, exists := cm.contextsByName[contextName]; exists {
+		existingPath, err := filepath.Abs(existing.Kubeconfig)
+		if err != nil {
+			existingPath = existing.Kubeconfig
+		}
+		if existing.Kubeconfig == "" || existingPath != kubeconfigPath {
+			cm.logger.Warn("skipping kubeconfig in contexts directory: context name already defined",
+				"context", contextName, "kubeconfig", kubeconfigPath, "defined_in", existing.Kubeconfig)
+			return nil
+		}
+		delete(cm.clients, contextName)
+		delete(cm.initErrors, contextName)
+		cm.logger.Info("reloaded kubeconfig from contexts directory", "context", contextName, "kubeconfig", kubeconfigPath)
+		return nil
+	}
+
+	cm.contextsByName[contextName] = api.KubernetesContextConfig{
 		Name:       contextName,
 		Kubeconfig: kubeconfigPath,
 	}
+	cm.fileToContexts[kubeconfigPath] = append(cm.fileToContexts[kubeconfigPath], contextName)
 
-	client, err := cm.createClient(contextName, ctxConfig)
-	if err != nil {
-		return fmt.Errorf("failed to create client: %w", err)
+	// Watch the new kubeconfig file for future changes
+	if err := cm.watcher.Add(kubeconfigPath); err != nil {
+		cm.logger.Warn("failed to watch new kubeconfig file", "path", kubeconfigPath, "error", err)
 	}
 
-	_, replacing := cm.contextsByName[contextName]
-
-	cm.clients[contextName] = client
-	cm.contextsByName[contextName] = ctxConfig
-
-	if !replacing {
-		cm.fileToContexts[kubeconfigPath] = append(cm.fileToContexts[kubeconfigPath], contextName)
-
-		// Watch the new kubeconfig file for future changes
-		if err := cm.watcher.Add(kubeconfigPath); err != nil {
-			cm.logger.Warn("failed to watch new kubeconfig file", "path", kubeconfigPath, "error", err)
-		}
-
-		cm.logger.Info("loaded new kubeconfig from contexts directory", "context", contextName, "kubeconfig", kubeconfigPath)
-	} else {
-		cm.logger.Info("replaced existing kubeconfig from contexts directory", "context", contextName, "kubeconfig", kubeconfigPath)
-	}
+	cm.logger.Info("loaded new kubeconfig from contexts directory", "context", contextName, "kubeconfig", kubeconfigPath)
 	return nil
 }
 
@@ -387,14 +435,104 @@ func (cm *ClientManager) reloadContextsForFile(filePath string) {
 
 		client, err := cm.createClient(contextName, ctxConfig)
 		if err != nil {
+			// Keep serving with the previous client, if there is one
 			cm.logger.Error("failed to reload kubernetes client", "context", contextName, "error", err)
+			cm.initErrors[contextName] = err
 			continue
 		}
 
 		cm.clients[contextName] = client
+		delete(cm.initErrors, contextName)
 	}
 }
 
+// Reload applies a new Kubernetes configuration. Explicit contexts that
+// were added, removed or changed are updated; the clients of changed ones
+// are rebuilt on next use, while the others keep theirs (and their
+// discovery caches). Changing the global qps, burst or request_timeout
+// counts as changing every context. Calls in flight keep the client they
+// started with. 'contexts_dir' and 'discovery' are only read at startup.
+func (cm *ClientManager) Reload(config *api.KubernetesConfig) error {
+	explicit := make(map[string]api.KubernetesContextConfig, len(config.Contexts))
+	for _, ctxConfig := range config.Contexts {
+		if _, exists := explicit[ctxConfig.Name]; exists {
+			return fmt.Errorf("duplicate context name %q in explicit contexts", ctxConfig.Name)
+		}
+		explicit[ctxConfig.Name] = ctxConfig
+	}
+
+	old := cm.config.Load()
+	if config.ContextsDir != old.ContextsDir {
+		cm.logger.Warn("kubernetes.contexts_dir changed, restart the server to apply it",
+			"current", old.ContextsDir, "configured", config.ContextsDir)
+	}
+	rebuildAll := config.QPS != old.QPS || config.Burst != old.Burst ||
+		config.Tools.RequestTimeout != old.Tools.RequestTimeout
+
+	cm.mutex.Lock()
+	defer cm.mutex.Unlock()
+
+	for _, previous := range old.Contexts {
+		if _, kept := explicit[previous.Name]; kept {
+			continue
+		}
+		cm.dropContext(previous.Name)
+		cm.logger.Info("removed kubernetes context on config reload", "context", previous.Name)
+	}
+
+	for name, ctxConfig := range explicit {
+		current, known := cm.contextsByName[name]
+		if known && !rebuildAll && reflect.DeepEqual(current, ctxConfig) {
+			continue
+		}
+		cm.contextsByName[name] = ctxConfig
+		delete(cm.clients, name)
+		delete(cm.initErrors, name)
+		if ctxConfig.Kubeconfig != "" && !slices.Contains(cm.fileToContexts[absPath(ctxConfig.Kubeconfig)], name) {
+			cm.trackFile(ctxConfig.Kubeconfig, name)
+		}
+		if known {
+			cm.logger.Info("kubernetes context changed on config reload, its client is rebuilt on next use", "context", name)
+		} else {
+			cm.logger.Info("added kubernetes context on config reload", "context", name)
+		}
+	}
+
+	// Calls without 'context' must not land on a context that is gone: the
+	// current context falls back to the new default, sessions to it too
+	_, currentKept := cm.contextsByName[cm.currentContext]
+	if !currentKept || (config.DefaultContext != old.DefaultContext && cm.currentContext == old.DefaultContext) {
+		cm.currentContext = config.DefaultContext
+	}
+	for session, context := range cm.sessionContexts {
+		if _, kept := cm.contextsByName[context]; !kept {
+			delete(cm.sessionContexts, session)
+		}
+	}
+	cm.config.Store(config)
+	return nil
+}
+
+// dropContext forgets a context and its client. The caller holds the lock
+// and moves the current context and sessions off it.
+func (cm *ClientManager) dropContext(name string) {
+	delete(cm.contextsByName, name)
+	delete(cm.clients, name)
+	delete(cm.initErrors, name)
+	for file, contexts := range cm.fileToContexts {
+		cm.fileToContexts[file] = slices.DeleteFunc(contexts, func(c string) bool { return c == name })
+	}
+}
+
+// absPath returns the absolute form of a path, or the path itself when it
+// cannot be resolved
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
 // Stop stops the file watcher and cleans up resources
 func (cm *ClientManager) Stop() {
 	close(cm.stopChan)
@@ -404,8 +542,158 @@ func (cm *ClientManager) Stop() {
 }
 
 // createClient creates a kubernetes client for a given context configuration.
+// Its clients share one HTTP client whose transport reloads the credentials
+// when the API server rejects them; see reauthTransport.
+func (cm *ClientManager) createClient(name string, ctxConfig api.KubernetesContextConfig) (*Client, error) {
+	restConfig, err := cm.loadConfig(ctxConfig)
+	if err != nil {
+		return nil, err
+	}
+	if ctxConfig.InsecureSkipTLSVerify {
+		cm.logger.Warn("TLS verification is DISABLED for this context: the API server's identity is not checked, use only for test clusters",
+			"context", name, "host", restConfig.Host)
+	}
+
+	// Every request is bounded by the request timeout, discovery included,
+	// which can't carry the tool call's context
+	restConfig.Timeout = cm.requestTimeout()
+
+	// A config without TLS or auth settings resolves to http.DefaultClient;
+	// work on a copy so the shared one is never modified.
+	sharedHTTP, err := rest.HTTPClientFor(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+	httpClient := *sharedHTTP
+	transport := httpClient.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	httpClient.Transport = &reauthTransport{
+		current: transport,
+		reload:  func() (http.RoundTripper, error) { return cm.reloadClient(name) },
+	}
+
+	// Create clientset
+	clientset, err := kubernetes.NewForConfigAndClient(restConfig, &httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create clientset: %w", err)
+	}
+
+	// Create dynamic client
+	dynamicClient, err := dynamic.NewForConfigAndClient(restConfig, &httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	// The request timeout would cut watches and log follows: they get
+	// clients without it. SPDY streams (exec, port-forward) build their own
+	// transport and never had it.
+	streamHTTP := httpClient
+	streamHTTP.Timeout = 0
+	streamClientset, err := kubernetes.NewForConfigAndClient(restConfig, &streamHTTP)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create streaming clientset: %w", err)
+	}
+	streamDynamicClient, err := dynamic.NewForConfigAndClient(restConfig, &streamHTTP)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create streaming dynamic client: %w", err)
+	}
+
+	// Create metadata client (PartialObjectMetadata reads)
+	metadataClient, err := metadata.NewForConfigAndClient(restConfig, &httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metadata client: %w", err)
+	}
+
+	// Create metrics client (may fail if metrics-server is not installed).
+	// Left as a nil interface on failure so callers can test for nil.
+	var metricsClient metricsv.Interface
+	if mc, err := metricsv.NewForConfigAndClient(restConfig, &httpClient); err == nil {
+		metricsClient = mc
+	}
+
+	// Create cached discovery client and lazy RESTMapper
+	cachedDiscovery := memcache.NewMemCacheClient(clientset.Discovery())
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(cachedDiscovery)
+
+	return &Client{
+		Config:          restConfig,
+		Clientset:       clientset,
+		DynamicClient:   dynamicClient,
+		MetadataClient:  metadataClient,
+		MetricsClient:   metricsClient,
+		DiscoveryClient: cachedDiscovery,
+		RESTMapper:      mapper,
+
+		StreamClientset:     streamClientset,
+		StreamDynamicClient: streamDynamicClient,
+
+		transport: transport,
+	}, nil
+}
+
+// StreamingClientset returns the clientset for long-lived streams, or the
+// regular one when the client was built without it
+func (c *Client) StreamingClientset() kubernetes.Interface {
+	if c.StreamClientset != nil {
+		return c.StreamClientset
+	}
+	return c.Clientset
+}
+
+// StreamingDynamicClient returns the dynamic client for watches, or the
+// regular one when the client was built without it
+func (c *Client) StreamingDynamicClient() dynamic.Interface {
+	if c.StreamDynamicClient != nil {
+		return c.StreamDynamicClient
+	}
+	return c.DynamicClient
+}
+
+// defaultRequestTimeout mirrors the default of 'kubernetes.tools.request_timeout'
+const defaultRequestTimeout = 30 * time.Second
+
+// requestTimeout bounds each API request of the clients, on top of the
+// tool call's context: the configured request timeout.
+func (cm *ClientManager) requestTimeout() time.Duration {
+	if timeout := cm.config.Load().Tools.RequestTimeout; timeout > 0 {
+		return timeout
+	}
+	return defaultRequestTimeout
+}
+
+// reloadClient replaces the cached client of a context with one built from
+// freshly loaded credentials, and returns its transport so a rejected
+// request can be retried with them.
+func (cm *ClientManager) reloadClient(name string) (http.RoundTripper, error) {
+	cm.mutex.RLock()
+	ctxConfig, known := cm.contextsByName[name]
+	cm.mutex.RUnlock()
+	if !known {
+		return nil, fmt.Errorf("context %s not found", name)
+	}
+
+	client, err := cm.createClient(name, ctxConfig)
+	if err != nil {
+		cm.logger.Error("failed to reload kubernetes client after an authentication failure", "context", name, "error", err)
+		return nil, err
+	}
+	cm.logger.Info("reloaded kubernetes client after the API server rejected its credentials", "context", name)
+
+	cm.mutex.Lock()
+	cm.clients[name] = client
+	delete(cm.initErrors, name)
+	cm.mutex.Unlock()
+	return client.transport, nil
+}
+
+// restConfigFor resolves the rest.Config of a context configuration.
 //
-// Resolution order (mirrors `kubectl` / client-go semantics):
+// With ctxConfig.InCluster set, only the in-cluster configuration is used:
+// no kubeconfig is ever read, so a stray ~/.kube/config baked into an image
+// cannot redirect the server. Otherwise the resolution order mirrors
+// `kubectl` / client-go semantics:
 //  1. If ctxConfig.Kubeconfig is set explicitly, use that file. A failure
 //     is fatal — we don't silently fall back, otherwise the operator may
 //     end up talking to the wrong cluster.
@@ -418,11 +706,19 @@ func (cm *ClientManager) Stop() {
 //     mounted at /var/run/secrets/kubernetes.io/serviceaccount.
 //  5. If none of the above succeed, return a single descriptive error
 //     listing what was tried.
-func (cm *ClientManager) createClient(name string, ctxConfig api.KubernetesContextConfig) (*Client, error) {
+func (cm *ClientManager) restConfigFor(ctxConfig api.KubernetesContextConfig) (*rest.Config, error) {
 	var restConfig *rest.Config
 	var err error
 
 	switch {
+	case ctxConfig.InCluster:
+		if ctxConfig.Kubeconfig != "" || ctxConfig.KubeconfigContext != "" {
+			return nil, fmt.Errorf("in_cluster cannot be combined with kubeconfig or kubeconfig_context")
+		}
+		if restConfig, err = rest.InClusterConfig(); err != nil {
+			return nil, fmt.Errorf("in_cluster is set but in-cluster config is not available: %w", err)
+		}
+
 	case ctxConfig.Kubeconfig != "":
 		// Explicit path. Honour KubeconfigContext if provided.
 		loadingRules := &clientcmd.ClientConfigLoadingRules{ExplicitPath: ctxConfig.Kubeconfig}
@@ -443,37 +739,88 @@ func (cm *ClientManager) createClient(name string, ctxConfig api.KubernetesConte
 		}
 	}
 
-	// Create clientset
-	clientset, err := kubernetes.NewForConfig(restConfig)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create clientset: %w", err)
+	if err := applyTLSOverrides(restConfig, ctxConfig); err != nil {
+		return nil, err
+	}
+	if err := applyRateLimits(restConfig, ctxConfig, cm.config.Load()); err != nil {
+		return nil, err
+	}
+	if err := applyImpersonation(restConfig, ctxConfig); err != nil {
+		return nil, err
 	}
+	return restConfig, nil
+}
 
-	// Create dynamic client
-	dynamicClient, err := dynamic.NewForConfig(restConfig)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+// applyRateLimits sets the client-side QPS and burst of a context: its own
+// values first, then the global defaults, else client-go's.
+func applyRateLimits(restConfig *rest.Config, ctxConfig api.KubernetesContextConfig, global *api.KubernetesConfig) error {
+	if ctxConfig.QPS < 0 || global.QPS < 0 {
+		return fmt.Errorf("qps must not be negative")
+	}
+	if ctxConfig.Burst < 0 || global.Burst < 0 {
+		return fmt.Errorf("burst must not be negative")
 	}
 
-	// Create metrics client (may fail if metrics-server is not installed)
-	metricsClient, err := metricsv.NewForConfig(restConfig)
-	if err != nil {
-		// Metrics client is optional, log warning but continue
-		metricsClient = nil
+	switch {
+	case ctxConfig.QPS > 0:
+		restConfig.QPS = ctxConfig.QPS
+	case global.QPS > 0:
+		restConfig.QPS = global.QPS
+	}
+	switch {
+	case ctxConfig.Burst > 0:
+		restConfig.Burst = ctxConfig.Burst
+	case global.Burst > 0:
+		restConfig.Burst = global.Burst
 	}
+	return nil
+}
 
-	// Create cached discovery client and lazy RESTMapper
-	cachedDiscovery := memcache.NewMemCacheClient(clientset.Discovery())
-	mapper := restmapper.NewDeferredDiscoveryRESTMapper(cachedDiscovery)
+// applyTLSOverrides applies the per-context CA and TLS verification settings
+// on top of whatever the kubeconfig or in-cluster config resolved.
+func applyTLSOverrides(restConfig *rest.Config, ctxConfig api.KubernetesContextConfig) error {
+	if ctxConfig.CAData != "" && ctxConfig.CAFile != "" {
+		return fmt.Errorf("ca_data and ca_file are mutually exclusive")
+	}
+	if ctxConfig.InsecureSkipTLSVerify && (ctxConfig.CAData != "" || ctxConfig.CAFile != "") {
+		return fmt.Errorf("insecure_skip_tls_verify cannot be combined with ca_data or ca_file")
+	}
 
-	return &Client{
-		Config:          restConfig,
-		Clientset:       clientset,
-		DynamicClient:   dynamicClient,
-		MetricsClient:   metricsClient,
-		DiscoveryClient: cachedDiscovery,
-		RESTMapper:      mapper,
-	}, nil
+	tls := &restConfig.TLSClientConfig
+	switch {
+	case ctxConfig.InsecureSkipTLSVerify:
+		// client-go refuses a CA together with the insecure flag, so drop
+		// whatever CA the kubeconfig carried.
+		tls.Insecure = true
+		tls.CAData, tls.CAFile = nil, ""
+
+	case ctxConfig.CAData != "":
+		data := []byte(ctxConfig.CAData)
+		if !strings.Contains(ctxConfig.CAData, "-----BEGIN") {
+			decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(ctxConfig.CAData))
+			if err != nil {
+				return fmt.Errorf("ca_data is neither PEM nor base64-encoded PEM: %w", err)
+			}
+			data = decoded
+		}
+		if !x509.NewCertPool().AppendCertsFromPEM(data) {
+			return fmt.Errorf("ca_data contains no valid PEM certificate")
+		}
+		tls.CAData, tls.CAFile = data, ""
+		tls.Insecure = false
+
+	case ctxConfig.CAFile != "":
+		data, err := os.ReadFile(ctxConfig.CAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read ca_file: %w", err)
+		}
+		if !x509.NewCertPool().AppendCertsFromPEM(data) {
+			return fmt.Errorf("ca_file %q contains no valid PEM certificate", ctxConfig.CAFile)
+		}
+		tls.CAFile, tls.CAData = ctxConfig.CAFile, nil
+		tls.Insecure = false
+	}
+	return nil
 }
 
 // resolveImplicitConfig builds a *rest.Config without an explicit kubeconfig
@@ -541,57 +888,104 @@ func inClusterConfigOrError(prevErr error) (*rest.Config, error) {
 	return nil, fmt.Errorf("no kubeconfig found and in-cluster config not available: %w", err)
 }
 
-// GetClient returns the client for a given context
+// GetClient returns the client for a given context, building it on first
+// use. A failed build is retried on the next call, so a context that was
+// unavailable recovers without a restart.
 func (cm *ClientManager) GetClient(context string) (*Client, error) {
 	cm.mutex.RLock()
-	defer cm.mutex.RUnlock()
-
 	if context == "" {
 		context = cm.currentContext
 	}
-
 	client, ok := cm.clients[context]
-	if !ok {
+	ctxConfig, known := cm.contextsByName[context]
+	cm.mutex.RUnlock()
+
+	if ok {
+		return client, nil
+	}
+	if !known {
 		return nil, fmt.Errorf("context %s not found", context)
 	}
 
+	client, err := cm.createClient(context, ctxConfig)
+
+	cm.mutex.Lock()
+	defer cm.mutex.Unlock()
+	if err != nil {
+		// Log a failure once, not on every call that retries it
+		if prev, seen := cm.initErrors[context]; !seen || prev.Error() != err.Error() {
+			cm.logger.Warn("failed to initialize kubernetes client", "context", context, "error", err)
+		}
+		cm.initErrors[context] = err
+		return nil, fmt.Errorf("context %s is not available: %w", context, err)
+	}
+
+	// A concurrent call may have built it first
+	if existing, ok := cm.clients[context]; ok {
+		return existing, nil
+	}
+	cm.clients[context] = client
+	delete(cm.initErrors, context)
 	return client, nil
 }
 
-// GetCurrentContext returns the current context name
-func (cm *ClientManager) GetCurrentContext() string {
+// GetCurrentContext returns the current context of an MCP session: the one
+// it switched to, else the server's default. An empty session reads the
+// default.
+func (cm *ClientManager) GetCurrentContext(session string) string {
 	cm.mutex.RLock()
 	defer cm.mutex.RUnlock()
+
+	if context, ok := cm.sessionContexts[session]; ok && session != "" {
+		return context
+	}
 	return cm.currentContext
 }
 
-// SetCurrentContext sets the current context
-func (cm *ClientManager) SetCurrentContext(context string) error {
+// SetCurrentContext sets the current context of an MCP session, leaving
+// every other session untouched. An empty session sets the server's
+// default, used by sessions that never switched.
+func (cm *ClientManager) SetCurrentContext(session, context string) error {
 	cm.mutex.Lock()
 	defer cm.mutex.Unlock()
 
-	if _, ok := cm.clients[context]; !ok {
+	if _, ok := cm.contextsByName[context]; !ok {
 		return fmt.Errorf("context %s not found", context)
 	}
 
-	cm.currentContext = context
+	if session == "" {
+		cm.currentContext = context
+		return nil
+	}
+	cm.sessionContexts[session] = context
 	return nil
 }
 
+// ForgetSession drops the current context of an MCP session that ended
+func (cm *ClientManager) ForgetSession(session string) {
+	cm.mutex.Lock()
+	defer cm.mutex.Unlock()
+	delete(cm.sessionContexts, session)
+}
+
 // ListContexts returns all available context names
 func (cm *ClientManager) ListContexts() []string {
 	cm.mutex.RLock()
 	defer cm.mutex.RUnlock()
 
-	contexts := make([]string, 0, len(cm.clients))
-	for name := range cm.clients {
+	contexts := make([]string, 0, len(cm.contextsByName))
+	for name := range cm.contextsByName {
 		contexts = append(contexts, name)
 	}
+	sort.Strings(contexts)
 	return contexts
 }
 
 // GetContextConfig returns the configuration for a given context
 func (cm *ClientManager) GetContextConfig(context string) (api.KubernetesContextConfig, bool) {
+	cm.mutex.RLock()
+	defer cm.mutex.RUnlock()
+
 	if context == "" {
 		context = cm.currentContext
 	}
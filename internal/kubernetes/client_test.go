@@ -0,0 +1,397 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"kubernetes-mcp/api"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: https://127.0.0.1:6443
+users:
+- name: test
+  user:
+    token: test-token
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: test
+current-context: test
+`
+
+func TestClientManager_LazyContexts(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.kubeconfig")
+	if err := os.WriteFile(good, []byte(testKubeconfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cm, err := NewClientManager(slog.New(slog.NewTextHandler(io.Discard, nil)), &api.KubernetesConfig{
+		DefaultContext: "good",
+		Contexts: []api.KubernetesContextConfig{
+			{Name: "good", Kubeconfig: good},
+			{Name: "broken", Kubeconfig: filepath.Join(dir, "missing.kubeconfig")},
+		},
+	})
+	if err != nil {
+		t.Fatalf("one broken context must not fail startup: %v", err)
+	}
+	defer cm.Stop()
+
+	if got := cm.ListContexts(); !slices.Equal(got, []string{"broken", "good"}) {
+		t.Fatalf("expected every configured context listed, got %v", got)
+	}
+
+	client, err := cm.GetClient("good")
+	if err != nil {
+		t.Fatalf("GetClient(good): %v", err)
+	}
+	if again, _ := cm.GetClient(""); again != client {
+		t.Fatalf("expected the client built once and cached")
+	}
+
+	_, err = cm.GetClient("broken")
+	if err == nil || !strings.Contains(err.Error(), "context broken is not available") {
+		t.Fatalf("expected the init error surfaced on use, got %v", err)
+	}
+	if _, recorded := cm.initErrors["broken"]; !recorded {
+		t.Fatalf("expected the init error recorded")
+	}
+
+	// A context that becomes usable recovers on the next call
+	if err := os.WriteFile(filepath.Join(dir, "missing.kubeconfig"), []byte(testKubeconfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cm.GetClient("broken"); err != nil {
+		t.Fatalf("expected the context to recover, got %v", err)
+	}
+	if _, recorded := cm.initErrors["broken"]; recorded {
+		t.Fatalf("expected the init error cleared")
+	}
+
+	if err := cm.SetCurrentContext("", "broken"); err != nil {
+		t.Fatalf("SetCurrentContext: %v", err)
+	}
+	if _, err := cm.GetClient("nope"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected unknown context error, got %v", err)
+	}
+}
+
+func TestClientManager_ReloadOnUnauthorized(t *testing.T) {
+	// The API server only accepts the rotated token
+	var validToken atomic.Value
+	validToken.Store("fresh")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("Authorization") != "Bearer "+validToken.Load().(string) {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = io.WriteString(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Unauthorized","code":401}`)
+			return
+		}
+		_, _ = io.WriteString(w, `{"kind":"NamespaceList","apiVersion":"v1","items":[{"metadata":{"name":"default"}}]}`)
+	}))
+	defer server.Close()
+
+	cm, err := NewClientManager(slog.New(slog.NewTextHandler(io.Discard, nil)), &api.KubernetesConfig{
+		DefaultContext: "test",
+		Contexts:       []api.KubernetesContextConfig{{Name: "test"}},
+	})
+	if err != nil {
+		t.Fatalf("NewClientManager: %v", err)
+	}
+	defer cm.Stop()
+
+	// The loader hands out the expired token first, then the rotated one
+	var loads atomic.Int32
+	cm.loadConfig = func(api.KubernetesContextConfig) (*rest.Config, error) {
+		token := "fresh"
+		if loads.Add(1) == 1 {
+			token = "expired"
+		}
+		return &rest.Config{Host: server.URL, BearerToken: token}, nil
+	}
+
+	stale, err := cm.GetClient("test")
+	if err != nil {
+		t.Fatalf("GetClient: %v", err)
+	}
+	list, err := stale.Clientset.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("expected the rejected request retried with fresh credentials, got %v", err)
+	}
+	if len(list.Items) != 1 || loads.Load() != 2 {
+		t.Fatalf("expected one reload and the list, got %d loads and %d items", loads.Load(), len(list.Items))
+	}
+
+	// The cached client is replaced; the stale one keeps the new credentials
+	if fresh, _ := cm.GetClient("test"); fresh == stale || fresh.Config.BearerToken != "fresh" {
+		t.Fatalf("expected the cached client rebuilt with the new credentials")
+	}
+	if _, err := stale.Clientset.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{}); err != nil || loads.Load() != 2 {
+		t.Fatalf("expected the stale client to reuse the reloaded transport, got %v after %d loads", err, loads.Load())
+	}
+
+	// Credentials that stay invalid fail after a single reload
+	validToken.Store("revoked")
+	client, _ := cm.GetClient("test")
+	_, err = client.Clientset.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{})
+	if !apierrors.IsUnauthorized(err) {
+		t.Fatalf("expected Unauthorized, got %v", err)
+	}
+	if loads.Load() != 3 {
+		t.Fatalf("expected exactly one more reload, got %d loads", loads.Load())
+	}
+	if _, err = client.Clientset.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{}); !apierrors.IsUnauthorized(err) || loads.Load() != 3 {
+		t.Fatalf("expected reloads spaced out, got %v after %d loads", err, loads.Load())
+	}
+}
+
+func TestClientManager_RequestTimeout(t *testing.T) {
+	// A hung API server: answers only once the client gives up
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	cm, err := NewClientManager(slog.New(slog.NewTextHandler(io.Discard, nil)), &api.KubernetesConfig{
+		DefaultContext: "test",
+		Contexts:       []api.KubernetesContextConfig{{Name: "test"}},
+		Tools:          api.KubernetesToolsConfig{RequestTimeout: 50 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("NewClientManager: %v", err)
+	}
+	defer cm.Stop()
+	cm.loadConfig = func(api.KubernetesContextConfig) (*rest.Config, error) {
+		return &rest.Config{Host: server.URL}, nil
+	}
+
+	client, err := cm.GetClient("test")
+	if err != nil {
+		t.Fatalf("GetClient: %v", err)
+	}
+	start := time.Now()
+	if _, err := client.Clientset.Discovery().ServerVersion(); err == nil {
+		t.Fatalf("expected the discovery request to time out")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("discovery request not bounded by the request timeout: took %s", elapsed)
+	}
+
+	start = time.Now()
+	if _, err := client.Clientset.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{}); err == nil {
+		t.Fatalf("expected the API request to time out")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("API request not bounded by the request timeout: took %s", elapsed)
+	}
+
+	// Streams are bounded by their own deadline only
+	if client.StreamClientset == nil || client.StreamDynamicClient == nil {
+		t.Fatalf("expected streaming clients")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	_, _ = client.StreamingClientset().CoreV1().Pods("default").GetLogs("web", &corev1.PodLogOptions{Follow: true}).Stream(ctx)
+	if elapsed := time.Since(start); elapsed < 250*time.Millisecond {
+		t.Fatalf("stream cut by the request timeout after %s", elapsed)
+	}
+}
+
+func TestClientManager_SessionContexts(t *testing.T) {
+	dir := t.TempDir()
+	kubeconfig := filepath.Join(dir, "kubeconfig")
+	if err := os.WriteFile(kubeconfig, []byte(testKubeconfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cm, err := NewClientManager(slog.New(slog.NewTextHandler(io.Discard, nil)), &api.KubernetesConfig{
+		DefaultContext: "prod",
+		Contexts: []api.KubernetesContextConfig{
+			{Name: "prod", Kubeconfig: kubeconfig},
+			{Name: "staging", Kubeconfig: kubeconfig},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewClientManager: %v", err)
+	}
+	defer cm.Stop()
+
+	if err := cm.SetCurrentContext("session-a", "staging"); err != nil {
+		t.Fatalf("SetCurrentContext: %v", err)
+	}
+	if got := cm.GetCurrentContext("session-a"); got != "staging" {
+		t.Fatalf("expected session-a on staging, got %s", got)
+	}
+	// Other sessions and the server default don't move
+	if got := cm.GetCurrentContext("session-b"); got != "prod" {
+		t.Fatalf("expected session-b on the default, got %s", got)
+	}
+	if got := cm.GetCurrentContext(""); got != "prod" {
+		t.Fatalf("expected the default untouched, got %s", got)
+	}
+
+	if err := cm.SetCurrentContext("session-b", "nope"); err == nil {
+		t.Fatalf("expected unknown context rejected")
+	}
+
+	cm.ForgetSession("session-a")
+	if got := cm.GetCurrentContext("session-a"); got != "prod" {
+		t.Fatalf("expected a forgotten session back on the default, got %s", got)
+	}
+}
+
+func TestClientManager_Reload(t *testing.T) {
+	cm, err := NewClientManager(slog.New(slog.NewTextHandler(io.Discard, nil)), &api.KubernetesConfig{
+		DefaultContext: "stable",
+		Contexts: []api.KubernetesContextConfig{
+			{Name: "stable", AllowedNamespaces: []string{"shop"}},
+			{Name: "changed", AllowedNamespaces: []string{"shop"}},
+			{Name: "removed"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewClientManager: %v", err)
+	}
+	defer cm.Stop()
+	cm.loadConfig = func(api.KubernetesContextConfig) (*rest.Config, error) {
+		return &rest.Config{Host: "https://127.0.0.1:6443"}, nil
+	}
+
+	clients := map[string]*Client{}
+	for _, name := range []string{"stable", "changed", "removed"} {
+		if clients[name], err = cm.GetClient(name); err != nil {
+			t.Fatalf("GetClient(%s): %v", name, err)
+		}
+	}
+	if err := cm.SetCurrentContext("session-1", "removed"); err != nil {
+		t.Fatalf("SetCurrentContext: %v", err)
+	}
+	if err := cm.SetCurrentContext("", "removed"); err != nil {
+		t.Fatalf("SetCurrentContext: %v", err)
+	}
+
+	// A duplicate name rejects the whole reload
+	err = cm.Reload(&api.KubernetesConfig{Contexts: []api.KubernetesContextConfig{{Name: "stable"}, {Name: "stable"}}})
+	if err == nil || !slices.Equal(cm.ListContexts(), []string{"changed", "removed", "stable"}) {
+		t.Fatalf("expected an invalid reload to change nothing, got %v and %v", err, cm.ListContexts())
+	}
+
+	err = cm.Reload(&api.KubernetesConfig{
+		DefaultContext: "added",
+		Contexts: []api.KubernetesContextConfig{
+			{Name: "stable", AllowedNamespaces: []string{"shop"}},
+			{Name: "changed", AllowedNamespaces: []string{"billing"}},
+			{Name: "added"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+
+	if got := cm.ListContexts(); !slices.Equal(got, []string{"added", "changed", "stable"}) {
+		t.Errorf("contexts = %v, want added, changed and stable", got)
+	}
+	if client, _ := cm.GetClient("stable"); client != clients["stable"] {
+		t.Error("expected the unchanged context to keep its client")
+	}
+	if client, _ := cm.GetClient("changed"); client == clients["changed"] {
+		t.Error("expected the changed context to get a new client")
+	}
+	if cm.IsNamespaceAllowed("changed", "shop") || !cm.IsNamespaceAllowed("changed", "billing") {
+		t.Error("expected the new namespace allow-list to apply")
+	}
+	if _, err := cm.GetClient("removed"); err == nil {
+		t.Error("expected the removed context to be gone")
+	}
+	if got := cm.GetCurrentContext(""); got != "added" {
+		t.Errorf("current context after its removal = %q, want the new default", got)
+	}
+	if got := cm.GetCurrentContext("session-1"); got != "added" {
+		t.Errorf("session on a removed context = %q, want the new default", got)
+	}
+	if client, err := cm.GetClient(""); err != nil || client == nil {
+		t.Errorf("expected calls without a context to use the new default, got %v", err)
+	}
+}
+
+func TestClientManager_LoadNewContextFromFile(t *testing.T) {
+	configured := filepath.Join(t.TempDir(), "configured.kubeconfig")
+	if err := os.WriteFile(configured, []byte(testKubeconfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cm, err := NewClientManager(slog.New(slog.NewTextHandler(io.Discard, nil)), &api.KubernetesConfig{
+		DefaultContext: "test",
+		Contexts: []api.KubernetesContextConfig{
+			{Name: "test", Kubeconfig: configured, AllowedNamespaces: []string{"shop"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewClientManager: %v", err)
+	}
+	defer cm.Stop()
+
+	dir := t.TempDir()
+	clash := filepath.Join(dir, "clash.yaml")
+	if err := os.WriteFile(clash, []byte(testKubeconfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := cm.loadNewContextFromFile(clash); err != nil {
+		t.Fatalf("loadNewContextFromFile(clash): %v", err)
+	}
+	if got := cm.contextsByName["test"]; got.Kubeconfig != configured || !slices.Equal(got.AllowedNamespaces, []string{"shop"}) {
+		t.Fatalf("expected the configured context kept on a name clash, got %+v", got)
+	}
+	if cm.IsNamespaceAllowed("test", "billing") {
+		t.Fatal("expected the configured namespace allow-list to still apply")
+	}
+
+	extra := filepath.Join(dir, "extra.yaml")
+	if err := os.WriteFile(extra, []byte(strings.ReplaceAll(testKubeconfig, "test", "extra")), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := cm.loadNewContextFromFile(extra); err != nil {
+		t.Fatalf("loadNewContextFromFile(extra): %v", err)
+	}
+	if got := cm.ListContexts(); !slices.Equal(got, []string{"extra", "test"}) {
+		t.Fatalf("expected the new context added, got %v", got)
+	}
+}
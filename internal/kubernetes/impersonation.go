@@ -0,0 +1,149 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"kubernetes-mcp/api"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/transport"
+)
+
+// Caller is the identity behind a tool call, as authenticated by the
+// middlewares. Contexts with impersonate.from_caller send their API
+// requests on its behalf.
+type Caller struct {
+	// Name is the value of the configured identity claim
+	Name string
+	// Claims is the whole JWT or API key payload
+	Claims map[string]any
+}
+
+type callerKey struct{}
+
+// WithCaller returns a copy of ctx carrying the caller of a tool call. Every
+// API request made with that context is impersonated as the caller by the
+// contexts configured to do so.
+func WithCaller(ctx context.Context, caller Caller) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// CallerFrom returns the caller carried by ctx, if any
+func CallerFrom(ctx context.Context) (Caller, bool) {
+	caller, ok := ctx.Value(callerKey{}).(Caller)
+	return caller, ok
+}
+
+// applyImpersonation configures the identity the API server sees for a
+// context. A fixed identity uses client-go's own impersonation; the
+// caller's identity is resolved per request from its context.
+func applyImpersonation(restConfig *rest.Config, ctxConfig api.KubernetesContextConfig) error {
+	imp := ctxConfig.Impersonate
+	switch {
+	case imp.FromCaller && imp.User != "":
+		return fmt.Errorf("impersonate.user and impersonate.from_caller are mutually exclusive")
+	case !imp.FromCaller && imp.GroupsClaim != "":
+		return fmt.Errorf("impersonate.groups_claim requires impersonate.from_caller")
+	case !imp.FromCaller && imp.User == "" && (len(imp.Groups) > 0 || len(imp.Extra) > 0):
+		return fmt.Errorf("impersonate.groups and impersonate.extra require impersonate.user or impersonate.from_caller")
+	}
+
+	if imp.User != "" {
+		restConfig.Impersonate = rest.ImpersonationConfig{
+			UserName: imp.User,
+			Groups:   imp.Groups,
+			Extra:    imp.Extra,
+		}
+	}
+	if imp.FromCaller {
+		restConfig.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+			return &callerImpersonator{delegate: rt, config: imp}
+		})
+	}
+	return nil
+}
+
+// callerImpersonator impersonates the Caller carried by each request's
+// context. It fails closed: a request without a caller would otherwise run
+// with the server's own credentials.
+type callerImpersonator struct {
+	delegate http.RoundTripper
+	config   api.ImpersonationConfig
+}
+
+func (c *callerImpersonator) RoundTrip(req *http.Request) (*http.Response, error) {
+	caller, ok := CallerFrom(req.Context())
+	if !ok || caller.Name == "" {
+		// Discovery is shared by every caller and is cached with no
+		// request context, so it keeps the server's identity.
+		if isDiscoveryPath(req.URL.Path) {
+			return c.delegate.RoundTrip(req)
+		}
+		return nil, fmt.Errorf("impersonation of the caller is enabled for this context, but the request carries no caller identity")
+	}
+
+	groups := slices.Concat(claimStrings(caller.Claims, c.config.GroupsClaim), c.config.Groups)
+	return transport.NewImpersonatingRoundTripper(transport.ImpersonationConfig{
+		UserName: caller.Name,
+		Groups:   groups,
+		Extra:    c.config.Extra,
+	}, c.delegate).RoundTrip(req)
+}
+
+// isDiscoveryPath reports whether an API path is served by discovery:
+// /version, /openapi/*, /api, /api/<version>, /apis, /apis/<group> and
+// /apis/<group>/<version>. Everything below those addresses resources.
+func isDiscoveryPath(path string) bool {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	switch segments[0] {
+	case "version", "openapi":
+		return true
+	case "api":
+		return len(segments) <= 2
+	case "apis":
+		return len(segments) <= 3
+	}
+	return false
+}
+
+// claimStrings reads a claim holding a string or a list of strings
+func claimStrings(claims map[string]any, name string) []string {
+	if name == "" {
+		return nil
+	}
+	switch v := claims[name].(type) {
+	case string:
+		return []string{v}
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	case []string:
+		return v
+	}
+	return nil
+}
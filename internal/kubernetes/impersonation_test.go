@@ -0,0 +1,121 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"kubernetes-mcp/api"
+
+	"k8s.io/client-go/rest"
+)
+
+func TestApplyImpersonation_FromCaller(t *testing.T) {
+	var seen http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = r.Header.Clone()
+	}))
+	defer server.Close()
+
+	restConfig := &rest.Config{Host: server.URL}
+	err := applyImpersonation(restConfig, api.KubernetesContextConfig{
+		Impersonate: api.ImpersonationConfig{FromCaller: true, GroupsClaim: "groups", Groups: []string{"mcp-users"}},
+	})
+	if err != nil {
+		t.Fatalf("applyImpersonation: %v", err)
+	}
+	httpClient, err := rest.HTTPClientFor(restConfig)
+	if err != nil {
+		t.Fatalf("HTTPClientFor: %v", err)
+	}
+	get := func(ctx context.Context, path string) error {
+		t.Helper()
+		seen = nil
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+path, nil)
+		resp, err := httpClient.Do(req)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	caller := WithCaller(context.Background(), Caller{
+		Name:   "jane@example.com",
+		Claims: map[string]any{"groups": []any{"sre", "oncall"}},
+	})
+	if err := get(caller, "/api/v1/namespaces/default/pods"); err != nil {
+		t.Fatalf("request as caller: %v", err)
+	}
+	if got := seen.Get("Impersonate-User"); got != "jane@example.com" {
+		t.Fatalf("expected the caller impersonated, got %q", got)
+	}
+	if got := seen.Values("Impersonate-Group"); !slices.Equal(got, []string{"sre", "oncall", "mcp-users"}) {
+		t.Fatalf("unexpected groups: %v", got)
+	}
+
+	// Without a caller, discovery keeps the server's identity...
+	if err := get(context.Background(), "/apis/apps/v1"); err != nil {
+		t.Fatalf("discovery without caller: %v", err)
+	}
+	if got := seen.Get("Impersonate-User"); got != "" {
+		t.Fatalf("expected discovery not impersonated, got %q", got)
+	}
+
+	// ...and anything else is refused before reaching the server
+	err = get(context.Background(), "/apis/apps/v1/namespaces/default/deployments")
+	if err == nil || !strings.Contains(err.Error(), "no caller identity") {
+		t.Fatalf("expected the request refused, got %v", err)
+	}
+	if seen != nil {
+		t.Fatalf("refused request reached the server")
+	}
+}
+
+func TestApplyImpersonation_Validation(t *testing.T) {
+	tests := []struct {
+		name   string
+		config api.ImpersonationConfig
+		errMsg string
+	}{
+		{name: "user and caller", config: api.ImpersonationConfig{User: "bot", FromCaller: true}, errMsg: "mutually exclusive"},
+		{name: "groups claim alone", config: api.ImpersonationConfig{GroupsClaim: "groups"}, errMsg: "requires impersonate.from_caller"},
+		{name: "groups without user", config: api.ImpersonationConfig{Groups: []string{"sre"}}, errMsg: "require impersonate.user"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := applyImpersonation(&rest.Config{}, api.KubernetesContextConfig{Impersonate: tt.config})
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Fatalf("expected %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+
+	restConfig := &rest.Config{}
+	if err := applyImpersonation(restConfig, api.KubernetesContextConfig{
+		Impersonate: api.ImpersonationConfig{User: "bot", Groups: []string{"readers"}},
+	}); err != nil {
+		t.Fatalf("fixed identity: %v", err)
+	}
+	if restConfig.Impersonate.UserName != "bot" || len(restConfig.Impersonate.Groups) != 1 {
+		t.Fatalf("unexpected impersonation config: %+v", restConfig.Impersonate)
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/openapi"
+	"k8s.io/kube-openapi/pkg/spec3"
+)
+
+// OpenAPIDocument returns the OpenAPI v3 document the API server publishes
+// for one group/version, CRDs included. Parsed documents are kept until
+// ResetDiscovery.
+func (c *Client) OpenAPIDocument(gv schema.GroupVersion) (*spec3.OpenAPI, error) {
+	c.openAPIMu.Lock()
+	defer c.openAPIMu.Unlock()
+
+	if doc, ok := c.openAPIDocs[gv]; ok {
+		return doc, nil
+	}
+
+	paths, err := c.openAPIClient().Paths()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list OpenAPI v3 paths: %w", err)
+	}
+	path := "apis/" + gv.Group + "/" + gv.Version
+	if gv.Group == "" {
+		path = "api/" + gv.Version
+	}
+	groupVersion, ok := paths[path]
+	if !ok {
+		return nil, fmt.Errorf("the API server publishes no OpenAPI v3 schema for %s", gv)
+	}
+
+	raw, err := groupVersion.Schema("application/json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the OpenAPI v3 schema for %s: %w", gv, err)
+	}
+	doc := &spec3.OpenAPI{}
+	if err := json.Unmarshal(raw, doc); err != nil {
+		return nil, fmt.Errorf("failed to parse the OpenAPI v3 schema for %s: %w", gv, err)
+	}
+
+	if c.openAPIDocs == nil {
+		c.openAPIDocs = map[schema.GroupVersion]*spec3.OpenAPI{}
+	}
+	c.openAPIDocs[gv] = doc
+	return doc, nil
+}
+
+// openAPIClient returns the OpenAPI v3 client, asking discovery each time
+// so an invalidated discovery cache also drops its cached schemas.
+func (c *Client) openAPIClient() openapi.Client {
+	if c.OpenAPIClient != nil {
+		return c.OpenAPIClient
+	}
+	if c.DiscoveryClient != nil {
+		return c.DiscoveryClient.OpenAPIV3()
+	}
+	return c.Clientset.Discovery().OpenAPIV3()
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// reauthMinInterval spaces out credential reloads, so credentials that are
+// really invalid don't trigger a reload on every request
+const reauthMinInterval = 10 * time.Second
+
+// reauthTransport retries a request the API server rejected with 401
+// Unauthorized once, through a transport built from freshly loaded
+// credentials: a rotated token or certificate in the kubeconfig, or a new
+// token from an exec plugin. A 401 means the request had no effect, so
+// the retry is safe for writes too.
+type reauthTransport struct {
+	// reload builds the transport of a client with fresh credentials
+	reload func() (http.RoundTripper, error)
+
+	mu         sync.Mutex
+	current    http.RoundTripper
+	generation int
+	lastReload time.Time
+}
+
+func (t *reauthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	rt, generation := t.current, t.generation
+	t.mu.Unlock()
+
+	resp, err := rt.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	retry, ok := rewindRequest(req)
+	if !ok {
+		return resp, nil
+	}
+	fresh := t.refresh(generation)
+	if fresh == nil {
+		return resp, nil
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return fresh.RoundTrip(retry)
+}
+
+// refresh returns the transport to retry with, reloading the credentials
+// unless a concurrent request already did since 'generation' was read. It
+// returns nil when no reload is possible right now.
+func (t *reauthTransport) refresh(generation int) http.RoundTripper {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.generation != generation {
+		return t.current
+	}
+	if time.Since(t.lastReload) < reauthMinInterval {
+		return nil
+	}
+	t.lastReload = time.Now()
+
+	fresh, err := t.reload()
+	if err != nil {
+		return nil
+	}
+	t.current = fresh
+	t.generation++
+	return fresh
+}
+
+// rewindRequest returns a copy of req that can be sent again, which is not
+// possible when its body can't be re-read.
+func rewindRequest(req *http.Request) (*http.Request, bool) {
+	retry := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return retry, true
+	}
+	if req.GetBody == nil {
+		return nil, false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, false
+	}
+	retry.Body = body
+	return retry, true
+}
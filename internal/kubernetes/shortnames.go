@@ -0,0 +1,83 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+)
+
+// ResourceForShortName returns the resource a short name such as 'po',
+// 'deploy' or a CRD's own short name stands for, as published by discovery.
+// The table is built on first use and kept until ResetDiscovery.
+func (c *Client) ResourceForShortName(shortName string) (schema.GroupResource, bool, error) {
+	c.shortNamesMu.Lock()
+	defer c.shortNamesMu.Unlock()
+
+	if c.shortNames == nil {
+		table, err := c.loadShortNames()
+		if err != nil {
+			return schema.GroupResource{}, false, err
+		}
+		c.shortNames = table
+	}
+	resource, ok := c.shortNames[shortName]
+	return resource, ok, nil
+}
+
+// ResetDiscovery drops every cached discovery result (RESTMapper, short
+// names and OpenAPI documents) so newly installed CRDs and API changes are
+// picked up.
+func (c *Client) ResetDiscovery() {
+	if c.RESTMapper != nil {
+		c.RESTMapper.Reset()
+	}
+	c.shortNamesMu.Lock()
+	c.shortNames = nil
+	c.shortNamesMu.Unlock()
+	c.openAPIMu.Lock()
+	c.openAPIDocs = nil
+	c.openAPIMu.Unlock()
+}
+
+// loadShortNames reads the short names of every served resource. Groups
+// that fail discovery (an unavailable aggregated API) are skipped.
+func (c *Client) loadShortNames() (map[string]schema.GroupResource, error) {
+	var client discovery.DiscoveryInterface = c.DiscoveryClient
+	if c.DiscoveryClient == nil {
+		client = c.Clientset.Discovery()
+	}
+
+	_, lists, err := client.ServerGroupsAndResources()
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return nil, err
+	}
+
+	table := map[string]schema.GroupResource{}
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, r := range list.APIResources {
+			for _, short := range r.ShortNames {
+				table[short] = schema.GroupResource{Group: gv.Group, Resource: r.Name}
+			}
+		}
+	}
+	return table, nil
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middlewares
+
+import (
+	"net/http"
+	"time"
+
+	"kubernetes-mcp/internal/globals"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	// MetricsDefaultPath is where metrics are served when 'middleware.metrics.path' is unset
+	MetricsDefaultPath = "/metrics"
+
+	metricsNamespace = "kubernetes_mcp"
+)
+
+type MetricsMiddlewareDependencies struct {
+	AppCtx *globals.ApplicationContext
+}
+
+// MetricsMiddleware collects Prometheus metrics about tool calls and
+// authorization decisions, and serves them. Labels are limited to the tool
+// and the context: resource names, namespaces or callers would make the
+// series count unbounded.
+type MetricsMiddleware struct {
+	dependencies MetricsMiddlewareDependencies
+
+	registry       *prometheus.Registry
+	toolCalls      *prometheus.CounterVec
+	toolDuration   *prometheus.HistogramVec
+	authzDecisions *prometheus.CounterVec
+}
+
+func NewMetricsMiddleware(deps MetricsMiddlewareDependencies) *MetricsMiddleware {
+
+	mw := &MetricsMiddleware{
+		dependencies: deps,
+		registry:     prometheus.NewRegistry(),
+
+		toolCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "tool_calls_total",
+			Help:      "Tool calls by tool, context and result (success or error).",
+		}, []string{"tool", "context", "result"}),
+
+		toolDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "tool_call_duration_seconds",
+			Help:      "Duration of tool calls by tool and context.",
+			Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 300},
+		}, []string{"tool", "context"}),
+
+		authzDecisions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "authorization_decisions_total",
+			Help:      "Authorization policy decisions by tool, context and decision (allow or deny).",
+		}, []string{"tool", "context", "decision"}),
+	}
+
+	mw.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		mw.toolCalls,
+		mw.toolDuration,
+		mw.authzDecisions,
+	)
+
+	return mw
+}
+
+// Handler serves the collected metrics in the Prometheus exposition format
+func (mw *MetricsMiddleware) Handler() http.Handler {
+	return promhttp.HandlerFor(mw.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveToolCall records one finished tool call
+func (mw *MetricsMiddleware) ObserveToolCall(tool, context string, duration time.Duration, failed bool) {
+	result := "success"
+	if failed {
+		result = "error"
+	}
+	mw.toolCalls.WithLabelValues(tool, context, result).Inc()
+	mw.toolDuration.WithLabelValues(tool, context).Observe(duration.Seconds())
+}
+
+// ObserveAuthorization records one authorization decision
+func (mw *MetricsMiddleware) ObserveAuthorization(tool, context string, allowed bool) {
+	decision := "allow"
+	if !allowed {
+		decision = "deny"
+	}
+	mw.authzDecisions.WithLabelValues(tool, context, decision).Inc()
+}
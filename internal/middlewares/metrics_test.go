@@ -0,0 +1,52 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	mw := NewMetricsMiddleware(MetricsMiddlewareDependencies{})
+
+	mw.ObserveToolCall("get_resource", "prod", 150*time.Millisecond, false)
+	mw.ObserveAuthorization("delete_resource", "prod", false)
+	mw.ObserveAuthorization("delete_resource", "prod", false)
+	mw.ObserveAuthorization("get_resource", "prod", true)
+
+	rec := httptest.NewRecorder()
+	mw.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, MetricsDefaultPath, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("scrape: got %d", rec.Code)
+	}
+	scraped := rec.Body.String()
+	for _, want := range []string{
+		`kubernetes_mcp_tool_calls_total{context="prod",result="success",tool="get_resource"} 1`,
+		`kubernetes_mcp_tool_call_duration_seconds_bucket{context="prod",tool="get_resource",le="0.25"} 1`,
+		`kubernetes_mcp_authorization_decisions_total{context="prod",decision="deny",tool="delete_resource"} 2`,
+		`kubernetes_mcp_authorization_decisions_total{context="prod",decision="allow",tool="get_resource"} 1`,
+		`go_goroutines`,
+	} {
+		if !strings.Contains(scraped, want) {
+			t.Errorf("scraped metrics lack %q", want)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middlewares
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"kubernetes-mcp/internal/globals"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// rateLimitDefaultIdentityClaim mirrors authorization.identity_claim's default
+	rateLimitDefaultIdentityClaim = "sub"
+
+	// rateLimitIdleTTL is how long an unused bucket is kept. A bucket idle
+	// that long is full again, so dropping it changes nothing.
+	rateLimitIdleTTL = 10 * time.Minute
+)
+
+type RateLimitMiddlewareDependencies struct {
+	AppCtx *globals.ApplicationContext
+}
+
+type RateLimitMiddleware struct {
+	dependencies RateLimitMiddlewareDependencies
+
+	mutex       sync.Mutex
+	buckets     map[string]*rateLimitBucket
+	lastCleanup time.Time
+}
+
+type rateLimitBucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+func NewRateLimitMiddleware(deps RateLimitMiddlewareDependencies) (*RateLimitMiddleware, error) {
+
+	config := deps.AppCtx.Config.Middleware.RateLimit
+	if config.Enabled && (config.RequestsPerSecond <= 0 || config.Burst <= 0) {
+		return nil, fmt.Errorf("rate_limit.requests_per_second and rate_limit.burst must be positive, got %v and %d",
+			config.RequestsPerSecond, config.Burst)
+	}
+
+	return &RateLimitMiddleware{
+		dependencies: deps,
+		buckets:      map[string]*rateLimitBucket{},
+		lastCleanup:  time.Now(),
+	}, nil
+}
+
+func (mw *RateLimitMiddleware) Middleware(next http.Handler) http.Handler {
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+
+		if !mw.dependencies.AppCtx.Config.Middleware.RateLimit.Enabled {
+			next.ServeHTTP(rw, req)
+			return
+		}
+
+		identity := mw.identity(req)
+		reservation := mw.limiter(identity, time.Now()).Reserve()
+		if delay := reservation.Delay(); delay > 0 {
+			reservation.Cancel()
+
+			mw.dependencies.AppCtx.Logger.Warn("rate limit exceeded", "identity", identity, "retry_after", delay.String())
+			rw.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+			http.Error(rw, "Too Many Requests: rate limit exceeded, retry later", http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(rw, req)
+	})
+}
+
+// identity returns the caller named by the authenticated payload, or ""
+// for anonymous requests. It runs after the JWT and API key middlewares,
+// which leave the payload in AuthPayloadHeader.
+func (mw *RateLimitMiddleware) identity(req *http.Request) string {
+	payloadHex := req.Header.Get(AuthPayloadHeader)
+	if payloadHex == "" {
+		return ""
+	}
+	payloadJSON, err := hex.DecodeString(payloadHex)
+	if err != nil {
+		return ""
+	}
+	payload := map[string]any{}
+	if err := json.Unmarshal(payloadJSON, &payload); err != nil {
+		return ""
+	}
+
+	claim := mw.dependencies.AppCtx.Config.Authorization.IdentityClaim
+	if claim == "" {
+		claim = rateLimitDefaultIdentityClaim
+	}
+	name, _ := payload[claim].(string)
+	return name
+}
+
+// limiter returns the identity's bucket, creating it on first use, and
+// drops the buckets left idle for rateLimitIdleTTL.
+func (mw *RateLimitMiddleware) limiter(identity string, now time.Time) *rate.Limiter {
+	mw.mutex.Lock()
+	defer mw.mutex.Unlock()
+
+	if now.Sub(mw.lastCleanup) > rateLimitIdleTTL {
+		for key, bucket := range mw.buckets {
+			if now.Sub(bucket.lastSeen) > rateLimitIdleTTL {
+				delete(mw.buckets, key)
+			}
+		}
+		mw.lastCleanup = now
+	}
+
+	bucket, ok := mw.buckets[identity]
+	if !ok {
+		config := mw.dependencies.AppCtx.Config.Middleware.RateLimit
+		bucket = &rateLimitBucket{limiter: rate.NewLimiter(rate.Limit(config.RequestsPerSecond), config.Burst)}
+		mw.buckets[identity] = bucket
+	}
+	bucket.lastSeen = now
+	return bucket.limiter
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middlewares
+
+import (
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"kubernetes-mcp/api"
+	"kubernetes-mcp/internal/globals"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	config := &api.Configuration{}
+	config.Middleware.RateLimit = api.RateLimitConfig{Enabled: true, RequestsPerSecond: 0.01, Burst: 2}
+	config.Authorization.IdentityClaim = "email"
+	mw, err := NewRateLimitMiddleware(RateLimitMiddlewareDependencies{
+		AppCtx: &globals.ApplicationContext{Config: config, Logger: slog.New(slog.NewTextHandler(io.Discard, nil))},
+	})
+	if err != nil {
+		t.Fatalf("NewRateLimitMiddleware: %v", err)
+	}
+	handler := mw.Middleware(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}))
+
+	call := func(payload string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		if payload != "" {
+			req.Header.Set(AuthPayloadHeader, hex.EncodeToString([]byte(payload)))
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	alice := `{"email":"alice@example.com"}`
+	for i := 0; i < 2; i++ {
+		if rec := call(alice); rec.Code != http.StatusOK {
+			t.Fatalf("request %d within burst: got %d", i+1, rec.Code)
+		}
+	}
+	rec := call(alice)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("request over burst: got %d, want 429", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("429 without Retry-After")
+	}
+
+	// Other identities, and anonymous callers, have their own buckets
+	if rec := call(`{"email":"bob@example.com"}`); rec.Code != http.StatusOK {
+		t.Errorf("bob limited by alice's bucket: got %d", rec.Code)
+	}
+	call("")
+	call("")
+	if rec := call(""); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("anonymous requests share one bucket: got %d, want 429", rec.Code)
+	}
+
+	config.Middleware.RateLimit.RequestsPerSecond = 0
+	if _, err := NewRateLimitMiddleware(RateLimitMiddlewareDependencies{AppCtx: &globals.ApplicationContext{Config: config}}); err == nil {
+		t.Error("expected an error for a non-positive rate")
+	}
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redaction
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"kubernetes-mcp/api"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Mask is the value written in place of every redacted field
+const Mask = "***"
+
+// Redactor masks sensitive values in YAML tool output
+type Redactor struct {
+	secretData bool
+	fieldPaths []string
+	keyNames   []string
+}
+
+// NewRedactor creates a new redactor from the tools configuration.
+// A nil *Redactor is returned when redaction is disabled, and every
+// method on it is a no-op.
+func NewRedactor(config api.RedactionConfig) *Redactor {
+	if !config.Enabled {
+		return nil
+	}
+
+	r := &Redactor{
+		secretData: config.SecretData,
+		fieldPaths: config.FieldPaths,
+	}
+	for _, k := range config.KeyNames {
+		r.keyNames = append(r.keyNames, strings.ToLower(k))
+	}
+	return r
+}
+
+// RedactYAML masks the matched values in a (possibly multi-document) YAML
+// string. Input that is not YAML, or where nothing matched, is returned
+// unchanged so free-form text (logs, exec output) passes through untouched.
+func (r *Redactor) RedactYAML(input string) string {
+	if r == nil || strings.TrimSpace(input) == "" {
+		return input
+	}
+
+	decoder := yaml.NewDecoder(strings.NewReader(input))
+	var docs []*yaml.Node
+	for {
+		doc := &yaml.Node{}
+		err := decoder.Decode(doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return input
+		}
+		docs = append(docs, doc)
+	}
+
+	changed := false
+	for _, doc := range docs {
+		for _, child := range doc.Content {
+			if r.walk(child, "") {
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return input
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	for _, doc := range docs {
+		if err := encoder.Encode(doc); err != nil {
+			return input
+		}
+	}
+	_ = encoder.Close()
+
+	return buf.String()
+}
+
+// walk masks matching values under node and reports whether anything changed.
+// 'fieldPath' is the dotted path of node from the document root; list elements
+// are addressed by their index ('items.0.data.password').
+func (r *Redactor) walk(node *yaml.Node, fieldPath string) bool {
+	changed := false
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		isSecret := r.secretData && mappingValue(node, "kind") == "Secret"
+		envName := mappingValue(node, "name")
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			value := node.Content[i+1]
+			childPath := joinPath(fieldPath, key)
+
+			switch {
+			case r.matchesKeyName(key),
+				r.matchesFieldPath(childPath),
+				// name/value pairs (container env) are matched by the 'name' entry
+				key == "value" && envName != "" && r.matchesKeyName(envName):
+				changed = mask(value) || changed
+			case isSecret && (key == "data" || key == "stringData"):
+				changed = maskChildren(value) || changed
+			default:
+				changed = r.walk(value, childPath) || changed
+			}
+		}
+
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			changed = r.walk(item, joinPath(fieldPath, strconv.Itoa(i))) || changed
+		}
+	}
+
+	return changed
+}
+
+// matchesKeyName reports whether a map key matches any configured key-name glob
+func (r *Redactor) matchesKeyName(key string) bool {
+	key = strings.ToLower(key)
+	for _, pattern := range r.keyNames {
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesFieldPath reports whether a dotted field path matches any configured glob
+func (r *Redactor) matchesFieldPath(fieldPath string) bool {
+	for _, pattern := range r.fieldPaths {
+		if ok, _ := path.Match(pattern, fieldPath); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// mask replaces a node with the Mask scalar. Already masked scalars are left
+// untouched so the pass can run more than once on the same output.
+func mask(node *yaml.Node) bool {
+	if node.Kind == yaml.ScalarNode && node.Value == Mask {
+		return false
+	}
+	*node = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: Mask}
+	return true
+}
+
+// maskChildren masks every value of a mapping, keeping the keys visible
+func maskChildren(node *yaml.Node) bool {
+	if node.Kind != yaml.MappingNode {
+		return mask(node)
+	}
+	changed := false
+	for i := 1; i < len(node.Content); i += 2 {
+		changed = mask(node.Content[i]) || changed
+	}
+	return changed
+}
+
+// mappingValue returns the scalar value stored under key in a mapping node
+func mappingValue(node *yaml.Node, key string) string {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key && node.Content[i+1].Kind == yaml.ScalarNode {
+			return node.Content[i+1].Value
+		}
+	}
+	return ""
+}
+
+func joinPath(parent, child string) string {
+	if parent == "" {
+		return child
+	}
+	return parent + "." + child
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redaction
+
+import (
+	"strings"
+	"testing"
+
+	"kubernetes-mcp/api"
+)
+
+func TestNewRedactorDisabled(t *testing.T) {
+	r := NewRedactor(api.RedactionConfig{Enabled: false, SecretData: true})
+	if r != nil {
+		t.Fatalf("expected nil redactor when disabled")
+	}
+
+	input := "kind: Secret\ndata:\n  password: c2VjcmV0\n"
+	if got := r.RedactYAML(input); got != input {
+		t.Errorf("nil redactor changed the input:\n%s", got)
+	}
+}
+
+func TestRedactYAML(t *testing.T) {
+	r := NewRedactor(api.RedactionConfig{
+		Enabled:    true,
+		SecretData: true,
+		FieldPaths: []string{
+			"metadata.annotations.kubectl.kubernetes.io/last-applied-configuration",
+			"items.*.spec.token",
+		},
+		KeyNames: []string{"*PASSWORD*", "*_token"},
+	})
+
+	tests := []struct {
+		name     string
+		input    string
+		contains []string
+		absent   []string
+	}{
+		{
+			name:     "secret data and stringData",
+			input:    "kind: Secret\ndata:\n  username: YWRtaW4=\nstringData:\n  api: plain\n",
+			contains: []string{"username: '***'", "api: '***'"},
+			absent:   []string{"YWRtaW4=", "plain"},
+		},
+		{
+			name:     "configmap data untouched",
+			input:    "kind: ConfigMap\ndata:\n  username: admin\n",
+			contains: []string{"username: admin"},
+		},
+		{
+			name:     "key name glob is case insensitive",
+			input:    "spec:\n  DB_Password: hunter2\n  github_token: ghp\n  user: bob\n",
+			contains: []string{"DB_Password: '***'", "github_token: '***'", "user: bob"},
+			absent:   []string{"hunter2", "ghp"},
+		},
+		{
+			name:     "env var name/value pairs",
+			input:    "env:\n  - name: ADMIN_PASSWORD\n    value: hunter2\n  - name: LOG_LEVEL\n    value: debug\n",
+			contains: []string{"value: '***'", "value: debug"},
+			absent:   []string{"hunter2"},
+		},
+		{
+			name:     "field path with list index",
+			input:    "items:\n  - spec:\n      token: abc\n  - spec:\n      token: def\n",
+			contains: []string{"token: '***'"},
+			absent:   []string{"abc", "def"},
+		},
+		{
+			name:     "annotation field path",
+			input:    "metadata:\n  annotations:\n    kubectl.kubernetes.io/last-applied-configuration: '{\"a\":1}'\n    team: core\n",
+			contains: []string{"team: core"},
+			absent:   []string{`{"a":1}`},
+		},
+		{
+			name:     "secrets in a multi-document stream",
+			input:    "kind: Secret\ndata:\n  a: eA==\n---\nkind: Secret\ndata:\n  b: eQ==\n",
+			contains: []string{"a: '***'", "b: '***'", "---"},
+			absent:   []string{"eA==", "eQ=="},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := r.RedactYAML(tt.input)
+			for _, s := range tt.contains {
+				if !strings.Contains(got, s) {
+					t.Errorf("expected output to contain %q, got:\n%s", s, got)
+				}
+			}
+			for _, s := range tt.absent {
+				if strings.Contains(got, s) {
+					t.Errorf("expected output NOT to contain %q, got:\n%s", s, got)
+				}
+			}
+		})
+	}
+}
+
+func TestRedactYAMLPassThrough(t *testing.T) {
+	r := NewRedactor(api.RedactionConfig{Enabled: true, SecretData: true, KeyNames: []string{"*password*"}})
+
+	inputs := []string{
+		"",
+		"plain log line: password=hunter2 [not: yaml",
+		"kind: Pod\nmetadata:\n  name: web\n",
+	}
+	for _, input := range inputs {
+		if got := r.RedactYAML(input); got != input {
+			t.Errorf("expected %q to pass through unchanged, got %q", input, got)
+		}
+	}
+}
+
+func TestRedactYAMLIdempotent(t *testing.T) {
+	r := NewRedactor(api.RedactionConfig{Enabled: true, SecretData: true})
+
+	once := r.RedactYAML("kind: Secret\ndata:\n  a: eA==\n")
+	if twice := r.RedactYAML(once); twice != once {
+		t.Errorf("second pass changed the output:\n%s\nvs\n%s", once, twice)
+	}
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servertls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"kubernetes-mcp/api"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDebounce groups the events of one rotation (key and certificate
+// are rarely written at once) into a single reload
+const reloadDebounce = 500 * time.Millisecond
+
+// Reloader serves the TLS configuration of the HTTP transport and reloads
+// its certificate, key and client CA when the files change or on SIGHUP.
+// A reload that fails keeps the previous configuration.
+type Reloader struct {
+	logger *slog.Logger
+	config api.ServerTLSConfig
+
+	mu      sync.RWMutex
+	current *tls.Config
+}
+
+// NewReloader validates the configuration and loads the files once
+func NewReloader(logger *slog.Logger, config api.ServerTLSConfig) (*Reloader, error) {
+	if config.CertFile == "" || config.KeyFile == "" {
+		return nil, fmt.Errorf("tls.cert_file and tls.key_file must both be set")
+	}
+
+	r := &Reloader{logger: logger, config: config}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// TLSConfig returns the configuration to give the http.Server. Every
+// handshake picks up the latest loaded files.
+func (r *Reloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+			return r.current, nil
+		},
+	}
+}
+
+// Reload reads the files again and swaps them in
+func (r *Reloader) Reload() error {
+	cert, err := tls.LoadX509KeyPair(r.config.CertFile, r.config.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+
+	current := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+	if r.config.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.config.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read tls.client_ca_file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("tls.client_ca_file %q contains no valid PEM certificate", r.config.ClientCAFile)
+		}
+		current.ClientCAs = pool
+		current.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	r.mu.Lock()
+	r.current = current
+	r.mu.Unlock()
+	return nil
+}
+
+// Watch reloads the files on SIGHUP and whenever something changes in
+// their directories, until stop is closed. Directories are watched rather
+// than the files so atomic renames and Kubernetes Secret volume updates
+// (a symlink swap) are seen.
+func (r *Reloader) Watch(stop <-chan struct{}) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var events <-chan fsnotify.Event
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		r.logger.Warn("failed to watch TLS files, reload them with SIGHUP", "error", err)
+	} else {
+		defer watcher.Close()
+		events = watcher.Events
+		for _, dir := range r.dirs() {
+			if err := watcher.Add(dir); err != nil {
+				r.logger.Warn("failed to watch TLS directory, reload it with SIGHUP", "directory", dir, "error", err)
+			}
+		}
+	}
+
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-stop:
+			return
+		case <-hup:
+			r.reload("SIGHUP")
+		case _, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			debounce = time.After(reloadDebounce)
+		case <-debounce:
+			debounce = nil
+			r.reload("file change")
+		}
+	}
+}
+
+// reload reloads the files and logs the outcome
+func (r *Reloader) reload(reason string) {
+	if err := r.Reload(); err != nil {
+		r.logger.Error("failed to reload TLS files, keeping the previous ones", "reason", reason, "error", err)
+		return
+	}
+	r.logger.Info("reloaded TLS files", "reason", reason)
+}
+
+// dirs returns the distinct directories holding the configured files
+func (r *Reloader) dirs() []string {
+	seen := map[string]bool{}
+	var dirs []string
+	for _, file := range []string{r.config.CertFile, r.config.KeyFile, r.config.ClientCAFile} {
+		if file == "" {
+			continue
+		}
+		dir := filepath.Dir(file)
+		if abs, err := filepath.Abs(dir); err == nil {
+			dir = abs
+		}
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
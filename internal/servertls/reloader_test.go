@@ -0,0 +1,170 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servertls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log/slog"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"kubernetes-mcp/api"
+)
+
+// writeCert writes a self-signed certificate and its key for commonName
+func writeCert(t *testing.T, certFile, keyFile, commonName string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// servedCommonName returns the CommonName of the certificate served now
+func servedCommonName(t *testing.T, r *Reloader) string {
+	t.Helper()
+	config, err := r.TLSConfig().GetConfigForClient(&tls.ClientHelloInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(config.Certificates[0].Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return leaf.Subject.CommonName
+}
+
+func TestReloader(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	writeCert(t, certFile, keyFile, "first")
+
+	if _, err := NewReloader(logger, api.ServerTLSConfig{CertFile: certFile}); err == nil {
+		t.Error("expected an error when key_file is missing")
+	}
+
+	r, err := NewReloader(logger, api.ServerTLSConfig{CertFile: certFile, KeyFile: keyFile})
+	if err != nil {
+		t.Fatalf("NewReloader: %v", err)
+	}
+	if got := servedCommonName(t, r); got != "first" {
+		t.Errorf("served %q, want first", got)
+	}
+
+	t.Run("reload picks up a rotated certificate", func(t *testing.T) {
+		writeCert(t, certFile, keyFile, "second")
+		if err := r.Reload(); err != nil {
+			t.Fatalf("Reload: %v", err)
+		}
+		if got := servedCommonName(t, r); got != "second" {
+			t.Errorf("served %q, want second", got)
+		}
+	})
+
+	t.Run("a failed reload keeps the previous certificate", func(t *testing.T) {
+		if err := os.WriteFile(keyFile, []byte("garbage"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Reload(); err == nil {
+			t.Error("expected an error for an invalid key")
+		}
+		if got := servedCommonName(t, r); got != "second" {
+			t.Errorf("served %q, want second", got)
+		}
+	})
+
+	t.Run("watch reloads on file change", func(t *testing.T) {
+		stop := make(chan struct{})
+		defer close(stop)
+		go r.Watch(stop)
+		// Give the watcher time to register the directory
+		time.Sleep(100 * time.Millisecond)
+
+		writeCert(t, certFile, keyFile, "third")
+		deadline := time.Now().Add(5 * time.Second)
+		for servedCommonName(t, r) != "third" {
+			if time.Now().After(deadline) {
+				t.Fatal("certificate was not reloaded after the files changed")
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+	})
+}
+
+func TestReloader_ClientCA(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	caFile := filepath.Join(dir, "ca.crt")
+	writeCert(t, certFile, keyFile, "server")
+	writeCert(t, caFile, filepath.Join(dir, "ca.key"), "client-ca")
+
+	r, err := NewReloader(logger, api.ServerTLSConfig{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile})
+	if err != nil {
+		t.Fatalf("NewReloader: %v", err)
+	}
+	config, _ := r.TLSConfig().GetConfigForClient(&tls.ClientHelloInfo{})
+	if config.ClientAuth != tls.RequireAndVerifyClientCert {
+		t.Errorf("ClientAuth = %v, want RequireAndVerifyClientCert", config.ClientAuth)
+	}
+	if config.ClientCAs == nil {
+		t.Error("ClientCAs not set")
+	}
+
+	if err := os.WriteFile(caFile, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Reload(); err == nil {
+		t.Error("expected an error for a client CA without certificates")
+	}
+}